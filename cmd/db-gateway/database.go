@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/server"
+)
+
+// connectServer creates the configured server and connects its database;
+// callers disconnect it
+func connectServer(ctx context.Context) (*server.MCPServerWithDB, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	srv, err := server.NewMCPServerWithDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if srv.DBConn == nil {
+		return nil, fmt.Errorf("no database configured in %s", configPath)
+	}
+	if err := srv.DBConn.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return srv, nil
+}
+
+// allTables returns the given tables, or every table when none is given
+func allTables(ctx context.Context, conn connector.DatabaseConnector, tables []string) ([]string, error) {
+	if len(tables) > 0 {
+		return tables, nil
+	}
+	list, err := conn.ListTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	for _, t := range list {
+		tables = append(tables, t.Name)
+	}
+	return tables, nil
+}
+
+// writeJSON writes v as indented JSON to --output, or to standard output
+func writeJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if outputPath == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	return nil
+}
+
+func runIntrospect(tables []string) error {
+	ctx := context.Background()
+	srv, err := connectServer(ctx)
+	if err != nil {
+		return err
+	}
+	defer srv.DBConn.Disconnect(ctx)
+
+	if tables, err = allTables(ctx, srv.DBConn, tables); err != nil {
+		return err
+	}
+	metadata := make([]*connector.TableMetadata, 0, len(tables))
+	for _, table := range tables {
+		m, err := srv.DBConn.GetTableMetadata(ctx, table)
+		if err != nil {
+			return fmt.Errorf("failed to get metadata for table %s: %w", table, err)
+		}
+		metadata = append(metadata, m)
+	}
+	return writeJSON(metadata)
+}
+
+func runGenerateOpenAPI(tables []string) error {
+	ctx := context.Background()
+	srv, err := connectServer(ctx)
+	if err != nil {
+		return err
+	}
+	defer srv.DBConn.Disconnect(ctx)
+
+	if tables, err = allTables(ctx, srv.DBConn, tables); err != nil {
+		return err
+	}
+	endpoints, err := srv.GenerateEndpoints(ctx, tables)
+	if err != nil {
+		return fmt.Errorf("failed to generate API endpoints: %w", err)
+	}
+	prefix := srv.Config.APIPrefix
+	if prefix == "" {
+		prefix = server.DefaultAPIPrefix
+	}
+	return writeJSON(server.OpenAPISpec(srv.Config.Name, prefix, endpoints))
+}
+
+func runTestConnection() error {
+	ctx := context.Background()
+	start := time.Now()
+	srv, err := connectServer(ctx)
+	if err != nil {
+		return err
+	}
+	defer srv.DBConn.Disconnect(ctx)
+
+	tables, err := srv.DBConn.ListTables(ctx)
+	if err != nil {
+		return fmt.Errorf("connected, but failed to list tables: %w", err)
+	}
+	fmt.Printf("Connected to %s database in %s; %d tables visible\n",
+		srv.Config.Database.Type, time.Since(start).Round(time.Millisecond), len(tables))
+	return nil
+}
+
+func runGenConfig() error {
+	supported := false
+	for _, t := range connector.Types() {
+		if t == databaseType {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("unsupported database type %q; supported types: %v", databaseType, connector.Types())
+	}
+
+	cfg := &server.MCPServerConfig{
+		Name:       serverName,
+		Database:   &connector.DatabaseConfig{Type: databaseType},
+		EnableAPI:  true,
+		APIAddr:    ":8081",
+		TableTools: true,
+	}
+	if databaseType == "snowflake" {
+		cfg.Database.Snowflake = &connector.SnowflakeConfig{
+			Account:   "your-account",
+			Username:  "your-username",
+			Password:  "your-password",
+			Database:  "your-database",
+			Schema:    "PUBLIC",
+			Warehouse: "your-warehouse",
+			AuthType:  "password",
+		}
+	}
+	return writeJSON(cfg)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/server"
+)
+
+// captureStdout returns what fn writes to standard output
+func captureStdout(t *testing.T, fn func()) string {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	require.NoError(t, w.Close())
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestWriteJSON(t *testing.T) {
+	defer func() { outputPath = "" }()
+	v := map[string]interface{}{"name": "sales", "tables": []string{"ORDERS"}}
+	want := "{\n  \"name\": \"sales\",\n  \"tables\": [\n    \"ORDERS\"\n  ]\n}\n"
+
+	// Indented JSON goes to standard output by default
+	out := captureStdout(t, func() { require.NoError(t, writeJSON(v)) })
+	assert.Equal(t, want, out)
+
+	// --output writes it to a file instead
+	outputPath = filepath.Join(t.TempDir(), "out.json")
+	out = captureStdout(t, func() { require.NoError(t, writeJSON(v)) })
+	assert.Empty(t, out)
+	data, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+
+	outputPath = filepath.Join(t.TempDir(), "missing", "out.json")
+	assert.ErrorContains(t, writeJSON(v), "failed to write "+outputPath)
+	assert.Error(t, writeJSON(map[string]interface{}{"ch": make(chan int)}))
+}
+
+func TestGenConfigCommand(t *testing.T) {
+	defer func() { outputPath, databaseType, serverName = "", "snowflake", "db-gateway" }()
+	path := filepath.Join(t.TempDir(), "db-gateway.json")
+
+	rootCmd.SetArgs([]string{"gen-config", "--name", "sales", "-o", path})
+	require.NoError(t, rootCmd.Execute())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	cfg, err := server.FromJSON(data)
+	require.NoError(t, err)
+	assert.Equal(t, "sales", cfg.Name)
+	assert.Equal(t, "snowflake", cfg.Database.Type)
+	assert.Equal(t, "PUBLIC", cfg.Database.Snowflake.Schema)
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, true, raw["table_tools"])
+
+	rootCmd.SetArgs([]string{"gen-config", "--type", "oracle", "-o", path})
+	assert.ErrorContains(t, rootCmd.Execute(), `unsupported database type "oracle"`)
+}
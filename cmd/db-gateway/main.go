@@ -0,0 +1,407 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/secrets"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/server"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/version"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	configPath       string
+	dryRun           bool
+	failOnRegression bool
+	jsonOutput       bool
+	listenAddr       string
+	outputPath       string
+	databaseType     string
+	serverName       string
+
+	versionCmd = &cobra.Command{
+		Use:   "version",
+		Short: "Print the version number of db-gateway",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Printf("db-gateway version %s\n", version.Get())
+		},
+	}
+
+	migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending state store migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrate()
+		},
+	}
+
+	migrateStatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending state store migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateStatus()
+		},
+	}
+
+	secretsCmd = &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the encryption of stored server secrets",
+	}
+
+	secretsRotateCmd = &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-encrypt stored server secrets with the current key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSecretsRotate()
+		},
+	}
+
+	evalCmd = &cobra.Command{
+		Use:   "eval",
+		Short: "Evaluate NL-to-SQL quality",
+	}
+
+	evalRunCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Run the labeled eval cases and report regressions against the previous run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEval()
+		},
+	}
+
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Run the registered servers and the server and tenant management API",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe()
+		},
+	}
+
+	introspectCmd = &cobra.Command{
+		Use:   "introspect [table...]",
+		Short: "Print the metadata of the database tables as JSON",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIntrospect(args)
+		},
+	}
+
+	generateCmd = &cobra.Command{
+		Use:   "generate",
+		Short: "Generate artifacts from the database schema",
+	}
+
+	generateOpenAPICmd = &cobra.Command{
+		Use:   "openapi [table...]",
+		Short: "Print the OpenAPI document of the endpoints generated for the tables",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenerateOpenAPI(args)
+		},
+	}
+
+	testConnectionCmd = &cobra.Command{
+		Use:   "test-connection",
+		Short: "Check that the configured database is reachable",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTestConnection()
+		},
+	}
+
+	genConfigCmd = &cobra.Command{
+		Use:   "gen-config",
+		Short: "Print a starter configuration file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenConfig()
+		},
+	}
+
+	rootCmd = &cobra.Command{
+		Use:          "db-gateway",
+		Short:        "MCP Database Gateway",
+		Long:         `MCP Database Gateway exposes database tables as MCP tools and REST endpoints`,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&configPath, "conf", "c", "db-gateway.json", "path to configuration file")
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "list pending migrations without applying them")
+	migrateCmd.AddCommand(migrateStatusCmd)
+	evalRunCmd.Flags().BoolVar(&failOnRegression, "fail-on-regression", false, "exit with an error when a previously passing case fails")
+	evalRunCmd.Flags().BoolVar(&jsonOutput, "json", false, "print the full report as JSON")
+	evalCmd.AddCommand(evalRunCmd)
+	secretsCmd.AddCommand(secretsRotateCmd)
+	serveCmd.Flags().StringVar(&listenAddr, "listen", ":8080", "listen address of the server management API")
+	for _, cmd := range []*cobra.Command{introspectCmd, generateOpenAPICmd, genConfigCmd} {
+		cmd.Flags().StringVarP(&outputPath, "output", "o", "", "write to a file instead of standard output")
+	}
+	generateCmd.AddCommand(generateOpenAPICmd)
+	genConfigCmd.Flags().StringVar(&databaseType, "type", "snowflake", "database type")
+	genConfigCmd.Flags().StringVar(&serverName, "name", "db-gateway", "server name")
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(evalCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(introspectCmd)
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(testConnectionCmd)
+	rootCmd.AddCommand(genConfigCmd)
+}
+
+// loadConfig reads the server configuration file
+func loadConfig() (*server.MCPServerConfig, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	return server.FromJSON(data)
+}
+
+// openStateStore opens the configured state store without migrating it
+func openStateStore() (*state.Store, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.State == nil {
+		return nil, fmt.Errorf("no state store configured in %s", configPath)
+	}
+	return state.Open(cfg.State)
+}
+
+func runServe() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.State == nil {
+		return fmt.Errorf("serve requires a state store configured in %s", configPath)
+	}
+	store, err := state.OpenAndMigrate(cfg.State)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	recorder, err := audit.NewRecorder(cfg.Audit, store.DB)
+	if err != nil {
+		return fmt.Errorf("failed to create audit recorder: %w", err)
+	}
+	servers, err := openServerStore(cfg, store)
+	if err != nil {
+		return err
+	}
+	registry := server.NewRegistry(servers, recorder)
+	registry.Admin = cfg.Admin
+
+	bus, err := events.NewBus(cfg.Events)
+	if err != nil {
+		return fmt.Errorf("failed to create event sinks: %w", err)
+	}
+	defer bus.Close()
+	registry.Events = bus
+	registry.Federation = cfg.Federation
+
+	// The server in the configuration file is registered on first boot;
+	// afterwards the registry is the source of truth
+	ctx := context.Background()
+	if cfg.Name != "" && cfg.Database != nil {
+		def := &server.ServerDefinition{Name: cfg.Name, Config: cfg, Running: true}
+		if _, err := registry.Create(ctx, def, "config"); err != nil && !errors.Is(err, server.ErrServerExists) {
+			return err
+		}
+	}
+	if err := registry.Restore(ctx); err != nil {
+		return err
+	}
+	defer registry.Shutdown()
+
+	router := gin.Default()
+	registry.SetupRoutes(router.Group("/"))
+	registry.SetupTenantRoutes(router)
+	httpServer := &http.Server{Addr: listenAddr, Handler: router}
+	go func() {
+		log.Printf("Starting server management API on %s", listenAddr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Server management API error: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// openServerStore opens the registry's server store, sealing secrets left
+// in plaintext or under a previous key when encryption is configured
+func openServerStore(cfg *server.MCPServerConfig, store *state.Store) (*server.ServerStore, error) {
+	keys, err := secrets.NewKeyring(cfg.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets configuration: %w", err)
+	}
+	servers := server.NewServerStore(store.DB)
+	servers.Secrets = keys
+	if keys != nil {
+		rotated, err := servers.RotateSecrets(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt server secrets: %w", err)
+		}
+		if rotated > 0 {
+			log.Printf("Encrypted the secrets of %d servers with the current key", rotated)
+		}
+	}
+	return servers, nil
+}
+
+func runSecretsRotate() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.State == nil {
+		return fmt.Errorf("no state store configured in %s", configPath)
+	}
+	if cfg.Secrets == nil {
+		return fmt.Errorf("no secrets encryption configured in %s", configPath)
+	}
+	keys, err := secrets.NewKeyring(cfg.Secrets)
+	if err != nil {
+		return fmt.Errorf("invalid secrets configuration: %w", err)
+	}
+	store, err := state.OpenAndMigrate(cfg.State)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	servers := server.NewServerStore(store.DB)
+	servers.Secrets = keys
+	rotated, err := servers.RotateSecrets(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Re-encrypted the secrets of %d servers\n", rotated)
+	return nil
+}
+
+func runMigrate() error {
+	store, err := openStateStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	migrations, err := store.Migrate(dryRun)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		fmt.Println("State store is up to date")
+		return nil
+	}
+
+	verb := "Applied"
+	if dryRun {
+		verb = "Pending"
+	}
+	for _, m := range migrations {
+		fmt.Printf("%s %04d_%s\n", verb, m.Version, m.Name)
+	}
+	return nil
+}
+
+func runMigrateStatus() error {
+	store, err := openStateStore()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	status, err := store.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Current version: %d\n", status.Current)
+	fmt.Printf("Latest version:  %d\n", status.Latest)
+	for _, m := range status.Applied {
+		fmt.Printf("  applied  %04d_%s (%s)\n", m.Version, m.Name, m.AppliedAt.Format("2006-01-02 15:04:05"))
+	}
+	for _, m := range status.Pending {
+		fmt.Printf("  pending  %04d_%s\n", m.Version, m.Name)
+	}
+	return nil
+}
+
+func runEval() error {
+	ctx := context.Background()
+	srv, err := connectServer(ctx)
+	if err != nil {
+		return err
+	}
+	defer srv.DBConn.Disconnect(ctx)
+
+	report, err := srv.RunEval(ctx)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		run := report.Run
+		fmt.Printf("Run %s: %d/%d passed (%.1f%%), prompt version %d\n", run.ID, run.Passed, run.Total, run.Accuracy*100, run.PromptVersion)
+		for _, r := range run.Results {
+			status := "PASS"
+			if !r.Passed {
+				status = "FAIL"
+			}
+			fmt.Printf("  %s  %s\n", status, r.Question)
+			if r.Error != "" {
+				fmt.Printf("        %s\n", r.Error)
+			}
+		}
+		if report.Baseline != nil {
+			fmt.Printf("Accuracy change since run %s: %+.1f%%\n", report.Baseline.ID, report.AccuracyDelta*100)
+			for _, r := range report.Regressions {
+				fmt.Printf("  REGRESSED  %s\n", r.Question)
+			}
+			for _, r := range report.Fixed {
+				fmt.Printf("  FIXED      %s\n", r.Question)
+			}
+		}
+	}
+
+	if failOnRegression && len(report.Regressions) > 0 {
+		return fmt.Errorf("%d eval cases regressed", len(report.Regressions))
+	}
+	return nil
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
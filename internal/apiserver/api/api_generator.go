@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"slices"
 
 	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
 )
@@ -12,6 +12,7 @@ import (
 // APIGenerator handles the generation of API endpoints from database schemas
 type APIGenerator struct {
 	connector connector.DatabaseConnector
+	dialect   connector.Dialect
 	config    *APIGeneratorConfig
 }
 
@@ -20,6 +21,9 @@ type APIGeneratorConfig struct {
 	EnableLLM       bool   `json:"enable_llm"`
 	APIPrefix       string `json:"api_prefix"`
 	IncludeMetadata bool   `json:"include_metadata"`
+
+	// Overrides customize the endpoints generated for tables
+	Overrides EndpointOverrides `json:"overrides,omitempty"`
 }
 
 // NewAPIGenerator creates a new API generator
@@ -30,9 +34,10 @@ func NewAPIGenerator(dbConn connector.DatabaseConnector, config *APIGeneratorCon
 			IncludeMetadata: true,
 		}
 	}
-	
+
 	return &APIGenerator{
 		connector: dbConn,
+		dialect:   connector.DialectOf(dbConn),
 		config:    config,
 	}
 }
@@ -42,38 +47,46 @@ func (g *APIGenerator) GenerateAPIFromTables(ctx context.Context, tables []strin
 	if g.connector == nil {
 		return nil, fmt.Errorf("database connector is not initialized")
 	}
-	
+
 	// If no tables specified, get all tables
 	if len(tables) == 0 {
 		tableList, err := g.connector.ListTables(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list tables: %w", err)
 		}
-		
+
 		for _, table := range tableList {
 			tables = append(tables, table.Name)
 		}
 	}
-	
+
 	// Generate endpoints for each table
 	var allEndpoints []connector.APIEndpoint
-	
+
 	for _, tableName := range tables {
 		endpoints, err := g.generateEndpointsForTable(ctx, tableName)
 		if err != nil {
 			log.Printf("Warning: Failed to generate endpoints for table %s: %v", tableName, err)
 			continue
 		}
-		
+		if override, ok := g.config.Overrides.lookup(tableName); ok {
+			if endpoints, err = override.apply(tableName, endpoints); err != nil {
+				return nil, err
+			}
+		}
+		for i := range endpoints {
+			connector.OrderPage(g.dialect, &endpoints[i])
+		}
+
 		allEndpoints = append(allEndpoints, endpoints...)
 	}
-	
+
 	// Add metadata endpoints if enabled
 	if g.config.IncludeMetadata {
 		metadataEndpoints := g.generateMetadataEndpoints()
 		allEndpoints = append(allEndpoints, metadataEndpoints...)
 	}
-	
+
 	return allEndpoints, nil
 }
 
@@ -84,7 +97,7 @@ func (g *APIGenerator) generateEndpointsForTable(ctx context.Context, tableName
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metadata for table %s: %w", tableName, err)
 	}
-	
+
 	// Enhance metadata with LLM if enabled
 	if g.config.EnableLLM {
 		if err := g.connector.EnhanceMetadataWithLLM(ctx, metadata); err != nil {
@@ -92,90 +105,145 @@ func (g *APIGenerator) generateEndpointsForTable(ctx context.Context, tableName
 			// Continue anyway, this is not critical
 		}
 	}
-	
-	// Find primary key column
-	var primaryKeyColumn string
-	for _, col := range metadata.Columns {
-		if col.PrimaryKey {
-			primaryKeyColumn = col.Name
-			break
+
+	// Find the primary key columns, or the unique key configured for a
+	// table without one
+	primaryKey := connector.PrimaryKeyColumns(metadata.Columns)
+	if len(primaryKey) == 0 {
+		if primaryKey, err = g.config.Overrides.KeyColumns(tableName, metadata.Columns); err != nil {
+			return nil, err
 		}
 	}
-	
+	keyNames := connector.ColumnNames(primaryKey)
+
 	// Generate endpoints
 	var endpoints []connector.APIEndpoint
-	
-	// Base path for this table
+
+	// Base path for this table, and the path of a record with a segment per
+	// key column (/table/:k1/:k2)
 	basePath := fmt.Sprintf("/%s", tableName)
-	
+	keyPath := basePath
+	for _, key := range primaryKey {
+		keyPath += "/:" + connector.ParamName(key.Name)
+	}
+
 	// List endpoint (GET /table)
 	listEndpoint := connector.APIEndpoint{
 		Method:      "GET",
+		Operation:   connector.OperationList,
 		Path:        basePath,
-		Description: fmt.Sprintf("List records from %s table", tableName),
-		Query:       fmt.Sprintf("SELECT * FROM %s LIMIT :limit OFFSET :offset", quoteTableName(tableName)),
+		Description: operationDescription(metadata, connector.OperationList, fmt.Sprintf("List records from %s table", tableName)),
+		Query:       connector.SelectPageQuery(g.dialect, tableName),
 		Parameters: map[string]interface{}{
 			"limit":  "Number of records to return (default: 100)",
 			"offset": "Number of records to skip (default: 0)",
 		},
+		Params:  connector.PageParams(),
+		OrderBy: keyNames,
 	}
+	g.addTimeTravel(&listEndpoint)
 	endpoints = append(endpoints, listEndpoint)
-	
-	// If primary key exists, add get by ID endpoint
-	if primaryKeyColumn != "" {
-		getByIdEndpoint := connector.APIEndpoint{
+
+	// Search endpoint (GET /table/search) over the text columns
+	if textColumns := connector.TextColumns(metadata.Columns); len(textColumns) > 0 {
+		searchEndpoint := connector.APIEndpoint{
 			Method:      "GET",
-			Path:        fmt.Sprintf("%s/:%s", basePath, primaryKeyColumn),
-			Description: fmt.Sprintf("Get a record from %s by ID", tableName),
-			Query:       fmt.Sprintf("SELECT * FROM %s WHERE %s = :%s", quoteTableName(tableName), quoteColumnName(primaryKeyColumn), primaryKeyColumn),
+			Operation:   connector.OperationSearch,
+			Path:        basePath + "/search",
+			Description: operationDescription(metadata, connector.OperationSearch, fmt.Sprintf("Search the text columns of %s table", tableName)),
+			Query:       connector.SearchQuery(g.dialect, tableName, textColumns),
 			Parameters: map[string]interface{}{
-				primaryKeyColumn: fmt.Sprintf("ID of the %s record", tableName),
+				connector.SearchParam: "Text to search for",
+				"columns":             "Comma-separated columns to search (default: all text columns)",
+				"limit":               "Number of records to return (default: 100)",
+				"offset":              "Number of records to skip (default: 0)",
 			},
+			Params:        connector.SearchParams(),
+			SearchColumns: textColumns,
 		}
+		g.addTimeTravel(&searchEndpoint)
+		endpoints = append(endpoints, searchEndpoint)
+	}
+
+	// If primary key exists, add get by ID endpoint
+	if len(primaryKey) > 0 {
+		getByIdEndpoint := connector.APIEndpoint{
+			Method:      "GET",
+			Operation:   connector.OperationGet,
+			Path:        keyPath,
+			Description: operationDescription(metadata, connector.OperationGet, fmt.Sprintf("Get a record from %s by ID", tableName)),
+			Query:       connector.SelectByKeyQuery(g.dialect, tableName, keyNames...),
+			Parameters:  keyParameters(tableName, primaryKey, "record"),
+			Params:      connector.KeyParams(tableName, primaryKey),
+		}
+		g.addTimeTravel(&getByIdEndpoint)
 		endpoints = append(endpoints, getByIdEndpoint)
-		
+
 		// Add delete endpoint
 		deleteEndpoint := connector.APIEndpoint{
 			Method:      "DELETE",
-			Path:        fmt.Sprintf("%s/:%s", basePath, primaryKeyColumn),
-			Description: fmt.Sprintf("Delete a record from %s by ID", tableName),
-			Query:       fmt.Sprintf("DELETE FROM %s WHERE %s = :%s", quoteTableName(tableName), quoteColumnName(primaryKeyColumn), primaryKeyColumn),
-			Parameters: map[string]interface{}{
-				primaryKeyColumn: fmt.Sprintf("ID of the %s record to delete", tableName),
-			},
+			Operation:   connector.OperationDelete,
+			Path:        keyPath,
+			Description: operationDescription(metadata, connector.OperationDelete, fmt.Sprintf("Delete a record from %s by ID", tableName)),
+			Query:       connector.DeleteQuery(g.dialect, tableName, keyNames...),
+			Parameters:  keyParameters(tableName, primaryKey, "record to delete"),
+			Params:      connector.KeyParams(tableName, primaryKey),
 		}
 		endpoints = append(endpoints, deleteEndpoint)
 	}
-	
+
 	// Add create endpoint (POST /table)
 	createEndpoint := connector.APIEndpoint{
 		Method:      "POST",
+		Operation:   connector.OperationCreate,
 		Path:        basePath,
-		Description: fmt.Sprintf("Create a new record in %s table", tableName),
-		Query:       g.generateInsertQuery(tableName, metadata.Columns),
+		Description: operationDescription(metadata, connector.OperationCreate, fmt.Sprintf("Create a new record in %s table", tableName)),
+		Query:       connector.InsertQuery(g.dialect, tableName, metadata.Columns),
 		Parameters:  g.generateColumnParameters(metadata.Columns),
+		Columns:     bodyColumns(connector.InsertColumns(g.dialect, metadata.Columns)),
+		KeyColumns:  keyNames,
 	}
+	createEndpoint.Params = connector.InsertParams(createEndpoint.Columns)
 	endpoints = append(endpoints, createEndpoint)
-	
+
 	// Add update endpoint if primary key exists (PUT /table/:id)
-	if primaryKeyColumn != "" {
+	if len(primaryKey) > 0 {
 		updateEndpoint := connector.APIEndpoint{
 			Method:      "PUT",
-			Path:        fmt.Sprintf("%s/:%s", basePath, primaryKeyColumn),
-			Description: fmt.Sprintf("Update a record in %s table", tableName),
-			Query:       g.generateUpdateQuery(tableName, metadata.Columns, primaryKeyColumn),
+			Operation:   connector.OperationUpdate,
+			Path:        keyPath,
+			Description: operationDescription(metadata, connector.OperationUpdate, fmt.Sprintf("Update a record in %s table", tableName)),
+			Query:       connector.UpdateQuery(g.dialect, tableName, keyNames, metadata.Columns),
 			Parameters:  g.generateColumnParameters(metadata.Columns),
+			Columns:     bodyColumns(metadata.Columns, keyNames...),
+			KeyColumns:  keyNames,
 		}
+		if version, ok := connector.VersionColumn(metadata.Columns); ok {
+			connector.VersionUpdate(g.dialect, &updateEndpoint, tableName, keyNames, version, metadata.Columns)
+		}
+		updateEndpoint.Params = append(connector.KeyParams(tableName, primaryKey), connector.BodyParams(updateEndpoint.Columns)...)
 		endpoints = append(endpoints, updateEndpoint)
 	}
-	
+
 	return endpoints, nil
 }
 
+// addTimeTravel adds the time travel parameters to a read endpoint when the
+// database can read tables as they were at a point in time
+func (g *APIGenerator) addTimeTravel(endpoint *connector.APIEndpoint) {
+	if _, ok := g.dialect.(connector.TimeTraveler); !ok {
+		return
+	}
+	for _, param := range connector.TimeTravelParams() {
+		endpoint.Parameters[param.Name] = param.Description
+		endpoint.Params = append(endpoint.Params, param)
+	}
+}
+
 // generateMetadataEndpoints generates API endpoints for metadata operations
 func (g *APIGenerator) generateMetadataEndpoints() []connector.APIEndpoint {
 	var endpoints []connector.APIEndpoint
-	
+
 	// List tables endpoint
 	listTablesEndpoint := connector.APIEndpoint{
 		Method:      "GET",
@@ -185,7 +253,7 @@ func (g *APIGenerator) generateMetadataEndpoints() []connector.APIEndpoint {
 		Parameters:  map[string]interface{}{},
 	}
 	endpoints = append(endpoints, listTablesEndpoint)
-	
+
 	// Get table metadata endpoint
 	tableMetadataEndpoint := connector.APIEndpoint{
 		Method:      "GET",
@@ -197,7 +265,7 @@ func (g *APIGenerator) generateMetadataEndpoints() []connector.APIEndpoint {
 		},
 	}
 	endpoints = append(endpoints, tableMetadataEndpoint)
-	
+
 	// Custom query endpoint
 	customQueryEndpoint := connector.APIEndpoint{
 		Method:      "POST",
@@ -210,84 +278,67 @@ func (g *APIGenerator) generateMetadataEndpoints() []connector.APIEndpoint {
 		},
 	}
 	endpoints = append(endpoints, customQueryEndpoint)
-	
+
 	return endpoints
 }
 
 // Helper functions
 
-// generateInsertQuery generates an INSERT query for a table
-func (g *APIGenerator) generateInsertQuery(tableName string, columns []connector.Column) string {
-	var columnNames []string
-	var paramNames []string
-	
-	for _, col := range columns {
-		// Skip auto-increment primary keys for insert
-		if col.PrimaryKey && isAutoIncrementType(col.Type) {
-			continue
-		}
-		
-		columnNames = append(columnNames, quoteColumnName(col.Name))
-		paramNames = append(paramNames, fmt.Sprintf(":%s", col.Name))
+// keyParameters describes the path parameters of a record's key columns
+func keyParameters(tableName string, keys []connector.Column, what string) map[string]interface{} {
+	params := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		params[connector.ParamName(key.Name)] = fmt.Sprintf("%s of the %s %s", key.Name, tableName, what)
 	}
-	
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
-		quoteTableName(tableName),
-		strings.Join(columnNames, ", "),
-		strings.Join(paramNames, ", "))
+	return params
 }
 
-// generateUpdateQuery generates an UPDATE query for a table
-func (g *APIGenerator) generateUpdateQuery(tableName string, columns []connector.Column, primaryKeyColumn string) string {
-	var setParts []string
-	
+// bodyColumns returns the columns a write endpoint accepts in its request
+// body, so the body is validated against their types. The keys taken from
+// the path are left out.
+func bodyColumns(columns []connector.Column, pathKeys ...string) []connector.Column {
+	var body []connector.Column
 	for _, col := range columns {
-		// Skip primary key for update set clause
-		if col.Name == primaryKeyColumn {
+		if slices.Contains(pathKeys, col.Name) {
 			continue
 		}
-		
-		setParts = append(setParts, fmt.Sprintf("%s = :%s", quoteColumnName(col.Name), col.Name))
+		body = append(body, connector.Column{
+			Name:       col.Name,
+			Type:       col.Type,
+			PrimaryKey: col.PrimaryKey,
+			Nullable:   col.Nullable,
+			Default:    col.Default,
+			MaxLength:  col.MaxLength,
+			Precision:  col.Precision,
+			Scale:      col.Scale,
+		})
 	}
-	
-	return fmt.Sprintf("UPDATE %s SET %s WHERE %s = :%s",
-		quoteTableName(tableName),
-		strings.Join(setParts, ", "),
-		quoteColumnName(primaryKeyColumn),
-		primaryKeyColumn)
+	return body
 }
 
 // generateColumnParameters generates parameter descriptions for columns
 func (g *APIGenerator) generateColumnParameters(columns []connector.Column) map[string]interface{} {
 	params := make(map[string]interface{})
-	
+
 	for _, col := range columns {
 		description := col.Name
-		if col.Description != "" {
+		if col.VerboseDescription != "" {
+			description = col.VerboseDescription
+		} else if col.Description != "" {
 			description = col.Description
 		}
-		
-		params[col.Name] = description
-	}
-	
-	return params
-}
 
-// quoteTableName quotes a table name for SQL
-func quoteTableName(tableName string) string {
-	return fmt.Sprintf("\"%s\"", tableName)
-}
+		params[connector.ParamName(col.Name)] = description
+	}
 
-// quoteColumnName quotes a column name for SQL
-func quoteColumnName(columnName string) string {
-	return fmt.Sprintf("\"%s\"", columnName)
+	return params
 }
 
-// isAutoIncrementType checks if a column type is likely auto-increment
-func isAutoIncrementType(columnType string) bool {
-	// This is a simplistic check and may need to be database-specific
-	lowerType := strings.ToLower(columnType)
-	return strings.Contains(lowerType, "identity") ||
-		strings.Contains(lowerType, "autoincrement") ||
-		strings.Contains(lowerType, "serial")
+// operationDescription returns the LLM-generated description of a table
+// operation, falling back to the given default
+func operationDescription(metadata *connector.TableMetadata, operation, fallback string) string {
+	if desc := metadata.ToolDescriptions[operation]; desc != "" {
+		return desc
+	}
+	return fallback
 }
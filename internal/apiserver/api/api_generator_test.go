@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// metadataConnector serves the metadata of its tables
+type metadataConnector struct {
+	connector.DatabaseConnector
+	tables map[string]*connector.TableMetadata
+}
+
+func (c *metadataConnector) GetTableMetadata(_ context.Context, table string) (*connector.TableMetadata, error) {
+	return c.tables[table], nil
+}
+
+func TestGenerateCompositeKeyEndpoints(t *testing.T) {
+	conn := &metadataConnector{tables: map[string]*connector.TableMetadata{
+		"ORDER_ITEMS": {Name: "ORDER_ITEMS", Columns: []connector.Column{
+			{Name: "ORDER_ID", Type: "INTEGER", PrimaryKey: true},
+			{Name: "LINE", Type: "INTEGER", PrimaryKey: true},
+			{Name: "QTY", Type: "INTEGER"},
+		}},
+	}}
+	g := NewAPIGenerator(conn, &APIGeneratorConfig{})
+
+	endpoints, err := g.GenerateAPIFromTables(context.Background(), []string{"ORDER_ITEMS"})
+	require.NoError(t, err)
+	byOperation := make(map[string]connector.APIEndpoint)
+	for _, e := range endpoints {
+		byOperation[e.Operation] = e
+	}
+
+	for _, op := range []string{connector.OperationGet, connector.OperationUpdate, connector.OperationDelete} {
+		assert.Equal(t, "/ORDER_ITEMS/:ORDER_ID/:LINE", byOperation[op].Path, op)
+	}
+	assert.Equal(t, `SELECT * FROM "ORDER_ITEMS" WHERE "ORDER_ID" = :ORDER_ID AND "LINE" = :LINE`, byOperation[connector.OperationGet].Query)
+	assert.Equal(t, `UPDATE "ORDER_ITEMS" SET "QTY" = :QTY WHERE "ORDER_ID" = :ORDER_ID AND "LINE" = :LINE`, byOperation[connector.OperationUpdate].Query)
+	assert.Equal(t, `DELETE FROM "ORDER_ITEMS" WHERE "ORDER_ID" = :ORDER_ID AND "LINE" = :LINE`, byOperation[connector.OperationDelete].Query)
+	assert.Equal(t, []connector.Column{{Name: "QTY", Type: "INTEGER"}}, byOperation[connector.OperationUpdate].Columns)
+
+	var keyParams []string
+	for _, p := range byOperation[connector.OperationGet].Params {
+		keyParams = append(keyParams, p.Name)
+	}
+	assert.Equal(t, []string{"ORDER_ID", "LINE"}, keyParams)
+}
+
+func TestGenerateConfiguredKeyEndpoints(t *testing.T) {
+	conn := &metadataConnector{tables: map[string]*connector.TableMetadata{
+		"EVENTS": {Name: "EVENTS", Columns: []connector.Column{
+			{Name: "EVENT_ID", Type: "VARCHAR"},
+			{Name: "PAYLOAD", Type: "VARCHAR", Nullable: true},
+		}},
+	}}
+
+	// Tables without a primary key only get key endpoints when configured
+	endpoints, err := NewAPIGenerator(conn, &APIGeneratorConfig{}).GenerateAPIFromTables(context.Background(), []string{"EVENTS"})
+	require.NoError(t, err)
+	for _, e := range endpoints {
+		assert.NotEqual(t, connector.OperationGet, e.Operation)
+	}
+	// nor a stable order for their pages
+	assert.Equal(t, `SELECT * FROM "EVENTS" LIMIT :limit OFFSET :offset`, endpoints[0].Query)
+	assert.Contains(t, endpoints[0].Warning, "order_by")
+
+	config := &APIGeneratorConfig{Overrides: EndpointOverrides{"EVENTS": {Key: []string{"EVENT_ID"}}}}
+	require.NoError(t, config.Overrides.Validate())
+	endpoints, err = NewAPIGenerator(conn, config).GenerateAPIFromTables(context.Background(), []string{"EVENTS"})
+	require.NoError(t, err)
+	var operations []string
+	for _, e := range endpoints {
+		operations = append(operations, e.Operation)
+		if e.Operation == connector.OperationList {
+			assert.Equal(t, `SELECT * FROM "EVENTS" ORDER BY "EVENT_ID" LIMIT :limit OFFSET :offset`, e.Query)
+			assert.Empty(t, e.Warning)
+		}
+		if e.Operation == connector.OperationDelete {
+			assert.Equal(t, "/EVENTS/:EVENT_ID", e.Path)
+			assert.Equal(t, `DELETE FROM "EVENTS" WHERE "EVENT_ID" = :EVENT_ID`, e.Query)
+		}
+	}
+	assert.Equal(t, []string{connector.OperationList, connector.OperationSearch, connector.OperationGet, connector.OperationDelete,
+		connector.OperationCreate, connector.OperationUpdate}, operations)
+
+	assert.Error(t, EndpointOverrides{"EVENTS": {Key: []string{"EVENT_ID", "EVENT_ID"}}}.Validate())
+}
+
+// snowflakeConnector serves metadata with the Snowflake dialect
+type snowflakeConnector struct {
+	metadataConnector
+}
+
+func (c *snowflakeConnector) Dialect() connector.Dialect {
+	return connector.SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+}
+
+func TestGenerateTimeTravelParams(t *testing.T) {
+	tables := map[string]*connector.TableMetadata{
+		"ORDERS": {Name: "ORDERS", Columns: []connector.Column{
+			{Name: "ID", Type: "INTEGER", PrimaryKey: true},
+			{Name: "NOTE", Type: "VARCHAR"},
+		}},
+	}
+	hasTimeTravel := func(conn connector.DatabaseConnector) map[string]bool {
+		endpoints, err := NewAPIGenerator(conn, &APIGeneratorConfig{}).GenerateAPIFromTables(context.Background(), []string{"ORDERS"})
+		require.NoError(t, err)
+		found := make(map[string]bool)
+		for _, e := range endpoints {
+			for _, p := range e.Params {
+				if p.Name == connector.AtTimestampParam {
+					found[e.Operation] = true
+				}
+			}
+		}
+		return found
+	}
+
+	// Only the read endpoints of databases with time travel take them
+	assert.Equal(t, map[string]bool{connector.OperationList: true, connector.OperationSearch: true, connector.OperationGet: true},
+		hasTimeTravel(&snowflakeConnector{metadataConnector{tables: tables}}))
+	assert.Empty(t, hasTimeTravel(&metadataConnector{tables: tables}))
+}
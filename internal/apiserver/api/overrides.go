@@ -0,0 +1,211 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// operations are the table operations overrides refer to
+var operations = map[string]bool{
+	connector.OperationList:   true,
+	connector.OperationSearch: true,
+	connector.OperationGet:    true,
+	connector.OperationCreate: true,
+	connector.OperationUpdate: true,
+	connector.OperationDelete: true,
+}
+
+// EndpointOverride customizes the endpoints generated for a table
+type EndpointOverride struct {
+	// Path replaces the table name in the endpoints' paths, e.g. "/orders"
+	Path string `json:"path,omitempty"`
+
+	// Hide lists the operations whose endpoints are not generated
+	Hide []string `json:"hide,omitempty"`
+
+	// Filter is a SQL condition added to the generated queries reading,
+	// updating and deleting rows, e.g. "DELETED_AT IS NULL"
+	Filter string `json:"filter,omitempty"`
+
+	// Queries replace the SQL of operations entirely, keyed by operation.
+	// Their parameters are bound from the path and the query string.
+	Queries map[string]string `json:"queries,omitempty"`
+
+	// Key names the unique column, or columns, identifying the records of
+	// a table without a primary key, so the endpoints getting, updating and
+	// deleting a record are generated for it
+	Key []string `json:"key,omitempty"`
+
+	// OrderBy names the columns ordering the pages of the list endpoint,
+	// e.g. a unique column of a table without a primary key. It defaults
+	// to the primary key, or to Key.
+	OrderBy []string `json:"order_by,omitempty"`
+}
+
+// EndpointOverrides are the overrides of tables, keyed by table name
+type EndpointOverrides map[string]EndpointOverride
+
+// Validate checks the operations, paths and filters of the overrides
+func (o EndpointOverrides) Validate() error {
+	for table, override := range o {
+		if override.Path != "" && (!strings.HasPrefix(override.Path, "/") || strings.ContainsAny(override.Path, "{}:*")) {
+			return fmt.Errorf("table %s: path %q must start with / and contain no parameters", table, override.Path)
+		}
+		for _, op := range override.Hide {
+			if !operations[op] {
+				return fmt.Errorf("table %s: unknown operation %q to hide", table, op)
+			}
+		}
+		for op, query := range override.Queries {
+			if !operations[op] {
+				return fmt.Errorf("table %s: unknown operation %q to replace", table, op)
+			}
+			if strings.TrimSpace(query) == "" {
+				return fmt.Errorf("table %s: empty query for %s", table, op)
+			}
+		}
+		if override.Filter != "" {
+			if _, err := connector.AddRowFilter("SELECT * FROM t", override.Filter); err != nil {
+				return fmt.Errorf("table %s: %w", table, err)
+			}
+		}
+		if !namedOnce(override.Key) {
+			return fmt.Errorf("table %s: key columns must be named once each", table)
+		}
+		if !namedOnce(override.OrderBy) {
+			return fmt.Errorf("table %s: order_by columns must be named once each", table)
+		}
+	}
+	return nil
+}
+
+// namedOnce reports whether columns are named, and each only once
+func namedOnce(columns []string) bool {
+	seen := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		if strings.TrimSpace(col) == "" || seen[col] {
+			return false
+		}
+		seen[col] = true
+	}
+	return true
+}
+
+// HasKey reports whether a key is configured for a table
+func (o EndpointOverrides) HasKey(table string) bool {
+	override, ok := o.lookup(table)
+	return ok && len(override.Key) > 0
+}
+
+// KeyColumns returns the columns of a table's configured key, or nil when
+// none is configured. Configured columns the table lacks are an error.
+func (o EndpointOverrides) KeyColumns(table string, columns []connector.Column) ([]connector.Column, error) {
+	override, ok := o.lookup(table)
+	if !ok || len(override.Key) == 0 {
+		return nil, nil
+	}
+	byName := make(map[string]connector.Column, len(columns))
+	for _, col := range columns {
+		byName[col.Name] = col
+	}
+	keys := make([]connector.Column, 0, len(override.Key))
+	for _, name := range override.Key {
+		col, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("table %s: key column %s does not exist", table, name)
+		}
+		keys = append(keys, col)
+	}
+	return keys, nil
+}
+
+// lookup returns the override of a table, matching its name case-insensitively
+func (o EndpointOverrides) lookup(table string) (EndpointOverride, bool) {
+	if override, ok := o[table]; ok {
+		return override, true
+	}
+	for name, override := range o {
+		if strings.EqualFold(name, table) {
+			return override, true
+		}
+	}
+	return EndpointOverride{}, false
+}
+
+// Apply customizes generated endpoints by the overrides of their tables.
+// Endpoints without a table are left alone.
+func (o EndpointOverrides) Apply(endpoints []connector.APIEndpoint) ([]connector.APIEndpoint, error) {
+	var applied []connector.APIEndpoint
+	for _, e := range endpoints {
+		override, ok := o.lookup(e.Table)
+		if e.Table == "" || !ok {
+			applied = append(applied, e)
+			continue
+		}
+		customized, err := override.apply(e.Table, []connector.APIEndpoint{e})
+		if err != nil {
+			return nil, err
+		}
+		applied = append(applied, customized...)
+	}
+	return applied, nil
+}
+
+// apply customizes the endpoints generated for a table
+func (o EndpointOverride) apply(table string, endpoints []connector.APIEndpoint) ([]connector.APIEndpoint, error) {
+	hidden := make(map[string]bool, len(o.Hide))
+	for _, op := range o.Hide {
+		hidden[op] = true
+	}
+
+	applied := make([]connector.APIEndpoint, 0, len(endpoints))
+	for _, e := range endpoints {
+		if e.Operation == "" {
+			applied = append(applied, e)
+			continue
+		}
+		if hidden[e.Operation] {
+			continue
+		}
+
+		if o.Path != "" {
+			if rest, ok := strings.CutPrefix(e.Path, "/"+table); ok && (rest == "" || rest[0] == '/') {
+				e.Path = strings.TrimRight(o.Path, "/") + rest
+			}
+		}
+
+		if query, ok := o.Queries[e.Operation]; ok {
+			// Replaced SQL is used as is; the generated query's extras no
+			// longer apply to it
+			e.Query = query
+			e.SearchColumns = nil
+			e.VersionColumn = ""
+			e.KeyColumns = nil
+			e.OrderBy = nil
+		} else if o.Filter != "" && e.Operation != connector.OperationCreate {
+			filtered, err := connector.AddRowFilter(e.Query, o.Filter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add filter to %s %s: %w", e.Method, e.Path, err)
+			}
+			e.Query = filtered
+			e.Filter = o.Filter
+		}
+		_, replaced := o.Queries[e.Operation]
+		switch e.Operation {
+		case connector.OperationList:
+			if len(o.OrderBy) > 0 {
+				e.OrderBy = o.OrderBy
+			} else if len(e.OrderBy) == 0 && len(o.Key) > 0 && !replaced {
+				e.OrderBy = o.Key
+			}
+		case connector.OperationCreate:
+			if len(e.KeyColumns) == 0 && len(o.Key) > 0 && !replaced {
+				e.KeyColumns = o.Key
+			}
+		}
+		applied = append(applied, e)
+	}
+	return applied, nil
+}
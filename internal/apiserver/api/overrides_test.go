@@ -0,0 +1,61 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestEndpointOverrides(t *testing.T) {
+	d := connector.ANSIDialect{}
+	endpoints := []connector.APIEndpoint{
+		{Table: "ORDERS", Operation: connector.OperationList, Method: "GET", Path: "/ORDERS", Query: connector.SelectPageQuery(d, "ORDERS")},
+		{Table: "ORDERS", Operation: connector.OperationGet, Method: "GET", Path: "/ORDERS/{ID}", Query: connector.SelectByKeyQuery(d, "ORDERS", "ID")},
+		{Table: "ORDERS", Operation: connector.OperationCreate, Method: "POST", Path: "/ORDERS", Query: `INSERT INTO "ORDERS" ("ID") VALUES (:ID)`},
+		{Table: "ORDERS", Operation: connector.OperationDelete, Method: "DELETE", Path: "/ORDERS/{ID}", Query: connector.DeleteQuery(d, "ORDERS", "ID")},
+		{Table: "ORDERS_ARCHIVE", Operation: connector.OperationList, Method: "GET", Path: "/ORDERS_ARCHIVE", Query: connector.SelectPageQuery(d, "ORDERS_ARCHIVE")},
+	}
+	overrides := EndpointOverrides{
+		"orders": {
+			Path:    "/orders",
+			Hide:    []string{connector.OperationDelete},
+			Filter:  "DELETED_AT IS NULL",
+			Queries: map[string]string{connector.OperationGet: "SELECT ID, STATUS FROM ORDERS WHERE ID = :ID"},
+		},
+	}
+	require.NoError(t, overrides.Validate())
+
+	applied, err := overrides.Apply(endpoints)
+	require.NoError(t, err)
+	require.Len(t, applied, 4)
+	assert.Equal(t, "/orders", applied[0].Path)
+	assert.Equal(t, `SELECT * FROM "ORDERS" WHERE (DELETED_AT IS NULL) LIMIT :limit OFFSET :offset`, applied[0].Query)
+	assert.Equal(t, "DELETED_AT IS NULL", applied[0].Filter)
+	assert.Equal(t, "/orders/{ID}", applied[1].Path)
+	assert.Equal(t, "SELECT ID, STATUS FROM ORDERS WHERE ID = :ID", applied[1].Query)
+	assert.Equal(t, endpoints[2].Query, applied[2].Query)
+	assert.Equal(t, endpoints[4], applied[3])
+
+	// The configured order of pages replaces the key's, and a key orders
+	// the pages of a table without a primary key
+	endpoints[0].OrderBy = []string{"ID"}
+	applied, err = EndpointOverrides{"ORDERS": {OrderBy: []string{"CREATED_AT", "ID"}}, "ORDERS_ARCHIVE": {Key: []string{"ORDER_ID"}}}.Apply(endpoints)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CREATED_AT", "ID"}, applied[0].OrderBy)
+	assert.Nil(t, applied[1].OrderBy)
+	assert.Equal(t, []string{"ORDER_ID"}, applied[4].OrderBy)
+
+	for _, invalid := range []EndpointOverride{
+		{Path: "orders"},
+		{Path: "/orders/{ID}"},
+		{Hide: []string{"truncate"}},
+		{Queries: map[string]string{connector.OperationList: " "}},
+		{Filter: "1 = 1; DROP TABLE ORDERS"},
+		{OrderBy: []string{"ID", ""}},
+	} {
+		assert.Error(t, EndpointOverrides{"ORDERS": invalid}.Validate())
+	}
+}
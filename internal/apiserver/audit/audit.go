@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event represents a single audited action
+type Event struct {
+	Time        time.Time              `json:"time"`
+	Action      string                 `json:"action"`
+	Principal   string                 `json:"principal"`
+	Resource    string                 `json:"resource,omitempty"`
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// Filter narrows down the events returned by a recorder
+type Filter struct {
+	Action      string
+	Principal   string
+	Fingerprint string
+	Limit       int
+}
+
+// Recorder persists audit events
+type Recorder interface {
+	// Record stores an audit event
+	Record(ctx context.Context, event *Event) error
+
+	// List returns the recorded events matching the filter, newest first
+	List(ctx context.Context, filter Filter) ([]*Event, error)
+}
+
+// Config holds the configuration for audit recording
+type Config struct {
+	// Type of recorder (memory, file, db)
+	Type string `json:"type"`
+
+	// Path of the JSON lines file (only used when type is file)
+	Path string `json:"path,omitempty"`
+
+	// Maximum number of events kept in memory (only used when type is memory)
+	MaxEvents int `json:"max_events,omitempty"`
+}
+
+// NewRecorder creates a recorder based on the configuration. db is the
+// gateway's state store and is only required for the db recorder.
+func NewRecorder(cfg *Config, db *gorm.DB) (Recorder, error) {
+	if cfg == nil || cfg.Type == "" || cfg.Type == "memory" {
+		maxEvents := 0
+		if cfg != nil {
+			maxEvents = cfg.MaxEvents
+		}
+		return NewMemoryRecorder(maxEvents), nil
+	}
+
+	switch cfg.Type {
+	case "file":
+		return NewFileRecorder(cfg.Path)
+	case "db":
+		return NewDBRecorder(db)
+	default:
+		return nil, fmt.Errorf("unsupported audit recorder type: %s", cfg.Type)
+	}
+}
+
+// matches reports whether the event satisfies the filter
+func (f Filter) matches(event *Event) bool {
+	if f.Action != "" && f.Action != event.Action {
+		return false
+	}
+	if f.Principal != "" && f.Principal != event.Principal {
+		return false
+	}
+	if f.Fingerprint != "" && f.Fingerprint != event.Fingerprint {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// dbEvent is the row representation of an audit event
+type dbEvent struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	Time        time.Time
+	Action      string
+	Principal   string
+	Resource    string
+	Fingerprint string
+	Details     string
+}
+
+// TableName overrides the table name used by dbEvent
+func (dbEvent) TableName() string {
+	return "audit_events"
+}
+
+// DBRecorder stores audit events in the gateway's state store. The
+// audit_events table is created by the state store migrations.
+type DBRecorder struct {
+	db *gorm.DB
+}
+
+// NewDBRecorder creates a new database-backed recorder
+func NewDBRecorder(db *gorm.DB) (*DBRecorder, error) {
+	if db == nil {
+		return nil, fmt.Errorf("audit recorder type db requires a state store")
+	}
+	return &DBRecorder{
+		db: db,
+	}, nil
+}
+
+// Record stores an audit event
+func (r *DBRecorder) Record(ctx context.Context, event *Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	row := &dbEvent{
+		Time:        event.Time,
+		Action:      event.Action,
+		Principal:   event.Principal,
+		Resource:    event.Resource,
+		Fingerprint: event.Fingerprint,
+	}
+	if len(event.Details) > 0 {
+		data, err := json.Marshal(event.Details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit details: %w", err)
+		}
+		row.Details = string(data)
+	}
+
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// List returns the recorded events matching the filter, newest first
+func (r *DBRecorder) List(ctx context.Context, filter Filter) ([]*Event, error) {
+	query := r.db.WithContext(ctx).Model(&dbEvent{})
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Principal != "" {
+		query = query.Where("principal = ?", filter.Principal)
+	}
+	if filter.Fingerprint != "" {
+		query = query.Where("fingerprint = ?", filter.Fingerprint)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var rows []dbEvent
+	if err := query.Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read audit events: %w", err)
+	}
+
+	events := make([]*Event, 0, len(rows))
+	for _, row := range rows {
+		event := &Event{
+			Time:        row.Time,
+			Action:      row.Action,
+			Principal:   row.Principal,
+			Resource:    row.Resource,
+			Fingerprint: row.Fingerprint,
+		}
+		if row.Details != "" {
+			if err := json.Unmarshal([]byte(row.Details), &event.Details); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit details: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultMaxEvents = 10000
+
+// MemoryRecorder keeps the most recent audit events in memory
+type MemoryRecorder struct {
+	mu        sync.RWMutex
+	events    []*Event
+	maxEvents int
+}
+
+// NewMemoryRecorder creates a new in-memory recorder
+func NewMemoryRecorder(maxEvents int) *MemoryRecorder {
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxEvents
+	}
+	return &MemoryRecorder{
+		maxEvents: maxEvents,
+	}
+}
+
+// Record stores an audit event
+func (r *MemoryRecorder) Record(_ context.Context, event *Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+	if len(r.events) > r.maxEvents {
+		r.events = r.events[len(r.events)-r.maxEvents:]
+	}
+	return nil
+}
+
+// List returns the recorded events matching the filter, newest first
+func (r *MemoryRecorder) List(_ context.Context, filter Filter) ([]*Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Event
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if !filter.matches(r.events[i]) {
+			continue
+		}
+		result = append(result, r.events[i])
+		if filter.Limit > 0 && len(result) >= filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
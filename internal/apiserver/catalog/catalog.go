@@ -0,0 +1,219 @@
+// Package catalog reads curated table documentation from data catalogs,
+// DataHub and OpenMetadata, and reports the gateway's usage of the tables
+// back to them
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Catalog types
+const (
+	TypeDataHub      = "datahub"
+	TypeOpenMetadata = "openmetadata"
+)
+
+const defaultInterval = time.Hour
+
+// ErrNotFound is returned for tables the catalog does not know
+var ErrNotFound = errors.New("table not found in catalog")
+
+// Config configures the catalog a server's tables are documented in
+type Config struct {
+	// Type of catalog (datahub, openmetadata)
+	Type string `json:"type"`
+
+	// URL of the DataHub GMS or the OpenMetadata server
+	URL string `json:"url"`
+
+	// Token is sent as a bearer token: a DataHub personal access token or
+	// an OpenMetadata bot token
+	Token string `json:"token,omitempty"`
+
+	// Headers are added to every request
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Platform is the DataHub data platform of the tables (default: snowflake)
+	Platform string `json:"platform,omitempty"`
+
+	// Environment is the DataHub environment of the tables (default: PROD)
+	Environment string `json:"environment,omitempty"`
+
+	// Service is the OpenMetadata database service of the tables
+	Service string `json:"service,omitempty"`
+
+	// Database and Schema qualify the tables' names in the catalog; they
+	// default to the ones the server connects to
+	Database string `json:"database,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+
+	// Interval between syncs with the catalog (default: 1h)
+	Interval string `json:"interval,omitempty"`
+
+	// PushUsage reports the number of gateway queries of each table to the
+	// catalog at every sync
+	PushUsage bool `json:"push_usage,omitempty"`
+}
+
+// Validate checks the configuration
+func (c *Config) Validate() error {
+	switch c.Type {
+	case TypeDataHub:
+	case TypeOpenMetadata:
+		if c.Service == "" {
+			return fmt.Errorf("openmetadata service is required")
+		}
+	default:
+		return fmt.Errorf("unsupported catalog type: %s", c.Type)
+	}
+	if c.URL == "" {
+		return fmt.Errorf("%s url is required", c.Type)
+	}
+	if _, err := url.Parse(c.URL); err != nil {
+		return fmt.Errorf("invalid %s url: %w", c.Type, err)
+	}
+	if _, err := c.SyncInterval(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SyncInterval returns the interval between syncs
+func (c *Config) SyncInterval() (time.Duration, error) {
+	if c.Interval == "" {
+		return defaultInterval, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid catalog interval: %s", c.Interval)
+	}
+	return d, nil
+}
+
+// TableName is the qualified name of a table
+type TableName struct {
+	Database string
+	Schema   string
+	Table    string
+}
+
+// TableDoc is the curated documentation of a table
+type TableDoc struct {
+	Description string   `json:"description,omitempty"`
+	Owners      []string `json:"owners,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Columns documents the table's columns by name as the catalog has
+	// them, which may differ in case from the database's
+	Columns map[string]ColumnDoc `json:"columns,omitempty"`
+}
+
+// ColumnDoc is the curated documentation of a column
+type ColumnDoc struct {
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// Column returns the documentation of a column, matching its name
+// case-insensitively
+func (d *TableDoc) Column(name string) (ColumnDoc, bool) {
+	if doc, ok := d.Columns[name]; ok {
+		return doc, true
+	}
+	for n, doc := range d.Columns {
+		if strings.EqualFold(n, name) {
+			return doc, true
+		}
+	}
+	return ColumnDoc{}, false
+}
+
+// Usage is the gateway's usage of a table over a period
+type Usage struct {
+	Start   time.Time
+	End     time.Time
+	Queries int64
+}
+
+// Client reads and updates a catalog
+type Client interface {
+	// Table returns the documentation of a table, or ErrNotFound
+	Table(ctx context.Context, name TableName) (*TableDoc, error)
+
+	// PushUsage reports the usage of a table
+	PushUsage(ctx context.Context, name TableName, usage Usage) error
+}
+
+// New creates the client of the configured catalog
+func New(cfg *Config) (Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	api := &apiClient{baseURL: strings.TrimSuffix(cfg.URL, "/"), token: cfg.Token, headers: cfg.Headers}
+	if cfg.Type == TypeDataHub {
+		return newDataHub(api, cfg.Platform, cfg.Environment), nil
+	}
+	return newOpenMetadata(api, cfg.Service), nil
+}
+
+// apiClient sends JSON requests to a catalog's API
+type apiClient struct {
+	baseURL string
+	token   string
+	headers map[string]string
+}
+
+// do sends a request with a JSON body, when not nil, and decodes the JSON
+// response into out, when not nil. A 404 response returns ErrNotFound.
+func (a *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	for k, v := range a.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach catalog: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("catalog returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	case out == nil:
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode catalog response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataHub(t *testing.T) {
+	var pushed map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if r.URL.Path == "/aspects" {
+			pushed = body["proposal"].(map[string]interface{})
+			return
+		}
+
+		urn := body["variables"].(map[string]interface{})["urn"]
+		if urn != "urn:li:dataset:(urn:li:dataPlatform:snowflake,sales.public.orders,PROD)" {
+			_, _ = io.WriteString(w, `{"data": {"dataset": null}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data": {"dataset": {
+			"properties": {"description": "Ingested description"},
+			"editableProperties": {"description": "Orders placed in the web shop"},
+			"ownership": {"owners": [{"owner": {"username": "jane"}}, {"owner": {"name": "sales-eng"}}]},
+			"tags": {"tags": [{"tag": {"name": "gold"}}]},
+			"schemaMetadata": {"fields": [
+				{"fieldPath": "[version=2.0].[type=struct].[type=string].status", "description": "Ingested"},
+				{"fieldPath": "[version=2.0].[type=struct].[type=struct].address.[type=string].city", "description": "Nested"}
+			]},
+			"editableSchemaMetadata": {"editableSchemaFieldInfo": [
+				{"fieldPath": "status", "description": "Fulfilment status", "tags": {"tags": [{"tag": {"name": "enum"}}]}}
+			]}
+		}}}`)
+	}))
+	defer srv.Close()
+
+	client, err := New(&Config{Type: TypeDataHub, URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	doc, err := client.Table(context.Background(), TableName{Database: "SALES", Schema: "PUBLIC", Table: "ORDERS"})
+	require.NoError(t, err)
+	assert.Equal(t, &TableDoc{
+		Description: "Orders placed in the web shop",
+		Owners:      []string{"jane", "sales-eng"},
+		Tags:        []string{"gold"},
+		Columns:     map[string]ColumnDoc{"status": {Description: "Fulfilment status", Tags: []string{"enum"}}},
+	}, doc)
+	col, ok := doc.Column("STATUS")
+	assert.True(t, ok)
+	assert.Equal(t, "Fulfilment status", col.Description)
+
+	_, err = client.Table(context.Background(), TableName{Database: "SALES", Schema: "PUBLIC", Table: "RETURNS"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, client.PushUsage(context.Background(), TableName{Database: "SALES", Schema: "PUBLIC", Table: "ORDERS"}, Usage{Start: start, Queries: 42}))
+	assert.Equal(t, "datasetUsageStatistics", pushed["aspectName"])
+	aspect := pushed["aspect"].(map[string]interface{})
+	assert.JSONEq(t, `{"timestampMillis": 1792141200000, "totalSqlQueries": 42}`, aspect["value"].(string))
+}
+
+func TestOpenMetadata(t *testing.T) {
+	var usage string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v1/tables/name/warehouse.SALES.PUBLIC.ORDERS":
+			assert.Equal(t, "owners,tags,columns", r.URL.Query().Get("fields"))
+			_, _ = io.WriteString(w, `{
+				"description": "Orders placed in the web shop",
+				"owners": [{"name": "jane", "type": "user"}],
+				"tags": [{"tagFQN": "Tier.Tier1"}],
+				"columns": [{"name": "EMAIL", "description": "Customer email", "tags": [{"tagFQN": "PII.Sensitive"}]}]
+			}`)
+		case "/api/v1/usage/table/name/warehouse.SALES.PUBLIC.ORDERS":
+			body, _ := io.ReadAll(r.Body)
+			usage = string(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := New(&Config{Type: TypeOpenMetadata, URL: srv.URL, Service: "warehouse"})
+	require.NoError(t, err)
+	name := TableName{Database: "SALES", Schema: "PUBLIC", Table: "ORDERS"}
+	doc, err := client.Table(context.Background(), name)
+	require.NoError(t, err)
+	assert.Equal(t, &TableDoc{
+		Description: "Orders placed in the web shop",
+		Owners:      []string{"jane"},
+		Tags:        []string{"Tier.Tier1"},
+		Columns:     map[string]ColumnDoc{"EMAIL": {Description: "Customer email", Tags: []string{"PII.Sensitive"}}},
+	}, doc)
+
+	_, err = client.Table(context.Background(), TableName{Database: "SALES", Schema: "PUBLIC", Table: "RETURNS"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, client.PushUsage(context.Background(), name, Usage{Start: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), Queries: 7}))
+	assert.JSONEq(t, `{"date": "2026-10-16", "count": 7}`, usage)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, cfg := range []Config{
+		{Type: "atlas", URL: "http://atlas"},
+		{Type: TypeDataHub},
+		{Type: TypeOpenMetadata, URL: "http://om"},
+		{Type: TypeDataHub, URL: "http://datahub", Interval: "soon"},
+	} {
+		assert.Error(t, cfg.Validate(), cfg)
+	}
+	assert.NoError(t, (&Config{Type: TypeDataHub, URL: "http://datahub", Interval: "15m"}).Validate())
+}
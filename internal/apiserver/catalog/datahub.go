@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultDataHubPlatform    = "snowflake"
+	defaultDataHubEnvironment = "PROD"
+)
+
+// dataHubDatasetQuery reads the documentation of a dataset with DataHub's
+// GraphQL API. Editable properties hold what users curated in DataHub and
+// take precedence over the ingested ones.
+const dataHubDatasetQuery = `query dataset($urn: String!) {
+  dataset(urn: $urn) {
+    properties { description }
+    editableProperties { description }
+    ownership { owners { owner { ... on CorpUser { username } ... on CorpGroup { name } } } }
+    tags { tags { tag { name } } }
+    schemaMetadata { fields { fieldPath description tags { tags { tag { name } } } } }
+    editableSchemaMetadata { editableSchemaFieldInfo { fieldPath description tags { tags { tag { name } } } } }
+  }
+}`
+
+// dataHub reads datasets from DataHub's GraphQL API and writes usage
+// statistics with its Rest.li API
+type dataHub struct {
+	api         *apiClient
+	platform    string
+	environment string
+}
+
+func newDataHub(api *apiClient, platform, environment string) *dataHub {
+	if platform == "" {
+		platform = defaultDataHubPlatform
+	}
+	if environment == "" {
+		environment = defaultDataHubEnvironment
+	}
+	return &dataHub{api: api, platform: platform, environment: environment}
+}
+
+// urn returns the URN of a table's dataset. DataHub's ingestion lowercases
+// the names of Snowflake tables by default.
+func (d *dataHub) urn(name TableName) string {
+	var parts []string
+	for _, part := range []string{name.Database, name.Schema, name.Table} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	qualified := strings.Join(parts, ".")
+	if d.platform == defaultDataHubPlatform {
+		qualified = strings.ToLower(qualified)
+	}
+	return fmt.Sprintf("urn:li:dataset:(urn:li:dataPlatform:%s,%s,%s)", d.platform, qualified, d.environment)
+}
+
+type dataHubTags struct {
+	Tags []struct {
+		Tag struct {
+			Name string `json:"name"`
+		} `json:"tag"`
+	} `json:"tags"`
+}
+
+func (t *dataHubTags) names() []string {
+	if t == nil {
+		return nil
+	}
+	names := make([]string, 0, len(t.Tags))
+	for _, tag := range t.Tags {
+		names = append(names, tag.Tag.Name)
+	}
+	return names
+}
+
+type dataHubField struct {
+	FieldPath   string       `json:"fieldPath"`
+	Description string       `json:"description"`
+	Tags        *dataHubTags `json:"tags"`
+}
+
+// Table reads the documentation of a table's dataset
+func (d *dataHub) Table(ctx context.Context, name TableName) (*TableDoc, error) {
+	var resp struct {
+		Data struct {
+			Dataset *struct {
+				Properties         *struct{ Description string } `json:"properties"`
+				EditableProperties *struct{ Description string } `json:"editableProperties"`
+				Ownership          *struct {
+					Owners []struct {
+						Owner struct {
+							Username string `json:"username"`
+							Name     string `json:"name"`
+						} `json:"owner"`
+					} `json:"owners"`
+				} `json:"ownership"`
+				Tags           *dataHubTags `json:"tags"`
+				SchemaMetadata *struct {
+					Fields []dataHubField `json:"fields"`
+				} `json:"schemaMetadata"`
+				EditableSchemaMetadata *struct {
+					Fields []dataHubField `json:"editableSchemaFieldInfo"`
+				} `json:"editableSchemaMetadata"`
+			} `json:"dataset"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err := d.api.do(ctx, http.MethodPost, "/api/graphql", map[string]interface{}{
+		"query":     dataHubDatasetQuery,
+		"variables": map[string]string{"urn": d.urn(name)},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("datahub query failed: %s", resp.Errors[0].Message)
+	}
+	dataset := resp.Data.Dataset
+	if dataset == nil {
+		return nil, ErrNotFound
+	}
+
+	doc := &TableDoc{Tags: dataset.Tags.names(), Columns: make(map[string]ColumnDoc)}
+	if dataset.Properties != nil {
+		doc.Description = dataset.Properties.Description
+	}
+	if dataset.EditableProperties != nil && dataset.EditableProperties.Description != "" {
+		doc.Description = dataset.EditableProperties.Description
+	}
+	if dataset.Ownership != nil {
+		for _, o := range dataset.Ownership.Owners {
+			if owner := o.Owner.Username + o.Owner.Name; owner != "" {
+				doc.Owners = append(doc.Owners, owner)
+			}
+		}
+	}
+	if dataset.SchemaMetadata != nil {
+		addDataHubFields(doc, dataset.SchemaMetadata.Fields)
+	}
+	if dataset.EditableSchemaMetadata != nil {
+		addDataHubFields(doc, dataset.EditableSchemaMetadata.Fields)
+	}
+	return doc, nil
+}
+
+// addDataHubFields merges the documentation of top-level fields into a
+// table's, later non-empty descriptions replacing earlier ones and tags
+// adding up
+func addDataHubFields(doc *TableDoc, fields []dataHubField) {
+	for _, f := range fields {
+		name := dataHubColumn(f.FieldPath)
+		if strings.Contains(name, ".") {
+			continue
+		}
+		col := doc.Columns[name]
+		if f.Description != "" {
+			col.Description = f.Description
+		}
+		col.Tags = append(col.Tags, f.Tags.names()...)
+		doc.Columns[name] = col
+	}
+}
+
+// dataHubColumn strips the [version=2.0].[type=...] annotations of version
+// 2 field paths, leaving the dotted path of the field
+func dataHubColumn(fieldPath string) string {
+	var parts []string
+	for _, part := range strings.Split(fieldPath, ".") {
+		if !strings.HasPrefix(part, "[") {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// PushUsage upserts the dataset usage statistics of the period
+func (d *dataHub) PushUsage(ctx context.Context, name TableName, usage Usage) error {
+	stats, err := json.Marshal(map[string]interface{}{
+		"timestampMillis": usage.Start.UnixMilli(),
+		"totalSqlQueries": usage.Queries,
+	})
+	if err != nil {
+		return err
+	}
+	return d.api.do(ctx, http.MethodPost, "/aspects?action=ingestProposal", map[string]interface{}{
+		"proposal": map[string]interface{}{
+			"entityType": "dataset",
+			"entityUrn":  d.urn(name),
+			"changeType": "UPSERT",
+			"aspectName": "datasetUsageStatistics",
+			"aspect": map[string]string{
+				"contentType": "application/json",
+				"value":       string(stats),
+			},
+		},
+	}, nil)
+}
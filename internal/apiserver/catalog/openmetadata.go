@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// openMetadata reads tables from OpenMetadata's REST API and reports their
+// daily usage to it
+type openMetadata struct {
+	api     *apiClient
+	service string
+}
+
+func newOpenMetadata(api *apiClient, service string) *openMetadata {
+	return &openMetadata{api: api, service: service}
+}
+
+// fqn returns the fully qualified name of a table: its service, database,
+// schema and name, quoting names containing dots
+func (o *openMetadata) fqn(name TableName) string {
+	var parts []string
+	for _, part := range []string{o.service, name.Database, name.Schema, name.Table} {
+		if part == "" {
+			continue
+		}
+		if strings.Contains(part, ".") {
+			part = `"` + part + `"`
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+type openMetadataTag struct {
+	TagFQN string `json:"tagFQN"`
+}
+
+func tagNames(tags []openMetadataTag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.TagFQN
+	}
+	return names
+}
+
+// Table reads the documentation of a table
+func (o *openMetadata) Table(ctx context.Context, name TableName) (*TableDoc, error) {
+	var table struct {
+		Description string `json:"description"`
+		Owners      []struct {
+			Name        string `json:"name"`
+			DisplayName string `json:"displayName"`
+		} `json:"owners"`
+		Tags    []openMetadataTag `json:"tags"`
+		Columns []struct {
+			Name        string            `json:"name"`
+			Description string            `json:"description"`
+			Tags        []openMetadataTag `json:"tags"`
+		} `json:"columns"`
+	}
+	path := "/api/v1/tables/name/" + url.PathEscape(o.fqn(name)) + "?fields=owners,tags,columns"
+	if err := o.api.do(ctx, http.MethodGet, path, nil, &table); err != nil {
+		return nil, err
+	}
+
+	doc := &TableDoc{Description: table.Description, Tags: tagNames(table.Tags), Columns: make(map[string]ColumnDoc, len(table.Columns))}
+	for _, owner := range table.Owners {
+		doc.Owners = append(doc.Owners, owner.Name)
+	}
+	for _, col := range table.Columns {
+		doc.Columns[col.Name] = ColumnDoc{Description: col.Description, Tags: tagNames(col.Tags)}
+	}
+	return doc, nil
+}
+
+// PushUsage adds the queries of the period to the usage of the day it
+// started
+func (o *openMetadata) PushUsage(ctx context.Context, name TableName, usage Usage) error {
+	path := "/api/v1/usage/table/name/" + url.PathEscape(o.fqn(name))
+	return o.api.do(ctx, http.MethodPost, path, map[string]interface{}{
+		"date":  usage.Start.UTC().Format("2006-01-02"),
+		"count": usage.Queries,
+	}, nil)
+}
@@ -0,0 +1,188 @@
+// Package cron parses cron schedules and computes their activation times
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearch bounds the search for the next activation; a valid schedule
+// always fires within a few years (e.g. February 29th)
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Schedule computes the activation times of a cron expression
+type Schedule interface {
+	// Next returns the first activation strictly after t, or the zero time
+	// when the schedule never fires
+	Next(t time.Time) time.Time
+}
+
+// descriptors are the supported shorthand schedules
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// bounds of the five cron fields. limit exceeds max where an alias is
+// accepted: 7 stands for Sunday in the day of week field.
+var bounds = []struct {
+	name            string
+	min, max, limit int
+}{
+	{"minute", 0, 59, 59},
+	{"hour", 0, 23, 23},
+	{"day of month", 1, 31, 31},
+	{"month", 1, 12, 12},
+	{"day of week", 0, 6, 7},
+}
+
+// Parse parses a standard five-field cron expression (minute, hour, day of
+// month, month, day of week) with lists, ranges and steps, one of the
+// @hourly style descriptors, or "@every <duration>". Expressions are
+// evaluated in the location of the time passed to Next.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("invalid interval in %q", spec)
+		}
+		return every(d), nil
+	}
+	if expanded, ok := descriptors[spec]; ok {
+		spec = expanded
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(bounds) {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", spec)
+	}
+
+	var s fields
+	for i, part := range parts {
+		set, err := parseField(part, bounds[i].min, bounds[i].max, bounds[i].limit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s field %q: %w", bounds[i].name, part, err)
+		}
+		s.sets[i] = set
+	}
+	// Sunday may be written as 7
+	if s.sets[4]&(1<<7) != 0 {
+		s.sets[4] |= 1
+	}
+	s.domStar = strings.HasPrefix(parts[2], "*")
+	s.dowStar = strings.HasPrefix(parts[4], "*")
+	return &s, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps
+// into a bit set
+func parseField(field string, min, max, limit int) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			from, to, _ := strings.Cut(rangePart, "-")
+			a, errA := strconv.Atoi(from)
+			b, errB := strconv.Atoi(to)
+			if errA != nil || errB != nil || a > b {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rangePart)
+			}
+			// A single value with a step, e.g. 5/15, runs to the maximum
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+
+		if lo < min || hi > limit {
+			return 0, fmt.Errorf("value out of range %d-%d", min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// fields is a parsed five-field expression
+type fields struct {
+	sets             [5]uint64
+	domStar, dowStar bool
+}
+
+// Next returns the first matching minute after t
+func (s *fields) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.Add(maxSearch)
+	for t.Before(end) {
+		if s.sets[3]&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.sets[1]&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.sets[0]&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that when both day fields are
+// restricted, a day matching either one fires
+func (s *fields) dayMatches(t time.Time) bool {
+	dom := s.sets[2]&(1<<uint(t.Day())) != 0
+	dow := s.sets[4]&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dow
+	case s.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// every fires at a fixed interval
+type every time.Duration
+
+// Next returns t plus the interval
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
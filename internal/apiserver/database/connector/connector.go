@@ -1,34 +1,280 @@
-package database
+package connector
 
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
 )
 
 // DatabaseConnector defines the interface for database operations in MCP servers
 type DatabaseConnector interface {
 	// Connect establishes a connection to the database
 	Connect(ctx context.Context) error
-	
+
 	// Disconnect closes the database connection
 	Disconnect(ctx context.Context) error
-	
+
 	// ListTables returns a list of available tables
 	ListTables(ctx context.Context) ([]Table, error)
-	
+
 	// GetTableMetadata retrieves detailed information about a table
 	GetTableMetadata(ctx context.Context, tableName string) (*TableMetadata, error)
-	
+
 	// ExecuteQuery runs a SQL query against the database
 	ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error)
-	
+
 	// GenerateAPIEndpoints creates API endpoints based on database tables
 	GenerateAPIEndpoints(ctx context.Context, tables []string) ([]APIEndpoint, error)
-	
+
 	// EnhanceMetadataWithLLM uses LLM to generate verbose descriptions
 	EnhanceMetadataWithLLM(ctx context.Context, metadata *TableMetadata) error
 }
 
+// BudgetGuard is implemented by connectors that can enforce a spend budget
+type BudgetGuard interface {
+	// CreditUsage returns the estimated credit usage for the current day
+	CreditUsage(ctx context.Context) (*CreditUsage, error)
+
+	// OverrideBudget allows queries over budget until the given time
+	OverrideBudget(until time.Time)
+
+	// ClearBudgetOverride removes an active budget override
+	ClearBudgetOverride()
+}
+
+// SpendAttributor is implemented by connectors that can break warehouse
+// spend down by the query tags set with WithQueryTag
+type SpendAttributor interface {
+	// SpendByQueryTag estimates the spend of the connection's queries
+	// between since and until, grouped by query tag
+	SpendByQueryTag(ctx context.Context, since, until time.Time) ([]TagSpend, error)
+}
+
+// SchemaDescriber is implemented by connectors that can describe the columns
+// of every table at once, without the row counts and samples gathered by
+// GetTableMetadata
+type SchemaDescriber interface {
+	// DescribeSchema returns the columns of every table, keyed by table name
+	DescribeSchema(ctx context.Context) (map[string][]Column, error)
+}
+
+// ForeignKeyLister is implemented by connectors that can list the foreign
+// keys of every table at once
+type ForeignKeyLister interface {
+	// ListForeignKeys returns the foreign keys between tables of the schema
+	ListForeignKeys(ctx context.Context) ([]ForeignKey, error)
+}
+
+// Transactor is implemented by connectors that can run several statements
+// atomically on a single connection
+type Transactor interface {
+	// BeginTransaction starts a transaction; the caller must end it with
+	// Commit or Rollback
+	BeginTransaction(ctx context.Context) (Transaction, error)
+}
+
+// Transaction is an open transaction on a dedicated connection
+type Transaction interface {
+	// ExecuteQuery runs a statement within the transaction
+	ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error)
+
+	// Savepoint marks a point the transaction can be rolled back to
+	Savepoint(ctx context.Context, name string) error
+
+	// RollbackToSavepoint undoes the statements run since a savepoint
+	RollbackToSavepoint(ctx context.Context, name string) error
+
+	// Commit makes the transaction's changes permanent
+	Commit() error
+
+	// Rollback discards the transaction's changes
+	Rollback() error
+}
+
+// ChangeStreamer is implemented by connectors that capture the changes made
+// to tables, e.g. with Snowflake streams
+type ChangeStreamer interface {
+	// CreateChangeStream starts capturing the changes of a table in the
+	// named stream unless the stream exists
+	CreateChangeStream(ctx context.Context, table, stream string) error
+
+	// ConsumeChanges returns the changes a stream captured since it was
+	// last consumed and moves the stream past them
+	ConsumeChanges(ctx context.Context, stream string) ([]RowChange, error)
+}
+
+// RoutineLister is implemented by connectors that can list the stored
+// procedures and user-defined functions of the schema
+type RoutineLister interface {
+	// ListRoutines returns the procedures and functions with their
+	// signatures, ordered by name
+	ListRoutines(ctx context.Context) ([]Routine, error)
+}
+
+// Actions of row changes
+const (
+	ChangeInsert = "insert"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// RowChange is a captured change of a row: the new row of inserts and
+// updates, the old row of deletes
+type RowChange struct {
+	Action string                 `json:"action"`
+	Row    map[string]interface{} `json:"row"`
+}
+
+// Planner is implemented by connectors that can plan a query without
+// executing it
+type Planner interface {
+	// ExplainQuery compiles a query with its parameters and returns the
+	// database's plan and cost estimate
+	ExplainQuery(ctx context.Context, query string, params map[string]interface{}) (*QueryPlan, error)
+}
+
+// QueryPlan is the execution plan of a query. The partitions and bytes the
+// plan assigns estimate the cost of running it.
+type QueryPlan struct {
+	PartitionsTotal    int64           `json:"partitions_total"`
+	PartitionsAssigned int64           `json:"partitions_assigned"`
+	BytesAssigned      int64           `json:"bytes_assigned"`
+	Operations         []PlanOperation `json:"operations,omitempty"`
+}
+
+// PlanOperation is a step of a query plan
+type PlanOperation struct {
+	ID          int      `json:"id"`
+	Parent      *int     `json:"parent,omitempty"`
+	Operation   string   `json:"operation"`
+	Objects     []string `json:"objects,omitempty"`
+	Expressions []string `json:"expressions,omitempty"`
+}
+
+// TagSpend is the estimated spend of the queries sharing a query tag
+type TagSpend struct {
+	QueryTag     string  `json:"query_tag"`
+	Queries      int     `json:"queries"`
+	ExecutionMs  float64 `json:"execution_ms"`
+	Credits      float64 `json:"credits"`
+	BytesScanned int64   `json:"bytes_scanned"`
+}
+
+type queryTagKey struct{}
+
+// WithQueryTag returns a context whose queries are tagged for spend
+// attribution, on databases that support query tags
+func WithQueryTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, queryTagKey{}, tag)
+}
+
+// QueryTagFromContext returns the query tag set with WithQueryTag
+func QueryTagFromContext(ctx context.Context) string {
+	tag, _ := ctx.Value(queryTagKey{}).(string)
+	return tag
+}
+
+// Export formats of Unloader
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// Unloader is implemented by connectors that can write query results to
+// cloud storage themselves, e.g. with Snowflake's COPY INTO
+type Unloader interface {
+	// Unload writes the rows of a query to files under a storage location
+	Unload(ctx context.Context, query string, params map[string]interface{}, target UnloadTarget) (*UnloadResult, error)
+}
+
+// UnloadTarget is where and how Unload writes a query's rows
+type UnloadTarget struct {
+	// URL of the location, e.g. s3://bucket/path/ or gcs://bucket/path/
+	URL string
+
+	// Format of the files, ExportFormatCSV or ExportFormatParquet
+	Format string
+
+	// StorageIntegration authorizes the database to write to the location
+	StorageIntegration string
+}
+
+// UnloadResult summarizes the files written by Unload
+type UnloadResult struct {
+	Rows  int64 `json:"rows"`
+	Bytes int64 `json:"bytes"`
+}
+
+// StageLoader is implemented by connectors that can bulk load rows through
+// a stage, e.g. with Snowflake's PUT and COPY INTO, faster than inserts
+type StageLoader interface {
+	// LoadRows loads rows of values of the given columns into a table in a
+	// single statement, returning the number of rows loaded
+	LoadRows(ctx context.Context, table string, columns []string, rows [][]interface{}) (int64, error)
+}
+
+// QueryCanceler is implemented by connectors that can cancel a query in the
+// database, beyond abandoning it by cancelling its context
+type QueryCanceler interface {
+	// CancelQuery cancels the query the database identifies by id, as
+	// reported to WithQueryIDReporter
+	CancelQuery(ctx context.Context, id string) error
+}
+
+// ResultPager is implemented by connectors whose database keeps the result
+// of a query, e.g. Snowflake's persisted query results, so that later
+// pages are read from it instead of running the query again
+type ResultPager interface {
+	// ExecuteQueryPage runs a query and returns up to limit of its first
+	// rows with the ID of the kept result
+	ExecuteQueryPage(ctx context.Context, query string, params map[string]interface{}, limit int) (*ResultPage, error)
+
+	// FetchResultPage returns up to limit rows of a kept result from offset
+	FetchResultPage(ctx context.Context, resultID string, offset, limit int) (*ResultPage, error)
+
+	// ResultRetention is how long after a query ran its result is kept
+	ResultRetention() time.Duration
+}
+
+// ResultPage is a page of the rows of a kept query result
+type ResultPage struct {
+	*ResultSet
+
+	// ResultID identifies the kept result
+	ResultID string
+
+	// More reports whether rows follow the page
+	More bool
+}
+
+type queryIDReporterKey struct{}
+
+// WithQueryIDReporter returns a context whose queries call report with the
+// ID the database assigned them, on databases that assign one
+func WithQueryIDReporter(ctx context.Context, report func(id string)) context.Context {
+	return context.WithValue(ctx, queryIDReporterKey{}, report)
+}
+
+// QueryIDReporterFromContext returns the function set with WithQueryIDReporter
+func QueryIDReporterFromContext(ctx context.Context) func(id string) {
+	report, _ := ctx.Value(queryIDReporterKey{}).(func(id string))
+	return report
+}
+
+// CreditUsage describes the estimated spend against the daily budget
+type CreditUsage struct {
+	Date          string    `json:"date"`
+	Credits       float64   `json:"credits"`
+	Budget        float64   `json:"budget"`
+	Blocked       bool      `json:"blocked"`
+	OverrideUntil time.Time `json:"override_until,omitempty"`
+	RefreshedAt   time.Time `json:"refreshed_at"`
+}
+
 // Table represents a database table
 type Table struct {
 	Name     string `json:"name"`
@@ -41,38 +287,178 @@ type Column struct {
 	Type        string      `json:"type"`
 	Description string      `json:"description,omitempty"`
 	PrimaryKey  bool        `json:"primary_key,omitempty"`
+	Nullable    bool        `json:"nullable,omitempty"`
+	Default     string      `json:"default,omitempty"`    // SQL expression of the default; empty when none
+	MaxLength   int         `json:"max_length,omitempty"` // characters; 0 when unbounded or unknown
+	Precision   int         `json:"precision,omitempty"`  // total digits of a numeric column; 0 when unknown
+	Scale       int         `json:"scale,omitempty"`      // digits after the decimal point of a numeric column
 	ForeignKey  bool        `json:"foreign_key,omitempty"`
-	References  string      `json:"references,omitempty"`
+	References  string      `json:"references,omitempty"` // TABLE.COLUMN of a foreign key
 	Sample      interface{} `json:"sample,omitempty"`
+
+	// Computed columns are SQL expressions configured on the gateway rather
+	// than stored in the table; they are read-only
+	Computed bool `json:"computed,omitempty"`
+
+	// VerboseDescription is generated by the LLM when enhancement is enabled
+	VerboseDescription string `json:"verbose_description,omitempty"`
+
+	// Tags are curated in a data catalog
+	Tags []string `json:"tags,omitempty"`
 }
 
+// SQLType renders the column's type with its length, or precision and
+// scale, e.g. VARCHAR(20) or NUMBER(10,2), unless the type already has them
+func (c Column) SQLType() string {
+	switch {
+	case strings.Contains(c.Type, "("):
+		return c.Type
+	case c.Precision > 0:
+		return fmt.Sprintf("%s(%d,%d)", c.Type, c.Precision, c.Scale)
+	case c.MaxLength > 0:
+		return fmt.Sprintf("%s(%d)", c.Type, c.MaxLength)
+	}
+	return c.Type
+}
+
+// Index is an index of a table. Databases without indexes report the keys
+// they organize data by instead, like Snowflake's clustering keys.
+type Index struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type,omitempty"` // IndexTypeClustering for clustering keys
+	Columns []string `json:"columns"`        // column names, or expressions of clustering keys
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// IndexTypeClustering is the type of the clustering key of a table
+const IndexTypeClustering = "clustering"
+
+// Constraint is a primary key, unique or check constraint of a table
+type Constraint struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"` // ConstraintPrimaryKey, ConstraintUnique or ConstraintCheck
+	Columns []string `json:"columns,omitempty"`
+	Check   string   `json:"check,omitempty"` // SQL condition of a check constraint
+}
+
+// ForeignKey is a foreign key from columns of a table to the columns they
+// reference, in key order
+type ForeignKey struct {
+	Name              string   `json:"name,omitempty"`
+	Table             string   `json:"table"`
+	Columns           []string `json:"columns"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+}
+
+// Constraint types
+const (
+	ConstraintPrimaryKey = "PRIMARY KEY"
+	ConstraintUnique     = "UNIQUE"
+	ConstraintCheck      = "CHECK"
+)
+
 // TableMetadata contains enhanced metadata for a table
 type TableMetadata struct {
-	Name              string                   `json:"name"`
-	Description       string                   `json:"description,omitempty"`
-	Columns           []Column                 `json:"columns"`
-	SampleData        []map[string]interface{} `json:"sample_data,omitempty"`
-	RowCount          int                      `json:"row_count"`
-	VerboseDescription string                  `json:"verbose_description,omitempty"`
+	Name               string                   `json:"name"`
+	Description        string                   `json:"description,omitempty"`
+	Columns            []Column                 `json:"columns"`
+	SampleData         []map[string]interface{} `json:"sample_data,omitempty"`
+	RowCount           int                      `json:"row_count"`
+	VerboseDescription string                   `json:"verbose_description,omitempty"`
+
+	// Owners and Tags are curated in a data catalog
+	Owners []string `json:"owners,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+
+	// Indexes and Constraints tell which filters the database can serve
+	// efficiently and which values are unique
+	Indexes     []Index      `json:"indexes,omitempty"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+
+	// ToolDescriptions holds generated descriptions of the table's operations,
+	// keyed by operation (list, get, create, update, delete)
+	ToolDescriptions map[string]string `json:"tool_descriptions,omitempty"`
 }
 
+// Table operations used to key generated tool descriptions
+const (
+	OperationList   = "list"
+	OperationGet    = "get"
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationSearch = "search"
+)
+
 // APIEndpoint represents a generated API endpoint
 type APIEndpoint struct {
+	// Table the endpoint was generated for
+	Table string `json:"table,omitempty"`
+
+	// Operation is the table operation of the endpoint, e.g. OperationList
+	Operation string `json:"operation,omitempty"`
+
 	Method      string                 `json:"method"`
 	Path        string                 `json:"path"`
 	Description string                 `json:"description"`
 	Query       string                 `json:"query"`
 	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+
+	// Params are the typed definitions of the endpoint's path, query and
+	// body parameters; see ParamDefs for endpoints generated without them
+	Params []Parameter `json:"params,omitempty"`
+
+	// Columns accepted in the JSON body of create and update endpoints;
+	// request bodies are validated against their types
+	Columns []Column `json:"columns,omitempty"`
+
+	// SearchColumns are the text columns searched by a search endpoint;
+	// requests may narrow the search to some of them
+	SearchColumns []string `json:"search_columns,omitempty"`
+
+	// VersionColumn is the column an update endpoint checks for concurrent
+	// edits: the request body carries the version it read, and the update
+	// only applies while the row still has it
+	VersionColumn string `json:"version_column,omitempty"`
+
+	// Filter is a condition added to the endpoint's query by configuration;
+	// queries rebuilt per request, such as narrowed searches, keep it
+	Filter string `json:"filter,omitempty"`
+
+	// KeyColumns identify the row a create or update endpoint writes, so
+	// that the row can be read back from the bound key values and returned
+	KeyColumns []string `json:"key_columns,omitempty"`
+
+	// OrderBy are the columns ordering the rows of a list endpoint whose
+	// query pages through them without an ORDER BY; see OrderPage
+	OrderBy []string `json:"order_by,omitempty"`
+
+	// Warning tells about a problem with the endpoint, e.g. pages whose
+	// rows may come in a different order on every request
+	Warning string `json:"warning,omitempty"`
 }
 
 // DatabaseConfig holds the configuration for database connections
 type DatabaseConfig struct {
 	// Type of database (snowflake, postgres, etc.)
 	Type string `json:"type"`
-	
+
 	// Specific configuration for each database type
 	Snowflake *SnowflakeConfig `json:"snowflake,omitempty"`
-	// Other database types can be added here
+
+	// Custom holds the configuration section of a connector registered with
+	// RegisterConfig, read from the object named after Type
+	Custom interface{} `json:"-"`
+
+	// LLM configures the provider used to enhance metadata descriptions
+	LLM *llm.Config `json:"llm,omitempty"`
+
+	// Provider and Prompts let the embedding server share its LLM provider
+	// and prompt templates with the connector. When Provider is nil one is
+	// created from LLM; when Prompts is nil the built-in templates are used.
+	Provider llm.Provider     `json:"-"`
+	Prompts  *prompt.Registry `json:"-"`
 }
 
 // SnowflakeConfig holds Snowflake-specific configuration
@@ -87,6 +473,27 @@ type SnowflakeConfig struct {
 	PrivateKey     string `json:"private_key,omitempty"`
 	PrivateKeyPath string `json:"private_key_path,omitempty"`
 	AuthType       string `json:"auth_type"` // "password" or "key_pair"
+
+	// DailyCreditBudget blocks further queries once the estimated credits
+	// consumed today exceed it. Zero disables the guard.
+	DailyCreditBudget float64 `json:"daily_credit_budget,omitempty"`
+
+	// ResultTypes controls how NUMBER, TIMESTAMP, VARIANT and BINARY values
+	// are returned
+	ResultTypes *ResultTypeConfig `json:"result_types,omitempty"`
+
+	// Sampling controls the sample rows returned with table metadata
+	Sampling *SampleConfig `json:"sampling,omitempty"`
+
+	// Lazy opens sessions on first use instead of when connecting, and
+	// closes them once idle for IdleTimeout, so that a rarely used database
+	// holds no sessions. Servers that query the database on a schedule, e.g.
+	// to warm up or watch the schema, cannot use it.
+	Lazy bool `json:"lazy,omitempty"`
+
+	// IdleTimeout is how long an idle session of a lazy connection is kept
+	// (default: 5m)
+	IdleTimeout string `json:"idle_timeout,omitempty"`
 }
 
 // Factory for creating database connectors
@@ -95,11 +502,18 @@ func NewDatabaseConnector(config *DatabaseConfig) (DatabaseConnector, error) {
 		return nil, fmt.Errorf("database configuration is required")
 	}
 
-	switch config.Type {
-	case "snowflake":
-		return NewSnowflakeConnector(config.Snowflake)
-	// Other database types can be added here
-	default:
+	provider := config.Provider
+	if provider == nil && config.LLM != nil {
+		p, err := llm.NewProvider(config.LLM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create llm provider: %w", err)
+		}
+		provider = p
+	}
+
+	factory, ok := factoryFor(config.Type)
+	if !ok {
 		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
+	return factory(config, provider)
 }
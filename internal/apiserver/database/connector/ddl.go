@@ -0,0 +1,67 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateTableStatement renders the CREATE TABLE statement of a table from
+// its metadata: columns with their types, defaults and nullability, then
+// its primary and unique keys and the foreign keys of its columns.
+// Computed columns are left out since the table does not store them.
+func CreateTableStatement(d Dialect, metadata *TableMetadata) string {
+	var definitions []string
+	var primaryKey []string
+	for _, col := range metadata.Columns {
+		if col.Computed {
+			continue
+		}
+		definition := d.QuoteIdentifier(col.Name) + " " + col.SQLType()
+		if col.Default != "" {
+			definition += " DEFAULT " + col.Default
+		}
+		if !col.Nullable {
+			definition += " NOT NULL"
+		}
+		definitions = append(definitions, definition)
+		if col.PrimaryKey {
+			primaryKey = append(primaryKey, col.Name)
+		}
+	}
+
+	hasPrimaryKey := false
+	for _, constraint := range metadata.Constraints {
+		switch constraint.Type {
+		case ConstraintPrimaryKey:
+			hasPrimaryKey = true
+			fallthrough
+		case ConstraintUnique:
+			definitions = append(definitions, fmt.Sprintf("%s (%s)", constraint.Type, quoteList(d, constraint.Columns)))
+		case ConstraintCheck:
+			definitions = append(definitions, fmt.Sprintf("CHECK (%s)", constraint.Check))
+		}
+	}
+	if !hasPrimaryKey && len(primaryKey) > 0 {
+		definitions = append(definitions, fmt.Sprintf("%s (%s)", ConstraintPrimaryKey, quoteList(d, primaryKey)))
+	}
+
+	for _, col := range metadata.Columns {
+		table, column, ok := strings.Cut(col.References, ".")
+		if !col.ForeignKey || !ok {
+			continue
+		}
+		definitions = append(definitions, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+			d.QuoteIdentifier(col.Name), d.Table(table), d.QuoteIdentifier(column)))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", d.Table(metadata.Name), strings.Join(definitions, ",\n  "))
+}
+
+// quoteList quotes and joins column names
+func quoteList(d Dialect, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = d.QuoteIdentifier(col)
+	}
+	return strings.Join(quoted, ", ")
+}
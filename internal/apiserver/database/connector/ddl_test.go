@@ -0,0 +1,39 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableStatement(t *testing.T) {
+	metadata := &TableMetadata{
+		Name: "ORDERS",
+		Columns: []Column{
+			{Name: "ID", Type: "NUMBER", Precision: 38, PrimaryKey: true},
+			{Name: "STATUS", Type: "VARCHAR", MaxLength: 20, Nullable: true, Default: "'open'"},
+			{Name: "CUSTOMER_ID", Type: "NUMBER", Precision: 38, ForeignKey: true, References: "CUSTOMERS.ID"},
+			{Name: "TOTAL_WITH_TAX", Type: "NUMBER", Computed: true},
+		},
+	}
+	sf := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	assert.Equal(t, `CREATE TABLE "DB"."PUBLIC"."ORDERS" (
+  "ID" NUMBER(38,0) NOT NULL,
+  "STATUS" VARCHAR(20) DEFAULT 'open',
+  "CUSTOMER_ID" NUMBER(38,0) NOT NULL,
+  PRIMARY KEY ("ID"),
+  FOREIGN KEY ("CUSTOMER_ID") REFERENCES "DB"."PUBLIC"."CUSTOMERS" ("ID")
+)`, CreateTableStatement(sf, metadata))
+
+	// Declared constraints take precedence over primary key flags
+	metadata.Constraints = []Constraint{
+		{Name: "PK_ORDERS", Type: ConstraintPrimaryKey, Columns: []string{"ID"}},
+		{Name: "UQ_CUSTOMER", Type: ConstraintUnique, Columns: []string{"CUSTOMER_ID", "STATUS"}},
+	}
+	metadata.Columns = metadata.Columns[:1]
+	assert.Equal(t, `CREATE TABLE "ORDERS" (
+  "ID" NUMBER(38,0) NOT NULL,
+  PRIMARY KEY ("ID"),
+  UNIQUE ("CUSTOMER_ID", "STATUS")
+)`, CreateTableStatement(ANSIDialect{}, metadata))
+}
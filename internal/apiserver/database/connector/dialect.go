@@ -0,0 +1,493 @@
+package connector
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Dialect supplies the SQL syntax of a database, so generated queries are
+// built once and rendered correctly for every connector. Bind parameters
+// are always written as :name and bound by the connector.
+type Dialect interface {
+	// QuoteIdentifier quotes a table or column name
+	QuoteIdentifier(name string) string
+
+	// Table returns the quoted reference to a table of the connection,
+	// qualified as the database requires
+	Table(name string) string
+
+	// LimitOffset renders the clause returning limit rows after skipping
+	// offset; offset may be empty
+	LimitOffset(limit, offset string) string
+
+	// IsAutoIncrement reports whether the database generates a column's values
+	IsAutoIncrement(col Column) bool
+
+	// TextMatch renders a case-insensitive test of whether a text column
+	// contains the value bound to param
+	TextMatch(column, param string) string
+
+	// Merge renders an upsert into a table reference returned by Table,
+	// matching rows on the key columns and binding every column to its
+	// ParamName
+	Merge(table string, columns, keys []string) string
+}
+
+// DialectProvider is implemented by connectors that supply their SQL dialect
+type DialectProvider interface {
+	Dialect() Dialect
+}
+
+// DialectOf returns a connector's dialect, or ANSI SQL when it supplies none
+func DialectOf(conn DatabaseConnector) Dialect {
+	if p, ok := conn.(DialectProvider); ok {
+		return p.Dialect()
+	}
+	return ANSIDialect{}
+}
+
+// TimeTraveler is implemented by dialects that can read tables as they
+// were at a point in time
+type TimeTraveler interface {
+	// AtClause renders the clause following a table reference that reads
+	// the table as of the timestamp bound to :at_timestamp or, with offset,
+	// the number of seconds relative to now bound to :at_offset
+	AtClause(offset bool) string
+}
+
+// Cloner is implemented by dialects that can clone a table without
+// copying its data
+type Cloner interface {
+	// CloneTable renders the statement replacing clone with a clone of
+	// source; both are table references returned by Table
+	CloneTable(clone, source string) string
+}
+
+// RowSampler is implemented by dialects that can sample rows of a table at
+// random
+type RowSampler interface {
+	// SampleRows renders the clause following a table reference that
+	// samples n rows
+	SampleRows(n int) string
+}
+
+// Returner is implemented by dialects whose INSERT and UPDATE statements
+// can return the rows they write
+type Returner interface {
+	// Returning renders the clause appended to a write to return every
+	// column of the written rows, e.g. RETURNING *
+	Returning() string
+}
+
+// ReturningQuery appends the returning clause of the dialect to a write,
+// reporting whether the dialect has one
+func ReturningQuery(d Dialect, query string) (string, bool) {
+	r, ok := d.(Returner)
+	if !ok {
+		return query, false
+	}
+	return query + " " + r.Returning(), true
+}
+
+// Time travel parameters of generated read endpoints
+const (
+	AtTimestampParam = "at_timestamp"
+	AtOffsetParam    = "at_offset"
+)
+
+// ANSIDialect renders standard SQL with double-quoted identifiers
+type ANSIDialect struct{}
+
+// QuoteIdentifier quotes a name with double quotes
+func (ANSIDialect) QuoteIdentifier(name string) string {
+	return QuoteIdentifier(name)
+}
+
+// Table quotes a table name without qualifying it
+func (ANSIDialect) Table(name string) string {
+	return QuoteIdentifier(name)
+}
+
+// LimitOffset renders LIMIT ... OFFSET ...
+func (ANSIDialect) LimitOffset(limit, offset string) string {
+	if offset == "" {
+		return "LIMIT " + limit
+	}
+	return fmt.Sprintf("LIMIT %s OFFSET %s", limit, offset)
+}
+
+// IsAutoIncrement recognizes identity, autoincrement and serial types
+func (ANSIDialect) IsAutoIncrement(col Column) bool {
+	t := strings.ToLower(col.Type)
+	return strings.Contains(t, "identity") ||
+		strings.Contains(t, "autoincrement") ||
+		strings.Contains(t, "serial")
+}
+
+// TextMatch tests for the lowercased value with POSITION, so wildcard
+// characters in the value match literally
+func (d ANSIDialect) TextMatch(column, param string) string {
+	return fmt.Sprintf("POSITION(LOWER(:%s) IN LOWER(%s)) > 0", param, d.QuoteIdentifier(column))
+}
+
+// Merge renders a standard MERGE statement
+func (d ANSIDialect) Merge(table string, columns, keys []string) string {
+	return mergeStatement(d, table, columns, keys)
+}
+
+// SnowflakeDialect renders Snowflake SQL, qualifying tables with the
+// connection's database and schema
+type SnowflakeDialect struct {
+	ANSIDialect
+	Database string
+	Schema   string
+}
+
+// Table returns the fully qualified name of a table
+func (d SnowflakeDialect) Table(name string) string {
+	return QualifiedName(d.Database, d.Schema, name)
+}
+
+// TextMatch tests for the lowercased value with CONTAINS
+func (d SnowflakeDialect) TextMatch(column, param string) string {
+	return fmt.Sprintf("CONTAINS(LOWER(%s), LOWER(:%s))", d.QuoteIdentifier(column), param)
+}
+
+// AtClause renders Snowflake's AT clause
+func (SnowflakeDialect) AtClause(offset bool) string {
+	if offset {
+		return fmt.Sprintf("AT(OFFSET => :%s)", AtOffsetParam)
+	}
+	return fmt.Sprintf("AT(TIMESTAMP => TO_TIMESTAMP_TZ(:%s))", AtTimestampParam)
+}
+
+// CloneTable renders a zero-copy CLONE
+func (SnowflakeDialect) CloneTable(clone, source string) string {
+	return fmt.Sprintf("CREATE OR REPLACE TABLE %s CLONE %s", clone, source)
+}
+
+// SampleRows renders a fixed-size row sample
+func (SnowflakeDialect) SampleRows(n int) string {
+	return fmt.Sprintf("SAMPLE ROW (%d ROWS)", n)
+}
+
+// Merge renders a Snowflake MERGE statement
+func (d SnowflakeDialect) Merge(table string, columns, keys []string) string {
+	return mergeStatement(d, table, columns, keys)
+}
+
+// mergeStatement renders MERGE ... USING (SELECT ...) in the syntax shared
+// by ANSI SQL and Snowflake
+func mergeStatement(d Dialect, table string, columns, keys []string) string {
+	isKey := make(map[string]bool, len(keys))
+	on := make([]string, 0, len(keys))
+	for _, k := range keys {
+		isKey[k] = true
+		q := d.QuoteIdentifier(k)
+		on = append(on, fmt.Sprintf("t.%s = s.%s", q, q))
+	}
+
+	selects := make([]string, 0, len(columns))
+	sets := make([]string, 0, len(columns))
+	names := make([]string, 0, len(columns))
+	values := make([]string, 0, len(columns))
+	for _, col := range columns {
+		q := d.QuoteIdentifier(col)
+		selects = append(selects, fmt.Sprintf(":%s AS %s", ParamName(col), q))
+		if !isKey[col] {
+			sets = append(sets, fmt.Sprintf("%s = s.%s", q, q))
+		}
+		names = append(names, q)
+		values = append(values, "s."+q)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "MERGE INTO %s t USING (SELECT %s) s ON %s", table, strings.Join(selects, ", "), strings.Join(on, " AND "))
+	if len(sets) > 0 {
+		fmt.Fprintf(&b, " WHEN MATCHED THEN UPDATE SET %s", strings.Join(sets, ", "))
+	}
+	fmt.Fprintf(&b, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(names, ", "), strings.Join(values, ", "))
+	return b.String()
+}
+
+// SearchParam is the bind parameter holding the text searched for by
+// SearchQuery
+const SearchParam = "q"
+
+// SelectPageQuery selects a page of rows bound to :limit and :offset
+func SelectPageQuery(d Dialect, table string) string {
+	return fmt.Sprintf("SELECT * FROM %s %s", d.Table(table), d.LimitOffset(":limit", ":offset"))
+}
+
+// SelectByKeyQuery selects the row whose key columns match their parameters
+func SelectByKeyQuery(d Dialect, table string, keys ...string) string {
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s", d.Table(table), keyCondition(d, keys))
+}
+
+// keyCondition matches every key column to the parameter of its ParamName
+func keyCondition(d Dialect, keys []string) string {
+	conditions := make([]string, len(keys))
+	for i, key := range keys {
+		conditions[i] = fmt.Sprintf("%s = :%s", d.QuoteIdentifier(key), ParamName(key))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// SearchQuery selects a page of rows whose text columns contain the value
+// bound to :q, ranking rows by the number of matching columns
+func SearchQuery(d Dialect, table string, columns []string) string {
+	matches := make([]string, 0, len(columns))
+	ranks := make([]string, 0, len(columns))
+	for _, col := range columns {
+		match := d.TextMatch(col, SearchParam)
+		matches = append(matches, match)
+		ranks = append(ranks, fmt.Sprintf("CASE WHEN %s THEN 1 ELSE 0 END", match))
+	}
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s ORDER BY %s DESC %s",
+		d.Table(table), strings.Join(matches, " OR "), strings.Join(ranks, " + "), d.LimitOffset(":limit", ":offset"))
+}
+
+// TimeTravelQuery makes a query read a table as of the point in time bound
+// to AtTimestampParam or, with offset, AtOffsetParam, adding the dialect's
+// AT clause to the table's reference in the query's FROM clause
+func TimeTravelQuery(d Dialect, query, table string, offset bool) (string, error) {
+	traveler, ok := d.(TimeTraveler)
+	if !ok {
+		return "", fmt.Errorf("time travel is not supported by this database")
+	}
+	from := " FROM " + d.Table(table)
+	i := strings.Index(query, from)
+	if i < 0 {
+		return "", fmt.Errorf("query does not read table %s", table)
+	}
+	end := i + len(from)
+	return query[:end] + " " + traveler.AtClause(offset) + query[end:], nil
+}
+
+// NamedExpression is a SQL expression selected under a column name
+type NamedExpression struct {
+	Name string
+	SQL  string
+}
+
+// SelectExpressions adds expressions to the columns of a query selecting
+// every column of a table; other queries are returned unchanged
+func SelectExpressions(d Dialect, query string, expressions []NamedExpression) string {
+	rest, ok := strings.CutPrefix(query, "SELECT * FROM ")
+	if !ok || len(expressions) == 0 {
+		return query
+	}
+	columns := make([]string, 0, len(expressions)+1)
+	columns = append(columns, "*")
+	for _, e := range expressions {
+		columns = append(columns, fmt.Sprintf("(%s) AS %s", e.SQL, d.QuoteIdentifier(e.Name)))
+	}
+	return "SELECT " + strings.Join(columns, ", ") + " FROM " + rest
+}
+
+// TextColumns returns the names of the character columns
+func TextColumns(columns []Column) []string {
+	var text []string
+	for _, col := range columns {
+		t := strings.ToUpper(col.Type)
+		if strings.Contains(t, "CHAR") || strings.Contains(t, "TEXT") || strings.Contains(t, "STRING") {
+			text = append(text, col.Name)
+		}
+	}
+	return text
+}
+
+// InsertQuery inserts the columns the database does not generate
+func InsertQuery(d Dialect, table string, columns []Column) string {
+	names := make([]string, 0, len(columns))
+	values := make([]string, 0, len(columns))
+	for _, col := range InsertColumns(d, columns) {
+		names = append(names, d.QuoteIdentifier(col.Name))
+		values = append(values, ":"+ParamName(col.Name))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", d.Table(table), strings.Join(names, ", "), strings.Join(values, ", "))
+}
+
+// InsertRowsQuery inserts rows of the given columns, binding the value of
+// each row's column to its RowParamName
+func InsertRowsQuery(d Dialect, table string, columns []string, rows int) string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = d.QuoteIdentifier(col)
+	}
+	tuples := make([]string, rows)
+	for r := range tuples {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			values[i] = ":" + RowParamName(r, col)
+		}
+		tuples[r] = "(" + strings.Join(values, ", ") + ")"
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", d.Table(table), strings.Join(names, ", "), strings.Join(tuples, ", "))
+}
+
+// RowParamName is the bind parameter of a column of a row inserted by
+// InsertRowsQuery
+func RowParamName(row int, column string) string {
+	return fmt.Sprintf("r%d_%s", row, ParamName(column))
+}
+
+// InsertValuesQuery inserts only the columns bound in values, leaving the
+// others to the database's defaults
+func InsertValuesQuery(d Dialect, table string, columns []Column, values map[string]interface{}) string {
+	provided := make([]Column, 0, len(values))
+	for _, col := range columns {
+		if _, ok := values[ParamName(col.Name)]; ok {
+			provided = append(provided, col)
+		}
+	}
+	return InsertQuery(d, table, provided)
+}
+
+// UpdateQuery updates every column but the keys of the row matching the
+// keys
+func UpdateQuery(d Dialect, table string, keys []string, columns []Column) string {
+	sets := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if !slices.Contains(keys, col.Name) {
+			sets = append(sets, fmt.Sprintf("%s = :%s", d.QuoteIdentifier(col.Name), ParamName(col.Name)))
+		}
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s", d.Table(table), strings.Join(sets, ", "), keyCondition(d, keys))
+}
+
+// VersionedUpdateQuery updates like UpdateQuery, but only while the row's
+// version column still holds the value bound to its ParamName, and advances
+// the version: integers are incremented and timestamps set to the current
+// time. Zero rows are updated when another write got there first.
+func VersionedUpdateQuery(d Dialect, table string, keys []string, version Column, columns []Column) string {
+	sets := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if !slices.Contains(keys, col.Name) && col.Name != version.Name {
+			sets = append(sets, fmt.Sprintf("%s = :%s", d.QuoteIdentifier(col.Name), ParamName(col.Name)))
+		}
+	}
+	v := d.QuoteIdentifier(version.Name)
+	if isTimestampType(version.Type) {
+		sets = append(sets, fmt.Sprintf("%s = CURRENT_TIMESTAMP", v))
+	} else {
+		sets = append(sets, fmt.Sprintf("%s = %s + 1", v, v))
+	}
+	return fmt.Sprintf("UPDATE %s SET %s WHERE %s AND %s = :%s", d.Table(table), strings.Join(sets, ", "),
+		keyCondition(d, keys), v, ParamName(version.Name))
+}
+
+// VersionUpdate makes an update endpoint check the version column of the
+// row: its query becomes a VersionedUpdateQuery and its body must carry the
+// version the caller read
+func VersionUpdate(d Dialect, endpoint *APIEndpoint, table string, keys []string, version Column, columns []Column) {
+	endpoint.Query = VersionedUpdateQuery(d, table, keys, version, columns)
+	endpoint.VersionColumn = version.Name
+	for i := range endpoint.Columns {
+		if endpoint.Columns[i].Name == version.Name {
+			endpoint.Columns[i].Nullable = false
+		}
+	}
+}
+
+// versionColumns are the names of the columns used as row versions, in
+// order of preference
+var versionColumns = []string{"VERSION", "ROW_VERSION", "UPDATED_AT", "LAST_UPDATED", "MODIFIED_AT", "LAST_MODIFIED"}
+
+// VersionColumn returns the column tracking the version of a table's rows:
+// an integer column named like VERSION or a timestamp named like
+// UPDATED_AT. ok is false when the table has none.
+func VersionColumn(columns []Column) (version Column, ok bool) {
+	byName := make(map[string]Column, len(columns))
+	for _, col := range columns {
+		byName[strings.ToUpper(col.Name)] = col
+	}
+	for _, name := range versionColumns {
+		col, found := byName[name]
+		if found && !col.PrimaryKey && (isIntegerType(col.Type) || isTimestampType(col.Type)) {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+func isTimestampType(t string) bool {
+	return strings.HasPrefix(strings.ToUpper(t), "TIMESTAMP") || strings.EqualFold(t, "DATETIME")
+}
+
+// isIntegerType reports whether a column holds integers; Snowflake reports
+// NUMBER(38,0) for them
+func isIntegerType(t string) bool {
+	t = strings.ToUpper(strings.ReplaceAll(t, " ", ""))
+	switch {
+	case strings.Contains(t, "INT"):
+		return true
+	case strings.HasPrefix(t, "NUMBER") || strings.HasPrefix(t, "NUMERIC") || strings.HasPrefix(t, "DECIMAL"):
+		return !strings.Contains(t, ",") || strings.HasSuffix(t, ",0)")
+	}
+	return false
+}
+
+// UpdatedRows returns the number of rows an UPDATE reports updating; ok is
+// false when the result carries no count
+func UpdatedRows(rows []map[string]interface{}) (n int64, ok bool) {
+	if len(rows) != 1 {
+		return 0, false
+	}
+	for k, v := range rows[0] {
+		if !strings.EqualFold(k, "number of rows updated") {
+			continue
+		}
+		switch v := v.(type) {
+		case int64:
+			return v, true
+		case int:
+			return int64(v), true
+		case float64:
+			return int64(v), true
+		case string:
+			n, err := strconv.ParseInt(v, 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// DeleteQuery deletes the row matching the keys
+func DeleteQuery(d Dialect, table string, keys ...string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", d.Table(table), keyCondition(d, keys))
+}
+
+// PrimaryKeyColumns returns the columns of a table's primary key, in
+// column order; composite keys have several
+func PrimaryKeyColumns(columns []Column) []Column {
+	var keys []Column
+	for _, col := range columns {
+		if col.PrimaryKey {
+			keys = append(keys, col)
+		}
+	}
+	return keys
+}
+
+// ColumnNames returns the names of columns
+func ColumnNames(columns []Column) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// InsertColumns returns the columns an INSERT supplies values for
+func InsertColumns(d Dialect, columns []Column) []Column {
+	insert := make([]Column, 0, len(columns))
+	for _, col := range columns {
+		if !d.IsAutoIncrement(col) {
+			insert = append(insert, col)
+		}
+	}
+	return insert
+}
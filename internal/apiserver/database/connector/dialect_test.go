@@ -0,0 +1,124 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilders(t *testing.T) {
+	columns := []Column{
+		{Name: "ID", Type: "NUMBER IDENTITY", PrimaryKey: true},
+		{Name: "NAME", Type: "VARCHAR"},
+		{Name: "unit price", Type: "NUMBER(10,2)"},
+	}
+
+	ansi := ANSIDialect{}
+	assert.Equal(t, `SELECT * FROM "ORDERS" LIMIT :limit OFFSET :offset`, SelectPageQuery(ansi, "ORDERS"))
+	assert.Equal(t, `INSERT INTO "ORDERS" ("NAME", "unit price") VALUES (:NAME, :unit_x20_price)`, InsertQuery(ansi, "ORDERS", columns))
+	assert.Equal(t, `INSERT INTO "ORDERS" ("unit price") VALUES (:unit_x20_price)`,
+		InsertValuesQuery(ansi, "ORDERS", columns, map[string]interface{}{"unit_x20_price": 1, "ID": 2}))
+	assert.Equal(t, `UPDATE "ORDERS" SET "NAME" = :NAME, "unit price" = :unit_x20_price WHERE "ID" = :ID`, UpdateQuery(ansi, "ORDERS", []string{"ID"}, columns))
+	assert.Equal(t, `INSERT INTO "ORDERS" ("ID", "unit price") VALUES (:r0_ID, :r0_unit_x20_price), (:r1_ID, :r1_unit_x20_price)`,
+		InsertRowsQuery(ansi, "ORDERS", []string{"ID", "unit price"}, 2))
+	assert.Equal(t, `DELETE FROM "ORDERS" WHERE "ID" = :ID`, DeleteQuery(ansi, "ORDERS", "ID"))
+	assert.Equal(t, "LIMIT :limit", ansi.LimitOffset(":limit", ""))
+
+	// Composite keys match every key column
+	keys := []string{"ORDER_ID", "LINE"}
+	assert.Equal(t, `SELECT * FROM "ITEMS" WHERE "ORDER_ID" = :ORDER_ID AND "LINE" = :LINE`, SelectByKeyQuery(ansi, "ITEMS", keys...))
+	assert.Equal(t, `UPDATE "ITEMS" SET "QTY" = :QTY WHERE "ORDER_ID" = :ORDER_ID AND "LINE" = :LINE`,
+		UpdateQuery(ansi, "ITEMS", keys, []Column{{Name: "ORDER_ID"}, {Name: "LINE"}, {Name: "QTY"}}))
+	assert.Equal(t, `DELETE FROM "ITEMS" WHERE "ORDER_ID" = :ORDER_ID AND "LINE" = :LINE`, DeleteQuery(ansi, "ITEMS", keys...))
+	assert.Equal(t,
+		`SELECT *, (FIRST || ' ' || LAST) AS "full name" FROM "ORDERS" LIMIT :limit OFFSET :offset`,
+		SelectExpressions(ansi, SelectPageQuery(ansi, "ORDERS"), []NamedExpression{{Name: "full name", SQL: "FIRST || ' ' || LAST"}}))
+	assert.Equal(t, `SELECT ID FROM "ORDERS"`, SelectExpressions(ansi, `SELECT ID FROM "ORDERS"`, []NamedExpression{{Name: "X", SQL: "1"}}))
+
+	sf := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	assert.Equal(t, `SELECT * FROM "DB"."PUBLIC"."ORDERS" WHERE "ID" = :ID`, SelectByKeyQuery(sf, "ORDERS", "ID"))
+	assert.Equal(t,
+		`MERGE INTO "DB"."PUBLIC"."ORDERS" t USING (SELECT :ID AS "ID", :NAME AS "NAME") s ON t."ID" = s."ID"`+
+			` WHEN MATCHED THEN UPDATE SET "NAME" = s."NAME"`+
+			` WHEN NOT MATCHED THEN INSERT ("ID", "NAME") VALUES (s."ID", s."NAME")`,
+		sf.Merge(sf.Table("ORDERS"), []string{"ID", "NAME"}, []string{"ID"}))
+}
+
+func TestSearchQuery(t *testing.T) {
+	columns := []Column{
+		{Name: "ID", Type: "NUMBER"},
+		{Name: "NAME", Type: "TEXT"},
+		{Name: "CODE", Type: "CHAR(3)"},
+	}
+	text := TextColumns(columns)
+	assert.Equal(t, []string{"NAME", "CODE"}, text)
+
+	assert.Equal(t,
+		`SELECT * FROM "ORDERS" WHERE POSITION(LOWER(:q) IN LOWER("NAME")) > 0`+
+			` ORDER BY CASE WHEN POSITION(LOWER(:q) IN LOWER("NAME")) > 0 THEN 1 ELSE 0 END DESC LIMIT :limit OFFSET :offset`,
+		SearchQuery(ANSIDialect{}, "ORDERS", text[:1]))
+
+	sf := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	assert.Equal(t,
+		`SELECT * FROM "DB"."PUBLIC"."ORDERS" WHERE CONTAINS(LOWER("NAME"), LOWER(:q)) OR CONTAINS(LOWER("CODE"), LOWER(:q))`+
+			` ORDER BY CASE WHEN CONTAINS(LOWER("NAME"), LOWER(:q)) THEN 1 ELSE 0 END + CASE WHEN CONTAINS(LOWER("CODE"), LOWER(:q)) THEN 1 ELSE 0 END DESC`+
+			` LIMIT :limit OFFSET :offset`,
+		SearchQuery(sf, "ORDERS", text))
+}
+
+func TestVersionedUpdateQuery(t *testing.T) {
+	columns := []Column{
+		{Name: "ID", Type: "NUMBER", PrimaryKey: true},
+		{Name: "NAME", Type: "VARCHAR"},
+		{Name: "version", Type: "NUMBER(38,0)"},
+		{Name: "UPDATED_AT", Type: "TIMESTAMP_NTZ"},
+	}
+	version, ok := VersionColumn(columns)
+	assert.True(t, ok)
+	assert.Equal(t, "version", version.Name)
+	assert.Equal(t,
+		`UPDATE "ORDERS" SET "NAME" = :NAME, "UPDATED_AT" = :UPDATED_AT, "version" = "version" + 1 WHERE "ID" = :ID AND "version" = :version`,
+		VersionedUpdateQuery(ANSIDialect{}, "ORDERS", []string{"ID"}, version, columns))
+
+	version, ok = VersionColumn(columns[3:])
+	assert.True(t, ok)
+	assert.Equal(t,
+		`UPDATE "ORDERS" SET "NAME" = :NAME, "UPDATED_AT" = CURRENT_TIMESTAMP WHERE "ID" = :ID AND "UPDATED_AT" = :UPDATED_AT`,
+		VersionedUpdateQuery(ANSIDialect{}, "ORDERS", []string{"ID"}, version, columns[:2]))
+
+	// Versions must be integers or timestamps
+	_, ok = VersionColumn([]Column{{Name: "VERSION", Type: "VARCHAR"}, {Name: "UPDATED_AT", Type: "DATE"}})
+	assert.False(t, ok)
+
+	n, ok := UpdatedRows([]map[string]interface{}{{"number of rows updated": int64(0), "number of multi-joined rows updated": int64(0)}})
+	assert.True(t, ok)
+	assert.Zero(t, n)
+	_, ok = UpdatedRows([]map[string]interface{}{{"ID": 1}})
+	assert.False(t, ok)
+}
+
+func TestTimeTravelQuery(t *testing.T) {
+	sf := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	query, err := TimeTravelQuery(sf, SelectByKeyQuery(sf, "ORDERS", "ID"), "ORDERS", false)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "DB"."PUBLIC"."ORDERS" AT(TIMESTAMP => TO_TIMESTAMP_TZ(:at_timestamp)) WHERE "ID" = :ID`, query)
+	query, err = TimeTravelQuery(sf, SelectPageQuery(sf, "ORDERS"), "ORDERS", true)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "DB"."PUBLIC"."ORDERS" AT(OFFSET => :at_offset) LIMIT :limit OFFSET :offset`, query)
+
+	// Row filters are added after the clause
+	filtered, err := AddRowFilter(query, `"REGION" = :rls_region`)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "DB"."PUBLIC"."ORDERS" AT(OFFSET => :at_offset) WHERE ("REGION" = :rls_region) LIMIT :limit OFFSET :offset`, filtered)
+
+	_, err = TimeTravelQuery(sf, SelectPageQuery(sf, "ITEMS"), "ORDERS", true)
+	assert.Error(t, err)
+	_, err = TimeTravelQuery(ANSIDialect{}, SelectPageQuery(ANSIDialect{}, "ORDERS"), "ORDERS", true)
+	assert.Error(t, err)
+}
+
+func TestCloneTable(t *testing.T) {
+	sf := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	assert.Equal(t, `CREATE OR REPLACE TABLE "DB"."PUBLIC"."ORDERS_SANDBOX" CLONE "DB"."PUBLIC"."ORDERS"`,
+		sf.CloneTable(sf.Table("ORDERS_SANDBOX"), sf.Table("ORDERS")))
+}
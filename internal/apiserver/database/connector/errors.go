@@ -0,0 +1,65 @@
+package connector
+
+import (
+	"context"
+	"errors"
+)
+
+// Error codes classifying the errors of database operations
+const (
+	// CodeAuthFailed is a failure to authenticate with the database
+	CodeAuthFailed = "AUTH_FAILED"
+
+	// CodeTableNotFound is a table or other object that does not exist or
+	// that the connection's role may not access
+	CodeTableNotFound = "TABLE_NOT_FOUND"
+
+	// CodeInvalidQuery is a query the database cannot compile
+	CodeInvalidQuery = "INVALID_QUERY"
+
+	// CodeQueryTimeout is a query that ran past its timeout
+	CodeQueryTimeout = "QUERY_TIMEOUT"
+
+	// CodeQueryCancelled is a query cancelled before it completed
+	CodeQueryCancelled = "QUERY_CANCELLED"
+
+	// CodeBudgetExceeded is a query refused by the credit budget
+	CodeBudgetExceeded = "BUDGET_EXCEEDED"
+
+	// CodeUnavailable is a transient failure to reach the database
+	CodeUnavailable = "DATABASE_UNAVAILABLE"
+
+	// CodeInternal is any other error
+	CodeInternal = "INTERNAL_ERROR"
+)
+
+// driverErrorCodes classify the errors of database drivers, returning an
+// empty string for errors they do not recognize
+var driverErrorCodes = []func(err error) string{
+	snowflakeErrorCode,
+}
+
+// ErrorCode classifies the error of a database operation
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeQueryTimeout
+	case errors.Is(err, context.Canceled):
+		return CodeQueryCancelled
+	case errors.Is(err, ErrUnknownTable):
+		return CodeTableNotFound
+	case errors.Is(err, ErrCreditBudgetExceeded):
+		return CodeBudgetExceeded
+	}
+	for _, code := range driverErrorCodes {
+		if c := code(err); c != "" {
+			return c
+		}
+	}
+	if IsTransient(err) {
+		return CodeUnavailable
+	}
+	return CodeInternal
+}
@@ -0,0 +1,26 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	sf "github.com/snowflakedb/gosnowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	wrap := func(err error) error { return fmt.Errorf("failed to execute query: %w", err) }
+
+	assert.Equal(t, CodeTableNotFound, ErrorCode(wrap(&sf.SnowflakeError{Number: 2003, SQLState: "42S02", Message: "Object 'ORDERS' does not exist or not authorized."})))
+	assert.Equal(t, CodeInvalidQuery, ErrorCode(wrap(&sf.SnowflakeError{Number: 1003, SQLState: "42000", Message: "SQL compilation error: syntax error"})))
+	assert.Equal(t, CodeAuthFailed, ErrorCode(wrap(&sf.SnowflakeError{Number: 390100, SQLState: "08004", Message: "Incorrect username or password was specified."})))
+	assert.Equal(t, CodeQueryTimeout, ErrorCode(wrap(&sf.SnowflakeError{Number: 630, SQLState: "57014"})))
+	assert.Equal(t, CodeQueryTimeout, ErrorCode(wrap(context.DeadlineExceeded)))
+	assert.Equal(t, CodeQueryCancelled, ErrorCode(wrap(context.Canceled)))
+	assert.Equal(t, CodeBudgetExceeded, ErrorCode(ErrCreditBudgetExceeded))
+	assert.Equal(t, CodeUnavailable, ErrorCode(errors.New("connection reset by peer")))
+	assert.Equal(t, CodeInternal, ErrorCode(errors.New("something broke")))
+	assert.Empty(t, ErrorCode(nil))
+}
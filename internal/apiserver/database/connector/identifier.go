@@ -0,0 +1,42 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownTable is returned for table names missing from the catalog
+var ErrUnknownTable = errors.New("unknown table")
+
+// QuoteIdentifier quotes a name as a SQL identifier, doubling embedded
+// quotes so the name cannot end the identifier early
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QualifiedName quotes each part of a name and joins them with dots
+func QualifiedName(parts ...string) string {
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = QuoteIdentifier(part)
+	}
+	return strings.Join(quoted, ".")
+}
+
+// ParamName returns the named bind parameter used for a column in
+// generated SQL. Letters, digits and underscores are kept; any other byte
+// is escaped as _xHH_ so every column name maps to a distinct parameter.
+func ParamName(column string) string {
+	var b strings.Builder
+	for i := 0; i < len(column); i++ {
+		c := column[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '_':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_x%02X_", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,20 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, `"ORDERS"`, QuoteIdentifier("ORDERS"))
+	assert.Equal(t, `"a""; DROP"`, QuoteIdentifier(`a"; DROP`))
+	assert.Equal(t, `"DB"."PUBLIC"."my ""t"""`, QualifiedName("DB", "PUBLIC", `my "t"`))
+}
+
+func TestParamName(t *testing.T) {
+	assert.Equal(t, "ORDER_ID", ParamName("ORDER_ID"))
+	assert.Equal(t, "a_x20_b", ParamName("a b"))
+	assert.Equal(t, "a_x22__x3B_", ParamName(`a";`))
+	assert.NotEqual(t, ParamName("a-b"), ParamName("a.b"))
+}
@@ -0,0 +1,200 @@
+package connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+)
+
+// tableEnhancement is the LLM output for one table
+type tableEnhancement struct {
+	TableDescription string            `json:"table_description"`
+	Columns          map[string]string `json:"columns"`
+	Tools            map[string]string `json:"tools"`
+}
+
+// enrichmentPromptData is the data available to the metadata enrichment template
+type enrichmentPromptData struct {
+	Table *TableMetadata
+}
+
+// metadataEnhancer generates table, column and tool descriptions with an LLM,
+// caching the result per table schema and prompt version
+type metadataEnhancer struct {
+	provider llm.Provider
+	prompts  *prompt.Registry
+
+	mu    sync.Mutex
+	cache map[string]*tableEnhancement
+}
+
+func newMetadataEnhancer(provider llm.Provider, prompts *prompt.Registry) *metadataEnhancer {
+	if prompts == nil {
+		// A registry without a store only holds the built-in templates and can't fail
+		prompts, _ = prompt.NewRegistry(nil, nil)
+	}
+	return &metadataEnhancer{
+		provider: provider,
+		prompts:  prompts,
+		cache:    make(map[string]*tableEnhancement),
+	}
+}
+
+// enhance fills in the verbose descriptions of a table, its columns and its
+// operations. Without a provider only a schema-derived table description is set.
+func (e *metadataEnhancer) enhance(ctx context.Context, metadata *TableMetadata) error {
+	if e == nil || e.provider == nil {
+		metadata.VerboseDescription = basicTableDescription(metadata)
+		return nil
+	}
+
+	system, tmpl, err := e.prompts.Render(prompt.NameMetadataEnrichment, enrichmentPromptData{Table: metadata})
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("%s:v%d", schemaHash(metadata), tmpl.Version)
+	e.mu.Lock()
+	enhancement, ok := e.cache[key]
+	e.mu.Unlock()
+
+	if !ok {
+		resp, err := e.provider.Complete(ctx, &llm.Request{
+			System: system,
+			Messages: []llm.Message{
+				{Role: llm.RoleUser, Content: describeTableForPrompt(metadata)},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate table description: %w", err)
+		}
+
+		enhancement, err = parseTableEnhancement(resp.Text)
+		if err != nil {
+			return err
+		}
+
+		e.mu.Lock()
+		e.cache[key] = enhancement
+		e.mu.Unlock()
+	}
+
+	applyEnhancement(metadata, enhancement)
+	return nil
+}
+
+// applyEnhancement copies generated descriptions onto the metadata
+func applyEnhancement(metadata *TableMetadata, enhancement *tableEnhancement) {
+	metadata.VerboseDescription = enhancement.TableDescription
+	for i := range metadata.Columns {
+		if desc, ok := enhancement.Columns[metadata.Columns[i].Name]; ok {
+			metadata.Columns[i].VerboseDescription = desc
+		}
+	}
+	if len(enhancement.Tools) > 0 {
+		metadata.ToolDescriptions = make(map[string]string, len(enhancement.Tools))
+		for op, desc := range enhancement.Tools {
+			metadata.ToolDescriptions[op] = desc
+		}
+	}
+}
+
+// parseTableEnhancement extracts the JSON object from an LLM response,
+// tolerating surrounding prose or code fences
+func parseTableEnhancement(text string) (*tableEnhancement, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("llm response does not contain a JSON object")
+	}
+
+	var enhancement tableEnhancement
+	if err := json.Unmarshal([]byte(text[start:end+1]), &enhancement); err != nil {
+		return nil, fmt.Errorf("failed to parse llm response: %w", err)
+	}
+	return &enhancement, nil
+}
+
+// schemaHash identifies a table schema so cached descriptions are regenerated
+// when columns change
+func schemaHash(metadata *TableMetadata) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", metadata.Name, metadata.Description)
+	for _, col := range metadata.Columns {
+		fmt.Fprintf(h, "%s|%s|%t|%s|%s\n", col.Name, col.Type, col.PrimaryKey, col.References, col.Description)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// describeTableForPrompt renders table metadata as prompt input
+func describeTableForPrompt(metadata *TableMetadata) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Table: %s\n", metadata.Name)
+	if metadata.Description != "" {
+		fmt.Fprintf(&b, "Comment: %s\n", metadata.Description)
+	}
+	fmt.Fprintf(&b, "Row count: %d\n", metadata.RowCount)
+	b.WriteString("Columns:\n")
+	for _, col := range metadata.Columns {
+		fmt.Fprintf(&b, "- %s %s", col.Name, col.SQLType())
+		if !col.Nullable {
+			b.WriteString(" NOT NULL")
+		}
+		if col.Default != "" {
+			fmt.Fprintf(&b, " DEFAULT %s", col.Default)
+		}
+		if col.PrimaryKey {
+			b.WriteString(" PRIMARY KEY")
+		}
+		if col.References != "" {
+			fmt.Fprintf(&b, " REFERENCES %s", col.References)
+		}
+		if col.Description != "" {
+			fmt.Fprintf(&b, " -- %s", col.Description)
+		}
+		b.WriteString("\n")
+	}
+	for _, idx := range metadata.Indexes {
+		fmt.Fprintf(&b, "Index %s: %s\n", idx.Name, strings.Join(idx.Columns, ", "))
+	}
+	for _, c := range metadata.Constraints {
+		if c.Type == ConstraintCheck {
+			fmt.Fprintf(&b, "Constraint %s: CHECK (%s)\n", c.Name, c.Check)
+		} else {
+			fmt.Fprintf(&b, "Constraint %s: %s (%s)\n", c.Name, c.Type, strings.Join(c.Columns, ", "))
+		}
+	}
+
+	if len(metadata.SampleData) > 0 {
+		b.WriteString("Sample rows:\n")
+		for _, row := range metadata.SampleJSON() {
+			fmt.Fprintf(&b, "- %s\n", row)
+		}
+	}
+	return b.String()
+}
+
+// basicTableDescription builds a description from the schema alone
+func basicTableDescription(metadata *TableMetadata) string {
+	description := fmt.Sprintf("Table %s contains %d columns and %d rows. ",
+		metadata.Name, len(metadata.Columns), metadata.RowCount)
+
+	description += "Columns include: "
+	for i, col := range metadata.Columns {
+		if i > 0 {
+			description += ", "
+		}
+		description += col.Name + " (" + col.Type + ")"
+		if col.PrimaryKey {
+			description += " [Primary Key]"
+		}
+	}
+	return description
+}
@@ -0,0 +1,83 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+)
+
+// scriptedProvider answers every completion with a fixed response or error,
+// counting the calls
+type scriptedProvider struct {
+	text  string
+	err   error
+	calls int
+}
+
+func (p *scriptedProvider) Name() string { return "scripted" }
+
+func (p *scriptedProvider) Complete(context.Context, *llm.Request) (*llm.Response, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &llm.Response{Text: p.text}, nil
+}
+
+func TestMetadataEnhancer(t *testing.T) {
+	orders := func() *TableMetadata {
+		return &TableMetadata{Name: "ORDERS", RowCount: 3, Columns: []Column{
+			{Name: "ID", Type: "NUMBER", PrimaryKey: true},
+			{Name: "TOTAL", Type: "NUMBER"},
+		}}
+	}
+	provider := &scriptedProvider{text: "Here you go:\n```json\n" + `{
+		"table_description": "Customer orders",
+		"columns": {"ID": "Order number", "TOTAL": "Amount in EUR", "GONE": "Dropped column"},
+		"tools": {"list": "Browse recent orders"}
+	}` + "\n```"}
+	e := newMetadataEnhancer(provider, nil)
+
+	metadata := orders()
+	require.NoError(t, e.enhance(context.Background(), metadata))
+	assert.Equal(t, "Customer orders", metadata.VerboseDescription)
+	assert.Equal(t, "Order number", metadata.Columns[0].VerboseDescription)
+	assert.Equal(t, "Amount in EUR", metadata.Columns[1].VerboseDescription)
+	assert.Equal(t, map[string]string{OperationList: "Browse recent orders"}, metadata.ToolDescriptions)
+
+	// Descriptions are cached per schema
+	require.NoError(t, e.enhance(context.Background(), orders()))
+	assert.Equal(t, 1, provider.calls)
+	changed := orders()
+	changed.Columns = append(changed.Columns, Column{Name: "STATUS", Type: "TEXT"})
+	require.NoError(t, e.enhance(context.Background(), changed))
+	assert.Equal(t, 2, provider.calls)
+
+	// Without a provider the description comes from the schema
+	metadata = orders()
+	require.NoError(t, newMetadataEnhancer(nil, nil).enhance(context.Background(), metadata))
+	assert.Equal(t, "Table ORDERS contains 2 columns and 3 rows. Columns include: ID (NUMBER) [Primary Key], TOTAL (NUMBER)", metadata.VerboseDescription)
+}
+
+func TestMetadataEnhancerErrors(t *testing.T) {
+	metadata := &TableMetadata{Name: "ORDERS", Columns: []Column{{Name: "ID", Type: "NUMBER"}}}
+
+	failing := &scriptedProvider{err: errors.New("rate limited")}
+	err := newMetadataEnhancer(failing, nil).enhance(context.Background(), metadata)
+	assert.ErrorContains(t, err, "failed to generate table description: rate limited")
+	assert.Empty(t, metadata.VerboseDescription)
+
+	// Unparseable responses fail and are not cached
+	prose := &scriptedProvider{text: "ORDERS holds orders."}
+	e := newMetadataEnhancer(prose, nil)
+	assert.ErrorContains(t, e.enhance(context.Background(), metadata), "does not contain a JSON object")
+	prose.text = `{"table_description": 7}`
+	assert.ErrorContains(t, e.enhance(context.Background(), metadata), "failed to parse llm response")
+	assert.Equal(t, 2, prose.calls)
+	assert.Empty(t, e.cache)
+}
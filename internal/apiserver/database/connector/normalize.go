@@ -0,0 +1,180 @@
+package connector
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Timestamp formats of ResultTypeConfig besides Go time layouts
+const (
+	TimestampRFC3339 = "rfc3339"
+	TimestampUnix    = "unix"
+	TimestampUnixMs  = "unix_ms"
+)
+
+// Decimal handling of ResultTypeConfig
+const (
+	DecimalsNumber = "number"
+	DecimalsString = "string"
+)
+
+// Binary encodings of ResultTypeConfig
+const (
+	BinaryHex    = "hex"
+	BinaryBase64 = "base64"
+)
+
+// ResultTypeConfig controls how result values are converted to JSON types
+type ResultTypeConfig struct {
+	// TimestampFormat is rfc3339 (default), unix, unix_ms or a Go time layout
+	TimestampFormat string `json:"timestamp_format,omitempty"`
+
+	// Decimals returns fixed-point numbers with a scale as JSON numbers
+	// (default) or as strings that keep every digit
+	Decimals string `json:"decimals,omitempty"`
+
+	// Binary encodes binary values as hex (default) or base64
+	Binary string `json:"binary,omitempty"`
+}
+
+// valueKind classifies result columns by how their values are normalized
+type valueKind int
+
+const (
+	kindOther valueKind = iota
+	kindInteger
+	kindDecimal
+	kindFloat
+	kindBoolean
+	kindTimestamp
+	kindDate
+	kindTime
+	kindSemiStructured
+	kindBinary
+)
+
+// valueNormalizer converts driver values to consistent JSON types
+type valueNormalizer struct {
+	timestampFormat string
+	decimals        string
+	binary          string
+}
+
+// newValueNormalizer validates a result type configuration; nil selects
+// the defaults
+func newValueNormalizer(cfg *ResultTypeConfig) (*valueNormalizer, error) {
+	n := &valueNormalizer{
+		timestampFormat: TimestampRFC3339,
+		decimals:        DecimalsNumber,
+		binary:          BinaryHex,
+	}
+	if cfg == nil {
+		return n, nil
+	}
+	if cfg.TimestampFormat != "" {
+		n.timestampFormat = cfg.TimestampFormat
+	}
+	switch cfg.Decimals {
+	case "":
+	case DecimalsNumber, DecimalsString:
+		n.decimals = cfg.Decimals
+	default:
+		return nil, fmt.Errorf("unsupported decimal handling %q", cfg.Decimals)
+	}
+	switch cfg.Binary {
+	case "":
+	case BinaryHex, BinaryBase64:
+		n.binary = cfg.Binary
+	default:
+		return nil, fmt.Errorf("unsupported binary encoding %q", cfg.Binary)
+	}
+	return n, nil
+}
+
+// normalize converts a value of a column of the given kind. Values that
+// cannot be converted are returned unchanged, except for []byte which
+// becomes a string.
+func (n *valueNormalizer) normalize(kind valueKind, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if b, ok := v.([]byte); ok {
+		if kind == kindBinary {
+			if n.binary == BinaryBase64 {
+				return base64.StdEncoding.EncodeToString(b)
+			}
+			return hex.EncodeToString(b)
+		}
+		v = string(b)
+	}
+
+	switch kind {
+	case kindInteger:
+		if s, ok := v.(string); ok {
+			// Integers beyond int64 stay strings to keep every digit
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i
+			}
+		}
+	case kindDecimal:
+		if s, ok := v.(string); ok {
+			if n.decimals == DecimalsString {
+				return s
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		}
+	case kindFloat:
+		if s, ok := v.(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		}
+	case kindBoolean:
+		if s, ok := v.(string); ok {
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+	case kindTimestamp:
+		if t, ok := v.(time.Time); ok {
+			return n.formatTimestamp(t)
+		}
+	case kindDate:
+		if t, ok := v.(time.Time); ok {
+			return t.Format(time.DateOnly)
+		}
+	case kindTime:
+		if t, ok := v.(time.Time); ok {
+			return t.Format("15:04:05.999999999")
+		}
+	case kindSemiStructured:
+		// Semi-structured values arrive as JSON text
+		if s, ok := v.(string); ok {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(s), &decoded); err == nil {
+				return decoded
+			}
+		}
+	}
+	return v
+}
+
+// formatTimestamp renders a timestamp in the configured format
+func (n *valueNormalizer) formatTimestamp(t time.Time) interface{} {
+	switch n.timestampFormat {
+	case TimestampRFC3339:
+		return t.Format(time.RFC3339Nano)
+	case TimestampUnix:
+		return t.Unix()
+	case TimestampUnixMs:
+		return t.UnixMilli()
+	default:
+		return t.Format(n.timestampFormat)
+	}
+}
@@ -0,0 +1,52 @@
+package connector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueNormalizer(t *testing.T) {
+	n, err := newValueNormalizer(nil)
+	require.NoError(t, err)
+
+	ts := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		kind valueKind
+		in   interface{}
+		want interface{}
+	}{
+		{kindInteger, "42", int64(42)},
+		{kindInteger, "123456789012345678901234567890", "123456789012345678901234567890"},
+		{kindDecimal, "10.25", 10.25},
+		{kindFloat, "1.5e3", 1500.0},
+		{kindBoolean, "true", true},
+		{kindTimestamp, ts, "2024-03-01T12:30:00Z"},
+		{kindDate, ts, "2024-03-01"},
+		{kindTime, ts, "12:30:00"},
+		{kindSemiStructured, `{"a": [1, 2]}`, map[string]interface{}{"a": []interface{}{1.0, 2.0}}},
+		{kindSemiStructured, "not json", "not json"},
+		{kindBinary, []byte{0xde, 0xad}, "dead"},
+		{kindOther, []byte("text"), "text"},
+		{kindInteger, nil, nil},
+	} {
+		assert.Equal(t, tc.want, n.normalize(tc.kind, tc.in), tc.in)
+	}
+
+	n, err = newValueNormalizer(&ResultTypeConfig{TimestampFormat: TimestampUnixMs, Decimals: DecimalsString, Binary: BinaryBase64})
+	require.NoError(t, err)
+	assert.Equal(t, ts.UnixMilli(), n.normalize(kindTimestamp, ts))
+	assert.Equal(t, "10.25", n.normalize(kindDecimal, "10.25"))
+	assert.Equal(t, "3q0=", n.normalize(kindBinary, []byte{0xde, 0xad}))
+
+	n, err = newValueNormalizer(&ResultTypeConfig{TimestampFormat: "02 Jan 2006"})
+	require.NoError(t, err)
+	assert.Equal(t, "01 Mar 2024", n.normalize(kindTimestamp, ts))
+
+	_, err = newValueNormalizer(&ResultTypeConfig{Decimals: "float"})
+	assert.Error(t, err)
+	_, err = newValueNormalizer(&ResultTypeConfig{Binary: "base32"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pageClauses are the keywords starting the clause that pages through the
+// rows of a query
+var pageClauses = map[string]bool{"LIMIT": true, "OFFSET": true, "FETCH": true}
+
+// pageOrder locates the top-level ORDER BY and paging clauses of a query,
+// returning the offset of the paging clause or -1 when the query is not
+// paginated
+func pageOrder(query string) (ordered bool, page int, err error) {
+	words, _, err := scanSQL(query)
+	if err != nil {
+		return false, -1, err
+	}
+	page = -1
+	from := false
+	for _, w := range words {
+		switch {
+		case w.word == "FROM":
+			from = true
+		case w.word == "ORDER":
+			ordered = true
+		case from && pageClauses[w.word] && page < 0:
+			page = w.start
+		}
+	}
+	return ordered, page, nil
+}
+
+// OrderPage makes the pages of a paginated endpoint stable. A list query
+// paging through rows without an ORDER BY returns them in any order, so
+// the same row may show on two pages and another on none; it is ordered by
+// the endpoint's OrderBy columns, or given a Warning when it has none.
+func OrderPage(d Dialect, e *APIEndpoint) {
+	if e.Operation != OperationList {
+		return
+	}
+	ordered, page, err := pageOrder(e.Query)
+	switch {
+	case err != nil:
+		e.Warning = fmt.Sprintf("pages may not be stable: cannot check the ordering of the query: %v", err)
+	case ordered || page < 0:
+	case len(e.OrderBy) == 0:
+		e.Warning = "pages are not deterministically ordered: there is no key to order rows by; configure order_by for the table"
+	default:
+		e.Query = fmt.Sprintf("%s ORDER BY %s %s", strings.TrimRight(e.Query[:page], " \t\r\n"), quoteList(d, e.OrderBy), e.Query[page:])
+	}
+}
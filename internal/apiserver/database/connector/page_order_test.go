@@ -0,0 +1,44 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderPage(t *testing.T) {
+	d := ANSIDialect{}
+	list := func(query string, orderBy ...string) *APIEndpoint {
+		e := &APIEndpoint{Operation: OperationList, Query: query, OrderBy: orderBy}
+		OrderPage(d, e)
+		return e
+	}
+
+	e := list(SelectPageQuery(d, "ORDERS"), "REGION", "ID")
+	assert.Equal(t, `SELECT * FROM "ORDERS" ORDER BY "REGION", "ID" LIMIT :limit OFFSET :offset`, e.Query)
+	assert.Empty(t, e.Warning)
+
+	e = list(`SELECT * FROM "ORDERS" WHERE ("DELETED_AT" IS NULL) LIMIT :limit`, "ID")
+	assert.Equal(t, `SELECT * FROM "ORDERS" WHERE ("DELETED_AT" IS NULL) ORDER BY "ID" LIMIT :limit`, e.Query)
+
+	// Ordered and unpaginated queries are left alone
+	for _, query := range []string{
+		`SELECT * FROM "ORDERS" ORDER BY "CREATED_AT" LIMIT :limit`,
+		`SELECT * FROM "ORDERS"`,
+		`SELECT * FROM (SELECT * FROM "ORDERS" LIMIT 10) recent`,
+	} {
+		e = list(query, "ID")
+		assert.Equal(t, query, e.Query)
+		assert.Empty(t, e.Warning)
+	}
+
+	// Without columns to order by the endpoint is flagged
+	e = list(SelectPageQuery(d, "EVENTS"))
+	assert.Equal(t, SelectPageQuery(d, "EVENTS"), e.Query)
+	assert.Contains(t, e.Warning, "order_by")
+
+	// Other operations are not paged
+	get := &APIEndpoint{Operation: OperationSearch, Query: SelectPageQuery(d, "EVENTS")}
+	OrderPage(d, get)
+	assert.Empty(t, get.Warning)
+}
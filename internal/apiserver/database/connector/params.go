@@ -0,0 +1,178 @@
+package connector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Parameter locations
+const (
+	ParamInPath  = "path"
+	ParamInQuery = "query"
+	ParamInBody  = "body"
+)
+
+// Parameter types, as JSON Schema types
+const (
+	ParamTypeString  = "string"
+	ParamTypeInteger = "integer"
+	ParamTypeNumber  = "number"
+	ParamTypeBoolean = "boolean"
+)
+
+// Default page size of generated list and search endpoints
+const DefaultPageSize = 100
+
+// pathParam matches {param} and :param placeholders in endpoint paths
+var pathParam = regexp.MustCompile(`\{([^}/]+)\}|:([^/]+)`)
+
+// Parameter defines a request parameter of a generated endpoint
+type Parameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Type        string      `json:"type"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// ParamType maps a column type to the type of a parameter binding it
+func ParamType(columnType string) string {
+	t := strings.ToUpper(strings.TrimSpace(columnType))
+	switch {
+	case isIntegerType(t):
+		return ParamTypeInteger
+	case strings.HasPrefix(t, "NUMBER") || strings.HasPrefix(t, "NUMERIC") || strings.HasPrefix(t, "DECIMAL") ||
+		strings.HasPrefix(t, "FLOAT") || strings.HasPrefix(t, "DOUBLE") || t == "REAL":
+		return ParamTypeNumber
+	case t == "BOOLEAN" || t == "BOOL":
+		return ParamTypeBoolean
+	}
+	return ParamTypeString
+}
+
+// PageParams defines the limit and offset parameters of a paged endpoint
+func PageParams() []Parameter {
+	return []Parameter{
+		{Name: "limit", In: ParamInQuery, Type: ParamTypeInteger, Default: DefaultPageSize, Description: "Number of records to return"},
+		{Name: "offset", In: ParamInQuery, Type: ParamTypeInteger, Default: 0, Description: "Number of records to skip"},
+	}
+}
+
+// SearchParams defines the parameters of a text search endpoint
+func SearchParams() []Parameter {
+	return append([]Parameter{
+		{Name: SearchParam, In: ParamInQuery, Type: ParamTypeString, Required: true, Description: "Text to search for"},
+		{Name: "columns", In: ParamInQuery, Type: ParamTypeString, Description: "Comma-separated columns to search (default: all text columns)"},
+	}, PageParams()...)
+}
+
+// TimeTravelParams defines the parameters reading a table as it was at a
+// point in time, for dialects that are TimeTravelers
+func TimeTravelParams() []Parameter {
+	return []Parameter{
+		{Name: AtTimestampParam, In: ParamInQuery, Type: ParamTypeString, Description: "Read the table as it was at this RFC 3339 timestamp"},
+		{Name: AtOffsetParam, In: ParamInQuery, Type: ParamTypeInteger, Description: "Read the table as it was this many seconds ago, as a negative number"},
+	}
+}
+
+// KeyParam defines the path parameter selecting a record by its key column
+func KeyParam(table string, key Column) Parameter {
+	return Parameter{
+		Name:        ParamName(key.Name),
+		In:          ParamInPath,
+		Type:        ParamType(key.SQLType()),
+		Required:    true,
+		Description: fmt.Sprintf("%s of the %s record", key.Name, table),
+	}
+}
+
+// KeyParams defines the path parameters selecting a record by its key
+// columns
+func KeyParams(table string, keys []Column) []Parameter {
+	params := make([]Parameter, len(keys))
+	for i, key := range keys {
+		params[i] = KeyParam(table, key)
+	}
+	return params
+}
+
+// BodyParams defines the body fields of a write endpoint from its columns
+func BodyParams(columns []Column) []Parameter {
+	params := make([]Parameter, 0, len(columns))
+	for _, col := range columns {
+		description := col.Description
+		if col.VerboseDescription != "" {
+			description = col.VerboseDescription
+		}
+		params = append(params, Parameter{
+			Name:        col.Name,
+			In:          ParamInBody,
+			Type:        ParamType(col.SQLType()),
+			Required:    !col.Nullable,
+			Description: description,
+		})
+	}
+	return params
+}
+
+// InsertParams defines the body fields of a create endpoint: like
+// BodyParams, but columns the database has a default for may be omitted
+func InsertParams(columns []Column) []Parameter {
+	params := BodyParams(columns)
+	for i, col := range columns {
+		if col.Default != "" {
+			params[i].Required = false
+		}
+	}
+	return params
+}
+
+// ParamDefs returns the typed parameters of an endpoint. Endpoints
+// generated before parameters were typed only describe them in Parameters;
+// their parameters are derived from the path, the descriptions and the body
+// columns, typed as strings unless a column tells otherwise.
+func (e APIEndpoint) ParamDefs() []Parameter {
+	if len(e.Params) > 0 {
+		return e.Params
+	}
+
+	var params []Parameter
+	inPath := make(map[string]bool)
+	for _, m := range pathParam.FindAllStringSubmatch(e.Path, -1) {
+		name := m[1] + m[2]
+		inPath[name] = true
+		description, _ := e.Parameters[name].(string)
+		params = append(params, Parameter{Name: name, In: ParamInPath, Type: ParamTypeString, Required: true, Description: description})
+	}
+
+	body := make(map[string]bool, len(e.Columns))
+	for _, col := range e.Columns {
+		body[ParamName(col.Name)] = true
+	}
+	page := make(map[string]Parameter)
+	for _, p := range PageParams() {
+		page[p.Name] = p
+	}
+	names := make([]string, 0, len(e.Parameters))
+	for name := range e.Parameters {
+		if !inPath[name] && !body[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p, ok := page[name]; ok {
+			params = append(params, p)
+			continue
+		}
+		description, _ := e.Parameters[name].(string)
+		params = append(params, Parameter{Name: name, In: ParamInQuery, Type: ParamTypeString, Description: description})
+	}
+	if e.Operation == OperationCreate {
+		return append(params, InsertParams(e.Columns)...)
+	}
+	return append(params, BodyParams(e.Columns)...)
+}
@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamType(t *testing.T) {
+	assert.Equal(t, ParamTypeInteger, ParamType("NUMBER(38,0)"))
+	assert.Equal(t, ParamTypeInteger, ParamType("BIGINT"))
+	assert.Equal(t, ParamTypeNumber, ParamType("NUMBER(10,2)"))
+	assert.Equal(t, ParamTypeNumber, ParamType("FLOAT"))
+	assert.Equal(t, ParamTypeBoolean, ParamType("BOOLEAN"))
+	assert.Equal(t, ParamTypeString, ParamType("VARCHAR(16777216)"))
+	assert.Equal(t, ParamTypeString, ParamType("TIMESTAMP_NTZ"))
+}
+
+func TestParamDefs(t *testing.T) {
+	typed := APIEndpoint{Path: "/ORDERS", Params: PageParams()}
+	assert.Equal(t, PageParams(), typed.ParamDefs())
+
+	// Endpoints without typed parameters derive them
+	legacy := APIEndpoint{
+		Path: "/ORDERS/:ID",
+		Parameters: map[string]interface{}{
+			"ID":     "ID of the ORDERS record",
+			"STATUS": "Status of the order",
+			"limit":  "Number of records to return",
+			"expand": "Related records to include",
+		},
+		Columns: []Column{{Name: "STATUS", Type: "VARCHAR"}, {Name: "NOTE", Type: "VARCHAR", Nullable: true}},
+	}
+	assert.Equal(t, []Parameter{
+		{Name: "ID", In: ParamInPath, Type: ParamTypeString, Required: true, Description: "ID of the ORDERS record"},
+		{Name: "expand", In: ParamInQuery, Type: ParamTypeString, Description: "Related records to include"},
+		PageParams()[0],
+		{Name: "STATUS", In: ParamInBody, Type: ParamTypeString, Required: true},
+		{Name: "NOTE", In: ParamInBody, Type: ParamTypeString},
+	}, legacy.ParamDefs())
+}
+
+func TestColumnSQLType(t *testing.T) {
+	assert.Equal(t, "NUMBER(10,2)", Column{Type: "NUMBER", Precision: 10, Scale: 2}.SQLType())
+	assert.Equal(t, "TEXT(20)", Column{Type: "TEXT", MaxLength: 20}.SQLType())
+	assert.Equal(t, "VARCHAR(5)", Column{Type: "VARCHAR(5)", MaxLength: 5}.SQLType())
+	assert.Equal(t, "DATE", Column{Type: "DATE"}.SQLType())
+}
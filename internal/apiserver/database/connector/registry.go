@@ -0,0 +1,140 @@
+package connector
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+)
+
+// Factory creates a connector from the database configuration. provider is
+// the LLM provider for metadata enhancement, nil when none is configured.
+type Factory func(config *DatabaseConfig, provider llm.Provider) (DatabaseConnector, error)
+
+// SecretConfig is implemented by custom connector configurations holding
+// secrets, so the management API can redact them. Fields are keyed by
+// their JSON name within the section.
+type SecretConfig interface {
+	SecretFields() map[string]*string
+}
+
+var (
+	registryMu sync.RWMutex
+	factories  = make(map[string]Factory)
+	configs    = make(map[string]func() interface{})
+)
+
+func init() {
+	Register("snowflake", func(config *DatabaseConfig, provider llm.Provider) (DatabaseConnector, error) {
+		return NewSnowflakeConnector(config.Snowflake, provider, config.Prompts)
+	})
+}
+
+// Register makes a connector available under a database type. It is meant
+// to be called from the init function of the package providing the
+// connector and panics when the type is registered twice.
+func Register(dbType string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("connector: Register factory is nil")
+	}
+	if _, dup := factories[dbType]; dup {
+		panic("connector: Register called twice for " + dbType)
+	}
+	factories[dbType] = factory
+}
+
+// RegisterConfig declares the configuration section of a registered
+// connector. newConfig returns a pointer to a new value of the section's
+// type; the database config object named after the type is unmarshaled into
+// it and made available to the factory as DatabaseConfig.Custom.
+func RegisterConfig(dbType string, newConfig func() interface{}) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := configs[dbType]; dup {
+		panic("connector: RegisterConfig called twice for " + dbType)
+	}
+	configs[dbType] = newConfig
+}
+
+// Types returns the registered database types in alphabetical order
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(factories))
+	for t := range factories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// factoryFor returns the factory registered for a database type
+func factoryFor(dbType string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := factories[dbType]
+	return f, ok
+}
+
+// configFor returns the config constructor registered for a database type
+func configFor(dbType string) (func() interface{}, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := configs[dbType]
+	return f, ok
+}
+
+// databaseConfigJSON avoids recursing into the custom (un)marshalers
+type databaseConfigJSON DatabaseConfig
+
+// UnmarshalJSON decodes the built-in fields and the custom section of the
+// configured type, when one is registered
+func (c *DatabaseConfig) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*databaseConfigJSON)(c)); err != nil {
+		return err
+	}
+
+	newConfig, ok := configFor(c.Type)
+	if !ok {
+		return nil
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+	custom := newConfig()
+	if section, ok := sections[c.Type]; ok {
+		if err := json.Unmarshal(section, custom); err != nil {
+			return fmt.Errorf("invalid %s configuration: %w", c.Type, err)
+		}
+	}
+	c.Custom = custom
+	return nil
+}
+
+// MarshalJSON encodes the built-in fields and the custom section under the
+// configured type
+func (c DatabaseConfig) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(databaseConfigJSON(c))
+	if err != nil || c.Custom == nil || c.Type == "" {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	section, err := json.Marshal(c.Custom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s configuration: %w", c.Type, err)
+	}
+	fields[c.Type] = section
+	return json.Marshal(fields)
+}
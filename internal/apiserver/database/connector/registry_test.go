@@ -0,0 +1,50 @@
+package connector
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDBConfig struct {
+	Host     string `json:"host"`
+	Password string `json:"password"`
+}
+
+func TestRegisteredConnector(t *testing.T) {
+	errCalled := errors.New("factory called")
+	var got *DatabaseConfig
+	Register("testdb", func(config *DatabaseConfig, provider llm.Provider) (DatabaseConnector, error) {
+		got = config
+		return nil, errCalled
+	})
+	RegisterConfig("testdb", func() interface{} { return &testDBConfig{} })
+
+	assert.Contains(t, Types(), "snowflake")
+	assert.Contains(t, Types(), "testdb")
+	assert.Panics(t, func() {
+		Register("testdb", func(*DatabaseConfig, llm.Provider) (DatabaseConnector, error) { return nil, nil })
+	})
+
+	var config DatabaseConfig
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"testdb","testdb":{"host":"db.local","password":"secret"}}`), &config))
+	assert.Equal(t, &testDBConfig{Host: "db.local", Password: "secret"}, config.Custom)
+
+	_, err := NewDatabaseConnector(&config)
+	assert.ErrorIs(t, err, errCalled)
+	assert.Same(t, &config, got)
+
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"testdb","testdb":{"host":"db.local","password":"secret"}}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"type":"testdb","testdb":{"host":1}}`), &config)
+	assert.ErrorContains(t, err, "invalid testdb configuration")
+
+	_, err = NewDatabaseConnector(&DatabaseConfig{Type: "unknown"})
+	assert.EqualError(t, err, "unsupported database type: unknown")
+}
@@ -0,0 +1,76 @@
+package connector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMissingParam is returned when a query references an unbound parameter
+var ErrMissingParam = errors.New("missing query parameter")
+
+// renderParamPattern matches :name placeholders the way they are bound,
+// skipping :: casts
+var renderParamPattern = regexp.MustCompile(`(^|[^:]):([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// RenderQuery returns a query with its named parameters replaced by SQL
+// literals. The result is meant to be shown, e.g. for a dry run; queries are
+// always executed with bound parameters.
+func RenderQuery(query string, params map[string]interface{}) (string, error) {
+	var missing []string
+	rendered := renderParamPattern.ReplaceAllStringFunc(query, func(m string) string {
+		sub := renderParamPattern.FindStringSubmatch(m)
+		value, ok := params[sub[2]]
+		if !ok {
+			missing = append(missing, sub[2])
+			return m
+		}
+		return sub[1] + SQLLiteral(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrMissingParam, strings.Join(missing, ", "))
+	}
+	return rendered, nil
+}
+
+// SQLLiteral renders a parameter value as a SQL literal. Slices render as a
+// comma-separated list, as they are expanded when bound.
+func SQLLiteral(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case json.Number:
+		return v.String()
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return SQLLiteral(v.Format(time.RFC3339Nano))
+	case []byte:
+		return SQLLiteral(string(v))
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = SQLLiteral(rv.Index(i).Interface())
+		}
+		return strings.Join(items, ", ")
+	}
+	return SQLLiteral(fmt.Sprint(v))
+}
@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderQuery(t *testing.T) {
+	rendered, err := RenderQuery(
+		"SELECT * FROM ORDERS WHERE NAME = :name AND TOTAL::number > :min AND ACTIVE = :active AND REGION IN (:regions) AND NOTE = :note",
+		map[string]interface{}{
+			"name":    "O'Brien",
+			"min":     10.5,
+			"active":  true,
+			"regions": []string{"EU", "US"},
+			"note":    nil,
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM ORDERS WHERE NAME = 'O''Brien' AND TOTAL::number > 10.5 AND ACTIVE = TRUE AND REGION IN ('EU', 'US') AND NOTE = NULL", rendered)
+
+	_, err = RenderQuery("SELECT * FROM ORDERS WHERE ID = :id AND NAME = :name", map[string]interface{}{"id": 1})
+	assert.ErrorIs(t, err, ErrMissingParam)
+	assert.ErrorContains(t, err, "name")
+}
+
+func TestParseSnowflakePlan(t *testing.T) {
+	plan, err := parseSnowflakePlan(`{
+		"GlobalStats": {"partitionsTotal": 12, "partitionsAssigned": 3, "bytesAssigned": 4096},
+		"Operations": [[
+			{"id": 0, "operation": "Result", "expressions": ["ORDERS.ID"]},
+			{"id": 1, "parent": 0, "operation": "TableScan", "objects": ["DB.PUBLIC.ORDERS"], "partitionsAssigned": 3}
+		]]
+	}`)
+	require.NoError(t, err)
+
+	parent := 0
+	assert.Equal(t, &QueryPlan{
+		PartitionsTotal:    12,
+		PartitionsAssigned: 3,
+		BytesAssigned:      4096,
+		Operations: []PlanOperation{
+			{ID: 0, Operation: "Result", Expressions: []string{"ORDERS.ID"}},
+			{ID: 1, Parent: &parent, Operation: "TableScan", Objects: []string{"DB.PUBLIC.ORDERS"}},
+		},
+	}, plan)
+}
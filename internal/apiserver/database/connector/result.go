@@ -0,0 +1,123 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+)
+
+// OrderedQuerier is implemented by connectors that report the columns of a
+// query result in select order, which the maps returned by ExecuteQuery lose
+type OrderedQuerier interface {
+	// ExecuteQueryOrdered runs a SQL query like ExecuteQuery and returns
+	// the rows with their columns in select order
+	ExecuteQueryOrdered(ctx context.Context, query string, params map[string]interface{}) (*ResultSet, error)
+}
+
+// ResultSet holds the rows of a query with the order of their columns. It
+// encodes as the JSON array of its rows, each object listing its keys in
+// column order.
+type ResultSet struct {
+	Columns []string
+	Rows    []map[string]interface{}
+}
+
+// ColumnOrder returns the columns of the result followed, in sorted order,
+// by keys of the rows that are not among them, e.g. ones added after the
+// query ran or every key when the columns are unknown
+func (r *ResultSet) ColumnOrder() []string {
+	order := make([]string, 0, len(r.Columns))
+	seen := make(map[string]bool, len(r.Columns))
+	for _, col := range r.Columns {
+		if !seen[col] {
+			seen[col] = true
+			order = append(order, col)
+		}
+	}
+
+	var extra []string
+	for _, row := range r.Rows {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				extra = append(extra, key)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(order, extra...)
+}
+
+// MarshalJSON encodes the rows with their keys in ColumnOrder; keys a row
+// lacks are left out as they are from the row's own encoding
+func (r *ResultSet) MarshalJSON() ([]byte, error) {
+	if r.Rows == nil {
+		return []byte("null"), nil
+	}
+	order := r.ColumnOrder()
+
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, row := range r.Rows {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('{')
+		first := true
+		for _, col := range order {
+			v, ok := row[col]
+			if !ok {
+				continue
+			}
+			if !first {
+				b.WriteByte(',')
+			}
+			first = false
+			k, _ := json.Marshal(col)
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			b.Write(k)
+			b.WriteByte(':')
+			b.Write(data)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(']')
+	return b.Bytes(), nil
+}
+
+// MarshalJSON encodes the sample rows with their keys in column order
+func (m TableMetadata) MarshalJSON() ([]byte, error) {
+	type plain TableMetadata
+	var samples *ResultSet
+	if len(m.SampleData) > 0 {
+		samples = m.samples()
+	}
+	return json.Marshal(struct {
+		plain
+		SampleData *ResultSet `json:"sample_data,omitempty"`
+	}{plain(m), samples})
+}
+
+// SampleJSON returns each sample row encoded as JSON with its keys in
+// column order, for prompts that show the samples
+func (m *TableMetadata) SampleJSON() []string {
+	samples := m.samples()
+	rows := make([]string, 0, len(samples.Rows))
+	for _, row := range samples.Rows {
+		data, err := json.Marshal(&ResultSet{Columns: samples.Columns, Rows: []map[string]interface{}{row}})
+		if err != nil {
+			continue
+		}
+		rows = append(rows, string(bytes.TrimSuffix(bytes.TrimPrefix(data, []byte("[")), []byte("]"))))
+	}
+	return rows
+}
+
+// samples returns the sample rows with the table's columns in catalog order
+func (m *TableMetadata) samples() *ResultSet {
+	return &ResultSet{Columns: ColumnNames(m.Columns), Rows: m.SampleData}
+}
@@ -0,0 +1,48 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultSetKeepsColumnOrder(t *testing.T) {
+	result := &ResultSet{
+		Columns: []string{"ZIP", "CITY", "ADDED_AT"},
+		Rows: []map[string]interface{}{
+			{"ADDED_AT": "2024-01-01", "CITY": "Oslo", "ZIP": "0150", "NOTE": "x", "EXTRA": 1},
+			{"CITY": "Bergen", "ZIP": "5003"},
+		},
+	}
+	assert.Equal(t, []string{"ZIP", "CITY", "ADDED_AT", "EXTRA", "NOTE"}, result.ColumnOrder())
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"ZIP":"0150","CITY":"Oslo","ADDED_AT":"2024-01-01","EXTRA":1,"NOTE":"x"},{"ZIP":"5003","CITY":"Bergen"}]`, string(data))
+
+	data, err = json.Marshal(&ResultSet{Columns: []string{"ID"}})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
+
+func TestTableMetadataSamplesInCatalogOrder(t *testing.T) {
+	metadata := &TableMetadata{
+		Name:       "CITIES",
+		Columns:    []Column{{Name: "ZIP"}, {Name: "CITY"}},
+		SampleData: []map[string]interface{}{{"CITY": "Oslo", "ZIP": "0150"}},
+	}
+	data, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"sample_data":[{"ZIP":"0150","CITY":"Oslo"}]`)
+	assert.Equal(t, []string{`{"ZIP":"0150","CITY":"Oslo"}`}, metadata.SampleJSON())
+
+	var decoded TableMetadata
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, metadata.SampleData, decoded.SampleData)
+
+	data, err = json.Marshal(TableMetadata{Name: "EMPTY"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sample_data")
+}
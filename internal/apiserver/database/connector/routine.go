@@ -0,0 +1,79 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of routines
+const (
+	RoutineProcedure = "procedure"
+	RoutineFunction  = "function"
+)
+
+// Routine is a stored procedure or user-defined function
+type Routine struct {
+	Name        string            `json:"name"`
+	Kind        string            `json:"kind"`
+	Arguments   []RoutineArgument `json:"arguments"`
+	ReturnType  string            `json:"return_type"`
+	Description string            `json:"description,omitempty"`
+}
+
+// RoutineArgument is an argument of a routine
+type RoutineArgument struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Signature renders the name and argument types of a routine, which tell
+// overloads apart, e.g. ADD_ORDER(NUMBER, VARCHAR)
+func (r Routine) Signature() string {
+	types := make([]string, 0, len(r.Arguments))
+	for _, arg := range r.Arguments {
+		types = append(types, arg.Type)
+	}
+	return fmt.Sprintf("%s(%s)", r.Name, strings.Join(types, ", "))
+}
+
+// ReturnsTable reports whether a routine returns rows rather than a value
+func (r Routine) ReturnsTable() bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(r.ReturnType)), "TABLE")
+}
+
+// CallQuery renders the statement calling a routine with its arguments
+// bound to their ParamName: procedures are called with CALL, table
+// functions selected from and scalar functions selected as a column named
+// after the function
+func CallQuery(d Dialect, r Routine) string {
+	args := make([]string, 0, len(r.Arguments))
+	for _, arg := range r.Arguments {
+		args = append(args, ":"+ParamName(arg.Name))
+	}
+	call := fmt.Sprintf("%s(%s)", d.Table(r.Name), strings.Join(args, ", "))
+
+	switch {
+	case r.Kind == RoutineProcedure:
+		return "CALL " + call
+	case r.ReturnsTable():
+		return fmt.Sprintf("SELECT * FROM TABLE(%s)", call)
+	default:
+		return fmt.Sprintf("SELECT %s AS %s", call, d.QuoteIdentifier(r.Name))
+	}
+}
+
+// RoutineParams defines the arguments of a routine as required body
+// parameters
+func RoutineParams(r Routine) []Parameter {
+	params := make([]Parameter, 0, len(r.Arguments))
+	for _, arg := range r.Arguments {
+		params = append(params, Parameter{
+			Name:        arg.Name,
+			In:          ParamInBody,
+			Type:        ParamType(arg.Type),
+			Required:    true,
+			Description: fmt.Sprintf("%s argument of %s", arg.Type, r.Name),
+		})
+	}
+	return params
+}
@@ -0,0 +1,30 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArgumentSignature(t *testing.T) {
+	assert.Equal(t, []RoutineArgument{
+		{Name: "ID", Type: "NUMBER"},
+		{Name: "AMOUNT", Type: "NUMBER(10,2)"},
+	}, parseArgumentSignature("(ID NUMBER, AMOUNT NUMBER(10,2))"))
+	assert.Empty(t, parseArgumentSignature("()"))
+}
+
+func TestCallQuery(t *testing.T) {
+	d := SnowflakeDialect{Database: "DB", Schema: "S"}
+	args := []RoutineArgument{{Name: "ID", Type: "NUMBER"}, {Name: "NOTE", Type: "VARCHAR"}}
+
+	proc := Routine{Name: "CLOSE_ORDER", Kind: RoutineProcedure, Arguments: args, ReturnType: "VARCHAR"}
+	assert.Equal(t, `CALL "DB"."S"."CLOSE_ORDER"(:ID, :NOTE)`, CallQuery(d, proc))
+	assert.Equal(t, "CLOSE_ORDER(NUMBER, VARCHAR)", proc.Signature())
+
+	scalar := Routine{Name: "TAX", Kind: RoutineFunction, Arguments: args[:1], ReturnType: "NUMBER"}
+	assert.Equal(t, `SELECT "DB"."S"."TAX"(:ID) AS "TAX"`, CallQuery(d, scalar))
+
+	table := Routine{Name: "ORDER_LINES", Kind: RoutineFunction, Arguments: args[:1], ReturnType: "TABLE (LINE NUMBER)"}
+	assert.Equal(t, `SELECT * FROM TABLE("DB"."S"."ORDER_LINES"(:ID))`, CallQuery(d, table))
+}
@@ -0,0 +1,166 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRowFilter is returned when a row filter cannot be added to a query
+var ErrRowFilter = errors.New("cannot apply row filter")
+
+// sqlWord is a keyword or identifier outside parentheses, literals and
+// quoted identifiers
+type sqlWord struct {
+	word       string
+	start, end int
+}
+
+// clauseEnds are the keywords ending the WHERE clause of a statement
+var clauseEnds = map[string]bool{
+	"GROUP": true, "HAVING": true, "QUALIFY": true, "ORDER": true,
+	"LIMIT": true, "OFFSET": true, "FETCH": true, "RETURNING": true,
+}
+
+// AddRowFilter restricts a SELECT, UPDATE or DELETE statement to the rows
+// satisfying condition, ANDing it with the statement's WHERE clause or
+// adding one. It handles the single-table statements built by this
+// package; joins, combined queries and other statements are rejected.
+func AddRowFilter(query, condition string) (string, error) {
+	if _, _, err := scanSQL(condition); err != nil {
+		return "", fmt.Errorf("%w: invalid condition: %v", ErrRowFilter, err)
+	}
+	words, commas, err := scanSQL(query)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRowFilter, err)
+	}
+	if len(words) == 0 {
+		return "", fmt.Errorf("%w: empty statement", ErrRowFilter)
+	}
+
+	statement := words[0].word
+	if statement != "SELECT" && statement != "UPDATE" && statement != "DELETE" {
+		return "", fmt.Errorf("%w: %s statements are not filtered", ErrRowFilter, statement)
+	}
+
+	var from, where *sqlWord
+	end := len(query)
+	for i := 1; i < len(words); i++ {
+		w := &words[i]
+		switch {
+		case w.word == "JOIN" || w.word == "UNION" || w.word == "INTERSECT" || w.word == "EXCEPT" || w.word == "MINUS" || w.word == "USING":
+			return "", fmt.Errorf("%w: statements with %s are not supported", ErrRowFilter, w.word)
+		case w.word == "FROM" && from == nil:
+			if statement == "UPDATE" {
+				return "", fmt.Errorf("%w: UPDATE ... FROM is not supported", ErrRowFilter)
+			}
+			from = w
+		case w.word == "WHERE" && where == nil:
+			where = w
+		case clauseEnds[w.word] && end == len(query):
+			end = w.start
+		}
+	}
+	if statement != "UPDATE" && from == nil {
+		return "", fmt.Errorf("%w: %s without FROM", ErrRowFilter, statement)
+	}
+
+	// A comma after FROM lists several tables
+	if from != nil {
+		for _, pos := range commas {
+			if pos > from.end && pos < end {
+				return "", fmt.Errorf("%w: statements reading several tables are not supported", ErrRowFilter)
+			}
+		}
+	}
+
+	tail := ""
+	if end < len(query) {
+		tail = " " + query[end:]
+	}
+	if where != nil && where.start < end {
+		existing := strings.TrimSpace(query[where.end:end])
+		return fmt.Sprintf("%sWHERE (%s) AND (%s)%s", query[:where.start], existing, condition, tail), nil
+	}
+	return fmt.Sprintf("%s WHERE (%s)%s", strings.TrimRight(query[:end], " \t\r\n"), condition, tail), nil
+}
+
+// CheckExpression checks that a configured SQL expression or condition is
+// a single balanced expression without comments or statement separators
+func CheckExpression(sql string) error {
+	_, _, err := scanSQL(sql)
+	return err
+}
+
+// scanSQL returns the upper-cased words and the positions of the commas
+// at the top level of a statement. Bind parameters are skipped, and
+// unbalanced parentheses, unterminated literals, comments and statement
+// separators are rejected.
+func scanSQL(sql string) ([]sqlWord, []int, error) {
+	var words []sqlWord
+	var commas []int
+	depth := 0
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := i + 1
+			for ; j < len(sql); j++ {
+				if sql[j] == c {
+					if j+1 < len(sql) && sql[j+1] == c {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			if j >= len(sql) {
+				return nil, nil, fmt.Errorf("unterminated %c at offset %d", c, i)
+			}
+			i = j + 1
+		case c == '(':
+			depth++
+			i++
+		case c == ')':
+			if depth--; depth < 0 {
+				return nil, nil, fmt.Errorf("unbalanced ) at offset %d", i)
+			}
+			i++
+		case c == ';':
+			return nil, nil, fmt.Errorf("multiple statements are not supported")
+		case strings.HasPrefix(sql[i:], "--") || strings.HasPrefix(sql[i:], "/*"):
+			return nil, nil, fmt.Errorf("comments are not supported")
+		case c == ',':
+			if depth == 0 {
+				commas = append(commas, i)
+			}
+			i++
+		case c == ':':
+			// Bind parameters and casts name no clauses
+			j := i + 1
+			for j < len(sql) && (sql[j] == ':' || isWordChar(sql[j])) {
+				j++
+			}
+			i = j
+		case isWordChar(c):
+			j := i
+			for j < len(sql) && isWordChar(sql[j]) {
+				j++
+			}
+			if depth == 0 {
+				words = append(words, sqlWord{word: strings.ToUpper(sql[i:j]), start: i, end: j})
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+	if depth != 0 {
+		return nil, nil, fmt.Errorf("unbalanced (")
+	}
+	return words, commas, nil
+}
+
+func isWordChar(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_' || c == '$'
+}
@@ -0,0 +1,42 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRowFilter(t *testing.T) {
+	d := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	cond := "TENANT_ID = :rls_tenant"
+	for query, want := range map[string]string{
+		SelectPageQuery(d, "ORDERS"):                              `SELECT * FROM "DB"."PUBLIC"."ORDERS" WHERE (TENANT_ID = :rls_tenant) LIMIT :limit OFFSET :offset`,
+		SelectByKeyQuery(d, "ORDERS", "ID"):                       `SELECT * FROM "DB"."PUBLIC"."ORDERS" WHERE ("ID" = :ID) AND (TENANT_ID = :rls_tenant)`,
+		DeleteQuery(d, "ORDERS", "ID"):                            `DELETE FROM "DB"."PUBLIC"."ORDERS" WHERE ("ID" = :ID) AND (TENANT_ID = :rls_tenant)`,
+		SearchQuery(d, "ORDERS", []string{"NOTE"}):                `SELECT * FROM "DB"."PUBLIC"."ORDERS" WHERE (CONTAINS(LOWER("NOTE"), LOWER(:q))) AND (TENANT_ID = :rls_tenant) ORDER BY CASE WHEN CONTAINS(LOWER("NOTE"), LOWER(:q)) THEN 1 ELSE 0 END DESC LIMIT :limit OFFSET :offset`,
+		`SELECT "ID", "NOTE" FROM "ORDERS" WHERE "ID" IN (:keys)`: `SELECT "ID", "NOTE" FROM "ORDERS" WHERE ("ID" IN (:keys)) AND (TENANT_ID = :rls_tenant)`,
+		UpdateQuery(d, "ORDERS", []string{"ID"}, []Column{{Name: "ID"}, {Name: "NOTE"}, {Name: "WHERE"}}): `UPDATE "DB"."PUBLIC"."ORDERS" SET "NOTE" = :NOTE, "WHERE" = :WHERE WHERE ("ID" = :ID) AND (TENANT_ID = :rls_tenant)`,
+	} {
+		filtered, err := AddRowFilter(query, cond)
+		require.NoError(t, err, query)
+		assert.Equal(t, want, filtered)
+	}
+
+	for query, msg := range map[string]string{
+		InsertQuery(d, "ORDERS", []Column{{Name: "ID"}}):         "INSERT statements are not filtered",
+		`SELECT * FROM ORDERS JOIN CUSTOMERS ON 1 = 1`:           "statements with JOIN are not supported",
+		`SELECT * FROM ORDERS, CUSTOMERS`:                        "statements reading several tables are not supported",
+		`SELECT * FROM ORDERS WHERE ID = 1; DELETE FROM ORDERS`:  "multiple statements are not supported",
+		`SELECT * FROM ORDERS WHERE ID = 1 -- AND TENANT_ID = 2`: "comments are not supported",
+		`SELECT * FROM ORDERS WHERE NOTE = 'it''s`:               "unterminated ' at offset 34",
+	} {
+		_, err := AddRowFilter(query, cond)
+		assert.ErrorIs(t, err, ErrRowFilter, query)
+		assert.ErrorContains(t, err, msg, query)
+	}
+
+	// Conditions cannot close the parentheses they are wrapped in
+	_, err := AddRowFilter(SelectPageQuery(d, "ORDERS"), "1 = 1) OR (1 = 1")
+	assert.ErrorContains(t, err, "invalid condition: unbalanced )")
+}
@@ -0,0 +1,119 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sampling strategies of SampleConfig
+const (
+	// SampleHead takes the first rows the table returns
+	SampleHead = "head"
+
+	// SampleRandom picks rows at random from the whole table
+	SampleRandom = "random"
+
+	// SampleBlock picks rows at random from a percentage of the table's
+	// blocks, which reads far less of large tables than SampleRandom
+	SampleBlock = "block"
+)
+
+// DefaultSampleRows is the number of sample rows gathered by default
+const DefaultSampleRows = 5
+
+// SampleConfig controls the sample rows gathered with table metadata
+type SampleConfig struct {
+	// Disabled skips sample collection, e.g. for schemas holding
+	// sensitive data
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Rows is the number of sample rows (default: 5)
+	Rows int `json:"rows,omitempty"`
+
+	// Strategy is head (default), random or block
+	Strategy string `json:"strategy,omitempty"`
+
+	// Percent of the blocks read by the block strategy (default: 1)
+	Percent float64 `json:"percent,omitempty"`
+
+	// ExcludeColumns are left out of the samples, as COLUMN for every
+	// table or TABLE.COLUMN for one table
+	ExcludeColumns []string `json:"exclude_columns,omitempty"`
+}
+
+// sampler gathers sample rows as configured by a SampleConfig
+type sampler struct {
+	disabled bool
+	rows     int
+	strategy string
+	percent  float64
+	exclude  map[string]bool
+}
+
+// newSampler validates a sample configuration; nil selects the defaults
+func newSampler(cfg *SampleConfig) (*sampler, error) {
+	s := &sampler{rows: DefaultSampleRows, strategy: SampleHead, percent: 1, exclude: make(map[string]bool)}
+	if cfg == nil {
+		return s, nil
+	}
+	s.disabled = cfg.Disabled
+	switch {
+	case cfg.Rows < 0:
+		return nil, fmt.Errorf("rows must not be negative")
+	case cfg.Rows > 0:
+		s.rows = cfg.Rows
+	}
+	switch cfg.Strategy {
+	case "":
+	case SampleHead, SampleRandom, SampleBlock:
+		s.strategy = cfg.Strategy
+	default:
+		return nil, fmt.Errorf("unsupported sampling strategy %q", cfg.Strategy)
+	}
+	switch {
+	case cfg.Percent < 0 || cfg.Percent > 100:
+		return nil, fmt.Errorf("percent must be between 0 and 100")
+	case cfg.Percent > 0:
+		s.percent = cfg.Percent
+	}
+	for _, col := range cfg.ExcludeColumns {
+		s.exclude[strings.ToUpper(col)] = true
+	}
+	return s, nil
+}
+
+// columns returns the columns of a table included in its samples
+func (s *sampler) columns(table string, columns []Column) []Column {
+	included := make([]Column, 0, len(columns))
+	for _, col := range columns {
+		name := strings.ToUpper(col.Name)
+		if s.exclude[name] || s.exclude[strings.ToUpper(table)+"."+name] {
+			continue
+		}
+		included = append(included, col)
+	}
+	return included
+}
+
+// query renders the Snowflake query sampling the columns of a table, or
+// an empty string when no sample is taken
+func (s *sampler) query(d Dialect, table string, columns []Column) string {
+	columns = s.columns(table, columns)
+	if s.disabled || len(columns) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, col := range columns {
+		names = append(names, d.QuoteIdentifier(col.Name))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(names, ", "), d.Table(table))
+	switch s.strategy {
+	case SampleRandom:
+		return fmt.Sprintf("%s SAMPLE ROW (%d ROWS)", query, s.rows)
+	case SampleBlock:
+		return fmt.Sprintf("%s SAMPLE BLOCK (%g) LIMIT %d", query, s.percent, s.rows)
+	default:
+		return fmt.Sprintf("%s LIMIT %d", query, s.rows)
+	}
+}
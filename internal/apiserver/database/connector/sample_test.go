@@ -0,0 +1,39 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplerQuery(t *testing.T) {
+	d := ANSIDialect{}
+	columns := []Column{{Name: "ID"}, {Name: "EMAIL"}, {Name: "SSN"}}
+
+	s, err := newSampler(nil)
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "ID", "EMAIL", "SSN" FROM "USERS" LIMIT 5`, s.query(d, "USERS", columns))
+
+	s, err = newSampler(&SampleConfig{Rows: 20, Strategy: SampleRandom, ExcludeColumns: []string{"ssn", "USERS.EMAIL"}})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "ID" FROM "USERS" SAMPLE ROW (20 ROWS)`, s.query(d, "USERS", columns))
+	assert.Equal(t, `SELECT "ID", "EMAIL" FROM "LEADS" SAMPLE ROW (20 ROWS)`, s.query(d, "LEADS", columns))
+
+	s, err = newSampler(&SampleConfig{Strategy: SampleBlock, Percent: 0.5})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT "ID" FROM "USERS" SAMPLE BLOCK (0.5) LIMIT 5`, s.query(d, "USERS", columns[:1]))
+
+	// Disabled sampling and fully excluded tables take no sample
+	s, err = newSampler(&SampleConfig{Disabled: true})
+	require.NoError(t, err)
+	assert.Empty(t, s.query(d, "USERS", columns))
+	s, err = newSampler(&SampleConfig{ExcludeColumns: []string{"ID"}})
+	require.NoError(t, err)
+	assert.Empty(t, s.query(d, "USERS", columns[:1]))
+
+	for _, cfg := range []*SampleConfig{{Rows: -1}, {Strategy: "tail"}, {Percent: 150}} {
+		_, err := newSampler(cfg)
+		assert.Error(t, err)
+	}
+}
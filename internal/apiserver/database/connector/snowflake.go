@@ -4,33 +4,93 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
+	"database/sql"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
 	sf "github.com/snowflakedb/gosnowflake"
 )
 
 // SnowflakeConnector implements the DatabaseConnector interface for Snowflake
 type SnowflakeConnector struct {
-	db     *sqlx.DB
-	config *SnowflakeConfig
+	db       *sqlx.DB
+	config   *SnowflakeConfig
+	cost     costGuard
+	enhancer *metadataEnhancer
+	values   *valueNormalizer
+	samples  *sampler
+
+	// idleTimeout closes the idle sessions of a lazy connection; zero
+	// connects eagerly
+	idleTimeout time.Duration
 }
 
-// NewSnowflakeConnector creates a new Snowflake connector
-func NewSnowflakeConnector(config *SnowflakeConfig) (DatabaseConnector, error) {
+// defaultIdleTimeout is how long an idle session of a lazy connection is
+// kept
+const defaultIdleTimeout = 5 * time.Minute
+
+// snowflakeDriver is the database/sql driver connections are opened with
+var snowflakeDriver = "snowflake"
+
+// NewSnowflakeConnector creates a new Snowflake connector. The LLM provider
+// is optional; without it metadata descriptions are derived from the schema.
+// A nil prompt registry uses the built-in templates.
+func NewSnowflakeConnector(config *SnowflakeConfig, provider llm.Provider, prompts *prompt.Registry) (DatabaseConnector, error) {
 	if config == nil {
 		return nil, fmt.Errorf("snowflake configuration is required")
 	}
-	
+
+	values, err := newValueNormalizer(config.ResultTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid result types: %w", err)
+	}
+	samples, err := newSampler(config.Sampling)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling: %w", err)
+	}
+	idleTimeout, err := lazyIdleTimeout(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SnowflakeConnector{
-		config: config,
+		config:      config,
+		enhancer:    newMetadataEnhancer(provider, prompts),
+		values:      values,
+		samples:     samples,
+		idleTimeout: idleTimeout,
 	}, nil
 }
 
+// lazyIdleTimeout returns the idle timeout of a lazy connection, zero when
+// the connection is not lazy
+func lazyIdleTimeout(config *SnowflakeConfig) (time.Duration, error) {
+	if !config.Lazy {
+		if config.IdleTimeout != "" {
+			return 0, fmt.Errorf("idle_timeout requires lazy")
+		}
+		return 0, nil
+	}
+	if config.IdleTimeout == "" {
+		return defaultIdleTimeout, nil
+	}
+	d, err := time.ParseDuration(config.IdleTimeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid idle_timeout: %s", config.IdleTimeout)
+	}
+	return d, nil
+}
+
 // Connect establishes a connection to the Snowflake database
 func (c *SnowflakeConnector) Connect(ctx context.Context) error {
 	// Create DSN based on authentication type
@@ -39,16 +99,28 @@ func (c *SnowflakeConnector) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to create Snowflake DSN: %w", err)
 	}
 
-	// Connect to Snowflake
-	db, err := sqlx.ConnectContext(ctx, "snowflake", dsn)
+	// Connect to Snowflake. A lazy connection only prepares the pool, which
+	// opens a session when a query needs one and closes sessions left idle,
+	// so that the database holds none between uses.
+	var db *sqlx.DB
+	if c.idleTimeout > 0 {
+		db, err = sqlx.Open(snowflakeDriver, dsn)
+	} else {
+		db, err = sqlx.ConnectContext(ctx, snowflakeDriver, dsn)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to connect to Snowflake: %w", err)
 	}
 
-	// Set connection pool settings
+	// Set connection pool settings. A lazy connection keeps at most one
+	// idle session, and only for its idle timeout.
 	db.SetMaxOpenConns(10)
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(time.Hour)
+	if c.idleTimeout > 0 {
+		db.SetMaxIdleConns(1)
+		db.SetConnMaxIdleTime(c.idleTimeout)
+	}
 
 	c.db = db
 	return nil
@@ -121,11 +193,30 @@ func (c *SnowflakeConnector) GetTableMetadata(ctx context.Context, tableName str
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	// Get table columns
+	// Get table columns; the catalog lookup also validates the name before
+	// it is used in generated SQL
 	columns, err := c.getTableColumns(ctx, tableName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTable, tableName)
+	}
+
+	// Foreign keys are informational in Snowflake, so missing them is not critical
+	if err := c.markForeignKeys(ctx, tableName, columns); err != nil {
+		log.Printf("Warning: Failed to get foreign keys of %s: %v", tableName, err)
+	}
+
+	// Indexes and constraints only inform query writers; they are not critical
+	indexes, err := c.getTableIndexes(ctx, tableName)
+	if err != nil {
+		log.Printf("Warning: Failed to get indexes of %s: %v", tableName, err)
+	}
+	constraints, err := c.getTableConstraints(ctx, tableName)
+	if err != nil {
+		log.Printf("Warning: Failed to get constraints of %s: %v", tableName, err)
+	}
 
 	// Get row count
 	rowCount, err := c.getTableRowCount(ctx, tableName)
@@ -161,47 +252,261 @@ func (c *SnowflakeConnector) GetTableMetadata(ctx context.Context, tableName str
 		Columns:     columns,
 		SampleData:  sampleData,
 		RowCount:    rowCount,
+		Indexes:     indexes,
+		Constraints: constraints,
 	}
 
 	return metadata, nil
 }
 
+// DescribeSchema returns the columns of every base table in the schema with
+// a single information_schema query
+func (c *SnowflakeConnector) DescribeSchema(ctx context.Context) (map[string][]Column, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			c.TABLE_NAME,
+			c.COLUMN_NAME,
+			c.DATA_TYPE,
+			c.IS_NULLABLE,
+			c.COLUMN_DEFAULT,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			c.NUMERIC_PRECISION,
+			c.NUMERIC_SCALE
+		FROM
+			information_schema.columns c
+		JOIN
+			information_schema.tables t
+		ON
+			c.table_catalog = t.table_catalog
+			AND c.table_schema = t.table_schema
+			AND c.table_name = t.table_name
+		WHERE
+			t.table_type = 'BASE TABLE'
+			AND c.table_schema = ?
+			AND c.table_catalog = ?
+		ORDER BY
+			c.table_name, c.ordinal_position
+	`
+
+	rows, err := c.db.QueryxContext(ctx, query, c.config.Schema, c.config.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe schema: %w", err)
+	}
+	defer rows.Close()
+
+	schema := make(map[string][]Column)
+	for rows.Next() {
+		var tableName, name, dataType, isNullable string
+		var columnDefault sql.NullString
+		var maxLength, precision, scale sql.NullInt64
+		if err := rows.Scan(&tableName, &name, &dataType, &isNullable, &columnDefault, &maxLength, &precision, &scale); err != nil {
+			return nil, fmt.Errorf("failed to scan column row: %w", err)
+		}
+		schema[tableName] = append(schema[tableName], Column{
+			Name:      name,
+			Type:      dataType,
+			Nullable:  isNullable == "YES",
+			Default:   columnDefault.String,
+			MaxLength: int(maxLength.Int64),
+			Precision: int(precision.Int64),
+			Scale:     int(scale.Int64),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to describe schema: %w", err)
+	}
+
+	return schema, nil
+}
+
 // ExecuteQuery runs a SQL query against the database
 func (c *SnowflakeConnector) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
 	if c.db == nil {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	// Prepare the query with named parameters
-	namedQuery, args, err := sqlx.Named(query, params)
+	if err := c.checkCreditBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	return queryRows(ctx, c.db, c.values, query, params)
+}
+
+// ExecuteQueryOrdered runs a SQL query and returns the rows with their
+// columns in select order
+func (c *SnowflakeConnector) ExecuteQueryOrdered(ctx context.Context, query string, params map[string]interface{}) (*ResultSet, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	if err := c.checkCreditBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	return queryResult(ctx, c.db, c.values, query, params)
+}
+
+// CancelQuery cancels a running query with SYSTEM$CANCEL_QUERY
+func (c *SnowflakeConnector) CancelQuery(ctx context.Context, id string) error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	if _, err := c.db.ExecContext(ctx, "SELECT SYSTEM$CANCEL_QUERY(?)", id); err != nil {
+		return fmt.Errorf("failed to cancel query %s: %w", id, err)
+	}
+	return nil
+}
+
+// queryer is the query interface shared by sqlx.DB and sqlx.Tx
+type queryer interface {
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	Rebind(query string) string
+}
+
+// queryRows binds named parameters, runs a query and scans every row
+func queryRows(ctx context.Context, q queryer, values *valueNormalizer, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	result, err := queryResult(ctx, q, values, query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prepare named query: %w", err)
+		return nil, err
 	}
-	
-	// Convert to ? placeholders for Snowflake
-	query, args, err = sqlx.In(namedQuery, args...)
+	return result.Rows, nil
+}
+
+// queryResult binds named parameters, runs a query and scans every row
+// along with the order of the result's columns
+func queryResult(ctx context.Context, q queryer, values *valueNormalizer, query string, params map[string]interface{}) (*ResultSet, error) {
+	query, args, err := bindNamed(query, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert named parameters: %w", err)
+		return nil, err
+	}
+
+	// Tag the query so its spend can be attributed from QUERY_HISTORY
+	if tag := QueryTagFromContext(ctx); tag != "" {
+		ctx = sf.WithQueryTag(ctx, tag)
+	}
+
+	// Report the Snowflake query ID so the query can be cancelled
+	if report := QueryIDReporterFromContext(ctx); report != nil {
+		ids := make(chan string, 1)
+		done := make(chan struct{})
+		defer close(done)
+		ctx = sf.WithQueryIDChan(ctx, ids)
+		go func() {
+			select {
+			case id, ok := <-ids:
+				if ok && id != "" {
+					report(id)
+				}
+			case <-done:
+			}
+		}()
 	}
-	
+
 	// Execute the query
-	rows, err := c.db.QueryxContext(ctx, c.db.Rebind(query), args...)
+	rows, err := q.QueryxContext(ctx, q.Rebind(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
-	
-	// Process results
-	var result []map[string]interface{}
+
+	return scanResult(rows, values)
+}
+
+// bindNamed binds the named parameters of a query to ? placeholders
+func bindNamed(query string, params map[string]interface{}) (string, []interface{}, error) {
+	namedQuery, args, err := sqlx.Named(query, params)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to prepare named query: %w", err)
+	}
+	query, args, err = sqlx.In(namedQuery, args...)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert named parameters: %w", err)
+	}
+	return query, args, nil
+}
+
+// snowflakeValueKinds classifies Snowflake result column types
+var snowflakeValueKinds = map[string]valueKind{
+	"REAL":          kindFloat,
+	"BOOLEAN":       kindBoolean,
+	"TIMESTAMP_LTZ": kindTimestamp,
+	"TIMESTAMP_NTZ": kindTimestamp,
+	"TIMESTAMP_TZ":  kindTimestamp,
+	"DATE":          kindDate,
+	"TIME":          kindTime,
+	"VARIANT":       kindSemiStructured,
+	"OBJECT":        kindSemiStructured,
+	"ARRAY":         kindSemiStructured,
+	"BINARY":        kindBinary,
+}
+
+// scanRows reads every row, normalizing values by column type
+func scanRows(rows *sqlx.Rows, values *valueNormalizer) ([]map[string]interface{}, error) {
+	result, err := scanResult(rows, values)
+	if err != nil {
+		return nil, err
+	}
+	return result.Rows, nil
+}
+
+// scanResult reads every row like scanRows, keeping the order of the
+// result's columns
+func scanResult(rows *sqlx.Rows, values *valueNormalizer) (*ResultSet, error) {
+	result, _, err := scanResultPage(rows, values, 0, -1)
+	return result, err
+}
+
+// scanResultPage reads up to limit rows after skipping offset rows, or
+// every row when limit is negative, reporting whether more rows follow
+func scanResultPage(rows *sqlx.Rows, values *valueNormalizer, offset, limit int) (*ResultSet, bool, error) {
+	if values == nil {
+		values, _ = newValueNormalizer(nil)
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get column types: %w", err)
+	}
+	columns := make([]string, 0, len(columnTypes))
+	kinds := make(map[string]valueKind, len(columnTypes))
+	for _, ct := range columnTypes {
+		columns = append(columns, ct.Name())
+		kind := snowflakeValueKinds[ct.DatabaseTypeName()]
+		if ct.DatabaseTypeName() == "FIXED" {
+			kind = kindInteger
+			if _, scale, ok := ct.DecimalSize(); ok && scale > 0 {
+				kind = kindDecimal
+			}
+		}
+		kinds[ct.Name()] = kind
+	}
+
+	result := &ResultSet{Columns: columns}
 	for rows.Next() {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		if limit >= 0 && len(result.Rows) == limit {
+			return result, true, nil
+		}
 		row := make(map[string]interface{})
 		if err := rows.MapScan(row); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return nil, false, fmt.Errorf("failed to scan row: %w", err)
 		}
-		result = append(result, row)
+		for name, v := range row {
+			row[name] = values.normalize(kinds[name], v)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read rows: %w", err)
 	}
-	
-	return result, nil
+
+	return result, false, nil
 }
 
 // GenerateAPIEndpoints creates API endpoints based on database tables
@@ -211,6 +516,7 @@ func (c *SnowflakeConnector) GenerateAPIEndpoints(ctx context.Context, tables []
 	}
 
 	var endpoints []APIEndpoint
+	dialect := c.Dialect()
 
 	for _, tableName := range tables {
 		// Get table metadata
@@ -219,72 +525,136 @@ func (c *SnowflakeConnector) GenerateAPIEndpoints(ctx context.Context, tables []
 			return nil, fmt.Errorf("failed to get metadata for table %s: %w", tableName, err)
 		}
 
-		// Find primary key column
-		var primaryKeyColumn string
-		for _, col := range metadata.Columns {
-			if col.PrimaryKey {
-				primaryKeyColumn = col.Name
-				break
-			}
-		}
-
 		// Generate endpoints for this table
 		tableEndpoints := []APIEndpoint{
 			// List all records
 			{
+				Table:       tableName,
 				Method:      "GET",
+				Operation:   OperationList,
 				Path:        fmt.Sprintf("/%s", tableName),
 				Description: fmt.Sprintf("List all records from %s table", tableName),
-				Query:       fmt.Sprintf("SELECT * FROM \"%s\".\"%s\".\"%s\" LIMIT :limit OFFSET :offset", c.config.Database, c.config.Schema, tableName),
+				Query:       SelectPageQuery(dialect, tableName),
 				Parameters: map[string]interface{}{
 					"limit":  "Number of records to return",
 					"offset": "Number of records to skip",
 				},
+				Params:  PageParams(),
+				OrderBy: ColumnNames(PrimaryKeyColumns(metadata.Columns)),
 			},
 		}
 
-		// Add get by ID endpoint if primary key exists
-		if primaryKeyColumn != "" {
+		// Create a record
+		createColumns := bodyColumns(InsertColumns(dialect, metadata.Columns))
+		tableEndpoints = append(tableEndpoints, APIEndpoint{
+			Table:       tableName,
+			Method:      "POST",
+			Operation:   OperationCreate,
+			Path:        fmt.Sprintf("/%s", tableName),
+			Description: fmt.Sprintf("Create a record in %s", tableName),
+			Query:       InsertQuery(dialect, tableName, metadata.Columns),
+			Columns:     createColumns,
+			Params:      InsertParams(createColumns),
+			KeyColumns:  ColumnNames(PrimaryKeyColumns(metadata.Columns)),
+		})
+
+		// Search the text columns
+		if textColumns := TextColumns(metadata.Columns); len(textColumns) > 0 {
 			tableEndpoints = append(tableEndpoints, APIEndpoint{
+				Table:       tableName,
 				Method:      "GET",
-				Path:        fmt.Sprintf("/%s/{%s}", tableName, primaryKeyColumn),
-				Description: fmt.Sprintf("Get a single record from %s by ID", tableName),
-				Query:       fmt.Sprintf("SELECT * FROM \"%s\".\"%s\".\"%s\" WHERE \"%s\" = :%s", c.config.Database, c.config.Schema, tableName, primaryKeyColumn, primaryKeyColumn),
+				Operation:   OperationSearch,
+				Path:        fmt.Sprintf("/%s/search", tableName),
+				Description: fmt.Sprintf("Search the text columns of %s", tableName),
+				Query:       SearchQuery(dialect, tableName, textColumns),
 				Parameters: map[string]interface{}{
-					primaryKeyColumn: fmt.Sprintf("ID of the %s record", tableName),
+					SearchParam: "Text to search for",
+					"columns":   "Comma-separated columns to search (default: all text columns)",
+					"limit":     "Number of records to return",
+					"offset":    "Number of records to skip",
 				},
+				Params:        SearchParams(),
+				SearchColumns: textColumns,
 			})
 		}
 
+		// Add get by ID and update endpoints if primary key exists
+		tableEndpoints = append(tableEndpoints, KeyEndpoints(dialect, tableName, metadata.Columns, PrimaryKeyColumns(metadata.Columns))...)
+
 		endpoints = append(endpoints, tableEndpoints...)
 	}
 
 	return endpoints, nil
 }
 
+// KeyEndpoints generates the endpoints getting and replacing a record of a
+// table by its key columns: the primary key, or unique columns configured
+// for a table without one. Composite keys take a path segment per column.
+func KeyEndpoints(dialect Dialect, tableName string, columns []Column, keys []Column) []APIEndpoint {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	keyNames := ColumnNames(keys)
+	keyPath := fmt.Sprintf("/%s", tableName)
+	keyParameters := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		keyPath += fmt.Sprintf("/{%s}", ParamName(key.Name))
+		keyParameters[ParamName(key.Name)] = fmt.Sprintf("%s of the %s record", key.Name, tableName)
+	}
+
+	update := APIEndpoint{
+		Table:       tableName,
+		Method:      "PUT",
+		Operation:   OperationUpdate,
+		Path:        keyPath,
+		Description: fmt.Sprintf("Replace a record in %s by ID", tableName),
+		Query:       UpdateQuery(dialect, tableName, keyNames, columns),
+		Parameters:  keyParameters,
+		Columns:     bodyColumns(columns, keyNames...),
+		KeyColumns:  keyNames,
+	}
+	if version, ok := VersionColumn(columns); ok {
+		VersionUpdate(dialect, &update, tableName, keyNames, version, columns)
+	}
+	update.Params = append(KeyParams(tableName, keys), BodyParams(update.Columns)...)
+	return []APIEndpoint{{
+		Table:       tableName,
+		Method:      "GET",
+		Operation:   OperationGet,
+		Path:        keyPath,
+		Description: fmt.Sprintf("Get a single record from %s by ID", tableName),
+		Query:       SelectByKeyQuery(dialect, tableName, keyNames...),
+		Parameters:  keyParameters,
+		Params:      KeyParams(tableName, keys),
+	}, update}
+}
+
+// bodyColumns returns the columns a write endpoint accepts in its body,
+// without the keys taken from the path and the sample values
+func bodyColumns(columns []Column, pathKeys ...string) []Column {
+	body := make([]Column, 0, len(columns))
+	for _, col := range columns {
+		if slices.Contains(pathKeys, col.Name) {
+			continue
+		}
+		body = append(body, Column{
+			Name:       col.Name,
+			Type:       col.Type,
+			PrimaryKey: col.PrimaryKey,
+			Nullable:   col.Nullable,
+			Default:    col.Default,
+			MaxLength:  col.MaxLength,
+			Precision:  col.Precision,
+			Scale:      col.Scale,
+		})
+	}
+	return body
+}
+
 // EnhanceMetadataWithLLM uses LLM to generate verbose descriptions
 func (c *SnowflakeConnector) EnhanceMetadataWithLLM(ctx context.Context, metadata *TableMetadata) error {
-	// This is a placeholder for LLM integration
-	// In a real implementation, this would call an LLM service
-	
-	// For now, generate a basic description
-	description := fmt.Sprintf("Table %s contains %d columns and %d rows. ", 
-		metadata.Name, len(metadata.Columns), metadata.RowCount)
-	
-	// Add column information
-	description += "Columns include: "
-	for i, col := range metadata.Columns {
-		if i > 0 {
-			description += ", "
-		}
-		description += col.Name + " (" + col.Type + ")"
-		if col.PrimaryKey {
-			description += " [Primary Key]"
-		}
-	}
-	
-	metadata.VerboseDescription = description
-	return nil
+	return c.enhancer.enhance(ctx, metadata)
 }
 
 // Helper functions
@@ -296,6 +666,11 @@ func (c *SnowflakeConnector) getTableColumns(ctx context.Context, tableName stri
 			c.COLUMN_NAME,
 			c.DATA_TYPE,
 			c.COMMENT,
+			c.IS_NULLABLE,
+			c.COLUMN_DEFAULT,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			c.NUMERIC_PRECISION,
+			c.NUMERIC_SCALE,
 			CASE WHEN k.COLUMN_NAME IS NOT NULL THEN true ELSE false END as is_primary_key
 		FROM 
 			information_schema.columns c
@@ -323,9 +698,11 @@ func (c *SnowflakeConnector) getTableColumns(ctx context.Context, tableName stri
 
 	var columns []Column
 	for rows.Next() {
-		var name, dataType, comment string
+		var name, dataType, comment, isNullable string
+		var columnDefault sql.NullString
+		var maxLength, precision, scale sql.NullInt64
 		var isPrimaryKey bool
-		if err := rows.Scan(&name, &dataType, &comment, &isPrimaryKey); err != nil {
+		if err := rows.Scan(&name, &dataType, &comment, &isNullable, &columnDefault, &maxLength, &precision, &scale, &isPrimaryKey); err != nil {
 			return nil, fmt.Errorf("failed to scan column row: %w", err)
 		}
 
@@ -334,6 +711,11 @@ func (c *SnowflakeConnector) getTableColumns(ctx context.Context, tableName stri
 			Type:        dataType,
 			Description: comment,
 			PrimaryKey:  isPrimaryKey,
+			Nullable:    isNullable == "YES",
+			Default:     columnDefault.String,
+			MaxLength:   int(maxLength.Int64),
+			Precision:   int(precision.Int64),
+			Scale:       int(scale.Int64),
 		}
 
 		columns = append(columns, column)
@@ -342,11 +724,79 @@ func (c *SnowflakeConnector) getTableColumns(ctx context.Context, tableName stri
 	return columns, nil
 }
 
+// markForeignKeys flags the columns referencing other tables of the schema
+func (c *SnowflakeConnector) markForeignKeys(ctx context.Context, tableName string, columns []Column) error {
+	rows, err := queryRows(ctx, c.db, nil, "SHOW IMPORTED KEYS IN TABLE "+c.Dialect().Table(tableName), nil)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if fmt.Sprint(row["pk_schema_name"]) != c.config.Schema {
+			continue
+		}
+		fkColumn := fmt.Sprint(row["fk_column_name"])
+		for i := range columns {
+			if columns[i].Name == fkColumn {
+				columns[i].ForeignKey = true
+				columns[i].References = fmt.Sprintf("%v.%v", row["pk_table_name"], row["pk_column_name"])
+			}
+		}
+	}
+	return nil
+}
+
+// ListForeignKeys returns the foreign keys between tables of the schema,
+// grouping the columns of composite keys
+func (c *SnowflakeConnector) ListForeignKeys(ctx context.Context) ([]ForeignKey, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	schema := QualifiedName(c.config.Database, c.config.Schema)
+	rows, err := queryRows(ctx, c.db, nil, "SHOW IMPORTED KEYS IN SCHEMA "+schema, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+
+	// Rows list the columns of each key by key_sequence
+	sequence := func(row map[string]interface{}) int {
+		n, _ := strconv.Atoi(fmt.Sprint(row["key_sequence"]))
+		return n
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return sequence(rows[i]) < sequence(rows[j]) })
+	var keys []ForeignKey
+	index := make(map[string]int)
+	for _, row := range rows {
+		if fmt.Sprint(row["pk_schema_name"]) != c.config.Schema {
+			continue
+		}
+		table := fmt.Sprint(row["fk_table_name"])
+		id := table + "." + fmt.Sprint(row["fk_name"])
+		i, ok := index[id]
+		if !ok {
+			i = len(keys)
+			index[id] = i
+			keys = append(keys, ForeignKey{
+				Name:            fmt.Sprint(row["fk_name"]),
+				Table:           table,
+				ReferencedTable: fmt.Sprint(row["pk_table_name"]),
+			})
+		}
+		keys[i].Columns = append(keys[i].Columns, fmt.Sprint(row["fk_column_name"]))
+		keys[i].ReferencedColumns = append(keys[i].ReferencedColumns, fmt.Sprint(row["pk_column_name"]))
+	}
+	return keys, nil
+}
+
+// Dialect returns the Snowflake SQL dialect of the connection
+func (c *SnowflakeConnector) Dialect() Dialect {
+	return SnowflakeDialect{Database: c.config.Database, Schema: c.config.Schema}
+}
+
 // getTableRowCount gets the row count for a table
 func (c *SnowflakeConnector) getTableRowCount(ctx context.Context, tableName string) (int, error) {
-	query := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s"."%s"`, 
-		c.config.Database, c.config.Schema, tableName)
-	
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s`, c.Dialect().Table(tableName))
+
 	var count int
 	err := c.db.GetContext(ctx, &count, query)
 	if err != nil {
@@ -356,21 +806,14 @@ func (c *SnowflakeConnector) getTableRowCount(ctx context.Context, tableName str
 	return count, nil
 }
 
-// getTableSampleData retrieves sample data from a table
+// getTableSampleData retrieves sample rows of a table as configured by the
+// connector's sampling; there are none when sampling is disabled
 func (c *SnowflakeConnector) getTableSampleData(ctx context.Context, tableName string, columns []Column) ([]map[string]interface{}, error) {
-	// Build column list for query
-	var columnNames []string
-	for _, col := range columns {
-		columnNames = append(columnNames, fmt.Sprintf(`"%s"`, col.Name))
+	query := c.samples.query(c.Dialect(), tableName, columns)
+	if query == "" {
+		return nil, nil
 	}
 
-	// Build query to get sample data (limit to 5 rows)
-	query := fmt.Sprintf(`
-		SELECT %s 
-		FROM "%s"."%s"."%s" 
-		LIMIT 5
-	`, strings.Join(columnNames, ", "), c.config.Database, c.config.Schema, tableName)
-
 	// Execute query
 	rows, err := c.db.QueryxContext(ctx, query)
 	if err != nil {
@@ -379,16 +822,7 @@ func (c *SnowflakeConnector) getTableSampleData(ctx context.Context, tableName s
 	defer rows.Close()
 
 	// Process results
-	var result []map[string]interface{}
-	for rows.Next() {
-		row := make(map[string]interface{})
-		if err := rows.MapScan(row); err != nil {
-			return nil, fmt.Errorf("failed to scan sample data row: %w", err)
-		}
-		result = append(result, row)
-	}
-
-	return result, nil
+	return scanRows(rows, c.values)
 }
 
 // createSnowflakeDSN creates a DSN string for Snowflake connection
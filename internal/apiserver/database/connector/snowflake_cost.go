@@ -0,0 +1,241 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCreditBudgetExceeded is returned when the daily credit budget is exhausted
+var ErrCreditBudgetExceeded = errors.New("daily credit budget exceeded")
+
+// creditRefreshInterval bounds how often QUERY_HISTORY is consulted
+const creditRefreshInterval = 5 * time.Minute
+
+// warehouseCreditsPerHour maps Snowflake warehouse sizes to their hourly credit rate
+var warehouseCreditsPerHour = map[string]float64{
+	"X-SMALL":  1,
+	"SMALL":    2,
+	"MEDIUM":   4,
+	"LARGE":    8,
+	"X-LARGE":  16,
+	"2X-LARGE": 32,
+	"3X-LARGE": 64,
+	"4X-LARGE": 128,
+	"5X-LARGE": 256,
+	"6X-LARGE": 512,
+}
+
+// costGuard caches the estimated credit usage of a connection
+type costGuard struct {
+	mu            sync.Mutex
+	date          string
+	credits       float64
+	refreshedAt   time.Time
+	overrideUntil time.Time
+}
+
+// CreditUsage returns the estimated credit usage for the current day
+func (c *SnowflakeConnector) CreditUsage(ctx context.Context) (*CreditUsage, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	if err := c.refreshCreditUsage(ctx, true); err != nil {
+		return nil, err
+	}
+
+	c.cost.mu.Lock()
+	defer c.cost.mu.Unlock()
+	return c.creditUsageLocked(time.Now()), nil
+}
+
+// OverrideBudget allows queries over budget until the given time
+func (c *SnowflakeConnector) OverrideBudget(until time.Time) {
+	c.cost.mu.Lock()
+	defer c.cost.mu.Unlock()
+	c.cost.overrideUntil = until
+}
+
+// ClearBudgetOverride removes an active budget override
+func (c *SnowflakeConnector) ClearBudgetOverride() {
+	c.cost.mu.Lock()
+	defer c.cost.mu.Unlock()
+	c.cost.overrideUntil = time.Time{}
+}
+
+// checkCreditBudget returns ErrCreditBudgetExceeded when today's estimate is over budget
+func (c *SnowflakeConnector) checkCreditBudget(ctx context.Context) error {
+	if c.config.DailyCreditBudget <= 0 {
+		return nil
+	}
+
+	if err := c.refreshCreditUsage(ctx, false); err != nil {
+		// Failing to read the history must not take the gateway down
+		log.Printf("Warning: Failed to refresh Snowflake credit usage: %v", err)
+	}
+
+	c.cost.mu.Lock()
+	defer c.cost.mu.Unlock()
+	if usage := c.creditUsageLocked(time.Now()); usage.Blocked {
+		return fmt.Errorf("%w: %.2f of %.2f credits used", ErrCreditBudgetExceeded, usage.Credits, usage.Budget)
+	}
+	return nil
+}
+
+// creditUsageLocked builds the usage snapshot; the caller must hold c.cost.mu
+func (c *SnowflakeConnector) creditUsageLocked(now time.Time) *CreditUsage {
+	usage := &CreditUsage{
+		Date:        c.cost.date,
+		Credits:     c.cost.credits,
+		Budget:      c.config.DailyCreditBudget,
+		RefreshedAt: c.cost.refreshedAt,
+	}
+	if now.Before(c.cost.overrideUntil) {
+		usage.OverrideUntil = c.cost.overrideUntil
+	}
+	usage.Blocked = usage.Budget > 0 && usage.Credits >= usage.Budget && usage.OverrideUntil.IsZero()
+	return usage
+}
+
+// refreshCreditUsage re-reads QUERY_HISTORY when the cached estimate is stale or from another day
+func (c *SnowflakeConnector) refreshCreditUsage(ctx context.Context, force bool) error {
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+
+	c.cost.mu.Lock()
+	fresh := c.cost.date == today && now.Sub(c.cost.refreshedAt) < creditRefreshInterval
+	c.cost.mu.Unlock()
+	if fresh && !force {
+		return nil
+	}
+
+	credits, err := c.estimateCreditsSince(ctx, now.Truncate(24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	c.cost.mu.Lock()
+	c.cost.date = today
+	c.cost.credits = credits
+	c.cost.refreshedAt = now
+	c.cost.mu.Unlock()
+	return nil
+}
+
+// estimateCreditsSince estimates credits consumed by this connection's user and warehouse
+func (c *SnowflakeConnector) estimateCreditsSince(ctx context.Context, since time.Time) (float64, error) {
+	query := `
+		SELECT
+			COALESCE(warehouse_size, '') AS warehouse_size,
+			COALESCE(SUM(execution_time), 0) AS execution_ms,
+			COALESCE(SUM(credits_used_cloud_services), 0) AS cloud_credits
+		FROM
+			TABLE(information_schema.query_history_by_user(
+				USER_NAME => ?,
+				END_TIME_RANGE_START => ?,
+				RESULT_LIMIT => 10000))
+		WHERE
+			warehouse_name = ?
+		GROUP BY
+			warehouse_size
+	`
+
+	rows, err := c.db.QueryxContext(ctx, query, strings.ToUpper(c.config.Username), since, strings.ToUpper(c.config.Warehouse))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query credit usage: %w", err)
+	}
+	defer rows.Close()
+
+	var total float64
+	for rows.Next() {
+		var size string
+		var executionMs, cloudCredits float64
+		if err := rows.Scan(&size, &executionMs, &cloudCredits); err != nil {
+			return 0, fmt.Errorf("failed to scan credit usage row: %w", err)
+		}
+
+		rate, ok := warehouseCreditsPerHour[strings.ToUpper(size)]
+		if !ok {
+			rate = 1
+		}
+		total += executionMs/float64(time.Hour/time.Millisecond)*rate + cloudCredits
+	}
+
+	return total, rows.Err()
+}
+
+// SpendByQueryTag estimates the credits consumed by this connection's user
+// and warehouse between since and until, grouped by query tag
+func (c *SnowflakeConnector) SpendByQueryTag(ctx context.Context, since, until time.Time) ([]TagSpend, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	query := `
+		SELECT
+			COALESCE(query_tag, '') AS query_tag,
+			COALESCE(warehouse_size, '') AS warehouse_size,
+			COUNT(*) AS queries,
+			COALESCE(SUM(execution_time), 0) AS execution_ms,
+			COALESCE(SUM(credits_used_cloud_services), 0) AS cloud_credits,
+			COALESCE(SUM(bytes_scanned), 0) AS bytes_scanned
+		FROM
+			TABLE(information_schema.query_history_by_user(
+				USER_NAME => ?,
+				END_TIME_RANGE_START => ?,
+				END_TIME_RANGE_END => ?,
+				RESULT_LIMIT => 10000))
+		WHERE
+			warehouse_name = ?
+		GROUP BY
+			query_tag, warehouse_size
+	`
+
+	rows, err := c.db.QueryxContext(ctx, query, strings.ToUpper(c.config.Username), since, until, strings.ToUpper(c.config.Warehouse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spend by query tag: %w", err)
+	}
+	defer rows.Close()
+
+	byTag := make(map[string]*TagSpend)
+	var tags []string
+	for rows.Next() {
+		var tag, size string
+		var queries int
+		var executionMs, cloudCredits float64
+		var bytesScanned int64
+		if err := rows.Scan(&tag, &size, &queries, &executionMs, &cloudCredits, &bytesScanned); err != nil {
+			return nil, fmt.Errorf("failed to scan spend row: %w", err)
+		}
+
+		rate, ok := warehouseCreditsPerHour[strings.ToUpper(size)]
+		if !ok {
+			rate = 1
+		}
+
+		spend, ok := byTag[tag]
+		if !ok {
+			spend = &TagSpend{QueryTag: tag}
+			byTag[tag] = spend
+			tags = append(tags, tag)
+		}
+		spend.Queries += queries
+		spend.ExecutionMs += executionMs
+		spend.Credits += executionMs/float64(time.Hour/time.Millisecond)*rate + cloudCredits
+		spend.BytesScanned += bytesScanned
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]TagSpend, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, *byTag[tag])
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditBudget(t *testing.T) {
+	// A fresh estimate of today's usage keeps QUERY_HISTORY from being read
+	now := time.Now().UTC()
+	c := &SnowflakeConnector{
+		db:     sqlx.NewDb(&sql.DB{}, "snowflake"),
+		config: &SnowflakeConfig{DailyCreditBudget: 10},
+		cost:   costGuard{date: now.Format("2006-01-02"), credits: 12.5, refreshedAt: now},
+	}
+	ctx := context.Background()
+
+	// Over budget, queries are rejected before they reach the warehouse
+	_, err := c.ExecuteQuery(ctx, "SELECT 1", nil)
+	assert.ErrorIs(t, err, ErrCreditBudgetExceeded)
+	assert.ErrorContains(t, err, "12.50 of 10.00 credits used")
+	c.cost.mu.Lock()
+	usage := c.creditUsageLocked(time.Now())
+	c.cost.mu.Unlock()
+	assert.True(t, usage.Blocked)
+	assert.True(t, usage.OverrideUntil.IsZero())
+
+	// An override lets them through until it expires
+	until := time.Now().Add(time.Hour)
+	c.OverrideBudget(until)
+	assert.NoError(t, c.checkCreditBudget(ctx))
+	c.cost.mu.Lock()
+	usage = c.creditUsageLocked(time.Now())
+	assert.False(t, usage.Blocked)
+	assert.Equal(t, until, usage.OverrideUntil)
+	assert.True(t, c.creditUsageLocked(until.Add(time.Second)).Blocked, "expired overrides must not apply")
+	c.cost.mu.Unlock()
+
+	c.OverrideBudget(time.Now().Add(-time.Second))
+	assert.ErrorIs(t, c.checkCreditBudget(ctx), ErrCreditBudgetExceeded)
+
+	// Clearing the override restores the limit
+	c.OverrideBudget(until)
+	require.NoError(t, c.checkCreditBudget(ctx))
+	c.ClearBudgetOverride()
+	assert.ErrorIs(t, c.checkCreditBudget(ctx), ErrCreditBudgetExceeded)
+
+	// Under budget, or without one, queries run
+	c.cost.credits = 9
+	assert.NoError(t, c.checkCreditBudget(ctx))
+	c.cost.credits, c.config.DailyCreditBudget = 12.5, 0
+	assert.NoError(t, c.checkCreditBudget(ctx))
+}
@@ -0,0 +1,38 @@
+package connector
+
+import (
+	"errors"
+	"strings"
+
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+// Snowflake error numbers classified by snowflakeErrorCode
+const (
+	sfErrQueryCancelled   = 604
+	sfErrStatementTimeout = 630
+	sfErrObjectNotFound   = 2003
+)
+
+// snowflakeErrorCode classifies Snowflake errors by their error number and
+// SQL state
+func snowflakeErrorCode(err error) string {
+	var sfErr *sf.SnowflakeError
+	if !errors.As(err, &sfErr) {
+		return ""
+	}
+	switch {
+	case sfErr.Number == sfErrQueryCancelled:
+		return CodeQueryCancelled
+	case sfErr.Number == sfErrStatementTimeout:
+		return CodeQueryTimeout
+	case sfErr.Number == sfErrObjectNotFound, sfErr.SQLState == "42S02":
+		return CodeTableNotFound
+	// 390xxx are the errors of the login and token endpoints
+	case sfErr.Number >= 390000 && sfErr.Number < 391000, strings.HasPrefix(sfErr.SQLState, "28"):
+		return CodeAuthFailed
+	case strings.HasPrefix(sfErr.SQLState, "42"):
+		return CodeInvalidQuery
+	}
+	return ""
+}
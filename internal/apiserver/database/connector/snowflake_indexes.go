@@ -0,0 +1,133 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// getTableIndexes returns the clustering key of a table and, for hybrid
+// tables, their indexes
+func (c *SnowflakeConnector) getTableIndexes(ctx context.Context, tableName string) ([]Index, error) {
+	var clusteringKey sql.NullString
+	query := `
+		SELECT clustering_key
+		FROM information_schema.tables
+		WHERE table_name = ?
+		AND table_schema = ?
+		AND table_catalog = ?
+	`
+	if err := c.db.GetContext(ctx, &clusteringKey, query, tableName, c.config.Schema, c.config.Database); err != nil {
+		return nil, fmt.Errorf("failed to get clustering key: %w", err)
+	}
+
+	var indexes []Index
+	if columns := clusteringColumns(clusteringKey.String); len(columns) > 0 {
+		indexes = append(indexes, Index{Name: "CLUSTERING KEY", Type: IndexTypeClustering, Columns: columns})
+	}
+
+	// Only hybrid tables have indexes, and SHOW INDEXES fails for other
+	// tables on some accounts, so a failure means there are none
+	rows, err := queryRows(ctx, c.db, nil, "SHOW INDEXES IN TABLE "+c.Dialect().Table(tableName), nil)
+	if err != nil {
+		return indexes, nil
+	}
+	for _, row := range rows {
+		indexes = append(indexes, Index{
+			Name:    fmt.Sprint(row["name"]),
+			Columns: splitList(strings.Trim(fmt.Sprint(row["columns"]), "[]")),
+			Unique:  fmt.Sprint(row["is_unique"]) == "Y",
+		})
+	}
+	return indexes, nil
+}
+
+// getTableConstraints returns the primary and unique keys of a table.
+// Snowflake has no check constraints.
+func (c *SnowflakeConnector) getTableConstraints(ctx context.Context, tableName string) ([]Constraint, error) {
+	var constraints []Constraint
+	for _, kind := range []struct{ show, typ string }{
+		{"SHOW PRIMARY KEYS IN TABLE ", ConstraintPrimaryKey},
+		{"SHOW UNIQUE KEYS IN TABLE ", ConstraintUnique},
+	} {
+		rows, err := queryRows(ctx, c.db, nil, kind.show+c.Dialect().Table(tableName), nil)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, keyConstraints(kind.typ, rows)...)
+	}
+	return constraints, nil
+}
+
+// keyConstraints groups the rows of SHOW PRIMARY KEYS or SHOW UNIQUE KEYS,
+// one per key column, into constraints with their columns in key order
+func keyConstraints(typ string, rows []map[string]interface{}) []Constraint {
+	type keyColumn struct {
+		name     string
+		sequence int
+	}
+	var names []string
+	columns := make(map[string][]keyColumn)
+	for _, row := range rows {
+		name := fmt.Sprint(row["constraint_name"])
+		if _, ok := columns[name]; !ok {
+			names = append(names, name)
+		}
+		sequence, _ := strconv.Atoi(fmt.Sprint(row["key_sequence"]))
+		columns[name] = append(columns[name], keyColumn{name: fmt.Sprint(row["column_name"]), sequence: sequence})
+	}
+
+	constraints := make([]Constraint, 0, len(names))
+	for _, name := range names {
+		keyColumns := columns[name]
+		sort.SliceStable(keyColumns, func(i, j int) bool { return keyColumns[i].sequence < keyColumns[j].sequence })
+		constraint := Constraint{Name: name, Type: typ}
+		for _, col := range keyColumns {
+			constraint.Columns = append(constraint.Columns, col.name)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
+}
+
+// clusteringColumns returns the expressions of a clustering key reported
+// like LINEAR(A, TO_DATE(B))
+func clusteringColumns(key string) []string {
+	key = strings.TrimSpace(key)
+	if open := strings.IndexByte(key, '('); open >= 0 && strings.HasSuffix(key, ")") {
+		key = key[open+1 : len(key)-1]
+	}
+	return splitList(key)
+}
+
+// splitList splits a comma-separated list of SQL expressions, keeping
+// commas inside parentheses
+func splitList(list string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range list {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	items = append(items, list[start:])
+
+	trimmed := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,24 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusteringColumns(t *testing.T) {
+	assert.Equal(t, []string{"REGION", "TO_DATE(CREATED_AT, 'YYYY-MM-DD')"}, clusteringColumns("LINEAR(REGION, TO_DATE(CREATED_AT, 'YYYY-MM-DD'))"))
+	assert.Empty(t, clusteringColumns(""))
+}
+
+func TestKeyConstraints(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"constraint_name": "UQ_LINE", "column_name": "LINE", "key_sequence": int64(2)},
+		{"constraint_name": "UQ_LINE", "column_name": "ORDER_ID", "key_sequence": int64(1)},
+		{"constraint_name": "UQ_SKU", "column_name": "SKU", "key_sequence": "1"},
+	}
+	assert.Equal(t, []Constraint{
+		{Name: "UQ_LINE", Type: ConstraintUnique, Columns: []string{"ORDER_ID", "LINE"}},
+		{Name: "UQ_SKU", Type: ConstraintUnique, Columns: []string{"SKU"}},
+	}, keyConstraints(ConstraintUnique, rows))
+}
@@ -0,0 +1,78 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+// stageNull marks NULL values in the files loaded through a stage
+const stageNull = `\N`
+
+// LoadRows loads rows into a table by uploading them as a CSV file to the
+// table's stage with PUT and copying it in with COPY INTO, which is far
+// faster than inserts for large loads. The file is purged once loaded.
+func (c *SnowflakeConnector) LoadRows(ctx context.Context, table string, columns []string, rows [][]interface{}) (int64, error) {
+	if c.db == nil {
+		return 0, fmt.Errorf("not connected to database")
+	}
+	if err := c.checkCreditBudget(ctx); err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return 0, err
+	}
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, v := range row {
+			record[i] = stageNull
+			if v != nil {
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return 0, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return 0, err
+	}
+
+	d := c.Dialect().(SnowflakeDialect)
+	stage := fmt.Sprintf("@%s.%s.%%%s", QuoteIdentifier(d.Database), QuoteIdentifier(d.Schema), QuoteIdentifier(table))
+	file := fmt.Sprintf("import_%d.csv", time.Now().UnixNano())
+
+	// The driver uploads the stream in place of the named local file
+	put := fmt.Sprintf("PUT 'file://%s' %s AUTO_COMPRESS = TRUE", file, stage)
+	if _, err := c.db.ExecContext(sf.WithFileStream(ctx, &buf), put); err != nil {
+		return 0, fmt.Errorf("failed to stage rows: %w", err)
+	}
+
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = QuoteIdentifier(col)
+	}
+	copyInto := fmt.Sprintf("COPY INTO %s (%s) FROM %s FILES = ('%s.gz') "+
+		"FILE_FORMAT = (TYPE = CSV SKIP_HEADER = 1 FIELD_OPTIONALLY_ENCLOSED_BY = '\"' NULL_IF = ('\\\\N') EMPTY_FIELD_AS_NULL = FALSE) PURGE = TRUE",
+		d.Table(table), strings.Join(quoted, ", "), stage, file)
+	result, err := queryResult(ctx, c.db, c.values, copyInto, map[string]interface{}{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load staged rows: %w", err)
+	}
+
+	// COPY INTO a table reports a row per file with the rows loaded
+	var loaded int64
+	for _, row := range result.Rows {
+		loaded += unloadCount(row["rows_loaded"])
+	}
+	return loaded, nil
+}
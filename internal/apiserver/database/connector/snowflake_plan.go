@@ -0,0 +1,58 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// snowflakePlan is the output of EXPLAIN USING JSON
+type snowflakePlan struct {
+	GlobalStats struct {
+		PartitionsTotal    int64 `json:"partitionsTotal"`
+		PartitionsAssigned int64 `json:"partitionsAssigned"`
+		BytesAssigned      int64 `json:"bytesAssigned"`
+	} `json:"GlobalStats"`
+	Operations [][]PlanOperation `json:"Operations"`
+}
+
+// ExplainQuery compiles a query with EXPLAIN, which uses no warehouse
+// credits, and returns its plan
+func (c *SnowflakeConnector) ExplainQuery(ctx context.Context, query string, params map[string]interface{}) (*QueryPlan, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, err := queryRows(ctx, c.db, nil, "EXPLAIN USING JSON "+query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("failed to explain query: no plan returned")
+	}
+
+	// The plan is the single column of the single row
+	var content string
+	for _, v := range rows[0] {
+		content = fmt.Sprint(v)
+	}
+	return parseSnowflakePlan(content)
+}
+
+// parseSnowflakePlan converts the JSON plan returned by EXPLAIN
+func parseSnowflakePlan(content string) (*QueryPlan, error) {
+	var plan snowflakePlan
+	if err := json.Unmarshal([]byte(content), &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+
+	result := &QueryPlan{
+		PartitionsTotal:    plan.GlobalStats.PartitionsTotal,
+		PartitionsAssigned: plan.GlobalStats.PartitionsAssigned,
+		BytesAssigned:      plan.GlobalStats.BytesAssigned,
+	}
+	for _, ops := range plan.Operations {
+		result.Operations = append(result.Operations, ops...)
+	}
+	return result, nil
+}
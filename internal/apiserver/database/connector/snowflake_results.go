@@ -0,0 +1,97 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+// snowflakeResultRetention is how long Snowflake keeps the result of a
+// query for retrieval by its query ID
+const snowflakeResultRetention = 24 * time.Hour
+
+// ExecuteQueryPage runs a query and reads its first rows, keeping the
+// Snowflake query ID its persisted result is fetched by
+func (c *SnowflakeConnector) ExecuteQueryPage(ctx context.Context, query string, params map[string]interface{}, limit int) (*ResultPage, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	if err := c.checkCreditBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	query, args, err := bindNamed(query, params)
+	if err != nil {
+		return nil, err
+	}
+	if tag := QueryTagFromContext(ctx); tag != "" {
+		ctx = sf.WithQueryTag(ctx, tag)
+	}
+
+	// The driver sends the query ID once Snowflake accepts the query, so it
+	// is reported for cancellation while the query runs and known when it
+	// returns
+	ids := make(chan string, 1)
+	found := make(chan string, 1)
+	done := make(chan struct{})
+	defer close(done)
+	report := QueryIDReporterFromContext(ctx)
+	go func() {
+		select {
+		case id := <-ids:
+			if report != nil && id != "" {
+				report(id)
+			}
+			found <- id
+		case <-done:
+		}
+	}()
+
+	rows, err := c.db.QueryxContext(sf.WithQueryIDChan(ctx, ids), c.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var resultID string
+	select {
+	case resultID = <-found:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if resultID == "" {
+		return nil, fmt.Errorf("failed to execute query: no query ID was returned")
+	}
+
+	result, more, err := scanResultPage(rows, c.values, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultPage{ResultSet: result, ResultID: resultID, More: more}, nil
+}
+
+// FetchResultPage reads rows of a persisted query result by its query ID.
+// The result is downloaded again up to the page, but the query is not run
+// again.
+func (c *SnowflakeConnector) FetchResultPage(ctx context.Context, resultID string, offset, limit int) (*ResultPage, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	rows, err := c.db.QueryxContext(sf.WithFetchResultByID(ctx, resultID), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch result %s: %w", resultID, err)
+	}
+	defer rows.Close()
+
+	result, more, err := scanResultPage(rows, c.values, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultPage{ResultSet: result, ResultID: resultID, More: more}, nil
+}
+
+// ResultRetention is how long Snowflake keeps query results
+func (c *SnowflakeConnector) ResultRetention() time.Duration {
+	return snowflakeResultRetention
+}
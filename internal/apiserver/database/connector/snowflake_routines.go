@@ -0,0 +1,71 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ListRoutines returns the stored procedures and user-defined functions of
+// the schema
+func (c *SnowflakeConnector) ListRoutines(ctx context.Context) ([]Routine, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var routines []Routine
+	for _, kind := range []struct{ kind, view, name string }{
+		{RoutineProcedure, "information_schema.procedures", "procedure"},
+		{RoutineFunction, "information_schema.functions", "function"},
+	} {
+		query := fmt.Sprintf(`
+			SELECT %[1]s_name, argument_signature, data_type, comment
+			FROM %[2]s
+			WHERE %[1]s_schema = ?
+			AND %[1]s_catalog = ?
+		`, kind.name, kind.view)
+		rows, err := c.db.QueryxContext(ctx, query, c.config.Schema, c.config.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %ss: %w", kind.kind, err)
+		}
+		for rows.Next() {
+			var name, signature, returnType string
+			var comment sql.NullString
+			if err := rows.Scan(&name, &signature, &returnType, &comment); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s row: %w", kind.kind, err)
+			}
+			routines = append(routines, Routine{
+				Name:        name,
+				Kind:        kind.kind,
+				Arguments:   parseArgumentSignature(signature),
+				ReturnType:  returnType,
+				Description: comment.String,
+			})
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %ss: %w", kind.kind, err)
+		}
+	}
+
+	sort.SliceStable(routines, func(i, j int) bool { return routines[i].Name < routines[j].Name })
+	return routines, nil
+}
+
+// parseArgumentSignature parses the argument signature Snowflake reports
+// for a routine, like (ID NUMBER, AMOUNT NUMBER(10,2))
+func parseArgumentSignature(signature string) []RoutineArgument {
+	signature = strings.TrimSpace(signature)
+	signature = strings.TrimSuffix(strings.TrimPrefix(signature, "("), ")")
+
+	args := []RoutineArgument{}
+	for _, item := range splitList(signature) {
+		name, typ, _ := strings.Cut(item, " ")
+		args = append(args, RoutineArgument{Name: name, Type: strings.TrimSpace(typ)})
+	}
+	return args
+}
@@ -0,0 +1,97 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metadataStatsTypes are the types whose counts and ranges Snowflake
+// answers from micro-partition metadata
+var metadataStatsTypes = []string{"NUMBER", "DECIMAL", "NUMERIC", "INT", "BIGINT", "SMALLINT", "FLOAT", "DOUBLE", "REAL", "DATE", "TIME", "TIMESTAMP"}
+
+// hasMetadataStats reports whether Snowflake keeps the range of a column's
+// values in its micro-partition metadata
+func hasMetadataStats(col Column) bool {
+	t := strings.ToUpper(col.Type)
+	for _, prefix := range metadataStatsTypes {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// statsQuery renders the query Snowflake answers from micro-partition
+// metadata: the non-null count, minimum and maximum of each column
+func statsQuery(d Dialect, table string, columns []Column) string {
+	selects := make([]string, 0, 3*len(columns))
+	for i, col := range columns {
+		name := d.QuoteIdentifier(col.Name)
+		selects = append(selects,
+			fmt.Sprintf("COUNT(%s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("NONNULL_%d", i))),
+			fmt.Sprintf("MIN(%s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("MIN_%d", i))),
+			fmt.Sprintf("MAX(%s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("MAX_%d", i))))
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), d.Table(table))
+}
+
+// TableStatistics reads a table's row count and last change from
+// information_schema, and the null fractions and ranges of its numeric and
+// temporal columns from micro-partition metadata, which Snowflake serves
+// without scanning the table. Other columns are left out.
+func (c *SnowflakeConnector) TableStatistics(ctx context.Context, table string, columns []Column) (*TableStats, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	var rowCount sql.NullInt64
+	var lastAltered time.Time
+	query := `
+		SELECT row_count, last_altered
+		FROM information_schema.tables
+		WHERE table_name = ?
+		AND table_schema = ?
+		AND table_catalog = ?
+	`
+	err := c.db.QueryRowxContext(ctx, query, table, c.config.Schema, c.config.Database).Scan(&rowCount, &lastAltered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table statistics: %w", err)
+	}
+	stats := &TableStats{RowCount: rowCount.Int64, AsOf: lastAltered.UTC(), Columns: []ColumnStats{}}
+
+	var covered []Column
+	for _, col := range columns {
+		if hasMetadataStats(col) {
+			covered = append(covered, col)
+		}
+	}
+	if len(covered) == 0 {
+		return stats, nil
+	}
+	rows, err := queryRows(ctx, c.db, c.values, statsQuery(c.Dialect(), table, covered), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column statistics: %w", err)
+	}
+	if len(rows) != 1 {
+		return nil, fmt.Errorf("failed to get column statistics: expected one row, got %d", len(rows))
+	}
+	for i, col := range covered {
+		col := ColumnStats{
+			Column: col.Name,
+			Source: StatsSourceStatistics,
+			Min:    rows[0][fmt.Sprintf("MIN_%d", i)],
+			Max:    rows[0][fmt.Sprintf("MAX_%d", i)],
+		}
+		if stats.RowCount > 0 {
+			nonNull, _ := strconv.ParseFloat(fmt.Sprint(rows[0][fmt.Sprintf("NONNULL_%d", i)]), 64)
+			fraction := 1 - nonNull/float64(stats.RowCount)
+			col.NullFraction = &fraction
+		}
+		stats.Columns = append(stats.Columns, col)
+	}
+	return stats, nil
+}
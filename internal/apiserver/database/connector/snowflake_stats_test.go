@@ -0,0 +1,20 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsQuery(t *testing.T) {
+	assert.True(t, hasMetadataStats(Column{Type: "NUMBER"}))
+	assert.True(t, hasMetadataStats(Column{Type: "TIMESTAMP_NTZ"}))
+	assert.False(t, hasMetadataStats(Column{Type: "TEXT"}))
+	assert.False(t, IsOrderable(Column{Type: "VARIANT"}))
+	assert.True(t, IsOrderable(Column{Type: "TEXT"}))
+
+	sf := SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+	assert.Equal(t, `SELECT COUNT("TOTAL") AS "NONNULL_0", MIN("TOTAL") AS "MIN_0", MAX("TOTAL") AS "MAX_0" FROM "DB"."PUBLIC"."ORDERS"`,
+		statsQuery(sf, "ORDERS", []Column{{Name: "TOTAL", Type: "NUMBER"}}))
+	assert.Equal(t, "SAMPLE ROW (100 ROWS)", sf.SampleRows(100))
+}
@@ -0,0 +1,114 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Metadata columns Snowflake adds to the rows of a stream
+const (
+	streamActionColumn   = "METADATA$ACTION"
+	streamIsUpdateColumn = "METADATA$ISUPDATE"
+	streamMetadataPrefix = "METADATA$"
+)
+
+// streamSink names the empty table a stream is consumed into. Snowflake
+// only moves a stream's offset when a committed DML statement reads it, so
+// consuming inserts nothing into the sink but advances the stream.
+func streamSink(stream string) string {
+	return stream + "_CONSUMED"
+}
+
+// CreateChangeStream creates a standard stream on a table and the sink it
+// is consumed into, unless they exist
+func (c *SnowflakeConnector) CreateChangeStream(ctx context.Context, table, stream string) error {
+	if c.db == nil {
+		return fmt.Errorf("not connected to database")
+	}
+	d := c.Dialect()
+	statements := []string{
+		fmt.Sprintf("CREATE STREAM IF NOT EXISTS %s ON TABLE %s", d.Table(stream), d.Table(table)),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s LIKE %s", d.Table(streamSink(stream)), d.Table(table)),
+	}
+	for _, stmt := range statements {
+		if _, err := c.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create change stream %s: %w", stream, err)
+		}
+	}
+	return nil
+}
+
+// ConsumeChanges reads a stream and advances it in one transaction, so the
+// changes returned are exactly the ones consumed. Updates, which a stream
+// records as a deletion and an insertion, are returned once with the new
+// row.
+func (c *SnowflakeConnector) ConsumeChanges(ctx context.Context, stream string) ([]RowChange, error) {
+	tx, err := c.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if tx != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	d := c.Dialect()
+	rows, err := tx.ExecuteQuery(ctx, "SELECT * FROM "+d.Table(stream), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read change stream %s: %w", stream, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var columns []string
+	for name := range rows[0] {
+		if !strings.HasPrefix(strings.ToUpper(name), streamMetadataPrefix) {
+			columns = append(columns, d.QuoteIdentifier(name))
+		}
+	}
+	sort.Strings(columns)
+	consume := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE 1 = 0",
+		d.Table(streamSink(stream)), strings.Join(columns, ", "), strings.Join(columns, ", "), d.Table(stream))
+	if _, err := tx.ExecuteQuery(ctx, consume, nil); err != nil {
+		return nil, fmt.Errorf("failed to consume change stream %s: %w", stream, err)
+	}
+	err = tx.Commit()
+	tx = nil
+	if err != nil {
+		return nil, err
+	}
+
+	return streamChanges(rows), nil
+}
+
+// streamChanges converts the rows of a stream to changes
+func streamChanges(rows []map[string]interface{}) []RowChange {
+	changes := make([]RowChange, 0, len(rows))
+	for _, row := range rows {
+		action, _ := row[streamActionColumn].(string)
+		isUpdate, _ := row[streamIsUpdateColumn].(bool)
+		change := RowChange{Row: make(map[string]interface{}, len(row))}
+		switch {
+		case strings.EqualFold(action, "INSERT") && isUpdate:
+			change.Action = ChangeUpdate
+		case strings.EqualFold(action, "INSERT"):
+			change.Action = ChangeInsert
+		case isUpdate:
+			// The old row of an update
+			continue
+		default:
+			change.Action = ChangeDelete
+		}
+		for name, v := range row {
+			if !strings.HasPrefix(strings.ToUpper(name), streamMetadataPrefix) {
+				change.Row[name] = v
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
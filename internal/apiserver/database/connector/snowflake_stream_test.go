@@ -0,0 +1,21 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamChanges(t *testing.T) {
+	changes := streamChanges([]map[string]interface{}{
+		{"ID": int64(1), "STATUS": "new", "METADATA$ACTION": "INSERT", "METADATA$ISUPDATE": false, "METADATA$ROW_ID": "a"},
+		{"ID": int64(2), "STATUS": "open", "METADATA$ACTION": "DELETE", "METADATA$ISUPDATE": true, "METADATA$ROW_ID": "b"},
+		{"ID": int64(2), "STATUS": "closed", "METADATA$ACTION": "INSERT", "METADATA$ISUPDATE": true, "METADATA$ROW_ID": "b"},
+		{"ID": int64(3), "STATUS": "open", "METADATA$ACTION": "DELETE", "METADATA$ISUPDATE": false, "METADATA$ROW_ID": "c"},
+	})
+	assert.Equal(t, []RowChange{
+		{Action: ChangeInsert, Row: map[string]interface{}{"ID": int64(1), "STATUS": "new"}},
+		{Action: ChangeUpdate, Row: map[string]interface{}{"ID": int64(2), "STATUS": "closed"}},
+		{Action: ChangeDelete, Row: map[string]interface{}{"ID": int64(3), "STATUS": "open"}},
+	}, changes)
+}
@@ -0,0 +1,106 @@
+package connector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazyIdleTimeout(t *testing.T) {
+	eager, err := lazyIdleTimeout(&SnowflakeConfig{})
+	require.NoError(t, err)
+	assert.Zero(t, eager)
+
+	lazy, err := lazyIdleTimeout(&SnowflakeConfig{Lazy: true})
+	require.NoError(t, err)
+	assert.Equal(t, defaultIdleTimeout, lazy)
+
+	lazy, err = lazyIdleTimeout(&SnowflakeConfig{Lazy: true, IdleTimeout: "30s"})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, lazy)
+
+	for _, cfg := range []*SnowflakeConfig{
+		{Lazy: true, IdleTimeout: "later"},
+		{Lazy: true, IdleTimeout: "0s"},
+		{IdleTimeout: "30s"},
+	} {
+		_, err := NewSnowflakeConnector(cfg, nil, nil)
+		assert.Error(t, err)
+	}
+}
+
+// countingDriver counts the sessions a connector opens and closes
+type countingDriver struct {
+	opened, closed atomic.Int32
+}
+
+func (d *countingDriver) Open(string) (driver.Conn, error) {
+	d.opened.Add(1)
+	return &countingConn{driver: d}, nil
+}
+
+type countingConn struct {
+	driver *countingDriver
+}
+
+func (c *countingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *countingConn) Close() error {
+	c.driver.closed.Add(1)
+	return nil
+}
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *countingConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return emptyRows{}, nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string         { return []string{"N"} }
+func (emptyRows) Close() error              { return nil }
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
+
+func TestLazyConnect(t *testing.T) {
+	d := &countingDriver{}
+	sql.Register("snowflake-lazy-test", d)
+	defer func(name string) { snowflakeDriver = name }(snowflakeDriver)
+	snowflakeDriver = "snowflake-lazy-test"
+
+	c, err := NewSnowflakeConnector(&SnowflakeConfig{
+		Account:     "acme",
+		Username:    "gateway",
+		Password:    "secret",
+		AuthType:    "password",
+		Lazy:        true,
+		IdleTimeout: "10ms",
+	}, nil, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, c.Connect(ctx))
+	defer c.Disconnect(ctx)
+
+	// Connecting opens no session; the first query opens one
+	assert.Zero(t, d.opened.Load())
+	_, err = c.ExecuteQuery(ctx, "SELECT 1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), d.opened.Load())
+
+	// The idle session is closed once the idle timeout passes
+	require.Eventually(t, func() bool {
+		return d.closed.Load() == 1
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -0,0 +1,74 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+// ErrSavepointsUnsupported is returned by transactions on databases without
+// savepoints
+var ErrSavepointsUnsupported = errors.New("savepoints are not supported by this database")
+
+// snowflakeTransaction is a transaction on one pooled Snowflake connection
+type snowflakeTransaction struct {
+	c  *SnowflakeConnector
+	tx *sqlx.Tx
+}
+
+// BeginTransaction starts a transaction on a dedicated connection
+func (c *SnowflakeConnector) BeginTransaction(ctx context.Context) (Transaction, error) {
+	if c.db == nil {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	if err := c.checkCreditBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	if tag := QueryTagFromContext(ctx); tag != "" {
+		ctx = sf.WithQueryTag(ctx, tag)
+	}
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &snowflakeTransaction{c: c, tx: tx}, nil
+}
+
+// ExecuteQuery runs a statement within the transaction
+func (t *snowflakeTransaction) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if err := t.c.checkCreditBudget(ctx); err != nil {
+		return nil, err
+	}
+	return queryRows(ctx, t.tx, t.c.values, query, params)
+}
+
+// Savepoint is not available: Snowflake transactions have no savepoints
+func (t *snowflakeTransaction) Savepoint(context.Context, string) error {
+	return ErrSavepointsUnsupported
+}
+
+// RollbackToSavepoint is not available: Snowflake transactions have no savepoints
+func (t *snowflakeTransaction) RollbackToSavepoint(context.Context, string) error {
+	return ErrSavepointsUnsupported
+}
+
+// Commit commits the transaction
+func (t *snowflakeTransaction) Commit() error {
+	if err := t.tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rollback rolls the transaction back
+func (t *snowflakeTransaction) Rollback() error {
+	if err := t.tx.Rollback(); err != nil {
+		return fmt.Errorf("failed to roll back transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+package connector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StatementKind classifies SQL statements by their effect
+type StatementKind string
+
+// Statement kinds
+const (
+	StatementRead    StatementKind = "read"
+	StatementWrite   StatementKind = "write"
+	StatementDDL     StatementKind = "ddl"
+	StatementSession StatementKind = "session"
+	StatementOther   StatementKind = "other"
+)
+
+// statementKinds classifies statements by their leading keyword
+var statementKinds = map[string]StatementKind{
+	"SELECT": StatementRead, "WITH": StatementRead, "VALUES": StatementRead, "TABLE": StatementRead,
+	"SHOW": StatementRead, "DESCRIBE": StatementRead, "DESC": StatementRead, "EXPLAIN": StatementRead,
+
+	"INSERT": StatementWrite, "UPDATE": StatementWrite, "DELETE": StatementWrite, "MERGE": StatementWrite,
+	"UPSERT": StatementWrite, "REPLACE": StatementWrite, "COPY": StatementWrite, "PUT": StatementWrite,
+	"GET": StatementWrite, "REMOVE": StatementWrite, "UNLOAD": StatementWrite, "LOAD": StatementWrite,
+	"CALL": StatementWrite, "EXEC": StatementWrite, "EXECUTE": StatementWrite, "DO": StatementWrite,
+
+	"CREATE": StatementDDL, "ALTER": StatementDDL, "DROP": StatementDDL, "TRUNCATE": StatementDDL,
+	"RENAME": StatementDDL, "COMMENT": StatementDDL, "GRANT": StatementDDL, "REVOKE": StatementDDL,
+	"UNDROP": StatementDDL,
+
+	"USE": StatementSession, "SET": StatementSession, "UNSET": StatementSession, "RESET": StatementSession,
+	"BEGIN": StatementSession, "START": StatementSession, "COMMIT": StatementSession,
+	"ROLLBACK": StatementSession, "SAVEPOINT": StatementSession, "RELEASE": StatementSession,
+}
+
+// SQLSyntax holds the lexical rules that differ between databases. They
+// decide where literals and comments end, and so where statements end.
+type SQLSyntax struct {
+	// BackslashEscapes escape characters in string literals with
+	// backslashes, as MySQL and Snowflake do
+	BackslashEscapes bool
+
+	// DollarQuotes delimit string literals with $$ or $tag$
+	DollarQuotes bool
+
+	// EscapeStrings are E'...' literals with backslash escapes
+	EscapeStrings bool
+
+	// NestedComments nest /* */ comments
+	NestedComments bool
+
+	// HashComments start comments with #
+	HashComments bool
+
+	// SpacedDashComments start -- comments only when whitespace or a
+	// control character follows, as MySQL does: 1--1 is arithmetic
+	SpacedDashComments bool
+
+	// SlashComments start comments with //, as Snowflake does
+	SlashComments bool
+
+	// BacktickIdentifiers quote identifiers with backticks
+	BacktickIdentifiers bool
+}
+
+// SyntaxFor returns the SQL syntax of a database type
+func SyntaxFor(dbType string) SQLSyntax {
+	switch dbType {
+	case "snowflake":
+		return SQLSyntax{BackslashEscapes: true, DollarQuotes: true, SlashComments: true}
+	case "postgres":
+		return SQLSyntax{DollarQuotes: true, EscapeStrings: true, NestedComments: true}
+	case "mysql":
+		return SQLSyntax{BackslashEscapes: true, HashComments: true, SpacedDashComments: true, BacktickIdentifiers: true}
+	case "sqlite":
+		return SQLSyntax{BacktickIdentifiers: true}
+	}
+	return SQLSyntax{}
+}
+
+// Statement is a statement of a SQL text, as split by ParseStatements
+type Statement struct {
+	// SQL is the statement's text without the separator
+	SQL string
+
+	// Keyword is the statement's leading keyword, upper-cased
+	Keyword string
+
+	// Kind is the effect of the statement; reads that embed writes, like
+	// data-modifying CTEs, are writes
+	Kind StatementKind
+
+	// Comments reports whether the statement contains comments
+	Comments bool
+
+	// Literals are the values of the statement's string literals
+	Literals []string
+}
+
+// sqlToken is a keyword, identifier or punctuation character of a
+// statement; literals and comments yield no tokens. Quoted identifiers are
+// never keywords.
+type sqlToken struct {
+	word   string
+	quoted bool
+	punct  byte
+}
+
+// keyword returns the token's word when it can be a keyword
+func (t sqlToken) keyword() string {
+	if t.quoted {
+		return ""
+	}
+	return t.word
+}
+
+// ParseStatements splits a SQL text into its statements and classifies
+// them, following the literal, identifier and comment rules of a syntax.
+// Unterminated literals and comments are rejected, as are the executable
+// comments of MySQL, which run their content as SQL.
+func ParseStatements(syntax SQLSyntax, sql string) ([]Statement, error) {
+	var statements []Statement
+	var tokens []sqlToken
+	var literals []string
+	start, comments := 0, false
+	flush := func(end int) {
+		if len(tokens) > 0 {
+			st := classifyStatement(strings.TrimSpace(sql[start:end]), tokens, comments)
+			st.Literals = literals
+			statements = append(statements, st)
+		}
+		tokens, literals, comments = nil, nil, false
+		start = end + 1
+	}
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+		case syntax.dashComment(sql, i) || (c == '#' && syntax.HashComments) ||
+			(syntax.SlashComments && strings.HasPrefix(sql[i:], "//")):
+			comments = true
+			if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(sql)
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			if strings.HasPrefix(sql[i:], "/*!") {
+				return nil, fmt.Errorf("executable comments are not allowed")
+			}
+			comments = true
+			end, err := blockCommentEnd(sql, i, syntax.NestedComments)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		case c == '\'':
+			end, err := quotedEnd(sql, i, '\'', syntax.BackslashEscapes)
+			if err != nil {
+				return nil, err
+			}
+			literals = append(literals, unquote(sql[i:end], syntax.BackslashEscapes))
+			i = end
+		case (c == 'E' || c == 'e') && syntax.EscapeStrings && i+1 < len(sql) && sql[i+1] == '\'' && !precededByWord(sql, i):
+			end, err := quotedEnd(sql, i+1, '\'', true)
+			if err != nil {
+				return nil, err
+			}
+			literals = append(literals, unquote(sql[i+1:end], true))
+			i = end
+		case c == '"' || (c == '`' && syntax.BacktickIdentifiers):
+			end, err := quotedEnd(sql, i, c, false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{word: sql[i+1 : end-1], quoted: true})
+			i = end
+		case c == '$' && syntax.DollarQuotes && dollarTag(sql[i:]) != "":
+			tag := dollarTag(sql[i:])
+			j := strings.Index(sql[i+len(tag):], tag)
+			if j < 0 {
+				return nil, fmt.Errorf("unterminated %s string at offset %d", tag, i)
+			}
+			literals = append(literals, sql[i+len(tag):i+len(tag)+j])
+			i += len(tag) + j + len(tag)
+		case c == ';':
+			flush(i)
+			i++
+		case isWordChar(c):
+			j := i
+			for j < len(sql) && isWordChar(sql[j]) {
+				j++
+			}
+			tokens = append(tokens, sqlToken{word: strings.ToUpper(sql[i:j])})
+			i = j
+		default:
+			tokens = append(tokens, sqlToken{punct: c})
+			i++
+		}
+	}
+	flush(len(sql))
+	return statements, nil
+}
+
+// blockCommentEnd returns the offset after the /* */ comment at start
+func blockCommentEnd(sql string, start int, nested bool) (int, error) {
+	depth := 0
+	for i := start; i+1 < len(sql); {
+		switch {
+		case sql[i] == '/' && sql[i+1] == '*':
+			if depth == 0 || nested {
+				depth++
+			}
+			i += 2
+		case sql[i] == '*' && sql[i+1] == '/':
+			if depth--; depth == 0 {
+				return i + 2, nil
+			}
+			i += 2
+		default:
+			i++
+		}
+	}
+	return 0, fmt.Errorf("unterminated comment at offset %d", start)
+}
+
+// quotedEnd returns the offset after the literal or quoted identifier
+// opened by quote at start; doubled quotes and, with backslash escapes,
+// escaped characters do not close it
+func quotedEnd(sql string, start int, quote byte, backslash bool) (int, error) {
+	for i := start + 1; i < len(sql); i++ {
+		switch {
+		case backslash && sql[i] == '\\':
+			i++
+		case sql[i] == quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated %c at offset %d", quote, start)
+}
+
+// unquote returns the value of a quoted string literal
+func unquote(literal string, backslash bool) string {
+	body := literal[1 : len(literal)-1]
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		switch {
+		case backslash && body[i] == '\\' && i+1 < len(body):
+			i++
+			switch body[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			default:
+				b.WriteByte(body[i])
+			}
+		case body[i] == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		default:
+			b.WriteByte(body[i])
+		}
+	}
+	return b.String()
+}
+
+// dollarTag returns the $tag$ opening a dollar-quoted string, or an empty
+// string; $1 and other positional parameters open none
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1]
+		case c >= '0' && c <= '9' && i == 1, !isWordChar(c):
+			return ""
+		}
+	}
+	return ""
+}
+
+// dashComment reports whether a -- comment starts at i
+func (syntax SQLSyntax) dashComment(sql string, i int) bool {
+	if !strings.HasPrefix(sql[i:], "--") {
+		return false
+	}
+	return !syntax.SpacedDashComments || i+2 == len(sql) || sql[i+2] <= ' '
+}
+
+// precededByWord reports whether the character before i continues a word
+func precededByWord(sql string, i int) bool {
+	return i > 0 && isWordChar(sql[i-1])
+}
+
+// embeddedWrites are the writes a read can embed, like the data-modifying
+// CTEs of PostgreSQL, by the word following their keyword; an empty word
+// matches any identifier. Functions named like them, like INSERT(...),
+// are followed by a parenthesis instead.
+var embeddedWrites = map[string]string{"INSERT": "INTO", "DELETE": "FROM", "MERGE": "INTO", "UPDATE": ""}
+
+// classifyStatement classifies a statement by its leading keyword. Reads
+// are escalated to writes by the writes they embed after a parenthesis,
+// by SELECT ... INTO and by EXPLAIN ANALYZE, which runs the statement it
+// explains.
+func classifyStatement(sql string, tokens []sqlToken, comments bool) Statement {
+	st := Statement{SQL: sql, Keyword: tokens[0].keyword(), Comments: comments, Kind: StatementOther}
+	kind, ok := statementKinds[st.Keyword]
+	switch {
+	case !ok:
+		return st
+	case st.Keyword == "ALTER" && len(tokens) > 1 && tokens[1].keyword() == "SESSION":
+		st.Kind = StatementSession
+		return st
+	}
+	st.Kind = kind
+	if kind != StatementRead {
+		return st
+	}
+
+	if st.Keyword == "EXPLAIN" {
+		analyze := false
+		for i, t := range tokens[1:] {
+			if t.keyword() == "ANALYZE" {
+				analyze = true
+			}
+			if _, ok := statementKinds[t.keyword()]; ok && analyze {
+				st.Kind = classifyStatement(sql, tokens[i+1:], comments).Kind
+				break
+			}
+		}
+		return st
+	}
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.punct == '(':
+			depth++
+		case t.punct == ')':
+			depth--
+		case t.keyword() == "INTO" && depth == 0 && st.Keyword == "SELECT":
+			st.Kind = StatementWrite
+		case i > 0 && i+1 < len(tokens) && (tokens[i-1].punct == '(' || tokens[i-1].punct == ')'):
+			next, ok := embeddedWrites[t.keyword()]
+			if ok && tokens[i+1].word != "" && (next == "" || tokens[i+1].keyword() == next) {
+				st.Kind = StatementWrite
+			}
+		}
+	}
+	return st
+}
@@ -0,0 +1,86 @@
+package connector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatements(t *testing.T) {
+	kinds := func(syntax SQLSyntax, sql string) []StatementKind {
+		statements, err := ParseStatements(syntax, sql)
+		require.NoError(t, err, sql)
+		out := make([]StatementKind, len(statements))
+		for i, st := range statements {
+			out[i] = st.Kind
+		}
+		return out
+	}
+	ansi := SQLSyntax{}
+	read, write, ddl := StatementRead, StatementWrite, StatementDDL
+
+	for sql, want := range map[string][]StatementKind{
+		`SELECT * FROM ORDERS WHERE NOTE = 'a; DROP TABLE ORDERS'`:                             {read},
+		`SELECT "DROP" FROM ORDERS; `:                                                          {read},
+		`select 1; drop table orders`:                                                          {read, ddl},
+		`WITH d AS (DELETE FROM ORDERS RETURNING *) SELECT * FROM d`:                           {write},
+		`WITH o AS (SELECT 1) UPDATE ORDERS SET TOTAL = 0`:                                     {write},
+		`SELECT INSERT(NAME, 1, 2, 'x'), REPLACE(NOTE, 'a', 'b') FROM ORDERS`:                  {read},
+		`SELECT * FROM ORDERS FOR UPDATE`:                                                      {read},
+		`SELECT * INTO ARCHIVE FROM ORDERS`:                                                    {write},
+		`EXPLAIN SELECT * FROM ORDERS`:                                                         {read},
+		`EXPLAIN ANALYZE DELETE FROM ORDERS`:                                                   {write},
+		`ALTER SESSION SET QUERY_TAG = 'x'`:                                                    {StatementSession},
+		`USE ROLE ACCOUNTADMIN`:                                                                {StatementSession},
+		`VACUUM ORDERS`:                                                                        {StatementOther},
+		`SELECT 1 -- ; DROP TABLE ORDERS`:                                                      {read},
+		`SELECT 1 /* ; DROP TABLE ORDERS */`:                                                   {read},
+		`MERGE INTO ORDERS t USING (SELECT 1 AS ID) s ON t.ID = s.ID WHEN MATCHED THEN DELETE`: {write},
+	} {
+		assert.Equal(t, want, kinds(ansi, sql), sql)
+	}
+
+	// Where literals end depends on the database
+	sql := `SELECT 'a\'; DROP TABLE ORDERS; -- '`
+	assert.Equal(t, []StatementKind{read, ddl}, kinds(ansi, sql))
+	assert.Equal(t, []StatementKind{read}, kinds(SyntaxFor("mysql"), sql))
+	assert.Equal(t, []StatementKind{read}, kinds(SyntaxFor("postgres"), `SELECT $body$ ; DROP TABLE ORDERS $body$, $1`))
+	assert.Equal(t, []StatementKind{read, ddl}, kinds(SyntaxFor("postgres"), `SELECT E'\'' ; DROP TABLE ORDERS`))
+	assert.Equal(t, []StatementKind{read}, kinds(SyntaxFor("postgres"), `SELECT 1 /* /* nested */ ; DROP TABLE ORDERS */`))
+	assert.Equal(t, []StatementKind{read}, kinds(SyntaxFor("mysql"), "SELECT `DELETE` FROM ORDERS # ; DROP TABLE ORDERS"))
+	assert.Equal(t, []StatementKind{read, ddl}, kinds(SyntaxFor("snowflake"), "SELECT 1 // '\n; DROP TABLE t; --'"))
+
+	// MySQL needs whitespace after --: 1--1 is arithmetic
+	assert.Equal(t, []StatementKind{read}, kinds(ansi, "SELECT 1--1; DROP TABLE ORDERS"))
+	assert.Equal(t, []StatementKind{read, ddl}, kinds(SyntaxFor("mysql"), "SELECT 1--1; DROP TABLE ORDERS"))
+	assert.Equal(t, []StatementKind{read}, kinds(SyntaxFor("mysql"), "SELECT 1 --\t; DROP TABLE ORDERS"))
+	assert.Equal(t, []StatementKind{read}, kinds(SyntaxFor("mysql"), "SELECT 1 --"))
+
+	statements, err := ParseStatements(ansi, "SELECT 1 -- total\n")
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	assert.Equal(t, "SELECT", statements[0].Keyword)
+	assert.True(t, statements[0].Comments)
+
+	statements, err = ParseStatements(SyntaxFor("snowflake"), "// note\nDROP TABLE orders")
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	assert.Equal(t, ddl, statements[0].Kind)
+	assert.True(t, statements[0].Comments)
+
+	statements, err = ParseStatements(SyntaxFor("snowflake"), `SELECT 'it''s', 'a\tb', $$raw$$, "ID" FROM ORDERS`)
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+	assert.Equal(t, []string{"it's", "a\tb", "raw"}, statements[0].Literals)
+
+	for sql, msg := range map[string]string{
+		`SELECT 'open`:                   "unterminated '",
+		`SELECT "open`:                   `unterminated "`,
+		`SELECT 1 /* open`:               "unterminated comment",
+		`SELECT 1 /*! ; DROP TABLE X */`: "executable comments",
+	} {
+		_, err := ParseStatements(SyntaxFor("mysql"), sql)
+		assert.ErrorContains(t, err, msg, sql)
+	}
+}
@@ -0,0 +1,63 @@
+package connector
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Sources of column statistics
+const (
+	// StatsSourceStatistics marks statistics the database maintains
+	StatsSourceStatistics = "statistics"
+
+	// StatsSourceSample marks statistics computed from sampled rows
+	StatsSourceSample = "sample"
+)
+
+// ColumnStats summarizes the values of a column
+type ColumnStats struct {
+	Column string `json:"column"`
+	Source string `json:"source"`
+
+	// NullFraction is the share of null values
+	NullFraction *float64 `json:"null_fraction,omitempty"`
+
+	// Distinct is the number of distinct values; computed from a sample,
+	// it counts the values of the sample
+	Distinct *int64 `json:"distinct,omitempty"`
+
+	Min interface{} `json:"min,omitempty"`
+	Max interface{} `json:"max,omitempty"`
+}
+
+// TableStats are the statistics a database maintains about a table
+type TableStats struct {
+	RowCount int64 `json:"row_count"`
+
+	// AsOf is when the statistics were last current, e.g. when the table
+	// last changed
+	AsOf time.Time `json:"as_of"`
+
+	Columns []ColumnStats `json:"columns"`
+}
+
+// StatisticsProvider is implemented by connectors that can read the
+// statistics the database maintains about a table instead of scanning it
+type StatisticsProvider interface {
+	// TableStatistics returns the statistics of a table; columns the
+	// database keeps no statistics for are left out
+	TableStatistics(ctx context.Context, table string, columns []Column) (*TableStats, error)
+}
+
+// IsOrderable reports whether a column's values can be compared, so that
+// their minimum, maximum and distinct count can be computed
+func IsOrderable(col Column) bool {
+	t := strings.ToUpper(col.Type)
+	for _, prefix := range []string{"VARIANT", "OBJECT", "ARRAY", "GEOGRAPHY", "GEOMETRY", "BINARY", "VARBINARY", "BLOB", "JSON"} {
+		if strings.HasPrefix(t, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package connector
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// transientMessages are error messages of conditions that clear by
+// themselves, matched case-insensitively
+var transientMessages = []string{
+	"connection reset",
+	"broken pipe",
+	"resuming",
+	"service unavailable",
+	"too many requests",
+}
+
+// IsTransient reports whether a query failed on a transient condition, such
+// as a dropped connection or a resuming warehouse, so that running it again
+// may succeed. Cancelled and timed out contexts are not transient.
+func IsTransient(err error) bool {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.EPIPE):
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, transient := range transientMessages {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
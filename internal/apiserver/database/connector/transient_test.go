@@ -0,0 +1,22 @@
+package connector
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(fmt.Errorf("failed to execute query: %w", driver.ErrBadConn)))
+	assert.True(t, IsTransient(errors.New("read tcp 10.0.0.1:443: connection reset by peer")))
+	assert.True(t, IsTransient(errors.New("Warehouse 'WH' is resuming")))
+
+	assert.False(t, IsTransient(nil))
+	assert.False(t, IsTransient(fmt.Errorf("failed to execute query: %w", context.Canceled)))
+	assert.False(t, IsTransient(errors.New("SQL compilation error: invalid identifier 'NAME'")))
+	assert.False(t, IsTransient(errors.New("Warehouse 'WH' cannot be resumed because resource monitor 'RM' has exceeded its quota")))
+}
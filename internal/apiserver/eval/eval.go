@@ -0,0 +1,255 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound is returned when a case or run does not exist
+var ErrNotFound = errors.New("not found")
+
+// Case is a labeled question with the result, or the SQL producing the
+// result, that a correct answer must match
+type Case struct {
+	ID             string                   `json:"id"`
+	Connection     string                   `json:"connection"`
+	Question       string                   `json:"question"`
+	ExpectedSQL    string                   `json:"expected_sql,omitempty"`
+	ExpectedResult []map[string]interface{} `json:"expected_result,omitempty"`
+	Tags           []string                 `json:"tags,omitempty"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
+
+// CaseResult is the outcome of running one case
+type CaseResult struct {
+	CaseID        string `json:"case_id"`
+	Question      string `json:"question"`
+	GeneratedSQL  string `json:"generated_sql,omitempty"`
+	Passed        bool   `json:"passed"`
+	ExactSQLMatch bool   `json:"exact_sql_match"`
+	Error         string `json:"error,omitempty"`
+	DurationMs    int64  `json:"duration_ms"`
+}
+
+// Run is a complete evaluation of a connection's cases
+type Run struct {
+	ID            string       `json:"id"`
+	Connection    string       `json:"connection"`
+	StartedAt     time.Time    `json:"started_at"`
+	FinishedAt    time.Time    `json:"finished_at"`
+	Total         int          `json:"total"`
+	Passed        int          `json:"passed"`
+	Accuracy      float64      `json:"accuracy"`
+	PromptVersion int          `json:"prompt_version"`
+	Results       []CaseResult `json:"results,omitempty"`
+}
+
+// caseRow is the persisted form of a Case
+type caseRow struct {
+	ID             string `gorm:"primaryKey"`
+	Connection     string
+	Question       string
+	ExpectedSQL    string
+	ExpectedResult string
+	Tags           string
+	CreatedAt      time.Time
+}
+
+// TableName overrides the table name used by caseRow
+func (caseRow) TableName() string {
+	return "eval_cases"
+}
+
+// runRow is the persisted form of a Run
+type runRow struct {
+	ID            string `gorm:"primaryKey"`
+	Connection    string
+	StartedAt     time.Time
+	FinishedAt    time.Time
+	Total         int
+	Passed        int
+	Accuracy      float64
+	PromptVersion int
+	Results       string
+}
+
+// TableName overrides the table name used by runRow
+func (runRow) TableName() string {
+	return "eval_runs"
+}
+
+// Store persists eval cases and runs in the gateway's state store
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore creates a store on top of the state store. The eval tables are
+// created by the state store migrations.
+func NewStore(db *gorm.DB) *Store {
+	return &Store{
+		db: db,
+	}
+}
+
+// AddCase stores a new case
+func (s *Store) AddCase(ctx context.Context, c *Case) error {
+	if c.Question == "" {
+		return fmt.Errorf("question is required")
+	}
+	if c.ExpectedSQL == "" && c.ExpectedResult == nil {
+		return fmt.Errorf("expected_sql or expected_result is required")
+	}
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+
+	row := &caseRow{
+		ID:          c.ID,
+		Connection:  c.Connection,
+		Question:    c.Question,
+		ExpectedSQL: c.ExpectedSQL,
+		CreatedAt:   c.CreatedAt,
+	}
+	if c.ExpectedResult != nil {
+		data, err := json.Marshal(c.ExpectedResult)
+		if err != nil {
+			return fmt.Errorf("failed to encode expected result: %w", err)
+		}
+		row.ExpectedResult = string(data)
+	}
+	if len(c.Tags) > 0 {
+		data, _ := json.Marshal(c.Tags)
+		row.Tags = string(data)
+	}
+
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to save eval case: %w", err)
+	}
+	return nil
+}
+
+// ListCases returns the cases of a connection, oldest first
+func (s *Store) ListCases(ctx context.Context, connection string) ([]*Case, error) {
+	var rows []caseRow
+	if err := s.db.WithContext(ctx).Where("connection = ?", connection).Order("created_at").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read eval cases: %w", err)
+	}
+
+	cases := make([]*Case, 0, len(rows))
+	for _, row := range rows {
+		c := &Case{
+			ID:          row.ID,
+			Connection:  row.Connection,
+			Question:    row.Question,
+			ExpectedSQL: row.ExpectedSQL,
+			CreatedAt:   row.CreatedAt,
+		}
+		if row.ExpectedResult != "" {
+			if err := json.Unmarshal([]byte(row.ExpectedResult), &c.ExpectedResult); err != nil {
+				return nil, fmt.Errorf("failed to decode expected result of case %s: %w", row.ID, err)
+			}
+		}
+		if row.Tags != "" {
+			_ = json.Unmarshal([]byte(row.Tags), &c.Tags)
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// DeleteCase removes a case
+func (s *Store) DeleteCase(ctx context.Context, connection, id string) error {
+	result := s.db.WithContext(ctx).Where("connection = ? AND id = ?", connection, id).Delete(&caseRow{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete eval case: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SaveRun stores a completed run
+func (s *Store) SaveRun(ctx context.Context, run *Run) error {
+	data, err := json.Marshal(run.Results)
+	if err != nil {
+		return fmt.Errorf("failed to encode eval results: %w", err)
+	}
+	row := &runRow{
+		ID:            run.ID,
+		Connection:    run.Connection,
+		StartedAt:     run.StartedAt,
+		FinishedAt:    run.FinishedAt,
+		Total:         run.Total,
+		Passed:        run.Passed,
+		Accuracy:      run.Accuracy,
+		PromptVersion: run.PromptVersion,
+		Results:       string(data),
+	}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to save eval run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns returns the runs of a connection, newest first, without their
+// per-case results
+func (s *Store) ListRuns(ctx context.Context, connection string, limit int) ([]*Run, error) {
+	query := s.db.WithContext(ctx).Where("connection = ?", connection).Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var rows []runRow
+	if err := query.Omit("results").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read eval runs: %w", err)
+	}
+
+	runs := make([]*Run, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, row.toRun())
+	}
+	return runs, nil
+}
+
+// GetRun returns a run with its per-case results
+func (s *Store) GetRun(ctx context.Context, connection, id string) (*Run, error) {
+	var row runRow
+	err := s.db.WithContext(ctx).Where("connection = ? AND id = ?", connection, id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read eval run: %w", err)
+	}
+
+	run := row.toRun()
+	if row.Results != "" {
+		if err := json.Unmarshal([]byte(row.Results), &run.Results); err != nil {
+			return nil, fmt.Errorf("failed to decode eval results: %w", err)
+		}
+	}
+	return run, nil
+}
+
+func (row runRow) toRun() *Run {
+	return &Run{
+		ID:            row.ID,
+		Connection:    row.Connection,
+		StartedAt:     row.StartedAt,
+		FinishedAt:    row.FinishedAt,
+		Total:         row.Total,
+		Passed:        row.Passed,
+		Accuracy:      row.Accuracy,
+		PromptVersion: row.PromptVersion,
+	}
+}
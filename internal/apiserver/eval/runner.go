@@ -0,0 +1,202 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pipeline is the NL-to-SQL pipeline under evaluation
+type Pipeline interface {
+	// GenerateSQL translates a question into SQL
+	GenerateSQL(ctx context.Context, question string) (string, error)
+
+	// Execute runs a query and returns its rows
+	Execute(ctx context.Context, query string) ([]map[string]interface{}, error)
+}
+
+// Report is the outcome of a run compared with the previous run
+type Report struct {
+	Run *Run `json:"run"`
+
+	// Baseline is the previous run of the connection, if any
+	Baseline *Run `json:"baseline,omitempty"`
+
+	// AccuracyDelta is the change in accuracy since the baseline
+	AccuracyDelta float64 `json:"accuracy_delta"`
+
+	// Regressions are cases that passed in the baseline and fail now
+	Regressions []CaseResult `json:"regressions,omitempty"`
+
+	// Fixed are cases that failed in the baseline and pass now
+	Fixed []CaseResult `json:"fixed,omitempty"`
+}
+
+// Execute runs every case of a connection through the pipeline, stores the
+// run and compares it with the previous one
+func Execute(ctx context.Context, store *Store, pipeline Pipeline, connection string, promptVersion int) (*Report, error) {
+	cases, err := store.ListCases(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no eval cases for connection %s", connection)
+	}
+
+	var baseline *Run
+	previous, err := store.ListRuns(ctx, connection, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(previous) > 0 {
+		if baseline, err = store.GetRun(ctx, connection, previous[0].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	run := &Run{
+		ID:            uuid.New().String(),
+		Connection:    connection,
+		StartedAt:     time.Now(),
+		Total:         len(cases),
+		PromptVersion: promptVersion,
+	}
+	for _, c := range cases {
+		result := runCase(ctx, pipeline, c)
+		if result.Passed {
+			run.Passed++
+		}
+		run.Results = append(run.Results, result)
+	}
+	run.FinishedAt = time.Now()
+	run.Accuracy = float64(run.Passed) / float64(run.Total)
+
+	if err := store.SaveRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return compare(run, baseline), nil
+}
+
+// runCase evaluates a single case; the generated query passes when its
+// result matches the expected one
+func runCase(ctx context.Context, pipeline Pipeline, c *Case) CaseResult {
+	start := time.Now()
+	result := CaseResult{CaseID: c.ID, Question: c.Question}
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	sql, err := pipeline.GenerateSQL(ctx, c.Question)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to generate SQL: %v", err)
+		return result
+	}
+	result.GeneratedSQL = sql
+	result.ExactSQLMatch = c.ExpectedSQL != "" && normalizeSQL(sql) == normalizeSQL(c.ExpectedSQL)
+
+	rows, err := pipeline.Execute(ctx, sql)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to execute generated SQL: %v", err)
+		return result
+	}
+
+	expected := c.ExpectedResult
+	if expected == nil {
+		if expected, err = pipeline.Execute(ctx, c.ExpectedSQL); err != nil {
+			result.Error = fmt.Sprintf("failed to execute expected SQL: %v", err)
+			return result
+		}
+	}
+
+	result.Passed = sameResult(rows, expected)
+	return result
+}
+
+// compare builds the report of a run against its baseline
+func compare(run, baseline *Run) *Report {
+	report := &Report{Run: run}
+	if baseline == nil {
+		return report
+	}
+
+	summary := *baseline
+	summary.Results = nil
+	report.Baseline = &summary
+	report.AccuracyDelta = run.Accuracy - baseline.Accuracy
+
+	before := make(map[string]bool, len(baseline.Results))
+	for _, r := range baseline.Results {
+		before[r.CaseID] = r.Passed
+	}
+	for _, r := range run.Results {
+		passedBefore, ok := before[r.CaseID]
+		if !ok {
+			continue
+		}
+		if passedBefore && !r.Passed {
+			report.Regressions = append(report.Regressions, r)
+		} else if !passedBefore && r.Passed {
+			report.Fixed = append(report.Fixed, r)
+		}
+	}
+	return report
+}
+
+// sameResult compares two results as multisets of rows. Column names and row
+// order are ignored since equivalent queries may alias or sort differently.
+func sameResult(actual, expected []map[string]interface{}) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	counts := make(map[string]int, len(expected))
+	for _, row := range expected {
+		counts[rowKey(row)]++
+	}
+	for _, row := range actual {
+		key := rowKey(row)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+	return true
+}
+
+// rowKey renders the values of a row in a canonical, order-independent form
+func rowKey(row map[string]interface{}) string {
+	values := make([]string, 0, len(row))
+	for _, v := range row {
+		values = append(values, normalizeValue(v))
+	}
+	sort.Strings(values)
+	return strings.Join(values, "\x1f")
+}
+
+// normalizeValue renders a value so that numbers compare equal regardless of
+// whether the driver returned them as integers, floats or decimal strings
+func normalizeValue(v interface{}) string {
+	var s string
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		s = string(val)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	default:
+		s = fmt.Sprint(val)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return s
+}
+
+// normalizeSQL lowercases a query and collapses its whitespace
+func normalizeSQL(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.TrimRight(strings.TrimSpace(query), ";"))), " ")
+}
@@ -0,0 +1,77 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameResult(t *testing.T) {
+	expected := []map[string]interface{}{
+		{"region": "EU", "total": 12.5},
+		{"region": "US", "total": int64(3)},
+	}
+
+	tests := []struct {
+		name   string
+		actual []map[string]interface{}
+		want   bool
+	}{
+		{
+			name: "reordered rows and aliased columns",
+			actual: []map[string]interface{}{
+				{"REGION": "US", "SUM_TOTAL": "3"},
+				{"REGION": "EU", "SUM_TOTAL": "12.50"},
+			},
+			want: true,
+		},
+		{
+			name: "different value",
+			actual: []map[string]interface{}{
+				{"region": "US", "total": 4},
+				{"region": "EU", "total": 12.5},
+			},
+			want: false,
+		},
+		{
+			name: "missing row",
+			actual: []map[string]interface{}{
+				{"region": "EU", "total": 12.5},
+			},
+			want: false,
+		},
+		{
+			name: "duplicated row",
+			actual: []map[string]interface{}{
+				{"region": "EU", "total": 12.5},
+				{"region": "EU", "total": 12.5},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sameResult(tt.actual, expected))
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Run{Accuracy: 0.5, Results: []CaseResult{
+		{CaseID: "a", Passed: true},
+		{CaseID: "b", Passed: false},
+	}}
+	run := &Run{Accuracy: 0.5, Results: []CaseResult{
+		{CaseID: "a", Passed: false},
+		{CaseID: "b", Passed: true},
+		{CaseID: "c", Passed: false},
+	}}
+
+	report := compare(run, baseline)
+	assert.Len(t, report.Regressions, 1)
+	assert.Equal(t, "a", report.Regressions[0].CaseID)
+	assert.Len(t, report.Fixed, 1)
+	assert.Equal(t, "b", report.Fixed[0].CaseID)
+	assert.Nil(t, report.Baseline.Results)
+}
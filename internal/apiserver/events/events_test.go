@@ -0,0 +1,131 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusDeliversToSubscribedSinks(t *testing.T) {
+	var mu sync.Mutex
+	var webhook []Event
+	var kafka []map[string]interface{}
+	var kafkaPath, kafkaType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.HasPrefix(r.URL.Path, "/topics/") {
+			kafkaPath, kafkaType = r.URL.Path, r.Header.Get("Content-Type")
+			var body struct {
+				Records []map[string]interface{} `json:"records"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			kafka = append(kafka, body.Records...)
+			return
+		}
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		var e Event
+		_ = json.NewDecoder(r.Body).Decode(&e)
+		webhook = append(webhook, e)
+	}))
+	defer srv.Close()
+
+	bus, err := NewBus(&Config{Sinks: []SinkConfig{
+		{Type: "webhook", URL: srv.URL + "/hook", Headers: map[string]string{"Authorization": "secret"}, Events: []string{"query.*"}},
+		{Type: "kafka", URL: srv.URL, Topic: "audit"},
+	}})
+	require.NoError(t, err)
+
+	bus.Publish(&Event{Type: TypeServerStarted, Server: "sales"})
+	bus.Publish(&Event{Type: TypeQueryFailed, Server: "sales", Data: map[string]interface{}{"error": "boom"}})
+	require.NoError(t, bus.Close())
+
+	// Events published after Close are dropped
+	bus.Publish(&Event{Type: TypeQueryFailed})
+
+	require.Len(t, webhook, 1)
+	assert.Equal(t, TypeQueryFailed, webhook[0].Type)
+	assert.NotEmpty(t, webhook[0].ID)
+	assert.False(t, webhook[0].Time.IsZero())
+	assert.Equal(t, "boom", webhook[0].Data["error"])
+
+	require.Len(t, kafka, 2)
+	assert.Equal(t, "/topics/audit", kafkaPath)
+	assert.Equal(t, kafkaContentType, kafkaType)
+	assert.Equal(t, "sales", kafka[0]["key"])
+	assert.Equal(t, TypeServerStarted, kafka[0]["value"].(map[string]interface{})["type"])
+}
+
+func TestNilBusDiscardsEvents(t *testing.T) {
+	var bus *Bus
+	bus.Publish(&Event{Type: TypeServerStarted})
+	assert.NoError(t, bus.Close())
+}
+
+func TestNewSinkRejectsInvalidConfig(t *testing.T) {
+	_, err := NewSink(SinkConfig{Type: "smtp", URL: "smtp://localhost"})
+	assert.Error(t, err)
+	_, err = NewSink(SinkConfig{Type: "webhook"})
+	assert.Error(t, err)
+	_, err = NewSink(SinkConfig{Type: "nats", URL: "http://localhost:4222"})
+	assert.Error(t, err)
+}
+
+func TestNATSSinkPublishes(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	type message struct {
+		connect string
+		subject string
+		payload string
+	}
+	received := make(chan message, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.WriteString(conn, "INFO {\"server_id\":\"test\"}\r\n")
+
+		r := bufio.NewReader(conn)
+		connect, _ := r.ReadString('\n')
+		pub, _ := r.ReadString('\n')
+		fields := strings.Fields(pub)
+		size, _ := strconv.Atoi(fields[2])
+		payload := make([]byte, size+2)
+		_, _ = io.ReadFull(r, payload)
+		ping, _ := r.ReadString('\n')
+		if strings.TrimSpace(ping) == "PING" {
+			_, _ = io.WriteString(conn, "PONG\r\n")
+		}
+		received <- message{connect: connect, subject: fields[1], payload: string(payload[:size])}
+	}()
+
+	sink, err := NewNATSSink("nats://gateway:pw@"+ln.Addr().String(), "", "")
+	require.NoError(t, err)
+	defer sink.Close()
+
+	require.NoError(t, sink.Publish(t.Context(), &Event{ID: "1", Type: TypeSchemaChanged, Server: "sales"}))
+
+	msg := <-received
+	assert.Contains(t, msg.connect, `"user":"gateway"`)
+	assert.Contains(t, msg.connect, `"pass":"pw"`)
+	assert.Equal(t, "mcp.gateway.events.schema.changed", msg.subject)
+
+	var e Event
+	require.NoError(t, json.Unmarshal([]byte(msg.payload), &e))
+	assert.Equal(t, "sales", e.Server)
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultKafkaTopic = "mcp-gateway-events"
+
+	// kafkaContentType is the embedded JSON format of the Kafka REST proxy v2 API
+	kafkaContentType = "application/vnd.kafka.json.v2+json"
+)
+
+// WebhookSink posts every event as JSON to an HTTP endpoint
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+}
+
+// NewWebhookSink creates a webhook sink
+func NewWebhookSink(endpoint string, headers map[string]string) *WebhookSink {
+	return &WebhookSink{url: endpoint, headers: headers}
+}
+
+// Publish posts the event to the webhook
+func (s *WebhookSink) Publish(ctx context.Context, event *Event) error {
+	return postJSON(ctx, s.url, "application/json", s.headers, event)
+}
+
+// Close is a no-op; webhooks hold no connections
+func (s *WebhookSink) Close() error {
+	return nil
+}
+
+// KafkaSink produces events to a Kafka topic through a Kafka REST proxy,
+// which keeps a native Kafka client out of the gateway. Records are keyed
+// by server so each server's events stay ordered within a partition.
+type KafkaSink struct {
+	url     string
+	headers map[string]string
+}
+
+// NewKafkaSink creates a Kafka sink for the REST proxy at baseURL
+func NewKafkaSink(baseURL, topic string, headers map[string]string) *KafkaSink {
+	if topic == "" {
+		topic = defaultKafkaTopic
+	}
+	return &KafkaSink{
+		url:     strings.TrimSuffix(baseURL, "/") + "/topics/" + url.PathEscape(topic),
+		headers: headers,
+	}
+}
+
+// Publish produces the event to the topic
+func (s *KafkaSink) Publish(ctx context.Context, event *Event) error {
+	record := map[string]interface{}{"value": event}
+	if event.Server != "" {
+		record["key"] = event.Server
+	}
+	return postJSON(ctx, s.url, kafkaContentType, s.headers, map[string]interface{}{
+		"records": []interface{}{record},
+	})
+}
+
+// Close is a no-op; the REST proxy holds the Kafka connections
+func (s *KafkaSink) Close() error {
+	return nil
+}
+
+// postJSON posts a JSON payload and checks the response status
+func postJSON(ctx context.Context, endpoint, contentType string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
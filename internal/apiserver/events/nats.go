@@ -0,0 +1,220 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultNATSSubject = "mcp.gateway.events"
+	defaultNATSPort    = "4222"
+	natsDialTimeout    = 5 * time.Second
+)
+
+// natsConnect is the CONNECT message sent after the server's INFO
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// natsInfo holds the fields of the server's INFO message the sink needs
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+}
+
+// NATSSink publishes events to NATS subjects named after the event type,
+// e.g. mcp.gateway.events.query.failed. It speaks the NATS text protocol
+// directly and confirms every publish with a PING round trip, reconnecting
+// once when the connection was lost.
+type NATSSink struct {
+	addr    string
+	useTLS  bool
+	host    string
+	subject string
+	connect natsConnect
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewNATSSink creates a NATS sink for a nats:// or tls:// server URL
+func NewNATSSink(serverURL, subject, token string) (*NATSSink, error) {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS url: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported NATS url scheme: %s", u.Scheme)
+	}
+	if subject == "" {
+		subject = defaultNATSSubject
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultNATSPort
+	}
+	s := &NATSSink{
+		addr:    net.JoinHostPort(u.Hostname(), port),
+		useTLS:  u.Scheme == "tls",
+		host:    u.Hostname(),
+		subject: subject,
+		connect: natsConnect{Name: "mcp-db-gateway", Lang: "go", Version: "1.0.0", AuthToken: token},
+	}
+	if u.User != nil {
+		s.connect.User = u.User.Username()
+		s.connect.Pass, _ = u.User.Password()
+	}
+	return s, nil
+}
+
+// Publish publishes the event, reconnecting once on failure
+func (s *NATSSink) Publish(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	subject := s.subject + "." + event.Type
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reused := s.conn != nil
+	if err := s.ensureConnLocked(ctx); err != nil {
+		return err
+	}
+	err = s.publishLocked(ctx, subject, data)
+	if err != nil && reused {
+		s.closeLocked()
+		if err := s.ensureConnLocked(ctx); err != nil {
+			return err
+		}
+		err = s.publishLocked(ctx, subject, data)
+	}
+	if err != nil {
+		s.closeLocked()
+	}
+	return err
+}
+
+// Close closes the connection to the server
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+	return nil
+}
+
+// ensureConnLocked connects and authenticates unless already connected
+func (s *NATSSink) ensureConnLocked(ctx context.Context) error {
+	if s.conn != nil {
+		return nil
+	}
+
+	dialer := net.Dialer{Timeout: natsDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to read NATS server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS greeting: %s", strings.TrimSpace(line))
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "INFO "))), &info); err != nil {
+		conn.Close()
+		return fmt.Errorf("invalid NATS server info: %w", err)
+	}
+
+	switch {
+	case s.useTLS:
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: s.host})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("failed to establish TLS with NATS: %w", err)
+		}
+		conn, reader = tlsConn, bufio.NewReader(tlsConn)
+	case info.TLSRequired:
+		conn.Close()
+		return fmt.Errorf("NATS server requires TLS; use a tls:// url")
+	}
+
+	connect, err := json.Marshal(s.connect)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to encode NATS connect: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to send NATS connect: %w", err)
+	}
+
+	s.conn, s.reader = conn, reader
+	return nil
+}
+
+// publishLocked sends a PUB followed by a PING and waits for the PONG, so
+// errors reported by the server, e.g. authorization failures, are returned
+func (s *NATSSink) publishLocked(ctx context.Context, subject string, data []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(publishTimeout)
+	}
+	_ = s.conn.SetDeadline(deadline)
+
+	msg := fmt.Sprintf("PUB %s %d\r\n%s\r\nPING\r\n", subject, len(data), data)
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read NATS response: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("failed to answer NATS ping: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// closeLocked drops the connection
+func (s *NATSSink) closeLocked() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.reader = nil, nil
+	}
+}
@@ -0,0 +1,148 @@
+// Package graphql parses GraphQL executable documents. Fragments are
+// expanded into the selection sets that spread them, so executors only see
+// fields.
+package graphql
+
+import "fmt"
+
+// Operation types
+const (
+	OperationQuery    = "query"
+	OperationMutation = "mutation"
+)
+
+// Document is a parsed executable document
+type Document struct {
+	Operations []*Operation
+}
+
+// Operation is a query or mutation with its selection set
+type Operation struct {
+	Type         string
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []*Field
+}
+
+// VariableDefinition declares a variable of an operation
+type VariableDefinition struct {
+	Name     string
+	Type     string
+	NonNull  bool
+	Default  interface{}
+	HasValue bool
+}
+
+// Field is a selected field
+type Field struct {
+	Alias        string
+	Name         string
+	Arguments    map[string]interface{}
+	Directives   []*Directive
+	SelectionSet []*Field
+}
+
+// Directive is a directive applied to a field, such as @skip or @include
+type Directive struct {
+	Name      string
+	Arguments map[string]interface{}
+}
+
+// Variable is a reference to a variable within an argument value
+type Variable string
+
+// Enum is an enum value within an argument value
+type Enum string
+
+// ResponseKey returns the key of the field in the response: its alias,
+// or its name
+func (f *Field) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// Operation returns the operation to execute: the one named, or the only
+// operation of the document when name is empty
+func (d *Document) Operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) != 1 {
+			return nil, fmt.Errorf("the document has %d operations, an operation name is required", len(d.Operations))
+		}
+		return d.Operations[0], nil
+	}
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// VariableValues returns the values of an operation's variables: the provided
+// value, or the default. Required variables without a value are an error.
+func (op *Operation) VariableValues(provided map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(op.Variables))
+	for _, def := range op.Variables {
+		if v, ok := provided[def.Name]; ok {
+			if v == nil && def.NonNull {
+				return nil, fmt.Errorf("variable $%s of type %s must not be null", def.Name, def.Type)
+			}
+			values[def.Name] = v
+			continue
+		}
+		if def.HasValue {
+			values[def.Name] = Resolve(def.Default, nil)
+			continue
+		}
+		if def.NonNull {
+			return nil, fmt.Errorf("variable $%s of type %s is required", def.Name, def.Type)
+		}
+	}
+	return values, nil
+}
+
+// Resolve converts an argument value to plain Go values: variables are
+// replaced by their values, enums become strings, numbers are json.Number,
+// lists are []interface{} and objects are map[string]interface{}
+func Resolve(value interface{}, variables map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case Variable:
+		return variables[string(v)]
+	case Enum:
+		return string(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = Resolve(item, variables)
+		}
+		return list
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			obj[k] = Resolve(item, variables)
+		}
+		return obj
+	default:
+		return v
+	}
+}
+
+// Skipped reports whether a field is excluded by @skip or @include
+func (f *Field) Skipped(variables map[string]interface{}) bool {
+	for _, d := range f.Directives {
+		condition, _ := Resolve(d.Arguments["if"], variables).(bool)
+		switch d.Name {
+		case "skip":
+			if condition {
+				return true
+			}
+		case "include":
+			if !condition {
+				return true
+			}
+		}
+	}
+	return false
+}
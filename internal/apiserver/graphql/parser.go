@@ -0,0 +1,724 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tokenKind classifies lexical tokens
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+// token is a lexical token with its position
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+	col   int
+}
+
+// String describes a token for error messages
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of document"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// lexer splits a document into tokens, skipping whitespace, commas and
+// comments
+type lexer struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+}
+
+// next returns the next token
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	tok := token{line: l.line, col: l.pos - l.lineStart + 1}
+	if l.pos >= len(l.src) {
+		return tok, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		tok.kind, tok.value = tokenPunct, "..."
+	case strings.IndexByte("!$():=@[]{}|&", c) >= 0:
+		l.pos++
+		tok.kind, tok.value = tokenPunct, string(c)
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		tok.kind, tok.value = tokenName, l.src[start:l.pos]
+	case c == '-' || isDigit(c):
+		return l.number(tok)
+	case c == '"':
+		value, err := l.string()
+		if err != nil {
+			return tok, l.errorf(tok, "%v", err)
+		}
+		tok.kind, tok.value = tokenString, value
+	default:
+		r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+		return tok, l.errorf(tok, "unexpected character %q", r)
+	}
+	return tok, nil
+}
+
+// skipIgnored skips whitespace, commas, byte order marks and comments
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; {
+		case c == '\n':
+			l.pos++
+			l.line++
+			l.lineStart = l.pos
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return
+		}
+	}
+}
+
+// number lexes an Int or Float literal
+func (l *lexer) number(tok token) (token, error) {
+	start := l.pos
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := l.digits()
+	if digits == 0 {
+		return tok, l.errorf(tok, "invalid number")
+	}
+	tok.kind = tokenInt
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		if l.digits() == 0 {
+			return tok, l.errorf(tok, "invalid number")
+		}
+		tok.kind = tokenFloat
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if l.digits() == 0 {
+			return tok, l.errorf(tok, "invalid number")
+		}
+		tok.kind = tokenFloat
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || l.src[l.pos] == '.') {
+		return tok, l.errorf(tok, "invalid number")
+	}
+	tok.value = l.src[start:l.pos]
+	return tok, nil
+}
+
+// digits consumes a run of digits and returns its length
+func (l *lexer) digits() int {
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+	return l.pos - start
+}
+
+// string lexes a string or block string literal
+func (l *lexer) string() (string, error) {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		l.pos += 3
+		end := strings.Index(l.src[l.pos:], `"""`)
+		for end > 0 && l.src[l.pos+end-1] == '\\' {
+			next := strings.Index(l.src[l.pos+end+3:], `"""`)
+			if next < 0 {
+				end = -1
+				break
+			}
+			end += 3 + next
+		}
+		if end < 0 {
+			return "", fmt.Errorf("unterminated block string")
+		}
+		raw := l.src[l.pos : l.pos+end]
+		l.line += strings.Count(raw, "\n")
+		if i := strings.LastIndexByte(raw, '\n'); i >= 0 {
+			l.lineStart = l.pos + i + 1
+		}
+		l.pos += end + 3
+		return strings.ReplaceAll(raw, `\"""`, `"""`), nil
+	}
+
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return b.String(), nil
+		case c == '\n':
+			return "", fmt.Errorf("unterminated string")
+		case c == '\\':
+			if l.pos+1 >= len(l.src) {
+				return "", fmt.Errorf("unterminated string")
+			}
+			l.pos += 2
+			switch e := l.src[l.pos-1]; e {
+			case '"', '\\', '/':
+				b.WriteByte(e)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return "", fmt.Errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				l.pos += 4
+			default:
+				return "", fmt.Errorf("invalid escape \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// errorf returns a syntax error at a token's position
+func (l *lexer) errorf(tok token, format string, args ...interface{}) error {
+	return fmt.Errorf("syntax error at line %d, column %d: %s", tok.line, tok.col, fmt.Sprintf(format, args...))
+}
+
+func isLetter(c byte) bool { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' }
+func isDigit(c byte) bool  { return '0' <= c && c <= '9' }
+
+// selection is a parsed selection before fragments are expanded: a field,
+// a fragment spread or an inline fragment
+type selection struct {
+	field      *Field
+	children   []selection
+	spread     string
+	directives []*Directive
+}
+
+// parser builds a Document from tokens
+type parser struct {
+	lex       lexer
+	tok       token
+	fragments map[string][]selection
+}
+
+// Parse parses an executable document
+func Parse(src string) (*Document, error) {
+	p := &parser{lex: lexer{src: src, line: 1}, fragments: make(map[string][]selection)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	type pending struct {
+		op   *Operation
+		sels []selection
+	}
+	var ops []pending
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, pending{&Operation{Type: OperationQuery}, sels})
+		case p.peek(tokenName, OperationQuery), p.peek(tokenName, OperationMutation):
+			op, sels, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, pending{op, sels})
+		case p.peek(tokenName, "fragment"):
+			if err := p.fragment(); err != nil {
+				return nil, err
+			}
+		case p.peek(tokenName, "subscription"):
+			return nil, p.errorf("subscriptions are not supported")
+		default:
+			return nil, p.errorf("expected an operation or fragment, found %s", p.tok)
+		}
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("the document has no operations")
+	}
+
+	doc := &Document{}
+	names := make(map[string]bool)
+	for _, o := range ops {
+		if o.op.Name == "" && len(ops) > 1 {
+			return nil, fmt.Errorf("anonymous operations must be the only operation of the document")
+		}
+		if names[o.op.Name] {
+			return nil, fmt.Errorf("operation %q is defined twice", o.op.Name)
+		}
+		names[o.op.Name] = true
+
+		fields, err := p.expand(o.sels, nil, make(map[string]bool))
+		if err != nil {
+			return nil, err
+		}
+		o.op.SelectionSet = fields
+		doc.Operations = append(doc.Operations, o.op)
+	}
+	return doc, nil
+}
+
+// advance reads the next token
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// peek reports whether the current token has the kind and value
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.tok.kind == kind && p.tok.value == value
+}
+
+// expect consumes a punctuator
+func (p *parser) expect(value string) error {
+	if !p.peek(tokenPunct, value) {
+		return p.errorf("expected %q, found %s", value, p.tok)
+	}
+	return p.advance()
+}
+
+// skip consumes a punctuator when it is the current token
+func (p *parser) skip(value string) (bool, error) {
+	if !p.peek(tokenPunct, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+// name consumes a name
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("expected a name, found %s", p.tok)
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+// errorf returns a syntax error at the current token
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return p.lex.errorf(p.tok, format, args...)
+}
+
+// operation parses a query or mutation definition
+func (p *parser) operation() (*Operation, []selection, error) {
+	op := &Operation{Type: p.tok.value}
+	if err := p.advance(); err != nil {
+		return nil, nil, err
+	}
+	if p.tok.kind == tokenName {
+		op.Name = p.tok.value
+		if err := p.advance(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if ok, err := p.skip("("); err != nil {
+		return nil, nil, err
+	} else if ok {
+		for !p.peek(tokenPunct, ")") {
+			def, err := p.variableDefinition()
+			if err != nil {
+				return nil, nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if _, err := p.directives(); err != nil {
+		return nil, nil, err
+	}
+	sels, err := p.selectionSet()
+	if err != nil {
+		return nil, nil, err
+	}
+	return op, sels, nil
+}
+
+// variableDefinition parses $name: Type = default
+func (p *parser) variableDefinition() (*VariableDefinition, error) {
+	if err := p.expect("$"); err != nil {
+		return nil, err
+	}
+	name, err := p.name()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	typ, err := p.typeRef()
+	if err != nil {
+		return nil, err
+	}
+	def := &VariableDefinition{Name: name, Type: typ, NonNull: strings.HasSuffix(typ, "!")}
+
+	if ok, err := p.skip("="); err != nil {
+		return nil, err
+	} else if ok {
+		if def.Default, err = p.value(true); err != nil {
+			return nil, err
+		}
+		def.HasValue = true
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// typeRef parses a type reference such as [Int!]!
+func (p *parser) typeRef() (string, error) {
+	var typ string
+	if ok, err := p.skip("["); err != nil {
+		return "", err
+	} else if ok {
+		inner, err := p.typeRef()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+	if ok, err := p.skip("!"); err != nil {
+		return "", err
+	} else if ok {
+		typ += "!"
+	}
+	return typ, nil
+}
+
+// fragment parses a fragment definition
+func (p *parser) fragment() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if name == "on" {
+		return p.errorf("fragments cannot be named \"on\"")
+	}
+	if !p.peek(tokenName, "on") {
+		return p.errorf("expected \"on\", found %s", p.tok)
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if _, err := p.directives(); err != nil {
+		return err
+	}
+	if _, ok := p.fragments[name]; ok {
+		return fmt.Errorf("fragment %q is defined twice", name)
+	}
+	sels, err := p.selectionSet()
+	if err != nil {
+		return err
+	}
+	p.fragments[name] = sels
+	return nil
+}
+
+// selectionSet parses { selection... }
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var sels []selection
+	for !p.peek(tokenPunct, "}") {
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	if len(sels) == 0 {
+		return nil, p.errorf("selection sets must not be empty")
+	}
+	return sels, p.advance()
+}
+
+// selection parses a field, fragment spread or inline fragment
+func (p *parser) selection() (selection, error) {
+	if ok, err := p.skip("..."); err != nil {
+		return selection{}, err
+	} else if ok {
+		if p.tok.kind == tokenName && p.tok.value != "on" {
+			name := p.tok.value
+			if err := p.advance(); err != nil {
+				return selection{}, err
+			}
+			directives, err := p.directives()
+			return selection{spread: name, directives: directives}, err
+		}
+		if p.peek(tokenName, "on") {
+			if err := p.advance(); err != nil {
+				return selection{}, err
+			}
+			if _, err := p.name(); err != nil {
+				return selection{}, err
+			}
+		}
+		directives, err := p.directives()
+		if err != nil {
+			return selection{}, err
+		}
+		children, err := p.selectionSet()
+		return selection{children: children, directives: directives}, err
+	}
+
+	field := &Field{}
+	name, err := p.name()
+	if err != nil {
+		return selection{}, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return selection{}, err
+	} else if ok {
+		field.Alias = name
+		if name, err = p.name(); err != nil {
+			return selection{}, err
+		}
+	}
+	field.Name = name
+
+	if field.Arguments, err = p.arguments(); err != nil {
+		return selection{}, err
+	}
+	if field.Directives, err = p.directives(); err != nil {
+		return selection{}, err
+	}
+	var children []selection
+	if p.peek(tokenPunct, "{") {
+		if children, err = p.selectionSet(); err != nil {
+			return selection{}, err
+		}
+	}
+	return selection{field: field, children: children}, nil
+}
+
+// arguments parses (name: value ...) when present
+func (p *parser) arguments() (map[string]interface{}, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+	args := make(map[string]interface{})
+	for !p.peek(tokenPunct, ")") {
+		nameTok := p.tok
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := args[name]; ok {
+			return nil, p.lex.errorf(nameTok, "argument %q is given twice", name)
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if args[name], err = p.value(false); err != nil {
+			return nil, err
+		}
+	}
+	return args, p.advance()
+}
+
+// directives parses @name(arguments)...
+func (p *parser) directives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek(tokenPunct, "@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, &Directive{Name: name, Arguments: args})
+	}
+	return directives, nil
+}
+
+// value parses an argument value; constant values cannot use variables
+func (p *parser) value(constant bool) (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt, tokenFloat:
+		return json.Number(tok.value), p.advance()
+	case tokenString:
+		return tok.value, p.advance()
+	case tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return Enum(tok.value), nil
+	}
+
+	switch tok.value {
+	case "$":
+		if constant {
+			return nil, p.errorf("variables are not allowed here")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return Variable(name), err
+	case "[":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []interface{}{}
+		for !p.peek(tokenPunct, "]") {
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, p.advance()
+	case "{":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		obj := make(map[string]interface{})
+		for !p.peek(tokenPunct, "}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if obj[name], err = p.value(constant); err != nil {
+				return nil, err
+			}
+		}
+		return obj, p.advance()
+	}
+	return nil, p.errorf("expected a value, found %s", tok)
+}
+
+// expand replaces fragment spreads and inline fragments by their fields.
+// Directives of a fragment apply to each of its fields.
+func (p *parser) expand(sels []selection, inherited []*Directive, visiting map[string]bool) ([]*Field, error) {
+	var fields []*Field
+	for _, sel := range sels {
+		directives := append(append([]*Directive{}, inherited...), sel.directives...)
+		switch {
+		case sel.field != nil:
+			field := *sel.field
+			field.Directives = append(directives, field.Directives...)
+			children, err := p.expand(sel.children, nil, visiting)
+			if err != nil {
+				return nil, err
+			}
+			field.SelectionSet = children
+			fields = append(fields, &field)
+		case sel.spread != "":
+			fragment, ok := p.fragments[sel.spread]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q", sel.spread)
+			}
+			if visiting[sel.spread] {
+				return nil, fmt.Errorf("fragment %q spreads itself", sel.spread)
+			}
+			visiting[sel.spread] = true
+			expanded, err := p.expand(fragment, directives, visiting)
+			delete(visiting, sel.spread)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, expanded...)
+		default:
+			expanded, err := p.expand(sel.children, directives, visiting)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, expanded...)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# Orders of a customer
+		query Orders($region: String = "EU", $limit: Int!, $verbose: Boolean) {
+			recent: ORDERS(REGION: $region, limit: $limit, sort: DESC, tags: ["a", 1.5e3], where: {TOTAL: -2}) {
+				ID
+				...customer @include(if: $verbose)
+				... { NOTE @skip(if: true) }
+			}
+		}
+
+		fragment customer on ORDERS {
+			CUSTOMERS { NAME }
+		}
+
+		mutation Create { insert_ORDERS(input: {ID: 7, NOTE: "say \"hi\"\n"}) }
+	`)
+	require.NoError(t, err)
+	require.Len(t, doc.Operations, 2)
+
+	_, err = doc.Operation("")
+	assert.Error(t, err)
+	op, err := doc.Operation("Orders")
+	require.NoError(t, err)
+	assert.Equal(t, OperationQuery, op.Type)
+	require.Len(t, op.Variables, 3)
+	assert.Equal(t, &VariableDefinition{Name: "limit", Type: "Int!", NonNull: true}, op.Variables[1])
+
+	_, err = op.VariableValues(map[string]interface{}{})
+	assert.EqualError(t, err, "variable $limit of type Int! is required")
+	vars, err := op.VariableValues(map[string]interface{}{"limit": json.Number("5"), "verbose": false})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"region": "EU", "limit": json.Number("5"), "verbose": false}, vars)
+
+	require.Len(t, op.SelectionSet, 1)
+	orders := op.SelectionSet[0]
+	assert.Equal(t, "recent", orders.ResponseKey())
+	assert.Equal(t, "ORDERS", orders.Name)
+	assert.Equal(t, Variable("region"), orders.Arguments["REGION"])
+	assert.Equal(t, map[string]interface{}{
+		"REGION": "EU",
+		"limit":  json.Number("5"),
+		"sort":   "DESC",
+		"tags":   []interface{}{"a", json.Number("1.5e3")},
+		"where":  map[string]interface{}{"TOTAL": json.Number("-2")},
+	}, Resolve(orders.Arguments, vars))
+
+	// Fragments are expanded in place, carrying their directives
+	require.Len(t, orders.SelectionSet, 3)
+	assert.Equal(t, "ID", orders.SelectionSet[0].Name)
+	assert.Equal(t, "CUSTOMERS", orders.SelectionSet[1].Name)
+	assert.Equal(t, "NAME", orders.SelectionSet[1].SelectionSet[0].Name)
+	assert.True(t, orders.SelectionSet[1].Skipped(vars))
+	assert.Equal(t, "NOTE", orders.SelectionSet[2].Name)
+	assert.True(t, orders.SelectionSet[2].Skipped(vars))
+
+	create, err := doc.Operation("Create")
+	require.NoError(t, err)
+	assert.Equal(t, OperationMutation, create.Type)
+	assert.Equal(t, map[string]interface{}{"ID": json.Number("7"), "NOTE": "say \"hi\"\n"}, create.SelectionSet[0].Arguments["input"])
+}
+
+func TestParseErrors(t *testing.T) {
+	for doc, msg := range map[string]string{
+		`{ ORDERS { ID }`:                             `syntax error at line 1, column 16: expected a name, found end of document`,
+		`{ ORDERS(limit: 1 limit: 2) { ID } }`:        `syntax error at line 1, column 19: argument "limit" is given twice`,
+		`{ ORDERS { ...missing } }`:                   `unknown fragment "missing"`,
+		`{ A { ...f } } fragment f on A { ...f }`:     `fragment "f" spreads itself`,
+		`subscription { ORDERS { ID } }`:              `syntax error at line 1, column 1: subscriptions are not supported`,
+		`{ ORDERS(note: "unterminated) { ID } }`:      `syntax error at line 1, column 16: unterminated string`,
+		`query A { ORDERS { ID } } { ORDERS { ID } }`: `anonymous operations must be the only operation of the document`,
+	} {
+		_, err := Parse(doc)
+		assert.EqualError(t, err, msg, doc)
+	}
+}
@@ -0,0 +1,302 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// GatewayService is the fully qualified name of the service in gateway.proto
+const GatewayService = "mcpgateway.v1.Gateway"
+
+// queryBatchSize is the number of rows sent in each QueryResult message
+const queryBatchSize = 500
+
+// Gateway implements the methods of the Gateway service
+type Gateway interface {
+	ListTables(ctx context.Context) ([]connector.Table, error)
+	GetTableMetadata(ctx context.Context, table string) (*connector.TableMetadata, error)
+	ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error)
+}
+
+// RegisterGateway registers the methods of the Gateway service
+func RegisterGateway(s *Server, g Gateway) {
+	s.Handle(GatewayService, "ListTables", func(ctx context.Context, _ []byte, send func([]byte) error) error {
+		tables, err := g.ListTables(ctx)
+		if err != nil {
+			return err
+		}
+		var e encoder
+		for _, t := range tables {
+			e.Message(1, func(e *encoder) {
+				e.String(1, t.Name)
+				e.Int(2, int64(t.RowCount))
+			})
+		}
+		return send(e.buf)
+	})
+
+	s.Handle(GatewayService, "GetTableMetadata", func(ctx context.Context, req []byte, send func([]byte) error) error {
+		table, err := decodeGetTableMetadataRequest(req)
+		if err != nil {
+			return err
+		}
+		if table == "" {
+			return Errorf(InvalidArgument, "table is required")
+		}
+		metadata, err := g.GetTableMetadata(ctx, table)
+		if err != nil {
+			return err
+		}
+		var e encoder
+		encodeTableMetadata(&e, metadata)
+		return send(e.buf)
+	})
+
+	s.Handle(GatewayService, "ExecuteQuery", func(ctx context.Context, req []byte, send func([]byte) error) error {
+		query, params, err := decodeExecuteQueryRequest(req)
+		if err != nil {
+			return err
+		}
+		if query == "" {
+			return Errorf(InvalidArgument, "query is required")
+		}
+		rows, err := g.ExecuteQuery(ctx, query, params)
+		if err != nil {
+			return err
+		}
+		return sendRows(rows, send)
+	})
+}
+
+func encodeTableMetadata(e *encoder, m *connector.TableMetadata) {
+	e.String(1, m.Name)
+	e.String(2, m.Description)
+	for _, col := range m.Columns {
+		e.Message(3, func(e *encoder) {
+			e.String(1, col.Name)
+			e.String(2, col.Type)
+			e.String(3, col.Description)
+			e.Bool(4, col.PrimaryKey)
+			e.Bool(5, col.Nullable)
+			e.Int(6, int64(col.MaxLength))
+			e.Bool(7, col.ForeignKey)
+			e.String(8, col.References)
+			e.String(9, col.VerboseDescription)
+			e.String(10, col.Default)
+			e.Int(11, int64(col.Precision))
+			e.Int(12, int64(col.Scale))
+		})
+	}
+	e.Int(4, int64(m.RowCount))
+	e.String(5, m.VerboseDescription)
+}
+
+// sendRows streams rows in batches of QueryResult messages. Columns are
+// sorted by name and named in the first batch only; a result without rows
+// is a single empty batch.
+func sendRows(rows []map[string]interface{}, send func([]byte) error) error {
+	columnSet := make(map[string]struct{})
+	for _, row := range rows {
+		for k := range row {
+			columnSet[k] = struct{}{}
+		}
+	}
+	columns := make([]string, 0, len(columnSet))
+	for k := range columnSet {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+
+	for start := 0; start == 0 || start < len(rows); start += queryBatchSize {
+		var e encoder
+		if start == 0 {
+			for _, col := range columns {
+				e.Bytes(1, []byte(col))
+			}
+		}
+		for _, row := range rows[start:min(start+queryBatchSize, len(rows))] {
+			var values encoder
+			for _, col := range columns {
+				if err := encodeValue(&values, 1, row[col]); err != nil {
+					return Errorf(Internal, "failed to encode column %s: %v", col, err)
+				}
+			}
+			e.Bytes(2, values.buf)
+		}
+		if err := send(e.buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeValue writes a result or parameter value as a Value message
+func encodeValue(e *encoder, field int, v interface{}) error {
+	var m encoder
+	switch v := v.(type) {
+	case nil:
+		m.Varint(1, 0)
+	case string:
+		m.Bytes(2, []byte(v))
+	case int:
+		m.Varint(3, uint64(v))
+	case int32:
+		m.Varint(3, uint64(v))
+	case int64:
+		m.Varint(3, uint64(v))
+	case float32:
+		m.Double(4, float64(v))
+	case float64:
+		m.Double(4, v)
+	case bool:
+		var b uint64
+		if v {
+			b = 1
+		}
+		m.Varint(5, b)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			m.Varint(3, uint64(i))
+		} else if f, err := v.Float64(); err == nil {
+			m.Double(4, f)
+		} else {
+			m.Bytes(2, []byte(v))
+		}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		m.Bytes(6, data)
+	}
+	e.Bytes(field, m.buf)
+	return nil
+}
+
+// decodeValue reads a Value message; a value without a kind is null
+func decodeValue(b []byte) (interface{}, error) {
+	d := decoder{buf: b}
+	var value interface{}
+	for !d.Done() {
+		field, wire, err := d.Next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 1 && wire == wireVarint:
+			_, err = d.Varint()
+			value = nil
+		case field == 2 && wire == wireBytes:
+			var s []byte
+			s, err = d.Bytes()
+			value = string(s)
+		case field == 3 && wire == wireVarint:
+			var i uint64
+			i, err = d.Varint()
+			value = int64(i)
+		case field == 4 && wire == wireFixed64:
+			value, err = d.Double()
+		case field == 5 && wire == wireVarint:
+			var i uint64
+			i, err = d.Varint()
+			value = i != 0
+		case field == 6 && wire == wireBytes:
+			var data []byte
+			if data, err = d.Bytes(); err == nil {
+				if err = json.Unmarshal(data, &value); err != nil {
+					err = fmt.Errorf("invalid json_value: %w", err)
+				}
+			}
+		default:
+			err = d.Skip(wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func decodeGetTableMetadataRequest(b []byte) (string, error) {
+	d := decoder{buf: b}
+	var table string
+	for !d.Done() {
+		field, wire, err := d.Next()
+		if err == nil {
+			if field == 1 && wire == wireBytes {
+				var s []byte
+				s, err = d.Bytes()
+				table = string(s)
+			} else {
+				err = d.Skip(wire)
+			}
+		}
+		if err != nil {
+			return "", Errorf(InvalidArgument, "invalid GetTableMetadataRequest: %v", err)
+		}
+	}
+	return table, nil
+}
+
+func decodeExecuteQueryRequest(b []byte) (string, map[string]interface{}, error) {
+	d := decoder{buf: b}
+	var query string
+	params := make(map[string]interface{})
+	for !d.Done() {
+		field, wire, err := d.Next()
+		if err == nil {
+			switch {
+			case field == 1 && wire == wireBytes:
+				var s []byte
+				s, err = d.Bytes()
+				query = string(s)
+			case field == 2 && wire == wireBytes:
+				var entry []byte
+				if entry, err = d.Bytes(); err == nil {
+					err = decodeParam(entry, params)
+				}
+			default:
+				err = d.Skip(wire)
+			}
+		}
+		if err != nil {
+			return "", nil, Errorf(InvalidArgument, "invalid ExecuteQueryRequest: %v", err)
+		}
+	}
+	return query, params, nil
+}
+
+// decodeParam reads an entry of the params map into params
+func decodeParam(b []byte, params map[string]interface{}) error {
+	d := decoder{buf: b}
+	var key string
+	var value interface{}
+	for !d.Done() {
+		field, wire, err := d.Next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case field == 1 && wire == wireBytes:
+			var s []byte
+			s, err = d.Bytes()
+			key = string(s)
+		case field == 2 && wire == wireBytes:
+			var v []byte
+			if v, err = d.Bytes(); err == nil {
+				value, err = decodeValue(v)
+			}
+		default:
+			err = d.Skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	params[key] = value
+	return nil
+}
@@ -0,0 +1,170 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+type fakeGateway struct {
+	rows   []map[string]interface{}
+	query  string
+	params map[string]interface{}
+}
+
+func (g *fakeGateway) ListTables(context.Context) ([]connector.Table, error) {
+	return []connector.Table{{Name: "ORDERS", RowCount: 3}, {Name: "EMPTY"}}, nil
+}
+
+func (g *fakeGateway) GetTableMetadata(_ context.Context, table string) (*connector.TableMetadata, error) {
+	if table != "ORDERS" {
+		return nil, Errorf(NotFound, "table %s does not exist", table)
+	}
+	return &connector.TableMetadata{Name: "ORDERS", Columns: []connector.Column{{Name: "ID", Type: "NUMBER", PrimaryKey: true}}}, nil
+}
+
+func (g *fakeGateway) ExecuteQuery(_ context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	g.query, g.params = query, params
+	if query == "FAIL" {
+		return nil, fmt.Errorf("100%% broken")
+	}
+	return g.rows, nil
+}
+
+// call invokes a method over HTTP/2 and returns the response messages and
+// the status from the trailers
+func call(t *testing.T, url, method string, req []byte) ([][]byte, string, string) {
+	client := &http.Client{Transport: &http.Transport{Protocols: new(http.Protocols)}}
+	client.Transport.(*http.Transport).Protocols.SetUnencryptedHTTP2(true)
+
+	frame := binary.BigEndian.AppendUint32([]byte{0}, uint32(len(req)))
+	httpReq, err := http.NewRequest(http.MethodPost, url+"/"+GatewayService+"/"+method, bytes.NewReader(append(frame, req...)))
+	require.NoError(t, err)
+	httpReq.Header.Set("Content-Type", "application/grpc")
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, 2, resp.ProtoMajor)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var msgs [][]byte
+	for len(body) > 0 {
+		require.GreaterOrEqual(t, len(body), 5)
+		size := binary.BigEndian.Uint32(body[1:5])
+		msgs = append(msgs, body[5:5+size])
+		body = body[5+size:]
+	}
+	return msgs, resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+}
+
+// fields decodes the length-delimited fields of a message by number
+func fields(t *testing.T, msg []byte) map[int][][]byte {
+	d := decoder{buf: msg}
+	out := make(map[int][][]byte)
+	for !d.Done() {
+		field, wire, err := d.Next()
+		require.NoError(t, err)
+		if wire != wireBytes {
+			require.NoError(t, d.Skip(wire))
+			continue
+		}
+		b, err := d.Bytes()
+		require.NoError(t, err)
+		out[field] = append(out[field], b)
+	}
+	return out
+}
+
+func TestGateway(t *testing.T) {
+	g := &fakeGateway{}
+	srv := NewServer()
+	RegisterGateway(srv, g)
+	ts := httptest.NewUnstartedServer(nil)
+	ts.Config = NewHTTPServer("", srv)
+	ts.Start()
+	defer ts.Close()
+
+	msgs, status, _ := call(t, ts.URL, "ListTables", nil)
+	assert.Equal(t, "0", status)
+	require.Len(t, msgs, 1)
+	tables := fields(t, msgs[0])[1]
+	require.Len(t, tables, 2)
+	assert.Equal(t, "ORDERS", string(fields(t, tables[0])[1][0]))
+
+	var req encoder
+	req.String(1, "ORDERS")
+	msgs, status, _ = call(t, ts.URL, "GetTableMetadata", req.buf)
+	assert.Equal(t, "0", status)
+	require.Len(t, msgs, 1)
+	column := fields(t, fields(t, msgs[0])[3][0])
+	assert.Equal(t, "ID", string(column[1][0]))
+	assert.Equal(t, "NUMBER", string(column[2][0]))
+
+	req = encoder{}
+	req.String(1, "MISSING")
+	_, status, msg := call(t, ts.URL, "GetTableMetadata", req.buf)
+	assert.Equal(t, "5", status)
+	assert.Equal(t, "table MISSING does not exist", msg)
+
+	// Rows are streamed in batches, values follow the columns of the first batch
+	for i := 0; i < queryBatchSize+1; i++ {
+		g.rows = append(g.rows, map[string]interface{}{"ID": int64(i), "NOTE": nil, "TAGS": []interface{}{"a"}})
+	}
+	g.rows[0]["PRICE"] = 1.5
+	req = encoder{}
+	req.String(1, "SELECT * FROM ORDERS WHERE REGION = :region")
+	req.Message(2, func(e *encoder) {
+		e.String(1, "region")
+		require.NoError(t, encodeValue(e, 2, "EU"))
+	})
+	msgs, status, _ = call(t, ts.URL, "ExecuteQuery", req.buf)
+	assert.Equal(t, "0", status)
+	assert.Equal(t, map[string]interface{}{"region": "EU"}, g.params)
+	require.Len(t, msgs, 2)
+	first, second := fields(t, msgs[0]), fields(t, msgs[1])
+	assert.Equal(t, [][]byte{[]byte("ID"), []byte("NOTE"), []byte("PRICE"), []byte("TAGS")}, first[1])
+	assert.Empty(t, second[1])
+	assert.Len(t, first[2], queryBatchSize)
+	assert.Len(t, second[2], 1)
+
+	var values []interface{}
+	for _, v := range fields(t, first[2][0])[1] {
+		value, err := decodeValue(v)
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+	assert.Equal(t, []interface{}{int64(0), nil, 1.5, []interface{}{"a"}}, values)
+
+	req = encoder{}
+	req.String(1, "FAIL")
+	_, status, msg = call(t, ts.URL, "ExecuteQuery", req.buf)
+	assert.Equal(t, "2", status)
+	assert.Equal(t, "100%25 broken", msg)
+
+	_, status, _ = call(t, ts.URL, "ExecuteQuery", nil)
+	assert.Equal(t, "3", status)
+	_, status, _ = call(t, ts.URL, "DropTables", nil)
+	assert.Equal(t, "12", status)
+}
+
+func TestParseTimeout(t *testing.T) {
+	d, err := parseTimeout("250m")
+	require.NoError(t, err)
+	assert.Equal(t, "250ms", d.String())
+	for _, v := range []string{"", "5", "5x", "1234567890S", "-1S"} {
+		_, err := parseTimeout(v)
+		assert.Error(t, err, v)
+	}
+}
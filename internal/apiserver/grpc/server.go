@@ -0,0 +1,256 @@
+// Package grpc serves gRPC services over plaintext HTTP/2. Services register
+// handlers that encode and decode their own protobuf messages, so neither
+// generated stubs nor the grpc-go runtime are needed; clients generate their
+// stubs from the .proto files in this package.
+package grpc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentType = "application/grpc"
+
+	// maxMessageSize is the largest request message accepted, the gRPC default
+	maxMessageSize = 4 << 20
+)
+
+// Code is a gRPC status code
+type Code uint32
+
+// Status codes used by the gateway
+const (
+	OK                Code = 0
+	Canceled          Code = 1
+	Unknown           Code = 2
+	InvalidArgument   Code = 3
+	DeadlineExceeded  Code = 4
+	NotFound          Code = 5
+	PermissionDenied  Code = 7
+	ResourceExhausted Code = 8
+	Unimplemented     Code = 12
+	Internal          Code = 13
+	Unavailable       Code = 14
+	Unauthenticated   Code = 16
+)
+
+var codeNames = map[Code]string{
+	OK:                "OK",
+	Canceled:          "Canceled",
+	Unknown:           "Unknown",
+	InvalidArgument:   "InvalidArgument",
+	DeadlineExceeded:  "DeadlineExceeded",
+	NotFound:          "NotFound",
+	PermissionDenied:  "PermissionDenied",
+	ResourceExhausted: "ResourceExhausted",
+	Unimplemented:     "Unimplemented",
+	Internal:          "Internal",
+	Unavailable:       "Unavailable",
+	Unauthenticated:   "Unauthenticated",
+}
+
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// Status is an error carrying a gRPC status code
+type Status struct {
+	Code    Code
+	Message string
+}
+
+func (s *Status) Error() string {
+	return fmt.Sprintf("rpc error: code = %s desc = %s", s.Code, s.Message)
+}
+
+// Errorf returns a status error with a formatted message
+func Errorf(code Code, format string, args ...interface{}) error {
+	return &Status{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// StatusOf returns the status of an error returned by a handler. Errors
+// without a status are Unknown, except for context errors.
+func StatusOf(err error) *Status {
+	var status *Status
+	switch {
+	case err == nil:
+		return &Status{Code: OK}
+	case errors.As(err, &status):
+		return status
+	case errors.Is(err, context.Canceled):
+		return &Status{Code: Canceled, Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Status{Code: DeadlineExceeded, Message: err.Error()}
+	}
+	return &Status{Code: Unknown, Message: err.Error()}
+}
+
+// Handler handles a call of a unary or server streaming method. req is the
+// encoded request message and send writes an encoded response message;
+// unary methods send exactly one.
+type Handler func(ctx context.Context, req []byte, send func(msg []byte) error) error
+
+// Server routes gRPC calls to the handlers of their methods
+type Server struct {
+	methods map[string]Handler
+}
+
+// NewServer creates a server without methods
+func NewServer() *Server {
+	return &Server{methods: make(map[string]Handler)}
+}
+
+// Handle registers the handler of a method of a fully qualified service
+func (s *Server) Handle(service, method string, h Handler) {
+	s.methods["/"+service+"/"+method] = h
+}
+
+// ServeHTTP serves a gRPC call. The status is always sent in the trailers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "gRPC requires POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != contentType && !strings.HasPrefix(ct, contentType+"+") && !strings.HasPrefix(ct, contentType+";") {
+		http.Error(w, fmt.Sprintf("unsupported content type %q", ct), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	writeStatus(w, s.serve(w, r))
+}
+
+// Abort ends a call with an error before it reaches its handler, e.g. from
+// a middleware authenticating the caller
+func Abort(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	writeStatus(w, err)
+}
+
+// serve reads the request message and runs the method's handler
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) error {
+	h, ok := s.methods[r.URL.Path]
+	if !ok {
+		return Errorf(Unimplemented, "unknown method %s", r.URL.Path)
+	}
+
+	ctx := r.Context()
+	if v := r.Header.Get("Grpc-Timeout"); v != "" {
+		timeout, err := parseTimeout(v)
+		if err != nil {
+			return Errorf(InvalidArgument, "%v", err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := readMessage(r.Body)
+	if err != nil {
+		return err
+	}
+
+	flusher, _ := w.(http.Flusher)
+	return h(ctx, req, func(msg []byte) error {
+		frame := make([]byte, 5, 5+len(msg))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+		if _, err := w.Write(append(frame, msg...)); err != nil {
+			return Errorf(Unavailable, "failed to send message: %v", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
+
+// readMessage reads the single length-prefixed message of a request
+func readMessage(body io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, Errorf(Internal, "failed to read request message: %v", err)
+	}
+	if prefix[0] != 0 {
+		return nil, Errorf(Unimplemented, "compressed messages are not supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxMessageSize {
+		return nil, Errorf(ResourceExhausted, "request message of %d bytes exceeds the limit of %d", size, maxMessageSize)
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, Errorf(Internal, "failed to read request message: %v", err)
+	}
+	return msg, nil
+}
+
+// writeStatus sends the status of a call in the response trailers
+func writeStatus(w http.ResponseWriter, err error) {
+	status := StatusOf(err)
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.FormatUint(uint64(status.Code), 10))
+	if status.Message != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", encodeStatusMessage(status.Message))
+	}
+}
+
+// encodeStatusMessage percent-encodes a status message for its header
+func encodeStatusMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < ' ' || c > '~' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// parseTimeout parses a grpc-timeout header, e.g. 250m or 30S
+func parseTimeout(v string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	if len(v) < 2 || len(v) > 9 {
+		return 0, fmt.Errorf("malformed grpc-timeout %q", v)
+	}
+	unit, ok := units[v[len(v)-1]]
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if !ok || err != nil || n < 0 {
+		return 0, fmt.Errorf("malformed grpc-timeout %q", v)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// NewHTTPServer creates an HTTP server for a gRPC handler that accepts
+// plaintext HTTP/2 connections, as gRPC clients open them without TLS
+func NewHTTPServer(addr string, handler http.Handler) *http.Server {
+	srv := &http.Server{Addr: addr, Handler: handler}
+	srv.Protocols = new(http.Protocols)
+	srv.Protocols.SetUnencryptedHTTP2(true)
+	return srv
+}
@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// encoder appends protobuf fields to a buffer. Scalar fields with their
+// zero value are omitted, as proto3 does.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) Tag(field, wire int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wire))
+}
+
+func (e *encoder) String(field int, s string) {
+	if s != "" {
+		e.Bytes(field, []byte(s))
+	}
+}
+
+// Bytes writes a length-delimited field, even when it is empty
+func (e *encoder) Bytes(field int, b []byte) {
+	e.Tag(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) Int(field int, v int64) {
+	if v != 0 {
+		e.Varint(field, uint64(v))
+	}
+}
+
+func (e *encoder) Bool(field int, v bool) {
+	if v {
+		e.Varint(field, 1)
+	}
+}
+
+// Varint writes a varint field, even when it is zero
+func (e *encoder) Varint(field int, v uint64) {
+	e.Tag(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+// Double writes a double field, even when it is zero
+func (e *encoder) Double(field int, v float64) {
+	e.Tag(field, wireFixed64)
+	e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+// Message writes an embedded message encoded by fn
+func (e *encoder) Message(field int, fn func(*encoder)) {
+	var m encoder
+	fn(&m)
+	e.Bytes(field, m.buf)
+}
+
+// decoder reads the fields of a protobuf message
+type decoder struct {
+	buf []byte
+}
+
+func (d *decoder) Done() bool {
+	return len(d.buf) == 0
+}
+
+// Next reads the tag of the next field
+func (d *decoder) Next() (field, wire int, err error) {
+	v, err := d.Varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	if v>>3 == 0 {
+		return 0, 0, errors.New("invalid protobuf field number 0")
+	}
+	return int(v >> 3), int(v & 7), nil
+}
+
+func (d *decoder) Varint() (uint64, error) {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.buf = d.buf[n:]
+	return v, nil
+}
+
+func (d *decoder) Bytes() ([]byte, error) {
+	n, err := d.Varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.buf)) {
+		return nil, errTruncated
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b, nil
+}
+
+func (d *decoder) Double() (float64, error) {
+	if len(d.buf) < 8 {
+		return 0, errTruncated
+	}
+	v := binary.LittleEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return math.Float64frombits(v), nil
+}
+
+// Skip discards the value of a field the message does not know
+func (d *decoder) Skip(wire int) error {
+	var err error
+	switch wire {
+	case wireVarint:
+		_, err = d.Varint()
+	case wireFixed64:
+		_, err = d.Double()
+	case wireBytes:
+		_, err = d.Bytes()
+	case wireFixed32:
+		if len(d.buf) < 4 {
+			return errTruncated
+		}
+		d.buf = d.buf[4:]
+	default:
+		return errors.New("unsupported protobuf wire type")
+	}
+	return err
+}
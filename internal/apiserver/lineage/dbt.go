@@ -0,0 +1,142 @@
+// Package lineage reads the table lineage of dbt projects from their
+// manifests
+package lineage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Relation is a table or view built or read by a dbt project
+type Relation struct {
+	Database string `json:"database,omitempty"`
+	Schema   string `json:"schema,omitempty"`
+	Name     string `json:"name"`
+}
+
+// matches reports whether the relation is the named table, comparing names
+// case-insensitively like unquoted identifiers; an empty database or
+// schema matches any
+func (r Relation) matches(database, schema, name string) bool {
+	return strings.EqualFold(r.Name, name) &&
+		(database == "" || r.Database == "" || strings.EqualFold(r.Database, database)) &&
+		(schema == "" || r.Schema == "" || strings.EqualFold(r.Schema, schema))
+}
+
+// Graph is the lineage between the relations of a dbt project: the
+// relations each model, seed or snapshot is built from
+type Graph struct {
+	relations map[string]Relation
+	parents   map[string][]string
+	children  map[string][]string
+}
+
+// dbtNode is a node or source of a dbt manifest
+type dbtNode struct {
+	ResourceType string `json:"resource_type"`
+	Database     string `json:"database"`
+	Schema       string `json:"schema"`
+	Name         string `json:"name"`
+	Alias        string `json:"alias"`
+	Identifier   string `json:"identifier"`
+	DependsOn    struct {
+		Nodes []string `json:"nodes"`
+	} `json:"depends_on"`
+}
+
+// relation returns the relation a node builds or a source reads from
+func (n *dbtNode) relation() Relation {
+	name := n.Name
+	switch {
+	case n.Identifier != "":
+		name = n.Identifier
+	case n.Alias != "":
+		name = n.Alias
+	}
+	return Relation{Database: n.Database, Schema: n.Schema, Name: name}
+}
+
+// LoadDBTManifest reads the lineage of a dbt project from its
+// target/manifest.json
+func LoadDBTManifest(path string) (*Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dbt manifest: %w", err)
+	}
+	var manifest struct {
+		Nodes   map[string]*dbtNode `json:"nodes"`
+		Sources map[string]*dbtNode `json:"sources"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse dbt manifest: %w", err)
+	}
+
+	g := &Graph{
+		relations: make(map[string]Relation),
+		parents:   make(map[string][]string),
+		children:  make(map[string][]string),
+	}
+	for id, source := range manifest.Sources {
+		g.relations[id] = source.relation()
+	}
+	for id, node := range manifest.Nodes {
+		switch node.ResourceType {
+		case "model", "seed", "snapshot":
+			g.relations[id] = node.relation()
+		}
+	}
+	// Only dependencies between relations are lineage; tests, macros and
+	// ephemeral models are skipped
+	for id, node := range manifest.Nodes {
+		if _, ok := g.relations[id]; !ok {
+			continue
+		}
+		for _, parent := range node.DependsOn.Nodes {
+			if _, ok := g.relations[parent]; ok {
+				g.parents[id] = append(g.parents[id], parent)
+				g.children[parent] = append(g.children[parent], id)
+			}
+		}
+	}
+	return g, nil
+}
+
+// Upstream returns the relations a table is built from directly
+func (g *Graph) Upstream(database, schema, table string) []Relation {
+	return g.neighbours(g.parents, database, schema, table)
+}
+
+// Downstream returns the relations built from a table directly
+func (g *Graph) Downstream(database, schema, table string) []Relation {
+	return g.neighbours(g.children, database, schema, table)
+}
+
+// neighbours returns the relations linked to a table's, sorted by name
+func (g *Graph) neighbours(links map[string][]string, database, schema, table string) []Relation {
+	if g == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var related []Relation
+	for id, r := range g.relations {
+		if !r.matches(database, schema, table) {
+			continue
+		}
+		for _, linked := range links[id] {
+			if !seen[linked] {
+				seen[linked] = true
+				related = append(related, g.relations[linked])
+			}
+		}
+	}
+	sort.Slice(related, func(i, j int) bool {
+		if related[i].Name != related[j].Name {
+			return related[i].Name < related[j].Name
+		}
+		return related[i].Schema < related[j].Schema
+	})
+	return related
+}
@@ -0,0 +1,46 @@
+package lineage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifest = `{
+	"sources": {
+		"source.shop.raw.orders": {"resource_type": "source", "database": "RAW", "schema": "SHOP", "name": "orders", "identifier": "ORDERS_V2"}
+	},
+	"nodes": {
+		"model.shop.stg_orders": {"resource_type": "model", "database": "ANALYTICS", "schema": "PUBLIC", "name": "stg_orders",
+			"depends_on": {"nodes": ["source.shop.raw.orders"]}},
+		"model.shop.orders": {"resource_type": "model", "database": "ANALYTICS", "schema": "PUBLIC", "name": "orders_model", "alias": "orders",
+			"depends_on": {"nodes": ["model.shop.stg_orders", "seed.shop.regions"]}},
+		"seed.shop.regions": {"resource_type": "seed", "database": "ANALYTICS", "schema": "PUBLIC", "name": "regions"},
+		"test.shop.not_null_orders_id": {"resource_type": "test", "name": "not_null_orders_id",
+			"depends_on": {"nodes": ["model.shop.orders"]}}
+	}
+}`
+
+func TestDBTManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0o600))
+	g, err := LoadDBTManifest(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Relation{
+		{Database: "ANALYTICS", Schema: "PUBLIC", Name: "regions"},
+		{Database: "ANALYTICS", Schema: "PUBLIC", Name: "stg_orders"},
+	}, g.Upstream("ANALYTICS", "PUBLIC", "ORDERS"))
+	assert.Equal(t, []Relation{{Database: "RAW", Schema: "SHOP", Name: "ORDERS_V2"}}, g.Upstream("", "", "STG_ORDERS"))
+	assert.Equal(t, []Relation{{Database: "ANALYTICS", Schema: "PUBLIC", Name: "orders"}}, g.Downstream("ANALYTICS", "PUBLIC", "stg_orders"))
+
+	// Tests are not lineage, and other schemas' tables are not the same table
+	assert.Empty(t, g.Downstream("ANALYTICS", "PUBLIC", "ORDERS"))
+	assert.Empty(t, g.Upstream("ANALYTICS", "STAGING", "ORDERS"))
+
+	_, err = LoadDBTManifest(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com/v1"
+	anthropicAPIVersion     = "2023-06-01"
+)
+
+// anthropicProvider talks to the Anthropic messages API
+type anthropicProvider struct {
+	config *Config
+	client *http.Client
+}
+
+func newAnthropicProvider(cfg *Config, client *http.Client) (Provider, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required for anthropic")
+	}
+	return &anthropicProvider{
+		config: cfg,
+		client: client,
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *anthropicProvider) Name() string {
+	return ProviderAnthropic
+}
+
+type anthropicRequest struct {
+	Model       string    `json:"model"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature *float64  `json:"temperature,omitempty"`
+}
+
+type anthropicResponse struct {
+	Model   string `json:"model"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// Complete generates a completion for the request
+func (p *anthropicProvider) Complete(ctx context.Context, req *Request) (*Response, error) {
+	baseURL := p.config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+
+	body := anthropicRequest{
+		Model:       p.config.Model,
+		System:      req.System,
+		Messages:    req.Messages,
+		MaxTokens:   p.config.maxTokens(),
+		Temperature: p.config.Temperature,
+	}
+	headers := map[string]string{
+		"x-api-key":         p.config.APIKey,
+		"anthropic-version": anthropicAPIVersion,
+	}
+
+	var resp anthropicResponse
+	if err := postJSON(ctx, p.client, strings.TrimSuffix(baseURL, "/")+"/messages", headers, body, &resp); err != nil {
+		return nil, fmt.Errorf("anthropic completion failed: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	return &Response{
+		Text:  text.String(),
+		Model: resp.Model,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.InputTokens,
+			CompletionTokens: resp.Usage.OutputTokens,
+		},
+	}, nil
+}
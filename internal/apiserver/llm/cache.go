@@ -0,0 +1,102 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCacheTTL  = 24 * time.Hour
+	defaultCacheSize = 1000
+)
+
+// cachingProvider serves repeated requests from memory. Prompts embed the
+// schema they describe, so keying on the full request invalidates entries
+// whenever the schema or the prompt template changes.
+type cachingProvider struct {
+	Provider
+
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry struct {
+	key       string
+	response  Response
+	expiresAt time.Time
+}
+
+func newCachingProvider(p Provider, ttl time.Duration, maxSize int) *cachingProvider {
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+	if maxSize <= 0 {
+		maxSize = defaultCacheSize
+	}
+	return &cachingProvider{
+		Provider: p,
+		ttl:      ttl,
+		maxSize:  maxSize,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Complete returns a cached response when an identical request was answered
+// within the TTL, and calls the provider otherwise
+func (p *cachingProvider) Complete(ctx context.Context, req *Request) (*Response, error) {
+	key := requestKey(req)
+
+	p.mu.Lock()
+	if el, ok := p.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			p.lru.MoveToFront(el)
+			resp := entry.response
+			p.mu.Unlock()
+			resp.Cached = true
+			resp.Usage = Usage{}
+			return &resp, nil
+		}
+		p.lru.Remove(el)
+		delete(p.entries, key)
+	}
+	p.mu.Unlock()
+
+	resp, err := p.Provider.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if el, ok := p.entries[key]; ok {
+		p.lru.Remove(el)
+	}
+	p.entries[key] = p.lru.PushFront(&cacheEntry{key: key, response: *resp, expiresAt: time.Now().Add(p.ttl)})
+	for p.lru.Len() > p.maxSize {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return resp, nil
+}
+
+// requestKey hashes everything that influences a completion
+func requestKey(req *Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%s", len(req.System), req.System)
+	for _, m := range req.Messages {
+		fmt.Fprintf(h, "|%s:%d:%s", m.Role, len(m.Content), m.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,137 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Embedder converts texts into embedding vectors
+type Embedder interface {
+	// Embed returns one vector per input text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder creates an embedder based on the configuration. Only the
+// openai, azure_openai and ollama providers offer embeddings.
+func NewEmbedder(cfg *Config) (Embedder, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("embedding configuration is required")
+	}
+	if cfg.Model == "" && cfg.Deployment == "" {
+		return nil, fmt.Errorf("embedding model is required")
+	}
+
+	timeout := defaultTimeout
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedding timeout: %w", err)
+		}
+		timeout = d
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Provider {
+	case ProviderOpenAI, ProviderAzureOpenAI:
+		return &openAIEmbedder{config: cfg, client: client, azure: cfg.Provider == ProviderAzureOpenAI}, nil
+	case ProviderOllama:
+		return &ollamaEmbedder{config: cfg, client: client}, nil
+	default:
+		return nil, fmt.Errorf("provider %s does not support embeddings", cfg.Provider)
+	}
+}
+
+type openAIEmbedder struct {
+	config *Config
+	client *http.Client
+	azure  bool
+}
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed returns one vector per input text, in order
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body := openAIEmbeddingRequest{Input: texts}
+	headers := make(map[string]string)
+
+	var url string
+	if e.azure {
+		apiVersion := e.config.APIVersion
+		if apiVersion == "" {
+			apiVersion = defaultAzureAPIVersion
+		}
+		url = fmt.Sprintf("%s/openai/deployments/%s/embeddings?api-version=%s",
+			strings.TrimSuffix(e.config.BaseURL, "/"), e.config.Deployment, apiVersion)
+		headers["api-key"] = e.config.APIKey
+	} else {
+		baseURL := e.config.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		url = strings.TrimSuffix(baseURL, "/") + "/embeddings"
+		headers["Authorization"] = "Bearer " + e.config.APIKey
+		body.Model = e.config.Model
+	}
+
+	var resp openAIEmbeddingResponse
+	if err := postJSON(ctx, e.client, url, headers, body, &resp); err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors for %d inputs", len(resp.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding response has invalid index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
+
+type ollamaEmbedder struct {
+	config *Config
+	client *http.Client
+}
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embed returns one vector per input text, in order
+func (e *ollamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	baseURL := e.config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+
+	var resp ollamaEmbedResponse
+	body := ollamaEmbedRequest{Model: e.config.Model, Input: texts}
+	if err := postJSON(ctx, e.client, strings.TrimSuffix(baseURL, "/")+"/api/embed", nil, body, &resp); err != nil {
+		return nil, fmt.Errorf("embedding request failed: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors for %d inputs", len(resp.Embeddings), len(texts))
+	}
+	return resp.Embeddings, nil
+}
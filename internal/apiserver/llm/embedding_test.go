@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedders(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		path     string
+		response string
+	}{
+		{
+			// Vectors come back out of order and are placed by index
+			name:     "openai",
+			config:   Config{Provider: ProviderOpenAI, Model: "text-embedding-3-small", APIKey: "key"},
+			path:     "/embeddings",
+			response: `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`,
+		},
+		{
+			name:     "ollama",
+			config:   Config{Provider: ProviderOllama, Model: "nomic-embed-text"},
+			path:     "/api/embed",
+			response: `{"embeddings":[[1,0],[0,1]]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.path, r.URL.Path)
+				var body map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, []interface{}{"orders", "customers"}, body["input"].([]interface{})[:2])
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			tt.config.BaseURL = srv.URL
+			embedder, err := NewEmbedder(&tt.config)
+			require.NoError(t, err)
+			vectors, err := embedder.Embed(context.Background(), []string{"orders", "customers"})
+			require.NoError(t, err)
+			assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+
+			// Responses missing vectors fail
+			_, err = embedder.Embed(context.Background(), []string{"orders", "customers", "invoices"})
+			assert.ErrorContains(t, err, "has 2 vectors for 3 inputs")
+		})
+	}
+
+	_, err := NewEmbedder(&Config{Provider: ProviderAnthropic, Model: "claude"})
+	assert.ErrorContains(t, err, "does not support embeddings")
+	_, err = NewEmbedder(&Config{Provider: ProviderOpenAI})
+	assert.ErrorContains(t, err, "model is required")
+}
@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	ProviderOpenAI      = "openai"
+	ProviderAzureOpenAI = "azure_openai"
+	ProviderAnthropic   = "anthropic"
+	ProviderOllama      = "ollama"
+
+	defaultTimeout   = 60 * time.Second
+	defaultMaxTokens = 1024
+)
+
+// Role of a chat message author
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is a single chat message
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a provider-agnostic completion request
+type Request struct {
+	System   string
+	Messages []Message
+}
+
+// Usage reports the tokens consumed by a completion
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Response is a provider-agnostic completion response
+type Response struct {
+	Text  string `json:"text"`
+	Model string `json:"model"`
+	Usage Usage  `json:"usage"`
+
+	// Cached is set when the response was served from the response cache
+	Cached bool `json:"cached,omitempty"`
+}
+
+// Provider generates text completions
+type Provider interface {
+	// Name returns the provider identifier
+	Name() string
+
+	// Complete generates a completion for the request
+	Complete(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Config holds the configuration for an LLM provider
+type Config struct {
+	// Provider is one of openai, azure_openai, anthropic or ollama
+	Provider    string   `json:"provider"`
+	Model       string   `json:"model"`
+	APIKey      string   `json:"api_key,omitempty"`
+	BaseURL     string   `json:"base_url,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty"`
+	Timeout     string   `json:"timeout,omitempty"`
+
+	// Azure OpenAI specific settings
+	Deployment string `json:"deployment,omitempty"`
+	APIVersion string `json:"api_version,omitempty"`
+
+	// Pricing overrides the built-in price list used for cost estimates
+	Pricing *Pricing `json:"pricing,omitempty"`
+
+	// Response cache settings. Identical requests are answered from memory
+	// for CacheTTL (default 24h) unless DisableCache is set.
+	DisableCache bool   `json:"disable_cache,omitempty"`
+	CacheTTL     string `json:"cache_ttl,omitempty"`
+	CacheSize    int    `json:"cache_size,omitempty"`
+}
+
+// NewProvider creates a provider based on the configuration
+func NewProvider(cfg *Config) (Provider, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("llm configuration is required")
+	}
+
+	timeout := defaultTimeout
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm timeout: %w", err)
+		}
+		timeout = d
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Provider {
+	case ProviderOpenAI:
+		return newOpenAIProvider(cfg, client, false)
+	case ProviderAzureOpenAI:
+		return newOpenAIProvider(cfg, client, true)
+	case ProviderAnthropic:
+		return newAnthropicProvider(cfg, client)
+	case ProviderOllama:
+		return newOllamaProvider(cfg, client)
+	default:
+		return nil, fmt.Errorf("unsupported llm provider: %s", cfg.Provider)
+	}
+}
+
+// Instrument wraps a provider with the response cache configured in cfg and,
+// when meter is set, with token and cost accounting. Cache hits are counted
+// by the meter but consume no tokens.
+func Instrument(p Provider, cfg *Config, meter *Meter) (Provider, error) {
+	if !cfg.DisableCache {
+		var ttl time.Duration
+		if cfg.CacheTTL != "" {
+			d, err := time.ParseDuration(cfg.CacheTTL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid llm cache_ttl: %w", err)
+			}
+			ttl = d
+		}
+		p = newCachingProvider(p, ttl, cfg.CacheSize)
+	}
+	if meter != nil {
+		p = &meteredProvider{Provider: p, config: cfg, meter: meter}
+	}
+	return p, nil
+}
+
+// maxTokens returns the configured completion limit or the default
+func (c *Config) maxTokens() int {
+	if c.MaxTokens > 0 {
+		return c.MaxTokens
+	}
+	return defaultMaxTokens
+}
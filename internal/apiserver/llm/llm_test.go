@@ -0,0 +1,142 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviders_Complete(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		path     string
+		response string
+		wantText string
+		check    func(t *testing.T, r *http.Request, body map[string]interface{})
+	}{
+		{
+			name:     "openai",
+			config:   Config{Provider: ProviderOpenAI, Model: "gpt-4o-mini", APIKey: "key"},
+			path:     "/chat/completions",
+			response: `{"model":"gpt-4o-mini","choices":[{"message":{"role":"assistant","content":"hello"}}],"usage":{"prompt_tokens":3,"completion_tokens":1}}`,
+			wantText: "hello",
+			check: func(t *testing.T, r *http.Request, body map[string]interface{}) {
+				assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+				assert.Equal(t, "gpt-4o-mini", body["model"])
+				assert.Len(t, body["messages"], 2)
+			},
+		},
+		{
+			name:     "azure openai",
+			config:   Config{Provider: ProviderAzureOpenAI, Deployment: "desc", APIKey: "key"},
+			path:     "/openai/deployments/desc/chat/completions",
+			response: `{"choices":[{"message":{"role":"assistant","content":"hi"}}]}`,
+			wantText: "hi",
+			check: func(t *testing.T, r *http.Request, body map[string]interface{}) {
+				assert.Equal(t, "key", r.Header.Get("api-key"))
+				assert.Equal(t, defaultAzureAPIVersion, r.URL.Query().Get("api-version"))
+			},
+		},
+		{
+			name:     "anthropic",
+			config:   Config{Provider: ProviderAnthropic, Model: "claude", APIKey: "key"},
+			path:     "/messages",
+			response: `{"model":"claude","content":[{"type":"text","text":"hey"}],"usage":{"input_tokens":3,"output_tokens":1}}`,
+			wantText: "hey",
+			check: func(t *testing.T, r *http.Request, body map[string]interface{}) {
+				assert.Equal(t, "key", r.Header.Get("x-api-key"))
+				assert.Equal(t, "be brief", body["system"])
+			},
+		},
+		{
+			name:     "ollama",
+			config:   Config{Provider: ProviderOllama, Model: "llama3"},
+			path:     "/api/chat",
+			response: `{"model":"llama3","message":{"role":"assistant","content":"yo"},"prompt_eval_count":3,"eval_count":1}`,
+			wantText: "yo",
+			check: func(t *testing.T, r *http.Request, body map[string]interface{}) {
+				assert.Equal(t, false, body["stream"])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.path, r.URL.Path)
+				var body map[string]interface{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				tt.check(t, r, body)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			cfg := tt.config
+			cfg.BaseURL = server.URL
+			provider, err := NewProvider(&cfg)
+			require.NoError(t, err)
+
+			resp, err := provider.Complete(context.Background(), &Request{
+				System:   "be brief",
+				Messages: []Message{{Role: RoleUser, Content: "hi"}},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantText, resp.Text)
+		})
+	}
+}
+
+func TestNewProvider_Unsupported(t *testing.T) {
+	_, err := NewProvider(&Config{Provider: "unknown"})
+	assert.Error(t, err)
+}
+
+type stubProvider struct {
+	calls int
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Complete(_ context.Context, req *Request) (*Response, error) {
+	p.calls++
+	return &Response{
+		Text:  "answer to " + req.Messages[0].Content,
+		Model: "gpt-4o-mini",
+		Usage: Usage{PromptTokens: 1000000, CompletionTokens: 1000000},
+	}, nil
+}
+
+func TestInstrument_CachesAndMeters(t *testing.T) {
+	stub := &stubProvider{}
+	meter := NewMeter()
+	p, err := Instrument(stub, &Config{Provider: ProviderOpenAI, Model: "gpt-4o-mini"}, meter)
+	require.NoError(t, err)
+
+	req := &Request{System: "sys", Messages: []Message{{Role: RoleUser, Content: "q1"}}}
+	first, err := p.Complete(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, first.Cached)
+
+	second, err := p.Complete(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, second.Cached)
+	assert.Equal(t, first.Text, second.Text)
+	assert.Equal(t, 1, stub.calls)
+
+	_, err = p.Complete(context.Background(), &Request{System: "sys", Messages: []Message{{Role: RoleUser, Content: "q2"}}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stub.calls)
+
+	stats := meter.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Requests)
+	assert.Equal(t, int64(1), stats[0].CacheHits)
+	assert.Equal(t, int64(2000000), stats[0].PromptTokens)
+	assert.InDelta(t, 2*(0.15+0.60), stats[0].EstimatedCostUSD, 1e-9)
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+// ollamaProvider talks to a local Ollama server
+type ollamaProvider struct {
+	config *Config
+	client *http.Client
+}
+
+func newOllamaProvider(cfg *Config, client *http.Client) (Provider, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required for ollama")
+	}
+	return &ollamaProvider{
+		config: cfg,
+		client: client,
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *ollamaProvider) Name() string {
+	return ProviderOllama
+}
+
+type ollamaChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []Message              `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Model           string  `json:"model"`
+	Message         Message `json:"message"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+}
+
+// Complete generates a completion for the request
+func (p *ollamaProvider) Complete(ctx context.Context, req *Request) (*Response, error) {
+	baseURL := p.config.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+
+	body := ollamaChatRequest{
+		Model: p.config.Model,
+		Options: map[string]interface{}{
+			"num_predict": p.config.maxTokens(),
+		},
+	}
+	if p.config.Temperature != nil {
+		body.Options["temperature"] = *p.config.Temperature
+	}
+	if req.System != "" {
+		body.Messages = append(body.Messages, Message{Role: "system", Content: req.System})
+	}
+	body.Messages = append(body.Messages, req.Messages...)
+
+	var resp ollamaChatResponse
+	if err := postJSON(ctx, p.client, strings.TrimSuffix(baseURL, "/")+"/api/chat", nil, body, &resp); err != nil {
+		return nil, fmt.Errorf("ollama completion failed: %w", err)
+	}
+
+	return &Response{
+		Text:  resp.Message.Content,
+		Model: resp.Model,
+		Usage: Usage{
+			PromptTokens:     resp.PromptEvalCount,
+			CompletionTokens: resp.EvalCount,
+		},
+	}, nil
+}
@@ -0,0 +1,118 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIBaseURL   = "https://api.openai.com/v1"
+	defaultAzureAPIVersion = "2024-06-01"
+)
+
+// openAIProvider talks to the OpenAI chat completions API, or its Azure flavour
+type openAIProvider struct {
+	config *Config
+	client *http.Client
+	azure  bool
+}
+
+func newOpenAIProvider(cfg *Config, client *http.Client, azure bool) (Provider, error) {
+	if azure {
+		if cfg.BaseURL == "" || cfg.Deployment == "" {
+			return nil, fmt.Errorf("base_url and deployment are required for azure_openai")
+		}
+	} else if cfg.Model == "" {
+		return nil, fmt.Errorf("model is required for openai")
+	}
+
+	return &openAIProvider{
+		config: cfg,
+		client: client,
+		azure:  azure,
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *openAIProvider) Name() string {
+	if p.azure {
+		return ProviderAzureOpenAI
+	}
+	return ProviderOpenAI
+}
+
+type openAIChatRequest struct {
+	Model       string          `json:"model,omitempty"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Complete generates a completion for the request
+func (p *openAIProvider) Complete(ctx context.Context, req *Request) (*Response, error) {
+	body := openAIChatRequest{
+		Temperature: p.config.Temperature,
+		MaxTokens:   p.config.maxTokens(),
+	}
+	if req.System != "" {
+		body.Messages = append(body.Messages, openAIMessage{Role: "system", Content: req.System})
+	}
+	for _, m := range req.Messages {
+		body.Messages = append(body.Messages, openAIMessage{Role: string(m.Role), Content: m.Content})
+	}
+
+	var url string
+	headers := make(map[string]string)
+	if p.azure {
+		apiVersion := p.config.APIVersion
+		if apiVersion == "" {
+			apiVersion = defaultAzureAPIVersion
+		}
+		url = fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			strings.TrimSuffix(p.config.BaseURL, "/"), p.config.Deployment, apiVersion)
+		headers["api-key"] = p.config.APIKey
+	} else {
+		baseURL := p.config.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		url = strings.TrimSuffix(baseURL, "/") + "/chat/completions"
+		headers["Authorization"] = "Bearer " + p.config.APIKey
+		body.Model = p.config.Model
+	}
+
+	var resp openAIChatResponse
+	if err := postJSON(ctx, p.client, url, headers, body, &resp); err != nil {
+		return nil, fmt.Errorf("%s completion failed: %w", p.Name(), err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("%s completion returned no choices", p.Name())
+	}
+
+	return &Response{
+		Text:  resp.Choices[0].Message.Content,
+		Model: resp.Model,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+		},
+	}, nil
+}
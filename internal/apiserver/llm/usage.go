@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// modelPrices are the list prices in USD per million tokens, matched by the
+// longest model name prefix. Config overrides take precedence.
+var modelPrices = map[string]Pricing{
+	"gpt-4o":            {InputPerMillion: 2.50, OutputPerMillion: 10.00},
+	"gpt-4o-mini":       {InputPerMillion: 0.15, OutputPerMillion: 0.60},
+	"gpt-4.1":           {InputPerMillion: 2.00, OutputPerMillion: 8.00},
+	"gpt-4.1-mini":      {InputPerMillion: 0.40, OutputPerMillion: 1.60},
+	"gpt-4.1-nano":      {InputPerMillion: 0.10, OutputPerMillion: 0.40},
+	"claude-3-5-haiku":  {InputPerMillion: 0.80, OutputPerMillion: 4.00},
+	"claude-3-5-sonnet": {InputPerMillion: 3.00, OutputPerMillion: 15.00},
+	"claude-3-7-sonnet": {InputPerMillion: 3.00, OutputPerMillion: 15.00},
+	"claude-sonnet-4":   {InputPerMillion: 3.00, OutputPerMillion: 15.00},
+	"claude-opus-4":     {InputPerMillion: 15.00, OutputPerMillion: 75.00},
+}
+
+// Pricing is the cost of a model in USD per million tokens
+type Pricing struct {
+	InputPerMillion  float64 `json:"input_per_million"`
+	OutputPerMillion float64 `json:"output_per_million"`
+}
+
+// UsageStats aggregates the completions made with one provider and model
+type UsageStats struct {
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Requests         int64   `json:"requests"`
+	CacheHits        int64   `json:"cache_hits"`
+	Errors           int64   `json:"errors"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// Meter accumulates token usage and estimated cost per provider and model
+type Meter struct {
+	mu    sync.Mutex
+	stats map[string]*UsageStats
+}
+
+// NewMeter creates an empty meter
+func NewMeter() *Meter {
+	return &Meter{
+		stats: make(map[string]*UsageStats),
+	}
+}
+
+// Snapshot returns a copy of the accumulated usage, sorted by provider and model
+func (m *Meter) Snapshot() []UsageStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]UsageStats, 0, len(m.stats))
+	for _, s := range m.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Provider == stats[j].Provider {
+			return stats[i].Model < stats[j].Model
+		}
+		return stats[i].Provider < stats[j].Provider
+	})
+	return stats
+}
+
+func (m *Meter) entry(provider, model string) *UsageStats {
+	key := provider + "/" + model
+	s, ok := m.stats[key]
+	if !ok {
+		s = &UsageStats{Provider: provider, Model: model}
+		m.stats[key] = s
+	}
+	return s
+}
+
+// meteredProvider records the usage of every completion in a Meter
+type meteredProvider struct {
+	Provider
+
+	config *Config
+	meter  *Meter
+}
+
+// Complete generates a completion and records its usage
+func (p *meteredProvider) Complete(ctx context.Context, req *Request) (*Response, error) {
+	resp, err := p.Provider.Complete(ctx, req)
+
+	model := p.config.modelName()
+	if resp != nil && resp.Model != "" {
+		model = resp.Model
+	}
+
+	p.meter.mu.Lock()
+	defer p.meter.mu.Unlock()
+	s := p.meter.entry(p.Name(), model)
+	s.Requests++
+	if err != nil {
+		s.Errors++
+		return nil, err
+	}
+	if resp.Cached {
+		s.CacheHits++
+		return resp, nil
+	}
+	s.PromptTokens += int64(resp.Usage.PromptTokens)
+	s.CompletionTokens += int64(resp.Usage.CompletionTokens)
+	price := p.config.pricing(model)
+	s.EstimatedCostUSD += (float64(resp.Usage.PromptTokens)*price.InputPerMillion +
+		float64(resp.Usage.CompletionTokens)*price.OutputPerMillion) / 1e6
+	return resp, nil
+}
+
+// pricing returns the configured price or the list price of the model
+func (c *Config) pricing(model string) Pricing {
+	if c.Pricing != nil {
+		return *c.Pricing
+	}
+	if c.Provider == ProviderOllama {
+		return Pricing{}
+	}
+
+	var best string
+	for prefix := range modelPrices {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	return modelPrices[best]
+}
+
+// modelName returns the configured model, or the deployment for Azure
+func (c *Config) modelName() string {
+	if c.Model != "" {
+		return c.Model
+	}
+	return c.Deployment
+}
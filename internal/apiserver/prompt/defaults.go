@@ -0,0 +1,98 @@
+package prompt
+
+// Names of the templates used by the gateway's LLM features
+const (
+	NameMetadataEnrichment = "metadata_enrichment"
+	NameNLToSQL            = "nl_to_sql"
+	NameResultSummary      = "result_summary"
+
+	// Templates served to MCP clients as prompts
+	NameAnalyzeTable       = "analyze_table"
+	NameProfileDataQuality = "profile_data_quality"
+)
+
+// defaults are the built-in templates, registered as version 1 of each name
+// unless replaced by a configured file
+var defaults = map[string]string{
+	NameMetadataEnrichment: `You are a data catalog assistant. Given the schema and sample rows of a database table, ` +
+		`document it for AI agents that will call generated API tools against it. ` +
+		`Respond with a single JSON object and nothing else, in this shape:
+{
+  "table_description": "3-5 sentences on what the table contains, what a row represents and how it is used",
+  "columns": {"<column name>": "one sentence describing the column, its units or allowed values"},
+  "tools": {
+    "list": "when to use the tool that lists rows of this table",
+    "get": "when to use the tool that fetches one row by primary key",
+    "create": "when to use the tool that inserts a row",
+    "update": "when to use the tool that updates a row",
+    "delete": "when to use the tool that deletes a row"
+  }
+}
+Describe every column listed. Do not invent columns.
+{{- if language}}
+Write all descriptions in {{language}}.
+{{- end}}`,
+
+	NameNLToSQL: `You translate questions into a single read-only {{.Dialect}} SQL query.
+Only use the tables and columns below. Quote identifiers exactly as listed.
+Never modify data: no INSERT, UPDATE, DELETE, MERGE or DDL.
+Limit results to {{.MaxRows}} rows unless the question asks for an aggregate.
+Respond with the SQL query only, without explanation or code fences.
+
+Schema:
+{{.Schema}}`,
+
+	NameResultSummary: `You summarize query results for the person who asked the question.
+Answer the question in 1-3 sentences using only the data provided. ` +
+		`If the result is empty or does not answer the question, say so.
+{{- if language}} Answer in {{language}}.{{end}}
+
+Question: {{.Question}}
+SQL: {{.SQL}}
+Rows ({{.RowCount}} total, first rows shown):
+{{.Rows}}`,
+
+	NameAnalyzeTable: `Analyze the {{.Table.Name}} table of this {{.Dialect}} database.
+{{- with .Table.VerboseDescription}}
+{{.}}
+{{- else}}{{with .Table.Description}}
+{{.}}
+{{- end}}{{end}}
+
+It has {{.Table.RowCount}} rows and these columns:
+{{- range .Table.Columns}}
+- {{.Name}} ({{.SQLType}}){{if .PrimaryKey}}, primary key{{end}}{{with .Default}}, default {{.}}{{end}}{{if .References}}, references {{.References}}{{end}}
+{{- with .VerboseDescription}}: {{.}}{{else}}{{with .Description}}: {{.}}{{end}}{{end}}
+{{- end}}
+{{- if .Table.SampleData}}
+
+Sample rows:
+{{- range .Table.SampleJSON}}
+- {{.}}
+{{- end}}
+{{- end}}
+
+Using the query tool, explain what a row represents, summarize the distribution of the most important columns, ` +
+		`point out notable trends or outliers and suggest follow-up questions. Show the SQL you run.
+{{- if language}} Answer in {{language}}.{{end}}`,
+
+	NameProfileDataQuality: `Profile the data quality of the {{.Table.Name}} table ({{.Table.RowCount}} rows).
+
+Start by running this query with the query tool; it counts rows, nulls and distinct values per column:
+{{.ProfileSQL}}
+
+Then check:
+{{- range .Table.Columns}}
+{{- if .PrimaryKey}}
+- {{.Name}} is the primary key: look for duplicates and nulls
+{{- end}}
+{{- if .References}}
+- {{.Name}} references {{.References}}: look for orphaned values
+{{- end}}
+{{- end}}
+- columns that are mostly null, constant, or have suspicious placeholder values
+- numeric and date columns with out-of-range minimums or maximums
+
+Report each issue with the affected column, how many rows it touches and the SQL that shows it.
+{{- if language}} Answer in {{language}}.{{end}}`,
+}
@@ -0,0 +1,340 @@
+package prompt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Config lets operators replace the built-in templates with their own files.
+// Templates are Go text/templates; besides the data each feature passes in
+// (see the built-in templates), they can call {{language}} and {{var "name"}}.
+type Config struct {
+	// Dir holds template files named <template name>.tmpl
+	Dir string `json:"dir,omitempty"`
+
+	// Templates maps template names to files, overriding Dir
+	Templates map[string]string `json:"templates,omitempty"`
+
+	// Language the LLM should write descriptions and answers in
+	Language string `json:"language,omitempty"`
+
+	// Variables are organization-specific values available through {{var "name"}}
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+// Template is one version of a prompt template
+type Template struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"`
+	Text      string    `json:"text"`
+	Comment   string    `json:"comment,omitempty"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Active    bool      `json:"active"`
+}
+
+// templateRow is the persisted form of a template version
+type templateRow struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	Name      string
+	Version   int
+	Text      string
+	Comment   string
+	Author    string
+	CreatedAt time.Time
+	Active    bool
+}
+
+// TableName overrides the table name used by templateRow
+func (templateRow) TableName() string {
+	return "prompt_templates"
+}
+
+// Registry holds the versions of every prompt template and tracks which one
+// is active. Built-in templates are version 1; edits are persisted to the
+// state store when one is configured, otherwise they live in memory.
+type Registry struct {
+	db    *gorm.DB
+	funcs template.FuncMap
+
+	mu       sync.RWMutex
+	versions map[string][]*Template
+	active   map[string]int
+}
+
+// NewRegistry creates a registry seeded with the built-in templates, or the
+// files configured in cfg, and any versions previously saved in db
+func NewRegistry(db *gorm.DB, cfg *Config) (*Registry, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	r := &Registry{
+		db:       db,
+		funcs:    templateFuncs(cfg),
+		versions: make(map[string][]*Template),
+		active:   make(map[string]int),
+	}
+
+	base, err := loadTemplateFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	for name, text := range defaults {
+		t := &Template{Name: name, Version: 1, Text: text, Comment: "built-in"}
+		if file, ok := base[name]; ok {
+			t.Text = file.text
+			t.Comment = "file: " + file.path
+			if _, err := r.parse(name, t.Text); err != nil {
+				return nil, fmt.Errorf("invalid prompt template %s: %w", file.path, err)
+			}
+		}
+		r.versions[name] = []*Template{t}
+		r.active[name] = 1
+	}
+
+	if db == nil {
+		return r, nil
+	}
+
+	var rows []templateRow
+	if err := db.Order("name, version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+	for _, row := range rows {
+		if _, ok := r.versions[row.Name]; !ok {
+			continue
+		}
+		r.versions[row.Name] = append(r.versions[row.Name], &Template{
+			Name:      row.Name,
+			Version:   row.Version,
+			Text:      row.Text,
+			Comment:   row.Comment,
+			Author:    row.Author,
+			CreatedAt: row.CreatedAt,
+		})
+		if row.Active {
+			r.active[row.Name] = row.Version
+		}
+	}
+	return r, nil
+}
+
+// Get returns the active version of a template
+func (r *Registry) Get(name string) (*Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	version, ok := r.active[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt template: %s", name)
+	}
+	return r.lookup(name, version), nil
+}
+
+// Render executes the active version of a template with data, returning the
+// rendered text and the version used
+func (r *Registry) Render(name string, data interface{}) (string, *Template, error) {
+	t, err := r.Get(name)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpl, err := r.parse(name, t.Text)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse prompt template %s v%d: %w", name, t.Version, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", nil, fmt.Errorf("failed to render prompt template %s v%d: %w", name, t.Version, err)
+	}
+	return buf.String(), t, nil
+}
+
+// List returns the active version of every template
+func (r *Registry) List() []*Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]*Template, 0, len(r.active))
+	for name, version := range r.active {
+		templates = append(templates, r.lookup(name, version))
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// Versions returns every version of a template, oldest first
+func (r *Registry) Versions(name string) ([]*Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions, ok := r.versions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt template: %s", name)
+	}
+	templates := make([]*Template, 0, len(versions))
+	for _, v := range versions {
+		templates = append(templates, r.withActive(v))
+	}
+	return templates, nil
+}
+
+// Update stores a new version of a template and makes it active
+func (r *Registry) Update(name, text, comment, author string) (*Template, error) {
+	if _, err := r.parse(name, text); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions, ok := r.versions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt template: %s", name)
+	}
+	t := &Template{
+		Name:      name,
+		Version:   versions[len(versions)-1].Version + 1,
+		Text:      text,
+		Comment:   comment,
+		Author:    author,
+		CreatedAt: time.Now(),
+	}
+
+	if r.db != nil {
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&templateRow{}).Where("name = ?", name).Update("active", false).Error; err != nil {
+				return err
+			}
+			return tx.Create(&templateRow{
+				Name:      t.Name,
+				Version:   t.Version,
+				Text:      t.Text,
+				Comment:   t.Comment,
+				Author:    t.Author,
+				CreatedAt: t.CreatedAt,
+				Active:    true,
+			}).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save prompt template: %w", err)
+		}
+	}
+
+	r.versions[name] = append(versions, t)
+	r.active[name] = t.Version
+	return r.withActive(t), nil
+}
+
+// Activate makes an existing version of a template active, e.g. to roll back
+// an edit
+func (r *Registry) Activate(name string, version int) (*Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.versions[name]; !ok {
+		return nil, fmt.Errorf("unknown prompt template: %s", name)
+	}
+	t := r.lookup(name, version)
+	if t == nil {
+		return nil, fmt.Errorf("prompt template %s has no version %d", name, version)
+	}
+
+	if r.db != nil {
+		// The built-in version isn't stored; no active row means version 1
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&templateRow{}).Where("name = ?", name).Update("active", false).Error; err != nil {
+				return err
+			}
+			return tx.Model(&templateRow{}).Where("name = ? AND version = ?", name, version).Update("active", true).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save prompt template: %w", err)
+		}
+	}
+
+	r.active[name] = version
+	return r.withActive(t), nil
+}
+
+// parse compiles a template with the registry's functions
+func (r *Registry) parse(name, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=zero").Funcs(r.funcs).Parse(text)
+}
+
+// lookup finds a version of a template; callers must hold r.mu
+func (r *Registry) lookup(name string, version int) *Template {
+	for _, t := range r.versions[name] {
+		if t.Version == version {
+			return r.withActive(t)
+		}
+	}
+	return nil
+}
+
+// withActive returns a copy of t with its Active flag set; callers must hold r.mu
+func (r *Registry) withActive(t *Template) *Template {
+	c := *t
+	c.Active = r.active[t.Name] == t.Version
+	return &c
+}
+
+// templateFile is a template loaded from disk
+type templateFile struct {
+	path string
+	text string
+}
+
+// loadTemplateFiles reads the template files configured in cfg
+func loadTemplateFiles(cfg *Config) (map[string]templateFile, error) {
+	paths := make(map[string]string)
+	if cfg.Dir != "" {
+		for name := range defaults {
+			path := filepath.Join(cfg.Dir, name+".tmpl")
+			if _, err := os.Stat(path); err == nil {
+				paths[name] = path
+			}
+		}
+	}
+	for name, path := range cfg.Templates {
+		if _, ok := defaults[name]; !ok {
+			return nil, fmt.Errorf("unknown prompt template: %s", name)
+		}
+		paths[name] = path
+	}
+
+	files := make(map[string]templateFile, len(paths))
+	for name, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt template: %w", err)
+		}
+		files[name] = templateFile{path: path, text: string(data)}
+	}
+	return files, nil
+}
+
+// templateFuncs returns the functions available to every template
+func templateFuncs(cfg *Config) template.FuncMap {
+	return template.FuncMap{
+		"language": func() string { return cfg.Language },
+		"var":      func(name string) string { return cfg.Variables[name] },
+		"join":     strings.Join,
+		"json": func(v interface{}) string {
+			data, _ := json.Marshal(v)
+			return string(data)
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+}
@@ -0,0 +1,65 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_FileOverridesAndVariables(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, NameNLToSQL+".tmpl"),
+		[]byte(`{{var "org"}} {{.Dialect}} in {{language}}`), 0644))
+
+	r, err := NewRegistry(nil, &Config{
+		Dir:       dir,
+		Language:  "German",
+		Variables: map[string]string{"org": "Acme"},
+	})
+	require.NoError(t, err)
+
+	text, tmpl, err := r.Render(NameNLToSQL, map[string]string{"Dialect": "Snowflake"})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme Snowflake in German", text)
+	assert.Equal(t, 1, tmpl.Version)
+
+	summary, _, err := r.Render(NameResultSummary, map[string]interface{}{"Question": "q"})
+	require.NoError(t, err)
+	assert.Contains(t, summary, "Answer in German.")
+}
+
+func TestRegistry_UnknownTemplateFile(t *testing.T) {
+	_, err := NewRegistry(nil, &Config{Templates: map[string]string{"nope": "nope.tmpl"}})
+	assert.Error(t, err)
+}
+
+func TestRegistry_UpdateAndRollback(t *testing.T) {
+	r, err := NewRegistry(nil, nil)
+	require.NoError(t, err)
+
+	_, err = r.Update(NameNLToSQL, "{{.Broken", "", "admin")
+	assert.Error(t, err)
+
+	updated, err := r.Update(NameNLToSQL, "v2 {{.Question}}", "shorter", "admin")
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.Version)
+	assert.True(t, updated.Active)
+
+	text, _, err := r.Render(NameNLToSQL, map[string]string{"Question": "why"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2 why", text)
+
+	_, err = r.Activate(NameNLToSQL, 1)
+	require.NoError(t, err)
+	active, err := r.Get(NameNLToSQL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, active.Version)
+
+	versions, err := r.Versions(NameNLToSQL)
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.False(t, versions[1].Active)
+}
@@ -0,0 +1,168 @@
+package provenance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind identifies the step a node represents in a session's tool-call chain
+type Kind string
+
+const (
+	KindQuestion  Kind = "question"
+	KindToolCall  Kind = "tool_call"
+	KindSQL       Kind = "sql"
+	KindExecution Kind = "execution"
+	KindSummary   Kind = "summary"
+)
+
+const defaultMaxNodesPerSession = 1000
+
+// Node is a single step in the provenance graph
+type Node struct {
+	ID        string                 `json:"id"`
+	SessionID string                 `json:"session_id"`
+	ParentID  string                 `json:"parent_id,omitempty"`
+	Kind      Kind                   `json:"kind"`
+	Label     string                 `json:"label"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	StartedAt time.Time              `json:"started_at"`
+	Duration  time.Duration          `json:"duration_ns"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// Edge links a node to the step that produced it
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Trace is the provenance graph of a session
+type Trace struct {
+	SessionID string  `json:"session_id"`
+	Nodes     []*Node `json:"nodes"`
+	Edges     []Edge  `json:"edges"`
+}
+
+type parentKey struct{}
+
+type parentRef struct {
+	sessionID string
+	nodeID    string
+}
+
+// WithSession returns a context whose recorded nodes belong to the session
+func WithSession(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, parentKey{}, parentRef{sessionID: sessionID})
+}
+
+// Tracker records provenance nodes per session in memory
+type Tracker struct {
+	mu       sync.RWMutex
+	sessions map[string][]*Node
+	maxNodes int
+}
+
+// NewTracker creates a new provenance tracker
+func NewTracker(maxNodesPerSession int) *Tracker {
+	if maxNodesPerSession <= 0 {
+		maxNodesPerSession = defaultMaxNodesPerSession
+	}
+	return &Tracker{
+		sessions: make(map[string][]*Node),
+		maxNodes: maxNodesPerSession,
+	}
+}
+
+// Start records a node as a child of the node carried by ctx and returns a
+// context in which subsequent nodes become its children. Nothing is recorded
+// when ctx carries no session.
+func (t *Tracker) Start(ctx context.Context, kind Kind, label string, data map[string]interface{}) (context.Context, *Node) {
+	ref, ok := ctx.Value(parentKey{}).(parentRef)
+	if !ok || ref.sessionID == "" {
+		return ctx, nil
+	}
+
+	node := &Node{
+		ID:        uuid.New().String(),
+		SessionID: ref.sessionID,
+		ParentID:  ref.nodeID,
+		Kind:      kind,
+		Label:     label,
+		Data:      data,
+		StartedAt: time.Now(),
+	}
+
+	t.mu.Lock()
+	nodes := append(t.sessions[ref.sessionID], node)
+	if len(nodes) > t.maxNodes {
+		nodes = nodes[len(nodes)-t.maxNodes:]
+	}
+	t.sessions[ref.sessionID] = nodes
+	t.mu.Unlock()
+
+	return context.WithValue(ctx, parentKey{}, parentRef{sessionID: ref.sessionID, nodeID: node.ID}), node
+}
+
+// Finish completes a node started with Start, attaching the outcome
+func (t *Tracker) Finish(node *Node, err error, data map[string]interface{}) {
+	if node == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	node.Duration = time.Since(node.StartedAt)
+	if err != nil {
+		node.Error = err.Error()
+	}
+	if len(data) > 0 {
+		if node.Data == nil {
+			node.Data = make(map[string]interface{}, len(data))
+		}
+		for k, v := range data {
+			node.Data[k] = v
+		}
+	}
+}
+
+// Trace returns the provenance graph recorded for a session
+func (t *Tracker) Trace(sessionID string) (*Trace, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	nodes, ok := t.sessions[sessionID]
+	if !ok {
+		return nil, false
+	}
+
+	trace := &Trace{
+		SessionID: sessionID,
+		Nodes:     make([]*Node, 0, len(nodes)),
+	}
+	for _, n := range nodes {
+		copied := *n
+		if n.Data != nil {
+			copied.Data = make(map[string]interface{}, len(n.Data))
+			for k, v := range n.Data {
+				copied.Data[k] = v
+			}
+		}
+		trace.Nodes = append(trace.Nodes, &copied)
+		if n.ParentID != "" {
+			trace.Edges = append(trace.Edges, Edge{From: n.ParentID, To: n.ID})
+		}
+	}
+	return trace, true
+}
+
+// Forget drops all nodes recorded for a session
+func (t *Tracker) Forget(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, sessionID)
+}
@@ -0,0 +1,48 @@
+package provenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(3)
+
+	// Nothing is recorded outside a session
+	_, node := tracker.Start(context.Background(), KindToolCall, "query", nil)
+	assert.Nil(t, node)
+
+	ctx := WithSession(context.Background(), "s1")
+	ctx, call := tracker.Start(ctx, KindToolCall, "query", map[string]interface{}{"arguments": "x"})
+	_, exec := tracker.Start(ctx, KindExecution, "execute", nil)
+	tracker.Finish(exec, errors.New("timeout"), map[string]interface{}{"row_count": 0})
+	tracker.Finish(call, nil, nil)
+
+	trace, ok := tracker.Trace("s1")
+	require.True(t, ok)
+	require.Len(t, trace.Nodes, 2)
+	assert.Equal(t, []Edge{{From: call.ID, To: exec.ID}}, trace.Edges)
+	assert.Equal(t, "timeout", trace.Nodes[1].Error)
+	assert.Equal(t, map[string]interface{}{"row_count": 0}, trace.Nodes[1].Data)
+
+	// Traces are copies
+	trace.Nodes[1].Data["row_count"] = 9
+	trace, _ = tracker.Trace("s1")
+	assert.Equal(t, 0, trace.Nodes[1].Data["row_count"])
+
+	// Sessions keep their latest nodes
+	for i := 0; i < 3; i++ {
+		tracker.Start(ctx, KindSQL, "SELECT 1", nil)
+	}
+	trace, _ = tracker.Trace("s1")
+	require.Len(t, trace.Nodes, 3)
+	assert.Equal(t, KindSQL, trace.Nodes[0].Kind)
+
+	tracker.Forget("s1")
+	_, ok = tracker.Trace("s1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,186 @@
+// Package s3 uploads objects to Amazon S3 and S3-compatible stores with
+// AWS Signature Version 4, without pulling in the AWS SDK
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	service       = "s3"
+	algorithm     = "AWS4-HMAC-SHA256"
+	amzDateFormat = "20060102T150405Z"
+)
+
+// Config locates a bucket and the credentials to write to it
+type Config struct {
+	Bucket string `json:"bucket"`
+	Region string `json:"region"`
+
+	// Prefix is prepended to every object key
+	Prefix string `json:"prefix,omitempty"`
+
+	// Endpoint of an S3-compatible store, e.g. http://minio:9000. Objects are
+	// addressed path-style when set and virtual-hosted style on AWS otherwise.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// AccessKeyID and SecretAccessKey default to the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY environment variables; AWS_SESSION_TOKEN is
+	// used with environment credentials
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+}
+
+// Validate checks that the bucket can be addressed
+func (c *Config) Validate() error {
+	if c.Bucket == "" {
+		return fmt.Errorf("s3 bucket is required")
+	}
+	if c.Region == "" {
+		return fmt.Errorf("s3 region is required")
+	}
+	if c.Endpoint != "" {
+		if _, err := url.Parse(c.Endpoint); err != nil {
+			return fmt.Errorf("invalid s3 endpoint: %w", err)
+		}
+	}
+	return nil
+}
+
+// Put uploads an object under the configured prefix
+func (c *Config) Put(ctx context.Context, key, contentType string, body []byte) error {
+	accessKey, secretKey, token := c.AccessKeyID, c.SecretAccessKey, ""
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		token = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("s3 credentials are not configured")
+	}
+
+	objectURL, err := c.objectURL(c.Prefix + key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	sign(req, body, c.Region, accessKey, secretKey, time.Now().UTC())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to s3: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// objectURL addresses an object path-style on custom endpoints and
+// virtual-hosted style on AWS
+func (c *Config) objectURL(key string) (*url.URL, error) {
+	path := "/" + encodePath(key)
+	if c.Endpoint == "" {
+		return url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com%s", c.Bucket, c.Region, path))
+	}
+	u, err := url.Parse(strings.TrimSuffix(c.Endpoint, "/") + "/" + encodePath(c.Bucket) + path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %w", err)
+	}
+	return u, nil
+}
+
+// sign adds the Signature Version 4 headers of an S3 request
+func sign(req *http.Request, body []byte, region, accessKey, secretKey string, now time.Time) {
+	Sign(req, body, region, service, accessKey, secretKey, now)
+}
+
+// Sign adds the Signature Version 4 headers to a request to an AWS service
+func Sign(req *http.Request, body []byte, region, service, accessKey, secretKey string, now time.Time) {
+	payloadHash := hexSHA256(body)
+	amzDate := now.Format(amzDateFormat)
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, strings.TrimSpace(req.Header.Get(name)))
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", now.Format("20060102"), region, service)
+	stringToSign := strings.Join([]string{algorithm, amzDate, scope, hexSHA256([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, accessKey, scope, signedHeaders, signature))
+}
+
+// encodePath escapes an object key as SigV4 canonical URIs require:
+// everything but unreserved characters and slashes is percent-encoded
+func encodePath(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
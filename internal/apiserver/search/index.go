@@ -0,0 +1,100 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Document is an indexed item
+type Document struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Result is a document matched by a search, with its cosine similarity
+type Result struct {
+	Document
+	Score float64 `json:"score"`
+}
+
+type entry struct {
+	doc    Document
+	vector []float32
+	norm   float64
+}
+
+// Index is an in-memory vector index using cosine similarity
+type Index struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		entries: make(map[string]entry),
+	}
+}
+
+// Put adds or replaces a document
+func (i *Index) Put(doc Document, vector []float32) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.entries[doc.ID] = entry{doc: doc, vector: vector, norm: norm(vector)}
+}
+
+// Delete removes a document
+func (i *Index) Delete(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.entries, id)
+}
+
+// Len returns the number of indexed documents
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.entries)
+}
+
+// Search returns the k documents most similar to the query vector
+func (i *Index) Search(query []float32, k int) []Result {
+	qnorm := norm(query)
+	if qnorm == 0 {
+		return nil
+	}
+
+	i.mu.RLock()
+	results := make([]Result, 0, len(i.entries))
+	for _, e := range i.entries {
+		if e.norm == 0 || len(e.vector) != len(query) {
+			continue
+		}
+		var dot float64
+		for j := range query {
+			dot += float64(query[j]) * float64(e.vector[j])
+		}
+		results = append(results, Result{Document: e.doc, Score: dot / (qnorm * e.norm)})
+	}
+	i.mu.RUnlock()
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score == results[b].Score {
+			return results[a].ID < results[b].ID
+		}
+		return results[a].Score > results[b].Score
+	})
+	if k > 0 && len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+func norm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexSearch(t *testing.T) {
+	index := NewIndex()
+	index.Put(Document{ID: "ORDERS", Text: "orders"}, []float32{1, 0, 0})
+	index.Put(Document{ID: "CUSTOMERS", Text: "customers"}, []float32{0.6, 0.8, 0})
+	index.Put(Document{ID: "INVOICES", Text: "invoices"}, []float32{0, 0, 2})
+	index.Put(Document{ID: "LEGACY", Text: "embedded by another model"}, []float32{1, 0})
+	require.Equal(t, 4, index.Len())
+
+	// Documents rank by cosine similarity; other dimensions are skipped
+	results := index.Search([]float32{2, 1, 0}, 0)
+	require.Len(t, results, 3)
+	assert.Equal(t, []string{"CUSTOMERS", "ORDERS", "INVOICES"}, []string{results[0].ID, results[1].ID, results[2].ID})
+	assert.InDelta(t, 0.894, results[1].Score, 0.001)
+	assert.InDelta(t, 0, results[2].Score, 0.001)
+
+	assert.Len(t, index.Search([]float32{2, 1, 0}, 2), 2)
+	assert.Empty(t, index.Search([]float32{0, 0, 0}, 0))
+
+	// Ties keep a stable order
+	index.Put(Document{ID: "ARCHIVE", Text: "orders"}, []float32{1, 0, 0})
+	results = index.Search([]float32{1, 0, 0}, 2)
+	assert.Equal(t, []string{"ARCHIVE", "ORDERS"}, []string{results[0].ID, results[1].ID})
+
+	index.Delete("ARCHIVE")
+	assert.Equal(t, 4, index.Len())
+}
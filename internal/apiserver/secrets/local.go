@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// localKey wraps data keys with AES-GCM under a key held by the gateway
+type localKey struct {
+	key []byte
+}
+
+// newLocalKey reads a local key from its environment variable or file
+func newLocalKey(cfg KeyConfig) (*localKey, error) {
+	var encoded string
+	switch {
+	case cfg.KeyEnv != "":
+		encoded = os.Getenv(cfg.KeyEnv)
+		if encoded == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", cfg.KeyEnv)
+		}
+	case cfg.KeyFile != "":
+		data, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		encoded = string(data)
+	default:
+		return nil, fmt.Errorf("key_env or key_file is required")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != dataKeySize {
+		return nil, fmt.Errorf("the key must be %d bytes, base64-encoded", dataKeySize)
+	}
+	return &localKey{key: key}, nil
+}
+
+func (k *localKey) Wrap(_ context.Context, dataKey []byte) ([]byte, error) {
+	return seal(k.key, dataKey, nil)
+}
+
+func (k *localKey) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	return open(k.key, wrapped, nil)
+}
@@ -0,0 +1,240 @@
+// Package secrets encrypts the secrets the gateway persists with envelope
+// encryption: each secret is sealed with AES-GCM under a fresh data key,
+// which is itself wrapped by a key encryption key held locally or in a KMS
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// sealedPrefix marks sealed values; the key ID, wrapped data key and
+// ciphertext follow, separated by colons
+const sealedPrefix = "enc:v1:"
+
+const (
+	dataKeySize = 32
+
+	// maxCachedKeys bounds the unwrapped data keys kept in memory, so that
+	// reading the same secrets does not call the KMS each time
+	maxCachedKeys = 1024
+)
+
+// Key types
+const (
+	KeyTypeLocal  = "local"
+	KeyTypeAWSKMS = "aws_kms"
+)
+
+// ErrNoKey is returned when a value is sealed under a key that is not
+// configured
+var ErrNoKey = errors.New("encryption key not configured")
+
+// Config lists the key encryption keys. The first key seals new secrets and
+// every key opens them, so a key is rotated by adding its replacement in
+// front of the list and re-sealing the stored secrets.
+type Config struct {
+	Keys []KeyConfig `json:"keys"`
+}
+
+// KeyConfig configures a key encryption key
+type KeyConfig struct {
+	// ID names the key in sealed values; it must not change while values
+	// sealed under the key are stored
+	ID string `json:"id"`
+
+	// Type is local or aws_kms
+	Type string `json:"type"`
+
+	// KeyEnv and KeyFile hold a local key: 32 bytes, base64-encoded
+	KeyEnv  string `json:"key_env,omitempty"`
+	KeyFile string `json:"key_file,omitempty"`
+
+	// KMS configures an aws_kms key
+	KMS *KMSConfig `json:"kms,omitempty"`
+}
+
+// KeyWrapper wraps and unwraps data keys with a key encryption key
+type KeyWrapper interface {
+	Wrap(ctx context.Context, dataKey []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// Keyring seals and opens secrets with the configured keys
+type Keyring struct {
+	current string
+	keys    map[string]KeyWrapper
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+// NewKeyring creates the keyring of a configuration; nil stores secrets in
+// plaintext
+func NewKeyring(cfg *Config) (*Keyring, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.Keys) == 0 {
+		return nil, fmt.Errorf("at least one key is required")
+	}
+	keys := make(map[string]KeyWrapper, len(cfg.Keys))
+	for _, kc := range cfg.Keys {
+		if kc.ID == "" || strings.Contains(kc.ID, ":") {
+			return nil, fmt.Errorf("invalid key id %q", kc.ID)
+		}
+		if _, ok := keys[kc.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %s", kc.ID)
+		}
+		var (
+			wrapper KeyWrapper
+			err     error
+		)
+		switch kc.Type {
+		case KeyTypeLocal:
+			wrapper, err = newLocalKey(kc)
+		case KeyTypeAWSKMS:
+			wrapper, err = newKMSKey(kc.KMS)
+		default:
+			err = fmt.Errorf("unsupported key type: %s", kc.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", kc.ID, err)
+		}
+		keys[kc.ID] = wrapper
+	}
+	return NewKeyringWith(cfg.Keys[0].ID, keys), nil
+}
+
+// NewKeyringWith creates a keyring of key wrappers by ID, sealing with the
+// current one
+func NewKeyringWith(current string, keys map[string]KeyWrapper) *Keyring {
+	return &Keyring{current: current, keys: keys, cache: make(map[string][]byte)}
+}
+
+// IsSealed reports whether a value was sealed by a keyring
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// NeedsRotation reports whether a value is stored in plaintext or sealed
+// under a key other than the current one
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, sealedPrefix), ":")
+	return !IsSealed(value) || id != k.current
+}
+
+// Seal encrypts a secret under the current key. The additional data binds
+// the sealed value to where it is stored; Open must be given the same.
+func (k *Keyring) Seal(ctx context.Context, plaintext string, additional []byte) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrapped, err := k.keys[k.current].Wrap(ctx, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key with %s: %w", k.current, err)
+	}
+	sealed, err := seal(dataKey, []byte(plaintext), additional)
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + k.current + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a sealed secret; values that are not sealed are returned
+// as they are, so that secrets stored before encryption was enabled can
+// still be read
+func (k *Keyring) Open(ctx context.Context, value string, additional []byte) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, sealedPrefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed sealed value")
+	}
+	id := parts[0]
+	key, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNoKey, id)
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed sealed value: %w", err)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed sealed value: %w", err)
+	}
+
+	cacheKey := id + ":" + parts[1]
+	k.mu.Lock()
+	dataKey, ok := k.cache[cacheKey]
+	k.mu.Unlock()
+	if !ok {
+		if dataKey, err = key.Unwrap(ctx, wrapped); err != nil {
+			return "", fmt.Errorf("failed to unwrap data key with %s: %w", id, err)
+		}
+		k.mu.Lock()
+		if len(k.cache) >= maxCachedKeys {
+			k.cache = make(map[string][]byte)
+		}
+		k.cache[cacheKey] = dataKey
+		k.mu.Unlock()
+	}
+
+	plaintext, err := open(dataKey, sealed, additional)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// seal encrypts with AES-GCM, prefixing the ciphertext with its nonce
+func seal(key, plaintext, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, additional), nil
+}
+
+// open decrypts a ciphertext sealed with seal
+func open(key, sealed, additional []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed sealed value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], additional)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
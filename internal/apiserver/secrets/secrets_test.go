@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringSealsAndOpens(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "old.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))+"\n"), 0o600))
+	t.Setenv("GATEWAY_KEY", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{9}, 32)))
+
+	old, err := NewKeyring(&Config{Keys: []KeyConfig{{ID: "old", Type: KeyTypeLocal, KeyFile: keyFile}}})
+	require.NoError(t, err)
+	sealed, err := old.Seal(ctx, "hunter2", []byte("sales/password"))
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.True(t, strings.HasPrefix(sealed, "enc:v1:old:"))
+	assert.NotContains(t, sealed, "hunter2")
+
+	opened, err := old.Open(ctx, sealed, []byte("sales/password"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", opened)
+
+	// Sealed values are bound to where they are stored
+	_, err = old.Open(ctx, sealed, []byte("hr/password"))
+	assert.Error(t, err)
+
+	// Plaintext is read as is
+	opened, err = old.Open(ctx, "plain", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "plain", opened)
+
+	rotated, err := NewKeyring(&Config{Keys: []KeyConfig{
+		{ID: "new", Type: KeyTypeLocal, KeyEnv: "GATEWAY_KEY"},
+		{ID: "old", Type: KeyTypeLocal, KeyFile: keyFile},
+	}})
+	require.NoError(t, err)
+	assert.True(t, rotated.NeedsRotation(sealed))
+	assert.True(t, rotated.NeedsRotation("plain"))
+	assert.False(t, rotated.NeedsRotation(""))
+	opened, err = rotated.Open(ctx, sealed, []byte("sales/password"))
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", opened)
+	resealed, err := rotated.Seal(ctx, opened, []byte("sales/password"))
+	require.NoError(t, err)
+	assert.False(t, rotated.NeedsRotation(resealed))
+
+	_, err = old.Open(ctx, resealed, []byte("sales/password"))
+	assert.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestKeyringConfig(t *testing.T) {
+	for name, cfg := range map[string]*Config{
+		"no keys":     {},
+		"missing env": {Keys: []KeyConfig{{ID: "k", Type: KeyTypeLocal, KeyEnv: "UNSET_GATEWAY_KEY"}}},
+		"short key":   {Keys: []KeyConfig{{ID: "k", Type: KeyTypeLocal, KeyEnv: "SHORT_KEY"}}},
+		"bad id":      {Keys: []KeyConfig{{ID: "a:b", Type: KeyTypeLocal, KeyEnv: "SHORT_KEY"}}},
+		"no kms key":  {Keys: []KeyConfig{{ID: "k", Type: KeyTypeAWSKMS}}},
+		"bad type":    {Keys: []KeyConfig{{ID: "k", Type: "vault"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("SHORT_KEY", base64.StdEncoding.EncodeToString([]byte("short")))
+			_, err := NewKeyring(cfg)
+			assert.Error(t, err)
+		})
+	}
+	keys, err := NewKeyring(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+}
+
+func TestKMSKey(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	unwraps := 0
+	kms := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/kms/aws4_request")
+		var in struct {
+			KeyId          string
+			Plaintext      []byte
+			CiphertextBlob []byte
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		assert.Equal(t, "alias/gateway", in.KeyId)
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": append([]byte("kms:"), in.Plaintext...)})
+		case "TrentService.Decrypt":
+			unwraps++
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": bytes.TrimPrefix(in.CiphertextBlob, []byte("kms:"))})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer kms.Close()
+
+	ctx := context.Background()
+	keys, err := NewKeyring(&Config{Keys: []KeyConfig{{ID: "kms", Type: KeyTypeAWSKMS, KMS: &KMSConfig{
+		KeyID: "alias/gateway", Region: "us-east-1", Endpoint: kms.URL,
+	}}}})
+	require.NoError(t, err)
+	sealed, err := keys.Seal(ctx, "hunter2", nil)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		opened, err := keys.Open(ctx, sealed, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", opened)
+	}
+	// Unwrapped data keys are cached
+	assert.Equal(t, 1, unwraps)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/auth/jwt"
+)
+
+// defaultAdminRole is the role allowed to use the admin routes when no
+// roles are configured
+const defaultAdminRole = "admin"
+
+// AdminConfig restricts the /admin routes to callers of admin roles
+type AdminConfig struct {
+	// Roles are the role claims of the callers allowed to use the admin
+	// routes (default: admin)
+	Roles []string `json:"roles,omitempty"`
+
+	// JWTSecret verifies HS256 bearer tokens carrying the callers' role.
+	// Without it the role comes from the row security token, the gateway's
+	// JWT, the OAuth2 claims or the client certificate of the caller.
+	JWTSecret string `json:"jwt_secret,omitempty"`
+}
+
+// adminAuth admits the callers of the admin roles
+type adminAuth struct {
+	roles  map[string]bool
+	claims *rowSecurity
+}
+
+// newAdminAuth creates the admin check of a configuration, reading the
+// callers' claims like row security when no secret of its own is set
+func newAdminAuth(cfg *AdminConfig, rs *rowSecurity) *adminAuth {
+	a := &adminAuth{roles: map[string]bool{defaultAdminRole: true}, claims: rs}
+	if cfg != nil {
+		if len(cfg.Roles) > 0 {
+			a.roles = make(map[string]bool, len(cfg.Roles))
+			for _, role := range cfg.Roles {
+				a.roles[role] = true
+			}
+		}
+		if cfg.JWTSecret != "" {
+			a.claims = &rowSecurity{secret: []byte(cfg.JWTSecret)}
+		}
+	}
+	if a.claims == nil {
+		a.claims = &rowSecurity{}
+	}
+	return a
+}
+
+// admits reports whether the caller of a request holds an admin role,
+// returning the caller's claims
+func (a *adminAuth) admits(c *gin.Context) (map[string]interface{}, bool) {
+	var gatewayClaims *jwt.Claims
+	if v, ok := c.Get("claims"); ok {
+		gatewayClaims, _ = v.(*jwt.Claims)
+	}
+	claims, err := a.claims.callerClaims(c.Request, gatewayClaims)
+	if err != nil {
+		return nil, false
+	}
+	role, _ := claims["role"].(string)
+	return claims, a.roles[role]
+}
+
+// middleware rejects callers without an admin role from the requests
+// guards selects, or from every request when guards is nil. Admins are
+// identified by their subject claim when nothing identified them before.
+func (a *adminAuth) middleware(guards func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if guards != nil && !guards(c) {
+			c.Next()
+			return
+		}
+		claims, ok := a.admits(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin role required"})
+			return
+		}
+		ctx := c.Request.Context()
+		if principal, _ := ctx.Value(principalKey{}).(string); principal == "" {
+			if sub, _ := claims["sub"].(string); sub != "" {
+				c.Request = c.Request.WithContext(context.WithValue(ctx, principalKey{}, sub))
+			}
+		}
+		c.Next()
+	}
+}
+
+// adminMiddleware guards every /admin route of the server
+func (s *MCPServerWithDB) adminMiddleware() gin.HandlerFunc {
+	return newAdminAuth(s.Config.Admin, s.rowSecurity).middleware(func(c *gin.Context) bool {
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		path = strings.TrimPrefix(path, s.apiPrefix)
+		return path == "/admin" || strings.HasPrefix(path, "/admin/")
+	})
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/provenance"
+)
+
+// budgetConnector guards a credit budget without a warehouse
+type budgetConnector struct {
+	connector.DatabaseConnector
+	overrideUntil time.Time
+}
+
+func (c *budgetConnector) ListTables(context.Context) ([]connector.Table, error) {
+	return []connector.Table{}, nil
+}
+
+func (c *budgetConnector) CreditUsage(context.Context) (*connector.CreditUsage, error) {
+	return &connector.CreditUsage{Budget: 10, OverrideUntil: c.overrideUntil}, nil
+}
+
+func (c *budgetConnector) OverrideBudget(until time.Time) { c.overrideUntil = until }
+
+func (c *budgetConnector) ClearBudgetOverride() { c.overrideUntil = time.Time{} }
+
+// adminToken signs a bearer token of a role with the secret "secret"
+func adminToken(t *testing.T, role string) string {
+	token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{"sub": "ops", "role": role}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAdminRoutes(t *testing.T) {
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", Admin: &AdminConfig{JWTSecret: "secret"}},
+		DBConn: &budgetConnector{},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	call := func(method, target, bearer string) int {
+		r := httptest.NewRequest(method, target, nil)
+		if bearer != "" {
+			r.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Admin routes need an admin role, and the other routes do not
+	for _, bearer := range []string{"", adminToken(t, "agent"), "not-a-token"} {
+		assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/admin/budget", bearer))
+		assert.Equal(t, http.StatusForbidden, call(http.MethodDelete, "/admin/budget/override", bearer))
+	}
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/admin/budget", adminToken(t, "admin")))
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/admin/budget/override", adminToken(t, "admin")))
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/tables", ""))
+
+	// Configured roles replace the default one
+	s.Config.Admin.Roles = []string{"ops"}
+	router = gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/admin/budget", adminToken(t, "admin")))
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/admin/budget", adminToken(t, "ops")))
+}
+
+func TestAdminDataRoutes(t *testing.T) {
+	history, err := newQueryHistory("sales", &HistoryConfig{}, nil)
+	require.NoError(t, err)
+	monitors, err := newMonitors("sales", &MonitorConfig{}, nil)
+	require.NoError(t, err)
+	s := &MCPServerWithDB{
+		Config:     &MCPServerConfig{Name: "sales", Admin: &AdminConfig{JWTSecret: "secret"}},
+		DBConn:     &paramsConnector{},
+		Audit:      audit.NewMemoryRecorder(10),
+		Provenance: provenance.NewTracker(0),
+		history:    history,
+		monitors:   monitors,
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	call := func(method, target, bearer string) int {
+		r := httptest.NewRequest(method, target, strings.NewReader(`{"name":"orders","table":"ORDERS","min_rows":1}`))
+		if bearer != "" {
+			r.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Other callers' SQL, principals and sessions are for admins only
+	for _, route := range []struct{ method, target string }{
+		{http.MethodGet, "/admin/audit"},
+		{http.MethodGet, "/admin/history"},
+		{http.MethodGet, "/admin/history/stats"},
+		{http.MethodGet, "/admin/sessions/s1/trace"},
+		{http.MethodPost, "/admin/monitors"},
+		{http.MethodDelete, "/admin/monitors/orders"},
+	} {
+		assert.Equal(t, http.StatusForbidden, call(route.method, route.target, ""), route.target)
+		assert.Equal(t, http.StatusForbidden, call(route.method, route.target, adminToken(t, "agent")), route.target)
+	}
+	admin := adminToken(t, "admin")
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/admin/audit", admin))
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/admin/history", admin))
+	assert.Equal(t, http.StatusCreated, call(http.MethodPost, "/admin/monitors", admin))
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/monitors/orders", ""))
+	assert.Equal(t, http.StatusNoContent, call(http.MethodDelete, "/admin/monitors/orders", admin))
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Kinds of generated surface tracked by the usage analytics
+const (
+	UsageKindTool     = "tool"
+	UsageKindEndpoint = "endpoint"
+)
+
+const (
+	analyticsBucket       = 5 * time.Minute
+	analyticsRetention    = 7 * 24 * time.Hour
+	defaultAnalyticsRange = "24h"
+)
+
+// analyticsWindows are the rolling windows the usage can be reported over
+var analyticsWindows = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  analyticsRetention,
+}
+
+// latencyBoundsMs are the upper bounds of the latency histogram buckets;
+// the last bucket is unbounded
+var latencyBoundsMs = []int64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// SurfaceUsage is the usage of one MCP tool or generated endpoint over a
+// window. P95Ms is the upper bound of the latency bucket holding the 95th
+// percentile, or -1 when it is beyond the largest bound.
+type SurfaceUsage struct {
+	Kind      string  `json:"kind"`
+	Name      string  `json:"name"`
+	Calls     int     `json:"calls"`
+	Errors    int     `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	P95Ms     int64   `json:"p95_ms"`
+}
+
+// UsageReport is the usage of the tools and endpoints over a window
+type UsageReport struct {
+	Window  string         `json:"window"`
+	From    time.Time      `json:"from"`
+	To      time.Time      `json:"to"`
+	Surface []SurfaceUsage `json:"surface"`
+}
+
+// usageKey identifies a tool or endpoint
+type usageKey struct {
+	kind string
+	name string
+}
+
+// usageBucket counts the calls of one surface in one time bucket
+type usageBucket struct {
+	calls     int
+	errors    int
+	latencies []int
+}
+
+// usageAnalytics counts the invocations of tools and endpoints in time
+// buckets covering the longest window
+type usageAnalytics struct {
+	mu      sync.Mutex
+	buckets map[usageKey]map[int64]*usageBucket
+	now     func() time.Time
+}
+
+func newUsageAnalytics() *usageAnalytics {
+	return &usageAnalytics{
+		buckets: make(map[usageKey]map[int64]*usageBucket),
+		now:     time.Now,
+	}
+}
+
+// record counts one invocation of a tool or endpoint
+func (a *usageAnalytics) record(kind, name string, latency time.Duration, failed bool) {
+	if a == nil {
+		return
+	}
+	now := a.now()
+	slot := now.Truncate(analyticsBucket).Unix()
+	key := usageKey{kind: kind, name: name}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	slots, ok := a.buckets[key]
+	if !ok {
+		slots = make(map[int64]*usageBucket)
+		a.buckets[key] = slots
+	}
+	b, ok := slots[slot]
+	if !ok {
+		b = &usageBucket{latencies: make([]int, len(latencyBoundsMs)+1)}
+		slots[slot] = b
+
+		// A new bucket starts: drop this surface's expired buckets
+		expired := now.Add(-analyticsRetention - analyticsBucket).Unix()
+		for s := range slots {
+			if s < expired {
+				delete(slots, s)
+			}
+		}
+	}
+	b.calls++
+	if failed {
+		b.errors++
+	}
+	ms := latency.Milliseconds()
+	i := sort.Search(len(latencyBoundsMs), func(i int) bool { return ms <= latencyBoundsMs[i] })
+	b.latencies[i]++
+}
+
+// report sums the buckets started within the window, most used first
+func (a *usageAnalytics) report(window string, kind string) *UsageReport {
+	to := a.now()
+	from := to.Add(-analyticsWindows[window])
+	report := &UsageReport{Window: window, From: from, To: to, Surface: []SurfaceUsage{}}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, slots := range a.buckets {
+		if kind != "" && key.kind != kind {
+			continue
+		}
+
+		usage := SurfaceUsage{Kind: key.kind, Name: key.name}
+		latencies := make([]int, len(latencyBoundsMs)+1)
+		for slot, b := range slots {
+			if slot < from.Truncate(analyticsBucket).Unix() {
+				continue
+			}
+			usage.Calls += b.calls
+			usage.Errors += b.errors
+			for i, n := range b.latencies {
+				latencies[i] += n
+			}
+		}
+		if usage.Calls == 0 {
+			continue
+		}
+		usage.ErrorRate = float64(usage.Errors) / float64(usage.Calls)
+		usage.P95Ms = percentileBound(latencies, usage.Calls, 0.95)
+		report.Surface = append(report.Surface, usage)
+	}
+	sortSurfaceUsage(report.Surface)
+	return report
+}
+
+// percentileBound returns the upper bound of the histogram bucket holding
+// the percentile, or -1 for the unbounded bucket
+func percentileBound(latencies []int, total int, percentile float64) int64 {
+	rank := int(float64(total)*percentile + 0.999999)
+	seen := 0
+	for i, n := range latencies {
+		seen += n
+		if seen >= rank && i < len(latencyBoundsMs) {
+			return latencyBoundsMs[i]
+		}
+	}
+	return -1
+}
+
+// sortSurfaceUsage orders the usage by calls, most used first
+func sortSurfaceUsage(surface []SurfaceUsage) {
+	sort.Slice(surface, func(i, j int) bool {
+		if surface[i].Calls != surface[j].Calls {
+			return surface[i].Calls > surface[j].Calls
+		}
+		if surface[i].Kind != surface[j].Kind {
+			return surface[i].Kind < surface[j].Kind
+		}
+		return surface[i].Name < surface[j].Name
+	})
+}
+
+// endpointUsageName names a generated endpoint in the usage analytics
+func endpointUsageName(method, path string) string {
+	return method + " " + path
+}
+
+// setupAnalyticsRoutes configures the usage analytics route
+func (s *MCPServerWithDB) setupAnalyticsRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/usage", func(c *gin.Context) {
+		window := c.DefaultQuery("window", defaultAnalyticsRange)
+		if _, ok := analyticsWindows[window]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid window: %s (must be 1h, 24h or 7d)", window)})
+			return
+		}
+		kind := c.Query("kind")
+		if kind != "" && kind != UsageKindTool && kind != UsageKindEndpoint {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid kind: %s", kind)})
+			return
+		}
+		report := s.usage.report(window, kind)
+
+		// Unused surface is listed with no calls
+		if c.Query("include_unused") == "true" {
+			used := make(map[usageKey]bool)
+			for _, u := range report.Surface {
+				used[usageKey{kind: u.Kind, name: u.Name}] = true
+			}
+			unused := func(kind, name string) {
+				if key := (usageKey{kind: kind, name: name}); !used[key] {
+					used[key] = true
+					report.Surface = append(report.Surface, SurfaceUsage{Kind: kind, Name: name})
+				}
+			}
+			if kind != UsageKindEndpoint {
+				tools, _, err := s.mcpTools(c.Request.Context())
+				if err != nil {
+					s.respondError(c, queryErrorStatus(err), "Failed to list tools", err)
+					return
+				}
+				for _, tool := range tools {
+					unused(UsageKindTool, tool.Schema.Name)
+				}
+			}
+			if kind != UsageKindTool && s.routes != nil {
+				for _, endpoint := range s.routes.Endpoints() {
+					unused(UsageKindEndpoint, endpointUsageName(endpoint.Method, endpoint.Path))
+				}
+			}
+			sortSurfaceUsage(report.Surface)
+		}
+		c.JSON(http.StatusOK, report)
+	})
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestUsageAnalytics(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	usage := newUsageAnalytics()
+	usage.now = func() time.Time { return now }
+	conn := &paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": 1.0}}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, usage: usage}
+
+	// A call two days ago only counts in the 7 day window
+	usage.now = func() time.Time { return now.Add(-48 * time.Hour) }
+	usage.record(UsageKindTool, "sales_list_orders", 20*time.Millisecond, false)
+	usage.now = func() time.Time { return now }
+	for i := 0; i < 19; i++ {
+		usage.record(UsageKindTool, "sales_list_orders", 20*time.Millisecond, false)
+	}
+	usage.record(UsageKindTool, "sales_list_orders", 2*time.Second, true)
+	usage.record(UsageKindTool, "sales_describe_table", 2*time.Minute, false)
+
+	endpoint := connector.APIEndpoint{
+		Table:  "ORDERS",
+		Method: http.MethodGet,
+		Path:   "/ORDERS/search",
+		Query:  `SELECT * FROM ORDERS WHERE CUSTOMER = :q LIMIT :limit OFFSET :offset`,
+		Params: connector.SearchParams(),
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ORDERS/search", s.generatedEndpointHandler(endpoint))
+	s.setupAnalyticsRoutes(router.Group("/"))
+
+	for _, query := range []string{"q=ACME", "limit=ten"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ORDERS/search?"+query, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/usage?window=1h", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report UsageReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report.Surface, 3)
+	assert.Equal(t, SurfaceUsage{Kind: UsageKindTool, Name: "sales_list_orders", Calls: 20, Errors: 1, ErrorRate: 0.05, P95Ms: 25}, report.Surface[0])
+	assert.Equal(t, "GET /ORDERS/search", report.Surface[1].Name)
+	assert.Equal(t, 0.5, report.Surface[1].ErrorRate)
+	assert.Equal(t, SurfaceUsage{Kind: UsageKindTool, Name: "sales_describe_table", Calls: 1, P95Ms: -1}, report.Surface[2])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/usage?window=7d&kind=tool", nil))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report.Surface, 2)
+	assert.Equal(t, 21, report.Surface[0].Calls)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/usage?window=30d", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,428 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// Statuses of actions staged for approval
+const (
+	ActionPending  = "pending"
+	ActionExecuted = "executed"
+	ActionFailed   = "failed"
+	ActionRejected = "rejected"
+	ActionExpired  = "expired"
+)
+
+// defaultApprovalExpiry is how long a staged action waits for a decision
+const defaultApprovalExpiry = 24 * time.Hour
+
+var (
+	// ErrActionNotPending is returned when deciding an action that was
+	// already decided or expired
+	ErrActionNotPending = errors.New("action is not pending")
+
+	// ErrSelfApproval is returned when the caller who staged an action
+	// approves it
+	ErrSelfApproval = errors.New("actions cannot be approved by their caller")
+)
+
+// ApprovalConfig stages the destructive statements of MCP tools as actions
+// that run only once an administrator other than their caller approves
+// them through the admin API. Agents are told the action is pending and can
+// follow it with the action_status tool.
+type ApprovalConfig struct {
+	// Kinds lists the statement kinds needing approval: read, write, ddl,
+	// session and other (default: every kind but read). Statements of kind
+	// other, which cannot be told apart, always need it.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Expiry is how long an action waits for a decision (default: 24h)
+	Expiry string `json:"expiry,omitempty"`
+}
+
+// PendingAction is a statement staged for approval, with its outcome once
+// decided
+type PendingAction struct {
+	ID        string                  `json:"id"`
+	Tool      string                  `json:"tool,omitempty"`
+	SQL       string                  `json:"sql"`
+	Params    map[string]interface{}  `json:"params,omitempty"`
+	Kind      connector.StatementKind `json:"kind"`
+	Principal string                  `json:"principal,omitempty"`
+	Status    string                  `json:"status"`
+
+	// Connection is the label of the connection the statement runs on,
+	// empty for the primary
+	Connection string    `json:"connection,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+
+	// Risk is why a query was parked for review
+	Risk *QueryRisk `json:"risk,omitempty"`
+
+	// queryTag and claims are those of the caller, whom the statement
+	// runs as once approved
+	queryTag string
+	claims   map[string]interface{}
+
+	// replay runs a parked request other than a query, e.g. an export,
+	// once approved
+	replay func(ctx context.Context) (interface{}, error)
+
+	DecidedBy string     `json:"decided_by,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+
+	// Rows are the rows returned by the executed statement, Result the
+	// outcome of a replayed request, and Error why either failed
+	Rows   []map[string]interface{} `json:"rows,omitempty"`
+	Result interface{}              `json:"result,omitempty"`
+	Error  string                   `json:"error,omitempty"`
+}
+
+// actionQueue keeps the actions waiting for a decision by ID. Approved
+// actions are run as queries of source.
+type actionQueue struct {
+	source string
+	expiry time.Duration
+
+	mu      sync.Mutex
+	actions map[string]*PendingAction
+}
+
+// newActionQueue creates a queue whose actions expire after expiry
+// (default: 24h)
+func newActionQueue(source, expiry string) (*actionQueue, error) {
+	q := &actionQueue{source: source, expiry: defaultApprovalExpiry, actions: make(map[string]*PendingAction)}
+	if expiry != "" {
+		d, err := time.ParseDuration(expiry)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid expiry: %s", expiry)
+		}
+		q.expiry = d
+	}
+	return q, nil
+}
+
+// approvals stages the statements of MCP tools whose kinds need approval
+type approvals struct {
+	*actionQueue
+	syntax connector.SQLSyntax
+	kinds  map[connector.StatementKind]bool
+}
+
+// newApprovals validates an approval configuration for a database type;
+// nil runs every statement without approval
+func newApprovals(cfg *ApprovalConfig, dbType string) (*approvals, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	queue, err := newActionQueue("mcp", cfg.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	a := &approvals{
+		actionQueue: queue,
+		syntax:      connector.SyntaxFor(dbType),
+		kinds:       map[connector.StatementKind]bool{connector.StatementOther: true},
+	}
+	kinds := cfg.Kinds
+	if len(kinds) == 0 {
+		kinds = []string{string(connector.StatementWrite), string(connector.StatementDDL), string(connector.StatementSession)}
+	}
+	for _, kind := range kinds {
+		switch k := connector.StatementKind(kind); k {
+		case connector.StatementRead, connector.StatementWrite, connector.StatementDDL,
+			connector.StatementSession, connector.StatementOther:
+			a.kinds[k] = true
+		default:
+			return nil, fmt.Errorf("invalid statement kind: %s", kind)
+		}
+	}
+	return a, nil
+}
+
+// requires returns the kind of the first statement of a query needing
+// approval. Queries that cannot be parsed need it too.
+func (a *approvals) requires(query string) (connector.StatementKind, bool) {
+	statements, err := connector.ParseStatements(a.syntax, query)
+	if err != nil {
+		return connector.StatementOther, true
+	}
+	for _, st := range statements {
+		if a.kinds[st.Kind] {
+			return st.Kind, true
+		}
+	}
+	return "", false
+}
+
+// stage records an action waiting for a decision, along with the query
+// tag, claims and connection of the caller staging it
+func (q *actionQueue) stage(ctx context.Context, action PendingAction) PendingAction {
+	now := time.Now().UTC()
+	action.queryTag = connector.QueryTagFromContext(ctx)
+	action.claims = claimsFromContext(ctx)
+	action.Connection = connectionLabel(ctx)
+	action.ID = uuid.New().String()
+	action.Status = ActionPending
+	action.CreatedAt = now
+	action.ExpiresAt = now.Add(q.expiry)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.actions[action.ID] = &action
+	return action
+}
+
+// expire marks the pending actions past their expiry; the caller holds mu
+func (q *actionQueue) expire(now time.Time) {
+	for _, action := range q.actions {
+		if action.Status == ActionPending && action.DecidedAt == nil && now.After(action.ExpiresAt) {
+			action.Status = ActionExpired
+		}
+	}
+}
+
+// get returns a copy of an action
+func (q *actionQueue) get(id string) (PendingAction, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expire(time.Now())
+	action, ok := q.actions[id]
+	if !ok {
+		return PendingAction{}, false
+	}
+	return *action, true
+}
+
+// list returns the actions with a status, or every action, oldest first
+func (q *actionQueue) list(status string) []PendingAction {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.expire(time.Now())
+	list := []PendingAction{}
+	for _, action := range q.actions {
+		if status == "" || action.Status == status {
+			list = append(list, *action)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+// decide records the decision on a pending action. An approved action
+// stays pending until complete records its outcome, but cannot be decided
+// again. Callers may reject their own actions, not approve them.
+func (q *actionQueue) decide(id string, approve bool, by, reason string) (PendingAction, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := time.Now().UTC()
+	q.expire(now)
+	action, ok := q.actions[id]
+	if !ok {
+		return PendingAction{}, fmt.Errorf("action %s does not exist", id)
+	}
+	if action.Status != ActionPending || action.DecidedAt != nil {
+		return *action, fmt.Errorf("%w: %s", ErrActionNotPending, action.Status)
+	}
+	if approve && by == action.Principal {
+		return *action, ErrSelfApproval
+	}
+	action.DecidedBy, action.DecidedAt, action.Reason = by, &now, reason
+	if !approve {
+		action.Status = ActionRejected
+	}
+	return *action, nil
+}
+
+// complete records the outcome of an approved action
+func (q *actionQueue) complete(id string, result *connector.ResultSet, err error) PendingAction {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	action := q.actions[id]
+	if err != nil {
+		action.Status, action.Error = ActionFailed, err.Error()
+	} else {
+		action.Status, action.Rows = ActionExecuted, result.Rows
+	}
+	return *action
+}
+
+// completeReplay records the outcome of an approved request
+func (q *actionQueue) completeReplay(id string, result interface{}, err error) PendingAction {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	action := q.actions[id]
+	if err != nil {
+		action.Status, action.Error = ActionFailed, err.Error()
+	} else {
+		action.Status, action.Result = ActionExecuted, result
+	}
+	return *action
+}
+
+// stageAction stages a statement of an MCP tool that needs approval,
+// returning the tool result telling the agent so. It returns nil when the
+// statement may run now.
+func (s *MCPServerWithDB) stageAction(ctx context.Context, tool, query string, params map[string]interface{}) (*mcp.CallToolResult, error) {
+	if s.approvals == nil {
+		return nil, nil
+	}
+	kind, ok := s.approvals.requires(query)
+	if !ok {
+		return nil, nil
+	}
+	action := s.approvals.stage(ctx, PendingAction{Tool: tool, SQL: query, Params: params, Kind: kind, Principal: callerTag(ctx).Principal})
+	s.publish(events.TypeActionPending, map[string]interface{}{
+		"id":        action.ID,
+		"tool":      tool,
+		"kind":      string(kind),
+		"query":     query,
+		"principal": action.Principal,
+	})
+	return jsonToolResult(map[string]interface{}{
+		"message": fmt.Sprintf("The %s statement needs approval before it runs; follow it with the action_status tool", kind),
+		"action":  action,
+	})
+}
+
+// approveAction runs an approved action of a queue and records its outcome
+func (s *MCPServerWithDB) approveAction(ctx context.Context, queue *actionQueue, id, by string) (PendingAction, error) {
+	action, err := queue.decide(id, true, by, "")
+	if err != nil {
+		return action, err
+	}
+	// The statement runs as its caller, with the caller's query tag, claims
+	// and connection rather than the approver's
+	ctx = withClaims(connector.WithQueryTag(primaryConnection(ctx), action.queryTag), action.claims)
+	ctx, err = s.restoreConnection(ctx, action.Connection)
+	if err != nil {
+		return queue.complete(id, nil, err), nil
+	}
+	if action.replay != nil {
+		result, err := action.replay(ctx)
+		return queue.completeReplay(id, result, err), nil
+	}
+	result, err := s.executeOrdered(ctx, queue.source, action.SQL, action.Params)
+	return queue.complete(id, result, err), nil
+}
+
+// actionStatusTool lets agents follow the actions they staged; the actions
+// of other callers are reported as missing
+func (s *MCPServerWithDB) actionStatusTool() mcpTool {
+	return mcpTool{
+		Schema: mcp.ToolSchema{
+			Name:        "action_status",
+			Description: "Get the status of a statement staged for approval and, once executed, its result",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]any{
+					"id": map[string]any{"type": "string", "description": "ID of the staged action"},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			id, _ := args["id"].(string)
+			action, ok := s.approvals.get(id)
+			if !ok || action.Principal != callerTag(ctx).Principal {
+				return nil, fmt.Errorf("action %s does not exist", id)
+			}
+			return jsonToolResult(action)
+		},
+	}
+}
+
+// setupApprovalRoutes configures the routes listing and deciding the
+// actions of MCP tools staged for approval
+func (s *MCPServerWithDB) setupApprovalRoutes(router *gin.RouterGroup) {
+	if s.approvals == nil {
+		return
+	}
+	s.setupActionRoutes(router, "/admin/actions", s.approvals.actionQueue)
+}
+
+// setupActionRoutes configures the routes listing and deciding the actions
+// of a queue under path
+func (s *MCPServerWithDB) setupActionRoutes(router *gin.RouterGroup, path string, queue *actionQueue) {
+	// ?status=pending lists the actions waiting for a decision
+	router.GET(path, func(c *gin.Context) {
+		c.JSON(http.StatusOK, queue.list(c.Query("status")))
+	})
+
+	router.GET(path+"/:id", func(c *gin.Context) {
+		action, ok := queue.get(c.Param("id"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Action %s does not exist", c.Param("id"))})
+			return
+		}
+		c.JSON(http.StatusOK, action)
+	})
+
+	router.POST(path+"/:id/approve", func(c *gin.Context) {
+		s.decideAction(c, queue, true, "")
+	})
+
+	router.POST(path+"/:id/reject", func(c *gin.Context) {
+		var req struct {
+			Reason string `json:"reason"`
+		}
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+				return
+			}
+		}
+		s.decideAction(c, queue, false, req.Reason)
+	})
+}
+
+// decideAction approves and runs, or rejects, an action of a queue
+func (s *MCPServerWithDB) decideAction(c *gin.Context, queue *actionQueue, approve bool, reason string) {
+	id := c.Param("id")
+	if _, ok := queue.get(id); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Action %s does not exist", id)})
+		return
+	}
+	principal := principalFromContext(c)
+	var action PendingAction
+	var err error
+	if approve {
+		action, err = s.approveAction(c.Request.Context(), queue, id, principal)
+	} else {
+		action, err = queue.decide(id, false, principal, reason)
+	}
+	if errors.Is(err, ErrSelfApproval) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if s.Audit != nil {
+		_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+			Time:      time.Now().UTC(),
+			Action:    "action_" + action.Status,
+			Principal: principal,
+			Resource:  action.ID,
+			Details:   map[string]interface{}{"source": queue.source, "tool": action.Tool, "sql": action.SQL},
+		})
+	}
+	c.JSON(http.StatusOK, action)
+}
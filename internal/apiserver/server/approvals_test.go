@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// callerConnector records the query tag and claims of the caller its
+// statements run as
+type callerConnector struct {
+	paramsConnector
+	tag    queryTag
+	claims map[string]interface{}
+}
+
+func (c *callerConnector) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.tag, c.claims = callerTag(ctx), claimsFromContext(ctx)
+	return c.paramsConnector.ExecuteQuery(ctx, query, params)
+}
+
+func TestApprovals(t *testing.T) {
+	conn := &callerConnector{}
+	approvals, err := newApprovals(&ApprovalConfig{}, "snowflake")
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, approvals: approvals}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupApprovalRoutes(router.Group(""))
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+	query := s.builtinMCPTools()[2]
+	require.Equal(t, "query", query.Schema.Name)
+	stageAs := func(ctx context.Context, sql string) PendingAction {
+		result, err := query.Handler(ctx, &mcpSession{}, map[string]interface{}{"sql": sql})
+		require.NoError(t, err)
+		var staged struct {
+			Action PendingAction `json:"action"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &staged))
+		return staged.Action
+	}
+	stage := func(sql string) PendingAction {
+		return stageAs(context.Background(), sql)
+	}
+
+	// Reads run at once
+	_, err = query.Handler(context.Background(), &mcpSession{}, map[string]interface{}{"sql": "SELECT * FROM ORDERS"})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM ORDERS", conn.query)
+
+	// Writes wait for approval
+	conn.query = ""
+	purge := stage("DELETE FROM ORDERS WHERE STATUS = 'void'")
+	assert.Equal(t, ActionPending, purge.Status)
+	assert.EqualValues(t, "write", purge.Kind)
+	drop := stage("DROP TABLE ORDERS")
+	assert.Empty(t, conn.query)
+
+	var pending []PendingAction
+	require.NoError(t, json.Unmarshal(request(http.MethodGet, "/admin/actions?status=pending", "").Body.Bytes(), &pending))
+	require.Len(t, pending, 2)
+	assert.Equal(t, purge.ID, pending[0].ID)
+
+	w := request(http.MethodPost, "/admin/actions/"+drop.ID+"/reject", `{"reason": "never drop"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, conn.query)
+
+	w = request(http.MethodPost, "/admin/actions/"+purge.ID+"/approve", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "DELETE FROM ORDERS WHERE STATUS = 'void'", conn.query)
+
+	// Decided actions cannot be decided again
+	assert.Equal(t, http.StatusConflict, request(http.MethodPost, "/admin/actions/"+drop.ID+"/approve", "").Code)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPost, "/admin/actions/unknown/approve", "").Code)
+
+	// Agents follow their actions
+	result, err := s.actionStatusTool().Handler(context.Background(), &mcpSession{}, map[string]interface{}{"id": drop.ID})
+	require.NoError(t, err)
+	var rejected PendingAction
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &rejected))
+	assert.Equal(t, ActionRejected, rejected.Status)
+	assert.Equal(t, "never drop", rejected.Reason)
+	action, _ := approvals.get(purge.ID)
+	assert.Equal(t, ActionExecuted, action.Status)
+
+	// Actions expire without a decision
+	late := stage("UPDATE ORDERS SET STATUS = 'void'")
+	approvals.actions[late.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	assert.Equal(t, http.StatusConflict, request(http.MethodPost, "/admin/actions/"+late.ID+"/approve", "").Code)
+
+	// Session statements and those the parser cannot classify wait too
+	conn.query = ""
+	assert.EqualValues(t, "session", stage("USE ROLE ACCOUNTADMIN").Kind)
+	assert.EqualValues(t, "other", stage("VACUUM ORDERS").Kind)
+	assert.EqualValues(t, "other", stage("SELECT 'unterminated").Kind)
+	assert.Empty(t, conn.query)
+
+	// Approved statements run as their caller, who cannot approve them
+	caller := withClaims(connector.WithQueryTag(context.Background(),
+		s.queryTag("claude-desktop", "1.0", "anonymous@192.0.2.1", "", "s1")), map[string]interface{}{"role": "analyst"})
+	own := stageAs(caller, "UPDATE ORDERS SET STATUS = 'void'")
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/admin/actions/"+own.ID+"/approve", "").Code)
+	approver := withClaims(connector.WithQueryTag(context.Background(),
+		s.queryTag("rest", "", "ops", "", "")), map[string]interface{}{"role": "admin"})
+	action, err = s.approveAction(approver, approvals.actionQueue, own.ID, "ops")
+	require.NoError(t, err)
+	assert.Equal(t, ActionExecuted, action.Status)
+	assert.Equal(t, "anonymous@192.0.2.1", conn.tag.Principal)
+	assert.Equal(t, "claude-desktop", conn.tag.Client)
+	assert.Equal(t, map[string]interface{}{"role": "analyst"}, conn.claims)
+
+	_, err = newApprovals(&ApprovalConfig{Kinds: []string{"delete"}}, "")
+	assert.Error(t, err)
+
+	// Configured kinds cannot let statements of kind other through
+	approvals, err = newApprovals(&ApprovalConfig{Kinds: []string{"ddl"}}, "snowflake")
+	require.NoError(t, err)
+	_, ok := approvals.requires("UPDATE ORDERS SET STATUS = 'void'")
+	assert.False(t, ok)
+	kind, ok := approvals.requires("VACUUM ORDERS")
+	assert.True(t, ok)
+	assert.Equal(t, connector.StatementOther, kind)
+}
+
+func TestApprovalsStageToolWrites(t *testing.T) {
+	conn := &callerConnector{}
+	approvals, err := newApprovals(&ApprovalConfig{}, "snowflake")
+	require.NoError(t, err)
+	saved := newSavedQueries("sales", nil)
+	// Saved queries reloaded from the state store are not checked again
+	saved.queries["void_orders"] = &SavedQuery{
+		Name:       "void_orders",
+		SQL:        "UPDATE ORDERS SET STATUS = 'void' WHERE REGION = :region",
+		Parameters: []SavedQueryParameter{{Name: "region", Type: ParamString, Required: true}},
+	}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, approvals: approvals, saved: saved}
+	caller := connector.WithQueryTag(context.Background(), s.queryTag("claude-desktop", "1.0", "anonymous@192.0.2.1", "", "s1"))
+	staged := func(tool mcpTool, args map[string]interface{}) PendingAction {
+		result, err := tool.Handler(caller, &mcpSession{}, args)
+		require.NoError(t, err)
+		var staged struct {
+			Action PendingAction `json:"action"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &staged))
+		return staged.Action
+	}
+
+	void := staged(s.savedQueryTools()[0], map[string]interface{}{"region": "EU"})
+	assert.Equal(t, ActionPending, void.Status)
+	assert.Equal(t, "void_orders", void.Tool)
+	assert.Empty(t, conn.query)
+
+	// Callers follow only their own actions
+	status := s.actionStatusTool()
+	_, err = status.Handler(caller, &mcpSession{}, map[string]interface{}{"id": void.ID})
+	require.NoError(t, err)
+	other := connector.WithQueryTag(context.Background(), s.queryTag("claude-desktop", "1.0", "anonymous@198.51.100.7", "", "s2"))
+	_, err = status.Handler(other, &mcpSession{}, map[string]interface{}{"id": void.ID})
+	assert.EqualError(t, err, "action "+void.ID+" does not exist")
+}
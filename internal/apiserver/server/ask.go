@@ -0,0 +1,352 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/provenance"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	// maxAskTables caps the number of tables whose schema is sent to the LLM
+	maxAskTables = 20
+	// askMaxRows is the row limit the NL-to-SQL prompt asks the LLM to apply
+	askMaxRows = 1000
+	// summaryRows is the number of result rows shown to the summary prompt
+	summaryRows = 20
+)
+
+// AskResult is the answer to a natural-language question
+type AskResult struct {
+	Question string               `json:"question"`
+	SQL      string               `json:"sql"`
+	Tables   []string             `json:"tables"`
+	Rows     *connector.ResultSet `json:"rows"`
+	Summary  string               `json:"summary,omitempty"`
+}
+
+// nlToSQLPromptData is the data available to the NL-to-SQL template. Schema
+// is a rendered summary of Tables; custom templates can range over Tables
+// to lay the schema out differently.
+type nlToSQLPromptData struct {
+	Dialect  string
+	Schema   string
+	Tables   []*connector.TableMetadata
+	Question string
+	MaxRows  int
+}
+
+// summaryPromptData is the data available to the result summary template
+type summaryPromptData struct {
+	Question string
+	SQL      string
+	RowCount int
+	Rows     string
+}
+
+// setupAskRoutes configures the natural-language query route
+func (s *MCPServerWithDB) setupAskRoutes(router *gin.RouterGroup) {
+	router.POST("/ask", func(c *gin.Context) {
+		if s.llm == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "No LLM provider is configured"})
+			return
+		}
+
+		var request struct {
+			Question  string `json:"question" binding:"required"`
+			Summarize bool   `json:"summarize"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		result, err := s.ask(c.Request.Context(), request.Question, request.Summarize)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to answer question", err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// ask translates a question into SQL, executes it and optionally summarizes
+// the result, recording each step in the session's provenance graph
+func (s *MCPServerWithDB) ask(ctx context.Context, question string, summarize bool) (*AskResult, error) {
+	ctx, node := s.Provenance.Start(ctx, provenance.KindQuestion, question, nil)
+
+	result, err := s.answer(ctx, question, summarize)
+	s.Provenance.Finish(node, err, nil)
+	return result, err
+}
+
+func (s *MCPServerWithDB) answer(ctx context.Context, question string, summarize bool) (*AskResult, error) {
+	// Generated SQL is free-form; row filters cannot be enforced on it
+	if err := s.checkFreeForm(ctx); err != nil {
+		return nil, err
+	}
+
+	query, tables, err := s.generateSQL(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryPolicy(ctx, "ask", query, nil, question); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.executeTracked(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AskResult{
+		Question: question,
+		SQL:      query,
+		Tables:   tables,
+		Rows:     rows,
+	}
+	if summarize {
+		summary, err := s.summarizeResult(ctx, question, query, rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Summary = summary
+	}
+	return result, nil
+}
+
+// generateSQL asks the LLM for a query answering the question, returning it
+// with the tables whose schema was provided
+func (s *MCPServerWithDB) generateSQL(ctx context.Context, question string) (string, []string, error) {
+	tables, err := s.candidateTables(ctx, question)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var schema strings.Builder
+	metadata := make([]*connector.TableMetadata, 0, len(tables))
+	for _, table := range tables {
+		m, err := s.DBConn.GetTableMetadata(ctx, table)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get metadata for table %s: %w", table, err)
+		}
+		metadata = append(metadata, m)
+		schema.WriteString(tableSearchText(m))
+		schema.WriteString(tableIndexText(m))
+		schema.WriteString("\n")
+	}
+
+	system, _, err := s.Prompts.Render(prompt.NameNLToSQL, nlToSQLPromptData{
+		Dialect:  dialectName(s.Config.Database.Type),
+		Schema:   schema.String(),
+		Tables:   metadata,
+		Question: question,
+		MaxRows:  askMaxRows,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	resp, err := s.llm.Complete(ctx, &llm.Request{
+		System:   system,
+		Messages: []llm.Message{{Role: llm.RoleUser, Content: question}},
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate SQL: %w", err)
+	}
+
+	query := extractSQL(resp.Text)
+	if !isReadOnlySQL(query) {
+		s.publish(events.TypePolicyViolation, map[string]interface{}{
+			"policy": policyReadOnly,
+			"source": "ask",
+			"query":  query,
+		})
+		return "", nil, fmt.Errorf("generated SQL is not a read-only query: %s", query)
+	}
+	return query, tables, nil
+}
+
+// tableIndexText lists a table's indexes and keys, so generated queries
+// prefer filters the database serves efficiently
+func tableIndexText(metadata *connector.TableMetadata) string {
+	var b strings.Builder
+	for _, idx := range metadata.Indexes {
+		kind := "Index"
+		switch {
+		case idx.Type == connector.IndexTypeClustering:
+			kind = "Clustered by"
+		case idx.Unique:
+			kind = "Unique index"
+		}
+		fmt.Fprintf(&b, " %s %s;", kind, strings.Join(idx.Columns, ", "))
+	}
+	for _, c := range metadata.Constraints {
+		if c.Type == connector.ConstraintCheck {
+			fmt.Fprintf(&b, " CHECK (%s);", c.Check)
+			continue
+		}
+		fmt.Fprintf(&b, " %s (%s);", c.Type, strings.Join(c.Columns, ", "))
+	}
+	return b.String()
+}
+
+// candidateTables picks the tables relevant to a question, using semantic
+// search when it is configured
+func (s *MCPServerWithDB) candidateTables(ctx context.Context, question string) ([]string, error) {
+	if s.tableSearch != nil {
+		matches, err := s.searchTables(ctx, question, maxAskTables)
+		if err != nil {
+			return nil, err
+		}
+		tables := make([]string, 0, len(matches))
+		for _, m := range matches {
+			tables = append(tables, m.Table)
+		}
+		return tables, nil
+	}
+
+	infos, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	if len(infos) > maxAskTables {
+		infos = infos[:maxAskTables]
+	}
+	tables := make([]string, 0, len(infos))
+	for _, t := range infos {
+		tables = append(tables, t.Name)
+	}
+	return tables, nil
+}
+
+// summarizeResult asks the LLM for a short answer based on the query result
+func (s *MCPServerWithDB) summarizeResult(ctx context.Context, question, query string, rows *connector.ResultSet) (string, error) {
+	ctx, node := s.Provenance.Start(ctx, provenance.KindSummary, "summarize", nil)
+
+	sample := &connector.ResultSet{Columns: rows.Columns, Rows: rows.Rows}
+	if len(sample.Rows) > summaryRows {
+		sample.Rows = sample.Rows[:summaryRows]
+	}
+	data, err := json.Marshal(sample)
+	if err != nil {
+		s.Provenance.Finish(node, err, nil)
+		return "", fmt.Errorf("failed to encode rows: %w", err)
+	}
+
+	system, _, err := s.Prompts.Render(prompt.NameResultSummary, summaryPromptData{
+		Question: question,
+		SQL:      query,
+		RowCount: len(rows.Rows),
+		Rows:     string(data),
+	})
+	if err != nil {
+		s.Provenance.Finish(node, err, nil)
+		return "", err
+	}
+
+	resp, err := s.llm.Complete(ctx, &llm.Request{
+		System:   system,
+		Messages: []llm.Message{{Role: llm.RoleUser, Content: question}},
+	})
+	if err != nil {
+		s.Provenance.Finish(node, err, nil)
+		return "", fmt.Errorf("failed to summarize result: %w", err)
+	}
+
+	summary := strings.TrimSpace(resp.Text)
+	s.Provenance.Finish(node, nil, map[string]interface{}{"summary": summary})
+	return summary, nil
+}
+
+// extractSQL strips code fences and trailing semicolons from an LLM response
+func extractSQL(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```")
+		if i := strings.Index(text, "\n"); i >= 0 {
+			text = text[i+1:]
+		}
+		if i := strings.LastIndex(text, "```"); i >= 0 {
+			text = text[:i]
+		}
+	}
+	return strings.TrimRight(strings.TrimSpace(text), ";")
+}
+
+// isReadOnlySQL reports whether a query is a single read-only statement.
+// Semicolons are rejected even in literals, so that databases whose
+// literals end elsewhere cannot see a second statement.
+func isReadOnlySQL(query string) bool {
+	if strings.Contains(query, ";") {
+		return false
+	}
+	statements, err := connector.ParseStatements(connector.SQLSyntax{}, query)
+	return err == nil && len(statements) == 1 && statements[0].Kind == connector.StatementRead
+}
+
+// dialectName returns the SQL dialect name of a database type
+func dialectName(dbType string) string {
+	switch dbType {
+	case "snowflake":
+		return "Snowflake"
+	case "postgres":
+		return "PostgreSQL"
+	case "mysql":
+		return "MySQL"
+	default:
+		return "ANSI"
+	}
+}
+
+// askTool exposes natural-language querying as an MCP tool
+func (s *MCPServerWithDB) askTool() mcpTool {
+	return mcpTool{
+		Schema: mcp.ToolSchema{
+			Name:        "ask",
+			Description: "Answer a natural-language question about the data by generating and running a read-only SQL query",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]any{
+					"question":  map[string]any{"type": "string", "description": "The question to answer"},
+					"summarize": map[string]any{"type": "boolean", "description": "Also return a short natural-language answer"},
+				},
+				Required: []string{"question"},
+			},
+		},
+		Handler: func(ctx context.Context, sess *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			question, _ := args["question"].(string)
+			if question == "" {
+				return nil, fmt.Errorf("question is required")
+			}
+			summarize, _ := args["summarize"].(bool)
+
+			answer, err := s.ask(ctx, question, summarize)
+			if err != nil {
+				return nil, err
+			}
+
+			result, err := s.rowsToolResult("ask", sess, answer.Rows)
+			if err != nil {
+				return nil, err
+			}
+			header := fmt.Sprintf("SQL: %s", answer.SQL)
+			if answer.Summary != "" {
+				header = fmt.Sprintf("%s\nAnswer: %s", header, answer.Summary)
+			}
+			result.Content = append([]mcp.Content{
+				&mcp.TextContent{Type: mcp.TextContentType, Text: header},
+			}, result.Content...)
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// Table check types
+const (
+	CheckNotNull        = "not_null"
+	CheckUnique         = "unique"
+	CheckAcceptedValues = "accepted_values"
+	CheckSQL            = "sql"
+)
+
+// maxTableChecks bounds the checks of one assertion request
+const maxTableChecks = 50
+
+// ErrInvalidAssertion is returned for malformed table checks
+var ErrInvalidAssertion = errors.New("invalid assertion")
+
+// TableCheck is an expectation on a table's data
+type TableCheck struct {
+	// Type is not_null, unique, accepted_values or sql
+	Type string `json:"type"`
+
+	// Column is the column checked by not_null, unique and accepted_values
+	Column string `json:"column,omitempty"`
+
+	// Values are the values accepted_values allows; nulls are left to
+	// not_null
+	Values []interface{} `json:"values,omitempty"`
+
+	// SQL is a read query returning the rows that violate the expectation;
+	// the check passes when it returns none
+	SQL string `json:"sql,omitempty"`
+}
+
+// CheckResult is the outcome of a check. Failures counts the null rows,
+// the duplicated values, the rows with other values or the rows returned
+// by the SQL.
+type CheckResult struct {
+	TableCheck
+	Passed   bool   `json:"passed"`
+	Failures int64  `json:"failures"`
+	Error    string `json:"error,omitempty"`
+}
+
+// AssertionReport is the outcome of the checks of a table
+type AssertionReport struct {
+	Table     string        `json:"table"`
+	Passed    bool          `json:"passed"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// validateTableChecks rejects requests with no checks, too many checks or
+// checks missing their inputs
+func validateTableChecks(checks []TableCheck) error {
+	if len(checks) == 0 {
+		return fmt.Errorf("%w: at least one check is required", ErrInvalidAssertion)
+	}
+	if len(checks) > maxTableChecks {
+		return fmt.Errorf("%w: at most %d checks are allowed", ErrInvalidAssertion, maxTableChecks)
+	}
+	for i, check := range checks {
+		switch check.Type {
+		case CheckNotNull, CheckUnique, CheckAcceptedValues:
+			if check.Column == "" {
+				return fmt.Errorf("%w: check %d (%s) requires a column", ErrInvalidAssertion, i, check.Type)
+			}
+			if check.Type == CheckAcceptedValues && len(check.Values) == 0 {
+				return fmt.Errorf("%w: check %d (%s) requires values", ErrInvalidAssertion, i, check.Type)
+			}
+		case CheckSQL:
+			if !isReadOnlySQL(check.SQL) {
+				return fmt.Errorf("%w: check %d (%s) requires a single read-only statement", ErrInvalidAssertion, i, check.Type)
+			}
+		default:
+			return fmt.Errorf("%w: check %d has unsupported type %q", ErrInvalidAssertion, i, check.Type)
+		}
+	}
+	return nil
+}
+
+// checkQuery returns the query counting a check's failures
+func checkQuery(d connector.Dialect, table string, check TableCheck) (string, map[string]interface{}) {
+	failures := d.QuoteIdentifier("FAILURES")
+	column := d.QuoteIdentifier(check.Column)
+	switch check.Type {
+	case CheckNotNull:
+		return fmt.Sprintf("SELECT COUNT(*) AS %s FROM %s WHERE %s IS NULL", failures, d.Table(table), column), nil
+	case CheckUnique:
+		return fmt.Sprintf("SELECT COUNT(*) AS %s FROM (SELECT %s FROM %s WHERE %s IS NOT NULL GROUP BY %s HAVING COUNT(*) > 1) duplicates",
+			failures, column, d.Table(table), column, column), nil
+	case CheckAcceptedValues:
+		params := make(map[string]interface{}, len(check.Values))
+		placeholders := make([]string, len(check.Values))
+		for i, v := range check.Values {
+			name := fmt.Sprintf("accepted_%d", i)
+			params[name] = v
+			placeholders[i] = ":" + name
+		}
+		return fmt.Sprintf("SELECT COUNT(*) AS %s FROM %s WHERE %s IS NOT NULL AND %s NOT IN (%s)",
+			failures, d.Table(table), column, column, strings.Join(placeholders, ", ")), params
+	default:
+		return fmt.Sprintf("SELECT COUNT(*) AS %s FROM (%s) violations", failures, strings.TrimSpace(check.SQL)), nil
+	}
+}
+
+// assertTable runs checks against a table. A check that cannot run fails
+// with its error rather than aborting the others.
+func (s *MCPServerWithDB) assertTable(ctx context.Context, source, table string, checks []TableCheck) (*AssertionReport, error) {
+	if err := validateTableChecks(checks); err != nil {
+		return nil, err
+	}
+	if err := s.checkTableName(ctx, table); err != nil {
+		return nil, err
+	}
+	// Counts cover the whole table, which row filters cannot restrict
+	if err := s.checkFreeForm(ctx); err != nil {
+		return nil, err
+	}
+	for _, check := range checks {
+		if check.Type != CheckSQL {
+			continue
+		}
+		if err := s.checkQueryPolicy(ctx, source, check.SQL, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	d := connector.DialectOf(s.DBConn)
+	report := &AssertionReport{Table: table, Passed: true, CheckedAt: time.Now().UTC(), Checks: make([]CheckResult, len(checks))}
+	for i, check := range checks {
+		result := CheckResult{TableCheck: check}
+		query, params := checkQuery(d, table, check)
+		rows, err := s.executeQuery(ctx, source, query, params)
+		if err == nil && len(rows) != 1 {
+			err = fmt.Errorf("expected one row, got %d", len(rows))
+		}
+		if err == nil {
+			result.Failures, err = countValue(rows[0]["FAILURES"])
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		result.Passed = err == nil && result.Failures == 0
+		report.Passed = report.Passed && result.Passed
+		report.Checks[i] = result
+	}
+	return report, nil
+}
+
+// setupAssertionRoutes configures the data quality assertion route
+func (s *MCPServerWithDB) setupAssertionRoutes(router *gin.RouterGroup) {
+	// Responds 200 with a report whether or not the checks pass
+	router.POST("/tables/:tableName/assert", func(c *gin.Context) {
+		var request struct {
+			Checks []TableCheck `json:"checks"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if err := validateTableChecks(request.Checks); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to assert table", err)
+			return
+		}
+		table := c.Param("tableName")
+		queries := make([]string, len(request.Checks))
+		for i, check := range request.Checks {
+			queries[i], _ = checkQuery(connector.DialectOf(s.DBConn), table, check)
+		}
+		if s.parkRequest(c, strings.Join(queries, ";\n"), nil, func(ctx context.Context) (interface{}, error) {
+			return s.assertTable(ctx, "rest", table, request.Checks)
+		}) {
+			return
+		}
+		report, err := s.assertTable(c.Request.Context(), "rest", table, request.Checks)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to assert table", err)
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	})
+}
+
+// assertTableTool exposes the table assertions as an MCP tool
+func (s *MCPServerWithDB) assertTableTool() mcpTool {
+	return mcpTool{
+		Schema: mcp.ToolSchema{
+			Name:        "assert_table",
+			Description: "Run data quality checks against a table and report which pass: not_null, unique and accepted_values on a column, or sql returning the rows that violate an expectation",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]any{
+					"table": map[string]any{"type": "string", "description": "Name of the table"},
+					"checks": map[string]any{
+						"type": "array",
+						"items": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"type":   map[string]any{"type": "string", "enum": []string{CheckNotNull, CheckUnique, CheckAcceptedValues, CheckSQL}},
+								"column": map[string]any{"type": "string", "description": "Column checked by not_null, unique and accepted_values"},
+								"values": map[string]any{"type": "array", "description": "Values allowed by accepted_values"},
+								"sql":    map[string]any{"type": "string", "description": "Read query returning the violating rows, for sql checks"},
+							},
+							"required": []string{"type"},
+						},
+					},
+				},
+				Required: []string{"table", "checks"},
+			},
+		},
+		Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			table, _ := args["table"].(string)
+			if table == "" {
+				return nil, fmt.Errorf("table is required")
+			}
+			var checks []TableCheck
+			data, err := json.Marshal(args["checks"])
+			if err == nil {
+				err = json.Unmarshal(data, &checks)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidAssertion, err)
+			}
+			report, err := s.assertTable(ctx, "mcp", table, checks)
+			if err != nil {
+				return nil, err
+			}
+			return jsonToolResult(report)
+		},
+	}
+}
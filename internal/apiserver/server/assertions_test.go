@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// assertConnector counts failures by the kind of check a query runs
+type assertConnector struct {
+	connector.DatabaseConnector
+	queries []string
+	params  []map[string]interface{}
+}
+
+func (c *assertConnector) ListTables(context.Context) ([]connector.Table, error) {
+	return []connector.Table{{Name: "ORDERS"}}, nil
+}
+
+func (c *assertConnector) ExecuteQuery(_ context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.queries = append(c.queries, query)
+	c.params = append(c.params, params)
+	switch {
+	case strings.Contains(query, "MISSING"):
+		return nil, errors.New("invalid identifier MISSING")
+	case strings.Contains(query, "HAVING"):
+		return []map[string]interface{}{{"FAILURES": 2.0}}, nil
+	}
+	return []map[string]interface{}{{"FAILURES": 0.0}}, nil
+}
+
+func TestAssertTable(t *testing.T) {
+	conn := &assertConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAssertionRoutes(router.Group(""))
+	request := func(target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := request("/tables/ORDERS/assert", `{"checks":[
+		{"type":"not_null","column":"ID"},
+		{"type":"unique","column":"ID"},
+		{"type":"accepted_values","column":"STATUS","values":["open","closed"]},
+		{"type":"sql","sql":"SELECT * FROM ORDERS WHERE TOTAL < 0"},
+		{"type":"not_null","column":"MISSING"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report AssertionReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.False(t, report.Passed)
+	require.Len(t, report.Checks, 5)
+	assert.True(t, report.Checks[0].Passed)
+	assert.False(t, report.Checks[1].Passed)
+	assert.EqualValues(t, 2, report.Checks[1].Failures)
+	assert.True(t, report.Checks[2].Passed)
+	assert.True(t, report.Checks[3].Passed)
+	assert.False(t, report.Checks[4].Passed)
+	assert.Contains(t, report.Checks[4].Error, "MISSING")
+
+	assert.Equal(t, `SELECT COUNT(*) AS "FAILURES" FROM "ORDERS" WHERE "ID" IS NULL`, conn.queries[0])
+	assert.Equal(t, `SELECT COUNT(*) AS "FAILURES" FROM "ORDERS" WHERE "STATUS" IS NOT NULL AND "STATUS" NOT IN (:accepted_0, :accepted_1)`, conn.queries[2])
+	assert.Equal(t, map[string]interface{}{"accepted_0": "open", "accepted_1": "closed"}, conn.params[2])
+	assert.Equal(t, `SELECT COUNT(*) AS "FAILURES" FROM (SELECT * FROM ORDERS WHERE TOTAL < 0) violations`, conn.queries[3])
+
+	assert.Equal(t, http.StatusBadRequest, request("/tables/ORDERS/assert", `{"checks":[]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request("/tables/ORDERS/assert", `{"checks":[{"type":"unique"}]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request("/tables/ORDERS/assert", `{"checks":[{"type":"sql","sql":"DELETE FROM ORDERS"}]}`).Code)
+	assert.Equal(t, http.StatusNotFound, request("/tables/CUSTOMERS/assert", `{"checks":[{"type":"not_null","column":"ID"}]}`).Code)
+
+	// The MCP tool returns the same report
+	result, err := s.assertTableTool().Handler(context.Background(), nil, map[string]interface{}{
+		"table":  "ORDERS",
+		"checks": []interface{}{map[string]interface{}{"type": "unique", "column": "ID"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &report))
+	assert.False(t, report.Passed)
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	queryTagApp        = "mcp-db-gateway"
+	untaggedSpend      = "(untagged)"
+	defaultSpendPeriod = 24 * time.Hour
+	webhookTimeout     = 30 * time.Second
+)
+
+// SpendReportConfig schedules delivery of the spend attribution report
+type SpendReportConfig struct {
+	// Interval between reports, each covering the preceding interval (default: 24h)
+	Interval string `json:"interval,omitempty"`
+
+	// WebhookURL receives the report as a JSON POST
+	WebhookURL string `json:"webhook_url"`
+
+	// Headers are added to the webhook request, e.g. for authentication
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// queryTag identifies who issued a query; it is attached to warehouse
+// queries so their spend can be attributed from the query history
+type queryTag struct {
+	App           string `json:"app"`
+	Server        string `json:"server"`
+	Client        string `json:"client,omitempty"`
+	ClientVersion string `json:"client_version,omitempty"`
+	Principal     string `json:"principal,omitempty"`
+	APIKey        string `json:"api_key,omitempty"`
+	Session       string `json:"session,omitempty"`
+}
+
+// SpendAttribution breaks the warehouse spend of a period down by MCP client
+// application, by agent and by the ID of the tenant API key
+type SpendAttribution struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	TotalCredits float64            `json:"total_credits"`
+	Clients      []AttributionEntry `json:"clients"`
+	Agents       []AttributionEntry `json:"agents"`
+	APIKeys      []AttributionEntry `json:"api_keys"`
+}
+
+// AttributionEntry is the spend of one client, agent or API key
+type AttributionEntry struct {
+	Name         string  `json:"name"`
+	Queries      int     `json:"queries"`
+	ExecutionMs  float64 `json:"execution_ms"`
+	Credits      float64 `json:"credits"`
+	BytesScanned int64   `json:"bytes_scanned"`
+	Share        float64 `json:"share"`
+}
+
+// queryTag renders the query tag for a caller. apiKey is the ID of the
+// tenant API key that authenticated the caller, if any.
+func (s *MCPServerWithDB) queryTag(client, clientVersion, principal, apiKey, session string) string {
+	data, _ := json.Marshal(queryTag{
+		App:           queryTagApp,
+		Server:        s.Config.Name,
+		Client:        client,
+		ClientVersion: clientVersion,
+		Principal:     principal,
+		APIKey:        apiKey,
+		Session:       session,
+	})
+	return string(data)
+}
+
+// callerTag returns the query tag of a context, empty when it has none or
+// it was not set by a server
+func callerTag(ctx context.Context) queryTag {
+	var tag queryTag
+	if raw := connector.QueryTagFromContext(ctx); raw != "" {
+		_ = json.Unmarshal([]byte(raw), &tag)
+	}
+	return tag
+}
+
+// queryTagMiddleware tags the queries of REST requests with the caller
+func (s *MCPServerWithDB) queryTagMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag := s.queryTag("rest", "", principalFromContext(c), apiKeyFromContext(c), "")
+		c.Request = c.Request.WithContext(connector.WithQueryTag(c.Request.Context(), tag))
+		c.Next()
+	}
+}
+
+// setupAttributionRoutes configures the spend attribution report route
+func (s *MCPServerWithDB) setupAttributionRoutes(router *gin.RouterGroup) {
+	if _, ok := s.DBConn.(connector.SpendAttributor); !ok {
+		return
+	}
+
+	router.GET("/admin/spend/attribution", func(c *gin.Context) {
+		from, to, err := spendPeriod(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		report, err := s.SpendAttribution(c.Request.Context(), from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to build spend attribution: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	})
+}
+
+// spendPeriod parses the period of a spend report request: the last period
+// (default: 24h) or the from and to times
+func spendPeriod(c *gin.Context) (from, to time.Time, err error) {
+	to = time.Now().UTC()
+	from = to.Add(-defaultSpendPeriod)
+	if v := c.Query("period"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return from, to, fmt.Errorf("invalid period: %s", v)
+		}
+		from = to.Add(-d)
+	}
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid from: %s", v)
+		}
+		from = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid to: %s", v)
+		}
+		to = t
+	}
+	return from, to, nil
+}
+
+// SpendAttribution builds the spend attribution report for a period
+func (s *MCPServerWithDB) SpendAttribution(ctx context.Context, from, to time.Time) (*SpendAttribution, error) {
+	attributor, ok := s.DBConn.(connector.SpendAttributor)
+	if !ok {
+		return nil, fmt.Errorf("the database does not support spend attribution")
+	}
+
+	spends, err := attributor.SpendByQueryTag(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return attributeSpend(spends, s.Config.Name, from, to), nil
+}
+
+// attributeSpend aggregates tagged spend per client, per agent and per API
+// key. Queries not issued through this server are reported as untagged.
+func attributeSpend(spends []connector.TagSpend, server string, from, to time.Time) *SpendAttribution {
+	clients := make(map[string]*AttributionEntry)
+	agents := make(map[string]*AttributionEntry)
+	keys := make(map[string]*AttributionEntry)
+	add := func(m map[string]*AttributionEntry, name string, spend connector.TagSpend) {
+		e, ok := m[name]
+		if !ok {
+			e = &AttributionEntry{Name: name}
+			m[name] = e
+		}
+		e.Queries += spend.Queries
+		e.ExecutionMs += spend.ExecutionMs
+		e.Credits += spend.Credits
+		e.BytesScanned += spend.BytesScanned
+	}
+
+	report := &SpendAttribution{From: from, To: to}
+	for _, spend := range spends {
+		report.TotalCredits += spend.Credits
+
+		client, agent, key := untaggedSpend, untaggedSpend, untaggedSpend
+		var tag queryTag
+		if err := json.Unmarshal([]byte(spend.QueryTag), &tag); err == nil && tag.App == queryTagApp && tag.Server == server {
+			if tag.Client != "" {
+				client = tag.Client
+			}
+			if tag.Principal != "" {
+				agent = tag.Principal
+			}
+			if tag.APIKey != "" {
+				key = tag.APIKey
+			}
+		}
+		add(clients, client, spend)
+		add(agents, agent, spend)
+		add(keys, key, spend)
+	}
+
+	report.Clients = sortedEntries(clients, report.TotalCredits)
+	report.Agents = sortedEntries(agents, report.TotalCredits)
+	report.APIKeys = sortedEntries(keys, report.TotalCredits)
+	return report
+}
+
+// sortedEntries returns the entries by descending credits with their share of the total
+func sortedEntries(m map[string]*AttributionEntry, total float64) []AttributionEntry {
+	entries := make([]AttributionEntry, 0, len(m))
+	for _, e := range m {
+		if total > 0 {
+			e.Share = e.Credits / total
+		}
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Credits == entries[j].Credits {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Credits > entries[j].Credits
+	})
+	return entries
+}
+
+// runSpendReports delivers the attribution report to the configured webhook
+// every interval until the server stops
+func (s *MCPServerWithDB) runSpendReports(cfg *SpendReportConfig) {
+	interval := defaultSpendPeriod
+	if cfg.Interval != "" {
+		if d, err := time.ParseDuration(cfg.Interval); err == nil && d > 0 {
+			interval = d
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.deliverSpendReport(cfg, now.UTC().Add(-interval), now.UTC()); err != nil {
+				log.Printf("Warning: Failed to deliver spend attribution report: %v", err)
+			}
+		}
+	}
+}
+
+// deliverSpendReport posts the attribution report of a period to the webhook
+func (s *MCPServerWithDB) deliverSpendReport(cfg *SpendReportConfig, from, to time.Time) error {
+	ctx, cancel := context.WithTimeout(s.ctx, webhookTimeout)
+	defer cancel()
+
+	report, err := s.SpendAttribution(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	return postWebhook(ctx, cfg.WebhookURL, cfg.Headers, report)
+}
+
+// postWebhook posts a JSON payload to a webhook
+func postWebhook(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
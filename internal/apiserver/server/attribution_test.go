@@ -0,0 +1,44 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestAttributeSpend(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}}
+	spends := []connector.TagSpend{
+		{QueryTag: s.queryTag("claude-desktop", "1.0", "alice", "", "s1"), Queries: 3, Credits: 3},
+		{QueryTag: s.queryTag("claude-desktop", "1.0", "bob", "", "s2"), Queries: 1, Credits: 1},
+		{QueryTag: s.queryTag("rest", "", "alice", "key-1", ""), Queries: 2, Credits: 2},
+		{QueryTag: "nightly-etl", Queries: 5, Credits: 3.5},
+	}
+
+	report := attributeSpend(spends, "sales", time.Time{}, time.Time{})
+	assert.Equal(t, 9.5, report.TotalCredits)
+
+	require.Len(t, report.Clients, 3)
+	assert.Equal(t, "claude-desktop", report.Clients[0].Name)
+	assert.Equal(t, 4, report.Clients[0].Queries)
+	assert.Equal(t, untaggedSpend, report.Clients[1].Name)
+	assert.Equal(t, "rest", report.Clients[2].Name)
+	assert.InDelta(t, 2/9.5, report.Clients[2].Share, 1e-9)
+
+	require.Len(t, report.Agents, 3)
+	assert.Equal(t, "alice", report.Agents[0].Name)
+	assert.Equal(t, 5.0, report.Agents[0].Credits)
+
+	require.Len(t, report.APIKeys, 2)
+	assert.Equal(t, AttributionEntry{Name: untaggedSpend, Queries: 9, Credits: 7.5, Share: 7.5 / 9.5}, report.APIKeys[0])
+	assert.Equal(t, "key-1", report.APIKeys[1].Name)
+
+	// Tags of another server sharing the warehouse are not attributed
+	other := attributeSpend(spends, "finance", time.Time{}, time.Time{})
+	require.Len(t, other.Clients, 1)
+	assert.Equal(t, untaggedSpend, other.Clients[0].Name)
+}
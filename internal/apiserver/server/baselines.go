@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	// baselineTTL is how long a baseline is kept after it was taken
+	baselineTTL = 24 * time.Hour
+
+	// maxBaselines bounds the baselines kept in memory; the oldest are
+	// evicted first
+	maxBaselines = 100
+
+	// maxBaselineRows bounds the rows of a baseline
+	maxBaselineRows = 10000
+)
+
+var (
+	// ErrBaselineNotFound is returned for unknown or expired baselines
+	ErrBaselineNotFound = errors.New("baseline not found")
+
+	// ErrInvalidBaseline is returned for baselines of queries that are not
+	// single reads
+	ErrInvalidBaseline = errors.New("invalid baseline")
+)
+
+// Baseline is the result of a read query kept to be compared with later
+// runs of the query, e.g. before and after a pipeline loads data
+type Baseline struct {
+	ID     string                 `json:"id"`
+	Query  string                 `json:"query"`
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Key is the column matching rows between runs; without one, rows are
+	// matched by their whole content and a changed row is reported as
+	// removed and added
+	Key string `json:"key,omitempty"`
+
+	Rows      int       `json:"rows"`
+	TakenAt   time.Time `json:"taken_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	rows []map[string]interface{}
+}
+
+// ResultDiff is the difference between a baseline and the query's current
+// result
+type ResultDiff struct {
+	BaselineID string                   `json:"baseline_id"`
+	TakenAt    time.Time                `json:"taken_at"`
+	Added      []map[string]interface{} `json:"added"`
+	Updated    []map[string]interface{} `json:"updated"`
+	Removed    []map[string]interface{} `json:"removed"`
+	Unchanged  int                      `json:"unchanged"`
+}
+
+// baselineStore keeps baselines in memory for a limited time
+type baselineStore struct {
+	mu        sync.Mutex
+	baselines map[string]*Baseline
+	order     []string
+}
+
+func newBaselineStore() *baselineStore {
+	return &baselineStore{baselines: make(map[string]*Baseline)}
+}
+
+// put stores a baseline, evicting the oldest ones beyond capacity
+func (st *baselineStore) put(b *Baseline) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.evictExpiredLocked(time.Now())
+	if _, ok := st.baselines[b.ID]; !ok {
+		st.order = append(st.order, b.ID)
+	}
+	st.baselines[b.ID] = b
+	for len(st.order) > maxBaselines {
+		delete(st.baselines, st.order[0])
+		st.order = st.order[1:]
+	}
+}
+
+// get looks up a baseline by ID
+func (st *baselineStore) get(id string) (*Baseline, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.evictExpiredLocked(time.Now())
+	b, ok := st.baselines[id]
+	if !ok {
+		return nil, ErrBaselineNotFound
+	}
+	return b, nil
+}
+
+// delete removes a baseline
+func (st *baselineStore) delete(id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.baselines[id]; !ok {
+		return ErrBaselineNotFound
+	}
+	delete(st.baselines, id)
+	for i, other := range st.order {
+		if other == id {
+			st.order = append(st.order[:i], st.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (st *baselineStore) evictExpiredLocked(now time.Time) {
+	kept := st.order[:0]
+	for _, id := range st.order {
+		if now.After(st.baselines[id].ExpiresAt) {
+			delete(st.baselines, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	st.order = kept
+}
+
+// runBaselineQuery runs the query of a baseline under the checks of
+// free-form SQL
+func (s *MCPServerWithDB) runBaselineQuery(ctx context.Context, source, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if !isReadOnlySQL(query) {
+		return nil, fmt.Errorf("%w: the query must be a single read-only statement", ErrInvalidBaseline)
+	}
+	if err := s.checkFreeForm(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.checkQueryPolicy(ctx, source, query, params); err != nil {
+		return nil, err
+	}
+	rows, err := s.executeQuery(ctx, source, query, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > maxBaselineRows {
+		return nil, fmt.Errorf("%w: baselines are limited to %d rows", ErrLimitExceeded, maxBaselineRows)
+	}
+	return rows, nil
+}
+
+// takeBaseline runs a query and keeps its result
+func (s *MCPServerWithDB) takeBaseline(ctx context.Context, source, query string, params map[string]interface{}, key string) (*Baseline, error) {
+	rows, err := s.runBaselineQuery(ctx, source, query, params)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	b := &Baseline{
+		ID:        uuid.New().String(),
+		Query:     query,
+		Params:    params,
+		Key:       key,
+		Rows:      len(rows),
+		TakenAt:   now,
+		ExpiresAt: now.Add(baselineTTL),
+		rows:      rows,
+	}
+	s.baselines.put(b)
+	return b, nil
+}
+
+// diffBaseline runs a baseline's query again and compares the result with
+// the baseline. advance makes the new result the baseline.
+func (s *MCPServerWithDB) diffBaseline(ctx context.Context, source, id string, advance bool) (*ResultDiff, error) {
+	b, err := s.baselines.get(id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.runBaselineQuery(ctx, source, b.Query, b.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &ResultDiff{BaselineID: b.ID, TakenAt: b.TakenAt}
+	diff.Added, diff.Updated, diff.Removed = diffRows(b.rows, rows, b.Key)
+	diff.Unchanged = len(rows) - len(diff.Added) - len(diff.Updated)
+	for _, list := range []*[]map[string]interface{}{&diff.Added, &diff.Updated, &diff.Removed} {
+		if *list == nil {
+			*list = []map[string]interface{}{}
+		}
+	}
+
+	if advance {
+		now := time.Now().UTC()
+		next := *b
+		next.rows, next.Rows, next.TakenAt, next.ExpiresAt = rows, len(rows), now, now.Add(baselineTTL)
+		s.baselines.put(&next)
+	}
+	return diff, nil
+}
+
+// baselineTools returns the MCP tools taking baselines and diffing results
+// against them
+func (s *MCPServerWithDB) baselineTools() []mcpTool {
+	return []mcpTool{
+		{
+			Schema: mcp.ToolSchema{
+				Name:        "baseline_query",
+				Description: "Run a read query and keep its result as a baseline, to compare with a later run using diff_query",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"sql":    map[string]any{"type": "string", "description": "SQL query with :name placeholders"},
+						"params": map[string]any{"type": "object", "description": "Values for the named placeholders"},
+						"key":    map[string]any{"type": "string", "description": "Column identifying rows, so changed rows are reported as updated"},
+					},
+					Required: []string{"sql"},
+				},
+			},
+			Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				query, _ := args["sql"].(string)
+				if query == "" {
+					return nil, fmt.Errorf("sql is required")
+				}
+				params, _ := args["params"].(map[string]interface{})
+				key, _ := args["key"].(string)
+				b, err := s.takeBaseline(ctx, "mcp", query, params, key)
+				if err != nil {
+					return nil, err
+				}
+				return jsonToolResult(b)
+			},
+		},
+		{
+			Schema: mcp.ToolSchema{
+				Name:        "diff_query",
+				Description: "Run the query of a baseline again and return the rows added, updated and removed since the baseline was taken",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"baseline_id": map[string]any{"type": "string", "description": "ID returned by baseline_query"},
+						"advance":     map[string]any{"type": "boolean", "description": "Make the new result the baseline"},
+					},
+					Required: []string{"baseline_id"},
+				},
+			},
+			Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				id, _ := args["baseline_id"].(string)
+				advance, _ := args["advance"].(bool)
+				diff, err := s.diffBaseline(ctx, "mcp", id, advance)
+				if err != nil {
+					return nil, err
+				}
+				return jsonToolResult(diff)
+			},
+		},
+	}
+}
+
+// setupBaselineRoutes configures the routes taking baselines and diffing
+// results against them
+func (s *MCPServerWithDB) setupBaselineRoutes(router *gin.RouterGroup) {
+	router.POST("/query/baselines", func(c *gin.Context) {
+		var request struct {
+			Query  string                 `json:"query" binding:"required"`
+			Params map[string]interface{} `json:"params"`
+			Key    string                 `json:"key"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if s.parkRequest(c, request.Query, request.Params, func(ctx context.Context) (interface{}, error) {
+			return s.takeBaseline(ctx, "rest", request.Query, request.Params, request.Key)
+		}) {
+			return
+		}
+		b, err := s.takeBaseline(c.Request.Context(), "rest", request.Query, request.Params, request.Key)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to take baseline", err)
+			return
+		}
+		c.JSON(http.StatusCreated, b)
+	})
+
+	router.GET("/query/baselines/:id", func(c *gin.Context) {
+		b, err := s.baselines.get(c.Param("id"))
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to get baseline", err)
+			return
+		}
+		c.JSON(http.StatusOK, b)
+	})
+
+	// The new result replaces the baseline with ?advance=true
+	router.POST("/query/baselines/:id/diff", func(c *gin.Context) {
+		diff, err := s.diffBaseline(c.Request.Context(), "rest", c.Param("id"), c.Query("advance") == "true")
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to diff baseline", err)
+			return
+		}
+		c.JSON(http.StatusOK, diff)
+	})
+
+	router.DELETE("/query/baselines/:id", func(c *gin.Context) {
+		if err := s.baselines.delete(c.Param("id")); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to delete baseline", err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
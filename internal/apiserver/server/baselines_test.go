@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+func TestBaselineDiff(t *testing.T) {
+	conn := &rowsConnector{rows: []map[string]interface{}{
+		{"ID": 1.0, "STATUS": "open"},
+		{"ID": 2.0, "STATUS": "open"},
+		{"ID": 3.0, "STATUS": "open"},
+	}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, baselines: newBaselineStore()}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupBaselineRoutes(router.Group(""))
+	post := func(target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+		return w
+	}
+
+	w := post("/query/baselines", `{"query":"SELECT ID, STATUS FROM ORDERS","key":"ID"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var baseline Baseline
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &baseline))
+	assert.Equal(t, 3, baseline.Rows)
+
+	conn.rows = []map[string]interface{}{
+		{"ID": 1.0, "STATUS": "open"},
+		{"ID": 2.0, "STATUS": "shipped"},
+		{"ID": 4.0, "STATUS": "open"},
+	}
+	w = post("/query/baselines/"+baseline.ID+"/diff?advance=true", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var diff ResultDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	assert.Equal(t, []map[string]interface{}{{"ID": 4.0, "STATUS": "open"}}, diff.Added)
+	assert.Equal(t, []map[string]interface{}{{"ID": 2.0, "STATUS": "shipped"}}, diff.Updated)
+	assert.Equal(t, []map[string]interface{}{{"ID": 3.0, "STATUS": "open"}}, diff.Removed)
+	assert.Equal(t, 1, diff.Unchanged)
+
+	// The advanced baseline holds the last result
+	got, err := s.diffBaseline(context.Background(), "rest", baseline.ID, false)
+	require.NoError(t, err)
+	assert.Empty(t, got.Added)
+	assert.Empty(t, got.Updated)
+	assert.Empty(t, got.Removed)
+	assert.Equal(t, 3, got.Unchanged)
+
+	assert.Equal(t, http.StatusBadRequest, post("/query/baselines", `{"query":"DELETE FROM ORDERS"}`).Code)
+	assert.Equal(t, http.StatusNotFound, post("/query/baselines/missing/diff", "").Code)
+
+	// The MCP tools take and diff baselines too
+	tools := s.baselineTools()
+	result, err := tools[0].Handler(context.Background(), &mcpSession{}, map[string]interface{}{"sql": "SELECT * FROM ORDERS"})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &baseline))
+	conn.rows = conn.rows[:2]
+	result, err = tools[1].Handler(context.Background(), &mcpSession{}, map[string]interface{}{"baseline_id": baseline.ID})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &diff))
+	assert.Len(t, diff.Removed, 1)
+	assert.Empty(t, diff.Added)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const actionBudgetOverride = "budget_override"
+
+// setupBudgetRoutes configures the admin routes for the connection's credit budget
+func (s *MCPServerWithDB) setupBudgetRoutes(router *gin.RouterGroup) {
+	guard, ok := s.DBConn.(connector.BudgetGuard)
+	if !ok {
+		return
+	}
+
+	router.GET("/admin/budget", func(c *gin.Context) {
+		usage, err := guard.CreditUsage(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get credit usage: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, usage)
+	})
+
+	router.POST("/admin/budget/override", func(c *gin.Context) {
+		var request struct {
+			Duration string `json:"duration"`
+			Reason   string `json:"reason"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		// Without an explicit duration the override lasts until the end of the UTC day
+		now := time.Now().UTC()
+		until := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+		if request.Duration != "" {
+			d, err := time.ParseDuration(request.Duration)
+			if err != nil || d <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid duration: %s", request.Duration)})
+				return
+			}
+			until = now.Add(d)
+		}
+
+		guard.OverrideBudget(until)
+		_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+			Action:    actionBudgetOverride,
+			Principal: principalFromContext(c),
+			Resource:  s.Config.Name,
+			Details: map[string]interface{}{
+				"until":  until,
+				"reason": request.Reason,
+			},
+		})
+
+		c.JSON(http.StatusOK, gin.H{"override_until": until})
+	})
+
+	router.DELETE("/admin/budget/override", func(c *gin.Context) {
+		guard.ClearBudgetOverride()
+		c.Status(http.StatusNoContent)
+	})
+}
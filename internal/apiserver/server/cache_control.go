@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// CacheControlConfig sets the Cache-Control max-age of the responses of
+// generated read endpoints, so that slowly-changing reference tables can be
+// cached by clients and CDNs
+type CacheControlConfig struct {
+	// Tables map table names to the max-age of their read endpoints, as
+	// durations like "1h"
+	Tables map[string]string `json:"tables,omitempty"`
+
+	// Endpoints map endpoints, keyed by "METHOD /path" as the endpoints are
+	// generated, to a max-age; they take precedence over Tables
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+
+	// Public lets shared caches store the responses of tables without row
+	// filters; other responses are private to the caller
+	Public bool `json:"public,omitempty"`
+}
+
+// cacheControl are the max-ages of a CacheControlConfig
+type cacheControl struct {
+	tables    map[string]time.Duration
+	endpoints map[string]time.Duration
+	public    bool
+}
+
+// newCacheControl parses the max-ages of a configuration; nil returns nil
+func newCacheControl(cfg *CacheControlConfig) (*cacheControl, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	cc := &cacheControl{
+		tables:    make(map[string]time.Duration, len(cfg.Tables)),
+		endpoints: make(map[string]time.Duration, len(cfg.Endpoints)),
+		public:    cfg.Public,
+	}
+	for table, v := range cfg.Tables {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("table %s: invalid max-age %q", table, v)
+		}
+		cc.tables[strings.ToUpper(table)] = d
+	}
+	for endpoint, v := range cfg.Endpoints {
+		method, path, ok := strings.Cut(endpoint, " ")
+		if !ok || method != http.MethodGet || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("endpoint %q must be GET /path", endpoint)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("endpoint %s: invalid max-age %q", endpoint, v)
+		}
+		cc.endpoints[endpoint] = d
+	}
+	return cc, nil
+}
+
+// maxAge returns the max-age of an endpoint's responses
+func (cc *cacheControl) maxAge(endpoint connector.APIEndpoint) (time.Duration, bool) {
+	if d, ok := cc.endpoints[routeKey(endpoint)]; ok {
+		return d, true
+	}
+	d, ok := cc.tables[strings.ToUpper(endpoint.Table)]
+	return d, ok
+}
+
+// setCacheControl sets the Cache-Control header of a successful response
+// of a generated read endpoint configured with a max-age
+func (s *MCPServerWithDB) setCacheControl(c *gin.Context, endpoint connector.APIEndpoint) {
+	if s.cacheControl == nil || endpoint.Method != http.MethodGet {
+		return
+	}
+	maxAge, ok := s.cacheControl.maxAge(endpoint)
+	if !ok {
+		return
+	}
+
+	// Rows filtered by the caller's claims must not be shared
+	scope := "private"
+	if s.cacheControl.public && (s.rowSecurity == nil || len(s.rowSecurity.filters[strings.ToUpper(endpoint.Table)]) == 0) {
+		scope = "public"
+	}
+	c.Header("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, int(maxAge.Seconds())))
+}
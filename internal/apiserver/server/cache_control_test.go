@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestCacheControlHeaders(t *testing.T) {
+	cc, err := newCacheControl(&CacheControlConfig{
+		Tables:    map[string]string{"countries": "1h", "ORDERS": "1m"},
+		Endpoints: map[string]string{"GET /COUNTRIES/{CODE}": "24h"},
+		Public:    true,
+	})
+	require.NoError(t, err)
+	rs, err := newRowSecurity(&RowSecurityConfig{Filters: []RowFilterConfig{{Table: "ORDERS", Filter: "REGION = {claims.region}"}}})
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: &paramsConnector{}, cacheControl: cc, rowSecurity: rs}
+
+	endpoints := []connector.APIEndpoint{
+		{Table: "COUNTRIES", Method: http.MethodGet, Path: "/COUNTRIES", Query: "SELECT * FROM COUNTRIES"},
+		{Table: "COUNTRIES", Method: http.MethodGet, Path: "/COUNTRIES/{CODE}", Query: "SELECT * FROM COUNTRIES WHERE CODE = :CODE"},
+		{Table: "CUSTOMERS", Method: http.MethodGet, Path: "/CUSTOMERS", Query: "SELECT * FROM CUSTOMERS"},
+	}
+	routes := newRouteManager("/api", s.generatedEndpointHandler)
+	_, err = routes.Apply(endpoints)
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.NoRoute(routes.ServeHTTP)
+	get := func(path string) string {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w.Header().Get("Cache-Control")
+	}
+	assert.Equal(t, "public, max-age=3600", get("/api/COUNTRIES"))
+	assert.Equal(t, "public, max-age=86400", get("/api/COUNTRIES/NO"))
+	assert.Empty(t, get("/api/CUSTOMERS"))
+
+	// Tables with row filters are only cached by the caller
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	s.setCacheControl(c, connector.APIEndpoint{Table: "ORDERS", Method: http.MethodGet, Path: "/ORDERS"})
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+
+	for _, cfg := range []*CacheControlConfig{{Tables: map[string]string{"A": "soon"}}, {Endpoints: map[string]string{"POST /A": "1h"}}} {
+		_, err := newCacheControl(cfg)
+		assert.Error(t, err)
+	}
+}
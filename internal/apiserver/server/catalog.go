@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/catalog"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// CatalogStatus describes the last sync with the data catalog
+type CatalogStatus struct {
+	Type string `json:"type"`
+
+	// Tables is the number of tables documented in the catalog
+	Tables   int        `json:"tables"`
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// catalogSync holds the curated documentation of the server's tables read
+// from a data catalog, and counts the gateway's queries of each table
+// until they are pushed back to it
+type catalogSync struct {
+	client   catalog.Client
+	cfg      *catalog.Config
+	interval time.Duration
+
+	mu       sync.RWMutex
+	docs     map[string]*catalog.TableDoc
+	syncedAt *time.Time
+	err      string
+
+	usageMu    sync.Mutex
+	usage      map[string]int64
+	usageSince time.Time
+}
+
+// newCatalogSync creates the sync of a configuration, which may be nil
+func newCatalogSync(cfg *catalog.Config) (*catalogSync, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	client, err := catalog.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	interval, _ := cfg.SyncInterval()
+	return &catalogSync{
+		client:     client,
+		cfg:        cfg,
+		interval:   interval,
+		docs:       make(map[string]*catalog.TableDoc),
+		usage:      make(map[string]int64),
+		usageSince: time.Now().UTC(),
+	}, nil
+}
+
+// doc returns the documentation of a table
+func (c *catalogSync) doc(table string) (*catalog.TableDoc, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	doc, ok := c.docs[table]
+	return doc, ok
+}
+
+// recordUsage counts a query of a table when usage is pushed
+func (c *catalogSync) recordUsage(table string) {
+	if c == nil || !c.cfg.PushUsage || table == "" {
+		return
+	}
+	c.usageMu.Lock()
+	c.usage[table]++
+	c.usageMu.Unlock()
+}
+
+// takeUsage returns the queries counted since the last call and resets them
+func (c *catalogSync) takeUsage() (map[string]int64, time.Time, time.Time) {
+	c.usageMu.Lock()
+	defer c.usageMu.Unlock()
+	usage, since, now := c.usage, c.usageSince, time.Now().UTC()
+	c.usage, c.usageSince = make(map[string]int64), now
+	return usage, since, now
+}
+
+// status describes the last sync
+func (c *catalogSync) status() CatalogStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return CatalogStatus{Type: c.cfg.Type, Tables: len(c.docs), SyncedAt: c.syncedAt, Error: c.err}
+}
+
+// catalogName qualifies a table with the configured database and schema,
+// defaulting to the ones of the connection
+func (s *MCPServerWithDB) catalogName(table string) catalog.TableName {
+	name := catalog.TableName{Database: s.catalog.cfg.Database, Schema: s.catalog.cfg.Schema, Table: table}
+	if d, ok := connector.DialectOf(s.DBConn).(connector.SnowflakeDialect); ok {
+		if name.Database == "" {
+			name.Database = d.Database
+		}
+		if name.Schema == "" {
+			name.Schema = d.Schema
+		}
+	}
+	return name
+}
+
+// syncCatalog reads the documentation of every table from the catalog and
+// pushes the usage counted since the last sync. Tables that fail to read
+// keep their previous documentation.
+func (s *MCPServerWithDB) syncCatalog(ctx context.Context) error {
+	tables, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	docs := make(map[string]*catalog.TableDoc, len(tables))
+	var syncErr error
+	for _, t := range tables {
+		doc, err := s.catalog.client.Table(ctx, s.catalogName(t.Name))
+		switch {
+		case errors.Is(err, catalog.ErrNotFound):
+			continue
+		case err != nil:
+			syncErr = fmt.Errorf("table %s: %w", t.Name, err)
+			doc, _ = s.catalog.doc(t.Name)
+		}
+		if doc != nil {
+			docs[t.Name] = doc
+		}
+	}
+
+	now := time.Now().UTC()
+	s.catalog.mu.Lock()
+	s.catalog.docs, s.catalog.syncedAt, s.catalog.err = docs, &now, ""
+	if syncErr != nil {
+		s.catalog.err = syncErr.Error()
+	}
+	s.catalog.mu.Unlock()
+
+	// Tools describe tables with the documentation of the last sync
+	s.invalidateTableTools()
+
+	if s.catalog.cfg.PushUsage {
+		usage, start, end := s.catalog.takeUsage()
+		for table, queries := range usage {
+			err := s.catalog.client.PushUsage(ctx, s.catalogName(table), catalog.Usage{Start: start, End: end, Queries: queries})
+			if err != nil {
+				log.Printf("Warning: Failed to push usage of table %s to the catalog: %v", table, err)
+			}
+		}
+	}
+	return syncErr
+}
+
+// runCatalogSync syncs with the catalog at every interval until the
+// server stops
+func (s *MCPServerWithDB) runCatalogSync() {
+	run := func() {
+		if err := s.syncCatalog(s.ctx); err != nil {
+			log.Printf("Warning: Failed to sync with the %s catalog: %v", s.catalog.cfg.Type, err)
+		}
+	}
+	run()
+
+	ticker := time.NewTicker(s.catalog.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// withCatalog returns a table's metadata with its documentation from the
+// catalog. Curated descriptions replace the database's and generated ones.
+func (s *MCPServerWithDB) withCatalog(metadata *connector.TableMetadata) *connector.TableMetadata {
+	doc, ok := s.catalog.doc(metadata.Name)
+	if !ok {
+		return metadata
+	}
+
+	documented := *metadata
+	if doc.Description != "" {
+		documented.Description, documented.VerboseDescription = doc.Description, doc.Description
+	}
+	documented.Owners, documented.Tags = doc.Owners, doc.Tags
+	documented.Columns = append([]connector.Column(nil), metadata.Columns...)
+	for i := range documented.Columns {
+		col := &documented.Columns[i]
+		colDoc, ok := doc.Column(col.Name)
+		if !ok {
+			continue
+		}
+		if colDoc.Description != "" {
+			col.Description, col.VerboseDescription = colDoc.Description, colDoc.Description
+		}
+		col.Tags = colDoc.Tags
+	}
+	return &documented
+}
+
+// withTableUsage counts the calls of a table's tool as queries of it
+func (s *MCPServerWithDB) withTableUsage(table string, tool mcpTool) mcpTool {
+	if s.catalog == nil {
+		return tool
+	}
+	handler := tool.Handler
+	tool.Handler = func(ctx context.Context, sess *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+		s.catalog.recordUsage(table)
+		return handler(ctx, sess, args)
+	}
+	return tool
+}
+
+// setupCatalogRoutes configures the catalog sync routes when a catalog is
+// configured
+func (s *MCPServerWithDB) setupCatalogRoutes(router *gin.RouterGroup) {
+	if s.catalog == nil {
+		return
+	}
+
+	router.GET("/admin/catalog", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.catalog.status())
+	})
+
+	router.POST("/admin/catalog/sync", func(c *gin.Context) {
+		if err := s.syncCatalog(c.Request.Context()); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to sync with the catalog: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, s.catalog.status())
+	})
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/catalog"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestCatalogSync(t *testing.T) {
+	pushed := make(chan string, 1)
+	om := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/tables/name/warehouse.SALES.PUBLIC.CUSTOMERS":
+			_, _ = io.WriteString(w, `{
+				"description": "Customers of the web shop",
+				"owners": [{"name": "jane"}],
+				"tags": [{"tagFQN": "Tier.Tier1"}],
+				"columns": [{"name": "name", "description": "Legal name", "tags": [{"tagFQN": "PII.Sensitive"}]}]
+			}`)
+		case strings.HasPrefix(r.URL.Path, "/api/v1/usage/table/name/"):
+			body, _ := io.ReadAll(r.Body)
+			pushed <- strings.TrimPrefix(r.URL.Path, "/api/v1/usage/table/name/") + " " + string(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer om.Close()
+
+	sync, err := newCatalogSync(&catalog.Config{Type: catalog.TypeOpenMetadata, URL: om.URL, Service: "warehouse", Database: "SALES", Schema: "PUBLIC", PushUsage: true})
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: &importConnector{}, catalog: sync}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupCatalogRoutes(router.Group("/"))
+
+	s.catalog.recordUsage("CUSTOMERS")
+	s.catalog.recordUsage("CUSTOMERS")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/catalog/sync", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var status CatalogStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	assert.Equal(t, 1, status.Tables)
+	assert.NotNil(t, status.SyncedAt)
+	assert.Contains(t, <-pushed, `warehouse.SALES.PUBLIC.CUSTOMERS {"count":2,`)
+
+	// Curated documentation replaces the database's
+	metadata, err := s.DBConn.GetTableMetadata(context.Background(), "CUSTOMERS")
+	require.NoError(t, err)
+	documented := s.withCatalog(metadata)
+	assert.Equal(t, "Customers of the web shop", documented.VerboseDescription)
+	assert.Equal(t, []string{"jane"}, documented.Owners)
+	assert.Equal(t, []string{"Tier.Tier1"}, documented.Tags)
+	assert.Equal(t, connector.Column{
+		Name: "NAME", Type: "VARCHAR", MaxLength: 10,
+		Description: "Legal name", VerboseDescription: "Legal name", Tags: []string{"PII.Sensitive"},
+	}, documented.Columns[1])
+	assert.Empty(t, metadata.Columns[1].Tags)
+
+	// Tables missing from the catalog are left as they are
+	orders := &connector.TableMetadata{Name: "ORDERS"}
+	assert.Same(t, orders, s.withCatalog(orders))
+}
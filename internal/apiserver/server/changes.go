@@ -0,0 +1,389 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	defaultChangeInterval = 30 * time.Second
+
+	// changeHistorySize is the number of recent changes kept per table for
+	// the MCP change resources
+	changeHistorySize = 100
+
+	// changeSubscriberBuffer is the number of changes queued for a
+	// subscriber; subscribers falling further behind are disconnected
+	changeSubscriberBuffer = 256
+
+	// changesURIScheme prefixes the URIs of change resources
+	changesURIScheme = "changes://"
+)
+
+// ChangeStreamConfig streams the row changes of tables, captured by
+// database streams, to SSE and WebSocket subscribers and MCP clients
+type ChangeStreamConfig struct {
+	// Interval between polls of the streams (default: 30s)
+	Interval string              `json:"interval,omitempty"`
+	Tables   []ChangeStreamTable `json:"tables"`
+}
+
+// ChangeStreamTable is a table whose changes are streamed
+type ChangeStreamTable struct {
+	Table string `json:"table"`
+
+	// Stream is the stream object capturing the table's changes
+	// (default: <TABLE>_CHANGES)
+	Stream string `json:"stream,omitempty"`
+
+	// Create creates the stream when it does not exist. Streams are
+	// consumed by the gateway and should not be shared with other readers.
+	Create bool `json:"create,omitempty"`
+}
+
+// TableChange is a row change of a table as pushed to subscribers
+type TableChange struct {
+	Table string    `json:"table"`
+	Time  time.Time `json:"time"`
+	connector.RowChange
+}
+
+// changeFeed fans the polled changes out to subscribers and keeps the
+// recent changes of every table
+type changeFeed struct {
+	tables   []ChangeStreamTable
+	interval time.Duration
+
+	mu          sync.Mutex
+	recent      map[string][]TableChange // keyed by upper-cased table name
+	subscribers map[chan TableChange]string
+}
+
+// newChangeFeed validates a change stream configuration; nil disables it
+func newChangeFeed(cfg *ChangeStreamConfig) (*changeFeed, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	f := &changeFeed{
+		interval:    defaultChangeInterval,
+		recent:      make(map[string][]TableChange),
+		subscribers: make(map[chan TableChange]string),
+	}
+	if cfg.Interval != "" {
+		d, err := time.ParseDuration(cfg.Interval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", cfg.Interval)
+		}
+		f.interval = d
+	}
+	if len(cfg.Tables) == 0 {
+		return nil, fmt.Errorf("at least one table is required")
+	}
+	seen := make(map[string]bool, len(cfg.Tables))
+	for i, t := range cfg.Tables {
+		if t.Table == "" {
+			return nil, fmt.Errorf("table %d: table is required", i)
+		}
+		if seen[strings.ToUpper(t.Table)] {
+			return nil, fmt.Errorf("table %s is streamed twice", t.Table)
+		}
+		seen[strings.ToUpper(t.Table)] = true
+		if t.Stream == "" {
+			t.Stream = t.Table + "_CHANGES"
+		}
+		f.tables = append(f.tables, t)
+	}
+	return f, nil
+}
+
+// lookup returns the streamed table of a name, matched case-insensitively
+func (f *changeFeed) lookup(table string) (ChangeStreamTable, bool) {
+	for _, t := range f.tables {
+		if strings.EqualFold(t.Table, table) {
+			return t, true
+		}
+	}
+	return ChangeStreamTable{}, false
+}
+
+// subscribe returns a channel receiving the changes of a table. It is
+// closed when the subscriber falls behind.
+func (f *changeFeed) subscribe(table string) chan TableChange {
+	ch := make(chan TableChange, changeSubscriberBuffer)
+	f.mu.Lock()
+	f.subscribers[ch] = strings.ToUpper(table)
+	f.mu.Unlock()
+	return ch
+}
+
+// unsubscribe stops delivering changes to a subscriber
+func (f *changeFeed) unsubscribe(ch chan TableChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.subscribers[ch]; ok {
+		delete(f.subscribers, ch)
+		close(ch)
+	}
+}
+
+// publish records changes and delivers them to the table's subscribers
+func (f *changeFeed) publish(changes []TableChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, change := range changes {
+		table := strings.ToUpper(change.Table)
+		recent := append(f.recent[table], change)
+		if len(recent) > changeHistorySize {
+			recent = recent[len(recent)-changeHistorySize:]
+		}
+		f.recent[table] = recent
+
+		for ch, subscribed := range f.subscribers {
+			if subscribed != table {
+				continue
+			}
+			select {
+			case ch <- change:
+			default:
+				delete(f.subscribers, ch)
+				close(ch)
+			}
+		}
+	}
+}
+
+// history returns the recent changes of a table, oldest first
+func (f *changeFeed) history(table string) []TableChange {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]TableChange{}, f.recent[strings.ToUpper(table)]...)
+}
+
+// runChangeStreams creates the configured streams and polls them until the
+// server stops
+func (s *MCPServerWithDB) runChangeStreams() {
+	streamer, ok := s.DBConn.(connector.ChangeStreamer)
+	if !ok {
+		log.Printf("Warning: Change streams are not supported by this database")
+		return
+	}
+	for _, t := range s.changes.tables {
+		if !t.Create {
+			continue
+		}
+		if err := streamer.CreateChangeStream(s.ctx, t.Table, t.Stream); err != nil {
+			log.Printf("Warning: Failed to create change stream of %s: %v", t.Table, err)
+		}
+	}
+
+	ticker := time.NewTicker(s.changes.interval)
+	defer ticker.Stop()
+	for {
+		s.pollChanges(s.ctx, streamer)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollChanges consumes the streams and publishes their changes
+func (s *MCPServerWithDB) pollChanges(ctx context.Context, streamer connector.ChangeStreamer) {
+	for _, t := range s.changes.tables {
+		rowChanges, err := streamer.ConsumeChanges(ctx, t.Stream)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Warning: Failed to poll change stream of %s: %v", t.Table, err)
+			}
+			continue
+		}
+		if len(rowChanges) == 0 {
+			continue
+		}
+		now := time.Now().UTC()
+		changes := make([]TableChange, len(rowChanges))
+		for i, rc := range rowChanges {
+			changes[i] = TableChange{Table: t.Table, Time: now, RowChange: rc}
+		}
+		s.changes.publish(changes)
+		s.notifyResourceUpdated(changesURIScheme + t.Table)
+	}
+}
+
+// checkChangeAccess rejects subscriptions to the changes of tables whose
+// rows the caller may not all see, since row filters cannot be applied to
+// captured changes
+func (s *MCPServerWithDB) checkChangeAccess(ctx context.Context, table string) error {
+	if s.rowSecurity == nil {
+		return nil
+	}
+	condition, _, err := s.rowSecurity.filter(ctx, table)
+	if err != nil {
+		return err
+	}
+	if condition != "" {
+		return fmt.Errorf("%w: changes of %s are not available to callers restricted by row filters", ErrRowSecurity, table)
+	}
+	return nil
+}
+
+// setupChangeRoutes configures the endpoint streaming the changes of a
+// table, over WebSocket when the request asks for an upgrade and as
+// server-sent events otherwise
+func (s *MCPServerWithDB) setupChangeRoutes(router *gin.RouterGroup) {
+	router.GET("/changes/:table", func(c *gin.Context) {
+		if s.changes == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Change streams are not configured"})
+			return
+		}
+		t, ok := s.changes.lookup(c.Param("table"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Changes of %s are not streamed", c.Param("table"))})
+			return
+		}
+		if err := s.checkChangeAccess(c.Request.Context(), t.Table); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to stream changes", err)
+			return
+		}
+
+		if websocket.IsWebSocketUpgrade(c.Request) {
+			s.streamChangesWebSocket(c, t.Table)
+		} else {
+			s.streamChangesSSE(c, t.Table)
+		}
+	})
+}
+
+// streamChangesSSE sends the changes of a table as server-sent events
+func (s *MCPServerWithDB) streamChangesSSE(c *gin.Context, table string) {
+	changes := s.changes.subscribe(table)
+	defer s.changes.unsubscribe(changes)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache, no-transform")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(mcpStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(change)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", change.Action, data)
+		}
+		c.Writer.Flush()
+	}
+}
+
+// streamChangesWebSocket sends the changes of a table as JSON messages
+func (s *MCPServerWithDB) streamChangesWebSocket(c *gin.Context, table string) {
+	conn, err := subscriptionUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("Warning: Failed to upgrade change stream of %s: %v", table, err)
+		return
+	}
+	defer conn.Close()
+
+	changes := s.changes.subscribe(table)
+	defer s.changes.unsubscribe(changes)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-s.ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server stopping"), time.Now().Add(time.Second))
+			return
+		case change, ok := <-changes:
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "subscriber fell behind"), time.Now().Add(time.Second))
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout))
+			if err := conn.WriteJSON(change); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// changeResources lists the change resource of every streamed table
+func (s *MCPServerWithDB) changeResources() []mcp.ResourceSchema {
+	if s.changes == nil {
+		return nil
+	}
+	resources := make([]mcp.ResourceSchema, 0, len(s.changes.tables))
+	for _, t := range s.changes.tables {
+		resources = append(resources, mcp.ResourceSchema{
+			URI:         changesURIScheme + t.Table,
+			Name:        fmt.Sprintf("%s changes", t.Table),
+			Description: fmt.Sprintf("Last %d row changes of %s; subscribe to be notified of new changes", changeHistorySize, t.Table),
+			MimeType:    "application/json",
+		})
+	}
+	return resources
+}
+
+// readChangeResource reads a resource listed by changeResources. ok is
+// false when the URI does not belong to a change resource.
+func (s *MCPServerWithDB) readChangeResource(ctx context.Context, uri string) (result *mcp.ReadResourceResult, ok bool, err error) {
+	name, found := strings.CutPrefix(uri, changesURIScheme)
+	if !found {
+		return nil, false, nil
+	}
+	if s.changes == nil {
+		return nil, true, fmt.Errorf("resource not found: %s", uri)
+	}
+	t, found := s.changes.lookup(name)
+	if !found {
+		return nil, true, fmt.Errorf("resource not found: %s", uri)
+	}
+	if err := s.checkChangeAccess(ctx, t.Table); err != nil {
+		return nil, true, err
+	}
+	data, err := json.Marshal(s.changes.history(t.Table))
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{{URI: uri, MimeType: "application/json", Text: string(data)}},
+	}, true, nil
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamConnector returns its pending changes once per stream
+type streamConnector struct {
+	rowsConnector
+	pending map[string][]connector.RowChange
+}
+
+func (c *streamConnector) CreateChangeStream(context.Context, string, string) error {
+	return nil
+}
+
+func (c *streamConnector) ConsumeChanges(_ context.Context, stream string) ([]connector.RowChange, error) {
+	changes := c.pending[stream]
+	delete(c.pending, stream)
+	return changes, nil
+}
+
+func TestChangeStreams(t *testing.T) {
+	_, err := newChangeFeed(&ChangeStreamConfig{})
+	assert.Error(t, err)
+	_, err = newChangeFeed(&ChangeStreamConfig{Tables: []ChangeStreamTable{{Table: "ORDERS"}, {Table: "orders"}}})
+	assert.Error(t, err)
+
+	feed, err := newChangeFeed(&ChangeStreamConfig{Tables: []ChangeStreamTable{{Table: "ORDERS"}}})
+	require.NoError(t, err)
+	conn := &streamConnector{pending: map[string][]connector.RowChange{
+		"ORDERS_CHANGES": {
+			{Action: connector.ChangeInsert, Row: map[string]interface{}{"ID": 1.0}},
+			{Action: connector.ChangeDelete, Row: map[string]interface{}{"ID": 2.0}},
+		},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "sales"},
+		DBConn:      conn,
+		changes:     feed,
+		mcpSessions: newMCPSessionStore(),
+		ctx:         ctx,
+	}
+
+	sess := s.mcpSessions.create(mcp.ImplementationSchema{Name: "client"}, "", "")
+	sess.subscribe("changes://ORDERS")
+	notifications, ok := sess.openStream()
+	require.True(t, ok)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupChangeRoutes(router.Group(""))
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	base := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	_, resp, err := websocket.DefaultDialer.Dial(base+"/changes/CUSTOMERS", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	ws, _, err := websocket.DefaultDialer.Dial(base+"/changes/orders", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// The subscription is registered once the upgrade has completed
+	require.Eventually(t, func() bool {
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		return len(feed.subscribers) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	s.pollChanges(ctx, conn)
+
+	var change TableChange
+	require.NoError(t, ws.ReadJSON(&change))
+	assert.Equal(t, "ORDERS", change.Table)
+	assert.Equal(t, connector.ChangeInsert, change.Action)
+	assert.Equal(t, map[string]interface{}{"ID": 1.0}, change.Row)
+	require.NoError(t, ws.ReadJSON(&change))
+	assert.Equal(t, connector.ChangeDelete, change.Action)
+
+	select {
+	case n := <-notifications:
+		assert.Equal(t, mcp.NotificationResourceUpdated, n.Method)
+		assert.JSONEq(t, `{"uri": "changes://ORDERS"}`, string(n.Params))
+	default:
+		t.Fatal("no resource update notification")
+	}
+
+	result, ok, err := s.readChangeResource(ctx, "changes://ORDERS")
+	require.True(t, ok)
+	require.NoError(t, err)
+	var history []TableChange
+	require.NoError(t, json.Unmarshal([]byte(result.Contents[0].Text), &history))
+	assert.Len(t, history, 2)
+
+	_, ok, _ = s.readChangeResource(ctx, "scheduled://report")
+	assert.False(t, ok)
+	assert.Len(t, s.changeResources(), 1)
+}
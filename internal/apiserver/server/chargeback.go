@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// APIKeyCostReport charges the warehouse spend of a tenant's servers back
+// to the API keys whose requests issued the queries
+type APIKeyCostReport struct {
+	Tenant            string       `json:"tenant"`
+	From              time.Time    `json:"from"`
+	To                time.Time    `json:"to"`
+	TotalCredits      float64      `json:"total_credits"`
+	TotalBytesScanned int64        `json:"total_bytes_scanned"`
+	Keys              []APIKeyCost `json:"keys"`
+
+	// Servers lists the running servers whose spend is included
+	Servers []string `json:"servers"`
+}
+
+// APIKeyCost is the spend of the queries issued with one API key. Name and
+// Prefix are empty for keys deleted since.
+type APIKeyCost struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name,omitempty"`
+	Prefix       string  `json:"prefix,omitempty"`
+	Queries      int     `json:"queries"`
+	ExecutionMs  float64 `json:"execution_ms"`
+	Credits      float64 `json:"credits"`
+	BytesScanned int64   `json:"bytes_scanned"`
+	Share        float64 `json:"share"`
+}
+
+// APIKeyCosts builds the cost report of a tenant's API keys for a period,
+// from the spend attribution of its running servers. Servers whose
+// database cannot attribute spend are skipped.
+func (r *Registry) APIKeyCosts(ctx context.Context, tenant string, from, to time.Time) (*APIKeyCostReport, error) {
+	if _, err := r.tenants.Get(ctx, tenant); err != nil {
+		return nil, err
+	}
+	keys, err := r.tenants.ListKeys(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := r.store.List(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &APIKeyCostReport{Tenant: tenant, From: from, To: to, Keys: []APIKeyCost{}, Servers: []string{}}
+	costs := make(map[string]*APIKeyCost)
+	for _, key := range keys {
+		costs[key.ID] = &APIKeyCost{ID: key.ID, Name: key.Name, Prefix: key.Prefix}
+	}
+	for _, def := range defs {
+		srv, ok := r.server(tenant, def.Name)
+		if !ok {
+			continue
+		}
+		if _, ok := srv.DBConn.(connector.SpendAttributor); !ok {
+			continue
+		}
+		attribution, err := srv.SpendAttribution(ctx, from, to)
+		if err != nil {
+			// One unreachable warehouse does not void the others' report
+			log.Printf("Warning: Failed to attribute spend of server %s: %v", serverKey(tenant, def.Name), err)
+			continue
+		}
+		report.Servers = append(report.Servers, def.Name)
+		for _, entry := range attribution.APIKeys {
+			if entry.Name == untaggedSpend {
+				continue
+			}
+			cost, ok := costs[entry.Name]
+			if !ok {
+				cost = &APIKeyCost{ID: entry.Name}
+				costs[entry.Name] = cost
+			}
+			cost.Queries += entry.Queries
+			cost.ExecutionMs += entry.ExecutionMs
+			cost.Credits += entry.Credits
+			cost.BytesScanned += entry.BytesScanned
+			report.TotalCredits += entry.Credits
+			report.TotalBytesScanned += entry.BytesScanned
+		}
+	}
+
+	for _, cost := range costs {
+		if report.TotalCredits > 0 {
+			cost.Share = cost.Credits / report.TotalCredits
+		}
+		report.Keys = append(report.Keys, *cost)
+	}
+	sort.Slice(report.Keys, func(i, j int) bool {
+		if report.Keys[i].Credits != report.Keys[j].Credits {
+			return report.Keys[i].Credits > report.Keys[j].Credits
+		}
+		return report.Keys[i].ID < report.Keys[j].ID
+	})
+	return report, nil
+}
+
+// setupChargebackRoutes configures the API key cost report route
+func (r *Registry) setupChargebackRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/tenants/:tenant/costs", func(c *gin.Context) {
+		from, to, err := spendPeriod(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		report, err := r.APIKeyCosts(c.Request.Context(), c.Param("tenant"), from, to)
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to build API key costs: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	})
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+)
+
+// spendConnector reports fixed spend by query tag
+type spendConnector struct {
+	rowsConnector
+	spends []connector.TagSpend
+}
+
+func (c *spendConnector) SpendByQueryTag(context.Context, time.Time, time.Time) ([]connector.TagSpend, error) {
+	return c.spends, nil
+}
+
+func TestAPIKeyCosts(t *testing.T) {
+	store, err := state.OpenAndMigrate(&state.Config{DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	registry := NewRegistry(NewServerStore(store.DB), nil)
+	defer registry.Shutdown()
+	require.NoError(t, registry.tenants.Create(ctx, &Tenant{Name: "acme"}))
+	ci, err := registry.tenants.CreateKey(ctx, "acme", "ci")
+	require.NoError(t, err)
+	analyst, err := registry.tenants.CreateKey(ctx, "acme", "analyst")
+	require.NoError(t, err)
+	_, err = registry.Create(ctx, &ServerDefinition{Tenant: "acme", Name: "sales", Running: true, Config: &MCPServerConfig{Database: &connector.DatabaseConfig{Type: "none"}}}, "test")
+	require.NoError(t, err)
+
+	srv, ok := registry.server("acme", "sales")
+	require.True(t, ok)
+	conn := srv.DBConn
+	defer func() { srv.DBConn = conn }()
+	srv.DBConn = &spendConnector{spends: []connector.TagSpend{
+		{QueryTag: srv.queryTag("rest", "", "acme:ci", ci.ID, ""), Queries: 4, Credits: 3, BytesScanned: 4096},
+		{QueryTag: srv.queryTag("claude-desktop", "1.0", "acme:ci", ci.ID, "s1"), Queries: 1, Credits: 1, BytesScanned: 1024},
+		{QueryTag: srv.queryTag("rest", "", "acme:old", "revoked-key", ""), Queries: 1, Credits: 4},
+		{QueryTag: srv.queryTag("scheduled", "", "", "", "nightly"), Queries: 2, Credits: 10},
+	}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registry.Admin = &AdminConfig{JWTSecret: "secret"}
+	registry.SetupRoutes(router.Group("/"))
+	admin := adminToken(t, "admin")
+	get := func(target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Authorization", "Bearer "+admin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/admin/tenants/acme/costs?period=168h")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report APIKeyCostReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+
+	// Spend without an API key is not charged to any
+	assert.Equal(t, 8.0, report.TotalCredits)
+	assert.Equal(t, int64(5120), report.TotalBytesScanned)
+	assert.Equal(t, []string{"sales"}, report.Servers)
+	// Keys are ordered by credits, then by ID; key IDs are UUIDs, which
+	// sort before the revoked key's
+	assert.Equal(t, []APIKeyCost{
+		{ID: ci.ID, Name: "ci", Prefix: ci.Prefix, Queries: 5, Credits: 4, BytesScanned: 5120, Share: 0.5},
+		{ID: "revoked-key", Queries: 1, Credits: 4, Share: 0.5},
+		{ID: analyst.ID, Name: "analyst", Prefix: analyst.Prefix},
+	}, report.Keys)
+
+	assert.Equal(t, http.StatusNotFound, get("/admin/tenants/globex/costs").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/admin/tenants/acme/costs?period=soon").Code)
+
+	// Operators without an admin role are turned away
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/tenants/acme/costs", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
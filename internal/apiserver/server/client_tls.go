@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// Certificate fields a client's principal can be read from
+const (
+	PrincipalFromCommonName = "common_name"
+	PrincipalFromEmail      = "email"
+	PrincipalFromURI        = "uri"
+	PrincipalFromDNS        = "dns"
+)
+
+// ClientTLSConfig serves the server's own listener over TLS and verifies
+// client certificates against a CA. The principal of a verified client is
+// read from its certificate and used for attribution, quotas and row
+// security like the principal of any other authentication.
+type ClientTLSConfig struct {
+	// CertFile and KeyFile are the server's certificate and key, in PEM
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+
+	// ClientCAFile holds the PEM certificates of the CAs client
+	// certificates must chain to
+	ClientCAFile string `json:"client_ca_file"`
+
+	// RequireClientCert rejects MCP requests without a verified client
+	// certificate; other requests may still present one
+	RequireClientCert bool `json:"require_client_cert,omitempty"`
+
+	// PrincipalFrom is the certificate field naming the principal:
+	// common_name (default), email, uri or dns
+	PrincipalFrom string `json:"principal_from,omitempty"`
+
+	// Roles are the role claims of principals, for row security and quotas
+	Roles map[string]string `json:"roles,omitempty"`
+}
+
+// clientTLS is a validated ClientTLSConfig
+type clientTLS struct {
+	config  *tls.Config
+	require bool
+	field   string
+	roles   map[string]string
+}
+
+// certIdentity is the identity of a client read from its certificate
+type certIdentity struct {
+	Principal string
+	Subject   string
+	Role      string
+}
+
+// certIdentityKey carries the certIdentity of a request
+type certIdentityKey struct{}
+
+// newClientTLS loads the certificates of a client TLS configuration; nil
+// serves plain HTTP
+func newClientTLS(cfg *ClientTLSConfig) (*clientTLS, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" || cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("cert_file, key_file and client_ca_file are required")
+	}
+	field := cfg.PrincipalFrom
+	switch field {
+	case "":
+		field = PrincipalFromCommonName
+	case PrincipalFromCommonName, PrincipalFromEmail, PrincipalFromURI, PrincipalFromDNS:
+	default:
+		return nil, fmt.Errorf("invalid principal_from: %s", field)
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.ClientCAFile)
+	}
+
+	return &clientTLS{
+		config: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientCAs:    pool,
+			// Certificates are required on the MCP routes only, so that
+			// the same listener can serve other clients
+			ClientAuth: tls.VerifyClientCertIfGiven,
+			MinVersion: tls.VersionTLS12,
+		},
+		require: cfg.RequireClientCert,
+		field:   field,
+		roles:   cfg.Roles,
+	}, nil
+}
+
+// identity returns the identity of the verified client certificate of a
+// request, nil when the client presented none
+func (t *clientTLS) identity(r *http.Request) *certIdentity {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cert := r.TLS.VerifiedChains[0][0]
+	var principal string
+	switch t.field {
+	case PrincipalFromEmail:
+		if len(cert.EmailAddresses) > 0 {
+			principal = cert.EmailAddresses[0]
+		}
+	case PrincipalFromURI:
+		if len(cert.URIs) > 0 {
+			principal = cert.URIs[0].String()
+		}
+	case PrincipalFromDNS:
+		if len(cert.DNSNames) > 0 {
+			principal = cert.DNSNames[0]
+		}
+	default:
+		principal = cert.Subject.CommonName
+	}
+	if principal == "" {
+		return nil
+	}
+	return &certIdentity{Principal: principal, Subject: cert.Subject.String(), Role: t.roles[principal]}
+}
+
+// certIdentityFromContext returns the identity of the client certificate
+// of a request, nil without one
+func certIdentityFromContext(ctx context.Context) *certIdentity {
+	id, _ := ctx.Value(certIdentityKey{}).(*certIdentity)
+	return id
+}
+
+// clientCertMiddleware makes the principal of a verified client
+// certificate the principal of the request, unless the request was
+// authenticated otherwise
+func (s *MCPServerWithDB) clientCertMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.clientTLS == nil {
+			c.Next()
+			return
+		}
+		if id := s.clientTLS.identity(c.Request); id != nil {
+			ctx := context.WithValue(c.Request.Context(), certIdentityKey{}, id)
+			if principal, _ := ctx.Value(principalKey{}).(string); principal == "" {
+				ctx = context.WithValue(ctx, principalKey{}, id.Principal)
+			}
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
+
+// requireClientCert rejects MCP requests without a verified client
+// certificate when the configuration requires one
+func (s *MCPServerWithDB) requireClientCert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.clientTLS != nil && s.clientTLS.require && certIdentityFromContext(c.Request.Context()) == nil {
+			sendMCPError(c, nil, "Client certificate required", http.StatusUnauthorized, mcp.ErrorCodeInvalidRequest)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert writes a self-signed certificate and its key in PEM
+func writeTestCert(t *testing.T, dir string, subject pkix.Name, emails ...string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               subject,
+		EmailAddresses:        emails,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestClientTLS(t *testing.T) {
+	dir := t.TempDir()
+	cert := writeTestCert(t, dir, pkix.Name{CommonName: "etl-worker", Organization: []string{"Acme"}}, "etl@acme.test")
+	cfg := &ClientTLSConfig{
+		CertFile:          filepath.Join(dir, "cert.pem"),
+		KeyFile:           filepath.Join(dir, "key.pem"),
+		ClientCAFile:      filepath.Join(dir, "cert.pem"),
+		RequireClientCert: true,
+		Roles:             map[string]string{"etl-worker": "loader"},
+	}
+	certs, err := newClientTLS(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, certs.config.ClientAuth)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, clientTLS: certs}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.clientCertMiddleware())
+	router.GET("/mcp", s.requireClientCert(), func(c *gin.Context) {
+		claims, err := (&rowSecurity{}).callerClaims(c.Request, nil)
+		require.NoError(t, err)
+		c.JSON(http.StatusOK, gin.H{"principal": principalFromContext(c), "role": claims["role"]})
+	})
+	call := func(state *tls.ConnectionState) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+		req.TLS = state
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call(&tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"principal":"etl-worker","role":"loader"}`, w.Body.String())
+
+	// Unverified clients are rejected on the MCP routes
+	assert.Equal(t, http.StatusUnauthorized, call(&tls.ConnectionState{}).Code)
+	assert.Equal(t, http.StatusUnauthorized, call(nil).Code)
+
+	cfg.PrincipalFrom = PrincipalFromEmail
+	certs, err = newClientTLS(cfg)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodGet, "/mcp", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	id := certs.identity(req)
+	require.NotNil(t, id)
+	assert.Equal(t, "etl@acme.test", id.Principal)
+	assert.Equal(t, "CN=etl-worker,O=Acme", id.Subject)
+	assert.Empty(t, id.Role)
+
+	_, err = newClientTLS(&ClientTLSConfig{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientCAFile: cfg.KeyFile})
+	assert.Error(t, err)
+	_, err = newClientTLS(&ClientTLSConfig{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientCAFile: cfg.CertFile, PrincipalFrom: "serial"})
+	assert.Error(t, err)
+}
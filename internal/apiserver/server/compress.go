@@ -0,0 +1,209 @@
+package server
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Response encodings, in order of preference
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+const defaultCompressionMinBytes = 4096
+
+// compressibleTypes are the content types of compressed responses
+var compressibleTypes = []string{"application/json", "text/csv"}
+
+// CompressionConfig controls the compression of JSON and CSV responses
+type CompressionConfig struct {
+	// Disabled sends responses uncompressed
+	Disabled bool `json:"disabled,omitempty"`
+
+	// MinBytes is the smallest response compressed (default: 4096)
+	MinBytes int `json:"min_bytes,omitempty"`
+}
+
+// validate checks the compression threshold
+func (c *CompressionConfig) validate() error {
+	if c.MinBytes < 0 {
+		return fmt.Errorf("min_bytes must not be negative")
+	}
+	return nil
+}
+
+// acceptedEncoding returns the preferred encoding of an Accept-Encoding
+// header among brotli and gzip, or an empty string for neither
+func acceptedEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q > 0
+	}
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressionMiddleware compresses JSON and CSV responses of at least the
+// configured size with the encoding the client prefers
+func (s *MCPServerWithDB) compressionMiddleware() gin.HandlerFunc {
+	cfg := s.Config.Compression
+	minBytes := defaultCompressionMinBytes
+	if cfg != nil && cfg.MinBytes > 0 {
+		minBytes = cfg.MinBytes
+	}
+
+	return func(c *gin.Context) {
+		if cfg != nil && cfg.Disabled {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := acceptedEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minBytes: minBytes}
+		c.Writer = w
+		defer w.finish()
+		c.Next()
+	}
+}
+
+// compressWriter buffers the start of a response until it reaches the
+// compression threshold, then compresses the rest of it when its content
+// type is compressible. Flushed responses, like event streams, are sent
+// uncompressed.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minBytes int
+	status   int
+	buf      []byte
+	decided  bool
+	enc      io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if !w.decided {
+		w.status = code
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *compressWriter) Status() int {
+	if !w.decided && w.status != 0 {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *compressWriter) Written() bool {
+	return w.decided || w.status != 0 || len(w.buf) > 0
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minBytes {
+			return len(data), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide writes the header and the buffered data, compressing the
+// response when allowed and its content type is compressible
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.ResponseWriter.Header()
+	if compress && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		if w.encoding == encodingBrotli {
+			w.enc = brotli.NewWriter(w.ResponseWriter)
+		} else {
+			w.enc = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// finish sends a response left below the threshold and completes a
+// compressed one
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+	}
+}
+
+// compressible reports whether a content type is compressed
+func compressible(contentType string) bool {
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressLargeResponses(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", Compression: &CompressionConfig{MinBytes: 256}}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.compressionMiddleware())
+	rows := make([]map[string]interface{}, 100)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"ID": i, "STATUS": "shipped"}
+	}
+	router.GET("/large", func(c *gin.Context) { c.JSON(http.StatusOK, rows) })
+	router.GET("/small", func(c *gin.Context) { c.JSON(http.StatusCreated, rows[:1]) })
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	expected, err := json.Marshal(rows)
+	require.NoError(t, err)
+
+	w := get("/large", "gzip, br;q=0.5")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	body, err := io.ReadAll(brotli.NewReader(w.Body))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(body))
+
+	w = get("/large", "gzip, br;q=0")
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err = io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(body))
+	assert.Less(t, w.Body.Len(), len(expected))
+
+	// Small responses and clients without a supported encoding are not compressed
+	w = get("/small", "gzip")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), `[{"ID":0`))
+
+	w = get("/large", "deflate")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, string(expected), w.Body.String())
+}
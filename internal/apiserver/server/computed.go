@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// defaultComputedType is the type reported for computed columns without one
+const defaultComputedType = "VARCHAR"
+
+// ComputedColumnConfig derives a read-only column of a table from a SQL
+// expression over its columns, e.g. FIRST_NAME || ' ' || LAST_NAME
+type ComputedColumnConfig struct {
+	Table      string `json:"table"`
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+
+	// Type is the database type of the expression's values, used for
+	// metadata and output schemas (default: VARCHAR)
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// validateComputedColumns checks the computed columns of a configuration
+func validateComputedColumns(columns []ComputedColumnConfig) error {
+	seen := make(map[string]bool, len(columns))
+	for i, col := range columns {
+		if col.Table == "" || col.Name == "" || strings.TrimSpace(col.Expression) == "" {
+			return fmt.Errorf("computed column %d: table, name and expression are required", i)
+		}
+		key := strings.ToUpper(col.Table) + "." + col.Name
+		if seen[key] {
+			return fmt.Errorf("computed column %s.%s is defined twice", col.Table, col.Name)
+		}
+		seen[key] = true
+		if err := connector.CheckExpression(col.Expression); err != nil {
+			return fmt.Errorf("computed column %s.%s: %w", col.Table, col.Name, err)
+		}
+	}
+	return nil
+}
+
+// computedColumns returns the computed columns of a table
+func (s *MCPServerWithDB) computedColumns(table string) []ComputedColumnConfig {
+	var columns []ComputedColumnConfig
+	for _, col := range s.Config.ComputedColumns {
+		if strings.EqualFold(col.Table, table) {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// withComputedColumns returns a table's metadata with its computed columns
+// appended. Computed columns named like a stored column are skipped.
+func (s *MCPServerWithDB) withComputedColumns(metadata *connector.TableMetadata) *connector.TableMetadata {
+	computed := s.computedColumns(metadata.Name)
+	if len(computed) == 0 {
+		return metadata
+	}
+
+	stored := make(map[string]bool, len(metadata.Columns))
+	for _, col := range metadata.Columns {
+		stored[col.Name] = true
+	}
+	extended := *metadata
+	extended.Columns = append([]connector.Column(nil), metadata.Columns...)
+	for _, col := range computed {
+		if stored[col.Name] {
+			log.Printf("Warning: Computed column %s.%s is shadowed by a stored column", metadata.Name, col.Name)
+			continue
+		}
+		typ := col.Type
+		if typ == "" {
+			typ = defaultComputedType
+		}
+		extended.Columns = append(extended.Columns, connector.Column{
+			Name:        col.Name,
+			Type:        typ,
+			Description: col.Description,
+			Nullable:    true,
+			Computed:    true,
+		})
+	}
+	return &extended
+}
+
+// selectComputed adds the computed columns of a table to a query selecting
+// all of its columns
+func (s *MCPServerWithDB) selectComputed(table, query string) string {
+	computed := s.computedColumns(table)
+	if len(computed) == 0 {
+		return query
+	}
+	expressions := make([]connector.NamedExpression, len(computed))
+	for i, col := range computed {
+		expressions[i] = connector.NamedExpression{Name: col.Name, SQL: col.Expression}
+	}
+	return connector.SelectExpressions(connector.DialectOf(s.DBConn), query, expressions)
+}
+
+// selectsRows reports whether an endpoint returns rows of its table, so its
+// query carries the computed columns
+func selectsRows(e connector.APIEndpoint) bool {
+	switch e.Operation {
+	case connector.OperationList, connector.OperationGet, connector.OperationSearch:
+		return true
+	}
+	return false
+}
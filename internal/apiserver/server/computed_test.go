@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestComputedColumns(t *testing.T) {
+	for _, invalid := range [][]ComputedColumnConfig{
+		{{Table: "CUSTOMERS", Name: "FULL_NAME"}},
+		{{Table: "CUSTOMERS", Name: "FULL_NAME", Expression: "FIRST_NAME) FROM CUSTOMERS; --"}},
+		{{Table: "CUSTOMERS", Name: "X", Expression: "1"}, {Table: "customers", Name: "X", Expression: "2"}},
+	} {
+		assert.Error(t, validateComputedColumns(invalid))
+	}
+
+	computed := []ComputedColumnConfig{{Table: "customers", Name: "FULL_NAME", Expression: "FIRST_NAME || ' ' || LAST_NAME", Description: "First and last name"}}
+	require.NoError(t, validateComputedColumns(computed))
+	conn := &paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": "1", "FULL_NAME": "Ada Lovelace"}}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", ComputedColumns: computed}, DBConn: conn}
+
+	metadata := &connector.TableMetadata{
+		Name:    "CUSTOMERS",
+		Columns: []connector.Column{{Name: "ID", Type: "NUMBER", PrimaryKey: true}, {Name: "FIRST_NAME", Type: "VARCHAR"}},
+	}
+	extended := s.withComputedColumns(metadata)
+	require.Len(t, extended.Columns, 3)
+	assert.Equal(t, connector.Column{Name: "FULL_NAME", Type: "VARCHAR", Description: "First and last name", Nullable: true, Computed: true}, extended.Columns[2])
+	assert.Len(t, metadata.Columns, 2)
+
+	tools := s.toolsForTable(extended)
+	props := tools[0].Schema.OutputSchema["properties"].(map[string]any)["rows"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, props, "FULL_NAME")
+
+	_, err := tools[1].Handler(context.Background(), nil, map[string]interface{}{"ID": float64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, `SELECT *, (FIRST_NAME || ' ' || LAST_NAME) AS "FULL_NAME" FROM "CUSTOMERS" WHERE "ID" = :ID`, conn.query)
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// connectionHeader selects the labeled connection of a request's queries
+const connectionHeader = "X-Connection"
+
+// Connection selection errors
+var (
+	ErrUnknownConnection = errors.New("unknown connection")
+	ErrConnectionDenied  = errors.New("connection not allowed")
+)
+
+// LabeledConnection is a further database of a server, e.g. the staging
+// copy of the primary, that requests select by its label with the
+// X-Connection header or the connection argument of the query tool
+type LabeledConnection struct {
+	// Label selects the connection, e.g. staging
+	Label string `json:"label"`
+
+	// Database is connected like the primary and of its type
+	Database *connector.DatabaseConfig `json:"database"`
+
+	// Roles are the role claims of the callers allowed to select the
+	// connection; empty allows every caller
+	Roles []string `json:"roles,omitempty"`
+}
+
+// labeledConnection is the connection of a label, connected when it is
+// first selected if it was down when the server started
+type labeledConnection struct {
+	label string
+	conn  connector.DatabaseConnector
+	roles []string
+
+	mu        sync.Mutex
+	connected bool
+}
+
+// ready connects a connection that is not connected yet
+func (l *labeledConnection) ready(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.connected {
+		return nil
+	}
+	if err := l.conn.Connect(ctx); err != nil {
+		return err
+	}
+	l.connected = true
+	return nil
+}
+
+// allows reports whether a caller of a role may select the connection
+func (l *labeledConnection) allows(role string) bool {
+	if len(l.roles) == 0 {
+		return true
+	}
+	for _, r := range l.roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionSet holds the labeled connections of a server
+type connectionSet struct {
+	byLabel map[string]*labeledConnection
+	order   []*labeledConnection
+}
+
+// newConnectionSet validates labeled connections and creates their
+// connectors like the primary's; nil runs every query on the primary
+func newConnectionSet(cfgs []LabeledConnection, primary *connector.DatabaseConfig) (*connectionSet, error) {
+	if len(cfgs) == 0 {
+		return nil, nil
+	}
+	set := &connectionSet{byLabel: make(map[string]*labeledConnection)}
+	for _, cfg := range cfgs {
+		switch {
+		case cfg.Label == "":
+			return nil, fmt.Errorf("connection label is required")
+		case set.byLabel[cfg.Label] != nil:
+			return nil, fmt.Errorf("duplicate connection %s", cfg.Label)
+		case cfg.Database == nil:
+			return nil, fmt.Errorf("connection %s: database is required", cfg.Label)
+		}
+
+		// Queries are checked against the policies with the primary's
+		// syntax, so every connection is of its type
+		config := *cfg.Database
+		if config.Type == "" {
+			config.Type = primary.Type
+		}
+		if config.Type != primary.Type {
+			return nil, fmt.Errorf("connection %s: type %s differs from the primary's %s", cfg.Label, config.Type, primary.Type)
+		}
+		config.Provider, config.Prompts = primary.Provider, primary.Prompts
+		conn, err := connector.NewDatabaseConnector(&config)
+		if err != nil {
+			return nil, fmt.Errorf("connection %s: %w", cfg.Label, err)
+		}
+		l := &labeledConnection{label: cfg.Label, conn: conn, roles: cfg.Roles}
+		set.byLabel[cfg.Label] = l
+		set.order = append(set.order, l)
+	}
+	return set, nil
+}
+
+// restricted reports whether a connection is limited to some roles, so
+// that the callers' claims are read
+func (cs *connectionSet) restricted() bool {
+	for _, l := range cs.order {
+		if len(l.roles) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// connect connects the labeled connections; one that cannot be reached is
+// connected again once it is selected
+func (cs *connectionSet) connect(ctx context.Context) {
+	for _, l := range cs.order {
+		if err := l.ready(ctx); err != nil {
+			log.Printf("Warning: Failed to connect to connection %s: %v", l.label, err)
+		}
+	}
+}
+
+// disconnect closes the labeled connections
+func (cs *connectionSet) disconnect(ctx context.Context) {
+	for _, l := range cs.order {
+		l.mu.Lock()
+		if l.connected {
+			if err := l.conn.Disconnect(ctx); err != nil {
+				log.Printf("Error disconnecting from connection %s: %v", l.label, err)
+			}
+			l.connected = false
+		}
+		l.mu.Unlock()
+	}
+}
+
+type connectionKey struct{}
+
+// withConnection returns a context whose queries run on the connection of
+// a label, if the caller's role may select it; an empty label keeps the
+// primary
+func (s *MCPServerWithDB) withConnection(ctx context.Context, label string) (context.Context, error) {
+	if label == "" {
+		return ctx, nil
+	}
+	var l *labeledConnection
+	if s.connections != nil {
+		l = s.connections.byLabel[label]
+	}
+	if l == nil {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownConnection, label)
+	}
+	if !l.allows(callerRole(ctx)) {
+		return ctx, fmt.Errorf("%w: %s", ErrConnectionDenied, label)
+	}
+	return context.WithValue(ctx, connectionKey{}, l), nil
+}
+
+// restoreConnection returns a context whose queries run on a connection
+// selected earlier, e.g. by the caller of an action an admin approves
+func (s *MCPServerWithDB) restoreConnection(ctx context.Context, label string) (context.Context, error) {
+	if label == "" {
+		return ctx, nil
+	}
+	if s.connections == nil || s.connections.byLabel[label] == nil {
+		return ctx, fmt.Errorf("%w: %s", ErrUnknownConnection, label)
+	}
+	return context.WithValue(ctx, connectionKey{}, s.connections.byLabel[label]), nil
+}
+
+// primaryConnection returns a context whose queries run on the primary,
+// whichever connection its caller selected
+func primaryConnection(ctx context.Context) context.Context {
+	return context.WithValue(ctx, connectionKey{}, (*labeledConnection)(nil))
+}
+
+// connectionLabel returns the label of the connection selected for a
+// context, empty for the primary
+func connectionLabel(ctx context.Context) string {
+	if l, ok := ctx.Value(connectionKey{}).(*labeledConnection); ok && l != nil {
+		return l.label
+	}
+	return ""
+}
+
+// selectedConn returns the connection selected for a context's queries,
+// the primary when none is
+func (s *MCPServerWithDB) selectedConn(ctx context.Context) (connector.DatabaseConnector, error) {
+	l, ok := ctx.Value(connectionKey{}).(*labeledConnection)
+	if !ok || l == nil {
+		return s.DBConn, nil
+	}
+	if err := l.ready(ctx); err != nil {
+		return nil, err
+	}
+	return l.conn, nil
+}
+
+// connectionMiddleware selects the connection of a request's queries with
+// the X-Connection header
+func (s *MCPServerWithDB) connectionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, err := s.withConnection(c.Request.Context(), c.GetHeader(connectionHeader))
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Invalid connection", err)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// setupConnectionRoutes configures the route listing the labels of the
+// connections the caller may select
+func (s *MCPServerWithDB) setupConnectionRoutes(router *gin.RouterGroup) {
+	if s.connections == nil {
+		return
+	}
+	router.GET("/connections", func(c *gin.Context) {
+		role := callerRole(c.Request.Context())
+		labels := make([]string, 0, len(s.connections.order))
+		for _, l := range s.connections.order {
+			if l.allows(role) {
+				labels = append(labels, l.label)
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"connections": labels})
+	})
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+func TestConnectionRouting(t *testing.T) {
+	primary, staging, backfill := &paramsConnector{}, &paramsConnector{}, &paramsConnector{}
+	connections := &connectionSet{byLabel: map[string]*labeledConnection{}}
+	for _, l := range []*labeledConnection{
+		{label: "staging", conn: staging, connected: true},
+		{label: "backfill", conn: backfill, roles: []string{"admin"}, connected: true},
+	} {
+		connections.byLabel[l.label] = l
+		connections.order = append(connections.order, l)
+	}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: primary, connections: connections}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	query := func(label string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query": "SELECT * FROM ORDERS"}`))
+		if label != "" {
+			r.Header.Set(connectionHeader, label)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	// The header selects the connection, and the primary runs the rest
+	w := query("staging")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "SELECT * FROM ORDERS", staging.query)
+	assert.Empty(t, primary.query)
+	require.Equal(t, http.StatusOK, query("").Code)
+	assert.Equal(t, "SELECT * FROM ORDERS", primary.query)
+
+	// Unknown labels and connections of other roles are rejected
+	assert.Equal(t, http.StatusBadRequest, query("prod-eu").Code)
+	assert.Equal(t, http.StatusForbidden, query("backfill").Code)
+	assert.Empty(t, backfill.query)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/connections", nil))
+	assert.JSONEq(t, `{"connections": ["staging"]}`, w.Body.String())
+
+	// Query tools take the label as an argument, checked like the header
+	tool := s.builtinMCPTools()[2]
+	require.Equal(t, "query", tool.Schema.Name)
+	assert.Contains(t, tool.Schema.InputSchema.Properties, "connection")
+	admin := withClaims(context.Background(), map[string]interface{}{"role": "admin"})
+	_, err := tool.Handler(admin, &mcpSession{}, map[string]interface{}{"sql": "SELECT 1", "connection": "backfill"})
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", backfill.query)
+	_, err = tool.Handler(context.Background(), &mcpSession{}, map[string]interface{}{"sql": "SELECT 2", "connection": "backfill"})
+	assert.Equal(t, CodePolicyDenied, errorCode(err))
+	assert.Equal(t, "SELECT 1", backfill.query)
+}
+
+func TestConnectionApproval(t *testing.T) {
+	primary, staging := &paramsConnector{}, &paramsConnector{}
+	l := &labeledConnection{label: "staging", conn: staging, connected: true}
+	approvals, err := newApprovals(&ApprovalConfig{}, "snowflake")
+	require.NoError(t, err)
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "sales"},
+		DBConn:      primary,
+		approvals:   approvals,
+		connections: &connectionSet{byLabel: map[string]*labeledConnection{"staging": l}, order: []*labeledConnection{l}},
+	}
+
+	// A staged write runs on the connection its caller selected
+	ctx, err := s.withConnection(context.Background(), "staging")
+	require.NoError(t, err)
+	result, err := s.builtinMCPTools()[2].Handler(ctx, &mcpSession{}, map[string]interface{}{"sql": "DELETE FROM ORDERS"})
+	require.NoError(t, err)
+	var staged struct {
+		Action PendingAction `json:"action"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &staged))
+	assert.Equal(t, "staging", staged.Action.Connection)
+
+	action, err := s.approveAction(context.Background(), s.approvals.actionQueue, staged.Action.ID, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, ActionExecuted, action.Status)
+	assert.Equal(t, "DELETE FROM ORDERS", staging.query)
+	assert.Empty(t, primary.query)
+}
+
+func TestConnectionConfig(t *testing.T) {
+	primary := &connector.DatabaseConfig{Type: "snowflake"}
+	for name, cfgs := range map[string][]LabeledConnection{
+		"unlabeled":   {{Database: &connector.DatabaseConfig{}}},
+		"no database": {{Label: "staging"}},
+		"other type":  {{Label: "staging", Database: &connector.DatabaseConfig{Type: "mysql"}}},
+	} {
+		_, err := newConnectionSet(cfgs, primary)
+		assert.Error(t, err, name)
+	}
+	set, err := newConnectionSet(nil, primary)
+	assert.NoError(t, err)
+	assert.Nil(t, set)
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "X-API-Key", idempotencyKeyHeader, queryPriorityHeader, mcp.HeaderMcpSessionID}
+	defaultCORSExposed = []string{queryRetriesHeader, snapshotHeader, mcp.HeaderMcpSessionID}
+
+	// defaultSecurityHeaders are set on every API response unless disabled
+	defaultSecurityHeaders = map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "DENY",
+		"Referrer-Policy":        "no-referrer",
+	}
+)
+
+// CORSConfig allows browser-based tools on other origins to call the API
+type CORSConfig struct {
+	// AllowOrigins are the origins allowed to call the API, or * for any
+	AllowOrigins []string `json:"allow_origins"`
+
+	// AllowMethods are the methods allowed (default: GET, POST, PUT,
+	// PATCH, DELETE and OPTIONS)
+	AllowMethods []string `json:"allow_methods,omitempty"`
+
+	// AllowHeaders are the request headers allowed (default: the headers
+	// the API reads)
+	AllowHeaders []string `json:"allow_headers,omitempty"`
+
+	// ExposeHeaders are the response headers scripts may read (default:
+	// the headers the API sets)
+	ExposeHeaders []string `json:"expose_headers,omitempty"`
+
+	// AllowCredentials allows requests with cookies and authorization
+	AllowCredentials bool `json:"allow_credentials,omitempty"`
+
+	// MaxAge is how long browsers may cache preflight responses, in seconds
+	MaxAge int `json:"max_age,omitempty"`
+}
+
+// SecurityHeadersConfig controls the security headers of API responses
+type SecurityHeadersConfig struct {
+	// Disabled leaves out the default security headers
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Headers are set in addition to the defaults, replacing defaults of
+	// the same name; an empty value leaves the header out
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// validate checks that credentials are not allowed for any origin
+func (c *CORSConfig) validate() error {
+	if len(c.AllowOrigins) == 0 {
+		return fmt.Errorf("allow_origins is required")
+	}
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" && c.AllowCredentials {
+			return fmt.Errorf("allow_credentials cannot be combined with any origin")
+		}
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("max_age must not be negative")
+	}
+	return nil
+}
+
+// allowsOrigin reports whether an origin may call the API
+func (c *CORSConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.AllowOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// corsMiddleware answers preflight requests and sets the CORS headers of
+// requests from allowed origins
+func (s *MCPServerWithDB) corsMiddleware() gin.HandlerFunc {
+	cors := s.Config.CORS
+	methods, headers, exposed := defaultCORSMethods, defaultCORSHeaders, defaultCORSExposed
+	if cors != nil {
+		if len(cors.AllowMethods) > 0 {
+			methods = cors.AllowMethods
+		}
+		if len(cors.AllowHeaders) > 0 {
+			headers = cors.AllowHeaders
+		}
+		if len(cors.ExposeHeaders) > 0 {
+			exposed = cors.ExposeHeaders
+		}
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if cors == nil || origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+		if !cors.allowsOrigin(origin) {
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", origin)
+		if cors.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		// Preflight requests are answered without reaching the routes
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Header("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			c.Header("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			if cors.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(cors.MaxAge))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Header("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
+		c.Next()
+	}
+}
+
+// securityHeadersMiddleware sets the security headers of API responses
+func (s *MCPServerWithDB) securityHeadersMiddleware() gin.HandlerFunc {
+	headers := make(map[string]string)
+	cfg := s.Config.SecurityHeaders
+	if cfg == nil || !cfg.Disabled {
+		for name, value := range defaultSecurityHeaders {
+			headers[name] = value
+		}
+	}
+	if cfg != nil {
+		for name, value := range cfg.Headers {
+			if value == "" {
+				delete(headers, http.CanonicalHeaderKey(name))
+				continue
+			}
+			headers[http.CanonicalHeaderKey(name)] = value
+		}
+	}
+
+	return func(c *gin.Context) {
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSAndSecurityHeaders(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{
+		Name:            "sales",
+		CORS:            &CORSConfig{AllowOrigins: []string{"https://notebook.example.com"}, AllowCredentials: true, MaxAge: 600},
+		SecurityHeaders: &SecurityHeadersConfig{Headers: map[string]string{"x-frame-options": "", "Content-Security-Policy": "default-src 'none'"}},
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.corsMiddleware(), s.securityHeadersMiddleware())
+	router.GET("/tables", func(c *gin.Context) { c.JSON(http.StatusOK, []string{}) })
+	router.NoRoute(s.corsMiddleware(), func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/tables", nil)
+	req.Header.Set("Origin", "https://notebook.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://notebook.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Idempotency-Key")
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest(http.MethodGet, "/tables", nil)
+	req.Header.Set("Origin", "https://notebook.example.com")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://notebook.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "X-Query-Retries")
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "default-src 'none'", w.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, w.Header().Get("X-Frame-Options"))
+
+	// Other origins get no CORS headers
+	req = httptest.NewRequest(http.MethodGet, "/tables", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	assert.Error(t, (&CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true}).validate())
+	assert.Error(t, (&CORSConfig{}).validate())
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// DryRunResult describes a query that was checked and planned but not
+// executed
+type DryRunResult struct {
+	DryRun bool                   `json:"dry_run"`
+	Valid  bool                   `json:"valid"`
+	SQL    string                 `json:"sql,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty"`
+	Plan   *connector.QueryPlan   `json:"plan,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// dryRun renders a query with its parameters and plans it when the
+// connector supports planning. Problems with the query are reported in the
+// result rather than as an error.
+func (s *MCPServerWithDB) dryRun(ctx context.Context, query string, params map[string]interface{}) *DryRunResult {
+	result := &DryRunResult{DryRun: true, Params: params}
+
+	rendered, err := connector.RenderQuery(query, params)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.SQL = rendered
+
+	conn, err := s.selectedConn(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if planner, ok := conn.(connector.Planner); ok {
+		plan, err := planner.ExplainQuery(ctx, query, params)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		result.Plan = plan
+	}
+	result.Valid = true
+	return result
+}
+
+// isDryRun reports whether a request asks for a dry run with ?dry_run=true
+func isDryRun(c *gin.Context) bool {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	return dryRun
+}
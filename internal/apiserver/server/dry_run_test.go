@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// planConnector plans queries without executing them
+type planConnector struct {
+	paramsConnector
+	explained string
+}
+
+func (c *planConnector) ExplainQuery(_ context.Context, query string, _ map[string]interface{}) (*connector.QueryPlan, error) {
+	c.explained = query
+	return &connector.QueryPlan{PartitionsTotal: 4, PartitionsAssigned: 1, BytesAssigned: 512}, nil
+}
+
+func TestDryRun(t *testing.T) {
+	conn := &planConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	endpoint := connector.APIEndpoint{
+		Table:      "ORDERS",
+		Method:     http.MethodPut,
+		Path:       "/ORDERS/{ID}",
+		Query:      `UPDATE ORDERS SET CUSTOMER = :CUSTOMER WHERE ID = :ID`,
+		Parameters: map[string]interface{}{"ID": "ID of the ORDERS record"},
+		Columns:    orderColumns[1:2],
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	router.PUT("/ORDERS/:ID", s.generatedEndpointHandler(endpoint))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/ORDERS/7?dry_run=true", strings.NewReader(`{"CUSTOMER": "ACME"}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result DryRunResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.Valid)
+	assert.Equal(t, `UPDATE ORDERS SET CUSTOMER = 'ACME' WHERE ID = '7'`, result.SQL)
+	assert.Equal(t, int64(512), result.Plan.BytesAssigned)
+	assert.Equal(t, endpoint.Query, conn.explained)
+	assert.Empty(t, conn.query, "a dry run must not execute")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query": "SELECT * FROM ORDERS WHERE ID = :id", "dry_run": true}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	result = DryRunResult{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Error, "missing query parameter: id")
+	assert.Empty(t, conn.query)
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// EndpointStats summarizes the requests served by a generated endpoint
+// since the server started
+type EndpointStats struct {
+	Requests       int64      `json:"requests"`
+	Errors         int64      `json:"errors"`
+	LastStatus     int        `json:"last_status,omitempty"`
+	LastExecutedAt *time.Time `json:"last_executed_at,omitempty"`
+	LastDurationMS float64    `json:"last_duration_ms,omitempty"`
+	AvgDurationMS  float64    `json:"avg_duration_ms,omitempty"`
+}
+
+// add records a request answered with status
+func (st *EndpointStats) add(status int, start time.Time, elapsed time.Duration) {
+	ms := float64(elapsed.Microseconds()) / 1000
+	st.AvgDurationMS = (st.AvgDurationMS*float64(st.Requests) + ms) / float64(st.Requests+1)
+	st.Requests++
+	if status >= http.StatusBadRequest {
+		st.Errors++
+	}
+	at := start.UTC()
+	st.LastStatus, st.LastExecutedAt, st.LastDurationMS = status, &at, ms
+}
+
+// EndpointStatus describes a generated endpoint for the admin API
+type EndpointStatus struct {
+	Route       string        `json:"route"`
+	Method      string        `json:"method"`
+	Path        string        `json:"path"`
+	Table       string        `json:"table,omitempty"`
+	Operation   string        `json:"operation,omitempty"`
+	Description string        `json:"description,omitempty"`
+	SQL         string        `json:"sql"`
+	Warning     string        `json:"warning,omitempty"`
+	Disabled    bool          `json:"disabled"`
+	Stats       EndpointStats `json:"stats"`
+
+	// Tools lists the MCP tools serving the same operation on the table
+	Tools []string `json:"tools,omitempty"`
+}
+
+// GeneratedCaches reports which artifacts derived from the schema and the
+// generated endpoints are built
+type GeneratedCaches struct {
+	TableTools    bool `json:"table_tools"`
+	GraphQLSchema bool `json:"graphql_schema"`
+}
+
+// endpointRouteRequest names a generated endpoint by method and path, as
+// the path was generated, e.g. /ORDERS/{ID}
+type endpointRouteRequest struct {
+	Method string `json:"method" binding:"required"`
+	Path   string `json:"path" binding:"required"`
+}
+
+// endpointStatus describes a generated endpoint
+func (s *MCPServerWithDB) endpointStatus(e connector.APIEndpoint) EndpointStatus {
+	key := routeKey(e)
+	stats, disabled := s.routes.Stats(key)
+	return EndpointStatus{
+		Route:       key,
+		Method:      e.Method,
+		Path:        e.Path,
+		Table:       e.Table,
+		Operation:   e.Operation,
+		Description: e.Description,
+		SQL:         e.Query,
+		Warning:     e.Warning,
+		Disabled:    disabled,
+		Stats:       stats,
+		Tools:       s.endpointTools(e),
+	}
+}
+
+// endpointTools returns the names of the per-table MCP tools serving the
+// same operation as an endpoint; only list and get endpoints have one
+func (s *MCPServerWithDB) endpointTools(e connector.APIEndpoint) []string {
+	if !s.Config.TableTools || e.Table == "" {
+		return nil
+	}
+	var name string
+	switch e.Operation {
+	case connector.OperationList:
+		name = "list_" + tableToolBase(e.Table)
+	case connector.OperationGet:
+		name = "get_" + tableToolBase(e.Table)
+	default:
+		return nil
+	}
+	naming := s.toolNaming()
+	name = naming.namespaced(naming.Prefix, name)
+	if alias, ok := naming.Aliases[name]; ok {
+		name = alias
+	}
+	return []string{name}
+}
+
+// generatedCaches reports the state of the derived caches
+func (s *MCPServerWithDB) generatedCaches() GeneratedCaches {
+	s.tableToolsMu.Lock()
+	tableTools := s.tableToolsCache != nil
+	s.tableToolsMu.Unlock()
+	s.graphQLCache.mu.Lock()
+	graphQL := s.graphQLCache.schema != nil
+	s.graphQLCache.mu.Unlock()
+	return GeneratedCaches{TableTools: tableTools, GraphQLSchema: graphQL}
+}
+
+// setupEndpointAdminRoutes configures the admin routes introspecting the
+// generated endpoints and disabling or re-enabling them at runtime.
+// Disabled endpoints answer 503 until enabled again, also after being
+// regenerated.
+func (s *MCPServerWithDB) setupEndpointAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/endpoints", func(c *gin.Context) {
+		statuses := make([]EndpointStatus, 0)
+		if s.routes != nil {
+			for _, e := range s.routes.Endpoints() {
+				statuses = append(statuses, s.endpointStatus(e))
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"endpoints": statuses, "caches": s.generatedCaches()})
+	})
+
+	setDisabled := func(disabled bool) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			var request endpointRouteRequest
+			if err := c.ShouldBindJSON(&request); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+				return
+			}
+			if s.routes == nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Endpoint not found"})
+				return
+			}
+			e, ok := s.routes.Lookup(strings.ToUpper(request.Method) + " " + request.Path)
+			if !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Endpoint not found"})
+				return
+			}
+			s.routes.SetDisabled(routeKey(e), disabled)
+			c.JSON(http.StatusOK, s.endpointStatus(e))
+		}
+	}
+	router.POST("/admin/endpoints/disable", setDisabled(true))
+	router.POST("/admin/endpoints/enable", setDisabled(false))
+}
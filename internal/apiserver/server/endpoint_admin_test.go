@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", TableTools: true, ToolNaming: &ToolNamingConfig{Prefix: "sales"}},
+		DBConn: &rowsConnector{rows: []map[string]interface{}{{"ID": 1}}},
+	}
+	s.routes = newRouteManager("/api/db", s.generatedEndpointHandler)
+	_, err := s.routes.Apply([]connector.APIEndpoint{
+		{Table: "ORDERS", Operation: connector.OperationList, Method: "GET", Path: "/ORDERS", Query: "SELECT * FROM ORDERS"},
+		{Table: "ORDERS", Operation: connector.OperationDelete, Method: "DELETE", Path: "/ORDERS/{ID}", Query: "DELETE FROM ORDERS WHERE ID = :ID"},
+	})
+	require.NoError(t, err)
+
+	router := gin.New()
+	s.setupEndpointAdminRoutes(router.Group(""))
+	router.NoRoute(s.routes.ServeHTTP)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+	list := func() []EndpointStatus {
+		w := do(http.MethodGet, "/admin/endpoints", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Endpoints []EndpointStatus `json:"endpoints"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Endpoints
+	}
+
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/db/ORDERS", "").Code)
+	statuses := list()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "DELETE /ORDERS/{ID}", statuses[0].Route)
+	assert.Empty(t, statuses[0].Tools)
+	assert.Equal(t, "GET /ORDERS", statuses[1].Route)
+	assert.Equal(t, "SELECT * FROM ORDERS", statuses[1].SQL)
+	assert.Equal(t, []string{"sales_list_orders"}, statuses[1].Tools)
+	assert.Equal(t, int64(1), statuses[1].Stats.Requests)
+	assert.Equal(t, http.StatusOK, statuses[1].Stats.LastStatus)
+	assert.NotNil(t, statuses[1].Stats.LastExecutedAt)
+
+	w := do(http.MethodPost, "/admin/endpoints/disable", `{"method": "get", "path": "/ORDERS"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, do(http.MethodGet, "/api/db/ORDERS", "").Code)
+	assert.True(t, list()[1].Disabled)
+	assert.Equal(t, int64(1), list()[1].Stats.Requests)
+
+	// Regenerating the endpoint keeps it disabled
+	_, err = s.routes.Apply([]connector.APIEndpoint{
+		{Table: "ORDERS", Operation: connector.OperationList, Method: "GET", Path: "/ORDERS", Query: "SELECT ID FROM ORDERS"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, do(http.MethodGet, "/api/db/ORDERS", "").Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/admin/endpoints/enable", `{"method": "GET", "path": "/ORDERS"}`).Code)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/api/db/ORDERS", "").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/admin/endpoints/disable", `{"method": "GET", "path": "/MISSING"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/admin/endpoints/disable", `{}`).Code)
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// Formats of the entity-relationship diagram
+const (
+	ERDFormatMermaid = "mermaid"
+	ERDFormatDOT     = "dot"
+)
+
+// mermaidInvalidChars are the characters Mermaid names cannot hold
+var mermaidInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// erdTable is a table of the diagram with its columns
+type erdTable struct {
+	name    string
+	columns []connector.Column
+}
+
+// erdSchema is the content of a diagram: tables and the foreign keys
+// between them
+type erdSchema struct {
+	tables []erdTable
+	keys   []connector.ForeignKey
+}
+
+// loadERDSchema introspects the tables of the diagram, every table of the
+// schema unless selected. Selected tables are described one by one, which
+// also tells their primary keys.
+func (s *MCPServerWithDB) loadERDSchema(ctx context.Context, selected []string) (*erdSchema, error) {
+	columns := make(map[string][]connector.Column)
+	if len(selected) == 0 {
+		snapshot, err := s.introspectSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for table, cols := range snapshot {
+			columns[table] = cols
+		}
+	} else {
+		for _, table := range selected {
+			if err := s.checkTableName(ctx, table); err != nil {
+				return nil, err
+			}
+			metadata, err := s.DBConn.GetTableMetadata(ctx, table)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get metadata for table %s: %w", table, err)
+			}
+			columns[table] = metadata.Columns
+		}
+	}
+
+	keys, err := s.foreignKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	schema := &erdSchema{}
+	for _, key := range keys {
+		_, from := columns[key.Table]
+		_, to := columns[key.ReferencedTable]
+		if from && to {
+			schema.keys = append(schema.keys, key)
+		}
+	}
+	sort.Slice(schema.keys, func(i, j int) bool {
+		if schema.keys[i].Table != schema.keys[j].Table {
+			return schema.keys[i].Table < schema.keys[j].Table
+		}
+		return strings.Join(schema.keys[i].Columns, ",") < strings.Join(schema.keys[j].Columns, ",")
+	})
+	for table, cols := range columns {
+		schema.tables = append(schema.tables, erdTable{name: table, columns: cols})
+	}
+	sort.Slice(schema.tables, func(i, j int) bool { return schema.tables[i].name < schema.tables[j].name })
+	return schema, nil
+}
+
+// foreignKeyColumns returns the columns of each table that are part of a
+// foreign key
+func (e *erdSchema) foreignKeyColumns() map[string]map[string]bool {
+	fk := make(map[string]map[string]bool)
+	for _, key := range e.keys {
+		if fk[key.Table] == nil {
+			fk[key.Table] = make(map[string]bool)
+		}
+		for _, col := range key.Columns {
+			fk[key.Table][col] = true
+		}
+	}
+	return fk
+}
+
+// optional reports whether a foreign key may be null, so that a row may
+// reference no row
+func (e *erdSchema) optional(key connector.ForeignKey) bool {
+	for _, table := range e.tables {
+		if table.name != key.Table {
+			continue
+		}
+		for _, col := range table.columns {
+			for _, name := range key.Columns {
+				if col.Name == name && col.Nullable {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// mermaidName converts a table, column or type name to a Mermaid name
+func mermaidName(name string) string {
+	return mermaidInvalidChars.ReplaceAllString(name, "_")
+}
+
+// mermaid renders the diagram as a Mermaid erDiagram
+func (e *erdSchema) mermaid() string {
+	fk := e.foreignKeyColumns()
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, table := range e.tables {
+		fmt.Fprintf(&b, "    %s {\n", mermaidName(table.name))
+		for _, col := range table.columns {
+			var keys []string
+			if col.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if fk[table.name][col.Name] {
+				keys = append(keys, "FK")
+			}
+			typ := mermaidName(col.Type)
+			if typ == "" {
+				typ = "unknown"
+			}
+			fmt.Fprintf(&b, "        %s %s", typ, mermaidName(col.Name))
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ","))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, key := range e.keys {
+		referenced := "||"
+		if e.optional(key) {
+			referenced = "|o"
+		}
+		fmt.Fprintf(&b, "    %s %s--o{ %s : %q\n",
+			mermaidName(key.ReferencedTable), referenced, mermaidName(key.Table), strings.Join(key.Columns, ", "))
+	}
+	return b.String()
+}
+
+// dotEscaper escapes text for Graphviz HTML-like labels
+var dotEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+// dot renders the diagram as a Graphviz digraph with a table-shaped node
+// per table and an edge per foreign key column
+func (e *erdSchema) dot() string {
+	fk := e.foreignKeyColumns()
+	var b strings.Builder
+	b.WriteString("digraph erd {\n  rankdir=LR;\n  node [shape=plaintext];\n")
+	for _, table := range e.tables {
+		fmt.Fprintf(&b, "  %q [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\">", table.name)
+		fmt.Fprintf(&b, "<tr><td bgcolor=\"lightgrey\"><b>%s</b></td></tr>", dotEscaper.Replace(table.name))
+		for _, col := range table.columns {
+			label := []string{col.Name}
+			if col.Type != "" {
+				label = append(label, col.Type)
+			}
+			if col.PrimaryKey {
+				label = append(label, "PK")
+			}
+			if fk[table.name][col.Name] {
+				label = append(label, "FK")
+			}
+			fmt.Fprintf(&b, "<tr><td port=\"%s\" align=\"left\">%s</td></tr>", dotEscaper.Replace(col.Name), dotEscaper.Replace(strings.Join(label, " ")))
+		}
+		b.WriteString("</table>>];\n")
+	}
+	for _, key := range e.keys {
+		for i, col := range key.Columns {
+			if i >= len(key.ReferencedColumns) {
+				break
+			}
+			fmt.Fprintf(&b, "  %q:%q -> %q:%q;\n", key.Table, col, key.ReferencedTable, key.ReferencedColumns[i])
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// setupERDRoutes configures the entity-relationship diagram route
+func (s *MCPServerWithDB) setupERDRoutes(router *gin.RouterGroup) {
+	// ?tables=A,B limits the diagram to the listed tables
+	router.GET("/schema/erd", func(c *gin.Context) {
+		format := c.DefaultQuery("format", ERDFormatMermaid)
+		if format != ERDFormatMermaid && format != ERDFormatDOT {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported diagram format %q; use mermaid or dot", format)})
+			return
+		}
+		var selected []string
+		if tables := c.Query("tables"); tables != "" {
+			for _, table := range strings.Split(tables, ",") {
+				if table = strings.TrimSpace(table); table != "" {
+					selected = append(selected, table)
+				}
+			}
+		}
+
+		schema, err := s.loadERDSchema(c.Request.Context(), selected)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to generate diagram", err)
+			return
+		}
+		if format == ERDFormatDOT {
+			c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(schema.dot()))
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(schema.mermaid()))
+	})
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestERD(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: &keysConnector{}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupERDRoutes(router.Group(""))
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get("/schema/erd?tables=ORDERS,CUSTOMERS")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `erDiagram
+    CUSTOMERS {
+        unknown ID PK
+    }
+    ORDERS {
+        unknown ID PK
+        unknown CUSTOMER_ID FK
+        unknown SKU
+    }
+    CUSTOMERS ||--o{ ORDERS : "CUSTOMER_ID"
+`, w.Body.String())
+
+	w = get("/schema/erd?format=dot")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	body := w.Body.String()
+	assert.Contains(t, body, `"INVOICES":"BILLED_TO" -> "CUSTOMERS":"ID";`)
+	assert.Contains(t, body, `"ORDERS":"SKU" -> "PRODUCTS":"SKU";`)
+	assert.Contains(t, body, `<td port="CUSTOMER_ID" align="left">CUSTOMER_ID FK</td>`)
+
+	assert.Equal(t, http.StatusBadRequest, get("/schema/erd?format=svg").Code)
+	assert.Equal(t, http.StatusNotFound, get("/schema/erd?tables=MISSING").Code)
+}
+
+func TestMermaidOptionalReference(t *testing.T) {
+	schema := &erdSchema{
+		tables: []erdTable{
+			{name: "CUSTOMERS", columns: []connector.Column{{Name: "ID", Type: "NUMBER(38,0)", PrimaryKey: true}}},
+			{name: "ORDERS", columns: []connector.Column{{Name: "CUSTOMER ID", Type: "NUMBER", Nullable: true}}},
+		},
+		keys: []connector.ForeignKey{{Table: "ORDERS", Columns: []string{"CUSTOMER ID"}, ReferencedTable: "CUSTOMERS", ReferencedColumns: []string{"ID"}}},
+	}
+	diagram := schema.mermaid()
+	assert.Contains(t, diagram, "NUMBER_38_0_ ID PK")
+	assert.Contains(t, diagram, "NUMBER CUSTOMER_ID FK")
+	assert.Contains(t, diagram, `CUSTOMERS |o--o{ ORDERS : "CUSTOMER ID"`)
+}
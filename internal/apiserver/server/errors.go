@@ -0,0 +1,134 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// Error codes of requests denied by the gateway, alongside the database
+// error codes of the connector package
+const (
+	// CodePolicyDenied is a request denied by row security, tenant policy
+	// or the query policy
+	CodePolicyDenied = "POLICY_DENIED"
+
+	// CodeRateLimited is a query rejected by a full query queue
+	CodeRateLimited = "RATE_LIMITED"
+
+	// CodeNotFound is a routine, saved query, server or export that does
+	// not exist
+	CodeNotFound = "NOT_FOUND"
+
+	// CodeInvalidRequest is a request the gateway cannot run as given
+	CodeInvalidRequest = "INVALID_REQUEST"
+
+	// CodeUnsupported is a feature the database does not support
+	CodeUnsupported = "UNSUPPORTED"
+
+	// CodeLimitExceeded is a request larger than the server's limits
+	CodeLimitExceeded = "LIMIT_EXCEEDED"
+
+	// CodeQuotaExceeded is a query of a caller over its daily quota
+	CodeQuotaExceeded = "QUOTA_EXCEEDED"
+
+	// CodeExpired is a page token whose result the database no longer
+	// keeps
+	CodeExpired = "EXPIRED"
+)
+
+// publicMessages replace the messages of database errors in responses,
+// since driver messages carry SQL and internals
+var publicMessages = map[string]string{
+	connector.CodeAuthFailed:     "database authentication failed",
+	connector.CodeTableNotFound:  "table not found or not authorized",
+	connector.CodeInvalidQuery:   "the database could not compile the query",
+	connector.CodeQueryTimeout:   "query timed out",
+	connector.CodeQueryCancelled: "query was cancelled",
+	connector.CodeUnavailable:    "database is temporarily unavailable",
+	connector.CodeInternal:       "internal error",
+}
+
+// codeStatus maps error codes to HTTP status codes; others are 500
+var codeStatus = map[string]int{
+	CodePolicyDenied:             http.StatusForbidden,
+	CodeNotFound:                 http.StatusNotFound,
+	CodeInvalidRequest:           http.StatusBadRequest,
+	CodeUnsupported:              http.StatusNotImplemented,
+	CodeLimitExceeded:            http.StatusRequestEntityTooLarge,
+	CodeRateLimited:              http.StatusTooManyRequests,
+	CodeQuotaExceeded:            http.StatusTooManyRequests,
+	CodeExpired:                  http.StatusGone,
+	connector.CodeBudgetExceeded: http.StatusTooManyRequests,
+	connector.CodeTableNotFound:  http.StatusNotFound,
+	connector.CodeInvalidQuery:   http.StatusBadRequest,
+	connector.CodeAuthFailed:     http.StatusBadGateway,
+	connector.CodeQueryTimeout:   http.StatusGatewayTimeout,
+	connector.CodeUnavailable:    http.StatusServiceUnavailable,
+}
+
+// errorCode classifies the error of a request
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrRowSecurity), errors.Is(err, ErrTenantPolicy), errors.Is(err, ErrQueryPolicy),
+		errors.Is(err, ErrConnectionDenied):
+		return CodePolicyDenied
+	case errors.Is(err, ErrQueryQueueFull):
+		return CodeRateLimited
+	case errors.Is(err, ErrLimitExceeded):
+		return CodeLimitExceeded
+	case errors.Is(err, ErrQuotaExceeded):
+		return CodeQuotaExceeded
+	case errors.Is(err, ErrRoutineNotFound), errors.Is(err, ErrSavedQueryNotFound), errors.Is(err, ErrServerNotFound),
+		errors.Is(err, ErrExportNotFound), errors.Is(err, ErrBaselineNotFound), errors.Is(err, ErrPageTokenNotFound):
+		return CodeNotFound
+	case errors.Is(err, ErrPageTokenExpired):
+		return CodeExpired
+	case errors.Is(err, ErrInvalidTransaction), errors.Is(err, ErrInvalidSavedQuery), errors.Is(err, ErrInvalidRecipeInput),
+		errors.Is(err, ErrInvalidBaseline), errors.Is(err, ErrInvalidFederatedQuery), errors.Is(err, ErrInvalidExport),
+		errors.Is(err, ErrInvalidImport), errors.Is(err, ErrInvalidAssertion), errors.Is(err, ErrInvalidPageRequest),
+		errors.Is(err, ErrUnknownConnection), errors.Is(err, connector.ErrMissingParam):
+		return CodeInvalidRequest
+	case errors.Is(err, ErrRoutinesUnsupported), errors.Is(err, ErrTransactionsUnsupported), errors.Is(err, connector.ErrSavepointsUnsupported),
+		errors.Is(err, ErrExportsUnsupported), errors.Is(err, ErrSandboxUnsupported), errors.Is(err, ErrPagingUnsupported):
+		return CodeUnsupported
+	}
+	return connector.ErrorCode(err)
+}
+
+// publicMessage returns the message of an error shown to callers: the
+// generic message of its code for database errors, unless the server's
+// errors are in debug mode, else its own message
+func (s *MCPServerWithDB) publicMessage(code string, message string) string {
+	if generic, ok := publicMessages[code]; ok && !s.Config.DebugErrors {
+		return generic
+	}
+	return message
+}
+
+// respondError writes the error of a failed action with its code, logging
+// database errors whose messages are replaced
+func (s *MCPServerWithDB) respondError(c *gin.Context, status int, action string, err error) {
+	code := errorCode(err)
+	if _, ok := publicMessages[code]; ok {
+		log.Printf("Warning: %s: %v", action, err)
+	}
+	body := gin.H{
+		"error": fmt.Sprintf("%s: %s", action, s.publicMessage(code, err.Error())),
+		"code":  code,
+	}
+	// Callers over quota are told when they may retry
+	var exceeded *QuotaExceededError
+	if errors.As(err, &exceeded) {
+		body["reset_at"] = exceeded.ResetAt
+		c.Header("Retry-After", strconv.Itoa(int(time.Until(exceeded.ResetAt).Seconds())+1))
+	}
+	c.JSON(status, body)
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestRespondErrorSanitizesDatabaseErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	respond := func(debug bool, err error) (int, map[string]string) {
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", DebugErrors: debug}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body
+	}
+
+	// Driver messages are replaced unless errors are in debug mode
+	driverErr := fmt.Errorf("failed to execute query: %w", errors.New("syntax error near 'SELEC * FROM SECRET_TABLE'"))
+	status, body := respond(false, driverErr)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, map[string]string{"error": "Failed to execute query: internal error", "code": connector.CodeInternal}, body)
+	_, body = respond(true, driverErr)
+	assert.Contains(t, body["error"], "SECRET_TABLE")
+
+	// The gateway's own errors keep their messages
+	status, body = respond(false, fmt.Errorf("%w: free-form SQL is not allowed", ErrRowSecurity))
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, map[string]string{"error": "Failed to execute query: denied by row security: free-form SQL is not allowed", "code": CodePolicyDenied}, body)
+
+	status, body = respond(false, fmt.Errorf("%w: 64 queries queued", ErrQueryQueueFull))
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, CodeRateLimited, body["code"])
+}
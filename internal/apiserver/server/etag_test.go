@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestMetadataETags(t *testing.T) {
+	tables := []connector.Table{{Name: "ORDERS"}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tables", func(c *gin.Context) { respondWithETag(c, tables) })
+
+	get := func(match string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/tables", nil)
+		if match != "" {
+			req.Header.Set("If-None-Match", match)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.JSONEq(t, `[{"name":"ORDERS","row_count":0}]`, w.Body.String())
+
+	// Unchanged content is not sent again
+	w = get(`"other", W/` + etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	tables = append(tables, connector.Table{Name: "CUSTOMERS"})
+	w = get(etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/eval"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+)
+
+// evalPipeline exposes the server's NL-to-SQL pipeline to the eval runner
+type evalPipeline struct {
+	s *MCPServerWithDB
+}
+
+// GenerateSQL translates a question into SQL
+func (p evalPipeline) GenerateSQL(ctx context.Context, question string) (string, error) {
+	query, _, err := p.s.generateSQL(ctx, question)
+	return query, err
+}
+
+// Execute runs a query and returns its rows
+func (p evalPipeline) Execute(ctx context.Context, query string) ([]map[string]interface{}, error) {
+	return p.s.DBConn.ExecuteQuery(ctx, query, nil)
+}
+
+// RunEval runs the connection's eval cases against the current NL-to-SQL
+// pipeline and reports regressions against the previous run
+func (s *MCPServerWithDB) RunEval(ctx context.Context) (*eval.Report, error) {
+	if s.evals == nil {
+		return nil, fmt.Errorf("evaluations require a state store")
+	}
+	if s.llm == nil || s.DBConn == nil {
+		return nil, fmt.Errorf("evaluations require a database connection and an LLM provider")
+	}
+
+	t, err := s.Prompts.Get(prompt.NameNLToSQL)
+	if err != nil {
+		return nil, err
+	}
+	return eval.Execute(ctx, s.evals, evalPipeline{s: s}, s.Config.Name, t.Version)
+}
+
+// setupEvalRoutes configures the admin routes for NL-to-SQL evaluation
+func (s *MCPServerWithDB) setupEvalRoutes(router *gin.RouterGroup) {
+	if s.evals == nil {
+		return
+	}
+
+	router.GET("/admin/eval/cases", func(c *gin.Context) {
+		cases, err := s.evals.ListCases(c.Request.Context(), s.Config.Name)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list eval cases: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, cases)
+	})
+
+	router.POST("/admin/eval/cases", func(c *gin.Context) {
+		var evalCase eval.Case
+		if err := c.ShouldBindJSON(&evalCase); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		evalCase.Connection = s.Config.Name
+
+		if err := s.evals.AddCase(c.Request.Context(), &evalCase); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to add eval case: %v", err)})
+			return
+		}
+		c.JSON(http.StatusCreated, evalCase)
+	})
+
+	router.DELETE("/admin/eval/cases/:id", func(c *gin.Context) {
+		err := s.evals.DeleteCase(c.Request.Context(), s.Config.Name, c.Param("id"))
+		if errors.Is(err, eval.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Eval case not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to delete eval case: %v", err)})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	router.POST("/admin/eval/runs", func(c *gin.Context) {
+		report, err := s.RunEval(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to run evaluation: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, report)
+	})
+
+	router.GET("/admin/eval/runs", func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+		runs, err := s.evals.ListRuns(c.Request.Context(), s.Config.Name, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list eval runs: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, runs)
+	})
+
+	router.GET("/admin/eval/runs/:id", func(c *gin.Context) {
+		run, err := s.evals.GetRun(c.Request.Context(), s.Config.Name, c.Param("id"))
+		if errors.Is(err, eval.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Eval run not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get eval run: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, run)
+	})
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+)
+
+// Policies reported in policy violation events
+const (
+	policyCreditBudget = "credit_budget"
+	policyReadOnly     = "read_only"
+	policyTenant       = "tenant"
+	policyQuota        = "quota"
+	policyQuery        = "query_policy"
+)
+
+// publish sends an event about this server to the event sinks
+func (s *MCPServerWithDB) publish(eventType string, data map[string]interface{}) {
+	s.Events.Publish(&events.Event{
+		Type:   eventType,
+		Server: s.Config.Name,
+		Data:   data,
+	})
+}
+
+// executeQuery runs a query, queueing and tracking it so it can be listed
+// and cancelled, retrying it on transient errors and publishing an event
+// when it fails. source names the feature that
+// issued the query, e.g. rest or mcp.
+func (s *MCPServerWithDB) executeQuery(ctx context.Context, source, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if err := s.limits.checkParams(params); err != nil {
+		return nil, err
+	}
+	subject, err := s.admitQuery(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done, err := s.queries.start(ctx, source, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	started := time.Now()
+	var rows []map[string]interface{}
+	err = s.retries.do(ctx, query, func() (err error) {
+		return s.onConnection(ctx, source, query, func(conn connector.DatabaseConnector) (err error) {
+			rows, err = conn.ExecuteQuery(ctx, query, params)
+			return err
+		})
+	})
+	s.quotas.addRows(subject, len(rows))
+	s.recordHistory(ctx, source, query, started, len(rows), err)
+	s.reportQueryError(source, query, err)
+	return rows, err
+}
+
+// executeOrdered runs a query like executeQuery, keeping the order of the
+// result's columns when the connector reports it
+func (s *MCPServerWithDB) executeOrdered(ctx context.Context, source, query string, params map[string]interface{}) (*connector.ResultSet, error) {
+	if _, ok := s.DBConn.(connector.OrderedQuerier); !ok {
+		rows, err := s.executeQuery(ctx, source, query, params)
+		if err != nil {
+			return nil, err
+		}
+		return &connector.ResultSet{Rows: rows}, nil
+	}
+	if err := s.limits.checkParams(params); err != nil {
+		return nil, err
+	}
+	subject, err := s.admitQuery(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done, err := s.queries.start(ctx, source, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	started := time.Now()
+	var result *connector.ResultSet
+	err = s.retries.do(ctx, query, func() (err error) {
+		return s.onConnection(ctx, source, query, func(conn connector.DatabaseConnector) (err error) {
+			if querier, ok := conn.(connector.OrderedQuerier); ok {
+				result, err = querier.ExecuteQueryOrdered(ctx, query, params)
+				return err
+			}
+			rows, err := conn.ExecuteQuery(ctx, query, params)
+			result = &connector.ResultSet{Rows: rows}
+			return err
+		})
+	})
+	rows := 0
+	if result != nil {
+		rows = len(result.Rows)
+	}
+	s.quotas.addRows(subject, rows)
+	s.recordHistory(ctx, source, query, started, rows, err)
+	s.reportQueryError(source, query, err)
+	return result, err
+}
+
+// executeUnload unloads the rows of a query to cloud storage on the
+// primary, admitted, tracked, retried and recorded like executeQuery runs
+// queries
+func (s *MCPServerWithDB) executeUnload(ctx context.Context, source, query string, params map[string]interface{}, target connector.UnloadTarget) (*connector.UnloadResult, error) {
+	if err := s.limits.checkParams(params); err != nil {
+		return nil, err
+	}
+	subject, err := s.admitQuery(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done, err := s.queries.start(withPrimary(ctx), source, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	started := time.Now()
+	var result *connector.UnloadResult
+	err = s.retries.do(ctx, query, func() (err error) {
+		return s.onConnection(ctx, source, query, func(conn connector.DatabaseConnector) (err error) {
+			unloader, ok := conn.(connector.Unloader)
+			if !ok {
+				return ErrExportsUnsupported
+			}
+			result, err = unloader.Unload(ctx, query, params, target)
+			return err
+		})
+	})
+	rows := 0
+	if result != nil {
+		rows = int(result.Rows)
+	}
+	s.quotas.addRows(subject, rows)
+	s.recordHistory(ctx, source, query, started, rows, err)
+	s.reportQueryError(source, query, err)
+	return result, err
+}
+
+// reportQueryError publishes a failed query as a policy violation or a
+// query failure; nil errors and cancellations are ignored
+func (s *MCPServerWithDB) reportQueryError(source, query string, err error) {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+	case errors.Is(err, connector.ErrCreditBudgetExceeded):
+		s.publish(events.TypePolicyViolation, map[string]interface{}{
+			"policy": policyCreditBudget,
+			"source": source,
+			"error":  err.Error(),
+		})
+	default:
+		s.publish(events.TypeQueryFailed, map[string]interface{}{
+			"source": source,
+			"query":  query,
+			"error":  err.Error(),
+		})
+	}
+}
+
+// publishPolicyViolation publishes a server definition rejected by its
+// tenant's policy
+func (r *Registry) publishPolicyViolation(def *ServerDefinition, err error) {
+	if !errors.Is(err, ErrTenantPolicy) {
+		return
+	}
+	r.Events.Publish(&events.Event{
+		Type:   events.TypePolicyViolation,
+		Server: def.Name,
+		Tenant: def.Tenant,
+		Data: map[string]interface{}{
+			"policy": policyTenant,
+			"error":  err.Error(),
+		},
+	})
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/watermark"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/auth/jwt"
+)
+
+const (
+	defaultExportLimit = 10000
+	maxExportLimit     = 1000000
+
+	actionExport = "export"
+)
+
+// setupExportRoutes configures the export routes and the admin audit route
+func (s *MCPServerWithDB) setupExportRoutes(router *gin.RouterGroup) {
+	router.GET("/tables/:tableName/export", s.handleExportTable)
+
+	router.GET("/admin/audit", func(c *gin.Context) {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+		events, err := s.Audit.List(c.Request.Context(), audit.Filter{
+			Action:      c.Query("action"),
+			Principal:   c.Query("principal"),
+			Fingerprint: c.Query("fingerprint"),
+			Limit:       limit,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list audit events: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, events)
+	})
+}
+
+// handleExportTable exports the rows of a table, watermarking sensitive tables
+func (s *MCPServerWithDB) handleExportTable(c *gin.Context) {
+	tableName := c.Param("tableName")
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported export format: %s", format)})
+		return
+	}
+
+	limit := defaultExportLimit
+	if v := c.Query("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l <= 0 || l > maxExportLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit: %s", v)})
+			return
+		}
+		limit = l
+	}
+
+	// Only tables from the catalog are interpolated into the query
+	if err := s.checkTableName(c.Request.Context(), tableName); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, connector.ErrUnknownTable) {
+			status = http.StatusNotFound
+		}
+		s.respondError(c, status, "Failed to export table", err)
+		return
+	}
+
+	dialect := connector.DialectOf(s.DBConn)
+	query := fmt.Sprintf("SELECT * FROM %s %s", dialect.Table(tableName), dialect.LimitOffset(":limit", ""))
+	params := map[string]interface{}{"limit": limit}
+	query, err := s.restrictQuery(c.Request.Context(), tableName, query, params)
+	if err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to export table", err)
+		return
+	}
+	result, err := s.executeOrdered(c.Request.Context(), "export", query, params)
+	if err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to export table", err)
+		return
+	}
+
+	rows := result.Rows
+	if s.isSensitiveTable(tableName) {
+		rows = s.watermarkExport(c.Request.Context(), principalFromContext(c), []string{tableName}, rows,
+			map[string]interface{}{"format": format})
+	}
+
+	result.Rows = rows
+	if format == "csv" {
+		writeCSV(c, tableName, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// watermarkExport watermarks the exported rows of sensitive tables for the
+// principal they are exported to, and records the export of each table
+// with the fingerprint of the rows
+func (s *MCPServerWithDB) watermarkExport(ctx context.Context, principal string, tables []string, rows []map[string]interface{}, details map[string]interface{}) []map[string]interface{} {
+	if s.watermarker != nil {
+		rows = s.watermarker.Apply(principal, rows)
+	}
+	if s.Audit == nil {
+		return rows
+	}
+	details["row_count"] = len(rows)
+	details["watermarked"] = s.watermarker != nil
+	if s.watermarker != nil {
+		details["watermark_mode"] = s.watermarker.Mode()
+	}
+	fingerprint := watermark.Fingerprint(rows)
+	for _, table := range tables {
+		if err := s.Audit.Record(ctx, &audit.Event{
+			Action:      actionExport,
+			Principal:   principal,
+			Resource:    table,
+			Fingerprint: fingerprint,
+			Details:     details,
+		}); err != nil {
+			log.Printf("Warning: Failed to record export audit event: %v", err)
+		}
+	}
+	return rows
+}
+
+// sensitiveTables returns the sensitive tables a query names
+func (s *MCPServerWithDB) sensitiveTables(query string) []string {
+	var tables []string
+	for _, table := range s.Config.SensitiveTables {
+		if mentionsTable(query, table) {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// mentionsTable reports whether a query names a table, alone or qualified
+// by its schema
+func mentionsTable(query, table string) bool {
+	pattern := `(?i)(^|[^A-Za-z0-9_$"])"?` + regexp.QuoteMeta(table) + `"?($|[^A-Za-z0-9_$])`
+	return regexp.MustCompile(pattern).MatchString(query)
+}
+
+// isSensitiveTable reports whether the table is tagged as sensitive
+func (s *MCPServerWithDB) isSensitiveTable(tableName string) bool {
+	for _, t := range s.Config.SensitiveTables {
+		if strings.EqualFold(t, tableName) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalFromContext identifies the caller, preferring authenticated JWT
+// claims, then the tenant API key the request was authenticated with
+func principalFromContext(c *gin.Context) string {
+	if v, ok := c.Get("claims"); ok {
+		if claims, ok := v.(*jwt.Claims); ok && claims.Username != "" {
+			return claims.Username
+		}
+	}
+	if principal, ok := c.Request.Context().Value(principalKey{}).(string); ok && principal != "" {
+		return principal
+	}
+	return "anonymous@" + c.ClientIP()
+}
+
+// apiKeyFromContext returns the ID of the tenant API key that authenticated
+// the request, or an empty string
+func apiKeyFromContext(c *gin.Context) string {
+	if key := authenticatedKey(c.Request.Context()); key != nil {
+		return key.ID
+	}
+	return ""
+}
+
+// authenticatedKey returns the tenant API key that authenticated a request,
+// nil for other callers
+func authenticatedKey(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyKey{}).(*APIKey)
+	return key
+}
+
+// writeCSV writes rows as a CSV attachment with columns in result order
+func writeCSV(c *gin.Context, name string, rows *connector.ResultSet) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	c.Status(http.StatusOK)
+	_ = encodeCSV(c.Writer, rows)
+}
+
+// encodeCSV writes rows as CSV with a header row of the columns in result
+// order
+func encodeCSV(out io.Writer, rows *connector.ResultSet) error {
+	columns := rows.ColumnOrder()
+	w := csv.NewWriter(out)
+	_ = w.Write(columns)
+	for _, row := range rows.Rows {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			if v := row[col]; v != nil {
+				record[i] = fmt.Sprintf("%v", v)
+			}
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// checkTableName verifies that a client-supplied table name exists in the
+// catalog before it is used in generated SQL
+func (s *MCPServerWithDB) checkTableName(ctx context.Context, tableName string) error {
+	tables, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	for _, t := range tables {
+		if t.Name == tableName {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", connector.ErrUnknownTable, tableName)
+}
@@ -0,0 +1,459 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultExportConcurrency = 2
+	defaultExportMaxRows     = 1000000
+
+	// maxExportJobs is the number of jobs kept; the oldest finished jobs
+	// are forgotten first
+	maxExportJobs = 1000
+
+	actionExportJob = "export_job"
+)
+
+// Export job states
+const (
+	ExportPending   = "pending"
+	ExportRunning   = "running"
+	ExportSucceeded = "succeeded"
+	ExportFailed    = "failed"
+)
+
+// Export errors
+var (
+	ErrInvalidExport      = errors.New("invalid export")
+	ErrExportNotFound     = errors.New("export not found")
+	ErrExportsUnsupported = errors.New("database cannot unload to cloud storage")
+)
+
+var (
+	// cloudStorageSchemes are the destinations the database unloads to
+	cloudStorageSchemes = []string{"s3://", "gcs://", "azure://"}
+
+	// gatewayUploadSchemes are the destinations the gateway uploads to
+	gatewayUploadSchemes = []string{"https://"}
+
+	exportFormats        = map[string]bool{connector.ExportFormatCSV: true, connector.ExportFormatParquet: true}
+	gatewayUploadFormats = map[string]bool{connector.ExportFormatCSV: true}
+)
+
+// ExportConfig enables asynchronous exports of query results to cloud
+// storage. Databases that can unload results themselves, like Snowflake with
+// COPY INTO, write to s3://, gcs:// and azure:// locations; for other
+// databases the gateway uploads CSV files to signed https:// URLs.
+type ExportConfig struct {
+	// AllowedDestinations are the URL prefixes exports may write to, e.g.
+	// s3://bucket/exports/
+	AllowedDestinations []string `json:"allowed_destinations"`
+
+	// StorageIntegration is the Snowflake storage integration COPY INTO
+	// writes with
+	StorageIntegration string `json:"storage_integration,omitempty"`
+
+	// MaxConcurrent is the number of exports run at once (default: 2)
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+
+	// MaxRows is the most rows of an export uploaded by the gateway
+	// (default: 1000000)
+	MaxRows int `json:"max_rows,omitempty"`
+
+	// WebhookURL receives every finished export job
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// Headers are sent with every webhook request
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// validate checks that exports have somewhere to go
+func (c *ExportConfig) validate() error {
+	if len(c.AllowedDestinations) == 0 {
+		return fmt.Errorf("allowed_destinations is required")
+	}
+	if c.MaxConcurrent < 0 || c.MaxRows < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
+
+// ExportRequest asks for the rows of a query to be written to a destination
+type ExportRequest struct {
+	Query  string                 `json:"query"`
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Destination is the location URL the files are written under, or a
+	// signed URL the file is uploaded to
+	Destination string `json:"destination"`
+
+	// Format of the files: csv (default) or parquet
+	Format string `json:"format,omitempty"`
+}
+
+// ExportJob is the state and progress of an export
+type ExportJob struct {
+	ID          string     `json:"id"`
+	State       string     `json:"state"`
+	Query       string     `json:"query"`
+	Destination string     `json:"destination"`
+	Format      string     `json:"format"`
+	Principal   string     `json:"principal"`
+	Rows        int64      `json:"rows"`
+	Bytes       int64      `json:"bytes"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+
+	// target is the destination with the signature of signed URLs, which
+	// Destination leaves out
+	target string
+	params map[string]interface{}
+
+	// queryTag and claims are those of the caller, whom the export runs as
+	queryTag string
+	claims   map[string]interface{}
+}
+
+// exportJobs runs export jobs in the background
+type exportJobs struct {
+	cfg   *ExportConfig
+	slots chan struct{}
+
+	mu    sync.Mutex
+	jobs  map[string]*ExportJob
+	order []string
+}
+
+// newExportJobs creates the job runner of a configuration; nil disables
+// exports and returns a nil runner
+func newExportJobs(cfg *ExportConfig) (*exportJobs, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	concurrency := cfg.MaxConcurrent
+	if concurrency == 0 {
+		concurrency = defaultExportConcurrency
+	}
+	return &exportJobs{
+		cfg:   cfg,
+		slots: make(chan struct{}, concurrency),
+		jobs:  make(map[string]*ExportJob),
+	}, nil
+}
+
+// maxRows returns the row limit of gateway uploads
+func (e *exportJobs) maxRows() int {
+	if e.cfg.MaxRows > 0 {
+		return e.cfg.MaxRows
+	}
+	return defaultExportMaxRows
+}
+
+// add records a new pending job, forgetting the oldest finished jobs when
+// there are too many
+func (e *exportJobs) add(job *ExportJob) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.jobs[job.ID] = job
+	e.order = append(e.order, job.ID)
+	for i := 0; len(e.jobs) > maxExportJobs && i < len(e.order); {
+		if state := e.jobs[e.order[i]].State; state == ExportSucceeded || state == ExportFailed {
+			delete(e.jobs, e.order[i])
+			e.order = append(e.order[:i], e.order[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+// update changes a job under the lock and returns a copy of it
+func (e *exportJobs) update(id string, change func(job *ExportJob)) ExportJob {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	job := e.jobs[id]
+	change(job)
+	return *job
+}
+
+// get returns a copy of a job
+func (e *exportJobs) get(id string) (ExportJob, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	job, ok := e.jobs[id]
+	if !ok {
+		return ExportJob{}, false
+	}
+	return *job, true
+}
+
+// list returns copies of the jobs, newest first
+func (e *exportJobs) list() []ExportJob {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	jobs := make([]ExportJob, 0, len(e.jobs))
+	for _, job := range e.jobs {
+		jobs = append(jobs, *job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// checkExport validates an export request and applies its defaults
+func (s *MCPServerWithDB) checkExport(req *ExportRequest) error {
+	if !isReadOnlySQL(req.Query) {
+		return fmt.Errorf("%w: query must be a single read-only statement", ErrInvalidExport)
+	}
+	if req.Format == "" {
+		req.Format = connector.ExportFormatCSV
+	}
+	req.Format = strings.ToLower(req.Format)
+	if !exportFormats[req.Format] {
+		return fmt.Errorf("%w: unsupported format %s", ErrInvalidExport, req.Format)
+	}
+
+	allowed := false
+	for _, prefix := range s.exports.cfg.AllowedDestinations {
+		if strings.HasPrefix(req.Destination, prefix) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: destination is not allowed", ErrInvalidExport)
+	}
+
+	switch {
+	case hasScheme(req.Destination, cloudStorageSchemes):
+		if _, ok := s.DBConn.(connector.Unloader); !ok || s.exports.cfg.StorageIntegration == "" {
+			return fmt.Errorf("%w; export to a signed https:// URL instead", ErrExportsUnsupported)
+		}
+		// The database writes the files, which the gateway cannot watermark
+		if tables := s.sensitiveTables(req.Query); len(tables) > 0 {
+			return fmt.Errorf("%w: sensitive table %s can only be exported to a signed https:// URL", ErrInvalidExport, tables[0])
+		}
+	case hasScheme(req.Destination, gatewayUploadSchemes):
+		if !gatewayUploadFormats[req.Format] {
+			return fmt.Errorf("%w: %s files can only be written to cloud storage locations", ErrInvalidExport, req.Format)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported destination", ErrInvalidExport)
+	}
+	return s.limits.checkParams(req.Params)
+}
+
+// hasScheme reports whether a URL starts with one of the schemes
+func hasScheme(url string, schemes []string) bool {
+	for _, scheme := range schemes {
+		if strings.HasPrefix(strings.ToLower(url), scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSignature drops the query string of a URL, which carries the
+// signature of signed upload URLs
+func redactSignature(url string) string {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
+
+// startExport records an export job and runs it in the background as the
+// caller of ctx
+func (s *MCPServerWithDB) startExport(ctx context.Context, req *ExportRequest, principal string) ExportJob {
+	job := &ExportJob{
+		ID:          uuid.New().String(),
+		State:       ExportPending,
+		Query:       req.Query,
+		Destination: redactSignature(req.Destination),
+		Format:      req.Format,
+		Principal:   principal,
+		CreatedAt:   time.Now().UTC(),
+		target:      req.Destination,
+		params:      req.Params,
+		queryTag:    connector.QueryTagFromContext(ctx),
+		claims:      claimsFromContext(ctx),
+	}
+	s.exports.add(job)
+	pending := *job
+	go s.runExport(job.ID)
+	return pending
+}
+
+// runExport runs an export job once a slot is free, then reports it to the
+// audit trail and the webhook
+func (s *MCPServerWithDB) runExport(id string) {
+	select {
+	case s.exports.slots <- struct{}{}:
+	case <-s.ctx.Done():
+		return
+	}
+	defer func() { <-s.exports.slots }()
+
+	job := s.exports.update(id, func(job *ExportJob) {
+		now := time.Now().UTC()
+		job.State, job.StartedAt = ExportRunning, &now
+	})
+
+	ctx := withClaims(connector.WithQueryTag(s.ctx, job.queryTag), job.claims)
+	result, err := s.export(ctx, &job)
+	job = s.exports.update(id, func(job *ExportJob) {
+		now := time.Now().UTC()
+		job.FinishedAt = &now
+		if err != nil {
+			job.State = ExportFailed
+			job.Error = s.publicMessage(errorCode(err), err.Error())
+			return
+		}
+		job.State, job.Rows, job.Bytes = ExportSucceeded, result.Rows, result.Bytes
+	})
+	if err != nil {
+		log.Printf("Warning: Export %s failed: %v", id, err)
+	}
+
+	if s.Audit != nil {
+		if err := s.Audit.Record(s.ctx, &audit.Event{
+			Action:    actionExportJob,
+			Principal: job.Principal,
+			Resource:  s.Config.Name,
+			Details: map[string]interface{}{
+				"export_id":   job.ID,
+				"state":       job.State,
+				"query":       job.Query,
+				"destination": job.Destination,
+				"rows":        job.Rows,
+			},
+		}); err != nil {
+			log.Printf("Warning: Failed to record export audit event: %v", err)
+		}
+	}
+
+	if cfg := s.exports.cfg; cfg.WebhookURL != "" {
+		if err := postWebhook(s.ctx, cfg.WebhookURL, cfg.Headers, job); err != nil {
+			log.Printf("Warning: Failed to deliver export %s: %v", id, err)
+		}
+	}
+}
+
+// export writes the rows of a job's query to its destination, unloading
+// them from the database when it can write to cloud storage itself and
+// uploading them from the gateway otherwise
+func (s *MCPServerWithDB) export(ctx context.Context, job *ExportJob) (*connector.UnloadResult, error) {
+	if hasScheme(job.Destination, cloudStorageSchemes) {
+		return s.executeUnload(ctx, "export", job.Query, job.params, connector.UnloadTarget{
+			URL:                job.target,
+			Format:             job.Format,
+			StorageIntegration: s.exports.cfg.StorageIntegration,
+		})
+	}
+
+	maxRows := s.exports.maxRows()
+	d := connector.DialectOf(s.DBConn)
+	query := fmt.Sprintf("SELECT * FROM (%s) export %s", job.Query, d.LimitOffset(fmt.Sprint(maxRows+1), ""))
+	params := job.params
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	result, err := s.executeOrdered(ctx, "export", query, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) > maxRows {
+		return nil, fmt.Errorf("%w: export has more than %d rows", ErrLimitExceeded, maxRows)
+	}
+	s.exports.update(job.ID, func(j *ExportJob) { j.Rows = int64(len(result.Rows)) })
+	if tables := s.sensitiveTables(job.Query); len(tables) > 0 {
+		result.Rows = s.watermarkExport(ctx, job.Principal, tables, result.Rows,
+			map[string]interface{}{"format": job.Format, "export_id": job.ID, "destination": job.Destination})
+	}
+
+	var body bytes.Buffer
+	if err := encodeCSV(&body, result); err != nil {
+		return nil, err
+	}
+	size := int64(body.Len())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, job.target, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload export: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("upload returned status %d", resp.StatusCode)
+	}
+	return &connector.UnloadResult{Rows: int64(len(result.Rows)), Bytes: size}, nil
+}
+
+// setupExportJobRoutes configures the routes starting and tracking exports
+func (s *MCPServerWithDB) setupExportJobRoutes(router *gin.RouterGroup) {
+	if s.exports == nil {
+		return
+	}
+
+	router.POST("/exports", func(c *gin.Context) {
+		var req ExportRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if err := s.checkFreeForm(c.Request.Context()); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to start export", err)
+			return
+		}
+		if err := s.checkQueryPolicy(c.Request.Context(), "export", req.Query, req.Params); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to start export", err)
+			return
+		}
+		if err := s.checkExport(&req); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to start export", err)
+			return
+		}
+		principal := principalFromContext(c)
+		if s.parkRequest(c, req.Query, req.Params, func(ctx context.Context) (interface{}, error) {
+			return s.startExport(ctx, &req, principal), nil
+		}) {
+			return
+		}
+		c.JSON(http.StatusAccepted, s.startExport(c.Request.Context(), &req, principal))
+	})
+
+	router.GET("/exports", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.exports.list())
+	})
+
+	router.GET("/exports/:id", func(c *gin.Context) {
+		job, ok := s.exports.get(c.Param("id"))
+		if !ok {
+			s.respondError(c, http.StatusNotFound, "Failed to get export", ErrExportNotFound)
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	})
+}
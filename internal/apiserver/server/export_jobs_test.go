@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// unloadConnector unloads queries to cloud storage, recording the target
+type unloadConnector struct {
+	rowsConnector
+	target chan connector.UnloadTarget
+}
+
+func (c *unloadConnector) Unload(_ context.Context, _ string, _ map[string]interface{}, target connector.UnloadTarget) (*connector.UnloadResult, error) {
+	c.target <- target
+	return &connector.UnloadResult{Rows: 1200, Bytes: 4096}, nil
+}
+
+func TestExportJobs(t *testing.T) {
+	var uploaded string
+	storage := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploaded = string(body)
+	}))
+	defer storage.Close()
+	defaultClient := http.DefaultClient
+	http.DefaultClient = storage.Client()
+	defer func() { http.DefaultClient = defaultClient }()
+
+	finished := make(chan ExportJob, 2)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job ExportJob
+		_ = json.NewDecoder(r.Body).Decode(&job)
+		finished <- job
+	}))
+	defer hook.Close()
+
+	exports, err := newExportJobs(&ExportConfig{
+		AllowedDestinations: []string{storage.URL + "/exports/", "s3://bucket/exports/"},
+		StorageIntegration:  "EXPORTS",
+		WebhookURL:          hook.URL,
+	})
+	require.NoError(t, err)
+	conn := &unloadConnector{
+		rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": 1, "NAME": "Acme"}}},
+		target:        make(chan connector.UnloadTarget, 1),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, exports: exports, ctx: ctx}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupExportJobRoutes(router.Group("/"))
+	post := func(body string) (*httptest.ResponseRecorder, ExportJob) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/exports", strings.NewReader(body)))
+		var job ExportJob
+		_ = json.Unmarshal(w.Body.Bytes(), &job)
+		return w, job
+	}
+	wait := func() ExportJob {
+		select {
+		case job := <-finished:
+			return job
+		case <-time.After(5 * time.Second):
+			t.Fatal("export did not finish")
+			return ExportJob{}
+		}
+	}
+
+	// Signed URLs are uploaded to by the gateway
+	w, job := post(`{"query": "SELECT * FROM CUSTOMERS", "destination": "` + storage.URL + `/exports/customers.csv?signature=secret"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Equal(t, ExportPending, job.State)
+	assert.NotContains(t, job.Destination, "secret")
+	done := wait()
+	assert.Equal(t, ExportSucceeded, done.State)
+	assert.Equal(t, int64(1), done.Rows)
+	assert.Equal(t, "ID,NAME\n1,Acme\n", uploaded)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exports/"+job.ID, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"state":"succeeded"`)
+
+	// Cloud storage locations are unloaded to by the database
+	w, _ = post(`{"query": "SELECT * FROM ORDERS", "destination": "s3://bucket/exports/orders/", "format": "parquet"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Equal(t, connector.UnloadTarget{URL: "s3://bucket/exports/orders/", Format: "parquet", StorageIntegration: "EXPORTS"}, <-conn.target)
+	done = wait()
+	assert.Equal(t, ExportSucceeded, done.State)
+	assert.Equal(t, int64(1200), done.Rows)
+
+	for _, body := range []string{
+		`{"query": "DELETE FROM ORDERS", "destination": "s3://bucket/exports/x/"}`,
+		`{"query": "SELECT 1", "destination": "s3://other/x/"}`,
+		`{"query": "SELECT 1", "destination": "` + storage.URL + `/exports/x", "format": "parquet"}`,
+	} {
+		w, _ = post(body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+
+	// Sensitive tables are uploaded by the gateway only, and audited with
+	// the fingerprint of their rows
+	recorder := audit.NewMemoryRecorder(10)
+	s.Audit = recorder
+	s.Config.SensitiveTables = []string{"SALARIES"}
+	w, _ = post(`{"query": "SELECT * FROM HR.SALARIES", "destination": "s3://bucket/exports/salaries/"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "sensitive table SALARIES")
+	w, _ = post(`{"query": "SELECT * FROM HR.SALARIES", "destination": "` + storage.URL + `/exports/salaries.csv"}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Equal(t, ExportSucceeded, wait().State)
+	events, err := recorder.List(context.Background(), audit.Filter{Action: actionExport})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "SALARIES", events[0].Resource)
+	assert.NotEmpty(t, events[0].Fingerprint)
+
+	// Export queries are held to the query policy
+	s.queryPolicy, err = newQueryPolicy(&QueryPolicyConfig{}, "snowflake")
+	require.NoError(t, err)
+	w, _ = post(`{"query": "SELECT * FROM ORDERS // all of them", "destination": "s3://bucket/exports/orders/"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "comments are not allowed")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exports/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestExportJobAccounting(t *testing.T) {
+	exports, err := newExportJobs(&ExportConfig{AllowedDestinations: []string{"s3://bucket/exports/"}, StorageIntegration: "EXPORTS"})
+	require.NoError(t, err)
+	quotas, err := newQuotaTracker(&QuotaConfig{Default: &Quota{QueriesPerDay: 1}})
+	require.NoError(t, err)
+	conn := &unloadConnector{target: make(chan connector.UnloadTarget, 2)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, exports: exports, quotas: quotas, ctx: ctx}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupExportJobRoutes(router.Group("/", s.queryTagMiddleware()))
+	export := func() ExportJob {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/exports", strings.NewReader(`{"query": "SELECT * FROM ORDERS", "destination": "s3://bucket/exports/orders/"}`)))
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var job ExportJob
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		require.Eventually(t, func() bool {
+			job, _ = s.exports.get(job.ID)
+			return job.FinishedAt != nil
+		}, 5*time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	// Unloads count against the caller's quota like queries
+	assert.Equal(t, ExportSucceeded, export().State)
+	assert.Len(t, conn.target, 1)
+	assert.Equal(t, ExportFailed, export().State)
+	assert.Len(t, conn.target, 1)
+}
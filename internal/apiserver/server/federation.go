@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultFederationMaxSources    = 4
+	defaultFederationMaxSourceRows = 10000
+	defaultFederationMaxRows       = 1000
+	defaultFederationTimeout       = 30 * time.Second
+)
+
+// ErrInvalidFederatedQuery is returned for federated queries that are
+// malformed or not read-only
+var ErrInvalidFederatedQuery = errors.New("invalid federated query")
+
+// FederationConfig bounds the federated queries of the management API,
+// which join the results of queries on several servers
+type FederationConfig struct {
+	// MaxSources is the most source queries joined (default: 4)
+	MaxSources int `json:"max_sources,omitempty"`
+
+	// MaxSourceRows is the most rows a source query may return; queries
+	// returning more fail rather than join partial data (default: 10000)
+	MaxSourceRows int `json:"max_source_rows,omitempty"`
+
+	// MaxRows is the most rows a federated query may return (default: 1000)
+	MaxRows int `json:"max_rows,omitempty"`
+
+	// Timeout bounds the whole federated query (default: 30s)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// federationLimits are the limits of a FederationConfig with defaults applied
+type federationLimits struct {
+	maxSources    int
+	maxSourceRows int
+	maxRows       int
+	timeout       time.Duration
+}
+
+// newFederationLimits applies the defaults to a configuration, which may be nil
+func newFederationLimits(cfg *FederationConfig) (federationLimits, error) {
+	l := federationLimits{
+		maxSources:    defaultFederationMaxSources,
+		maxSourceRows: defaultFederationMaxSourceRows,
+		maxRows:       defaultFederationMaxRows,
+		timeout:       defaultFederationTimeout,
+	}
+	if cfg == nil {
+		return l, nil
+	}
+	if cfg.MaxSources < 0 || cfg.MaxSourceRows < 0 || cfg.MaxRows < 0 {
+		return l, fmt.Errorf("limits must not be negative")
+	}
+	if cfg.MaxSources > 0 {
+		l.maxSources = cfg.MaxSources
+	}
+	if cfg.MaxSourceRows > 0 {
+		l.maxSourceRows = cfg.MaxSourceRows
+	}
+	if cfg.MaxRows > 0 {
+		l.maxRows = cfg.MaxRows
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return l, fmt.Errorf("invalid timeout: %s", cfg.Timeout)
+		}
+		l.timeout = d
+	}
+	return l, nil
+}
+
+// FederatedQuery joins the results of read-only queries on several servers.
+// Each source query runs on its server and its rows are loaded into an
+// embedded SQLite database as a table named after the source; Query then
+// runs there, in SQLite's syntax.
+type FederatedQuery struct {
+	// Sources are the queries joined, keyed by the table they are loaded as
+	Sources map[string]FederatedSource `json:"sources"`
+
+	// Query joins the source tables
+	Query string `json:"query"`
+
+	// Params are bound to Query
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// FederatedSource is a query on one of the servers
+type FederatedSource struct {
+	Server string                 `json:"server"`
+	Query  string                 `json:"query"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// validate checks a federated query against the limits
+func (q *FederatedQuery) validate(l federationLimits) error {
+	switch {
+	case len(q.Sources) == 0:
+		return fmt.Errorf("%w: sources are required", ErrInvalidFederatedQuery)
+	case len(q.Sources) > l.maxSources:
+		return fmt.Errorf("%w: %d sources, more than %d", ErrLimitExceeded, len(q.Sources), l.maxSources)
+	case !isReadOnlySQL(q.Query):
+		return fmt.Errorf("%w: query must be a single read-only statement", ErrInvalidFederatedQuery)
+	}
+	for name, source := range q.Sources {
+		switch {
+		case name == "":
+			return fmt.Errorf("%w: sources must be named", ErrInvalidFederatedQuery)
+		case source.Server == "":
+			return fmt.Errorf("%w: source %s has no server", ErrInvalidFederatedQuery, name)
+		case !isReadOnlySQL(source.Query):
+			return fmt.Errorf("%w: source %s must be a single read-only statement", ErrInvalidFederatedQuery, name)
+		}
+	}
+	return nil
+}
+
+// Federate runs a federated query over the running servers of a tenant
+func (r *Registry) Federate(ctx context.Context, tenant string, q *FederatedQuery) (*connector.ResultSet, error) {
+	limits, err := newFederationLimits(r.Federation)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.validate(limits); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, limits.timeout)
+	defer cancel()
+
+	db, err := openSQLite("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open federation store: %w", err)
+	}
+	defer db.Close()
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Join results carry the kinds of the source columns of the same name
+	kinds := make(map[string]int)
+	for name, source := range q.Sources {
+		result, err := r.federatedSource(ctx, tenant, source, limits.maxSourceRows)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", name, err)
+		}
+		sourceKinds, err := createSQLiteTable(ctx, tx, name, result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load source %s: %w", name, err)
+		}
+		for col, kind := range sourceKinds {
+			kinds[col] = kind
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	params := q.Params
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	result, err := querySQLite(ctx, db, kinds, fmt.Sprintf("SELECT * FROM (%s) LIMIT %d", q.Query, limits.maxRows+1), params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFederatedQuery, err)
+	}
+	if len(result.Rows) > limits.maxRows {
+		return nil, fmt.Errorf("%w: query returns more than %d rows", ErrLimitExceeded, limits.maxRows)
+	}
+	return result, nil
+}
+
+// federatedSource runs a source query on its server, failing when it
+// returns more than maxRows rows
+func (r *Registry) federatedSource(ctx context.Context, tenant string, source FederatedSource, maxRows int) (*connector.ResultSet, error) {
+	srv, ok := r.server(tenant, source.Server)
+	if !ok || srv.DBConn == nil {
+		return nil, fmt.Errorf("%w: %s is not running", ErrServerNotFound, source.Server)
+	}
+	if err := srv.checkFreeForm(ctx); err != nil {
+		return nil, err
+	}
+	if err := srv.checkQueryPolicy(ctx, "federation", source.Query, source.Params); err != nil {
+		return nil, err
+	}
+
+	d := connector.DialectOf(srv.DBConn)
+	query := fmt.Sprintf("SELECT * FROM (%s) federated %s", source.Query, d.LimitOffset(strconv.Itoa(maxRows+1), ""))
+	params := source.Params
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	result, err := srv.executeOrdered(ctx, "federation", query, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) > maxRows {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrLimitExceeded, maxRows)
+	}
+	return result, nil
+}
+
+// setupFederationRoutes configures the federated query route of the tenant
+// returned by tenantOf
+func (r *Registry) setupFederationRoutes(router *gin.RouterGroup, tenantOf func(*gin.Context) string) {
+	router.POST("/admin/query", func(c *gin.Context) {
+		var q FederatedQuery
+		if err := c.ShouldBindJSON(&q); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		result, err := r.Federate(c.Request.Context(), tenantOf(c), &q)
+		if err != nil {
+			// Database messages of the sources are replaced like the
+			// servers' own
+			code, message := errorCode(err), err.Error()
+			if generic, ok := publicMessages[code]; ok {
+				log.Printf("Warning: Failed to run federated query: %v", err)
+				message = generic
+			}
+			c.JSON(queryErrorStatus(err), gin.H{"error": "Failed to run federated query: " + message, "code": code})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	})
+}
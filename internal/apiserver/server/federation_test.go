@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFederatedQuery(t *testing.T) {
+	sales := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: &rowsConnector{rows: []map[string]interface{}{
+		{"ID": int64(1), "CUSTOMER_ID": int64(10), "TOTAL": 99.5},
+		{"ID": int64(2), "CUSTOMER_ID": int64(11), "TOTAL": 12.0},
+		{"ID": int64(3), "CUSTOMER_ID": int64(10), "TOTAL": 5.25},
+	}}}
+	crm := &MCPServerWithDB{Config: &MCPServerConfig{Name: "crm"}, DBConn: &rowsConnector{rows: []map[string]interface{}{
+		{"ID": int64(10), "NAME": "Acme", "ACTIVE": true},
+		{"ID": int64(11), "NAME": "Globex", "ACTIVE": false},
+	}}}
+	r := &Registry{servers: map[string]*MCPServerWithDB{
+		serverKey("", "sales"): sales,
+		serverKey("", "crm"):   crm,
+	}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	r.setupFederationRoutes(router.Group("/"), func(*gin.Context) string { return "" })
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/query", strings.NewReader(body)))
+		return w
+	}
+
+	w := post(`{
+		"sources": {
+			"orders": {"server": "sales", "query": "SELECT * FROM ORDERS"},
+			"customers": {"server": "crm", "query": "SELECT * FROM CUSTOMERS"}
+		},
+		"query": "SELECT c.NAME, c.ACTIVE, SUM(o.TOTAL) AS TOTAL FROM orders o JOIN customers c ON o.CUSTOMER_ID = c.ID WHERE o.TOTAL > :min GROUP BY c.NAME, c.ACTIVE ORDER BY c.NAME",
+		"params": {"min": 10}
+	}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rows))
+	assert.Equal(t, []map[string]interface{}{
+		{"NAME": "Acme", "ACTIVE": true, "TOTAL": 99.5},
+		{"NAME": "Globex", "ACTIVE": false, "TOTAL": 12.0},
+	}, rows)
+
+	// Sources larger than the limit fail instead of joining partial data
+	r.Federation = &FederationConfig{MaxSourceRows: 2}
+	_, err := r.Federate(context.Background(), "", &FederatedQuery{
+		Sources: map[string]FederatedSource{"orders": {Server: "sales", Query: "SELECT * FROM ORDERS"}},
+		Query:   "SELECT * FROM orders",
+	})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	w = post(`{"sources": {"orders": {"server": "sales", "query": "DELETE FROM ORDERS"}}, "query": "SELECT * FROM orders"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post(`{"sources": {"orders": {"server": "billing", "query": "SELECT 1"}}, "query": "SELECT * FROM orders"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Source queries are held to the query policy of their server
+	sales.queryPolicy, err = newQueryPolicy(&QueryPolicyConfig{}, "snowflake")
+	require.NoError(t, err)
+	w = post(`{"sources": {"orders": {"server": "sales", "query": "SELECT * FROM ORDERS -- all"}}, "query": "SELECT * FROM orders"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+}
@@ -0,0 +1,471 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/graphql"
+)
+
+const (
+	defaultGraphQLMaxDepth      = 5
+	defaultGraphQLMaxComplexity = 5000
+	defaultGraphQLPageSize      = 100
+	maxGraphQLPageSize          = 1000
+)
+
+// GraphQLConfig enables a GraphQL API over the tables with generated
+// endpoints
+type GraphQLConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// MaxDepth limits the nesting of selections (default: 5)
+	MaxDepth int `json:"max_depth,omitempty"`
+
+	// MaxComplexity limits the estimated cost of an operation. Every field
+	// counts one, multiplied by the page size of the lists it is selected
+	// in (default: 5000).
+	MaxComplexity int `json:"max_complexity,omitempty"`
+}
+
+// limits returns the configured depth and complexity limits
+func (c *GraphQLConfig) limits() (int, int) {
+	depth, complexity := defaultGraphQLMaxDepth, defaultGraphQLMaxComplexity
+	if c.MaxDepth > 0 {
+		depth = c.MaxDepth
+	}
+	if c.MaxComplexity > 0 {
+		complexity = c.MaxComplexity
+	}
+	return depth, complexity
+}
+
+// gqlSchema is the GraphQL schema generated from table metadata: a type per
+// table with its columns and foreign key relations, list and by-key query
+// fields, and insert, update and delete mutations
+type gqlSchema struct {
+	dialect   connector.Dialect
+	types     map[string]*gqlType
+	typeNames []string
+	query     map[string]*gqlRoot
+	mutation  map[string]*gqlRoot
+}
+
+// gqlType is the object type of a table
+type gqlType struct {
+	name    string
+	table   string
+	columns []connector.Column
+	keys    []connector.Column // primary key; several columns when composite
+	fields  map[string]*gqlField
+	order   []string
+}
+
+// gqlField is a column or relation of an object type
+type gqlField struct {
+	name string
+
+	// column and its GraphQL scalar type, for column fields
+	column *connector.Column
+	scalar string
+
+	// target type and joined columns, for relation fields. Lists are the
+	// reverse side of a foreign key.
+	target *gqlType
+	list   bool
+	local  string
+	remote string
+}
+
+// Root field kinds
+const (
+	gqlList   = "list"
+	gqlByKey  = "by_pk"
+	gqlInsert = "insert"
+	gqlUpdate = "update"
+	gqlDelete = "delete"
+)
+
+// gqlRoot is a field of the Query or Mutation type
+type gqlRoot struct {
+	kind string
+	typ  *gqlType
+}
+
+// graphQLName turns a table or column name into a GraphQL name
+func graphQLName(name string) string {
+	n := connector.ParamName(name)
+	if n == "" || (n[0] >= '0' && n[0] <= '9') {
+		n = "_" + n
+	}
+	return n
+}
+
+// graphQLScalar maps a database type to a GraphQL scalar
+func graphQLScalar(dbType string) string {
+	switch jsonType, _ := jsonSchemaType(dbType); jsonType {
+	case "integer":
+		return "Int"
+	case "number":
+		return "Float"
+	case "boolean":
+		return "Boolean"
+	case "string":
+		return "String"
+	default:
+		return "JSON"
+	}
+}
+
+// buildGraphQLSchema generates the schema of the given tables
+func buildGraphQLSchema(dialect connector.Dialect, tables []*connector.TableMetadata) *gqlSchema {
+	schema := &gqlSchema{
+		dialect:  dialect,
+		types:    make(map[string]*gqlType),
+		query:    make(map[string]*gqlRoot),
+		mutation: make(map[string]*gqlRoot),
+	}
+	byTable := make(map[string]*gqlType)
+
+	for _, table := range tables {
+		typ := &gqlType{
+			name:    graphQLName(table.Name),
+			table:   table.Name,
+			columns: table.Columns,
+			keys:    connector.PrimaryKeyColumns(table.Columns),
+			fields:  make(map[string]*gqlField),
+		}
+		for i := range table.Columns {
+			col := &table.Columns[i]
+			typ.add(&gqlField{name: graphQLName(col.Name), column: col, scalar: graphQLScalar(col.Type)})
+		}
+		schema.types[typ.name] = typ
+		schema.typeNames = append(schema.typeNames, typ.name)
+		byTable[table.Name] = typ
+
+		schema.query[typ.name] = &gqlRoot{kind: gqlList, typ: typ}
+		schema.mutation["insert_"+typ.name] = &gqlRoot{kind: gqlInsert, typ: typ}
+		if len(typ.keys) > 0 {
+			schema.query[typ.name+"_by_pk"] = &gqlRoot{kind: gqlByKey, typ: typ}
+			schema.mutation["update_"+typ.name] = &gqlRoot{kind: gqlUpdate, typ: typ}
+			schema.mutation["delete_"+typ.name] = &gqlRoot{kind: gqlDelete, typ: typ}
+		}
+	}
+	sort.Strings(schema.typeNames)
+
+	// Foreign keys become a field on each side; names taken by columns or
+	// earlier relations are left alone
+	for _, name := range schema.typeNames {
+		typ := schema.types[name]
+		for _, col := range typ.columns {
+			i := strings.LastIndexByte(col.References, '.')
+			if !col.ForeignKey || i < 0 {
+				continue
+			}
+			target, ok := byTable[col.References[:i]]
+			if !ok {
+				continue
+			}
+			remote := col.References[i+1:]
+			if _, taken := typ.fields[target.name]; !taken {
+				typ.add(&gqlField{name: target.name, target: target, local: col.Name, remote: remote})
+			}
+			if _, taken := target.fields[typ.name]; !taken {
+				target.add(&gqlField{name: typ.name, target: typ, list: true, local: remote, remote: col.Name})
+			}
+		}
+	}
+	return schema
+}
+
+// add appends a field to the type
+func (t *gqlType) add(f *gqlField) {
+	if _, ok := t.fields[f.name]; ok {
+		return
+	}
+	t.fields[f.name] = f
+	t.order = append(t.order, f.name)
+}
+
+// filterable reports whether list fields accept an equality filter on the field
+func (f *gqlField) filterable() bool {
+	return f.column != nil && f.scalar != "JSON"
+}
+
+// SDL renders the schema in the GraphQL schema definition language
+func (s *gqlSchema) SDL() string {
+	var b strings.Builder
+	b.WriteString("scalar JSON\n")
+
+	for _, name := range s.typeNames {
+		typ := s.types[name]
+		fmt.Fprintf(&b, "\ntype %s {\n", typ.name)
+		for _, fieldName := range typ.order {
+			f := typ.fields[fieldName]
+			switch {
+			case f.column != nil:
+				writeDescription(&b, f.column.Description)
+				fmt.Fprintf(&b, "  %s: %s\n", f.name, nonNull(f.scalar, !f.column.Nullable))
+			case f.list:
+				fmt.Fprintf(&b, "  %s(limit: Int = %d): [%s!]!\n", f.name, defaultGraphQLPageSize, f.target.name)
+			default:
+				fmt.Fprintf(&b, "  %s: %s\n", f.name, f.target.name)
+			}
+		}
+		b.WriteString("}\n")
+
+		// Inserts may omit the columns the database has a default for
+		writeInput := func(suffix string, columns []connector.Column, insert bool) {
+			fmt.Fprintf(&b, "\ninput %s_%s {\n", typ.name, suffix)
+			for _, col := range columns {
+				required := !col.Nullable && (!insert || col.Default == "")
+				fmt.Fprintf(&b, "  %s: %s\n", graphQLName(col.Name), nonNull(graphQLScalar(col.Type), required))
+			}
+			b.WriteString("}\n")
+		}
+		writeInput("insert_input", connector.InsertColumns(s.dialect, typ.columns), true)
+		if len(typ.keys) > 0 {
+			writeInput("set_input", typ.setColumns(), false)
+		}
+	}
+
+	b.WriteString("\ntype Query {\n")
+	for _, name := range s.typeNames {
+		typ := s.types[name]
+		args := []string{fmt.Sprintf("limit: Int = %d", defaultGraphQLPageSize), "offset: Int = 0"}
+		for _, fieldName := range typ.order {
+			if f := typ.fields[fieldName]; f.filterable() {
+				args = append(args, fmt.Sprintf("%s: %s", f.name, f.scalar))
+			}
+		}
+		fmt.Fprintf(&b, "  %s(%s): [%s!]!\n", typ.name, strings.Join(args, ", "), typ.name)
+		if len(typ.keys) > 0 {
+			fmt.Fprintf(&b, "  %s_by_pk(%s): %s\n", typ.name, typ.keyArguments(), typ.name)
+		}
+	}
+	b.WriteString("}\n")
+
+	b.WriteString("\ntype Mutation {\n")
+	for _, name := range s.typeNames {
+		typ := s.types[name]
+		fmt.Fprintf(&b, "  insert_%s(input: %s_insert_input!): JSON\n", typ.name, typ.name)
+		if len(typ.keys) > 0 {
+			fmt.Fprintf(&b, "  update_%s(%s, set: %s_set_input!): JSON\n", typ.name, typ.keyArguments(), typ.name)
+			fmt.Fprintf(&b, "  delete_%s(%s): JSON\n", typ.name, typ.keyArguments())
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// keyArguments renders the primary key arguments of by-key fields
+func (t *gqlType) keyArguments() string {
+	args := make([]string, len(t.keys))
+	for i, key := range t.keys {
+		args[i] = fmt.Sprintf("%s: %s!", graphQLName(key.Name), graphQLScalar(key.Type))
+	}
+	return strings.Join(args, ", ")
+}
+
+// keyNames returns the argument names of the primary key columns
+func (t *gqlType) keyNames() []string {
+	names := make([]string, len(t.keys))
+	for i, key := range t.keys {
+		names[i] = graphQLName(key.Name)
+	}
+	return names
+}
+
+// setColumns returns the columns accepted by the update mutation
+func (t *gqlType) setColumns() []connector.Column {
+	var columns []connector.Column
+	for _, col := range t.columns {
+		if !col.PrimaryKey {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// nonNull marks a type non-null when required
+func nonNull(typ string, required bool) string {
+	if required {
+		return typ + "!"
+	}
+	return typ
+}
+
+// writeDescription writes a field description when there is one
+func writeDescription(b *strings.Builder, description string) {
+	if description == "" {
+		return
+	}
+	quoted, _ := json.Marshal(description)
+	fmt.Fprintf(b, "  %s\n", quoted)
+}
+
+// graphQLSchemaCache holds the schema generated for the current endpoints
+type graphQLSchemaCache struct {
+	mu     sync.Mutex
+	key    string
+	schema *gqlSchema
+}
+
+// graphQLSchema returns the schema of the tables with generated endpoints,
+// regenerating it when the endpoints change
+func (s *MCPServerWithDB) graphQLSchema(ctx context.Context) (*gqlSchema, error) {
+	var endpoints []connector.APIEndpoint
+	if s.routes != nil {
+		endpoints = s.routes.Endpoints()
+	}
+	data, err := json.Marshal(endpoints)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+
+	s.graphQLCache.mu.Lock()
+	defer s.graphQLCache.mu.Unlock()
+	if s.graphQLCache.schema != nil && s.graphQLCache.key == key {
+		return s.graphQLCache.schema, nil
+	}
+
+	seen := make(map[string]bool)
+	var tables []*connector.TableMetadata
+	for _, e := range endpoints {
+		if e.Table == "" || seen[e.Table] {
+			continue
+		}
+		seen[e.Table] = true
+		metadata, err := s.tableMetadata(ctx, e.Table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata for table %s: %w", e.Table, err)
+		}
+		tables = append(tables, metadata)
+	}
+
+	s.graphQLCache.schema = buildGraphQLSchema(connector.DialectOf(s.DBConn), tables)
+	s.graphQLCache.key = key
+	return s.graphQLCache.schema, nil
+}
+
+// graphQLRequest is a GraphQL request, sent as a JSON body or as query
+// parameters
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLResponse is the result of a GraphQL request
+type graphQLResponse struct {
+	Data   interface{}    `json:"data,omitempty"`
+	Errors []graphQLError `json:"errors,omitempty"`
+}
+
+// graphQLError is an error of a GraphQL response
+type graphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// setupGraphQLRoutes configures the GraphQL endpoint when enabled
+func (s *MCPServerWithDB) setupGraphQLRoutes(router *gin.RouterGroup) {
+	if s.Config.GraphQL == nil || !s.Config.GraphQL.Enabled {
+		return
+	}
+
+	router.POST("/graphql", s.handleGraphQL)
+	router.GET("/graphql", s.handleGraphQL)
+
+	// Schema in the schema definition language, in place of introspection
+	router.GET("/graphql/schema", func(c *gin.Context) {
+		schema, err := s.graphQLSchema(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to generate GraphQL schema: %v", err)})
+			return
+		}
+		c.String(http.StatusOK, schema.SDL())
+	})
+}
+
+// handleGraphQL executes a GraphQL operation. Mutations must be POSTed.
+func (s *MCPServerWithDB) handleGraphQL(c *gin.Context) {
+	fail := func(status int, format string, args ...interface{}) {
+		c.JSON(status, graphQLResponse{Errors: []graphQLError{{Message: fmt.Sprintf(format, args...)}}})
+	}
+
+	var request graphQLRequest
+	if c.Request.Method == http.MethodGet {
+		request.Query = c.Query("query")
+		request.OperationName = c.Query("operationName")
+		if v := c.Query("variables"); v != "" {
+			dec := json.NewDecoder(strings.NewReader(v))
+			dec.UseNumber()
+			if err := dec.Decode(&request.Variables); err != nil {
+				fail(http.StatusBadRequest, "Invalid variables: %v", err)
+				return
+			}
+		}
+	} else {
+		dec := json.NewDecoder(c.Request.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&request); err != nil {
+			fail(http.StatusBadRequest, "Invalid request: %v", err)
+			return
+		}
+	}
+
+	doc, err := graphql.Parse(request.Query)
+	if err != nil {
+		fail(http.StatusBadRequest, "%v", err)
+		return
+	}
+	op, err := doc.Operation(request.OperationName)
+	if err != nil {
+		fail(http.StatusBadRequest, "%v", err)
+		return
+	}
+	if op.Type == graphql.OperationMutation && c.Request.Method == http.MethodGet {
+		fail(http.StatusMethodNotAllowed, "mutations must be sent with POST")
+		return
+	}
+	variables, err := op.VariableValues(request.Variables)
+	if err != nil {
+		fail(http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	schema, err := s.graphQLSchema(c.Request.Context())
+	if err != nil {
+		fail(http.StatusInternalServerError, "Failed to generate GraphQL schema: %v", err)
+		return
+	}
+
+	exec := &graphQLExecution{
+		s:         s,
+		ctx:       c.Request.Context(),
+		schema:    schema,
+		dialect:   schema.dialect,
+		variables: variables,
+	}
+	maxDepth, maxComplexity := s.Config.GraphQL.limits()
+	if err := exec.check(op, maxDepth, maxComplexity); err != nil {
+		fail(http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	data := exec.execute(op)
+	c.JSON(http.StatusOK, graphQLResponse{Data: data, Errors: exec.errors})
+}
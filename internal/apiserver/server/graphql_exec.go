@@ -0,0 +1,570 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/graphql"
+)
+
+// graphQLExecution executes one operation against the generated schema
+type graphQLExecution struct {
+	s         *MCPServerWithDB
+	ctx       context.Context
+	schema    *gqlSchema
+	dialect   connector.Dialect
+	variables map[string]interface{}
+	errors    []graphQLError
+}
+
+// gqlObject is a response object keeping its fields in selection order
+type gqlObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newGQLObject() *gqlObject {
+	return &gqlObject{values: make(map[string]interface{})}
+}
+
+// set sets a field, keeping the position of fields set before
+func (o *gqlObject) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON encodes the fields in selection order
+func (o *gqlObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// check validates an operation against the schema and enforces the depth
+// and complexity limits before anything is executed
+func (e *graphQLExecution) check(op *graphql.Operation, maxDepth, maxComplexity int) error {
+	roots, rootType := e.schema.query, "Query"
+	if op.Type == graphql.OperationMutation {
+		roots, rootType = e.schema.mutation, "Mutation"
+	}
+
+	complexity := 0
+	for _, f := range op.SelectionSet {
+		if f.Skipped(e.variables) {
+			continue
+		}
+		if f.Name == "__typename" {
+			complexity++
+			continue
+		}
+		root, ok := roots[f.Name]
+		if !ok {
+			if strings.HasPrefix(f.Name, "__") {
+				return fmt.Errorf("introspection is not supported, the schema is served at /graphql/schema")
+			}
+			return fmt.Errorf("cannot query field %q on type %q", f.Name, rootType)
+		}
+		if err := e.checkRootArguments(root, f); err != nil {
+			return err
+		}
+
+		switch root.kind {
+		case gqlList, gqlByKey:
+			if len(f.SelectionSet) == 0 {
+				return fmt.Errorf("field %q of type %q must have a selection of subfields", f.Name, root.typ.name)
+			}
+			cost, err := e.checkSelection(root.typ, f.SelectionSet, 2, maxDepth)
+			if err != nil {
+				return err
+			}
+			multiplier := 1
+			if root.kind == gqlList {
+				if multiplier, err = e.pageSize(f.Arguments["limit"]); err != nil {
+					return err
+				}
+			}
+			complexity += 1 + multiplier*cost
+		default:
+			if len(f.SelectionSet) > 0 {
+				return fmt.Errorf("field %q returns JSON and has no subfields", f.Name)
+			}
+			complexity++
+		}
+	}
+
+	if complexity > maxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, maxComplexity)
+	}
+	return nil
+}
+
+// checkRootArguments rejects unknown and missing arguments of a root field
+func (e *graphQLExecution) checkRootArguments(root *gqlRoot, f *graphql.Field) error {
+	allowed := map[string]bool{}
+	var required []string
+	switch root.kind {
+	case gqlList:
+		allowed["limit"], allowed["offset"] = true, true
+		for _, name := range root.typ.order {
+			if root.typ.fields[name].filterable() {
+				allowed[name] = true
+			}
+		}
+	case gqlByKey, gqlDelete:
+		required = root.typ.keyNames()
+	case gqlUpdate:
+		required = append(root.typ.keyNames(), "set")
+	case gqlInsert:
+		required = []string{"input"}
+	}
+	for _, name := range required {
+		allowed[name] = true
+		if graphql.Resolve(f.Arguments[name], e.variables) == nil {
+			return fmt.Errorf("field %q requires argument %q", f.Name, name)
+		}
+	}
+	for name := range f.Arguments {
+		if !allowed[name] {
+			return fmt.Errorf("unknown argument %q on field %q", name, f.Name)
+		}
+	}
+	return nil
+}
+
+// checkSelection validates a selection of an object type and returns its
+// complexity
+func (e *graphQLExecution) checkSelection(typ *gqlType, fields []*graphql.Field, depth, maxDepth int) (int, error) {
+	if depth > maxDepth {
+		return 0, fmt.Errorf("query depth exceeds the limit of %d", maxDepth)
+	}
+
+	complexity := 0
+	for _, f := range fields {
+		if f.Skipped(e.variables) {
+			continue
+		}
+		if f.Name == "__typename" {
+			complexity++
+			continue
+		}
+		field, ok := typ.fields[f.Name]
+		if !ok {
+			return 0, fmt.Errorf("cannot query field %q on type %q", f.Name, typ.name)
+		}
+
+		if field.column != nil {
+			if len(f.SelectionSet) > 0 {
+				return 0, fmt.Errorf("field %q of type %q has no subfields", f.Name, typ.name)
+			}
+			if len(f.Arguments) > 0 {
+				return 0, fmt.Errorf("field %q of type %q takes no arguments", f.Name, typ.name)
+			}
+			complexity++
+			continue
+		}
+
+		if len(f.SelectionSet) == 0 {
+			return 0, fmt.Errorf("field %q of type %q must have a selection of subfields", f.Name, typ.name)
+		}
+		multiplier := 1
+		for name := range f.Arguments {
+			if !field.list || name != "limit" {
+				return 0, fmt.Errorf("unknown argument %q on field %q", name, f.Name)
+			}
+		}
+		if field.list {
+			var err error
+			if multiplier, err = e.pageSize(f.Arguments["limit"]); err != nil {
+				return 0, err
+			}
+		}
+		cost, err := e.checkSelection(field.target, f.SelectionSet, depth+1, maxDepth)
+		if err != nil {
+			return 0, err
+		}
+		complexity += 1 + multiplier*cost
+	}
+	return complexity, nil
+}
+
+// pageSize resolves a limit argument
+func (e *graphQLExecution) pageSize(arg interface{}) (int, error) {
+	return e.intArgument("limit", arg, defaultGraphQLPageSize, 1, maxGraphQLPageSize)
+}
+
+// intArgument resolves an Int argument within bounds
+func (e *graphQLExecution) intArgument(name string, arg interface{}, def, min, max int) (int, error) {
+	value := graphql.Resolve(arg, e.variables)
+	if value == nil {
+		return def, nil
+	}
+	n, ok := value.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("argument %q must be an Int", name)
+	}
+	i, err := n.Int64()
+	if err != nil || i < int64(min) || i > int64(max) {
+		return 0, fmt.Errorf("argument %q must be an Int between %d and %d", name, min, max)
+	}
+	return int(i), nil
+}
+
+// execute runs an operation, collecting field errors. Mutations run in
+// selection order.
+func (e *graphQLExecution) execute(op *graphql.Operation) *gqlObject {
+	roots, rootType := e.schema.query, "Query"
+	if op.Type == graphql.OperationMutation {
+		roots, rootType = e.schema.mutation, "Mutation"
+	}
+
+	data := newGQLObject()
+	for _, f := range op.SelectionSet {
+		if f.Skipped(e.variables) {
+			continue
+		}
+		key := f.ResponseKey()
+		if f.Name == "__typename" {
+			data.set(key, rootType)
+			continue
+		}
+
+		var value interface{}
+		var err error
+		root := roots[f.Name]
+		switch root.kind {
+		case gqlList, gqlByKey:
+			value, err = e.resolveRoot(root, f, []interface{}{key})
+		default:
+			value, err = e.mutate(root, f)
+		}
+		if err != nil {
+			e.errors = append(e.errors, graphQLError{Message: err.Error(), Path: []interface{}{key}})
+			value = nil
+		}
+		data.set(key, value)
+	}
+	return data
+}
+
+// resolveRoot selects the rows of a list or by-key field
+func (e *graphQLExecution) resolveRoot(root *gqlRoot, f *graphql.Field, path []interface{}) (interface{}, error) {
+	typ := root.typ
+	var where []string
+	params := make(map[string]interface{})
+
+	limit, offset := 1, 0
+	if root.kind == gqlByKey {
+		if err := e.keyParams(typ, f, params); err != nil {
+			return nil, err
+		}
+		for _, key := range typ.keys {
+			where = append(where, fmt.Sprintf("%s = :%s", e.dialect.QuoteIdentifier(key.Name), connector.ParamName(key.Name)))
+		}
+	} else {
+		var err error
+		if limit, err = e.pageSize(f.Arguments["limit"]); err != nil {
+			return nil, err
+		}
+		if offset, err = e.intArgument("offset", f.Arguments["offset"], 0, 0, 1<<31-1); err != nil {
+			return nil, err
+		}
+		for _, name := range typ.order {
+			field := typ.fields[name]
+			arg, ok := f.Arguments[name]
+			if !ok || !field.filterable() {
+				continue
+			}
+			param := "where_" + connector.ParamName(field.column.Name)
+			value := graphql.Resolve(arg, e.variables)
+			if value == nil {
+				where = append(where, fmt.Sprintf("%s IS NULL", e.dialect.QuoteIdentifier(field.column.Name)))
+				continue
+			}
+			v, err := checkColumnValue(*field.column, value)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q %v", name, err)
+			}
+			where = append(where, fmt.Sprintf("%s = :%s", e.dialect.QuoteIdentifier(field.column.Name), param))
+			params[param] = v
+		}
+	}
+	params["limit"], params["offset"] = limit, offset
+
+	query := fmt.Sprintf("SELECT %s FROM %s", e.projection(typ, f.SelectionSet, ""), e.dialect.Table(typ.table))
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " " + e.dialect.LimitOffset(":limit", ":offset")
+	query, err := e.s.restrictQuery(e.ctx, typ.table, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := e.s.executeQuery(e.ctx, "graphql", query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	objects := e.resolveRows(typ, rows, f.SelectionSet, path)
+	if root.kind == gqlByKey {
+		if len(objects) == 0 {
+			return nil, nil
+		}
+		return objects[0], nil
+	}
+	return objects, nil
+}
+
+// projection returns the column list selecting the columns a selection
+// reads, including the columns relations are joined on
+func (e *graphQLExecution) projection(typ *gqlType, fields []*graphql.Field, extra string) string {
+	needed := make(map[string]bool)
+	if extra != "" {
+		needed[extra] = true
+	}
+	for _, f := range fields {
+		if field, ok := typ.fields[f.Name]; ok && !f.Skipped(e.variables) {
+			if field.column != nil {
+				needed[field.column.Name] = true
+			} else {
+				needed[field.local] = true
+			}
+		}
+	}
+
+	var columns []string
+	for _, col := range typ.columns {
+		if needed[col.Name] {
+			columns = append(columns, e.dialect.QuoteIdentifier(col.Name))
+		}
+	}
+	if len(columns) == 0 {
+		// Only __typename was selected; one column is enough to count rows
+		columns = append(columns, e.dialect.QuoteIdentifier(typ.columns[0].Name))
+	}
+	return strings.Join(columns, ", ")
+}
+
+// resolveRows builds the response objects of rows, resolving relations with
+// one query per relation field for all rows
+func (e *graphQLExecution) resolveRows(typ *gqlType, rows []map[string]interface{}, fields []*graphql.Field, path []interface{}) []*gqlObject {
+	objects := make([]*gqlObject, len(rows))
+	for i := range objects {
+		objects[i] = newGQLObject()
+	}
+
+	for _, f := range fields {
+		if f.Skipped(e.variables) {
+			continue
+		}
+		key := f.ResponseKey()
+		if f.Name == "__typename" {
+			for _, obj := range objects {
+				obj.set(key, typ.name)
+			}
+			continue
+		}
+
+		field := typ.fields[f.Name]
+		if field.column != nil {
+			for i, row := range rows {
+				objects[i].set(key, row[field.column.Name])
+			}
+			continue
+		}
+
+		fieldPath := append(append([]interface{}{}, path...), key)
+		related, err := e.resolveRelation(field, rows, f, fieldPath)
+		if err != nil {
+			e.errors = append(e.errors, graphQLError{Message: err.Error(), Path: fieldPath})
+		}
+		for i := range objects {
+			if err != nil {
+				objects[i].set(key, nil)
+				continue
+			}
+			objects[i].set(key, related[i])
+		}
+	}
+	return objects
+}
+
+// resolveRelation selects the rows related to each row with a single IN
+// query, returning an object (or list of objects) per row
+func (e *graphQLExecution) resolveRelation(field *gqlField, rows []map[string]interface{}, f *graphql.Field, path []interface{}) ([]interface{}, error) {
+	limit := 1
+	if field.list {
+		var err error
+		if limit, err = e.pageSize(f.Arguments["limit"]); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[string]bool)
+	var keys []interface{}
+	for _, row := range rows {
+		if v := row[field.local]; v != nil && !seen[fmt.Sprint(v)] {
+			seen[fmt.Sprint(v)] = true
+			keys = append(keys, v)
+		}
+	}
+
+	grouped := make(map[string][]*gqlObject)
+	if len(keys) > 0 {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s IN (:keys)",
+			e.projection(field.target, f.SelectionSet, field.remote),
+			e.dialect.Table(field.target.table),
+			e.dialect.QuoteIdentifier(field.remote))
+		params := map[string]interface{}{"keys": keys}
+		query, err := e.s.restrictQuery(e.ctx, field.target.table, query, params)
+		if err != nil {
+			return nil, err
+		}
+		related, err := e.s.executeQuery(e.ctx, "graphql", query, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute query: %w", err)
+		}
+		objects := e.resolveRows(field.target, related, f.SelectionSet, path)
+		for i, row := range related {
+			k := fmt.Sprint(row[field.remote])
+			if len(grouped[k]) < limit {
+				grouped[k] = append(grouped[k], objects[i])
+			}
+		}
+	}
+
+	values := make([]interface{}, len(rows))
+	for i, row := range rows {
+		group := grouped[fmt.Sprint(row[field.local])]
+		if row[field.local] == nil {
+			group = nil
+		}
+		switch {
+		case field.list && group == nil:
+			values[i] = []*gqlObject{}
+		case field.list:
+			values[i] = group
+		case len(group) > 0:
+			values[i] = group[0]
+		default:
+			values[i] = nil
+		}
+	}
+	return values, nil
+}
+
+// mutate runs an insert, update or delete mutation and returns the rows
+// the database reports
+func (e *graphQLExecution) mutate(root *gqlRoot, f *graphql.Field) (interface{}, error) {
+	typ := root.typ
+	var query string
+	var params map[string]interface{}
+
+	switch root.kind {
+	case gqlInsert:
+		columns := connector.InsertColumns(e.dialect, typ.columns)
+		values, err := e.inputParams(columns, f.Arguments["input"], true)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("invalid input: no columns to insert")
+		}
+		query, params = connector.InsertValuesQuery(e.dialect, typ.table, columns, values), values
+	case gqlUpdate:
+		values, err := e.inputParams(typ.setColumns(), f.Arguments["set"], false)
+		if err != nil {
+			return nil, err
+		}
+		query, params = connector.UpdateQuery(e.dialect, typ.table, connector.ColumnNames(typ.keys), typ.columns), values
+	case gqlDelete:
+		query, params = connector.DeleteQuery(e.dialect, typ.table, connector.ColumnNames(typ.keys)...), make(map[string]interface{})
+	}
+
+	if root.kind != gqlInsert {
+		if err := e.keyParams(typ, f, params); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if root.kind == gqlInsert {
+		err = e.s.checkTableAccess(e.ctx, typ.table)
+	} else {
+		query, err = e.s.restrictQuery(e.ctx, typ.table, query, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := e.s.executeQuery(e.ctx, "graphql", query, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return rows, nil
+}
+
+// keyParams binds the primary key arguments of a by-key field
+func (e *graphQLExecution) keyParams(typ *gqlType, f *graphql.Field, params map[string]interface{}) error {
+	for _, key := range typ.keys {
+		name := graphQLName(key.Name)
+		value, err := checkColumnValue(key, graphql.Resolve(f.Arguments[name], e.variables))
+		if err != nil {
+			return fmt.Errorf("argument %q %v", name, err)
+		}
+		params[connector.ParamName(key.Name)] = value
+	}
+	return nil
+}
+
+// inputParams validates an input object against columns like a request
+// body of a generated write endpoint
+func (e *graphQLExecution) inputParams(columns []connector.Column, arg interface{}, insert bool) (map[string]interface{}, error) {
+	input, ok := graphql.Resolve(arg, e.variables).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("input must be an object")
+	}
+
+	// Input fields are named like the type's fields; validate by column name
+	body := make(map[string]interface{}, len(input))
+	byName := make(map[string]string, len(columns))
+	for _, col := range columns {
+		byName[graphQLName(col.Name)] = col.Name
+	}
+	for name, value := range input {
+		if col, ok := byName[name]; ok {
+			body[col] = value
+		} else {
+			body[name] = value
+		}
+	}
+
+	params, fieldErrs := validateBody(columns, body, insert)
+	if len(fieldErrs) > 0 {
+		msgs := make([]string, len(fieldErrs))
+		for i, fe := range fieldErrs {
+			msgs[i] = fmt.Sprintf("%s %s", graphQLName(fe.Field), fe.Message)
+		}
+		return nil, fmt.Errorf("invalid input: %s", strings.Join(msgs, "; "))
+	}
+	return params, nil
+}
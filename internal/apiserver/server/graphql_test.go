@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// graphQLConnector serves table metadata and answers queries by the table
+// they select from
+type graphQLConnector struct {
+	connector.DatabaseConnector
+	tables  map[string]*connector.TableMetadata
+	rows    map[string][]map[string]interface{}
+	queries []string
+	params  []map[string]interface{}
+}
+
+func (c *graphQLConnector) GetTableMetadata(_ context.Context, table string) (*connector.TableMetadata, error) {
+	return c.tables[table], nil
+}
+
+func (c *graphQLConnector) ExecuteQuery(_ context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.queries = append(c.queries, query)
+	c.params = append(c.params, params)
+	for table, rows := range c.rows {
+		if strings.Contains(query, `FROM "`+table+`"`) {
+			return rows, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestGraphQL(t *testing.T) {
+	conn := &graphQLConnector{
+		tables: map[string]*connector.TableMetadata{
+			"CUSTOMERS": {Name: "CUSTOMERS", Columns: []connector.Column{
+				{Name: "ID", Type: "INTEGER", PrimaryKey: true},
+				{Name: "NAME", Type: "VARCHAR", Description: "Customer name"},
+			}},
+			"ORDERS": {Name: "ORDERS", Columns: []connector.Column{
+				{Name: "ID", Type: "INTEGER", PrimaryKey: true},
+				{Name: "CUSTOMER_ID", Type: "INTEGER", ForeignKey: true, References: "CUSTOMERS.ID"},
+				{Name: "STATUS", Type: "VARCHAR", Nullable: true},
+			}},
+		},
+		rows: map[string][]map[string]interface{}{
+			"ORDERS": {
+				{"ID": int64(1), "CUSTOMER_ID": int64(10), "STATUS": "open"},
+				{"ID": int64(2), "CUSTOMER_ID": int64(10), "STATUS": "open"},
+			},
+			"CUSTOMERS": {{"ID": int64(10), "NAME": "ACME"}},
+		},
+	}
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", GraphQL: &GraphQLConfig{Enabled: true, MaxDepth: 4}},
+		DBConn: conn,
+		routes: newRouteManager("/api", func(connector.APIEndpoint) gin.HandlerFunc { return func(*gin.Context) {} }),
+	}
+	_, err := s.routes.Apply([]connector.APIEndpoint{
+		{Table: "ORDERS", Method: http.MethodGet, Path: "/ORDERS", Query: "list"},
+		{Table: "CUSTOMERS", Method: http.MethodGet, Path: "/CUSTOMERS", Query: "list"},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupGraphQLRoutes(router.Group(""))
+	post := func(body string) (int, graphQLResponse, string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+		var resp graphQLResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return w.Code, resp, w.Body.String()
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql/schema", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "type ORDERS {\n  ID: Int!\n  CUSTOMER_ID: Int!\n  STATUS: String\n  CUSTOMERS: CUSTOMERS\n}")
+	assert.Contains(t, w.Body.String(), "  ORDERS(limit: Int = 100): [ORDERS!]!")
+	assert.Contains(t, w.Body.String(), "  ORDERS(limit: Int = 100, offset: Int = 0, ID: Int, CUSTOMER_ID: Int, STATUS: String): [ORDERS!]!")
+	assert.Contains(t, w.Body.String(), "  update_ORDERS(ID: Int!, set: ORDERS_set_input!): JSON")
+
+	// Relations are resolved with one batched query per field
+	code, _, body := post(`{"query": "query($status: String) { open: ORDERS(STATUS: $status, limit: 5) { ID CUSTOMERS { NAME } } }", "variables": {"status": "open"}}`)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.JSONEq(t, `{"data": {"open": [{"ID": 1, "CUSTOMERS": {"NAME": "ACME"}}, {"ID": 2, "CUSTOMERS": {"NAME": "ACME"}}]}}`, body)
+	assert.True(t, strings.HasPrefix(body, `{"data":{"open":[{"ID":1,"CUSTOMERS"`), "fields keep their selection order")
+	require.Len(t, conn.queries, 2)
+	assert.Equal(t, `SELECT "ID", "CUSTOMER_ID" FROM "ORDERS" WHERE "STATUS" = :where_STATUS LIMIT :limit OFFSET :offset`, conn.queries[0])
+	assert.Equal(t, map[string]interface{}{"where_STATUS": "open", "limit": 5, "offset": 0}, conn.params[0])
+	assert.Equal(t, `SELECT "ID", "NAME" FROM "CUSTOMERS" WHERE "ID" IN (:keys)`, conn.queries[1])
+	assert.Equal(t, map[string]interface{}{"keys": []interface{}{int64(10)}}, conn.params[1])
+
+	// Mutations validate their input like generated write endpoints
+	code, resp, body := post(`{"query": "mutation { insert_CUSTOMERS(input: {ID: 11, NAME: 5}) }"}`)
+	require.Equal(t, http.StatusOK, code, body)
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "invalid input: NAME must be a string, got number", resp.Errors[0].Message)
+	code, resp, body = post(`{"query": "mutation { update_CUSTOMERS(ID: 11, set: {NAME: \"Initech\"}) }"}`)
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, `UPDATE "CUSTOMERS" SET "NAME" = :NAME WHERE "ID" = :ID`, conn.queries[len(conn.queries)-1])
+	assert.Equal(t, map[string]interface{}{"ID": int64(11), "NAME": "Initech"}, conn.params[len(conn.params)-1])
+
+	// Operations are checked before anything runs
+	executed := len(conn.queries)
+	for query, msg := range map[string]string{
+		`{ ORDERS { PRICE } }`: `cannot query field "PRICE" on type "ORDERS"`,
+		`{ ORDERS { CUSTOMERS { ORDERS { CUSTOMERS { ID } } } } }`:           `query depth exceeds the limit of 4`,
+		`{ ORDERS(limit: 1000) { CUSTOMERS { ORDERS(limit: 10) { ID } } } }`: `query complexity 12001 exceeds the limit of 5000`,
+		`{ __schema { types { name } } }`:                                    `introspection is not supported, the schema is served at /graphql/schema`,
+		`{ ORDERS_by_pk { ID } }`:                                            `field "ORDERS_by_pk" requires argument "ID"`,
+	} {
+		data, _ := json.Marshal(map[string]string{"query": query})
+		code, resp, _ := post(string(data))
+		assert.Equal(t, http.StatusBadRequest, code, query)
+		require.Len(t, resp.Errors, 1, query)
+		assert.Equal(t, msg, resp.Errors[0].Message, query)
+	}
+	assert.Len(t, conn.queries, executed)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query="+strings.ReplaceAll(`mutation { delete_ORDERS(ID: 1) }`, " ", "%20"), nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/grpc"
+)
+
+// GRPCConfig serves the Gateway gRPC service of grpc/gateway.proto
+type GRPCConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Addr is the listen address of the gRPC service (default: :9090)
+	Addr string `json:"addr,omitempty"`
+}
+
+// grpcGateway implements the Gateway service with the server's connector
+type grpcGateway struct {
+	s *MCPServerWithDB
+}
+
+func (g grpcGateway) ListTables(ctx context.Context) ([]connector.Table, error) {
+	tables, err := g.s.DBConn.ListTables(ctx)
+	if err != nil {
+		return nil, grpc.Errorf(grpc.Internal, "failed to list tables: %v", err)
+	}
+	return tables, nil
+}
+
+func (g grpcGateway) GetTableMetadata(ctx context.Context, table string) (*connector.TableMetadata, error) {
+	metadata, err := g.s.tableMetadata(ctx, table)
+	if err != nil {
+		return nil, grpc.Errorf(grpc.Internal, "failed to get table metadata: %v", err)
+	}
+	if g.s.Config.EnableLLM {
+		if err := g.s.DBConn.EnhanceMetadataWithLLM(ctx, metadata); err != nil {
+			log.Printf("Warning: Failed to enhance metadata with LLM: %v", err)
+		}
+	}
+	return g.s.redactSamples(ctx, g.s.withCatalog(g.s.withComputedColumns(metadata))), nil
+}
+
+func (g grpcGateway) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	if err := g.s.checkFreeForm(ctx); err != nil {
+		return nil, grpc.Errorf(grpc.PermissionDenied, "%v", err)
+	}
+	if err := g.s.checkQueryPolicy(ctx, "grpc", query, params); err != nil {
+		return nil, grpc.Errorf(grpc.PermissionDenied, "%v", err)
+	}
+	rows, err := g.s.executeQuery(ctx, "grpc", query, params)
+	switch {
+	case err == nil:
+		return rows, nil
+	case errors.Is(err, connector.ErrCreditBudgetExceeded), errors.Is(err, ErrQueryQueueFull), errors.Is(err, ErrQuotaExceeded):
+		return nil, grpc.Errorf(grpc.ResourceExhausted, "failed to execute query: %v", err)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return nil, err
+	}
+	return nil, grpc.Errorf(grpc.Internal, "failed to execute query: %v", err)
+}
+
+// grpcHandler serves the Gateway service
+func (s *MCPServerWithDB) grpcHandler() http.Handler {
+	srv := grpc.NewServer()
+	grpc.RegisterGateway(srv, grpcGateway{s: s})
+	return s.grpcTagMiddleware(srv)
+}
+
+// grpcTagMiddleware tags the queries of gRPC calls with the caller and
+// reads the caller's claims for row filters
+func (s *MCPServerWithDB) grpcTagMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.network != nil && !s.network.allows(r) {
+			grpc.Abort(w, grpc.Errorf(grpc.PermissionDenied, "client address not allowed by the network policy"))
+			return
+		}
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		tag := s.queryTag("grpc", "", "anonymous@"+host, "", "")
+		ctx := connector.WithQueryTag(r.Context(), tag)
+		if s.rowSecurity != nil {
+			claims, err := s.rowSecurity.callerClaims(r, nil)
+			if err != nil {
+				grpc.Abort(w, grpc.Errorf(grpc.Unauthenticated, "invalid token: %v", err))
+				return
+			}
+			ctx = withClaims(ctx, claims)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// startGRPC starts the gRPC listener
+func (s *MCPServerWithDB) startGRPC(cfg *GRPCConfig) {
+	addr := cfg.Addr
+	if addr == "" {
+		addr = ":9090"
+	}
+	s.grpcServer = grpc.NewHTTPServer(addr, s.grpcHandler())
+	go func(srv *http.Server) {
+		log.Printf("Starting gRPC server on %s", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("gRPC server error: %v", err)
+		}
+	}(s.grpcServer)
+}
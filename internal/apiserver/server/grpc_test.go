@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/grpc"
+)
+
+// failingConnector fails every query with err
+type failingConnector struct {
+	connector.DatabaseConnector
+	err error
+}
+
+func (c *failingConnector) ExecuteQuery(context.Context, string, map[string]interface{}) ([]map[string]interface{}, error) {
+	return nil, c.err
+}
+
+func TestGRPCGateway(t *testing.T) {
+	conn := &failingConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+
+	// Calls are tagged with the caller like REST requests
+	var tagged string
+	probe := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		tagged = connector.QueryTagFromContext(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = "10.0.0.7:51234"
+	s.grpcTagMiddleware(probe).ServeHTTP(httptest.NewRecorder(), req)
+	var tag queryTag
+	require.NoError(t, json.Unmarshal([]byte(tagged), &tag))
+	assert.Equal(t, "grpc", tag.Client)
+	assert.Equal(t, "anonymous@10.0.0.7", tag.Principal)
+
+	g := grpcGateway{s: s}
+	_, err := g.ExecuteQuery(context.Background(), "SELECT 1", nil)
+	require.NoError(t, err)
+
+	conn.err = fmt.Errorf("%w: 12.5 of 10 credits used", connector.ErrCreditBudgetExceeded)
+	_, err = g.ExecuteQuery(context.Background(), "SELECT 1", nil)
+	assert.Equal(t, grpc.ResourceExhausted, grpc.StatusOf(err).Code)
+
+	conn.err = fmt.Errorf("syntax error")
+	_, err = g.ExecuteQuery(context.Background(), "SELEC 1", nil)
+	assert.Equal(t, &grpc.Status{Code: grpc.Internal, Message: "failed to execute query: syntax error"}, grpc.StatusOf(err))
+}
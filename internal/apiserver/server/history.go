@@ -0,0 +1,464 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultHistoryEntries   = 10000
+	defaultHistoryRetention = 7 * 24 * time.Hour
+	defaultHistoryLimit     = 100
+	maxHistoryLimit         = 1000
+	defaultHistoryStats     = 20
+	historyPruneInterval    = time.Hour
+)
+
+// HistoryConfig records the queries the server executes. The history is
+// kept in the state store when one is configured, and in memory otherwise.
+type HistoryConfig struct {
+	// IncludeSQL stores the text of each query; by default only its hash
+	// is kept
+	IncludeSQL bool `json:"include_sql,omitempty"`
+
+	// Retention of the history in the state store (default: 168h)
+	Retention string `json:"retention,omitempty"`
+
+	// MaxEntries kept in memory without a state store (default: 10000)
+	MaxEntries int `json:"max_entries,omitempty"`
+}
+
+// HistoryEntry is one executed query
+type HistoryEntry struct {
+	Source      string    `json:"source"`
+	Fingerprint string    `json:"fingerprint"`
+	SQL         string    `json:"sql,omitempty"`
+	Caller      string    `json:"caller,omitempty"`
+	Client      string    `json:"client,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	DurationMs  int64     `json:"duration_ms"`
+	Rows        int       `json:"rows"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// HistoryFilter narrows down the history entries
+type HistoryFilter struct {
+	Source      string
+	Caller      string
+	Fingerprint string
+	From        time.Time
+	To          time.Time
+	FailedOnly  bool
+	Limit       int
+}
+
+// matches reports whether the entry satisfies the filter
+func (f HistoryFilter) matches(entry *HistoryEntry) bool {
+	return (f.Source == "" || f.Source == entry.Source) &&
+		(f.Caller == "" || f.Caller == entry.Caller) &&
+		(f.Fingerprint == "" || f.Fingerprint == entry.Fingerprint) &&
+		(f.From.IsZero() || !entry.StartedAt.Before(f.From)) &&
+		(f.To.IsZero() || entry.StartedAt.Before(f.To)) &&
+		(!f.FailedOnly || entry.Error != "")
+}
+
+// QueryStats aggregates the executions of the queries sharing a
+// fingerprint
+type QueryStats struct {
+	Fingerprint     string    `json:"fingerprint"`
+	SQL             string    `json:"sql,omitempty"`
+	Count           int       `json:"count"`
+	Errors          int       `json:"errors"`
+	TotalDurationMs int64     `json:"total_duration_ms"`
+	AvgDurationMs   float64   `json:"avg_duration_ms"`
+	MaxDurationMs   int64     `json:"max_duration_ms"`
+	TotalRows       int64     `json:"total_rows"`
+	Callers         []string  `json:"callers"`
+	LastRunAt       time.Time `json:"last_run_at"`
+}
+
+// historyStatsOrders are the orders of the aggregate view, most expensive
+// first
+var historyStatsOrders = map[string]func(a, b *QueryStats) bool{
+	"total_duration": func(a, b *QueryStats) bool { return a.TotalDurationMs > b.TotalDurationMs },
+	"avg_duration":   func(a, b *QueryStats) bool { return a.AvgDurationMs > b.AvgDurationMs },
+	"max_duration":   func(a, b *QueryStats) bool { return a.MaxDurationMs > b.MaxDurationMs },
+	"count":          func(a, b *QueryStats) bool { return a.Count > b.Count },
+	"rows":           func(a, b *QueryStats) bool { return a.TotalRows > b.TotalRows },
+	"errors":         func(a, b *QueryStats) bool { return a.Errors > b.Errors },
+}
+
+// historyRow is the persisted form of a HistoryEntry
+type historyRow struct {
+	ID          uint `gorm:"primaryKey;autoIncrement"`
+	Server      string
+	Source      string
+	Fingerprint string
+	SQL         string `gorm:"column:sql"`
+	Caller      string
+	Client      string
+	StartedAt   time.Time
+	DurationMs  int64
+	Rows        int
+	Error       string
+}
+
+// TableName overrides the table name used by historyRow
+func (historyRow) TableName() string {
+	return "query_history"
+}
+
+func (r *historyRow) entry() HistoryEntry {
+	return HistoryEntry{
+		Source:      r.Source,
+		Fingerprint: r.Fingerprint,
+		SQL:         r.SQL,
+		Caller:      r.Caller,
+		Client:      r.Client,
+		StartedAt:   r.StartedAt,
+		DurationMs:  r.DurationMs,
+		Rows:        r.Rows,
+		Error:       r.Error,
+	}
+}
+
+// queryHistory records executed queries in the state store, or the most
+// recent ones in memory when db is nil
+type queryHistory struct {
+	server     string
+	includeSQL bool
+	retention  time.Duration
+	db         *gorm.DB
+
+	mu         sync.RWMutex
+	entries    []HistoryEntry
+	maxEntries int
+}
+
+// newQueryHistory creates the query history of a configuration, which may
+// be nil
+func newQueryHistory(server string, cfg *HistoryConfig, db *gorm.DB) (*queryHistory, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	h := &queryHistory{
+		server:     server,
+		includeSQL: cfg.IncludeSQL,
+		retention:  defaultHistoryRetention,
+		db:         db,
+		maxEntries: defaultHistoryEntries,
+	}
+	if cfg.Retention != "" {
+		d, err := time.ParseDuration(cfg.Retention)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid retention: %s", cfg.Retention)
+		}
+		h.retention = d
+	}
+	if cfg.MaxEntries < 0 {
+		return nil, fmt.Errorf("max_entries must not be negative")
+	}
+	if cfg.MaxEntries > 0 {
+		h.maxEntries = cfg.MaxEntries
+	}
+	return h, nil
+}
+
+// queryFingerprint hashes a query, ignoring differences in whitespace
+func queryFingerprint(query string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(query), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// record stores an executed query
+func (h *queryHistory) record(ctx context.Context, entry HistoryEntry) error {
+	entry.Fingerprint = queryFingerprint(entry.SQL)
+	if !h.includeSQL {
+		entry.SQL = ""
+	}
+
+	if h.db == nil {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.entries = append(h.entries, entry)
+		if len(h.entries) > h.maxEntries {
+			h.entries = h.entries[len(h.entries)-h.maxEntries:]
+		}
+		return nil
+	}
+
+	row := &historyRow{
+		Server:      h.server,
+		Source:      entry.Source,
+		Fingerprint: entry.Fingerprint,
+		SQL:         entry.SQL,
+		Caller:      entry.Caller,
+		Client:      entry.Client,
+		StartedAt:   entry.StartedAt,
+		DurationMs:  entry.DurationMs,
+		Rows:        entry.Rows,
+		Error:       entry.Error,
+	}
+	if err := h.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to record query history: %w", err)
+	}
+	return nil
+}
+
+// query selects the persisted entries matching a filter
+func (h *queryHistory) query(ctx context.Context, filter HistoryFilter) *gorm.DB {
+	query := h.db.WithContext(ctx).Model(&historyRow{}).Where("server = ?", h.server)
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Caller != "" {
+		query = query.Where("caller = ?", filter.Caller)
+	}
+	if filter.Fingerprint != "" {
+		query = query.Where("fingerprint = ?", filter.Fingerprint)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("started_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("started_at < ?", filter.To)
+	}
+	if filter.FailedOnly {
+		query = query.Where("error <> ''")
+	}
+	return query
+}
+
+// list returns the entries matching a filter, newest first
+func (h *queryHistory) list(ctx context.Context, filter HistoryFilter) ([]HistoryEntry, error) {
+	entries := []HistoryEntry{}
+	if h.db == nil {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		for i := len(h.entries) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+			if filter.matches(&h.entries[i]) {
+				entries = append(entries, h.entries[i])
+			}
+		}
+		return entries, nil
+	}
+
+	var rows []historyRow
+	err := h.query(ctx, filter).Order("started_at DESC").Order("id DESC").Limit(filter.Limit).Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query history: %w", err)
+	}
+	for i := range rows {
+		entries = append(entries, rows[i].entry())
+	}
+	return entries, nil
+}
+
+// stats aggregates the entries matching a filter by fingerprint, returning
+// the first filter.Limit in the order
+func (h *queryHistory) stats(ctx context.Context, filter HistoryFilter, order string) ([]*QueryStats, error) {
+	byFingerprint := make(map[string]*QueryStats)
+	callers := make(map[string]map[string]bool)
+	add := func(entry *HistoryEntry) {
+		stats, ok := byFingerprint[entry.Fingerprint]
+		if !ok {
+			stats = &QueryStats{Fingerprint: entry.Fingerprint, Callers: []string{}}
+			byFingerprint[entry.Fingerprint] = stats
+			callers[entry.Fingerprint] = make(map[string]bool)
+		}
+		stats.Count++
+		if entry.Error != "" {
+			stats.Errors++
+		}
+		stats.TotalDurationMs += entry.DurationMs
+		stats.MaxDurationMs = max(stats.MaxDurationMs, entry.DurationMs)
+		stats.TotalRows += int64(entry.Rows)
+		if entry.StartedAt.After(stats.LastRunAt) {
+			stats.LastRunAt = entry.StartedAt
+		}
+		if entry.SQL != "" {
+			stats.SQL = entry.SQL
+		}
+		if entry.Caller != "" && !callers[entry.Fingerprint][entry.Caller] {
+			callers[entry.Fingerprint][entry.Caller] = true
+			stats.Callers = append(stats.Callers, entry.Caller)
+		}
+	}
+
+	if h.db == nil {
+		h.mu.RLock()
+		for i := range h.entries {
+			if filter.matches(&h.entries[i]) {
+				add(&h.entries[i])
+			}
+		}
+		h.mu.RUnlock()
+	} else {
+		var rows []historyRow
+		err := h.query(ctx, filter).FindInBatches(&rows, 1000, func(*gorm.DB, int) error {
+			for i := range rows {
+				entry := rows[i].entry()
+				add(&entry)
+			}
+			return nil
+		}).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to read query history: %w", err)
+		}
+	}
+
+	all := make([]*QueryStats, 0, len(byFingerprint))
+	for _, stats := range byFingerprint {
+		stats.AvgDurationMs = float64(stats.TotalDurationMs) / float64(stats.Count)
+		sort.Strings(stats.Callers)
+		all = append(all, stats)
+	}
+	less := historyStatsOrders[order]
+	sort.Slice(all, func(i, j int) bool {
+		if less(all[i], all[j]) != less(all[j], all[i]) {
+			return less(all[i], all[j])
+		}
+		return all[i].Fingerprint < all[j].Fingerprint
+	})
+	if len(all) > filter.Limit {
+		all = all[:filter.Limit]
+	}
+	return all, nil
+}
+
+// prune deletes the persisted entries older than the retention
+func (h *queryHistory) prune(ctx context.Context) error {
+	err := h.db.WithContext(ctx).Where("server = ? AND started_at < ?", h.server, time.Now().Add(-h.retention)).
+		Delete(&historyRow{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to prune query history: %w", err)
+	}
+	return nil
+}
+
+// recordHistory adds an executed query to the history, attributing it to
+// the caller of its query tag
+func (s *MCPServerWithDB) recordHistory(ctx context.Context, source, query string, started time.Time, rows int, err error) {
+	if s.history == nil {
+		return
+	}
+	tag := callerTag(ctx)
+	entry := HistoryEntry{
+		Source:     source,
+		SQL:        query,
+		Caller:     tag.Principal,
+		Client:     tag.Client,
+		StartedAt:  started.UTC(),
+		DurationMs: time.Since(started).Milliseconds(),
+		Rows:       rows,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	// The query may have been cancelled with its request
+	if err := s.history.record(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// runHistoryPruning deletes expired history entries periodically
+func (s *MCPServerWithDB) runHistoryPruning() {
+	ticker := time.NewTicker(historyPruneInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.history.prune(s.ctx); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// historyFilter parses the filter of a history request
+func historyFilter(c *gin.Context, defaultLimit int) (HistoryFilter, error) {
+	filter := HistoryFilter{
+		Source:      c.Query("source"),
+		Caller:      c.Query("caller"),
+		Fingerprint: c.Query("fingerprint"),
+		FailedOnly:  c.Query("failed") == "true",
+		Limit:       defaultLimit,
+	}
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from: %s", v)
+		}
+		filter.From = t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to: %s", v)
+		}
+		filter.To = t
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxHistoryLimit {
+			return filter, fmt.Errorf("invalid limit: %s (must be between 1 and %d)", v, maxHistoryLimit)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// setupHistoryRoutes configures the admin query history routes when the
+// history is enabled
+func (s *MCPServerWithDB) setupHistoryRoutes(router *gin.RouterGroup) {
+	if s.history == nil {
+		return
+	}
+
+	router.GET("/admin/history", func(c *gin.Context) {
+		filter, err := historyFilter(c, defaultHistoryLimit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		entries, err := s.history.list(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list query history: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, entries)
+	})
+
+	router.GET("/admin/history/stats", func(c *gin.Context) {
+		filter, err := historyFilter(c, defaultHistoryStats)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		order := c.DefaultQuery("order_by", "total_duration")
+		if _, ok := historyStatsOrders[order]; !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid order_by: %s", order)})
+			return
+		}
+		stats, err := s.history.stats(c.Request.Context(), filter, order)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to aggregate query history: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	})
+}
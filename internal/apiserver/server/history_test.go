@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+)
+
+func TestQueryHistory(t *testing.T) {
+	store, err := state.OpenAndMigrate(&state.Config{DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	defer store.Close()
+
+	history, err := newQueryHistory("sales", &HistoryConfig{IncludeSQL: true}, store.DB)
+	require.NoError(t, err)
+	conn := &rowsConnector{rows: []map[string]interface{}{{"ID": 1.0}, {"ID": 2.0}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, history: history}
+
+	jane := connector.WithQueryTag(context.Background(), s.queryTag("rest", "", "jane", "", ""))
+	bob := connector.WithQueryTag(context.Background(), s.queryTag("claude-desktop", "1.0", "bob", "", ""))
+	for _, ctx := range []context.Context{jane, jane, bob} {
+		_, err := s.executeQuery(ctx, "api", "SELECT * FROM ORDERS", nil)
+		require.NoError(t, err)
+	}
+	// Whitespace does not change the fingerprint
+	_, err = s.executeQuery(bob, "mcp", "SELECT *\n  FROM CUSTOMERS", nil)
+	require.NoError(t, err)
+	s.recordHistory(bob, "mcp", "SELECT * FROM CUSTOMERS", time.Now(), 0, errors.New("warehouse suspended"))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupHistoryRoutes(router.Group("/"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/history?caller=bob", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var entries []HistoryEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 3)
+	assert.Equal(t, "warehouse suspended", entries[0].Error)
+	assert.Equal(t, "claude-desktop", entries[2].Client)
+	assert.Equal(t, 2, entries[2].Rows)
+	assert.Equal(t, queryFingerprint("SELECT * FROM ORDERS"), entries[2].Fingerprint)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/history?source=mcp&failed=true", nil))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	assert.Len(t, entries, 1)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/history/stats?order_by=count", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stats []QueryStats
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	require.Len(t, stats, 2)
+	assert.Equal(t, "SELECT * FROM ORDERS", stats[0].SQL)
+	assert.Equal(t, 3, stats[0].Count)
+	assert.Equal(t, int64(6), stats[0].TotalRows)
+	assert.Equal(t, []string{"bob", "jane"}, stats[0].Callers)
+	assert.Equal(t, 2, stats[1].Count)
+	assert.Equal(t, 1, stats[1].Errors)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/history/stats?order_by=cost", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Without a state store the most recent queries are kept in memory,
+	// hashed only by default
+	memory, err := newQueryHistory("sales", &HistoryConfig{MaxEntries: 2}, nil)
+	require.NoError(t, err)
+	s.history = memory
+	for _, query := range []string{"SELECT 1", "SELECT 2", "SELECT 3"} {
+		_, err := s.executeQuery(jane, "api", query, nil)
+		require.NoError(t, err)
+	}
+	recent, err := memory.list(context.Background(), HistoryFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, recent, 2)
+	assert.Empty(t, recent[0].SQL)
+	assert.Equal(t, queryFingerprint("SELECT 3"), recent[0].Fingerprint)
+
+	_, err = newQueryHistory("sales", &HistoryConfig{Retention: "forever"}, nil)
+	assert.Error(t, err)
+}
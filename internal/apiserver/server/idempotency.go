@@ -0,0 +1,168 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idempotencyTTL is how long the response of a write is replayed to
+	// retries with its idempotency key
+	idempotencyTTL = 24 * time.Hour
+
+	// idempotentReplayedHeader marks a response replayed to a retry
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// replayedHeaders are the response headers replayed with the body
+var replayedHeaders = []string{"Content-Type", "Location", sandboxHeader}
+
+// idempotentResponse is the response of a write made with an idempotency
+// key, pending until its request completes
+type idempotentResponse struct {
+	hash    string
+	pending bool
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// idempotencyStore keeps the responses of writes by caller and idempotency
+// key, so that a retried request gets the original response instead of
+// writing twice
+type idempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]*idempotentResponse
+}
+
+func newIdempotencyStore() *idempotencyStore {
+	return &idempotencyStore{entries: make(map[string]*idempotentResponse)}
+}
+
+// begin returns the response stored for a key. A key seen for the first
+// time is reserved for the request, and nil is returned.
+func (st *idempotencyStore) begin(key, hash string) *idempotentResponse {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	now := time.Now()
+	for k, e := range st.entries {
+		if !e.pending && now.After(e.expires) {
+			delete(st.entries, k)
+		}
+	}
+	if e, ok := st.entries[key]; ok {
+		copied := *e
+		return &copied
+	}
+	st.entries[key] = &idempotentResponse{hash: hash, pending: true}
+	return nil
+}
+
+// finish stores the response of a reserved key. Server errors release the
+// key instead, so that the request may be retried.
+func (st *idempotencyStore) finish(key string, status int, header http.Header, body []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if status >= http.StatusInternalServerError {
+		delete(st.entries, key)
+		return
+	}
+	st.entries[key] = &idempotentResponse{
+		hash:    st.entries[key].hash,
+		status:  status,
+		header:  header,
+		body:    body,
+		expires: time.Now().Add(idempotencyTTL),
+	}
+}
+
+// recordingWriter keeps a copy of the response it writes
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// requestHash identifies a request by its method, path, query and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{r.Method, r.URL.Path, r.URL.RawQuery} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyMiddleware replays the response of a write to the retries
+// carrying its Idempotency-Key header. Keys are scoped to the caller; a key
+// reused for a different request, or while its request is running, is
+// rejected.
+func (s *MCPServerWithDB) idempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		write := c.Request.Method == http.MethodPost || c.Request.Method == http.MethodPut ||
+			c.Request.Method == http.MethodPatch || c.Request.Method == http.MethodDelete
+		if key == "" || !write || s.idempotency == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		scoped := principalFromContext(c) + "\x00" + key
+		hash := requestHash(c.Request, body)
+
+		if stored := s.idempotency.begin(scoped, hash); stored != nil {
+			switch {
+			case stored.pending:
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+			case stored.hash != hash:
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			default:
+				for name, values := range stored.header {
+					c.Writer.Header()[name] = values
+				}
+				c.Header(idempotentReplayedHeader, "true")
+				c.Writer.WriteHeader(stored.status)
+				_, _ = c.Writer.Write(stored.body)
+				c.Abort()
+			}
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			header := make(http.Header)
+			for _, name := range replayedHeaders {
+				if values := w.Header().Values(name); len(values) > 0 {
+					header[http.CanonicalHeaderKey(name)] = values
+				}
+			}
+			s.idempotency.finish(scoped, w.Status(), header, w.body.Bytes())
+		}()
+		c.Next()
+	}
+}
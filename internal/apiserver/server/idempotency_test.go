@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, idempotency: newIdempotencyStore()}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.idempotencyMiddleware())
+	inserts := 0
+	router.POST("/ORDERS", func(c *gin.Context) {
+		inserts++
+		if c.Query("fail") == "true" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "warehouse unavailable"})
+			return
+		}
+		c.Header(sandboxHeader, "ORDERS_SANDBOX")
+		c.JSON(http.StatusCreated, gin.H{"id": inserts})
+	})
+	post := func(target, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := post("/ORDERS", "order-1", `{"TOTAL": 10}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.JSONEq(t, `{"id": 1}`, first.Body.String())
+
+	// A retry gets the original response without inserting again
+	retry := post("/ORDERS", "order-1", `{"TOTAL": 10}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.JSONEq(t, `{"id": 1}`, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(idempotentReplayedHeader))
+	assert.Equal(t, "ORDERS_SANDBOX", retry.Header().Get(sandboxHeader))
+	assert.Equal(t, 1, inserts)
+
+	// The key cannot be reused for another request
+	assert.Equal(t, http.StatusUnprocessableEntity, post("/ORDERS", "order-1", `{"TOTAL": 20}`).Code)
+	assert.Equal(t, 1, inserts)
+
+	// Requests without a key always run
+	for i := 2; i <= 3; i++ {
+		assert.JSONEq(t, `{"id": `+strconv.Itoa(i)+`}`, post("/ORDERS", "", `{"TOTAL": 10}`).Body.String())
+	}
+
+	// Server errors are not replayed, so the request can be retried
+	assert.Equal(t, http.StatusServiceUnavailable, post("/ORDERS?fail=true", "order-2", `{}`).Code)
+	assert.Equal(t, http.StatusServiceUnavailable, post("/ORDERS?fail=true", "order-2", `{}`).Code)
+	assert.Equal(t, 5, inserts)
+
+	// A key is reserved while its request runs
+	s.idempotency.begin("anonymous@192.0.2.1\x00order-3", "pending")
+	assert.Equal(t, http.StatusConflict, post("/ORDERS", "order-3", `{}`).Code)
+}
@@ -0,0 +1,405 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultImportMaxRows        = 100000
+	defaultImportStageThreshold = 10000
+
+	// maxImportBatchRows bounds the rows of one INSERT, below the limit on
+	// bind parameters
+	maxImportBatchRows = 1000
+
+	// maxImportErrors is the most invalid rows described in a response
+	maxImportErrors = 100
+
+	importFormatCSV    = "csv"
+	importFormatNDJSON = "ndjson"
+
+	actionImport = "import"
+)
+
+// ErrInvalidImport is returned for import files that cannot be read
+var ErrInvalidImport = errors.New("invalid import")
+
+// ImportConfig enables loading CSV and NDJSON files into tables with
+// POST /tables/{table}/import. Uploads are bounded by the request body
+// limit as well.
+type ImportConfig struct {
+	// MaxRows is the most rows of an import file (default: 100000)
+	MaxRows int `json:"max_rows,omitempty"`
+
+	// StageThreshold is the number of rows from which imports are loaded
+	// through a stage, on connectors that can, rather than with batched
+	// inserts (default: 10000)
+	StageThreshold int `json:"stage_threshold,omitempty"`
+}
+
+// validate checks an import configuration
+func (cfg *ImportConfig) validate() error {
+	if cfg.MaxRows < 0 || cfg.StageThreshold < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
+
+// maxRows returns the configured row limit or its default
+func (cfg *ImportConfig) maxRows() int {
+	if cfg.MaxRows > 0 {
+		return cfg.MaxRows
+	}
+	return defaultImportMaxRows
+}
+
+// stageThreshold returns the configured staging threshold or its default
+func (cfg *ImportConfig) stageThreshold() int {
+	if cfg.StageThreshold > 0 {
+		return cfg.StageThreshold
+	}
+	return defaultImportStageThreshold
+}
+
+// ImportRowError describes why a row of an import file was rejected
+type ImportRowError struct {
+	// Row is the 1-based position of the row in the file, not counting the
+	// CSV header
+	Row    int          `json:"row"`
+	Fields []FieldError `json:"fields"`
+}
+
+// ImportResult describes a finished import
+type ImportResult struct {
+	Table string `json:"table"`
+
+	// Rows is the number of rows loaded
+	Rows int64 `json:"rows"`
+
+	// Invalid is the number of rows skipped for failing validation, the
+	// first of which are described by Errors
+	Invalid int              `json:"invalid"`
+	Errors  []ImportRowError `json:"errors,omitempty"`
+
+	// Method is how the rows were loaded: "insert" or "stage"
+	Method string `json:"method,omitempty"`
+}
+
+// importFile is a parsed import file: its records keyed by column, and the
+// columns they name in file order
+type importFile struct {
+	columns []string
+	records []map[string]interface{}
+}
+
+// setupImportRoutes configures the table import route when imports are
+// enabled
+func (s *MCPServerWithDB) setupImportRoutes(router *gin.RouterGroup) {
+	if s.Config.Imports == nil {
+		return
+	}
+	router.POST("/tables/:tableName/import", s.handleImportTable)
+}
+
+// handleImportTable loads an uploaded CSV or NDJSON file into a table.
+// Every row is validated against the table's columns first; unless
+// skip_invalid is set, any invalid row fails the import and the response
+// lists the rows rejected.
+func (s *MCPServerWithDB) handleImportTable(c *gin.Context) {
+	ctx := c.Request.Context()
+	tableName := c.Param("tableName")
+
+	// Only tables from the catalog are interpolated into the statements
+	if err := s.checkTableName(ctx, tableName); err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to import table", err)
+		return
+	}
+	if err := s.checkTableAccess(ctx, tableName); err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to import table", err)
+		return
+	}
+	skipInvalid, _ := strconv.ParseBool(c.Query("skip_invalid"))
+
+	upload, header, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: a file is required: %v", err)})
+		return
+	}
+	defer upload.Close()
+	format, err := importFormat(c.Query("format"), header.Filename)
+	if err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to import table", err)
+		return
+	}
+
+	metadata, err := s.DBConn.GetTableMetadata(ctx, tableName)
+	if err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to import table", err)
+		return
+	}
+	columns := connector.InsertColumns(connector.DialectOf(s.DBConn), metadata.Columns)
+
+	file, err := readImportFile(upload, format, columns, s.Config.Imports.maxRows())
+	if err != nil {
+		s.respondError(c, queryErrorStatus(err), "Failed to import table", err)
+		return
+	}
+
+	// Rows bind every column of the file; ones a row leaves out are NULL
+	result := &ImportResult{Table: tableName}
+	rows := make([][]interface{}, 0, len(file.records))
+	for i, record := range file.records {
+		for _, col := range file.columns {
+			if _, ok := record[col]; !ok {
+				record[col] = nil
+			}
+		}
+		params, fieldErrs := validateBody(columns, record, true)
+		if len(fieldErrs) > 0 {
+			result.Invalid++
+			if len(result.Errors) < maxImportErrors {
+				result.Errors = append(result.Errors, ImportRowError{Row: i + 1, Fields: fieldErrs})
+			}
+			continue
+		}
+		values := make([]interface{}, len(file.columns))
+		for j, col := range file.columns {
+			values[j] = params[connector.ParamName(col)]
+		}
+		rows = append(rows, values)
+	}
+	if result.Invalid > 0 && !skipInvalid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   fmt.Sprintf("Invalid import: %d of %d rows are invalid; no rows were loaded", result.Invalid, len(file.records)),
+			"code":    CodeInvalidRequest,
+			"invalid": result.Invalid,
+			"rows":    result.Errors,
+		})
+		return
+	}
+
+	if len(rows) > 0 {
+		if result.Rows, result.Method, err = s.importRows(ctx, tableName, file.columns, rows); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to import table", err)
+			return
+		}
+	}
+
+	if s.Audit != nil {
+		if err := s.Audit.Record(ctx, &audit.Event{
+			Action:    actionImport,
+			Principal: principalFromContext(c),
+			Resource:  tableName,
+			Details: map[string]interface{}{
+				"format":  format,
+				"file":    header.Filename,
+				"rows":    result.Rows,
+				"invalid": result.Invalid,
+				"method":  result.Method,
+			},
+		}); err != nil {
+			log.Printf("Warning: Failed to record import audit event: %v", err)
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// importFormat returns the format of an import file, given explicitly or
+// by its extension
+func importFormat(format, filename string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(path.Ext(filename)) {
+		case ".csv":
+			format = importFormatCSV
+		case ".ndjson", ".jsonl":
+			format = importFormatNDJSON
+		}
+	}
+	switch strings.ToLower(format) {
+	case importFormatCSV, importFormatNDJSON:
+		return strings.ToLower(format), nil
+	case "":
+		return "", fmt.Errorf("%w: format is required for %q", ErrInvalidImport, filename)
+	}
+	return "", fmt.Errorf("%w: unsupported format %s", ErrInvalidImport, format)
+}
+
+// readImportFile parses an import file of at most maxRows rows. CSV files
+// start with a header row of column names; their values are converted to
+// the JSON types of the columns, with empty values read as NULL.
+func readImportFile(r io.Reader, format string, columns []connector.Column, maxRows int) (*importFile, error) {
+	file := &importFile{}
+	switch format {
+	case importFormatCSV:
+		reader := csv.NewReader(r)
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read CSV header: %v", ErrInvalidImport, err)
+		}
+		types := make(map[string]string, len(columns))
+		for _, col := range columns {
+			types[col.Name], _ = jsonSchemaType(col.Type)
+		}
+		seen := make(map[string]bool, len(header))
+		for _, name := range header {
+			if seen[name] {
+				return nil, fmt.Errorf("%w: column %s appears twice in the CSV header", ErrInvalidImport, name)
+			}
+			seen[name] = true
+		}
+		file.columns = header
+
+		for {
+			values, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+			}
+			if len(file.records) == maxRows {
+				return nil, fmt.Errorf("%w: file has more than %d rows", ErrLimitExceeded, maxRows)
+			}
+			record := make(map[string]interface{}, len(header))
+			for i, name := range header {
+				record[name] = csvImportValue(values[i], types[name])
+			}
+			file.records = append(file.records, record)
+		}
+	case importFormatNDJSON:
+		dec := json.NewDecoder(r)
+		dec.UseNumber()
+		seen := make(map[string]bool)
+		for line := 1; ; line++ {
+			var record map[string]interface{}
+			err := dec.Decode(&record)
+			if err == io.EOF {
+				break
+			}
+			if err != nil || record == nil {
+				return nil, fmt.Errorf("%w: row %d is not a JSON object", ErrInvalidImport, line)
+			}
+			if len(file.records) == maxRows {
+				return nil, fmt.Errorf("%w: file has more than %d rows", ErrLimitExceeded, maxRows)
+			}
+			for name := range record {
+				seen[name] = true
+			}
+			file.records = append(file.records, record)
+		}
+		// Rows bind the columns any of them names, in table order; unknown
+		// ones are reported by validation
+		for _, col := range columns {
+			if seen[col.Name] {
+				file.columns = append(file.columns, col.Name)
+			}
+		}
+	}
+	return file, nil
+}
+
+// csvImportValue converts a CSV value to the JSON type of its column, so
+// it validates like the values of request bodies. Values that do not
+// convert are left as strings to fail validation.
+func csvImportValue(value, jsonType string) interface{} {
+	if value == "" {
+		return nil
+	}
+	switch jsonType {
+	case "integer", "number":
+		return json.Number(value)
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "":
+		dec := json.NewDecoder(strings.NewReader(value))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err == nil && !dec.More() {
+			return v
+		}
+	}
+	return value
+}
+
+// importRows loads validated rows into a table, through a stage when the
+// connector supports it and the import is large, and otherwise with
+// batched inserts in one transaction where the connector supports them.
+// It returns the rows loaded and the method used.
+func (s *MCPServerWithDB) importRows(ctx context.Context, table string, columns []string, rows [][]interface{}) (int64, string, error) {
+	conn, err := s.selectedConn(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+	if loader, ok := conn.(connector.StageLoader); ok && len(rows) >= s.Config.Imports.stageThreshold() {
+		n, err := loader.LoadRows(ctx, table, columns, rows)
+		return n, "stage", err
+	}
+
+	batchRows := maxImportBatchRows
+	if perRow := s.limits.maxParams / len(columns); perRow > 0 && perRow < batchRows {
+		batchRows = perRow
+	}
+	d := connector.DialectOf(conn)
+
+	exec := func(query string, params map[string]interface{}) error {
+		_, err := s.executeOrdered(ctx, actionImport, query, params)
+		return err
+	}
+	commit := func() error { return nil }
+	if transactor, ok := conn.(connector.Transactor); ok {
+		tx, err := transactor.BeginTransaction(ctx)
+		if err != nil {
+			return 0, "", err
+		}
+		committed := false
+		defer func() {
+			if !committed {
+				if err := tx.Rollback(); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			}
+		}()
+		exec = func(query string, params map[string]interface{}) error {
+			_, err := tx.ExecuteQuery(ctx, query, params)
+			return err
+		}
+		commit = func() error {
+			committed = true
+			return tx.Commit()
+		}
+	}
+
+	for start := 0; start < len(rows); start += batchRows {
+		batch := rows[start:min(start+batchRows, len(rows))]
+		params := make(map[string]interface{}, len(batch)*len(columns))
+		for r, values := range batch {
+			for i, col := range columns {
+				params[connector.RowParamName(r, col)] = values[i]
+			}
+		}
+		if err := exec(connector.InsertRowsQuery(d, table, columns, len(batch)), params); err != nil {
+			return 0, "", fmt.Errorf("rows %d-%d: %w", start+1, start+len(batch), err)
+		}
+	}
+	if err := commit(); err != nil {
+		return 0, "", err
+	}
+	return int64(len(rows)), "insert", nil
+}
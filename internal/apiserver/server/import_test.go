@@ -0,0 +1,131 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// importConnector records the statements loading a CUSTOMERS table
+type importConnector struct {
+	rowsConnector
+	queries []string
+	params  []map[string]interface{}
+}
+
+func (c *importConnector) ListTables(context.Context) ([]connector.Table, error) {
+	return []connector.Table{{Name: "CUSTOMERS"}}, nil
+}
+
+func (c *importConnector) GetTableMetadata(context.Context, string) (*connector.TableMetadata, error) {
+	return &connector.TableMetadata{Name: "CUSTOMERS", Columns: []connector.Column{
+		{Name: "ID", Type: "INTEGER"},
+		{Name: "NAME", Type: "VARCHAR", MaxLength: 10},
+		{Name: "ACTIVE", Type: "BOOLEAN", Nullable: true},
+	}}, nil
+}
+
+func (c *importConnector) ExecuteQuery(_ context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.queries, c.params = append(c.queries, query), append(c.params, params)
+	return nil, nil
+}
+
+// stageConnector loads imports through a stage
+type stageConnector struct {
+	importConnector
+	staged [][]interface{}
+}
+
+func (c *stageConnector) LoadRows(_ context.Context, _ string, _ []string, rows [][]interface{}) (int64, error) {
+	c.staged = rows
+	return int64(len(rows)), nil
+}
+
+func TestImportTable(t *testing.T) {
+	conn := &importConnector{}
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", Imports: &ImportConfig{}},
+		DBConn: conn,
+		limits: requestLimits{maxParams: 6},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupImportRoutes(router.Group("/"))
+	upload := func(filename, content, query string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte(content))
+		require.NoError(t, form.Close())
+		req := httptest.NewRequest(http.MethodPost, "/tables/CUSTOMERS/import"+query, &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Rows are inserted in batches bounded by the parameter limit
+	w := upload("customers.csv", "ID,NAME,ACTIVE\n1,Acme,true\n2,Globex,\n3,Initech,false\n", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result ImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, ImportResult{Table: "CUSTOMERS", Rows: 3, Method: "insert"}, result)
+	require.Len(t, conn.queries, 2)
+	assert.Equal(t, `INSERT INTO "CUSTOMERS" ("ID", "NAME", "ACTIVE") VALUES (:r0_ID, :r0_NAME, :r0_ACTIVE), (:r1_ID, :r1_NAME, :r1_ACTIVE)`, conn.queries[0])
+	assert.Equal(t, map[string]interface{}{"r0_ID": int64(1), "r0_NAME": "Acme", "r0_ACTIVE": true, "r1_ID": int64(2), "r1_NAME": "Globex", "r1_ACTIVE": nil}, conn.params[0])
+	assert.Equal(t, map[string]interface{}{"r0_ID": int64(3), "r0_NAME": "Initech", "r0_ACTIVE": false}, conn.params[1])
+
+	// Invalid rows fail the import and are reported
+	conn.queries = nil
+	ndjson := `{"ID": 4, "NAME": "Umbrella"}` + "\n" + `{"ID": "five", "NAME": "Hooli"}` + "\n" + `{"ID": 6, "NAME": "Massive Dynamic"}` + "\n"
+	w = upload("customers.ndjson", ndjson, "")
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	var report struct {
+		Invalid int              `json:"invalid"`
+		Rows    []ImportRowError `json:"rows"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 2, report.Invalid)
+	assert.Equal(t, []ImportRowError{
+		{Row: 2, Fields: []FieldError{{Field: "ID", Message: "must be an integer, got string"}}},
+		{Row: 3, Fields: []FieldError{{Field: "NAME", Message: "must be at most 10 characters, got 15"}}},
+	}, report.Rows)
+	assert.Empty(t, conn.queries)
+
+	// ... unless they are skipped
+	w = upload("customers.ndjson", ndjson, "?skip_invalid=true")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, int64(1), result.Rows)
+	assert.Equal(t, 2, result.Invalid)
+	assert.Equal(t, []string{`INSERT INTO "CUSTOMERS" ("ID", "NAME") VALUES (:r0_ID, :r0_NAME)`}, conn.queries)
+
+	// Large imports are staged by connectors that can
+	stage := &stageConnector{}
+	s.DBConn, s.Config.Imports.StageThreshold = stage, 2
+	w = upload("customers.csv", "ID,NAME\n1,Acme\n2,Globex\n", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"method":"stage"`)
+	assert.Equal(t, [][]interface{}{{int64(1), "Acme"}, {int64(2), "Globex"}}, stage.staged)
+	assert.Empty(t, stage.queries)
+
+	for _, bad := range []struct{ filename, content string }{
+		{"customers.txt", "ID\n1\n"},
+		{"customers.csv", "ID,ID\n1,2\n"},
+		{"customers.ndjson", "[1]\n"},
+	} {
+		w = upload(bad.filename, bad.content, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, bad.filename)
+	}
+}
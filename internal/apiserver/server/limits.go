@@ -0,0 +1,104 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultMaxBodyBytes = 4 << 20
+	defaultMaxParams    = 2000
+	defaultMaxInList    = 1000
+)
+
+// ErrLimitExceeded is returned for queries binding more parameters than
+// the server's limits allow
+var ErrLimitExceeded = errors.New("request limit exceeded")
+
+// LimitsConfig bounds the size of requests so that abusive payloads reach
+// neither the gateway nor the database
+type LimitsConfig struct {
+	// MaxBodyBytes is the largest request body accepted (default: 4 MiB)
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+
+	// MaxParams is the most bind parameters of a query, counting each
+	// element of an expanded IN list (default: 2000)
+	MaxParams int `json:"max_params,omitempty"`
+
+	// MaxInList is the most elements of a list parameter expanded into an
+	// IN list (default: 1000)
+	MaxInList int `json:"max_in_list,omitempty"`
+}
+
+// requestLimits are the limits of a LimitsConfig with defaults applied
+type requestLimits struct {
+	maxBodyBytes int64
+	maxParams    int
+	maxInList    int
+}
+
+// newRequestLimits applies the defaults to a configuration, which may be nil
+func newRequestLimits(cfg *LimitsConfig) (requestLimits, error) {
+	l := requestLimits{maxBodyBytes: defaultMaxBodyBytes, maxParams: defaultMaxParams, maxInList: defaultMaxInList}
+	if cfg == nil {
+		return l, nil
+	}
+	if cfg.MaxBodyBytes < 0 || cfg.MaxParams < 0 || cfg.MaxInList < 0 {
+		return l, fmt.Errorf("limits must not be negative")
+	}
+	if cfg.MaxBodyBytes > 0 {
+		l.maxBodyBytes = cfg.MaxBodyBytes
+	}
+	if cfg.MaxParams > 0 {
+		l.maxParams = cfg.MaxParams
+	}
+	if cfg.MaxInList > 0 {
+		l.maxInList = cfg.MaxInList
+	}
+	return l, nil
+}
+
+// checkParams rejects query parameters that bind too many values, counting
+// list parameters by the elements they expand to
+func (l requestLimits) checkParams(params map[string]interface{}) error {
+	count := 0
+	for name, v := range params {
+		n := 1
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			n = rv.Len()
+			if l.maxInList > 0 && n > l.maxInList {
+				return fmt.Errorf("%w: parameter %s lists %d values, more than %d", ErrLimitExceeded, name, n, l.maxInList)
+			}
+		}
+		count += n
+	}
+	if l.maxParams > 0 && count > l.maxParams {
+		return fmt.Errorf("%w: query binds %d values, more than %d", ErrLimitExceeded, count, l.maxParams)
+	}
+	return nil
+}
+
+// bodyLimitMiddleware rejects request bodies larger than the limit
+func (s *MCPServerWithDB) bodyLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := s.limits.maxBodyBytes
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+		if c.Request.ContentLength > limit {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Request body exceeds %d bytes", limit),
+				"code":  CodeLimitExceeded,
+			})
+			return
+		}
+		// Bodies of unknown length fail to read past the limit
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLimits(t *testing.T) {
+	limits, err := newRequestLimits(&LimitsConfig{MaxBodyBytes: 16, MaxParams: 4, MaxInList: 3})
+	require.NoError(t, err)
+
+	assert.NoError(t, limits.checkParams(map[string]interface{}{"ids": []interface{}{1, 2, 3}, "status": "open"}))
+	assert.ErrorIs(t, limits.checkParams(map[string]interface{}{"ids": []interface{}{1, 2, 3, 4}}), ErrLimitExceeded)
+	assert.ErrorIs(t, limits.checkParams(map[string]interface{}{"ids": []int{1, 2, 3}, "a": 1, "b": 2}), ErrLimitExceeded)
+
+	// Queries over the limits never reach the database
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, limits: limits}
+	_, err = s.executeQuery(context.Background(), "rest", "SELECT * FROM ORDERS WHERE ID IN (:ids)", map[string]interface{}{"ids": []interface{}{1, 2, 3, 4}})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Empty(t, conn.query)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.bodyLimitMiddleware())
+	router.POST("/query", func(c *gin.Context) {
+		if _, err := decodeBody(c.Request.Body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"a": 1}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query": "SELECT * FROM ORDERS"}`)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), CodeLimitExceeded)
+
+	// Bodies of unknown length are cut off at the limit
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(`{"query": "SELECT * FROM ORDERS"}`))
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "too large")
+}
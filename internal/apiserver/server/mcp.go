@@ -0,0 +1,514 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/provenance"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/version"
+)
+
+// mcpSession holds the state of an MCP client session
+type mcpSession struct {
+	ID         string
+	CreatedAt  time.Time
+	ClientInfo mcp.ImplementationSchema
+
+	// Principal is the authenticated caller that initialized the session
+	Principal string
+
+	// APIKey is the ID of the tenant API key that initialized the session
+	APIKey string
+
+	mu            sync.Mutex
+	subscriptions map[string]bool              // subscribed resource URIs
+	stream        chan mcp.JSONRPCNotification // open notification stream
+}
+
+// mcpToolHandler executes an MCP tool call
+type mcpToolHandler func(ctx context.Context, session *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error)
+
+// mcpTool binds an MCP tool schema to its handler
+type mcpTool struct {
+	Schema  mcp.ToolSchema
+	Handler mcpToolHandler
+
+	// Source names where the tool comes from, for conflict reports; empty
+	// for the server's own database tools
+	Source string
+}
+
+// mcpSessionStore keeps the active MCP sessions in memory
+type mcpSessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*mcpSession
+}
+
+func newMCPSessionStore() *mcpSessionStore {
+	return &mcpSessionStore{
+		sessions: make(map[string]*mcpSession),
+	}
+}
+
+func (st *mcpSessionStore) create(clientInfo mcp.ImplementationSchema, principal, apiKey string) *mcpSession {
+	sess := &mcpSession{
+		ID:         uuid.New().String(),
+		CreatedAt:  time.Now(),
+		ClientInfo: clientInfo,
+		Principal:  principal,
+		APIKey:     apiKey,
+	}
+	st.mu.Lock()
+	st.sessions[sess.ID] = sess
+	st.mu.Unlock()
+	return sess
+}
+
+func (st *mcpSessionStore) get(id string) (*mcpSession, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	sess, ok := st.sessions[id]
+	return sess, ok
+}
+
+func (st *mcpSessionStore) remove(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	_, ok := st.sessions[id]
+	delete(st.sessions, id)
+	return ok
+}
+
+// setupMCPRoutes exposes the database as an MCP server over streamable HTTP
+func (s *MCPServerWithDB) setupMCPRoutes(router *gin.RouterGroup) {
+	router.POST("/mcp", s.requireClientCert(), s.handleMCPPost)
+	router.GET("/mcp", s.requireClientCert(), s.handleMCPGet)
+
+	router.DELETE("/mcp", s.requireClientCert(), func(c *gin.Context) {
+		sessionID := c.GetHeader(mcp.HeaderMcpSessionID)
+		if !s.mcpSessions.remove(sessionID) {
+			sendMCPError(c, nil, "Invalid Request: Session not found", http.StatusNotFound, mcp.ErrorCodeInvalidRequest)
+			return
+		}
+		s.Provenance.Forget(sessionID)
+		c.Status(http.StatusOK)
+	})
+
+	router.GET("/admin/sessions/:sessionId/trace", func(c *gin.Context) {
+		trace, ok := s.Provenance.Trace(c.Param("sessionId"))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session trace not found"})
+			return
+		}
+		c.JSON(http.StatusOK, trace)
+	})
+}
+
+// handleMCPPost handles a single JSON-RPC message
+func (s *MCPServerWithDB) handleMCPPost(c *gin.Context) {
+	var req mcp.JSONRPCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendMCPError(c, nil, "Invalid JSON-RPC request", http.StatusBadRequest, mcp.ErrorCodeParseError)
+		return
+	}
+
+	if req.Method == mcp.Initialize {
+		var params mcp.InitializeRequestParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendMCPError(c, req.Id, fmt.Sprintf("invalid initialize parameters: %v", err), http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+
+		sess := s.mcpSessions.create(params.ClientInfo, principalFromContext(c), apiKeyFromContext(c))
+		c.Header(mcp.HeaderMcpSessionID, sess.ID)
+		sendMCPResult(c, req.Id, mcp.InitializedResult{
+			ProtocolVersion: mcp.LatestProtocolVersion,
+			Capabilities: mcp.ServerCapabilitiesSchema{
+				Tools:     mcp.ToolsCapabilitySchema{ListChanged: true},
+				Resources: mcp.ResourcesCapabilitySchema{Subscribe: true, ListChanged: true},
+				Prompts:   mcp.PromptsCapabilitySchema{},
+			},
+			ServerInfo: mcp.ImplementationSchema{
+				Name:    s.Config.Name,
+				Version: version.Get(),
+			},
+		})
+		return
+	}
+
+	sess, ok := s.mcpSessions.get(c.GetHeader(mcp.HeaderMcpSessionID))
+	if !ok {
+		sendMCPError(c, req.Id, "Invalid Request: Session not found", http.StatusBadRequest, mcp.ErrorCodeInvalidRequest)
+		return
+	}
+
+	switch req.Method {
+	case mcp.NotificationInitialized:
+		c.Status(http.StatusAccepted)
+	case mcp.Ping:
+		sendMCPResult(c, req.Id, struct{}{})
+	case mcp.ToolsList:
+		tools, conflicts, err := s.mcpTools(c.Request.Context())
+		if err != nil {
+			sendMCPError(c, req.Id, err.Error(), http.StatusOK, mcp.ErrorCodeInternalError)
+			return
+		}
+		logToolConflicts(conflicts)
+		schemas := make([]mcp.ToolSchema, 0, len(tools))
+		for _, tool := range tools {
+			schemas = append(schemas, tool.Schema)
+		}
+		sendMCPResult(c, req.Id, mcp.ListToolsResult{Tools: schemas})
+	case mcp.ToolsCall:
+		var params mcp.CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendMCPError(c, req.Id, fmt.Sprintf("invalid tool call parameters: %v", err), http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		ctx := connector.WithQueryTag(c.Request.Context(), s.queryTag(sess.ClientInfo.Name, sess.ClientInfo.Version, sess.Principal, sess.APIKey, sess.ID))
+		sendMCPResult(c, req.Id, s.callMCPTool(ctx, sess, params))
+	case mcp.PromptsList:
+		sendMCPResult(c, req.Id, mcp.ListPromptsResult{Prompts: mcpPrompts()})
+	case mcp.PromptsGet:
+		var params mcp.GetPromptParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendMCPError(c, req.Id, fmt.Sprintf("invalid prompt parameters: %v", err), http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		result, err := s.getMCPPrompt(c.Request.Context(), params)
+		if err != nil {
+			sendMCPError(c, req.Id, err.Error(), http.StatusOK, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		sendMCPResult(c, req.Id, result)
+	case mcp.ResourcesList:
+		resources := make([]mcp.ResourceSchema, 0)
+		for _, r := range s.results.list() {
+			resources = append(resources, mcp.ResourceSchema{
+				URI:         r.URI(),
+				Name:        fmt.Sprintf("%s result", r.Tool),
+				Description: fmt.Sprintf("Result of %d rows created at %s", len(r.Rows), r.CreatedAt.Format(time.RFC3339)),
+				MimeType:    "application/json",
+			})
+		}
+		resources = append(resources, s.scheduledResources()...)
+		resources = append(resources, s.changeResources()...)
+		resources = append(resources, s.schemaResources()...)
+		resources = append(resources, s.upstreamResources(c.Request.Context())...)
+		sendMCPResult(c, req.Id, mcp.ListResourcesResult{Resources: resources})
+	case mcp.ResourcesRead:
+		var params mcp.ReadResourceParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sendMCPError(c, req.Id, fmt.Sprintf("invalid resource read parameters: %v", err), http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		if scheduledResult, ok, err := s.readScheduledResource(params.URI); ok {
+			if err != nil {
+				sendMCPError(c, req.Id, err.Error(), http.StatusOK, mcp.ErrorCodeInvalidParams)
+				return
+			}
+			sendMCPResult(c, req.Id, scheduledResult)
+			return
+		}
+		if changeResult, ok, err := s.readChangeResource(c.Request.Context(), params.URI); ok {
+			if err != nil {
+				sendMCPError(c, req.Id, err.Error(), http.StatusOK, mcp.ErrorCodeInvalidParams)
+				return
+			}
+			sendMCPResult(c, req.Id, changeResult)
+			return
+		}
+		if schemaResult, ok, err := s.readSchemaResource(c.Request.Context(), params.URI); ok {
+			if err != nil {
+				sendMCPError(c, req.Id, err.Error(), http.StatusOK, mcp.ErrorCodeInvalidParams)
+				return
+			}
+			sendMCPResult(c, req.Id, schemaResult)
+			return
+		}
+		if upstreamResult, ok, err := s.readUpstreamResource(c.Request.Context(), params.URI); ok {
+			if err != nil {
+				sendMCPError(c, req.Id, err.Error(), http.StatusOK, mcp.ErrorCodeInternalError)
+				return
+			}
+			sendMCPResult(c, req.Id, upstreamResult)
+			return
+		}
+		result, ok := s.results.get(params.URI)
+		if !ok {
+			sendMCPError(c, req.Id, fmt.Sprintf("resource not found: %s", params.URI), http.StatusOK, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		data, err := json.Marshal(&connector.ResultSet{Columns: result.Columns, Rows: result.Rows})
+		if err != nil {
+			sendMCPError(c, req.Id, "failed to encode resource", http.StatusOK, mcp.ErrorCodeInternalError)
+			return
+		}
+		sendMCPResult(c, req.Id, mcp.ReadResourceResult{
+			Contents: []mcp.ResourceContents{
+				{URI: result.URI(), MimeType: "application/json", Text: string(data)},
+			},
+		})
+	case mcp.ResourcesSubscribe, mcp.ResourcesUnsubscribe:
+		var params mcp.SubscribeResourceParams
+		if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+			sendMCPError(c, req.Id, "invalid resource subscription parameters", http.StatusBadRequest, mcp.ErrorCodeInvalidParams)
+			return
+		}
+		if req.Method == mcp.ResourcesUnsubscribe {
+			sess.unsubscribe(params.URI)
+		} else {
+			sess.subscribe(params.URI)
+		}
+		sendMCPResult(c, req.Id, struct{}{})
+	default:
+		sendMCPError(c, req.Id, fmt.Sprintf("method not found: %s", req.Method), http.StatusOK, mcp.ErrorCodeMethodNotFound)
+	}
+}
+
+// callMCPTool runs a tool, recording the call in the session's provenance graph
+func (s *MCPServerWithDB) callMCPTool(ctx context.Context, sess *mcpSession, params mcp.CallToolParams) *mcp.CallToolResult {
+	tools, _, err := s.mcpTools(ctx)
+	if err != nil {
+		return mcp.NewCallToolResultError(fmt.Sprintf("Error: %s", err.Error()))
+	}
+	var tool *mcpTool
+	for _, t := range tools {
+		if t.Schema.Name == params.Name {
+			tool = &t
+			break
+		}
+	}
+	if tool == nil {
+		return mcp.NewCallToolResultError(fmt.Sprintf("Error: unknown tool %s", params.Name))
+	}
+
+	args := make(map[string]interface{})
+	if len(params.Arguments) > 0 {
+		if err := json.Unmarshal(params.Arguments, &args); err != nil {
+			return mcp.NewCallToolResultError(fmt.Sprintf("Error: invalid arguments: %v", err))
+		}
+	}
+
+	ctx = provenance.WithSession(ctx, sess.ID)
+	ctx, node := s.Provenance.Start(ctx, provenance.KindToolCall, params.Name, map[string]interface{}{
+		"arguments": args,
+	})
+	started := time.Now()
+	result, err := tool.Handler(ctx, sess, args)
+	s.usage.record(UsageKindTool, params.Name, time.Since(started), err != nil || (result != nil && result.IsError))
+	s.Provenance.Finish(node, err, nil)
+	if err != nil {
+		return mcp.NewCallToolResultError(fmt.Sprintf("Error: %s", err.Error()))
+	}
+	return result
+}
+
+// mcpTools returns the tools exposed by the server under their final names,
+// along with the name conflicts that were resolved
+func (s *MCPServerWithDB) mcpTools(ctx context.Context) ([]mcpTool, []ToolConflict, error) {
+	return s.toolNaming().resolveToolNames(s.namespacedTools(ctx))
+}
+
+// namespacedTools returns the server's own tools under its prefix followed
+// by the upstream tools under their namespaces
+func (s *MCPServerWithDB) namespacedTools(ctx context.Context) []mcpTool {
+	tools := append(s.builtinMCPTools(), s.baselineTools()...)
+	if s.tableSearch != nil {
+		tools = append(tools, s.searchTablesTool())
+	}
+	tools = append(tools, s.relatedTablesTool(), s.assertTableTool())
+	if s.approvals != nil {
+		tools = append(tools, s.actionStatusTool())
+	}
+	if s.llm != nil {
+		tools = append(tools, s.askTool())
+	}
+	tools = append(tools, s.tableTools(ctx)...)
+	tools = append(tools, s.savedQueryTools()...)
+	tools = append(tools, s.recipeTools()...)
+	tools = append(tools, s.routineTools(ctx)...)
+
+	naming := s.toolNaming()
+	for i := range tools {
+		tools[i].Schema.Name = naming.namespaced(naming.Prefix, tools[i].Schema.Name)
+	}
+	return append(tools, s.upstreamTools(ctx)...)
+}
+
+// builtinMCPTools returns the tools every database server exposes
+func (s *MCPServerWithDB) builtinMCPTools() []mcpTool {
+	queryProperties := map[string]any{
+		"sql":      map[string]any{"type": "string", "description": "SQL query with :name placeholders"},
+		"params":   map[string]any{"type": "object", "description": "Values for the named placeholders"},
+		"question": map[string]any{"type": "string", "description": "Natural-language question the query answers"},
+	}
+	if s.connections != nil {
+		labels := make([]string, 0, len(s.connections.order))
+		for _, l := range s.connections.order {
+			labels = append(labels, l.label)
+		}
+		queryProperties["connection"] = map[string]any{"type": "string", "enum": labels, "description": "Label of the connection to run the query on; the primary database when omitted"}
+	}
+
+	return []mcpTool{
+		{
+			Schema: mcp.ToolSchema{
+				Name:        "list_tables",
+				Description: "List all available tables with their row counts",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: map[string]any{},
+				},
+			},
+			Handler: func(ctx context.Context, sess *mcpSession, _ map[string]interface{}) (*mcp.CallToolResult, error) {
+				tables, err := s.DBConn.ListTables(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tables: %w", err)
+				}
+				rows := make([]map[string]interface{}, 0, len(tables))
+				for _, t := range tables {
+					rows = append(rows, map[string]interface{}{"name": t.Name, "row_count": t.RowCount})
+				}
+				return s.rowsToolResult("list_tables", sess, &connector.ResultSet{Columns: []string{"name", "row_count"}, Rows: rows})
+			},
+		},
+		{
+			Schema: mcp.ToolSchema{
+				Name:        "describe_table",
+				Description: "Get the columns, row count and sample data of a table",
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"table": map[string]any{"type": "string", "description": "Name of the table"},
+					},
+					Required: []string{"table"},
+				},
+			},
+			Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				table, _ := args["table"].(string)
+				if table == "" {
+					return nil, fmt.Errorf("table is required")
+				}
+				metadata, err := s.tableMetadata(ctx, table)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get table metadata: %w", err)
+				}
+				return jsonToolResult(s.redactSamples(ctx, s.withCatalog(s.withComputedColumns(metadata))))
+			},
+		},
+		{
+			Schema: mcp.ToolSchema{
+				Name:        "query",
+				Description: "Execute a SQL query. Pass the natural-language question being answered so the session trace shows how the answer was produced.",
+				InputSchema: mcp.ToolInputSchema{
+					Type:       "object",
+					Properties: queryProperties,
+					Required:   []string{"sql"},
+				},
+			},
+			Handler: func(ctx context.Context, sess *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				query, _ := args["sql"].(string)
+				if query == "" {
+					return nil, fmt.Errorf("sql is required")
+				}
+				params, _ := args["params"].(map[string]interface{})
+				if err := s.checkFreeForm(ctx); err != nil {
+					return nil, err
+				}
+				label, _ := args["connection"].(string)
+				ctx, err := s.withConnection(ctx, label)
+				if err != nil {
+					return nil, err
+				}
+				question, _ := args["question"].(string)
+				if err := s.checkQueryPolicy(ctx, "mcp", query, params, question); err != nil {
+					return nil, err
+				}
+
+				if staged, err := s.stageAction(ctx, "query", query, params); staged != nil || err != nil {
+					return staged, err
+				}
+
+				if question != "" {
+					var node *provenance.Node
+					ctx, node = s.Provenance.Start(ctx, provenance.KindQuestion, question, nil)
+					defer s.Provenance.Finish(node, nil, nil)
+				}
+
+				rows, err := s.executeTracked(ctx, query, params)
+				if err != nil {
+					return nil, err
+				}
+				return s.rowsToolResult("query", sess, rows)
+			},
+		},
+	}
+}
+
+// executeTracked executes a query, recording the SQL and its execution as provenance nodes
+func (s *MCPServerWithDB) executeTracked(ctx context.Context, query string, params map[string]interface{}) (*connector.ResultSet, error) {
+	ctx, sqlNode := s.Provenance.Start(ctx, provenance.KindSQL, query, map[string]interface{}{
+		"params": params,
+	})
+	defer s.Provenance.Finish(sqlNode, nil, nil)
+
+	ctx, execNode := s.Provenance.Start(ctx, provenance.KindExecution, "execute", nil)
+	var retries int
+	ctx = withRetryReporter(ctx, func(total int) { retries = total })
+	result, err := s.executeOrdered(ctx, "mcp", query, params)
+	rowCount := 0
+	if result != nil {
+		rowCount = len(result.Rows)
+	}
+	s.Provenance.Finish(execNode, err, map[string]interface{}{
+		"row_count": rowCount,
+		"retries":   retries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	return result, nil
+}
+
+// jsonToolResult encodes a value as a text tool result
+func jsonToolResult(v interface{}) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return mcp.NewCallToolResultText(string(data)), nil
+}
+
+// sendMCPResult sends a JSON-RPC success response
+func sendMCPResult(c *gin.Context, id any, result any) {
+	c.JSON(http.StatusOK, mcp.JSONRPCResponse{
+		JSONRPCBaseResult: mcp.JSONRPCBaseResult{
+			JSONRPC: mcp.JSPNRPCVersion,
+			ID:      id,
+		},
+		Result: result,
+	})
+}
+
+// sendMCPError sends a JSON-RPC error response
+func sendMCPError(c *gin.Context, id any, message string, statusCode int, code int) {
+	c.JSON(statusCode, mcp.JSONRPCErrorSchema{
+		JSONRPCBaseResult: mcp.JSONRPCBaseResult{
+			JSONRPC: mcp.JSPNRPCVersion,
+			ID:      id,
+		},
+		Error: mcp.JSONRPCError{
+			Code:    code,
+			Message: message,
+		},
+	})
+}
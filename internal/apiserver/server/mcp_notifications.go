@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	// mcpNotificationBuffer is the number of notifications queued for a
+	// session's stream; further notifications are dropped until it drains
+	mcpNotificationBuffer = 64
+
+	// mcpStreamKeepAlive is the interval of keep-alive comments on idle
+	// notification streams
+	mcpStreamKeepAlive = 30 * time.Second
+)
+
+// subscribe registers the session for update notifications of a resource
+func (sess *mcpSession) subscribe(uri string) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.subscriptions == nil {
+		sess.subscriptions = make(map[string]bool)
+	}
+	sess.subscriptions[uri] = true
+}
+
+// unsubscribe stops the update notifications of a resource
+func (sess *mcpSession) unsubscribe(uri string) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	delete(sess.subscriptions, uri)
+}
+
+// openStream opens the session's notification stream; ok is false when
+// one is already open
+func (sess *mcpSession) openStream() (stream chan mcp.JSONRPCNotification, ok bool) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.stream != nil {
+		return nil, false
+	}
+	sess.stream = make(chan mcp.JSONRPCNotification, mcpNotificationBuffer)
+	return sess.stream, true
+}
+
+// closeStream closes the stream opened by openStream
+func (sess *mcpSession) closeStream(stream chan mcp.JSONRPCNotification) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.stream == stream {
+		sess.stream = nil
+	}
+}
+
+// notify queues a notification on the session's stream, if one is open.
+// Notifications are dropped while the stream is full.
+func (sess *mcpSession) notify(method string, params interface{}) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.queue(method, params)
+}
+
+// notifyUpdated queues a resource update notification if the session
+// subscribed to the resource
+func (sess *mcpSession) notifyUpdated(uri string) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.subscriptions[uri] {
+		sess.queue(mcp.NotificationResourceUpdated, mcp.ResourceUpdatedNotificationParams{URI: uri})
+	}
+}
+
+// queue sends a notification to the open stream; callers hold sess.mu
+func (sess *mcpSession) queue(method string, params interface{}) {
+	if sess.stream == nil {
+		return
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		log.Printf("Warning: Failed to encode %s notification: %v", method, err)
+		return
+	}
+	select {
+	case sess.stream <- mcp.JSONRPCNotification{
+		JSONRPCBaseResult: mcp.JSONRPCBaseResult{JSONRPC: mcp.JSPNRPCVersion},
+		Method:            method,
+		Params:            data,
+	}:
+	default:
+		log.Printf("Warning: Dropped %s notification for MCP session %s", method, sess.ID)
+	}
+}
+
+// all returns the active sessions; a nil store has none
+func (st *mcpSessionStore) all() []*mcpSession {
+	if st == nil {
+		return nil
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	sessions := make([]*mcpSession, 0, len(st.sessions))
+	for _, sess := range st.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
+
+// notifyResourceUpdated notifies the sessions subscribed to a resource
+func (s *MCPServerWithDB) notifyResourceUpdated(uri string) {
+	for _, sess := range s.mcpSessions.all() {
+		sess.notifyUpdated(uri)
+	}
+}
+
+// notifyListChanged tells every session the list of tools, resources or
+// prompts changed, by sending one of the notifications/*/list_changed methods
+func (s *MCPServerWithDB) notifyListChanged(method string) {
+	for _, sess := range s.mcpSessions.all() {
+		sess.notify(method, struct{}{})
+	}
+}
+
+// handleMCPGet opens the server-to-client SSE stream of a session, which
+// carries its resource update notifications
+func (s *MCPServerWithDB) handleMCPGet(c *gin.Context) {
+	sess, ok := s.mcpSessions.get(c.GetHeader(mcp.HeaderMcpSessionID))
+	if !ok {
+		sendMCPError(c, nil, "Invalid Request: Session not found", http.StatusBadRequest, mcp.ErrorCodeInvalidRequest)
+		return
+	}
+	stream, ok := sess.openStream()
+	if !ok {
+		sendMCPError(c, nil, "Conflict: Only one stream is allowed per session", http.StatusConflict, mcp.ErrorCodeInvalidRequest)
+		return
+	}
+	defer sess.closeStream(stream)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache, no-transform")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(mcpStreamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		case n := <-stream:
+			data, err := json.Marshal(n)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(c.Writer, "event: message\ndata: %s\n\n", data)
+		}
+		c.Writer.Flush()
+	}
+}
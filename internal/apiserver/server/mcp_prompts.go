@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// tablePromptData is the data available to table-scoped MCP prompt templates
+type tablePromptData struct {
+	Table      *connector.TableMetadata
+	Dialect    string
+	ProfileSQL string
+}
+
+// mcpPrompts returns the prompts exposed to MCP clients. Their text comes from
+// the prompt registry, so operators can edit them like the other templates.
+func mcpPrompts() []mcp.PromptSchema {
+	tableArg := []mcp.PromptArgumentSchema{
+		{Name: "table", Description: "Name of the table", Required: true},
+	}
+	return []mcp.PromptSchema{
+		{
+			Name:        prompt.NameAnalyzeTable,
+			Description: "Explore a table: what it contains, how its key columns are distributed and what stands out",
+			Arguments:   tableArg,
+		},
+		{
+			Name:        prompt.NameProfileDataQuality,
+			Description: "Check a table for nulls, duplicates, orphaned references and out-of-range values",
+			Arguments:   tableArg,
+		},
+	}
+}
+
+// getMCPPrompt renders a prompt from the live metadata of the requested table
+func (s *MCPServerWithDB) getMCPPrompt(ctx context.Context, params mcp.GetPromptParams) (*mcp.GetPromptResult, error) {
+	var schema *mcp.PromptSchema
+	for _, p := range mcpPrompts() {
+		if p.Name == params.Name {
+			schema = &p
+			break
+		}
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("unknown prompt: %s", params.Name)
+	}
+
+	table := params.Arguments["table"]
+	if table == "" {
+		return nil, fmt.Errorf("argument table is required")
+	}
+	metadata, err := s.tableMetadata(ctx, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table metadata: %w", err)
+	}
+
+	dialect := ""
+	if s.Config.Database != nil {
+		dialect = dialectName(s.Config.Database.Type)
+	}
+	text, _, err := s.Prompts.Render(params.Name, tablePromptData{
+		Table:      metadata,
+		Dialect:    dialect,
+		ProfileSQL: profileSQL(connector.DialectOf(s.DBConn), metadata),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mcp.GetPromptResult{
+		Description: schema.Description,
+		Messages: []mcp.PromptMessage{
+			{
+				Role:    "user",
+				Content: &mcp.TextContent{Type: mcp.TextContentType, Text: text},
+			},
+		},
+	}, nil
+}
+
+// profileSQL builds a single query counting nulls and distinct values per column
+func profileSQL(d connector.Dialect, metadata *connector.TableMetadata) string {
+	var b strings.Builder
+	b.WriteString("SELECT COUNT(*) AS row_count")
+	for _, col := range metadata.Columns {
+		name := d.QuoteIdentifier(col.Name)
+		fmt.Fprintf(&b, ",\n  COUNT(*) - COUNT(%s) AS %s", name, d.QuoteIdentifier(col.Name+"_nulls"))
+		fmt.Fprintf(&b, ",\n  COUNT(DISTINCT %s) AS %s", name, d.QuoteIdentifier(col.Name+"_distinct"))
+	}
+	fmt.Fprintf(&b, "\nFROM %s", d.Table(metadata.Name))
+	return b.String()
+}
@@ -3,32 +3,237 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/api"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/catalog"
 	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/eval"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/lineage"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/provenance"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/search"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/secrets"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/watermark"
+	"gorm.io/gorm"
 )
 
+// DefaultAPIPrefix is the path the REST API is served under unless
+// api_prefix is configured
+const DefaultAPIPrefix = "/api/db"
+
 // MCPServerConfig extends the existing configuration with database options
 type MCPServerConfig struct {
 	// Existing fields
-	Type        string            `json:"type"`
-	Name        string            `json:"name"`
-	Command     string            `json:"command,omitempty"`
-	Args        []string          `json:"args,omitempty"`
-	Env         map[string]string `json:"env,omitempty"`
-	URL         string            `json:"url,omitempty"`
-	Preinstalled bool             `json:"preinstalled,omitempty"`
-	Policy      string            `json:"policy,omitempty"`
-	
+	Type         string            `json:"type"`
+	Name         string            `json:"name"`
+	Command      string            `json:"command,omitempty"`
+	Args         []string          `json:"args,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	URL          string            `json:"url,omitempty"`
+	Preinstalled bool              `json:"preinstalled,omitempty"`
+	Policy       string            `json:"policy,omitempty"`
+
 	// New database fields
-	Database    *connector.DatabaseConfig `json:"database,omitempty"`
-	EnableAPI   bool                      `json:"enable_api,omitempty"`
-	APIPrefix   string                    `json:"api_prefix,omitempty"`
-	EnableLLM   bool                      `json:"enable_llm,omitempty"`
+	Database  *connector.DatabaseConfig `json:"database,omitempty"`
+	EnableAPI bool                      `json:"enable_api,omitempty"`
+	APIPrefix string                    `json:"api_prefix,omitempty"`
+	EnableLLM bool                      `json:"enable_llm,omitempty"`
+
+	// EnableUI serves the web UI for exploring tables and trying the
+	// generated endpoints at <api_prefix>/ui
+	EnableUI bool `json:"enable_ui,omitempty"`
+
+	// APIAddr is the listen address of the REST API (default: :8081)
+	APIAddr string `json:"api_addr,omitempty"`
+
+	// Admin restricts the /admin routes to callers of admin roles
+	Admin *AdminConfig `json:"admin,omitempty"`
+
+	// Tables whose exports are watermarked and fingerprinted
+	SensitiveTables []string          `json:"sensitive_tables,omitempty"`
+	Watermark       *watermark.Config `json:"watermark,omitempty"`
+	Audit           *audit.Config     `json:"audit,omitempty"`
+
+	// ToolResults controls how MCP tool results are serialized
+	ToolResults *ToolResultConfig `json:"tool_results,omitempty"`
+
+	// Embedding enables semantic table search
+	Embedding *llm.Config `json:"embedding,omitempty"`
+
+	// State configures the gateway's own state store
+	State *state.Config `json:"state,omitempty"`
+
+	// Prompts replaces the built-in LLM prompt templates with files
+	Prompts *prompt.Config `json:"prompts,omitempty"`
+
+	// TableTools exposes list and get MCP tools for every table
+	TableTools bool `json:"table_tools,omitempty"`
+
+	// SpendReport schedules delivery of the spend attribution report
+	SpendReport *SpendReportConfig `json:"spend_report,omitempty"`
+
+	// ReadReplicas route read queries to replicas of the database
+	ReadReplicas *ReplicaConfig `json:"read_replicas,omitempty"`
+
+	// Connections are further databases, e.g. staging, that requests
+	// select by label
+	Connections []LabeledConnection `json:"connections,omitempty"`
+
+	// Upstreams are MCP servers whose tools and resources are re-exposed
+	// through this server's MCP endpoint
+	Upstreams []UpstreamConfig `json:"upstreams,omitempty"`
+
+	// ToolNaming namespaces tool names and resolves conflicts between them
+	ToolNaming *ToolNamingConfig `json:"tool_naming,omitempty"`
+
+	// SchemaWatch re-introspects the schema periodically and updates what
+	// was generated from it
+	SchemaWatch *SchemaWatchConfig `json:"schema_watch,omitempty"`
+
+	// Warmup preloads the server on startup before it reports ready
+	Warmup *WarmupConfig `json:"warmup,omitempty"`
+
+	// Events publishes lifecycle and query events to webhooks, NATS or Kafka
+	Events *events.Config `json:"events,omitempty"`
+
+	// ScheduledQueries are named queries run on cron schedules
+	ScheduledQueries []ScheduledQueryConfig `json:"scheduled_queries,omitempty"`
+
+	// Transactions limits the transactional execution API
+	Transactions *TransactionConfig `json:"transactions,omitempty"`
+
+	// GraphQL serves a GraphQL API alongside the generated REST endpoints
+	GraphQL *GraphQLConfig `json:"graphql,omitempty"`
+
+	// GRPC serves metadata and queries over gRPC on its own listener
+	GRPC *GRPCConfig `json:"grpc,omitempty"`
+
+	// RowSecurity filters the rows callers can read and change by their claims
+	RowSecurity *RowSecurityConfig `json:"row_security,omitempty"`
+
+	// EndpointOverrides customize the endpoints generated for tables
+	EndpointOverrides api.EndpointOverrides `json:"endpoint_overrides,omitempty"`
+
+	// ComputedColumns are derived columns added to table metadata and to
+	// the rows returned by generated endpoints and table tools
+	ComputedColumns []ComputedColumnConfig `json:"computed_columns,omitempty"`
+
+	// ResponseTransforms reshape the rows of generated endpoints, keyed by
+	// "METHOD /path" as the endpoints are generated, e.g. "GET /ORDERS"
+	ResponseTransforms map[string]ResponseTransformConfig `json:"response_transforms,omitempty"`
+
+	// Subscriptions limits the WebSocket subscriptions to saved queries
+	Subscriptions *SubscriptionConfig `json:"subscriptions,omitempty"`
+
+	// ChangeStreams streams the row changes of tables captured by database
+	// streams
+	ChangeStreams *ChangeStreamConfig `json:"change_streams,omitempty"`
+
+	// Routines are the stored procedures and functions exposed as endpoints
+	// and MCP tools, by name or by signature like ADD_ORDER(NUMBER, VARCHAR)
+	Routines []string `json:"routines,omitempty"`
+
+	// DebugErrors returns the messages of database errors to callers, which
+	// otherwise get generic messages by error code
+	DebugErrors bool `json:"debug_errors,omitempty"`
+
+	// QueryQueue bounds the queries run on the connection at once
+	QueryQueue *QueryQueueConfig `json:"query_queue,omitempty"`
+
+	// CORS allows browser-based tools on other origins to call the API
+	CORS *CORSConfig `json:"cors,omitempty"`
+
+	// SecurityHeaders controls the security headers of API responses
+	SecurityHeaders *SecurityHeadersConfig `json:"security_headers,omitempty"`
+
+	// Compression controls the compression of JSON and CSV responses
+	Compression *CompressionConfig `json:"compression,omitempty"`
+
+	// CacheControl sets the Cache-Control max-age of generated read
+	// endpoints by table or endpoint
+	CacheControl *CacheControlConfig `json:"cache_control,omitempty"`
+
+	// Limits bound request bodies and query parameters
+	Limits *LimitsConfig `json:"limits,omitempty"`
+
+	// Retry retries queries that fail on transient errors
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// Snapshots copy small tables into an embedded store that serves
+	// their generated read endpoints
+	Snapshots *SnapshotConfig `json:"snapshots,omitempty"`
+
+	// Exports write query results to cloud storage in the background
+	Exports *ExportConfig `json:"exports,omitempty"`
+
+	// QueryJobs run free-form queries in the background for clients to
+	// poll
+	QueryJobs *QueryJobConfig `json:"query_jobs,omitempty"`
+
+	// Imports load CSV and NDJSON files into tables
+	Imports *ImportConfig `json:"imports,omitempty"`
+
+	// Catalog syncs table documentation from DataHub or OpenMetadata
+	Catalog *catalog.Config `json:"catalog,omitempty"`
+
+	// Lineage adds dbt lineage to the related tables of each table
+	Lineage *LineageConfig `json:"lineage,omitempty"`
+
+	// History records executed queries for the query history endpoints
+	History *HistoryConfig `json:"history,omitempty"`
+
+	// Quotas limit the daily queries, rows and bytes scanned of callers
+	Quotas *QuotaConfig `json:"quotas,omitempty"`
+
+	// NetworkPolicy restricts the client addresses of the API, MCP and
+	// gRPC transports
+	NetworkPolicy *NetworkPolicyConfig `json:"network_policy,omitempty"`
+
+	// Monitors checks expectations on the row count and freshness of
+	// tables, registered through the monitors API
+	Monitors *MonitorConfig `json:"monitors,omitempty"`
+
+	// Sandbox lets tables be cloned on demand, sending the writes of their
+	// generated endpoints to the clone
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// Recipes are SQL templates with typed inputs served as MCP tools
+	Recipes []QueryRecipeConfig `json:"recipes,omitempty"`
+
+	// QueryPolicy restricts the statements of free-form SQL
+	QueryPolicy *QueryPolicyConfig `json:"query_policy,omitempty"`
+
+	// Approvals stage the destructive statements of MCP tools until an
+	// administrator approves them
+	Approvals *ApprovalConfig `json:"approvals,omitempty"`
+
+	// Review parks the risky queries of POST /query until a reviewer
+	// approves them
+	Review *ReviewConfig `json:"review,omitempty"`
+
+	// ClientTLS serves the server's listener over TLS with client
+	// certificates mapped to principals
+	ClientTLS *ClientTLSConfig `json:"client_tls,omitempty"`
+
+	// Federation bounds the federated queries of the management API; it is
+	// read from the configuration file by the serve command
+	Federation *FederationConfig `json:"federation,omitempty"`
+
+	// Secrets encrypts the secrets of the server configurations stored in
+	// the registry; it is read from the configuration file by the serve
+	// command
+	Secrets *secrets.Config `json:"secrets,omitempty"`
 }
 
 // MCPServerWithDB extends the MCP server with database capabilities
@@ -36,7 +241,86 @@ type MCPServerWithDB struct {
 	Config    *MCPServerConfig
 	DBConn    connector.DatabaseConnector
 	APIRouter *gin.Engine
-	
+	Audit     audit.Recorder
+
+	// State is the gateway's own state store, nil when not configured
+	State *state.Store
+
+	// Prompts holds the editable templates used by the LLM features
+	Prompts *prompt.Registry
+
+	// LLMUsage accumulates token usage and estimated LLM cost
+	LLMUsage *llm.Meter
+
+	// Provenance tracks the chain of tool calls within each MCP session
+	Provenance *provenance.Tracker
+
+	// Events publishes lifecycle and query events to the configured sinks
+	Events *events.Bus
+
+	// OnEndpointsGenerated is called with all generated endpoints whenever
+	// /generate-api changes them, so they can be persisted
+	OnEndpointsGenerated func(endpoints []connector.APIEndpoint)
+
+	// TransformRows, when set, post-processes the rows of every generated
+	// endpoint after the configured response transforms
+	TransformRows func(endpoint connector.APIEndpoint, rows []map[string]interface{}) ([]map[string]interface{}, error)
+
+	watermarker  *watermark.Watermarker
+	mcpSessions  *mcpSessionStore
+	results      *resultStore
+	tableSearch  *tableSearcher
+	llm          llm.Provider
+	evals        *eval.Store
+	upstreams    []*upstream
+	replicas     *replicaSet
+	connections  *connectionSet
+	warmup       *warmup
+	schemaWatch  *schemaWatcher
+	scheduler    *scheduler
+	saved        *savedQueries
+	rowSecurity  *rowSecurity
+	transforms   map[string]*rowTransform
+	changes      *changeFeed
+	queries      *queryTracker
+	retries      *retryPolicy
+	limits       requestLimits
+	cacheControl *cacheControl
+	snapshots    *snapshotStore
+	exports      *exportJobs
+	jobs         *queryJobs
+	catalog      *catalogSync
+	lineage      *lineage.Graph
+	history      *queryHistory
+	usage        *usageAnalytics
+	quotas       *quotaTracker
+	network      *networkPolicy
+	clientTLS    *clientTLS
+	queryPolicy  *queryPolicy
+	approvals    *approvals
+	review       *queryReview
+	recipes      []*queryRecipe
+	sandboxes    *sandboxes
+	baselines    *baselineStore
+	monitors     *monitors
+	tableStats   *statsCache
+	idempotency  *idempotencyStore
+	pages        *pageTokens
+
+	tableToolsMu    sync.Mutex
+	tableToolsCache []mcpTool
+	routinesMu      sync.Mutex
+	routinesCache   map[string]connector.Routine
+	graphQLCache    graphQLSchemaCache
+
+	routes     *routeManager
+	apiPrefix  string
+	httpServer *http.Server
+	grpcServer *http.Server
+
+	// mounted servers are served by the registry instead of their own listener
+	mounted bool
+
 	// For managing the lifecycle
 	ctx        context.Context
 	cancelFunc context.CancelFunc
@@ -49,73 +333,515 @@ func NewMCPServerWithDB(config *MCPServerConfig) (*MCPServerWithDB, error) {
 	if config == nil {
 		return nil, fmt.Errorf("server configuration is required")
 	}
-	
+
 	// Create context with cancel function for lifecycle management
 	ctx, cancel := context.WithCancel(context.Background())
-	
+
 	server := &MCPServerWithDB{
-		Config:     config,
-		ctx:        ctx,
-		cancelFunc: cancel,
+		Config:      config,
+		ctx:         ctx,
+		cancelFunc:  cancel,
+		Provenance:  provenance.NewTracker(0),
+		LLMUsage:    llm.NewMeter(),
+		mcpSessions: newMCPSessionStore(),
+		schemaWatch: &schemaWatcher{},
+		usage:       newUsageAnalytics(),
+		baselines:   newBaselineStore(),
+		tableStats:  newStatsCache(),
+		idempotency: newIdempotencyStore(),
+		pages:       newPageTokens(),
+	}
+
+	var resultTTL time.Duration
+	if config.ToolResults != nil {
+		if err := config.ToolResults.validate(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid tool result configuration: %w", err)
+		}
+		resultTTL, _ = time.ParseDuration(config.ToolResults.ResultTTL)
+	}
+	server.results = newResultStore(resultTTL, 0)
+
+	if config.Embedding != nil {
+		embedder, err := llm.NewEmbedder(config.Embedding)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create embedder: %w", err)
+		}
+		server.tableSearch = &tableSearcher{
+			embedder: embedder,
+			index:    search.NewIndex(),
+		}
+	}
+
+	if config.ToolNaming != nil {
+		if err := config.ToolNaming.validate(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid tool naming configuration: %w", err)
+		}
+	}
+
+	if err := config.EndpointOverrides.Validate(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid endpoint overrides: %w", err)
+	}
+	if err := validateComputedColumns(config.ComputedColumns); err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid computed columns: %w", err)
+	}
+
+	transforms, err := newRowTransforms(config.ResponseTransforms)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid response transforms: %w", err)
+	}
+	server.transforms = transforms
+
+	cacheControl, err := newCacheControl(config.CacheControl)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid cache control: %w", err)
+	}
+	server.cacheControl = cacheControl
+
+	changes, err := newChangeFeed(config.ChangeStreams)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid change streams: %w", err)
+	}
+	server.changes = changes
+
+	queue, err := newQueryQueue(config.QueryQueue)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid query queue: %w", err)
+	}
+	server.queries = newQueryTracker(queue)
+
+	if config.CORS != nil {
+		if err := config.CORS.validate(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid CORS configuration: %w", err)
+		}
+	}
+
+	if config.Compression != nil {
+		if err := config.Compression.validate(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid compression configuration: %w", err)
+		}
+	}
+
+	limits, err := newRequestLimits(config.Limits)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid limits: %w", err)
+	}
+	server.limits = limits
+
+	retries, err := newRetryPolicy(config.Retry)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid retry configuration: %w", err)
+	}
+	server.retries = retries
+
+	snapshots, err := newSnapshotStore(config.Snapshots)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid snapshot configuration: %w", err)
+	}
+	server.snapshots = snapshots
+
+	exports, err := newExportJobs(config.Exports)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid export configuration: %w", err)
+	}
+	server.exports = exports
+
+	jobs, err := newQueryJobs(config.QueryJobs)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid query job configuration: %w", err)
+	}
+	server.jobs = jobs
+
+	docs, err := newCatalogSync(config.Catalog)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid catalog configuration: %w", err)
+	}
+	server.catalog = docs
+
+	policy, err := newNetworkPolicy(config.NetworkPolicy)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid network policy: %w", err)
+	}
+	server.network = policy
+
+	certs, err := newClientTLS(config.ClientTLS)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid client TLS configuration: %w", err)
+	}
+	server.clientTLS = certs
+
+	dbType := ""
+	if config.Database != nil {
+		dbType = config.Database.Type
+	}
+	sqlPolicy, err := newQueryPolicy(config.QueryPolicy, dbType)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid query policy: %w", err)
+	}
+	server.queryPolicy = sqlPolicy
+
+	approvals, err := newApprovals(config.Approvals, dbType)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid approval configuration: %w", err)
+	}
+	server.approvals = approvals
+
+	review, err := newQueryReview(config.Review, dbType)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid review configuration: %w", err)
+	}
+	server.review = review
+
+	sandboxes, err := newSandboxes(config.Sandbox)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid sandbox configuration: %w", err)
+	}
+	server.sandboxes = sandboxes
+
+	recipes, err := newQueryRecipes(config.Recipes)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid recipes: %w", err)
+	}
+	server.recipes = recipes
+
+	quotas, err := newQuotaTracker(config.Quotas)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid quota configuration: %w", err)
+	}
+	server.quotas = quotas
+
+	graph, err := newLineage(config.Lineage)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid lineage configuration: %w", err)
+	}
+	server.lineage = graph
+
+	if config.Imports != nil {
+		if err := config.Imports.validate(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid import configuration: %w", err)
+		}
+	}
+
+	rowSecurity, err := newRowSecurity(config.RowSecurity)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid row security configuration: %w", err)
+	}
+	server.rowSecurity = rowSecurity
+
+	upstreams, err := newUpstreams(config.Upstreams)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	server.upstreams = upstreams
+
+	bus, err := events.NewBus(config.Events)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create event sinks: %w", err)
+	}
+	server.Events = bus
+
+	var stateDB *gorm.DB
+	if config.State != nil {
+		store, err := state.OpenAndMigrate(config.State)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to initialize state store: %w", err)
+		}
+		server.State = store
+		server.evals = eval.NewStore(store.DB)
+		stateDB = store.DB
+	}
+
+	sched, err := newScheduler(config.ScheduledQueries, stateDB)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid scheduled queries: %w", err)
+	}
+	server.scheduler = sched
+	server.saved = newSavedQueries(config.Name, stateDB)
+	history, err := newQueryHistory(config.Name, config.History, stateDB)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid history configuration: %w", err)
+	}
+	server.history = history
+	if err := server.saved.load(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	monitors, err := newMonitors(config.Name, config.Monitors, stateDB)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("invalid monitor configuration: %w", err)
+	}
+	if err := monitors.load(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	server.monitors = monitors
+
+	prompts, err := prompt.NewRegistry(stateDB, config.Prompts)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to load prompt templates: %w", err)
+	}
+	server.Prompts = prompts
+
+	recorder, err := audit.NewRecorder(config.Audit, stateDB)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create audit recorder: %w", err)
+	}
+	server.Audit = recorder
+
+	if config.Watermark != nil && config.Watermark.Enabled {
+		wm, err := watermark.New(config.Watermark)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to create watermarker: %w", err)
+		}
+		server.watermarker = wm
 	}
-	
+
 	// Initialize database connector if configured
 	if config.Database != nil && config.Database.Type != "" && config.Database.Type != "none" {
+		// Share one provider and prompt registry between the connector and
+		// the server's own LLM features
+		if config.Database.LLM != nil {
+			provider, err := llm.NewProvider(config.Database.LLM)
+			if err == nil {
+				provider, err = llm.Instrument(provider, config.Database.LLM, server.LLMUsage)
+			}
+			if err != nil {
+				cancel()
+				return nil, fmt.Errorf("failed to create llm provider: %w", err)
+			}
+			server.llm = provider
+			config.Database.Provider = provider
+		}
+		config.Database.Prompts = prompts
+
 		dbConn, err := connector.NewDatabaseConnector(config.Database)
 		if err != nil {
 			cancel() // Clean up context
 			return nil, fmt.Errorf("failed to create database connector: %w", err)
 		}
 		server.DBConn = dbConn
-		
+
+		replicas, err := newReplicaSet(config.ReadReplicas, config.Database)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid read replica configuration: %w", err)
+		}
+		server.replicas = replicas
+
+		connections, err := newConnectionSet(config.Connections, config.Database)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid connection configuration: %w", err)
+		}
+		server.connections = connections
+
+		warmup, err := newWarmup(config.Warmup)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid warmup configuration: %w", err)
+		}
+		server.warmup = warmup
+
+		if err := server.checkLazyConnection(); err != nil {
+			cancel()
+			return nil, fmt.Errorf("invalid database configuration: %w", err)
+		}
+
 		// Initialize API router if API is enabled
 		if config.EnableAPI {
 			server.APIRouter = gin.Default()
-			
+
 			// Set up API prefix
-			apiPrefix := "/api/db"
+			apiPrefix := DefaultAPIPrefix
 			if config.APIPrefix != "" {
 				apiPrefix = config.APIPrefix
 			}
-			
-			// Initialize API routes
-			apiGroup := server.APIRouter.Group(apiPrefix)
-			server.setupAPIRoutes(apiGroup)
+
+			// Initialize API routes. Generated endpoints are served by the
+			// route manager for requests no static route matches.
+			server.apiPrefix = apiPrefix
+			server.setupAPIRoutes(server.APIRouter.Group(apiPrefix))
+			server.routes = newRouteManager(apiPrefix, server.generatedEndpointHandler)
+			server.APIRouter.NoRoute(append(server.apiMiddleware(), server.routes.ServeHTTP)...)
 		}
 	}
-	
+
 	return server, nil
 }
 
+// checkLazyConnection rejects lazy connections to the primary along with
+// the features that query it on a schedule, which would keep its sessions
+// from ever being closed
+func (s *MCPServerWithDB) checkLazyConnection() error {
+	db := s.Config.Database
+	if db == nil || db.Snowflake == nil || !db.Snowflake.Lazy {
+		return nil
+	}
+	pollers := []struct {
+		name  string
+		polls bool
+	}{
+		{"warmup", s.warmup != nil},
+		{"schema_watch", s.Config.SchemaWatch != nil},
+		{"change_streams", s.changes != nil},
+		{"snapshots", s.snapshots != nil},
+		{"catalog", s.catalog != nil},
+		{"monitors", s.monitors != nil},
+		{"spend_report", s.Config.SpendReport != nil && s.Config.SpendReport.WebhookURL != ""},
+		{"quotas", s.quotas != nil && s.quotas.tracksBytes()},
+	}
+	for _, p := range pollers {
+		if p.polls {
+			return fmt.Errorf("lazy connections cannot be used with %s, which queries the database on a schedule", p.name)
+		}
+	}
+	return nil
+}
+
 // Start initializes and starts the MCP server
 func (s *MCPServerWithDB) Start() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
+
 	if s.isRunning {
 		return nil // Already running
 	}
-	
+
 	// Connect to database if configured
 	if s.DBConn != nil {
 		if err := s.DBConn.Connect(s.ctx); err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
-		
+		if s.replicas != nil {
+			s.replicas.connect(s.ctx)
+		}
+		if s.connections != nil {
+			s.connections.connect(s.ctx)
+		}
+
+		// Warm up before accepting requests
+		if s.warmup != nil {
+			s.runWarmup()
+		}
+
 		// Start API server if enabled
-		if s.Config.EnableAPI && s.APIRouter != nil {
-			go func() {
-				// Use a random port or configured port
-				addr := ":8081" // Default port, should be configurable
+		if s.Config.EnableAPI && s.APIRouter != nil && !s.mounted {
+			addr := s.Config.APIAddr
+			if addr == "" {
+				addr = ":8081"
+			}
+			s.httpServer = &http.Server{Addr: addr, Handler: s.APIRouter}
+			if s.clientTLS != nil {
+				s.httpServer.TLSConfig = s.clientTLS.config
+			}
+			go func(srv *http.Server) {
 				log.Printf("Starting API server on %s", addr)
-				if err := s.APIRouter.Run(addr); err != nil {
+				var err error
+				if srv.TLSConfig != nil {
+					err = srv.ListenAndServeTLS("", "")
+				} else {
+					err = srv.ListenAndServe()
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
 					log.Printf("API server error: %v", err)
 				}
-			}()
+			}(s.httpServer)
+		}
+		if s.clientTLS != nil && s.mounted {
+			log.Printf("Warning: Server %s is served by the gateway's listener; its client TLS configuration does not apply", s.Config.Name)
+		}
+
+		// Mounted servers are only reachable through the gateway's listener,
+		// behind its tenant authentication
+		if cfg := s.Config.GRPC; cfg != nil && cfg.Enabled {
+			if s.mounted {
+				log.Printf("Warning: Server %s is served by the gateway's listener; its gRPC listener is not started", s.Config.Name)
+			} else {
+				s.startGRPC(cfg)
+			}
+		}
+
+		if cfg := s.Config.SpendReport; cfg != nil && cfg.WebhookURL != "" {
+			go s.runSpendReports(cfg)
+		}
+
+		if s.Config.SchemaWatch != nil {
+			go s.runSchemaWatch(s.Config.SchemaWatch)
+		}
+
+		if s.changes != nil {
+			go s.runChangeStreams()
+		}
+
+		if s.snapshots != nil {
+			go s.runSnapshots()
+		}
+
+		if s.catalog != nil {
+			go s.runCatalogSync()
+		}
+
+		if s.monitors != nil {
+			go s.runMonitors()
+		}
+
+		if s.history != nil && s.history.db != nil {
+			go s.runHistoryPruning()
+		}
+
+		if s.quotas != nil && s.quotas.tracksBytes() {
+			go s.runQuotaRefresh()
+		}
+
+		if len(s.scheduler.queries) > 0 {
+			s.runScheduledQueries()
+		}
+	}
+
+	for _, u := range s.upstreams {
+		if err := u.start(s.ctx); err != nil {
+			log.Printf("Warning: Failed to connect to upstream %s: %v", u.namespace, err)
 		}
 	}
-	
+
 	s.isRunning = true
+	s.publish(events.TypeServerStarted, nil)
 	return nil
 }
 
@@ -123,46 +849,136 @@ func (s *MCPServerWithDB) Start() error {
 func (s *MCPServerWithDB) Stop() error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
-	
+
 	if !s.isRunning {
 		return nil // Already stopped
 	}
-	
+
+	// Stop accepting API requests before disconnecting
+	if s.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down API server: %v", err)
+		}
+		cancel()
+		s.httpServer = nil
+	}
+	if s.grpcServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.grpcServer.Shutdown(ctx); err != nil {
+			log.Printf("Error shutting down gRPC server: %v", err)
+		}
+		cancel()
+		s.grpcServer = nil
+	}
+
+	for _, u := range s.upstreams {
+		if err := u.stop(s.ctx); err != nil {
+			log.Printf("Error disconnecting from upstream %s: %v", u.namespace, err)
+		}
+	}
+
 	// Disconnect from database if connected
 	if s.DBConn != nil {
 		if err := s.DBConn.Disconnect(s.ctx); err != nil {
 			log.Printf("Error disconnecting from database: %v", err)
 		}
+		if s.replicas != nil {
+			s.replicas.disconnect(s.ctx)
+		}
+		if s.connections != nil {
+			s.connections.disconnect(s.ctx)
+		}
 	}
-	
+
 	// Cancel context to signal shutdown
 	s.cancelFunc()
-	
+
+	s.publish(events.TypeServerStopped, nil)
+	if err := s.Events.Close(); err != nil {
+		log.Printf("Error closing event sinks: %v", err)
+	}
+
 	s.isRunning = false
 	return nil
 }
 
+// apiMiddleware returns the middleware of the API routes and the generated
+// endpoints
+func (s *MCPServerWithDB) apiMiddleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		// Reject clients outside the network policy
+		s.networkPolicyMiddleware(),
+
+		// Read the principal of the caller's client certificate
+		s.clientCertMiddleware(),
+
+		// Answer preflight requests and allow configured origins
+		s.corsMiddleware(),
+
+		// Set security headers on every response
+		s.securityHeadersMiddleware(),
+
+		// Compress large responses
+		s.compressionMiddleware(),
+
+		// Reject oversized request bodies
+		s.bodyLimitMiddleware(),
+
+		// Tag warehouse queries with the caller for spend attribution
+		s.queryTagMiddleware(),
+
+		// Read the priority of the caller's queries
+		s.queryPriorityMiddleware(),
+
+		// Read the caller's idempotency key and report query retries
+		s.retryMiddleware(),
+
+		// Read the caller's claims for row filters
+		s.rowSecurityMiddleware(),
+
+		// Restrict the admin routes to callers of an admin role
+		s.adminMiddleware(),
+
+		// Select the connection of the caller's queries
+		s.connectionMiddleware(),
+
+		// Replay the responses of writes to retries with their idempotency key
+		s.idempotencyMiddleware(),
+	}
+}
+
 // setupAPIRoutes configures the API routes for database operations
 func (s *MCPServerWithDB) setupAPIRoutes(router *gin.RouterGroup) {
-	// List tables endpoint
+	router.Use(s.apiMiddleware()...)
+
+	// List tables endpoint; metadata responses carry entity tags so that
+	// clients can poll for changes cheaply
 	router.GET("/tables", func(c *gin.Context) {
 		tables, err := s.DBConn.ListTables(c.Request.Context())
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list tables: %v", err)})
+			s.respondError(c, queryErrorStatus(err), "Failed to list tables", err)
 			return
 		}
-		c.JSON(http.StatusOK, tables)
+		respondWithETag(c, tables)
 	})
-	
+
+	s.setupTableSearchRoutes(router)
+	s.setupRelatedTableRoutes(router)
+	s.setupAssertionRoutes(router)
+	s.setupTableStatsRoutes(router)
+	s.setupSchemaExportRoutes(router)
+	s.setupERDRoutes(router)
+
 	// Get table metadata endpoint
 	router.GET("/tables/:tableName", func(c *gin.Context) {
 		tableName := c.Param("tableName")
-		metadata, err := s.DBConn.GetTableMetadata(c.Request.Context(), tableName)
+		metadata, err := s.tableMetadata(c.Request.Context(), tableName)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get table metadata: %v", err)})
+			s.respondError(c, queryErrorStatus(err), "Failed to get table metadata", err)
 			return
 		}
-		
+
 		// Enhance metadata with LLM if enabled
 		if s.Config.EnableLLM {
 			if err := s.DBConn.EnhanceMetadataWithLLM(c.Request.Context(), metadata); err != nil {
@@ -170,106 +986,395 @@ func (s *MCPServerWithDB) setupAPIRoutes(router *gin.RouterGroup) {
 				// Continue anyway, this is not critical
 			}
 		}
-		
-		c.JSON(http.StatusOK, metadata)
+
+		respondWithETag(c, s.redactSamples(c.Request.Context(), s.withCatalog(s.withComputedColumns(metadata))))
 	})
-	
+
 	// Execute query endpoint
 	router.POST("/query", func(c *gin.Context) {
 		var request struct {
 			Query  string                 `json:"query"`
 			Params map[string]interface{} `json:"params"`
+			DryRun bool                   `json:"dry_run"`
+
+			// PageSize returns the rows in pages read from the result the
+			// database keeps, instead of all at once
+			PageSize int `json:"page_size"`
 		}
-		
+
 		if err := c.ShouldBindJSON(&request); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
 			return
 		}
-		
-		results, err := s.DBConn.ExecuteQuery(c.Request.Context(), request.Query, request.Params)
+
+		if err := s.checkFreeForm(c.Request.Context()); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+			return
+		}
+		if err := s.checkQueryPolicy(c.Request.Context(), "rest", request.Query, request.Params); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+			return
+		}
+
+		if request.DryRun {
+			c.JSON(http.StatusOK, s.dryRun(c.Request.Context(), request.Query, request.Params))
+			return
+		}
+		if s.parkQuery(c, request.Query, request.Params) {
+			return
+		}
+
+		if request.PageSize != 0 {
+			if err := checkPageSize(request.PageSize); err != nil {
+				s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+				return
+			}
+			page, err := s.executeFirstPage(c.Request.Context(), "rest", request.Query, request.Params, request.PageSize, principalFromContext(c))
+			if err != nil {
+				s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+				return
+			}
+			c.JSON(http.StatusOK, page)
+			return
+		}
+
+		results, err := s.executeOrdered(c.Request.Context(), "rest", request.Query, request.Params)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to execute query: %v", err)})
+			s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
 			return
 		}
-		
+
 		c.JSON(http.StatusOK, results)
 	})
-	
+
 	// Generate API endpoints
 	router.POST("/generate-api", func(c *gin.Context) {
 		var request struct {
 			Tables []string `json:"tables"`
 		}
-		
+
 		if err := c.ShouldBindJSON(&request); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
 			return
 		}
-		
-		endpoints, err := s.DBConn.GenerateAPIEndpoints(c.Request.Context(), request.Tables)
+
+		endpoints, err := s.GenerateEndpoints(c.Request.Context(), request.Tables)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to generate API endpoints: %v", err)})
 			return
 		}
-		
-		// Register the generated endpoints
-		s.registerGeneratedEndpoints(router, endpoints)
-		
+
+		// Register the generated endpoints, replacing earlier ones of the same tables
+		diff, err := s.routes.Apply(endpoints)
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Failed to register API endpoints: %v", err)})
+			return
+		}
+		if !diff.Empty() {
+			log.Printf("Generated routes changed: %d added, %d updated, %d removed", len(diff.Added), len(diff.Updated), len(diff.Removed))
+			s.publish(events.TypeEndpointsGenerated, map[string]interface{}{
+				"tables":  request.Tables,
+				"added":   diff.Added,
+				"updated": diff.Updated,
+				"removed": diff.Removed,
+			})
+			if s.OnEndpointsGenerated != nil {
+				s.OnEndpointsGenerated(s.routes.Endpoints())
+			}
+		}
+
 		c.JSON(http.StatusOK, endpoints)
 	})
+
+	s.setupExportRoutes(router)
+	s.setupExportJobRoutes(router)
+	s.setupQueryJobRoutes(router)
+	s.setupImportRoutes(router)
+	s.setupHistoryRoutes(router)
+	s.setupBudgetRoutes(router)
+	s.setupPromptRoutes(router)
+	s.setupAskRoutes(router)
+	s.setupEvalRoutes(router)
+	s.setupMetricsRoutes(router)
+	s.setupAttributionRoutes(router)
+	s.setupAnalyticsRoutes(router)
+	s.setupQuotaRoutes(router)
+	s.setupToolNamingRoutes(router)
+	s.setupSchemaWatchRoutes(router)
+	s.setupEndpointAdminRoutes(router)
+	s.setupOpenAPIRoutes(router)
+	s.setupUIRoutes(router)
+	s.setupScheduledRoutes(router)
+	s.setupSavedQueryRoutes(router)
+	s.setupRoutineRoutes(router)
+	s.setupQueryRoutes(router)
+	s.setupBaselineRoutes(router)
+	s.setupSnapshotRoutes(router)
+	s.setupSandboxRoutes(router)
+	s.setupApprovalRoutes(router)
+	s.setupReviewRoutes(router)
+	s.setupResultPageRoutes(router)
+	s.setupReplicaRoutes(router)
+	s.setupConnectionRoutes(router)
+	s.setupWarmupRoutes(router)
+	s.setupMonitorRoutes(router)
+	s.setupCatalogRoutes(router)
+	s.setupSubscriptionRoutes(router)
+	s.setupChangeRoutes(router)
+	s.setupTransactionRoutes(router)
+	s.setupGraphQLRoutes(router)
+	s.setupMCPRoutes(router)
 }
 
-// registerGeneratedEndpoints dynamically registers the generated API endpoints
-func (s *MCPServerWithDB) registerGeneratedEndpoints(router *gin.RouterGroup, endpoints []connector.APIEndpoint) {
-	for _, endpoint := range endpoints {
-		// Create a closure to capture the endpoint
-		handler := func(endpoint connector.APIEndpoint) gin.HandlerFunc {
-			return func(c *gin.Context) {
-				// Extract parameters from path and query
-				params := make(map[string]interface{})
-				
-				// Path parameters
-				for param := range endpoint.Parameters {
-					if value, exists := c.Params.Get(param); exists {
-						params[param] = value
-					}
-				}
-				
-				// Query parameters
-				for key, value := range c.Request.URL.Query() {
-					if len(value) > 0 {
-						params[key] = value[0]
-					}
-				}
-				
-				// Execute the query
-				results, err := s.DBConn.ExecuteQuery(c.Request.Context(), endpoint.Query, params)
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to execute query: %v", err)})
-					return
-				}
-				
-				c.JSON(http.StatusOK, results)
+// GenerateEndpoints generates the API endpoints of tables, customized by
+// the configured overrides and computed columns, without registering them
+func (s *MCPServerWithDB) GenerateEndpoints(ctx context.Context, tables []string) ([]connector.APIEndpoint, error) {
+	endpoints, err := s.DBConn.GenerateAPIEndpoints(ctx, tables)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints, err = s.addKeyEndpoints(ctx, endpoints); err != nil {
+		return nil, err
+	}
+	if endpoints, err = s.Config.EndpointOverrides.Apply(endpoints); err != nil {
+		return nil, err
+	}
+	dialect := connector.DialectOf(s.DBConn)
+	for i, e := range endpoints {
+		if selectsRows(e) {
+			endpoints[i].Query = s.selectComputed(e.Table, e.Query)
+		}
+		connector.OrderPage(dialect, &endpoints[i])
+		if endpoints[i].Warning != "" {
+			log.Printf("Warning: %s %s: %s", e.Method, e.Path, endpoints[i].Warning)
+		}
+	}
+	return endpoints, nil
+}
+
+// addKeyEndpoints generates the get and update endpoints of tables without
+// a primary key from the unique key configured in their overrides
+func (s *MCPServerWithDB) addKeyEndpoints(ctx context.Context, endpoints []connector.APIEndpoint) ([]connector.APIEndpoint, error) {
+	var tables []string
+	keyed := make(map[string]bool)
+	for _, e := range endpoints {
+		if e.Table == "" {
+			continue
+		}
+		if _, seen := keyed[e.Table]; !seen {
+			tables = append(tables, e.Table)
+		}
+		keyed[e.Table] = keyed[e.Table] || e.Operation == connector.OperationGet
+	}
+
+	for _, table := range tables {
+		if keyed[table] || !s.Config.EndpointOverrides.HasKey(table) {
+			continue
+		}
+		metadata, err := s.tableMetadata(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata for table %s: %w", table, err)
+		}
+		keys, err := s.Config.EndpointOverrides.KeyColumns(table, metadata.Columns)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, connector.KeyEndpoints(connector.DialectOf(s.DBConn), table, metadata.Columns, keys)...)
+	}
+	return endpoints, nil
+}
+
+// RegisterEndpoints registers previously generated endpoints, e.g. when
+// restoring a server from the registry
+func (s *MCPServerWithDB) RegisterEndpoints(endpoints []connector.APIEndpoint) error {
+	if s.routes == nil || len(endpoints) == 0 {
+		return nil
+	}
+	_, err := s.routes.Apply(endpoints)
+	return err
+}
+
+// IsRunning reports whether the server has been started
+func (s *MCPServerWithDB) IsRunning() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.isRunning
+}
+
+// generatedEndpointHandler executes a generated endpoint's query with the
+// request's path and query parameters
+func (s *MCPServerWithDB) generatedEndpointHandler(endpoint connector.APIEndpoint) gin.HandlerFunc {
+	name := endpointUsageName(endpoint.Method, endpoint.Path)
+	return func(c *gin.Context) {
+		started := time.Now()
+		defer func() {
+			s.usage.record(UsageKindEndpoint, name, time.Since(started), c.Writer.Status() >= http.StatusBadRequest)
+		}()
+
+		// Extract parameters from path and query
+		params := make(map[string]interface{})
+
+		// Path parameters
+		for _, param := range c.Params {
+			params[param.Key] = param.Value
+		}
+
+		// Write endpoints can be dry run with ?dry_run=true
+		dryRun := endpoint.Method != http.MethodGet && isDryRun(c)
+
+		// Query parameters
+		for key, value := range c.Request.URL.Query() {
+			if dryRun && key == "dry_run" {
+				continue
+			}
+			if len(value) > 0 {
+				params[key] = value[0]
+			}
+		}
+
+		if fieldErrs := validateParams(endpoint.ParamDefs(), params); len(fieldErrs) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request parameters", "fields": fieldErrs})
+			return
+		}
+
+		// Create and update endpoints take the record from the JSON body
+		insert := s.generatedInsert(endpoint)
+		var values map[string]interface{}
+		if len(endpoint.Columns) > 0 {
+			body, err := decodeBody(c.Request.Body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+				return
+			}
+			var fieldErrs []FieldError
+			values, fieldErrs = validateBody(endpoint.Columns, body, insert)
+			if len(fieldErrs) > 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "fields": fieldErrs})
+				return
 			}
-		}(endpoint)
-		
-		// Register the endpoint with the router
-		path := endpoint.Path
-		switch endpoint.Method {
-		case "GET":
-			router.GET(path, handler)
-		case "POST":
-			router.POST(path, handler)
-		case "PUT":
-			router.PUT(path, handler)
-		case "DELETE":
-			router.DELETE(path, handler)
-		default:
-			log.Printf("Unsupported HTTP method: %s", endpoint.Method)
+			for name, v := range values {
+				params[name] = v
+			}
+		}
+
+		// Generated inserts only set the columns the body provides
+		query := endpoint.Query
+		if insert {
+			if len(values) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: no columns to insert"})
+				return
+			}
+			query = connector.InsertValuesQuery(connector.DialectOf(s.DBConn), endpoint.Table, endpoint.Columns, values)
+		}
+
+		// Search endpoints may narrow the searched columns
+		if len(endpoint.SearchColumns) > 0 {
+			var err error
+			if query, err = s.textSearchQuery(endpoint, params); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+				return
+			}
+		}
+
+		// Writes return the row they wrote, in the same statement when the
+		// dialect can
+		returning := false
+		if returnsRow(endpoint) {
+			query, returning = connector.ReturningQuery(connector.DialectOf(s.DBConn), query)
+		}
+
+		// Read endpoints can read the table as it was at a point in time
+		query, timeTravel, err := s.timeTravelQuery(endpoint, query, params)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		// Writes to sandboxed tables go to their clone
+		query, clone := s.sandboxQuery(endpoint, query)
+		if clone != "" {
+			c.Header(sandboxHeader, clone)
+		}
+
+		// Restrict the rows to the caller's row filter of the table
+		if endpoint.Method == http.MethodPost {
+			err = s.checkTableAccess(c.Request.Context(), endpoint.Table)
+		} else {
+			query, err = s.restrictQuery(c.Request.Context(), endpoint.Table, query, params)
+		}
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+			return
+		}
+
+		if dryRun {
+			c.JSON(http.StatusOK, s.dryRun(c.Request.Context(), query, params))
+			return
 		}
+
+		// Execute the query, on the table's snapshot when it can answer
+		var results *connector.ResultSet
+		ok := false
+		if !timeTravel {
+			results, ok = s.snapshotResult(c, endpoint, query, params)
+		}
+		if !ok {
+			if results, err = s.executeOrdered(c.Request.Context(), "generated", query, params); err != nil {
+				s.respondError(c, queryErrorStatus(err), "Failed to execute query", err)
+				return
+			}
+		}
+		s.catalog.recordUsage(endpoint.Table)
+
+		// Versioned updates change no row when the version moved on
+		if endpoint.VersionColumn != "" {
+			n, ok := connector.UpdatedRows(results.Rows)
+			if returning {
+				n, ok = int64(len(results.Rows)), true
+			}
+			if ok && n == 0 {
+				c.JSON(http.StatusConflict, gin.H{
+					"error": fmt.Sprintf("Record was changed by another request or does not exist; read it again for its current %s", endpoint.VersionColumn),
+				})
+				return
+			}
+		}
+
+		// Other writes read their row back; the write stands when that fails
+		if returnsRow(endpoint) && !returning {
+			if written, err := s.writtenRow(c.Request.Context(), endpoint, params); err != nil {
+				log.Printf("Warning: failed to read back the row written by %s %s: %v", endpoint.Method, endpoint.Path, err)
+			} else if written != nil && len(written.Rows) > 0 {
+				results = written
+			}
+		}
+
+		if results.Rows, err = s.transformRows(endpoint, results.Rows); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to transform response: %v", err)})
+			return
+		}
+
+		s.setCacheControl(c, endpoint)
+		c.JSON(http.StatusOK, results)
 	}
 }
 
+// generatedInsert reports whether an endpoint runs the INSERT generated
+// for its table, which can be narrowed to the columns a request provides;
+// customized queries bind every column
+func (s *MCPServerWithDB) generatedInsert(endpoint connector.APIEndpoint) bool {
+	return endpoint.Operation == connector.OperationCreate && endpoint.Table != "" &&
+		endpoint.Query == connector.InsertQuery(connector.DialectOf(s.DBConn), endpoint.Table, endpoint.Columns)
+}
+
+// queryErrorStatus maps query execution errors to HTTP status codes
+func queryErrorStatus(err error) int {
+	if status, ok := codeStatus[errorCode(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
 // GetServerInfo returns information about the server
 func (s *MCPServerWithDB) GetServerInfo() map[string]interface{} {
 	info := map[string]interface{}{
@@ -277,7 +1382,7 @@ func (s *MCPServerWithDB) GetServerInfo() map[string]interface{} {
 		"type":       s.Config.Type,
 		"is_running": s.isRunning,
 	}
-	
+
 	if s.Config.Database != nil {
 		info["database"] = map[string]interface{}{
 			"type":       s.Config.Database.Type,
@@ -285,7 +1390,7 @@ func (s *MCPServerWithDB) GetServerInfo() map[string]interface{} {
 			"enable_llm": s.Config.EnableLLM,
 		}
 	}
-	
+
 	return info
 }
 
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/provenance"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+func TestQueryToolProvenance(t *testing.T) {
+	conn := &paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": 1.0}, {"ID": 2.0}}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, Provenance: provenance.NewTracker(0)}
+	query := s.builtinMCPTools()[2]
+	require.Equal(t, "query", query.Schema.Name)
+
+	ctx := provenance.WithSession(context.Background(), "s1")
+	_, err := query.Handler(ctx, &mcpSession{ID: "s1"}, map[string]interface{}{
+		"sql":      "SELECT ID FROM ORDERS WHERE REGION = :region",
+		"params":   map[string]interface{}{"region": "EU"},
+		"question": "Which orders were placed in Europe?",
+	})
+	require.NoError(t, err)
+
+	// The result is traced back through its execution and SQL to the question
+	trace, ok := s.Provenance.Trace("s1")
+	require.True(t, ok)
+	require.Len(t, trace.Nodes, 3)
+	question, sql, exec := trace.Nodes[0], trace.Nodes[1], trace.Nodes[2]
+	assert.Equal(t, provenance.KindQuestion, question.Kind)
+	assert.Equal(t, "Which orders were placed in Europe?", question.Label)
+	assert.Equal(t, provenance.KindSQL, sql.Kind)
+	assert.Equal(t, "SELECT ID FROM ORDERS WHERE REGION = :region", sql.Label)
+	assert.Equal(t, map[string]interface{}{"region": "EU"}, sql.Data["params"])
+	assert.Equal(t, provenance.KindExecution, exec.Kind)
+	assert.Equal(t, map[string]interface{}{"row_count": 2, "retries": 0}, exec.Data)
+	assert.Empty(t, exec.Error)
+	assert.Equal(t, []provenance.Edge{{From: question.ID, To: sql.ID}, {From: sql.ID, To: exec.ID}}, trace.Edges)
+}
+
+func TestMCPPrompts(t *testing.T) {
+	prompts, err := prompt.NewRegistry(nil, nil)
+	require.NoError(t, err)
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "sales", Database: &connector.DatabaseConfig{Type: "snowflake"}},
+		DBConn:      &statsConnector{},
+		Prompts:     prompts,
+		Provenance:  provenance.NewTracker(0),
+		mcpSessions: newMCPSessionStore(),
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupMCPRoutes(router.Group(""))
+	var session string
+	call := func(method string, params interface{}) (json.RawMessage, *mcp.JSONRPCError) {
+		body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(string(body)))
+		r.Header.Set(mcp.HeaderMcpSessionID, session)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if id := w.Header().Get(mcp.HeaderMcpSessionID); id != "" {
+			session = id
+		}
+		var resp struct {
+			Result json.RawMessage   `json:"result"`
+			Error  *mcp.JSONRPCError `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), w.Body.String())
+		return resp.Result, resp.Error
+	}
+	// getPrompt returns the text of the single user message of a prompt
+	getPrompt := func(name string, args map[string]string) (string, *mcp.JSONRPCError) {
+		raw, rpcErr := call(mcp.PromptsGet, mcp.GetPromptParams{Name: name, Arguments: args})
+		if rpcErr != nil {
+			return "", rpcErr
+		}
+		var result struct {
+			Messages []struct {
+				Role    string          `json:"role"`
+				Content mcp.TextContent `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &result))
+		require.Len(t, result.Messages, 1)
+		assert.Equal(t, "user", result.Messages[0].Role)
+		return result.Messages[0].Content.Text, nil
+	}
+
+	_, rpcErr := call(mcp.Initialize, mcp.InitializeRequestParams{ClientInfo: mcp.ImplementationSchema{Name: "inspector"}})
+	require.Nil(t, rpcErr)
+
+	raw, rpcErr := call(mcp.PromptsList, struct{}{})
+	require.Nil(t, rpcErr)
+	var list mcp.ListPromptsResult
+	require.NoError(t, json.Unmarshal(raw, &list))
+	require.Len(t, list.Prompts, 2)
+	assert.Equal(t, prompt.NameAnalyzeTable, list.Prompts[0].Name)
+	assert.Equal(t, prompt.NameProfileDataQuality, list.Prompts[1].Name)
+	assert.Equal(t, []mcp.PromptArgumentSchema{{Name: "table", Description: "Name of the table", Required: true}}, list.Prompts[0].Arguments)
+
+	// The table argument is substituted with the table's live metadata
+	analysis, rpcErr := getPrompt(prompt.NameAnalyzeTable, map[string]string{"table": "ORDERS"})
+	require.Nil(t, rpcErr)
+	assert.Contains(t, analysis, "Analyze the ORDERS table of this Snowflake database.")
+	assert.Contains(t, analysis, "It has 100 rows and these columns:")
+	assert.Contains(t, analysis, "- STATUS (TEXT)")
+
+	profile, rpcErr := getPrompt(prompt.NameProfileDataQuality, map[string]string{"table": "ORDERS"})
+	require.Nil(t, rpcErr)
+	assert.Contains(t, profile, `COUNT(*) - COUNT("TOTAL") AS "TOTAL_nulls"`)
+
+	// Edited templates are served at once
+	_, err = prompts.Update(prompt.NameAnalyzeTable, "Look at {{.Table.Name}} ({{len .Table.Columns}} columns).", "", "ops")
+	require.NoError(t, err)
+	analysis, rpcErr = getPrompt(prompt.NameAnalyzeTable, map[string]string{"table": "ORDERS"})
+	require.Nil(t, rpcErr)
+	assert.Equal(t, "Look at ORDERS (3 columns).", analysis)
+
+	// Unknown prompts and missing arguments are invalid parameters
+	_, rpcErr = getPrompt("summarize_table", map[string]string{"table": "ORDERS"})
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, mcp.ErrorCodeInvalidParams, rpcErr.Code)
+	assert.Equal(t, "unknown prompt: summarize_table", rpcErr.Message)
+	_, rpcErr = getPrompt(prompt.NameAnalyzeTable, nil)
+	require.NotNil(t, rpcErr)
+	assert.Equal(t, "argument table is required", rpcErr.Message)
+}
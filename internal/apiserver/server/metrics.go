@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+)
+
+// setupMetricsRoutes configures the LLM usage endpoints
+func (s *MCPServerWithDB) setupMetricsRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/llm/usage", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.LLMUsage.Snapshot())
+	})
+
+	// metrics in the Prometheus text exposition format
+	router.GET("/metrics", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(s.renderMetrics()))
+	})
+}
+
+// renderMetrics renders the server's counters in the Prometheus text format
+func (s *MCPServerWithDB) renderMetrics() string {
+	stats := s.LLMUsage.Snapshot()
+
+	var b strings.Builder
+	counter := func(name, help string, value func(st llm.UsageStats) string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		for _, st := range stats {
+			fmt.Fprintf(&b, "%s{provider=%q,model=%q} %s\n", name, st.Provider, st.Model, value(st))
+		}
+	}
+
+	counter("db_gateway_llm_requests_total", "LLM completion requests, including cache hits.", func(st llm.UsageStats) string {
+		return fmt.Sprint(st.Requests)
+	})
+	counter("db_gateway_llm_cache_hits_total", "LLM completions served from the response cache.", func(st llm.UsageStats) string {
+		return fmt.Sprint(st.CacheHits)
+	})
+	counter("db_gateway_llm_errors_total", "Failed LLM completion requests.", func(st llm.UsageStats) string {
+		return fmt.Sprint(st.Errors)
+	})
+	counter("db_gateway_llm_prompt_tokens_total", "Prompt tokens consumed.", func(st llm.UsageStats) string {
+		return fmt.Sprint(st.PromptTokens)
+	})
+	counter("db_gateway_llm_completion_tokens_total", "Completion tokens generated.", func(st llm.UsageStats) string {
+		return fmt.Sprint(st.CompletionTokens)
+	})
+	counter("db_gateway_llm_estimated_cost_usd_total", "Estimated LLM spend in USD.", func(st llm.UsageStats) string {
+		return fmt.Sprintf("%g", st.EstimatedCostUSD)
+	})
+	return b.String()
+}
@@ -0,0 +1,486 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+)
+
+const defaultMonitorInterval = 15 * time.Minute
+
+// Monitor states
+const (
+	MonitorPending = "pending"
+	MonitorOK      = "ok"
+	MonitorFailing = "failing"
+	MonitorError   = "error"
+)
+
+var (
+	// ErrMonitorNotFound is returned for unknown monitors
+	ErrMonitorNotFound = errors.New("monitor not found")
+
+	// ErrMonitorExists is returned when creating a monitor whose name is taken
+	ErrMonitorExists = errors.New("monitor already exists")
+
+	// ErrInvalidMonitor is returned for invalid monitor definitions
+	ErrInvalidMonitor = errors.New("invalid monitor")
+)
+
+// MonitorConfig enables the monitors API and the background checker that
+// evaluates the monitors' expectations
+type MonitorConfig struct {
+	// Interval between checks (default: 15m)
+	Interval string `json:"interval,omitempty"`
+}
+
+// Monitor holds expectations on a table's row count and freshness. A
+// monitor that starts failing publishes a monitor.failed event, and one
+// that passes again a monitor.recovered event.
+type Monitor struct {
+	Name  string `json:"name"`
+	Table string `json:"table"`
+
+	// MinRows is the fewest rows the table may hold
+	MinRows *int64 `json:"min_rows,omitempty"`
+
+	// TimestampColumn and MaxStaleness bound the age of the table's newest
+	// row, e.g. UPDATED_AT and 6h
+	TimestampColumn string `json:"timestamp_column,omitempty"`
+	MaxStaleness    string `json:"max_staleness,omitempty"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	Status    *MonitorStatus `json:"status"`
+
+	staleness time.Duration
+}
+
+// MonitorStatus is the result of a monitor's last check
+type MonitorStatus struct {
+	State     string     `json:"state"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+	RowCount  *int64     `json:"row_count,omitempty"`
+	LatestAt  *time.Time `json:"latest_at,omitempty"`
+
+	// Failures are the expectations the table did not meet
+	Failures []string `json:"failures,omitempty"`
+
+	// Error is why the table could not be checked
+	Error string `json:"error,omitempty"`
+}
+
+// monitorRow is the persisted form of a Monitor
+type monitorRow struct {
+	Server          string `gorm:"primaryKey"`
+	Name            string `gorm:"primaryKey"`
+	Table           string `gorm:"column:table_name"`
+	MinRows         *int64
+	TimestampColumn string
+	MaxStaleness    string
+	CreatedAt       time.Time
+}
+
+// TableName overrides the table name used by monitorRow
+func (monitorRow) TableName() string {
+	return "monitors"
+}
+
+// monitors holds a server's monitors, writing through to the state store
+// when the server has one and keeping them in memory otherwise
+type monitors struct {
+	server   string
+	db       *gorm.DB
+	interval time.Duration
+
+	mu       sync.RWMutex
+	monitors map[string]*Monitor
+}
+
+// newMonitors validates a monitor configuration; nil disables monitors.
+// db may be nil.
+func newMonitors(server string, cfg *MonitorConfig, db *gorm.DB) (*monitors, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	m := &monitors{
+		server:   server,
+		db:       db,
+		interval: defaultMonitorInterval,
+		monitors: make(map[string]*Monitor),
+	}
+	if cfg.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval %q", cfg.Interval)
+		}
+		m.interval = interval
+	}
+	return m, nil
+}
+
+// load reads the persisted monitors
+func (m *monitors) load(ctx context.Context) error {
+	if m == nil || m.db == nil {
+		return nil
+	}
+	var rows []monitorRow
+	if err := m.db.WithContext(ctx).Where("server = ?", m.server).Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to load monitors: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, row := range rows {
+		monitor := &Monitor{
+			Name:            row.Name,
+			Table:           row.Table,
+			MinRows:         row.MinRows,
+			TimestampColumn: row.TimestampColumn,
+			MaxStaleness:    row.MaxStaleness,
+			CreatedAt:       row.CreatedAt,
+			Status:          &MonitorStatus{State: MonitorPending},
+		}
+		if err := validateMonitor(monitor); err != nil {
+			return fmt.Errorf("monitor %s: %w", row.Name, err)
+		}
+		m.monitors[row.Name] = monitor
+	}
+	return nil
+}
+
+// list returns copies of the monitors ordered by name
+func (m *monitors) list() []*Monitor {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]*Monitor, 0, len(m.monitors))
+	for _, monitor := range m.monitors {
+		copied := *monitor
+		list = append(list, &copied)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// get returns a copy of a monitor by name
+func (m *monitors) get(name string) (*Monitor, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	monitor, ok := m.monitors[name]
+	if !ok {
+		return nil, ErrMonitorNotFound
+	}
+	copied := *monitor
+	return &copied, nil
+}
+
+// create stores a new monitor
+func (m *monitors) create(ctx context.Context, monitor *Monitor) error {
+	if err := validateMonitor(monitor); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.monitors[monitor.Name]; ok {
+		return ErrMonitorExists
+	}
+	monitor.CreatedAt = time.Now().UTC()
+	monitor.Status = &MonitorStatus{State: MonitorPending}
+	if m.db != nil {
+		row := &monitorRow{
+			Server:          m.server,
+			Name:            monitor.Name,
+			Table:           monitor.Table,
+			MinRows:         monitor.MinRows,
+			TimestampColumn: monitor.TimestampColumn,
+			MaxStaleness:    monitor.MaxStaleness,
+			CreatedAt:       monitor.CreatedAt,
+		}
+		if err := m.db.WithContext(ctx).Create(row).Error; err != nil {
+			return fmt.Errorf("failed to store monitor: %w", err)
+		}
+	}
+	m.monitors[monitor.Name] = monitor
+	return nil
+}
+
+// delete removes a monitor
+func (m *monitors) delete(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.monitors[name]; !ok {
+		return ErrMonitorNotFound
+	}
+	if m.db != nil {
+		err := m.db.WithContext(ctx).Where("server = ? AND name = ?", m.server, name).Delete(&monitorRow{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete monitor: %w", err)
+		}
+	}
+	delete(m.monitors, name)
+	return nil
+}
+
+// setStatus records the result of a check, returning the previous state;
+// monitors deleted during the check are left out
+func (m *monitors) setStatus(name string, status *MonitorStatus) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	monitor, ok := m.monitors[name]
+	if !ok {
+		return "", false
+	}
+	previous := MonitorPending
+	if monitor.Status != nil {
+		previous = monitor.Status.State
+	}
+	monitor.Status = status
+	return previous, true
+}
+
+// validateMonitor checks a definition: a monitor needs a table and at least
+// one expectation
+func validateMonitor(monitor *Monitor) error {
+	if !toolNamePattern.MatchString(monitor.Name) {
+		return fmt.Errorf("%w: name must match %s", ErrInvalidMonitor, toolNamePattern)
+	}
+	if monitor.Table == "" {
+		return fmt.Errorf("%w: table is required", ErrInvalidMonitor)
+	}
+	if monitor.MinRows == nil && monitor.MaxStaleness == "" {
+		return fmt.Errorf("%w: min_rows or max_staleness is required", ErrInvalidMonitor)
+	}
+	if monitor.MinRows != nil && *monitor.MinRows < 0 {
+		return fmt.Errorf("%w: min_rows must not be negative", ErrInvalidMonitor)
+	}
+	if (monitor.MaxStaleness == "") != (monitor.TimestampColumn == "") {
+		return fmt.Errorf("%w: max_staleness and timestamp_column go together", ErrInvalidMonitor)
+	}
+	if monitor.MaxStaleness != "" {
+		staleness, err := time.ParseDuration(monitor.MaxStaleness)
+		if err != nil || staleness <= 0 {
+			return fmt.Errorf("%w: invalid max_staleness %q", ErrInvalidMonitor, monitor.MaxStaleness)
+		}
+		monitor.staleness = staleness
+	}
+	return nil
+}
+
+// runMonitors checks the monitors on the configured interval until the
+// server stops
+func (s *MCPServerWithDB) runMonitors() {
+	check := func() {
+		for _, monitor := range s.monitors.list() {
+			s.checkMonitor(s.ctx, monitor)
+		}
+	}
+	check()
+
+	ticker := time.NewTicker(s.monitors.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+// checkMonitor evaluates a monitor's expectations, publishing an event when
+// it starts failing or recovers, and returns its new status
+func (s *MCPServerWithDB) checkMonitor(ctx context.Context, monitor *Monitor) *MonitorStatus {
+	now := time.Now().UTC()
+	status := &MonitorStatus{State: MonitorOK, CheckedAt: &now}
+
+	count, latest, err := s.measureTable(ctx, monitor)
+	switch {
+	case err != nil:
+		status.State, status.Error = MonitorError, err.Error()
+	default:
+		status.RowCount, status.LatestAt = &count, latest
+		if monitor.MinRows != nil && count < *monitor.MinRows {
+			status.Failures = append(status.Failures, fmt.Sprintf("%d rows, expected at least %d", count, *monitor.MinRows))
+		}
+		if monitor.staleness > 0 {
+			switch {
+			case latest == nil:
+				status.Failures = append(status.Failures, fmt.Sprintf("no %s value", monitor.TimestampColumn))
+			case now.Sub(*latest) > monitor.staleness:
+				status.Failures = append(status.Failures, fmt.Sprintf("newest %s is %s old, expected at most %s",
+					monitor.TimestampColumn, now.Sub(*latest).Round(time.Second), monitor.MaxStaleness))
+			}
+		}
+		if len(status.Failures) > 0 {
+			status.State = MonitorFailing
+		}
+	}
+
+	previous, ok := s.monitors.setStatus(monitor.Name, status)
+	if !ok {
+		return status
+	}
+	data := map[string]interface{}{"monitor": monitor.Name, "table": monitor.Table}
+	switch {
+	case status.State != MonitorOK && (previous == MonitorOK || previous == MonitorPending):
+		data["state"] = status.State
+		if status.Error != "" {
+			data["error"] = status.Error
+		} else {
+			data["failures"] = status.Failures
+		}
+		log.Printf("Warning: Monitor %s of table %s is %s", monitor.Name, monitor.Table, status.State)
+		s.publish(events.TypeMonitorFailed, data)
+	case status.State == MonitorOK && previous != MonitorOK && previous != MonitorPending:
+		s.publish(events.TypeMonitorRecovered, data)
+	}
+	return status
+}
+
+// measureTable returns the row count of a monitor's table and the newest
+// value of its timestamp column
+func (s *MCPServerWithDB) measureTable(ctx context.Context, monitor *Monitor) (int64, *time.Time, error) {
+	d := connector.DialectOf(s.DBConn)
+	selects := "COUNT(*) AS " + d.QuoteIdentifier("ROW_COUNT")
+	if monitor.TimestampColumn != "" {
+		selects += fmt.Sprintf(", MAX(%s) AS %s", d.QuoteIdentifier(monitor.TimestampColumn), d.QuoteIdentifier("LATEST"))
+	}
+	rows, err := s.executeQuery(ctx, "monitor", fmt.Sprintf("SELECT %s FROM %s", selects, d.Table(monitor.Table)), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(rows) != 1 {
+		return 0, nil, fmt.Errorf("expected one row, got %d", len(rows))
+	}
+	count, err := countValue(rows[0]["ROW_COUNT"])
+	if err != nil {
+		return 0, nil, err
+	}
+	if monitor.TimestampColumn == "" || rows[0]["LATEST"] == nil {
+		return count, nil, nil
+	}
+	latest, err := monitorTime(rows[0]["LATEST"])
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s: %w", monitor.TimestampColumn, err)
+	}
+	return count, &latest, nil
+}
+
+// countValue converts a row count as returned by a connector
+func countValue(v interface{}) (int64, error) {
+	switch x := v.(type) {
+	case int64:
+		return x, nil
+	case int:
+		return int64(x), nil
+	case float64:
+		return int64(x), nil
+	case string:
+		return strconv.ParseInt(x, 10, 64)
+	}
+	return 0, fmt.Errorf("unexpected row count %v", v)
+}
+
+// monitorTimeLayouts are the timestamp formats connectors return
+var monitorTimeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999 -0700", "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// monitorTime converts a timestamp as returned by a connector; numbers are
+// Unix times in seconds
+func monitorTime(v interface{}) (time.Time, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case int64:
+		return time.Unix(x, 0), nil
+	case float64:
+		return time.Unix(int64(x), 0), nil
+	case string:
+		for _, layout := range monitorTimeLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(x)); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %v", v)
+}
+
+// setupMonitorRoutes configures the monitor routes; monitors are created
+// and deleted through admin routes
+func (s *MCPServerWithDB) setupMonitorRoutes(router *gin.RouterGroup) {
+	if s.monitors == nil {
+		return
+	}
+
+	router.GET("/monitors", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.monitors.list())
+	})
+
+	router.GET("/monitors/:name", func(c *gin.Context) {
+		monitor, err := s.monitors.get(c.Param("name"))
+		if err != nil {
+			c.JSON(monitorErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to get monitor: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, monitor)
+	})
+
+	router.POST("/admin/monitors", func(c *gin.Context) {
+		var monitor Monitor
+		if err := c.ShouldBindJSON(&monitor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if err := s.monitors.create(c.Request.Context(), &monitor); err != nil {
+			c.JSON(monitorErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to create monitor: %v", err)})
+			return
+		}
+		c.JSON(http.StatusCreated, monitor)
+	})
+
+	router.DELETE("/admin/monitors/:name", func(c *gin.Context) {
+		if err := s.monitors.delete(c.Request.Context(), c.Param("name")); err != nil {
+			c.JSON(monitorErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to delete monitor: %v", err)})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	// Checks a monitor now instead of waiting for the next interval
+	router.POST("/monitors/:name/check", func(c *gin.Context) {
+		monitor, err := s.monitors.get(c.Param("name"))
+		if err != nil {
+			c.JSON(monitorErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to check monitor: %v", err)})
+			return
+		}
+		monitor.Status = s.checkMonitor(c.Request.Context(), monitor)
+		c.JSON(http.StatusOK, monitor)
+	})
+}
+
+// monitorErrorStatus maps monitor errors to HTTP status codes
+func monitorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrMonitorNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrMonitorExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidMonitor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+)
+
+func TestMonitors(t *testing.T) {
+	store, err := state.OpenAndMigrate(&state.Config{DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	defer store.Close()
+
+	var mu sync.Mutex
+	var published []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event events.Event
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		mu.Lock()
+		published = append(published, event.Type)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+	bus, err := events.NewBus(&events.Config{Sinks: []events.SinkConfig{{Type: "webhook", URL: hook.URL}}})
+	require.NoError(t, err)
+
+	m, err := newMonitors("sales", &MonitorConfig{Interval: "1h"}, store.DB)
+	require.NoError(t, err)
+	conn := &rowsConnector{rows: []map[string]interface{}{
+		{"ROW_COUNT": 5.0, "LATEST": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)},
+	}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, Events: bus, monitors: m}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupMonitorRoutes(router.Group("/api"))
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, bytes.NewBufferString(body)))
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/api/admin/monitors", `{"name":"orders","table":"ORDERS"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/api/admin/monitors", `{"name":"orders","table":"ORDERS","max_staleness":"6h"}`).Code)
+	w := request(http.MethodPost, "/api/admin/monitors", `{"name":"orders","table":"ORDERS","min_rows":3,"timestamp_column":"UPDATED_AT","max_staleness":"6h"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusConflict, request(http.MethodPost, "/api/admin/monitors", `{"name":"orders","table":"ORDERS","min_rows":1}`).Code)
+
+	check := func() *MonitorStatus {
+		w := request(http.MethodPost, "/api/monitors/orders/check", "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var monitor Monitor
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &monitor))
+		return monitor.Status
+	}
+	status := check()
+	assert.Equal(t, MonitorOK, status.State)
+	assert.EqualValues(t, 5, *status.RowCount)
+
+	// Too few rows and stale data fail the monitor once, until it recovers
+	conn.rows = []map[string]interface{}{{"ROW_COUNT": 1.0, "LATEST": "2020-01-01 00:00:00"}}
+	status = check()
+	assert.Equal(t, MonitorFailing, status.State)
+	assert.Len(t, status.Failures, 2)
+	check()
+	conn.rows = []map[string]interface{}{{"ROW_COUNT": 5.0, "LATEST": time.Now().UTC().Format(time.RFC3339)}}
+	assert.Equal(t, MonitorOK, check().State)
+
+	require.NoError(t, bus.Close())
+	mu.Lock()
+	assert.Equal(t, []string{events.TypeMonitorFailed, events.TypeMonitorRecovered}, published)
+	mu.Unlock()
+
+	// Monitors survive a restart
+	reloaded, err := newMonitors("sales", &MonitorConfig{}, store.DB)
+	require.NoError(t, err)
+	require.NoError(t, reloaded.load(context.Background()))
+	monitor, err := reloaded.get("orders")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, *monitor.MinRows)
+	assert.Equal(t, MonitorPending, monitor.Status.State)
+
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/api/admin/monitors/orders", "").Code)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/api/monitors/orders", "").Code)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// forwardedForHeader lists the client and proxies a request went through
+const forwardedForHeader = "X-Forwarded-For"
+
+// NetworkPolicyConfig restricts the addresses clients may call the API, MCP
+// and gRPC transports from. The most specific list applies: the list of
+// the caller's API key, else of its tenant, else AllowCIDRs. Ranges are
+// CIDRs or single addresses.
+type NetworkPolicyConfig struct {
+	// AllowCIDRs are the client ranges allowed; empty allows any
+	AllowCIDRs []string `json:"allow_cidrs,omitempty"`
+
+	// Tenants are the client ranges allowed for the API keys of tenants
+	Tenants map[string][]string `json:"tenants,omitempty"`
+
+	// APIKeys are the client ranges allowed for tenant API keys, by key ID
+	APIKeys map[string][]string `json:"api_keys,omitempty"`
+
+	// TrustedProxies are the ranges of the proxies in front of the server,
+	// whose X-Forwarded-For header is trusted for the client address
+	TrustedProxies []string `json:"trusted_proxies,omitempty"`
+}
+
+// networkPolicy is a validated NetworkPolicyConfig
+type networkPolicy struct {
+	allow   []netip.Prefix
+	tenants map[string][]netip.Prefix
+	keys    map[string][]netip.Prefix
+	proxies []netip.Prefix
+}
+
+// newNetworkPolicy validates a network policy configuration; nil allows
+// every client
+func newNetworkPolicy(cfg *NetworkPolicyConfig) (*networkPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	p := &networkPolicy{
+		tenants: make(map[string][]netip.Prefix),
+		keys:    make(map[string][]netip.Prefix),
+	}
+	var err error
+	if p.allow, err = parsePrefixes(cfg.AllowCIDRs); err != nil {
+		return nil, err
+	}
+	if p.proxies, err = parsePrefixes(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	for tenant, ranges := range cfg.Tenants {
+		if p.tenants[tenant], err = parsePrefixes(ranges); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+	}
+	for key, ranges := range cfg.APIKeys {
+		if p.keys[key], err = parsePrefixes(ranges); err != nil {
+			return nil, fmt.Errorf("API key %s: %w", key, err)
+		}
+	}
+	return p, nil
+}
+
+// parsePrefixes parses CIDRs and single addresses
+func parsePrefixes(ranges []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(ranges))
+	for _, r := range ranges {
+		r = strings.TrimSpace(r)
+		if !strings.Contains(r, "/") {
+			addr, err := netip.ParseAddr(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address %q", r)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q", r)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()).Masked())
+	}
+	return prefixes, nil
+}
+
+// containsAddr reports whether an address is in one of the ranges
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddr returns the address of the client of a request: the peer
+// address, or the last address of X-Forwarded-For not added by a trusted
+// proxy when the peer is one
+func (p *networkPolicy) clientAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr = addr.Unmap()
+
+	var hops []string
+	for _, header := range r.Header.Values(forwardedForHeader) {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0 && containsAddr(p.proxies, addr); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		addr = hop.Unmap()
+	}
+	return addr, true
+}
+
+// allows reports whether the client of a request may call the server
+func (p *networkPolicy) allows(r *http.Request) bool {
+	ranges := p.allow
+	if key := authenticatedKey(r.Context()); key != nil {
+		if tenantRanges, ok := p.tenants[key.Tenant]; ok {
+			ranges = tenantRanges
+		}
+		if keyRanges, ok := p.keys[key.ID]; ok {
+			ranges = keyRanges
+		}
+	}
+	if len(ranges) == 0 {
+		return true
+	}
+	addr, ok := p.clientAddr(r)
+	return ok && containsAddr(ranges, addr)
+}
+
+// networkPolicyMiddleware rejects REST and MCP requests from clients
+// outside the network policy
+func (s *MCPServerWithDB) networkPolicyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if s.network != nil && !s.network.allows(c.Request) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Client address not allowed by the network policy",
+				"code":  CodePolicyDenied,
+			})
+			return
+		}
+		c.Next()
+	}
+}
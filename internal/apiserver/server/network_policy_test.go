@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkPolicy(t *testing.T) {
+	policy, err := newNetworkPolicy(&NetworkPolicyConfig{
+		AllowCIDRs:     []string{"10.0.0.0/8", "2001:db8::/32"},
+		Tenants:        map[string][]string{"acme": {"192.0.2.0/24"}},
+		APIKeys:        map[string][]string{"key-ci": {"198.51.100.7"}},
+		TrustedProxies: []string{"172.16.0.1"},
+	})
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, network: policy}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.networkPolicyMiddleware())
+	router.GET("/tables", func(c *gin.Context) { c.Status(http.StatusOK) })
+	call := func(remote, forwarded string, key *APIKey) int {
+		req := httptest.NewRequest(http.MethodGet, "/tables", nil)
+		req.RemoteAddr = remote
+		if forwarded != "" {
+			req.Header.Set(forwardedForHeader, forwarded)
+		}
+		if key != nil {
+			req = req.WithContext(context.WithValue(req.Context(), apiKeyKey{}, key))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, call("10.1.2.3:5000", "", nil))
+	assert.Equal(t, http.StatusOK, call("[2001:db8::1]:5000", "", nil))
+	assert.Equal(t, http.StatusForbidden, call("203.0.113.9:5000", "", nil))
+
+	// Only trusted proxies may forward the client address
+	assert.Equal(t, http.StatusOK, call("172.16.0.1:5000", "203.0.113.9, 10.1.2.3", nil))
+	assert.Equal(t, http.StatusForbidden, call("172.16.0.1:5000", "10.1.2.3, 203.0.113.9", nil))
+	assert.Equal(t, http.StatusForbidden, call("203.0.113.9:5000", "10.1.2.3", nil))
+
+	// Tenant and key ranges replace the server's
+	analyst := &APIKey{ID: "key-analyst", Tenant: "acme"}
+	assert.Equal(t, http.StatusOK, call("192.0.2.10:5000", "", analyst))
+	assert.Equal(t, http.StatusForbidden, call("10.1.2.3:5000", "", analyst))
+	ci := &APIKey{ID: "key-ci", Tenant: "acme"}
+	assert.Equal(t, http.StatusOK, call("198.51.100.7:5000", "", ci))
+	assert.Equal(t, http.StatusForbidden, call("192.0.2.10:5000", "", ci))
+
+	_, err = newNetworkPolicy(&NetworkPolicyConfig{AllowCIDRs: []string{"10.0.0.0/33"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/version"
+)
+
+// colonParamPattern matches :param placeholders in endpoint paths
+var colonParamPattern = regexp.MustCompile(`:([^/]+)`)
+
+// OpenAPISpec describes generated endpoints served under prefix as an
+// OpenAPI 3.1 document. Parameters come from the endpoints' parameter
+// definitions and request bodies from the columns of create and update
+// endpoints.
+func OpenAPISpec(title, prefix string, endpoints []connector.APIEndpoint) map[string]any {
+	paths := make(map[string]any)
+	for _, e := range endpoints {
+		path := prefix + colonParamPattern.ReplaceAllString(e.Path, "{$1}")
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		item[strings.ToLower(e.Method)] = openAPIOperation(e)
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":   title,
+			"version": version.Get(),
+		},
+		"paths": paths,
+	}
+}
+
+// openAPIOperation describes one generated endpoint
+func openAPIOperation(e connector.APIEndpoint) map[string]any {
+	var parameters []any
+	for _, p := range e.ParamDefs() {
+		if p.In != connector.ParamInPath && p.In != connector.ParamInQuery {
+			continue
+		}
+		schema := map[string]any{"type": p.Type}
+		if p.Default != nil {
+			schema["default"] = p.Default
+		}
+		param := map[string]any{"name": p.Name, "in": p.In, "schema": schema}
+		if p.Required {
+			param["required"] = true
+		}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		parameters = append(parameters, param)
+	}
+
+	op := map[string]any{
+		"summary": e.Description,
+		"responses": map[string]any{
+			"200": map[string]any{
+				"description": "Rows returned by the endpoint's query",
+				"content": map[string]any{
+					"application/json": map[string]any{
+						"schema": map[string]any{"type": "array", "items": map[string]any{"type": "object"}},
+					},
+				},
+			},
+		},
+	}
+	if e.Warning != "" {
+		op["description"] = "Warning: " + e.Warning
+	}
+	if e.Table != "" {
+		op["tags"] = []string{e.Table}
+	}
+	if e.Operation != "" && e.Table != "" {
+		op["operationId"] = e.Operation + "_" + tableToolBase(e.Table)
+	}
+	if len(parameters) > 0 {
+		op["parameters"] = parameters
+	}
+
+	if len(e.Columns) > 0 {
+		properties := make(map[string]any, len(e.Columns))
+		for _, col := range e.Columns {
+			properties[col.Name] = columnSchema(col)
+		}
+		var required []string
+		for _, p := range e.ParamDefs() {
+			if p.In == connector.ParamInBody && p.Required {
+				required = append(required, p.Name)
+			}
+		}
+		body := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			body["required"] = required
+		}
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{"application/json": map[string]any{"schema": body}},
+		}
+	}
+	return op
+}
+
+// setupOpenAPIRoutes configures the endpoint serving the OpenAPI document of
+// the generated endpoints
+func (s *MCPServerWithDB) setupOpenAPIRoutes(router *gin.RouterGroup) {
+	router.GET("/openapi.json", func(c *gin.Context) {
+		var endpoints []connector.APIEndpoint
+		if s.routes != nil {
+			endpoints = s.routes.Endpoints()
+		}
+		c.JSON(http.StatusOK, OpenAPISpec(s.Config.Name, s.apiPrefix, endpoints))
+	})
+}
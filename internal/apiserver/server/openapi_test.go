@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	spec := OpenAPISpec("sales", "/api/db", []connector.APIEndpoint{
+		{
+			Table: "ORDERS", Operation: connector.OperationList, Method: "GET", Path: "/ORDERS",
+			Parameters: map[string]interface{}{"limit": "Number of records to return", "offset": "Number of records to skip"},
+		},
+		{
+			Table: "ORDERS", Operation: connector.OperationUpdate, Method: "PUT", Path: "/ORDERS/{ID}",
+			Parameters: map[string]interface{}{"ID": "ID of the ORDERS record"},
+			Columns:    []connector.Column{{Name: "STATUS", Type: "VARCHAR"}, {Name: "NOTE", Type: "VARCHAR", Nullable: true}},
+		},
+	})
+
+	paths := spec["paths"].(map[string]any)
+	require.Len(t, paths, 2)
+	list := paths["/api/db/ORDERS"].(map[string]any)["get"].(map[string]any)
+	assert.Equal(t, "list_orders", list["operationId"])
+	params := list["parameters"].([]any)
+	require.Len(t, params, 2)
+	assert.Equal(t, "limit", params[0].(map[string]any)["name"])
+	assert.Equal(t, "query", params[0].(map[string]any)["in"])
+
+	update := paths["/api/db/ORDERS/{ID}"].(map[string]any)["put"].(map[string]any)
+	param := update["parameters"].([]any)[0].(map[string]any)
+	assert.Equal(t, "path", param["in"])
+	assert.Equal(t, true, param["required"])
+	body := update["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	assert.Equal(t, []string{"STATUS"}, body["required"])
+	assert.Contains(t, body["properties"], "NOTE")
+}
+
+func TestUIRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	serve := func(enabled bool) *httptest.ResponseRecorder {
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", EnableUI: enabled}}
+		router := gin.New()
+		s.setupUIRoutes(router.Group("/api/db"))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/db/ui", nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve(false).Code)
+	w := serve(true)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "openapi.json")
+}
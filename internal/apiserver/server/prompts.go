@@ -0,0 +1,85 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+)
+
+const (
+	actionPromptUpdate   = "prompt_update"
+	actionPromptRollback = "prompt_rollback"
+)
+
+// setupPromptRoutes configures the admin routes for editing prompt templates
+func (s *MCPServerWithDB) setupPromptRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/prompts", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.Prompts.List())
+	})
+
+	router.GET("/admin/prompts/:name", func(c *gin.Context) {
+		versions, err := s.Prompts.Versions(c.Param("name"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, versions)
+	})
+
+	router.PUT("/admin/prompts/:name", func(c *gin.Context) {
+		var request struct {
+			Template string `json:"template" binding:"required"`
+			Comment  string `json:"comment"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		principal := principalFromContext(c)
+		t, err := s.Prompts.Update(c.Param("name"), request.Template, request.Comment, principal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to update prompt template: %v", err)})
+			return
+		}
+
+		_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+			Action:    actionPromptUpdate,
+			Principal: principal,
+			Resource:  t.Name,
+			Details: map[string]interface{}{
+				"version": t.Version,
+				"comment": t.Comment,
+			},
+		})
+		c.JSON(http.StatusCreated, t)
+	})
+
+	router.POST("/admin/prompts/:name/rollback", func(c *gin.Context) {
+		var request struct {
+			Version int `json:"version" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		t, err := s.Prompts.Activate(c.Param("name"), request.Version)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to roll back prompt template: %v", err)})
+			return
+		}
+
+		_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+			Action:    actionPromptRollback,
+			Principal: principalFromContext(c),
+			Resource:  t.Name,
+			Details: map[string]interface{}{
+				"version": t.Version,
+			},
+		})
+		c.JSON(http.StatusOK, t)
+	})
+}
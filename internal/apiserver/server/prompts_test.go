@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/prompt"
+)
+
+func TestPromptRoutes(t *testing.T) {
+	prompts, err := prompt.NewRegistry(nil, nil)
+	require.NoError(t, err)
+	recorder := audit.NewMemoryRecorder(10)
+	s := &MCPServerWithDB{
+		Config:  &MCPServerConfig{Name: "sales", Admin: &AdminConfig{JWTSecret: "secret"}},
+		DBConn:  &paramsConnector{},
+		Audit:   recorder,
+		Prompts: prompts,
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	admin := adminToken(t, "admin")
+	call := func(method, target, bearer, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if bearer != "" {
+			r.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder, v interface{}) {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), v), w.Body.String())
+	}
+	target := "/admin/prompts/" + prompt.NameResultSummary
+
+	// Prompts can only be read and edited by admins
+	for _, bearer := range []string{"", adminToken(t, "agent")} {
+		assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/admin/prompts", bearer, "").Code)
+		assert.Equal(t, http.StatusForbidden, call(http.MethodPut, target, bearer, `{"template": "Be terse."}`).Code)
+		assert.Equal(t, http.StatusForbidden, call(http.MethodPost, target+"/rollback", bearer, `{"version": 1}`).Code)
+	}
+
+	w := call(http.MethodGet, "/admin/prompts", admin, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list []prompt.Template
+	decode(w, &list)
+	assert.NotEmpty(t, list)
+
+	// Edits add an active version
+	w = call(http.MethodPut, target, admin, `{"template": "Answer {{.Question}} tersely.", "comment": "shorter"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var updated prompt.Template
+	decode(w, &updated)
+	assert.Equal(t, 2, updated.Version)
+	assert.True(t, updated.Active)
+	assert.Equal(t, "ops", updated.Author)
+
+	w = call(http.MethodGet, target, admin, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var versions []prompt.Template
+	decode(w, &versions)
+	require.Len(t, versions, 2)
+	assert.False(t, versions[0].Active)
+	assert.True(t, versions[1].Active)
+
+	// Invalid edits and unknown prompts or versions are rejected
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, target, admin, `{"template": "{{.Question"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, target, admin, `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPut, "/admin/prompts/unknown", admin, `{"template": "x"}`).Code)
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, "/admin/prompts/unknown", admin, "").Code)
+	assert.Equal(t, http.StatusBadRequest, call(http.MethodPost, target+"/rollback", admin, `{"version": 7}`).Code)
+
+	// Rollbacks reactivate an earlier version
+	w = call(http.MethodPost, target+"/rollback", admin, `{"version": 1}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	active, err := prompts.Get(prompt.NameResultSummary)
+	require.NoError(t, err)
+	assert.Equal(t, 1, active.Version)
+
+	events, err := recorder.List(context.Background(), audit.Filter{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, actionPromptRollback, events[0].Action)
+	assert.Equal(t, actionPromptUpdate, events[1].Action)
+	assert.Equal(t, prompt.NameResultSummary, events[1].Resource)
+}
@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const actionQueryCancel = "query_cancel"
+
+// ErrNotQueryOwner is returned when cancelling the query of another caller
+var ErrNotQueryOwner = errors.New("query belongs to another caller")
+
+// States of a RunningQuery
+const (
+	QueryQueued  = "queued"
+	QueryRunning = "running"
+)
+
+// RunningQuery describes a query being executed or waiting in the queue
+type RunningQuery struct {
+	ID         string    `json:"id"`
+	Source     string    `json:"source"`
+	Query      string    `json:"query"`
+	Principal  string    `json:"principal,omitempty"`
+	Priority   string    `json:"priority"`
+	State      string    `json:"state"`
+	Position   int       `json:"position,omitempty"`
+	DatabaseID string    `json:"database_id,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	ElapsedMs  int64     `json:"elapsed_ms"`
+}
+
+// runningQuery is a tracked query with the function cancelling its context
+type runningQuery struct {
+	info   RunningQuery
+	cancel context.CancelFunc
+}
+
+// queryTracker keeps the queries a server is executing so they can be
+// listed and cancelled, and queues them when their number is bounded
+type queryTracker struct {
+	mu      sync.Mutex
+	queries map[string]*runningQuery
+	queue   *queryQueue
+}
+
+// newQueryTracker creates a tracker; a nil queue leaves queries unbounded
+func newQueryTracker(queue *queryQueue) *queryTracker {
+	return &queryTracker{queries: make(map[string]*runningQuery), queue: queue}
+}
+
+// start assigns a query an ID and tracks it until the returned function is
+// called, waiting for a slot in the queue first. The returned context is
+// cancelled when the query is. A nil tracker tracks nothing.
+func (t *queryTracker) start(ctx context.Context, source, query string) (context.Context, func(), error) {
+	if t == nil {
+		return ctx, func() {}, nil
+	}
+
+	state := QueryRunning
+	if t.queue != nil {
+		state = QueryQueued
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	q := &runningQuery{
+		info: RunningQuery{
+			ID:        uuid.New().String(),
+			Source:    source,
+			Query:     query,
+			Principal: callerTag(ctx).Principal,
+			Priority:  queryPriority(ctx, source),
+			State:     state,
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+	}
+	id := q.info.ID
+	ctx = connector.WithQueryIDReporter(ctx, func(databaseID string) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		q.info.DatabaseID = databaseID
+	})
+
+	t.mu.Lock()
+	t.queries[id] = q
+	t.mu.Unlock()
+
+	finish := func() {
+		t.mu.Lock()
+		delete(t.queries, id)
+		t.mu.Unlock()
+		cancel()
+	}
+	if t.queue == nil {
+		return ctx, finish, nil
+	}
+
+	if err := t.queue.acquire(ctx, id, q.info.Priority); err != nil {
+		finish()
+		return nil, nil, err
+	}
+	t.mu.Lock()
+	q.info.State = QueryRunning
+	t.mu.Unlock()
+	return ctx, func() {
+		t.queue.release()
+		finish()
+	}, nil
+}
+
+// list returns the running and queued queries of a principal, or of every
+// principal when it is empty, oldest first
+func (t *queryTracker) list(principal string) []RunningQuery {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var positions map[string]int
+	if t.queue != nil {
+		positions = t.queue.positions()
+	}
+	now := time.Now()
+	queries := make([]RunningQuery, 0, len(t.queries))
+	for _, q := range t.queries {
+		if principal != "" && q.info.Principal != principal {
+			continue
+		}
+		info := q.info
+		info.ElapsedMs = now.Sub(info.StartedAt).Milliseconds()
+		if info.State == QueryQueued {
+			info.Position = positions[info.ID]
+		}
+		queries = append(queries, info)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].StartedAt.Before(queries[j].StartedAt)
+	})
+	return queries
+}
+
+// cancel cancels the context of a running query of a principal, or of any
+// principal when it is empty, and returns it. It fails with
+// ErrNotQueryOwner for the queries of other principals.
+func (t *queryTracker) cancel(id, principal string) (RunningQuery, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q, ok := t.queries[id]
+	if !ok {
+		return RunningQuery{}, false, nil
+	}
+	if principal != "" && q.info.Principal != principal {
+		return RunningQuery{}, true, ErrNotQueryOwner
+	}
+	q.cancel()
+	info := q.info
+	info.ElapsedMs = time.Since(info.StartedAt).Milliseconds()
+	return info, true, nil
+}
+
+// queryOwner returns the principal whose queries a caller may list and
+// cancel; admins may list and cancel every query
+func (s *MCPServerWithDB) queryOwner(c *gin.Context) string {
+	if _, ok := newAdminAuth(s.Config.Admin, s.rowSecurity).admits(c); ok {
+		return ""
+	}
+	return principalFromContext(c)
+}
+
+// setupQueryRoutes configures the routes listing and cancelling running and
+// queued queries
+func (s *MCPServerWithDB) setupQueryRoutes(router *gin.RouterGroup) {
+	if s.queries == nil {
+		return
+	}
+
+	router.GET("/queries", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.queries.list(s.queryOwner(c)))
+	})
+
+	router.DELETE("/queries/:id", func(c *gin.Context) {
+		query, ok, err := s.queries.cancel(c.Param("id"), s.queryOwner(c))
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Query not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Cancelling the context abandons the query; the database keeps
+		// running it unless the connector can cancel it there too
+		if canceler, ok := s.DBConn.(connector.QueryCanceler); ok && query.DatabaseID != "" {
+			if err := canceler.CancelQuery(c.Request.Context(), query.DatabaseID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to cancel query: %v", err)})
+				return
+			}
+		}
+
+		if s.Audit != nil {
+			_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+				Action:    actionQueryCancel,
+				Principal: principalFromContext(c),
+				Resource:  s.Config.Name,
+				Details: map[string]interface{}{
+					"query_id": query.ID,
+					"source":   query.Source,
+					"query":    query.Query,
+				},
+			})
+		}
+
+		c.JSON(http.StatusOK, query)
+	})
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// blockingConnector runs queries until they are cancelled
+type blockingConnector struct {
+	rowsConnector
+	started   chan struct{}
+	cancelled string
+}
+
+func (c *blockingConnector) ExecuteQuery(ctx context.Context, _ string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+	connector.QueryIDReporterFromContext(ctx)("01b2-db-id")
+	close(c.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *blockingConnector) CancelQuery(_ context.Context, id string) error {
+	c.cancelled = id
+	return nil
+}
+
+func TestCancelRunningQuery(t *testing.T) {
+	conn := &blockingConnector{started: make(chan struct{})}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", Admin: &AdminConfig{JWTSecret: "secret"}}, DBConn: conn, queries: newQueryTracker(nil)}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupQueryRoutes(router.Group(""))
+	// Requests come from 192.0.2.1 unless another caller's address is given
+	request := func(method, target, addr, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, nil)
+		if addr != "" {
+			r.RemoteAddr = addr
+		}
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	list := func(addr, token string) []RunningQuery {
+		w := request(http.MethodGet, "/queries", addr, token)
+		require.Equal(t, http.StatusOK, w.Code)
+		var running []RunningQuery
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &running))
+		return running
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		ctx := connector.WithQueryTag(context.Background(), s.queryTag("rest", "", "anonymous@192.0.2.1", "", ""))
+		_, err := s.executeQuery(ctx, "rest", "SELECT * FROM ORDERS", nil)
+		errs <- err
+	}()
+	<-conn.started
+
+	running := list("", "")
+	require.Len(t, running, 1)
+	assert.Equal(t, "SELECT * FROM ORDERS", running[0].Query)
+	assert.Equal(t, "anonymous@192.0.2.1", running[0].Principal)
+	assert.Equal(t, "01b2-db-id", running[0].DatabaseID)
+
+	// Other callers neither see nor cancel the query; admins see every query
+	assert.Empty(t, list("198.51.100.7:1234", ""))
+	assert.Equal(t, http.StatusForbidden, request(http.MethodDelete, "/queries/"+running[0].ID, "198.51.100.7:1234", "").Code)
+	assert.Empty(t, conn.cancelled)
+	assert.Len(t, list("198.51.100.7:1234", adminToken(t, "admin")), 1)
+
+	w := request(http.MethodDelete, "/queries/"+running[0].ID, "", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "01b2-db-id", conn.cancelled)
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("query was not cancelled")
+	}
+
+	// Finished queries are no longer listed
+	assert.Empty(t, s.queries.list(""))
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/queries/"+running[0].ID, "", "").Code)
+}
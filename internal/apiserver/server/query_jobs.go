@@ -0,0 +1,388 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultQueryJobConcurrency = 4
+	defaultQueryJobRetention   = time.Hour
+
+	// maxQueryJobs is the number of jobs kept; the oldest finished jobs are
+	// forgotten first
+	maxQueryJobs = 1000
+
+	defaultJobPageSize = 1000
+	maxJobPageSize     = 10000
+)
+
+// Query job states
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCanceled  = "canceled"
+)
+
+// ErrJobNotFound is returned for unknown jobs and jobs of other callers
+var ErrJobNotFound = errors.New("job not found")
+
+// QueryJobConfig enables running free-form queries as background jobs, so
+// that clients poll for long-running warehouse queries instead of holding a
+// connection open
+type QueryJobConfig struct {
+	// MaxConcurrent is the number of jobs run at once (default: 4)
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+
+	// Retention is how long the results of a finished job are kept
+	// (default: 1h)
+	Retention string `json:"retention,omitempty"`
+}
+
+// QueryJob is the state and progress of a query run in the background
+type QueryJob struct {
+	ID         string     `json:"id"`
+	State      string     `json:"state"`
+	Query      string     `json:"query"`
+	Principal  string     `json:"principal"`
+	Rows       int        `json:"rows"`
+	ElapsedMs  int64      `json:"elapsed_ms"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+
+	params map[string]interface{}
+	result *connector.ResultSet
+	cancel context.CancelFunc
+}
+
+// finished reports whether a job ran to an end
+func (j *QueryJob) finished() bool {
+	return j.State == JobSucceeded || j.State == JobFailed || j.State == JobCanceled
+}
+
+// snapshot returns a copy of a job with its elapsed time
+func (j *QueryJob) snapshot() QueryJob {
+	job := *j
+	switch {
+	case j.FinishedAt != nil:
+		job.ElapsedMs = j.FinishedAt.Sub(*j.StartedAt).Milliseconds()
+	case j.StartedAt != nil:
+		job.ElapsedMs = time.Since(*j.StartedAt).Milliseconds()
+	}
+	return job
+}
+
+// QueryJobPage is a page of the rows of a finished job
+type QueryJobPage struct {
+	Columns    []string                 `json:"columns,omitempty"`
+	Rows       []map[string]interface{} `json:"rows"`
+	Offset     int                      `json:"offset"`
+	Total      int                      `json:"total"`
+	NextOffset *int                     `json:"next_offset,omitempty"`
+}
+
+// queryJobs runs query jobs in the background and keeps their results
+type queryJobs struct {
+	retention time.Duration
+	slots     chan struct{}
+
+	mu    sync.Mutex
+	jobs  map[string]*QueryJob
+	order []string
+}
+
+// newQueryJobs creates the job runner of a configuration; nil disables
+// query jobs and returns a nil runner
+func newQueryJobs(cfg *QueryJobConfig) (*queryJobs, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.MaxConcurrent < 0 {
+		return nil, fmt.Errorf("max_concurrent must not be negative")
+	}
+	j := &queryJobs{
+		retention: defaultQueryJobRetention,
+		slots:     make(chan struct{}, defaultQueryJobConcurrency),
+		jobs:      make(map[string]*QueryJob),
+	}
+	if cfg.MaxConcurrent > 0 {
+		j.slots = make(chan struct{}, cfg.MaxConcurrent)
+	}
+	if cfg.Retention != "" {
+		d, err := time.ParseDuration(cfg.Retention)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid retention: %s", cfg.Retention)
+		}
+		j.retention = d
+	}
+	return j, nil
+}
+
+// prune forgets expired jobs, and the oldest finished jobs when there are
+// too many; the caller holds mu
+func (j *queryJobs) prune(now time.Time) {
+	for i := 0; i < len(j.order); {
+		job := j.jobs[j.order[i]]
+		if job.finished() && (now.After(*job.ExpiresAt) || len(j.jobs) > maxQueryJobs) {
+			delete(j.jobs, j.order[i])
+			j.order = append(j.order[:i], j.order[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+// add records a new pending job
+func (j *queryJobs) add(job *QueryJob) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jobs[job.ID] = job
+	j.order = append(j.order, job.ID)
+	j.prune(time.Now())
+}
+
+// update changes a job under the lock and returns a copy of it
+func (j *queryJobs) update(id string, change func(job *QueryJob)) QueryJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job := j.jobs[id]
+	change(job)
+	return job.snapshot()
+}
+
+// get returns a copy of a job of a caller
+func (j *queryJobs) get(id, principal string) (QueryJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.prune(time.Now())
+	job, ok := j.jobs[id]
+	if !ok || job.Principal != principal {
+		return QueryJob{}, false
+	}
+	return job.snapshot(), true
+}
+
+// list returns copies of the jobs of a caller, newest first
+func (j *queryJobs) list(principal string) []QueryJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.prune(time.Now())
+	jobs := []QueryJob{}
+	for _, job := range j.jobs {
+		if job.Principal == principal {
+			jobs = append(jobs, job.snapshot())
+		}
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].CreatedAt.After(jobs[b].CreatedAt) })
+	return jobs
+}
+
+// page returns rows of a finished job of a caller from offset
+func (j *queryJobs) page(id, principal string, offset, limit int) (*QueryJobPage, QueryJob, error) {
+	job, ok := j.get(id, principal)
+	if !ok {
+		return nil, job, ErrJobNotFound
+	}
+	if job.State != JobSucceeded {
+		return nil, job, fmt.Errorf("job is %s", job.State)
+	}
+	rows := job.result.Rows
+	page := &QueryJobPage{Columns: job.result.Columns, Rows: []map[string]interface{}{}, Offset: offset, Total: len(rows)}
+	if offset < len(rows) {
+		end := offset + limit
+		if end < len(rows) {
+			page.NextOffset = &end
+		} else {
+			end = len(rows)
+		}
+		page.Rows = rows[offset:end]
+	}
+	return page, job, nil
+}
+
+// cancel cancels a job of a caller that has not finished, and forgets one
+// that has
+func (j *queryJobs) cancel(id, principal string) (QueryJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok || job.Principal != principal {
+		return QueryJob{}, false
+	}
+	if job.finished() {
+		delete(j.jobs, id)
+		for i, other := range j.order {
+			if other == id {
+				j.order = append(j.order[:i], j.order[i+1:]...)
+				break
+			}
+		}
+		return job.snapshot(), true
+	}
+	job.cancel()
+	return job.snapshot(), true
+}
+
+// startQueryJob records a query job and runs it in the background. The job
+// keeps the values of the request's context, like its caller and priority,
+// but outlives the request.
+func (s *MCPServerWithDB) startQueryJob(c *gin.Context, query string, params map[string]interface{}) QueryJob {
+	ctx := withQueryPriority(context.WithoutCancel(c.Request.Context()), queryPriority(c.Request.Context(), "job"))
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.ctx, cancel)
+	job := &QueryJob{
+		ID:        uuid.New().String(),
+		State:     JobPending,
+		Query:     query,
+		Principal: principalFromContext(c),
+		CreatedAt: time.Now().UTC(),
+		params:    params,
+		cancel:    cancel,
+	}
+	s.jobs.add(job)
+	pending := job.snapshot()
+	go func() {
+		defer stop()
+		defer cancel()
+		s.runQueryJob(ctx, job.ID)
+	}()
+	return pending
+}
+
+// runQueryJob runs a query job once a slot is free and keeps its result
+func (s *MCPServerWithDB) runQueryJob(ctx context.Context, id string) {
+	finish := func(result *connector.ResultSet, err error) {
+		job := s.jobs.update(id, func(job *QueryJob) {
+			now := time.Now().UTC()
+			expires := now.Add(s.jobs.retention)
+			if job.StartedAt == nil {
+				job.StartedAt = &now
+			}
+			job.FinishedAt, job.ExpiresAt = &now, &expires
+			switch {
+			case ctx.Err() != nil:
+				job.State = JobCanceled
+			case err != nil:
+				job.State, job.Error = JobFailed, s.publicMessage(errorCode(err), err.Error())
+			default:
+				job.State, job.result, job.Rows = JobSucceeded, result, len(result.Rows)
+			}
+		})
+		if job.State == JobFailed {
+			log.Printf("Warning: Query job %s failed: %v", id, err)
+		}
+	}
+
+	select {
+	case s.jobs.slots <- struct{}{}:
+	case <-ctx.Done():
+		finish(nil, ctx.Err())
+		return
+	}
+	defer func() { <-s.jobs.slots }()
+
+	job := s.jobs.update(id, func(job *QueryJob) {
+		now := time.Now().UTC()
+		job.State, job.StartedAt = JobRunning, &now
+	})
+	finish(s.executeOrdered(ctx, "job", job.Query, job.params))
+}
+
+// setupQueryJobRoutes configures the routes starting query jobs and
+// retrieving their progress and results. Callers only see their own jobs.
+func (s *MCPServerWithDB) setupQueryJobRoutes(router *gin.RouterGroup) {
+	if s.jobs == nil {
+		return
+	}
+
+	router.POST("/jobs/query", func(c *gin.Context) {
+		var request struct {
+			Query  string                 `json:"query"`
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if err := s.checkFreeForm(c.Request.Context()); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to start query job", err)
+			return
+		}
+		if err := s.checkQueryPolicy(c.Request.Context(), "job", request.Query, request.Params); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to start query job", err)
+			return
+		}
+		if s.parkQuery(c, request.Query, request.Params) {
+			return
+		}
+
+		job := s.startQueryJob(c, request.Query, request.Params)
+		c.Header("Location", "/jobs/query/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+	})
+
+	router.GET("/jobs/query", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.jobs.list(principalFromContext(c)))
+	})
+
+	router.GET("/jobs/query/:id", func(c *gin.Context) {
+		job, ok := s.jobs.get(c.Param("id"), principalFromContext(c))
+		if !ok {
+			s.respondError(c, http.StatusNotFound, "Failed to get query job", ErrJobNotFound)
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	})
+
+	// ?offset=0&limit=1000 pages through the rows of a succeeded job
+	router.GET("/jobs/query/:id/results", func(c *gin.Context) {
+		offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid offset: %s", c.Query("offset"))})
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultJobPageSize)))
+		if err != nil || limit <= 0 || limit > maxJobPageSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit: %s", c.Query("limit"))})
+			return
+		}
+
+		page, job, err := s.jobs.page(c.Param("id"), principalFromContext(c), offset, limit)
+		switch {
+		case errors.Is(err, ErrJobNotFound):
+			s.respondError(c, http.StatusNotFound, "Failed to get query job results", err)
+		case err != nil:
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Results are not available: %v", err), "job": job})
+		default:
+			c.JSON(http.StatusOK, page)
+		}
+	})
+
+	// Cancels a pending or running job, or discards the results of a
+	// finished one
+	router.DELETE("/jobs/query/:id", func(c *gin.Context) {
+		job, ok := s.jobs.cancel(c.Param("id"), principalFromContext(c))
+		if !ok {
+			s.respondError(c, http.StatusNotFound, "Failed to cancel query job", ErrJobNotFound)
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	})
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	jobs, err := newQueryJobs(&QueryJobConfig{})
+	require.NoError(t, err)
+	conn := &rowsConnector{rows: []map[string]interface{}{{"ID": 1}, {"ID": 2}, {"ID": 3}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, jobs: jobs, queries: newQueryTracker(nil), ctx: ctx}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupQueryJobRoutes(router.Group(""))
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+	start := func(query string) QueryJob {
+		w := request(http.MethodPost, "/jobs/query", `{"query": "`+query+`"}`)
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var job QueryJob
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		assert.Equal(t, "/jobs/query/"+job.ID, w.Header().Get("Location"))
+		return job
+	}
+	poll := func(id, state string) QueryJob {
+		var job QueryJob
+		require.Eventually(t, func() bool {
+			w := request(http.MethodGet, "/jobs/query/"+id, "")
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+			return job.State == state
+		}, time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	job := start("SELECT * FROM ORDERS")
+	job = poll(job.ID, JobSucceeded)
+	assert.Equal(t, 3, job.Rows)
+	assert.NotNil(t, job.ExpiresAt)
+
+	// Results are paged
+	var page QueryJobPage
+	w := request(http.MethodGet, "/jobs/query/"+job.ID+"/results?limit=2", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Len(t, page.Rows, 2)
+	assert.Equal(t, 3, page.Total)
+	require.NotNil(t, page.NextOffset)
+	page = QueryJobPage{}
+	require.NoError(t, json.Unmarshal(request(http.MethodGet, "/jobs/query/"+job.ID+"/results?offset=2&limit=2", "").Body.Bytes(), &page))
+	assert.Len(t, page.Rows, 1)
+	assert.Nil(t, page.NextOffset)
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodGet, "/jobs/query/"+job.ID+"/results?limit=0", "").Code)
+
+	// Jobs belong to their caller
+	other := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/jobs/query/"+job.ID, nil)
+	r.RemoteAddr = "198.51.100.7:1234"
+	router.ServeHTTP(other, r)
+	assert.Equal(t, http.StatusNotFound, other.Code)
+
+	// Running jobs can be cancelled, and their results are not available
+	s.DBConn = &blockingConnector{started: make(chan struct{})}
+	running := start("SELECT * FROM EVENTS")
+	poll(running.ID, JobRunning)
+	assert.Equal(t, http.StatusConflict, request(http.MethodGet, "/jobs/query/"+running.ID+"/results", "").Code)
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, "/jobs/query/"+running.ID, "").Code)
+	poll(running.ID, JobCanceled)
+
+	// Deleting a finished job discards it
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, "/jobs/query/"+job.ID, "").Code)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/jobs/query/"+job.ID, "").Code)
+
+	_, err = newQueryJobs(&QueryJobConfig{Retention: "forever"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+)
+
+// Parameter binding modes of the query policy
+const (
+	BindingParams = "params"
+	BindingStrict = "strict"
+)
+
+// minInputLiteral is the length from which a literal found in the caller's
+// question is taken to come from it
+const minInputLiteral = 3
+
+// ErrQueryPolicy is returned for free-form SQL the query policy rejects
+var ErrQueryPolicy = errors.New("query rejected by the query policy")
+
+// QueryPolicyConfig restricts the SQL of the query endpoint, the query
+// tool, exports, federated queries, generated answers, saved queries,
+// subscriptions, recipes, routines, table statistics and the gRPC and
+// transaction APIs. Queries are parsed with the database's literal and
+// comment rules, so that separators and keywords hidden in literals or
+// comments are told apart from real ones.
+type QueryPolicyConfig struct {
+	// Allow lists the statement kinds allowed: read, write, ddl, session
+	// and other (default: read and write)
+	Allow []string `json:"allow,omitempty"`
+
+	// AllowMultipleStatements allows several statements in one query
+	AllowMultipleStatements bool `json:"allow_multiple_statements,omitempty"`
+
+	// AllowComments allows comments in queries
+	AllowComments bool `json:"allow_comments,omitempty"`
+
+	// Binding requires values to be bound as parameters rather than
+	// written as string literals: "params" rejects literals equal to the
+	// value of a parameter or found in the question the query answers,
+	// "strict" rejects every string literal
+	Binding string `json:"binding,omitempty"`
+}
+
+// queryPolicy is a validated QueryPolicyConfig
+type queryPolicy struct {
+	syntax   connector.SQLSyntax
+	allow    map[connector.StatementKind]bool
+	multiple bool
+	comments bool
+	binding  string
+}
+
+// newQueryPolicy validates a query policy for a database type; nil allows
+// any SQL
+func newQueryPolicy(cfg *QueryPolicyConfig, dbType string) (*queryPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	p := &queryPolicy{
+		syntax:   connector.SyntaxFor(dbType),
+		allow:    make(map[connector.StatementKind]bool),
+		multiple: cfg.AllowMultipleStatements,
+		comments: cfg.AllowComments,
+		binding:  cfg.Binding,
+	}
+	switch cfg.Binding {
+	case "", BindingParams, BindingStrict:
+	default:
+		return nil, fmt.Errorf("invalid binding mode: %s", cfg.Binding)
+	}
+	allow := cfg.Allow
+	if len(allow) == 0 {
+		allow = []string{string(connector.StatementRead), string(connector.StatementWrite)}
+	}
+	for _, kind := range allow {
+		switch k := connector.StatementKind(kind); k {
+		case connector.StatementRead, connector.StatementWrite, connector.StatementDDL,
+			connector.StatementSession, connector.StatementOther:
+			p.allow[k] = true
+		default:
+			return nil, fmt.Errorf("invalid statement kind: %s", kind)
+		}
+	}
+	return p, nil
+}
+
+// check returns why a query is rejected, or nil. params are the query's
+// bound parameters and input the caller's free text, like the question
+// the query answers.
+func (p *queryPolicy) check(query string, params map[string]interface{}, input ...string) error {
+	statements, err := connector.ParseStatements(p.syntax, query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrQueryPolicy, err)
+	}
+	if len(statements) == 0 {
+		return fmt.Errorf("%w: empty query", ErrQueryPolicy)
+	}
+	if len(statements) > 1 && !p.multiple {
+		return fmt.Errorf("%w: multiple statements are not allowed", ErrQueryPolicy)
+	}
+	for _, st := range statements {
+		if st.Comments && !p.comments {
+			return fmt.Errorf("%w: comments are not allowed", ErrQueryPolicy)
+		}
+		if !p.allow[st.Kind] {
+			return fmt.Errorf("%w: %s statements (%s) are not allowed", ErrQueryPolicy, st.Kind, st.Keyword)
+		}
+		if err := p.checkBinding(st.Literals, params, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkBinding rejects the string literals the binding mode requires to be
+// bound parameters
+func (p *queryPolicy) checkBinding(literals []string, params map[string]interface{}, input []string) error {
+	if p.binding == "" || len(literals) == 0 {
+		return nil
+	}
+	if p.binding == BindingStrict {
+		return fmt.Errorf("%w: string literals are not allowed; bind values as :name parameters", ErrQueryPolicy)
+	}
+	for _, literal := range literals {
+		if literal == "" {
+			continue
+		}
+		for name, value := range params {
+			if v, ok := value.(string); ok && v == literal {
+				return fmt.Errorf("%w: a string literal repeats the value of parameter %s; bind it as :%s", ErrQueryPolicy, name, name)
+			}
+		}
+		if len(literal) < minInputLiteral {
+			continue
+		}
+		for _, text := range input {
+			if strings.Contains(strings.ToLower(text), strings.ToLower(literal)) {
+				return fmt.Errorf("%w: the string literal %q comes from the question; bind it as a :name parameter", ErrQueryPolicy, literal)
+			}
+		}
+	}
+	return nil
+}
+
+// checkQueryPolicy rejects free-form SQL outside the query policy,
+// publishing the rejection as a policy violation
+func (s *MCPServerWithDB) checkQueryPolicy(ctx context.Context, source, query string, params map[string]interface{}, input ...string) error {
+	if s.queryPolicy == nil {
+		return nil
+	}
+	err := s.queryPolicy.check(query, params, input...)
+	if err != nil {
+		tag := callerTag(ctx)
+		s.publish(events.TypePolicyViolation, map[string]interface{}{
+			"policy":    policyQuery,
+			"source":    source,
+			"query":     query,
+			"reason":    err.Error(),
+			"principal": tag.Principal,
+		})
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestQueryPolicy(t *testing.T) {
+	policy, err := newQueryPolicy(&QueryPolicyConfig{}, "mysql")
+	require.NoError(t, err)
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, queryPolicy: policy}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	call := func(query string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]string{"query": query})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/query", bytes.NewReader(body)))
+		return w
+	}
+
+	w := call(`UPDATE ORDERS SET NOTE = 'a; DROP TABLE ORDERS' WHERE ID = 1`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, conn.query, "UPDATE ORDERS")
+
+	conn.query = ""
+	for query, reason := range map[string]string{
+		`SELECT 1; DROP TABLE ORDERS`:                   "multiple statements",
+		`SELECT 'it\'s'; DROP TABLE ORDERS`:             "multiple statements",
+		`SELECT * FROM ORDERS -- AND TENANT = 1`:        "comments",
+		`SELECT 1 /*!50000 , (SELECT LOAD_FILE('x'))*/`: "executable comments",
+		`DROP TABLE ORDERS`:                             "ddl statements (DROP)",
+		`SET GLOBAL general_log = 1`:                    "session statements (SET)",
+	} {
+		w := call(query)
+		assert.Equal(t, http.StatusForbidden, w.Code, query)
+		assert.Contains(t, w.Body.String(), CodePolicyDenied, query)
+		assert.Contains(t, w.Body.String(), reason, query)
+	}
+	assert.Empty(t, conn.query, "rejected queries must not run")
+
+	_, err = newQueryPolicy(&QueryPolicyConfig{Allow: []string{"admin"}}, "")
+	assert.Error(t, err)
+
+	// Multiple statements and comments are opt-in
+	policy, err = newQueryPolicy(&QueryPolicyConfig{Allow: []string{"read"}, AllowMultipleStatements: true, AllowComments: true}, "")
+	require.NoError(t, err)
+	assert.NoError(t, policy.check("SELECT 1; -- first\nSELECT 2", nil))
+	assert.ErrorIs(t, policy.check("SELECT 1; DELETE FROM ORDERS", nil), ErrQueryPolicy)
+	assert.NoError(t, (&MCPServerWithDB{}).checkQueryPolicy(context.Background(), "rest", "DROP TABLE ORDERS", nil))
+}
+
+func TestQueryPolicyBinding(t *testing.T) {
+	policy, err := newQueryPolicy(&QueryPolicyConfig{Binding: BindingParams}, "snowflake")
+	require.NoError(t, err)
+	params := map[string]interface{}{"region": "EMEA", "limit": 10}
+
+	assert.NoError(t, policy.check(`SELECT * FROM ORDERS WHERE REGION = :region AND STATUS = 'open'`, params))
+	err = policy.check(`SELECT * FROM ORDERS WHERE REGION = 'EMEA'`, params)
+	assert.ErrorIs(t, err, ErrQueryPolicy)
+	assert.ErrorContains(t, err, "bind it as :region")
+
+	// Literals taken from the question the query answers
+	question := "How many orders did Acme Corp place last month?"
+	err = policy.check(`SELECT COUNT(*) FROM ORDERS WHERE CUSTOMER = 'acme corp'`, nil, question)
+	assert.ErrorContains(t, err, "comes from the question")
+	assert.NoError(t, policy.check(`SELECT COUNT(*) FROM ORDERS WHERE CUSTOMER = :customer AND KIND = 'x'`, nil, question))
+
+	strict, err := newQueryPolicy(&QueryPolicyConfig{Binding: BindingStrict}, "")
+	require.NoError(t, err)
+	assert.ErrorIs(t, strict.check(`SELECT * FROM ORDERS WHERE STATUS = 'open'`, nil), ErrQueryPolicy)
+	assert.NoError(t, strict.check(`SELECT * FROM "ORDERS" WHERE STATUS = :status`, nil))
+
+	_, err = newQueryPolicy(&QueryPolicyConfig{Binding: "always"}, "")
+	assert.Error(t, err)
+}
+
+func TestQueryPolicyEntryPoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// Only writes pass, so every read below is rejected
+	writes, err := newQueryPolicy(&QueryPolicyConfig{Allow: []string{"write"}}, "snowflake")
+	require.NoError(t, err)
+	reads, err := newQueryPolicy(&QueryPolicyConfig{Allow: []string{"read"}}, "snowflake")
+	require.NoError(t, err)
+	rejected := func(t *testing.T, w *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), CodePolicyDenied)
+	}
+
+	t.Run("saved", func(t *testing.T) {
+		conn := &paramsConnector{}
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, saved: newSavedQueries("sales", nil), queryPolicy: writes}
+		require.NoError(t, s.saved.Create(context.Background(), &SavedQuery{Name: "open_orders", SQL: "SELECT * FROM ORDERS"}))
+		router := gin.New()
+		s.setupSavedQueryRoutes(router.Group(""))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saved/open_orders", nil))
+		rejected(t, w)
+
+		_, err := s.savedQueryTools()[0].Handler(context.Background(), &mcpSession{}, map[string]interface{}{})
+		assert.ErrorIs(t, err, ErrQueryPolicy)
+		assert.Empty(t, conn.query)
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		conn := &paramsConnector{}
+		s := &MCPServerWithDB{
+			Config:      &MCPServerConfig{Name: "sales", Subscriptions: &SubscriptionConfig{}},
+			DBConn:      conn,
+			saved:       newSavedQueries("sales", nil),
+			queryPolicy: writes,
+		}
+		require.NoError(t, s.saved.Create(context.Background(), &SavedQuery{Name: "open_orders", SQL: "SELECT * FROM ORDERS"}))
+		router := gin.New()
+		s.setupSubscriptionRoutes(router.Group(""))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saved/open_orders/subscribe", nil))
+		rejected(t, w)
+		assert.Empty(t, conn.query)
+	})
+
+	t.Run("recipe", func(t *testing.T) {
+		recipes, err := newQueryRecipes([]QueryRecipeConfig{{Name: "open_orders", Template: "SELECT * FROM ORDERS"}})
+		require.NoError(t, err)
+		conn := &paramsConnector{}
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, recipes: recipes, queryPolicy: writes}
+		_, err = s.recipeTools()[0].Handler(context.Background(), &mcpSession{}, map[string]interface{}{})
+		assert.ErrorIs(t, err, ErrQueryPolicy)
+		assert.Empty(t, conn.query)
+	})
+
+	t.Run("routine", func(t *testing.T) {
+		conn := &routinesConnector{routines: []connector.Routine{{Name: "PURGE", Kind: connector.RoutineProcedure, Arguments: []connector.RoutineArgument{}}}}
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", Routines: []string{"PURGE"}}, DBConn: conn, queryPolicy: reads}
+		router := gin.New()
+		s.setupRoutineRoutes(router.Group(""))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/routines/PURGE", nil))
+		rejected(t, w)
+
+		tools := s.routineTools(context.Background())
+		require.Len(t, tools, 1)
+		_, err := tools[0].Handler(context.Background(), &mcpSession{}, map[string]interface{}{})
+		assert.ErrorIs(t, err, ErrQueryPolicy)
+		assert.Empty(t, conn.query)
+	})
+
+	t.Run("table_stats", func(t *testing.T) {
+		conn := &statsConnector{}
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, tableStats: newStatsCache(), queryPolicy: writes}
+		router := gin.New()
+		s.setupTableStatsRoutes(router.Group(""))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tables/ORDERS/stats", nil))
+		rejected(t, w)
+		assert.Empty(t, conn.queries)
+	})
+}
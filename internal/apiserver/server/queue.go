@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Query priorities; queued interactive queries run before batch ones
+const (
+	PriorityInteractive = "interactive"
+	PriorityBatch       = "batch"
+)
+
+const (
+	defaultMaxConcurrentQueries = 8
+	defaultMaxQueuedQueries     = 64
+
+	// queryPriorityHeader sets the priority of a REST request's queries
+	queryPriorityHeader = "X-Query-Priority"
+)
+
+// ErrQueryQueueFull is returned for queries arriving while every slot is
+// busy and the queue is full
+var ErrQueryQueueFull = errors.New("query queue is full")
+
+// batchSources are the query sources that run at batch priority unless a
+// request asks otherwise
+var batchSources = map[string]bool{
+	"scheduled":    true,
+	"subscription": true,
+	"export":       true,
+	"job":          true,
+	"snapshot":     true,
+}
+
+// QueryQueueConfig bounds the queries a server runs on its connection at
+// once, queueing the rest by priority
+type QueryQueueConfig struct {
+	// MaxConcurrent queries run at once (default: 8)
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+
+	// MaxQueued queries wait for a slot before further ones are rejected
+	// (default: 64)
+	MaxQueued int `json:"max_queued,omitempty"`
+}
+
+// queryQueue hands out a bounded number of query slots, serving waiting
+// interactive queries before batch ones
+type queryQueue struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	maxQueued     int
+	running       int
+	interactive   []*queueWaiter
+	batch         []*queueWaiter
+}
+
+// queueWaiter is a query waiting for a slot
+type queueWaiter struct {
+	id      string
+	ready   chan struct{}
+	granted bool
+}
+
+// newQueryQueue creates the queue of a configuration; nil leaves queries
+// unbounded and returns a nil queue
+func newQueryQueue(cfg *QueryQueueConfig) (*queryQueue, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.MaxConcurrent < 0 || cfg.MaxQueued < 0 {
+		return nil, fmt.Errorf("max_concurrent and max_queued must not be negative")
+	}
+	q := &queryQueue{maxConcurrent: cfg.MaxConcurrent, maxQueued: cfg.MaxQueued}
+	if q.maxConcurrent == 0 {
+		q.maxConcurrent = defaultMaxConcurrentQueries
+	}
+	if q.maxQueued == 0 {
+		q.maxQueued = defaultMaxQueuedQueries
+	}
+	return q, nil
+}
+
+// acquire waits for a query slot, failing with ErrQueryQueueFull when the
+// queue is full or with the context's error when it ends first. Acquired
+// slots are returned with release.
+func (q *queryQueue) acquire(ctx context.Context, id, priority string) error {
+	q.mu.Lock()
+	queued := len(q.interactive) + len(q.batch)
+	if q.running < q.maxConcurrent && queued == 0 {
+		q.running++
+		q.mu.Unlock()
+		return nil
+	}
+	if queued >= q.maxQueued {
+		q.mu.Unlock()
+		return fmt.Errorf("%w: %d queries queued", ErrQueryQueueFull, queued)
+	}
+	w := &queueWaiter{id: id, ready: make(chan struct{})}
+	if priority == PriorityBatch {
+		q.batch = append(q.batch, w)
+	} else {
+		q.interactive = append(q.interactive, w)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if w.granted {
+		// The slot was handed over while the context ended; pass it on
+		q.releaseLocked()
+	} else {
+		q.interactive = removeWaiter(q.interactive, w)
+		q.batch = removeWaiter(q.batch, w)
+	}
+	return ctx.Err()
+}
+
+// release returns a slot, handing it to the next waiting query
+func (q *queryQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.releaseLocked()
+}
+
+func (q *queryQueue) releaseLocked() {
+	var next *queueWaiter
+	switch {
+	case len(q.interactive) > 0:
+		next, q.interactive = q.interactive[0], q.interactive[1:]
+	case len(q.batch) > 0:
+		next, q.batch = q.batch[0], q.batch[1:]
+	default:
+		q.running--
+		return
+	}
+	next.granted = true
+	close(next.ready)
+}
+
+// positions returns the 1-based position of each waiting query, in the
+// order slots are handed out
+func (q *queryQueue) positions() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	positions := make(map[string]int, len(q.interactive)+len(q.batch))
+	for i, w := range append(append([]*queueWaiter{}, q.interactive...), q.batch...) {
+		positions[w.id] = i + 1
+	}
+	return positions
+}
+
+func removeWaiter(waiters []*queueWaiter, w *queueWaiter) []*queueWaiter {
+	for i, other := range waiters {
+		if other == w {
+			return append(waiters[:i], waiters[i+1:]...)
+		}
+	}
+	return waiters
+}
+
+type queryPriorityKey struct{}
+
+// withQueryPriority returns a context whose queries run at a priority
+func withQueryPriority(ctx context.Context, priority string) context.Context {
+	return context.WithValue(ctx, queryPriorityKey{}, priority)
+}
+
+// queryPriority returns the priority of a query: the one set on its
+// context, else batch for batch sources and interactive otherwise
+func queryPriority(ctx context.Context, source string) string {
+	if priority, ok := ctx.Value(queryPriorityKey{}).(string); ok {
+		return priority
+	}
+	if batchSources[source] {
+		return PriorityBatch
+	}
+	return PriorityInteractive
+}
+
+// queryPriorityMiddleware sets the priority of a REST request's queries
+// from its X-Query-Priority header
+func (s *MCPServerWithDB) queryPriorityMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch priority := c.GetHeader(queryPriorityHeader); priority {
+		case "":
+		case PriorityInteractive, PriorityBatch:
+			c.Request = c.Request.WithContext(withQueryPriority(c.Request.Context(), priority))
+		default:
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid query priority: %s", priority)})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryQueuePriorities(t *testing.T) {
+	queue, err := newQueryQueue(&QueryQueueConfig{MaxConcurrent: 1, MaxQueued: 2})
+	require.NoError(t, err)
+	tracker := newQueryTracker(queue)
+	ctx := context.Background()
+
+	_, done, err := tracker.start(ctx, "rest", "SELECT 1")
+	require.NoError(t, err)
+
+	// Waiting queries are served interactive first, whatever their arrival
+	order := make(chan string, 2)
+	wait := func(ctx context.Context, source string) {
+		_, done, err := tracker.start(ctx, source, source)
+		if assert.NoError(t, err) {
+			order <- source
+			done()
+		}
+	}
+	go wait(ctx, "scheduled")
+	require.Eventually(t, func() bool { return len(queue.positions()) == 1 }, time.Second, time.Millisecond)
+	go wait(withQueryPriority(ctx, PriorityInteractive), "export")
+	require.Eventually(t, func() bool { return len(queue.positions()) == 2 }, time.Second, time.Millisecond)
+
+	queued := make(map[string]RunningQuery)
+	for _, q := range tracker.list("") {
+		queued[q.Query] = q
+	}
+	assert.Equal(t, QueryRunning, queued["SELECT 1"].State)
+	assert.Equal(t, QueryQueued, queued["scheduled"].State)
+	assert.Equal(t, PriorityBatch, queued["scheduled"].Priority)
+	assert.Equal(t, 2, queued["scheduled"].Position)
+	assert.Equal(t, PriorityInteractive, queued["export"].Priority)
+	assert.Equal(t, 1, queued["export"].Position)
+
+	// A full queue rejects further queries
+	_, _, err = tracker.start(ctx, "mcp", "SELECT 2")
+	assert.ErrorIs(t, err, ErrQueryQueueFull)
+	assert.Equal(t, http.StatusTooManyRequests, queryErrorStatus(err))
+
+	done()
+	assert.Equal(t, "export", <-order)
+	assert.Equal(t, "scheduled", <-order)
+	assert.Empty(t, tracker.list(""))
+}
+
+func TestQueuedQueryCancellation(t *testing.T) {
+	queue, err := newQueryQueue(&QueryQueueConfig{MaxConcurrent: 1})
+	require.NoError(t, err)
+	tracker := newQueryTracker(queue)
+
+	_, done, err := tracker.start(context.Background(), "rest", "SELECT 1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := tracker.start(ctx, "rest", "SELECT 2")
+		errs <- err
+	}()
+	require.Eventually(t, func() bool { return len(queue.positions()) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-errs, context.Canceled)
+	assert.Empty(t, queue.positions())
+
+	// The slot is free for the next query once the running one is done
+	done()
+	_, done, err = tracker.start(context.Background(), "rest", "SELECT 3")
+	require.NoError(t, err)
+	done()
+}
@@ -0,0 +1,360 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+)
+
+// Quotas limited per day
+const (
+	QuotaQueries      = "queries"
+	QuotaRows         = "rows"
+	QuotaBytesScanned = "bytes_scanned"
+)
+
+const (
+	defaultQuotaRefresh      = time.Minute
+	quotaAPIKeySubjectPrefix = "key:"
+	quotaRefreshTimeout      = 30 * time.Second
+)
+
+// ErrQuotaExceeded is returned for queries of callers that used up one of
+// their daily quotas
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaConfig limits the queries, rows and bytes scanned of each caller per
+// UTC day. A caller's quota is the quota of its API key, else the quota of
+// its role, else the default; usage is counted per API key, or per
+// principal for callers without one. Queries the server issues on its own,
+// like scheduled queries, are not limited.
+type QuotaConfig struct {
+	// Default applies to callers without a quota of their key or role
+	Default *Quota `json:"default,omitempty"`
+
+	// Roles are the quotas of the callers with a role claim
+	Roles map[string]Quota `json:"roles,omitempty"`
+
+	// APIKeys are the quotas of tenant API keys, by key ID
+	APIKeys map[string]Quota `json:"api_keys,omitempty"`
+
+	// RefreshInterval between reads of the bytes scanned from the
+	// warehouse's query history (default: 1m)
+	RefreshInterval string `json:"refresh_interval,omitempty"`
+}
+
+// Quota is the daily usage allowed to a caller; zero values are unlimited.
+// Bytes scanned are read from the warehouse's query history, so they are
+// enforced with a delay and only on databases that attribute spend.
+type Quota struct {
+	QueriesPerDay      int64 `json:"queries_per_day,omitempty"`
+	RowsPerDay         int64 `json:"rows_per_day,omitempty"`
+	BytesScannedPerDay int64 `json:"bytes_scanned_per_day,omitempty"`
+}
+
+// QuotaUsage is a caller's usage of the current day
+type QuotaUsage struct {
+	Subject      string    `json:"subject"`
+	Queries      int64     `json:"queries"`
+	Rows         int64     `json:"rows"`
+	BytesScanned int64     `json:"bytes_scanned"`
+	Quota        Quota     `json:"quota"`
+	ResetAt      time.Time `json:"reset_at"`
+}
+
+// QuotaExceededError is returned for the queries of a caller over quota
+type QuotaExceededError struct {
+	Subject string
+	Quota   string
+	Limit   int64
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("daily %s quota of %d exceeded for %s; it resets at %s",
+		e.Quota, e.Limit, e.Subject, e.ResetAt.Format(time.RFC3339))
+}
+
+// Is makes QuotaExceededError match ErrQuotaExceeded
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// quotaTracker counts the daily usage of callers against their quotas
+type quotaTracker struct {
+	defaults *Quota
+	roles    map[string]Quota
+	keys     map[string]Quota
+	refresh  time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	day      time.Time
+	usage    map[string]*QuotaUsage
+	scanned  map[string]int64 // bytes scanned by subject, from the warehouse
+	notified map[string]bool  // subject and quota of the exceeded quotas
+}
+
+// newQuotaTracker validates a quota configuration; nil disables quotas
+func newQuotaTracker(cfg *QuotaConfig) (*quotaTracker, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	t := &quotaTracker{
+		defaults: cfg.Default,
+		roles:    cfg.Roles,
+		keys:     cfg.APIKeys,
+		refresh:  defaultQuotaRefresh,
+		now:      time.Now,
+	}
+	if cfg.RefreshInterval != "" {
+		d, err := time.ParseDuration(cfg.RefreshInterval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid refresh interval: %s", cfg.RefreshInterval)
+		}
+		t.refresh = d
+	}
+
+	quotas := []Quota{}
+	if cfg.Default != nil {
+		quotas = append(quotas, *cfg.Default)
+	}
+	for _, q := range cfg.Roles {
+		quotas = append(quotas, q)
+	}
+	for _, q := range cfg.APIKeys {
+		quotas = append(quotas, q)
+	}
+	for _, q := range quotas {
+		if q.QueriesPerDay < 0 || q.RowsPerDay < 0 || q.BytesScannedPerDay < 0 {
+			return nil, fmt.Errorf("quotas must not be negative")
+		}
+	}
+	t.rollover(t.now())
+	return t, nil
+}
+
+// tracksBytes reports whether any quota limits the bytes scanned
+func (t *quotaTracker) tracksBytes() bool {
+	if t.defaults != nil && t.defaults.BytesScannedPerDay > 0 {
+		return true
+	}
+	for _, quotas := range []map[string]Quota{t.roles, t.keys} {
+		for _, q := range quotas {
+			if q.BytesScannedPerDay > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// subjectOf returns the subject whose usage the queries of a tag count
+// towards, empty for queries the server issues on its own
+func subjectOf(tag queryTag) string {
+	if tag.APIKey != "" {
+		return quotaAPIKeySubjectPrefix + tag.APIKey
+	}
+	return tag.Principal
+}
+
+// quotaOf returns the subject and quota of the caller of a context; the
+// quota is nil when the caller is not limited
+func (t *quotaTracker) quotaOf(ctx context.Context) (string, *Quota) {
+	tag := callerTag(ctx)
+	subject := subjectOf(tag)
+	if subject == "" {
+		return "", nil
+	}
+	if q, ok := t.keys[tag.APIKey]; ok && tag.APIKey != "" {
+		return subject, &q
+	}
+	if q, ok := t.roles[callerRole(ctx)]; ok {
+		return subject, &q
+	}
+	return subject, t.defaults
+}
+
+// rollover starts a new day of usage when now is past the current one
+func (t *quotaTracker) rollover(now time.Time) {
+	day := now.UTC().Truncate(24 * time.Hour)
+	if day.Equal(t.day) {
+		return
+	}
+	t.day = day
+	t.usage = make(map[string]*QuotaUsage)
+	t.scanned = make(map[string]int64)
+	t.notified = make(map[string]bool)
+}
+
+// usageOf returns the usage of a subject, locked
+func (t *quotaTracker) usageOf(subject string) *QuotaUsage {
+	u, ok := t.usage[subject]
+	if !ok {
+		u = &QuotaUsage{Subject: subject}
+		t.usage[subject] = u
+	}
+	return u
+}
+
+// admit counts a query of the caller of a context against its quota. It
+// returns the subject to count the query's rows towards, and a
+// QuotaExceededError when a quota is used up; notify is true for the first
+// rejection of the subject's quota of the day.
+func (t *quotaTracker) admit(ctx context.Context) (subject string, notify bool, err error) {
+	subject, quota := t.quotaOf(ctx)
+	if quota == nil {
+		return "", false, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(t.now())
+	u := t.usageOf(subject)
+	u.Quota = *quota
+	exceeded := func(name string, used, limit int64) error {
+		if limit <= 0 || used < limit {
+			return nil
+		}
+		key := subject + "|" + name
+		notify = !t.notified[key]
+		t.notified[key] = true
+		return &QuotaExceededError{Subject: subject, Quota: name, Limit: limit, ResetAt: t.day.Add(24 * time.Hour)}
+	}
+	if err := exceeded(QuotaQueries, u.Queries, quota.QueriesPerDay); err != nil {
+		return subject, notify, err
+	}
+	if err := exceeded(QuotaRows, u.Rows, quota.RowsPerDay); err != nil {
+		return subject, notify, err
+	}
+	if err := exceeded(QuotaBytesScanned, t.scanned[subject], quota.BytesScannedPerDay); err != nil {
+		return subject, notify, err
+	}
+	u.Queries++
+	return subject, false, nil
+}
+
+// addRows counts the rows returned to a subject
+func (t *quotaTracker) addRows(subject string, rows int) {
+	if t == nil || subject == "" {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(t.now())
+	t.usageOf(subject).Rows += int64(rows)
+}
+
+// setScanned replaces the bytes scanned of the day with the tagged spend of
+// a server's queries
+func (t *quotaTracker) setScanned(server string, spends []connector.TagSpend) {
+	scanned := make(map[string]int64)
+	for _, spend := range spends {
+		var tag queryTag
+		if err := json.Unmarshal([]byte(spend.QueryTag), &tag); err != nil || tag.App != queryTagApp || tag.Server != server {
+			continue
+		}
+		if subject := subjectOf(tag); subject != "" {
+			scanned[subject] += spend.BytesScanned
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(t.now())
+	t.scanned = scanned
+}
+
+// snapshot returns the usage of the day, by subject
+func (t *quotaTracker) snapshot() []QuotaUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(t.now())
+	usage := make([]QuotaUsage, 0, len(t.usage))
+	for subject, u := range t.usage {
+		entry := *u
+		entry.BytesScanned = t.scanned[subject]
+		entry.ResetAt = t.day.Add(24 * time.Hour)
+		usage = append(usage, entry)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Subject < usage[j].Subject })
+	return usage
+}
+
+// admitQuery checks the quota of the caller of a context before a query,
+// publishing the first rejection of each quota of the day as a policy
+// violation
+func (s *MCPServerWithDB) admitQuery(ctx context.Context, source string) (string, error) {
+	if s.quotas == nil {
+		return "", nil
+	}
+	subject, notify, err := s.quotas.admit(ctx)
+	var exceeded *QuotaExceededError
+	if notify && errors.As(err, &exceeded) {
+		s.publish(events.TypePolicyViolation, map[string]interface{}{
+			"policy":   policyQuota,
+			"source":   source,
+			"subject":  exceeded.Subject,
+			"quota":    exceeded.Quota,
+			"limit":    exceeded.Limit,
+			"reset_at": exceeded.ResetAt,
+		})
+	}
+	return subject, err
+}
+
+// refreshQuotaBytes reads the bytes scanned of the day from the warehouse
+func (s *MCPServerWithDB) refreshQuotaBytes(attributor connector.SpendAttributor) {
+	ctx, cancel := context.WithTimeout(s.ctx, quotaRefreshTimeout)
+	defer cancel()
+	now := s.quotas.now().UTC()
+	spends, err := attributor.SpendByQueryTag(ctx, now.Truncate(24*time.Hour), now)
+	if err != nil {
+		log.Printf("Warning: Failed to read bytes scanned for quotas: %v", err)
+		return
+	}
+	s.quotas.setScanned(s.Config.Name, spends)
+}
+
+// runQuotaRefresh keeps the bytes scanned of the quotas up to date
+func (s *MCPServerWithDB) runQuotaRefresh() {
+	attributor, ok := s.DBConn.(connector.SpendAttributor)
+	if !ok {
+		log.Printf("Warning: The database does not report bytes scanned; bytes scanned quotas are not enforced")
+		return
+	}
+
+	ticker := time.NewTicker(s.quotas.refresh)
+	defer ticker.Stop()
+	for {
+		s.refreshQuotaBytes(attributor)
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setupQuotaRoutes configures the quota usage route when quotas are
+// configured
+func (s *MCPServerWithDB) setupQuotaRoutes(router *gin.RouterGroup) {
+	if s.quotas == nil {
+		return
+	}
+
+	router.GET("/admin/quotas", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.quotas.snapshot())
+	})
+}
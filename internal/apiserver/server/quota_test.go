@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestQuotas(t *testing.T) {
+	quotas, err := newQuotaTracker(&QuotaConfig{
+		Default: &Quota{QueriesPerDay: 2},
+		Roles:   map[string]Quota{"analyst": {RowsPerDay: 3}},
+		APIKeys: map[string]Quota{"key-1": {BytesScannedPerDay: 100}},
+	})
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	quotas.now = func() time.Time { return now }
+	assert.True(t, quotas.tracksBytes())
+
+	conn := &rowsConnector{rows: []map[string]interface{}{{"ID": 1.0}, {"ID": 2.0}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, quotas: quotas}
+	tagged := func(principal, apiKey string) context.Context {
+		return connector.WithQueryTag(context.Background(), s.queryTag("rest", "", principal, apiKey, ""))
+	}
+
+	// The default quota limits the queries of each caller
+	jane := tagged("jane", "")
+	for i := 0; i < 2; i++ {
+		_, err := s.executeQuery(jane, "rest", "SELECT * FROM ORDERS", nil)
+		require.NoError(t, err)
+	}
+	_, err = s.executeQuery(jane, "rest", "SELECT * FROM ORDERS", nil)
+	var exceeded *QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, QuotaQueries, exceeded.Quota)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), exceeded.ResetAt)
+	_, err = s.executeQuery(tagged("bob", ""), "rest", "SELECT * FROM ORDERS", nil)
+	assert.NoError(t, err)
+
+	// Only the first rejection of the day is notified
+	_, notify, err := quotas.admit(jane)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.False(t, notify)
+
+	// Roles replace the default, and rows count once returned
+	analyst := withClaims(tagged("ann", ""), map[string]interface{}{"role": "analyst"})
+	for i := 0; i < 2; i++ {
+		_, err := s.executeQuery(analyst, "rest", "SELECT * FROM ORDERS", nil)
+		require.NoError(t, err)
+	}
+	_, err = s.executeQuery(analyst, "rest", "SELECT * FROM ORDERS", nil)
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, QuotaRows, exceeded.Quota)
+
+	// Bytes scanned come from the warehouse's tagged spend
+	key := tagged("acme:ci", "key-1")
+	quotas.setScanned("sales", []connector.TagSpend{
+		{QueryTag: s.queryTag("rest", "", "acme:ci", "key-1", ""), BytesScanned: 60},
+		{QueryTag: s.queryTag("mcp", "", "acme:ci", "key-1", "s1"), BytesScanned: 40},
+	})
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ORDERS", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(key)
+		c.Next()
+	}, s.generatedEndpointHandler(connector.APIEndpoint{Table: "ORDERS", Method: http.MethodGet, Path: "/ORDERS", Query: "SELECT * FROM ORDERS"}))
+	s.setupQuotaRoutes(router.Group("/"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ORDERS", nil))
+	require.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	var resp struct {
+		Code    string    `json:"code"`
+		ResetAt time.Time `json:"reset_at"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, CodeQuotaExceeded, resp.Code)
+	assert.Equal(t, exceeded.ResetAt, resp.ResetAt)
+
+	// Queries the server issues on its own are not limited
+	_, err = s.executeQuery(context.Background(), "scheduled", "SELECT * FROM ORDERS", nil)
+	assert.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/quotas", nil))
+	var usage []QuotaUsage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	require.Len(t, usage, 4)
+	assert.Equal(t, QuotaUsage{Subject: "ann", Queries: 2, Rows: 4, Quota: Quota{RowsPerDay: 3}, ResetAt: exceeded.ResetAt}, usage[0])
+	assert.Equal(t, int64(100), usage[3].BytesScanned)
+
+	// Usage starts over the next day
+	now = now.Add(24 * time.Hour)
+	_, err = s.executeQuery(jane, "rest", "SELECT * FROM ORDERS", nil)
+	assert.NoError(t, err)
+
+	_, err = newQuotaTracker(&QuotaConfig{Default: &Quota{RowsPerDay: -1}})
+	assert.Error(t, err)
+}
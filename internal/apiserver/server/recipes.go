@@ -0,0 +1,307 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// ErrInvalidRecipeInput is returned for recipe arguments that do not satisfy
+// the recipe's inputs
+var ErrInvalidRecipeInput = errors.New("invalid recipe input")
+
+// QueryRecipeConfig is a SQL template with typed inputs, served as an MCP
+// tool named after it. Recipes let agents answer common questions without
+// writing SQL: the template decides the statement's shape and every input
+// is bound as a parameter.
+type QueryRecipeConfig struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Template is a text/template rendering a single read-only statement.
+	// Inputs whose values are known in advance — enums, numbers and
+	// booleans — can shape it, e.g. `ORDER BY {{.sort}}`; free-text inputs
+	// can only be referenced as :name parameters.
+	Template string `json:"template"`
+
+	Inputs []RecipeInput `json:"inputs,omitempty"`
+}
+
+// RecipeInput declares an input of a recipe. Range and length constraints
+// are checked before the template is rendered.
+type RecipeInput struct {
+	SavedQueryParameter
+
+	// Minimum and Maximum bound integer and number inputs
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	// MinLength, MaxLength and Pattern constrain string inputs; Pattern
+	// must match the whole value
+	MinLength int    `json:"min_length,omitempty"`
+	MaxLength int    `json:"max_length,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+}
+
+// queryRecipe is a validated QueryRecipeConfig
+type queryRecipe struct {
+	cfg      QueryRecipeConfig
+	tmpl     *template.Template
+	patterns map[string]*regexp.Regexp
+}
+
+// newQueryRecipes validates the recipes, rendering each template with
+// sample inputs to check that it yields a read-only statement
+func newQueryRecipes(configs []QueryRecipeConfig) ([]*queryRecipe, error) {
+	recipes := make([]*queryRecipe, 0, len(configs))
+	names := make(map[string]bool, len(configs))
+	for _, cfg := range configs {
+		if !toolNamePattern.MatchString(cfg.Name) || names[cfg.Name] {
+			return nil, fmt.Errorf("recipe names must be unique and match %s: %q", toolNamePattern, cfg.Name)
+		}
+		names[cfg.Name] = true
+		recipe, err := newQueryRecipe(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("recipe %s: %w", cfg.Name, err)
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes, nil
+}
+
+func newQueryRecipe(cfg QueryRecipeConfig) (*queryRecipe, error) {
+	tmpl, err := template.New(cfg.Name).Option("missingkey=error").Parse(cfg.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	r := &queryRecipe{cfg: cfg, tmpl: tmpl, patterns: make(map[string]*regexp.Regexp)}
+	r.cfg.Inputs = append([]RecipeInput(nil), cfg.Inputs...)
+
+	declared := make(map[string]bool, len(r.cfg.Inputs))
+	sample := make(map[string]interface{}, len(r.cfg.Inputs))
+	for i := range r.cfg.Inputs {
+		in := &r.cfg.Inputs[i]
+		if in.Name == "" || declared[in.Name] {
+			return nil, fmt.Errorf("input names must be unique and non-empty")
+		}
+		declared[in.Name] = true
+		switch in.Type {
+		case "":
+			in.Type = ParamString
+		case ParamString, ParamInteger, ParamNumber, ParamBoolean:
+		default:
+			return nil, fmt.Errorf("input %s has unsupported type %s", in.Name, in.Type)
+		}
+		if in.Pattern != "" {
+			pattern, err := regexp.Compile(`^(?:` + in.Pattern + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("input %s has an invalid pattern: %w", in.Name, err)
+			}
+			r.patterns[in.Name] = pattern
+		}
+		if in.Default != nil {
+			if _, err := r.coerce(*in, in.Default); err != nil {
+				return nil, fmt.Errorf("invalid default: %w", err)
+			}
+		}
+		sample[in.Name] = in.Default
+	}
+
+	// Render with the defaults, or the first allowed value, so that typos
+	// and writes are caught when the configuration is loaded
+	for _, in := range r.cfg.Inputs {
+		if sample[in.Name] != nil {
+			continue
+		}
+		switch {
+		case len(in.Enum) > 0:
+			sample[in.Name] = in.Enum[0]
+		case in.Type == ParamInteger && in.Minimum != nil:
+			sample[in.Name] = int64(*in.Minimum)
+		case in.Type == ParamInteger:
+			sample[in.Name] = int64(0)
+		case in.Type == ParamNumber && in.Minimum != nil:
+			sample[in.Name] = *in.Minimum
+		case in.Type == ParamNumber:
+			sample[in.Name] = 0.0
+		case in.Type == ParamBoolean:
+			sample[in.Name] = false
+		}
+	}
+	if _, err := r.render(sample); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// shapes reports whether an input can shape the template; free-text
+// strings are only bound as parameters
+func (in RecipeInput) shapes() bool {
+	return in.Type != ParamString || len(in.Enum) > 0
+}
+
+// coerce converts an argument to the input's type and checks its
+// constraints
+func (r *queryRecipe) coerce(in RecipeInput, value interface{}) (interface{}, error) {
+	v, err := coerceParam(in.SavedQueryParameter, value)
+	if err != nil {
+		return nil, err
+	}
+	switch x := v.(type) {
+	case int64:
+		err = checkRange(in, float64(x))
+	case float64:
+		err = checkRange(in, x)
+	case string:
+		n := utf8.RuneCountInString(x)
+		switch {
+		case n < in.MinLength:
+			err = fmt.Errorf("input %s must be at least %d characters", in.Name, in.MinLength)
+		case in.MaxLength > 0 && n > in.MaxLength:
+			err = fmt.Errorf("input %s must be at most %d characters", in.Name, in.MaxLength)
+		case r.patterns[in.Name] != nil && !r.patterns[in.Name].MatchString(x):
+			err = fmt.Errorf("input %s must match %s", in.Name, in.Pattern)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func checkRange(in RecipeInput, v float64) error {
+	if in.Minimum != nil && v < *in.Minimum {
+		return fmt.Errorf("input %s must be at least %v", in.Name, *in.Minimum)
+	}
+	if in.Maximum != nil && v > *in.Maximum {
+		return fmt.Errorf("input %s must be at most %v", in.Name, *in.Maximum)
+	}
+	return nil
+}
+
+// bind validates arguments against the inputs and renders the recipe's
+// SQL, returning it with its parameters
+func (r *queryRecipe) bind(args map[string]interface{}) (string, map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(r.cfg.Inputs))
+	for _, in := range r.cfg.Inputs {
+		value, ok := args[in.Name]
+		if !ok || value == nil {
+			if in.Required {
+				return "", nil, fmt.Errorf("%w: missing required input %s", ErrInvalidRecipeInput, in.Name)
+			}
+			value = in.Default
+		}
+		if value != nil {
+			v, err := r.coerce(in, value)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: %v", ErrInvalidRecipeInput, err)
+			}
+			value = v
+		}
+		params[in.Name] = value
+	}
+	for name := range args {
+		if _, ok := params[name]; !ok {
+			return "", nil, fmt.Errorf("%w: unknown input %s", ErrInvalidRecipeInput, name)
+		}
+	}
+	query, err := r.render(params)
+	if err != nil {
+		return "", nil, err
+	}
+	return query, params, nil
+}
+
+// render executes the template over the inputs that can shape it and
+// checks the statement it yields
+func (r *queryRecipe) render(values map[string]interface{}) (string, error) {
+	data := make(map[string]interface{}, len(values))
+	for _, in := range r.cfg.Inputs {
+		if in.shapes() {
+			data[in.Name] = values[in.Name]
+		}
+	}
+	var b strings.Builder
+	if err := r.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render recipe %s: %w", r.cfg.Name, err)
+	}
+	query := strings.TrimSpace(b.String())
+	if !isReadOnlySQL(query) {
+		return "", fmt.Errorf("recipe %s must render a single read-only statement", r.cfg.Name)
+	}
+	for _, m := range namedParamPattern.FindAllStringSubmatch(query, -1) {
+		if _, ok := values[m[1]]; !ok {
+			return "", fmt.Errorf("recipe %s: placeholder :%s is not a declared input", r.cfg.Name, m[1])
+		}
+	}
+	return query, nil
+}
+
+// inputSchema describes the inputs as the JSON Schema of an MCP tool
+func (r *queryRecipe) inputSchema() mcp.ToolInputSchema {
+	params := make([]SavedQueryParameter, len(r.cfg.Inputs))
+	for i, in := range r.cfg.Inputs {
+		params[i] = in.SavedQueryParameter
+	}
+	schema := (&SavedQuery{Parameters: params}).inputSchema()
+	for _, in := range r.cfg.Inputs {
+		prop := schema.Properties[in.Name].(map[string]any)
+		if in.Minimum != nil {
+			prop["minimum"] = *in.Minimum
+		}
+		if in.Maximum != nil {
+			prop["maximum"] = *in.Maximum
+		}
+		if in.MinLength > 0 {
+			prop["minLength"] = in.MinLength
+		}
+		if in.MaxLength > 0 {
+			prop["maxLength"] = in.MaxLength
+		}
+		if in.Pattern != "" {
+			prop["pattern"] = in.Pattern
+		}
+	}
+	return schema
+}
+
+// recipeTools exposes every query recipe as an MCP tool
+func (s *MCPServerWithDB) recipeTools() []mcpTool {
+	tools := make([]mcpTool, 0, len(s.recipes))
+	for _, recipe := range s.recipes {
+		tools = append(tools, mcpTool{
+			Schema: mcp.ToolSchema{
+				Name:        recipe.cfg.Name,
+				Description: recipe.cfg.Description,
+				InputSchema: recipe.inputSchema(),
+			},
+			Handler: func(ctx context.Context, sess *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				query, params, err := recipe.bind(args)
+				if err != nil {
+					return nil, err
+				}
+				if err := s.checkFreeForm(ctx); err != nil {
+					return nil, err
+				}
+				if err := s.checkQueryPolicy(ctx, "recipe", query, params); err != nil {
+					return nil, err
+				}
+				if staged, err := s.stageAction(ctx, recipe.cfg.Name, query, params); staged != nil || err != nil {
+					return staged, err
+				}
+				result, err := s.executeTracked(ctx, query, params)
+				if err != nil {
+					return nil, err
+				}
+				return s.rowsToolResult(recipe.cfg.Name, sess, result)
+			},
+		})
+	}
+	return tools
+}
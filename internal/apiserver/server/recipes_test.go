@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRecipes(t *testing.T) {
+	maxDays := 365.0
+	recipes, err := newQueryRecipes([]QueryRecipeConfig{{
+		Name:        "top_customers",
+		Description: "Top customers of a region by a metric",
+		Template: `SELECT CUSTOMER, SUM({{.metric}}) AS VALUE FROM ORDERS
+WHERE REGION = :region AND ORDERED_AT > DATEADD(day, -:days, CURRENT_DATE)
+{{if .exclude_test}}AND CUSTOMER NOT LIKE 'test%'{{end}}
+GROUP BY CUSTOMER ORDER BY VALUE DESC LIMIT {{.limit}}`,
+		Inputs: []RecipeInput{
+			{SavedQueryParameter: SavedQueryParameter{Name: "metric", Enum: []interface{}{"TOTAL", "QUANTITY"}, Default: "TOTAL"}},
+			{SavedQueryParameter: SavedQueryParameter{Name: "region", Required: true}, MaxLength: 8, Pattern: "[A-Z]+"},
+			{SavedQueryParameter: SavedQueryParameter{Name: "days", Type: ParamInteger, Default: 30.0}, Maximum: &maxDays},
+			{SavedQueryParameter: SavedQueryParameter{Name: "exclude_test", Type: ParamBoolean}},
+			{SavedQueryParameter: SavedQueryParameter{Name: "limit", Type: ParamInteger, Default: 10.0}},
+		},
+	}})
+	require.NoError(t, err)
+
+	conn := &paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"CUSTOMER": "Acme"}}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, recipes: recipes}
+	tools := s.recipeTools()
+	require.Len(t, tools, 1)
+	schema := tools[0].Schema.InputSchema
+	assert.Equal(t, []string{"region"}, schema.Required)
+	assert.Equal(t, 365.0, schema.Properties["days"].(map[string]any)["maximum"])
+	assert.Equal(t, "[A-Z]+", schema.Properties["region"].(map[string]any)["pattern"])
+
+	// Constrained inputs shape the statement; every input is bound
+	_, err = tools[0].Handler(context.Background(), &mcpSession{}, map[string]interface{}{
+		"metric": "QUANTITY", "region": "EMEA", "exclude_test": true, "limit": 5.0,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, conn.query, "SUM(QUANTITY)")
+	assert.Contains(t, conn.query, "NOT LIKE 'test%'")
+	assert.Contains(t, conn.query, "LIMIT 5")
+	assert.Equal(t, map[string]interface{}{
+		"metric": "QUANTITY", "region": "EMEA", "days": int64(30), "exclude_test": true, "limit": int64(5),
+	}, conn.params)
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"region": "EMEA", "metric": "PRICE"},
+		{"region": "emea"},
+		{"region": "EUROPEANS"},
+		{"region": "EMEA", "days": 400.0},
+		{"region": "EMEA", "limit": "ten"},
+		{"region": "EMEA", "sql": "DROP TABLE ORDERS"},
+	} {
+		_, err := tools[0].Handler(context.Background(), &mcpSession{}, args)
+		assert.ErrorIs(t, err, ErrInvalidRecipeInput, args)
+	}
+}
+
+func TestNewQueryRecipesRejectsInvalid(t *testing.T) {
+	for _, cfg := range []QueryRecipeConfig{
+		{Name: "bad name", Template: "SELECT 1"},
+		{Name: "purge", Template: "DELETE FROM ORDERS"},
+		{Name: "orders", Template: "SELECT * FROM {{.table"},
+		{Name: "orders", Template: "SELECT * FROM ORDERS WHERE ID = :id"},
+		// Free-text inputs cannot shape the statement
+		{Name: "orders", Template: "SELECT * FROM ORDERS WHERE {{.filter}}", Inputs: []RecipeInput{
+			{SavedQueryParameter: SavedQueryParameter{Name: "filter"}},
+		}},
+		{Name: "orders", Template: "SELECT * FROM ORDERS", Inputs: []RecipeInput{
+			{SavedQueryParameter: SavedQueryParameter{Name: "id"}, Pattern: "("},
+		}},
+		{Name: "orders", Template: "SELECT * FROM ORDERS", Inputs: []RecipeInput{
+			{SavedQueryParameter: SavedQueryParameter{Name: "days", Type: ParamInteger, Default: 0.0}, Minimum: &[]float64{1}[0]},
+		}},
+	} {
+		_, err := newQueryRecipes([]QueryRecipeConfig{cfg})
+		assert.Error(t, err, cfg.Template)
+	}
+}
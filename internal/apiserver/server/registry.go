@@ -0,0 +1,846 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/secrets"
+	"gorm.io/gorm"
+)
+
+// Registry errors
+var (
+	ErrServerNotFound = errors.New("server not found")
+	ErrServerExists   = errors.New("server already exists")
+	ErrInvalidServer  = errors.New("invalid server definition")
+)
+
+// redactedSecret replaces secrets in server definitions returned by the API
+const redactedSecret = "********"
+
+// ServerDefinition is a persisted server configuration together with the
+// endpoints generated for it
+type ServerDefinition struct {
+	// Tenant owning the server; empty for servers managed by the operator
+	Tenant string `json:"tenant,omitempty"`
+
+	Name      string                  `json:"name"`
+	Config    *MCPServerConfig        `json:"config"`
+	Endpoints []connector.APIEndpoint `json:"endpoints,omitempty"`
+
+	// Running is the desired state, restored when the registry starts
+	Running bool `json:"running"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ServerStatus describes a registered server and its runtime state
+type ServerStatus struct {
+	ServerDefinition
+	Active    bool   `json:"active"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// serverRow is the persisted form of a ServerDefinition
+type serverRow struct {
+	Tenant    string `gorm:"primaryKey"`
+	Name      string `gorm:"primaryKey"`
+	Config    string
+	Endpoints string
+	Running   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName overrides the table name used by serverRow
+func (serverRow) TableName() string {
+	return "server_definitions"
+}
+
+// toDefinition decodes a persisted definition, opening its sealed secrets
+func (r *serverRow) toDefinition(ctx context.Context, keys *secrets.Keyring) (*ServerDefinition, error) {
+	def := &ServerDefinition{
+		Tenant:    r.Tenant,
+		Name:      r.Name,
+		Running:   r.Running,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	cfg, err := FromJSON([]byte(r.Config))
+	if err != nil {
+		return nil, err
+	}
+	for path, field := range secretFields(cfg) {
+		if !secrets.IsSealed(field.get()) {
+			continue
+		}
+		if keys == nil {
+			return nil, fmt.Errorf("%w: %s is encrypted", secrets.ErrNoKey, path)
+		}
+		opened, err := keys.Open(ctx, field.get(), secretBinding(r.Tenant, r.Name, path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		field.set(opened)
+	}
+	def.Config = cfg
+	if r.Endpoints != "" {
+		if err := json.Unmarshal([]byte(r.Endpoints), &def.Endpoints); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal endpoints: %w", err)
+		}
+	}
+	return def, nil
+}
+
+// ServerStore persists server definitions in the gateway's state store
+type ServerStore struct {
+	db *gorm.DB
+
+	// Secrets seals the secrets of stored configurations; nil stores them
+	// in plaintext
+	Secrets *secrets.Keyring
+}
+
+// NewServerStore creates a store on top of the state store. The table is
+// created by the state store migrations.
+func NewServerStore(db *gorm.DB) *ServerStore {
+	return &ServerStore{
+		db: db,
+	}
+}
+
+// List returns the definitions of a tenant ordered by name
+func (s *ServerStore) List(ctx context.Context, tenant string) ([]*ServerDefinition, error) {
+	return s.find(ctx, s.db.WithContext(ctx).Where("tenant = ?", tenant).Order("name"))
+}
+
+// ListAll returns the definitions of every tenant
+func (s *ServerStore) ListAll(ctx context.Context) ([]*ServerDefinition, error) {
+	return s.find(ctx, s.db.WithContext(ctx).Order("tenant, name"))
+}
+
+// Count returns the number of definitions of a tenant
+func (s *ServerStore) Count(ctx context.Context, tenant string) (int, error) {
+	var n int64
+	if err := s.db.WithContext(ctx).Model(&serverRow{}).Where("tenant = ?", tenant).Count(&n).Error; err != nil {
+		return 0, fmt.Errorf("failed to count servers: %w", err)
+	}
+	return int(n), nil
+}
+
+// find decodes the definitions matched by a query
+func (s *ServerStore) find(ctx context.Context, query *gorm.DB) ([]*ServerDefinition, error) {
+	var rows []serverRow
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+	defs := make([]*ServerDefinition, 0, len(rows))
+	for i := range rows {
+		def, err := rows[i].toDefinition(ctx, s.Secrets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode server %s: %w", rows[i].Name, err)
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+// Get returns a tenant's definition by name
+func (s *ServerStore) Get(ctx context.Context, tenant, name string) (*ServerDefinition, error) {
+	var row serverRow
+	err := s.db.WithContext(ctx).Where("tenant = ? AND name = ?", tenant, name).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrServerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server: %w", err)
+	}
+	return row.toDefinition(ctx, s.Secrets)
+}
+
+// Create stores a new definition
+func (s *ServerStore) Create(ctx context.Context, def *ServerDefinition) error {
+	if _, err := s.Get(ctx, def.Tenant, def.Name); err == nil {
+		return ErrServerExists
+	} else if !errors.Is(err, ErrServerNotFound) {
+		return err
+	}
+
+	row, err := toServerRow(ctx, def, s.Secrets)
+	if err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	def.CreatedAt, def.UpdatedAt = row.CreatedAt, row.UpdatedAt
+	return nil
+}
+
+// Save updates an existing definition
+func (s *ServerStore) Save(ctx context.Context, def *ServerDefinition) error {
+	row, err := toServerRow(ctx, def, s.Secrets)
+	if err != nil {
+		return err
+	}
+	result := s.db.WithContext(ctx).Model(&serverRow{}).Where("tenant = ? AND name = ?", def.Tenant, def.Name).Updates(map[string]interface{}{
+		"config":     row.Config,
+		"endpoints":  row.Endpoints,
+		"running":    row.Running,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update server: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrServerNotFound
+	}
+	return nil
+}
+
+// Delete removes a tenant's definition
+func (s *ServerStore) Delete(ctx context.Context, tenant, name string) error {
+	result := s.db.WithContext(ctx).Where("tenant = ? AND name = ?", tenant, name).Delete(&serverRow{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete server: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrServerNotFound
+	}
+	return nil
+}
+
+// RotateSecrets seals the secrets of the stored definitions that are in
+// plaintext or sealed under a previous key with the current key, returning
+// the number of definitions rewritten
+func (s *ServerStore) RotateSecrets(ctx context.Context) (int, error) {
+	if s.Secrets == nil {
+		return 0, fmt.Errorf("no encryption keys are configured")
+	}
+	var rows []serverRow
+	if err := s.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return 0, fmt.Errorf("failed to list servers: %w", err)
+	}
+	rotated := 0
+	for i := range rows {
+		cfg, err := FromJSON([]byte(rows[i].Config))
+		if err != nil {
+			return rotated, fmt.Errorf("failed to decode server %s: %w", rows[i].Name, err)
+		}
+		stale := false
+		for _, field := range secretFields(cfg) {
+			stale = stale || s.Secrets.NeedsRotation(field.get())
+		}
+		if !stale {
+			continue
+		}
+
+		def, err := rows[i].toDefinition(ctx, s.Secrets)
+		if err != nil {
+			return rotated, fmt.Errorf("failed to decode server %s: %w", rows[i].Name, err)
+		}
+		row, err := toServerRow(ctx, def, s.Secrets)
+		if err != nil {
+			return rotated, err
+		}
+		// Rotation leaves the definition unchanged, and so its update time
+		err = s.db.WithContext(ctx).Model(&serverRow{}).Where("tenant = ? AND name = ?", row.Tenant, row.Name).
+			UpdateColumn("config", row.Config).Error
+		if err != nil {
+			return rotated, fmt.Errorf("failed to update server %s: %w", row.Name, err)
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+// secretBinding is the additional data sealing a secret of a definition,
+// so that sealed values cannot be moved to another server or field
+func secretBinding(tenant, name, path string) []byte {
+	return []byte(tenant + "/" + name + "/" + path)
+}
+
+// toServerRow encodes a definition for persistence, sealing its secrets
+// when keys are configured
+func toServerRow(ctx context.Context, def *ServerDefinition, keys *secrets.Keyring) (*serverRow, error) {
+	stored := def.Config
+	if keys != nil && def.Config != nil {
+		data, err := json.Marshal(def.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal server configuration: %w", err)
+		}
+		if stored, err = FromJSON(data); err != nil {
+			return nil, err
+		}
+		for path, field := range secretFields(stored) {
+			if field.get() == "" {
+				continue
+			}
+			sealed, err := keys.Seal(ctx, field.get(), secretBinding(def.Tenant, def.Name, path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt %s: %w", path, err)
+			}
+			field.set(sealed)
+		}
+	}
+	cfg, err := json.Marshal(stored)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server configuration: %w", err)
+	}
+	row := &serverRow{
+		Tenant:    def.Tenant,
+		Name:      def.Name,
+		Config:    string(cfg),
+		Running:   def.Running,
+		CreatedAt: def.CreatedAt,
+		UpdatedAt: def.UpdatedAt,
+	}
+	if len(def.Endpoints) > 0 {
+		endpoints, err := json.Marshal(def.Endpoints)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal endpoints: %w", err)
+		}
+		row.Endpoints = string(endpoints)
+	}
+	return row, nil
+}
+
+// Registry manages the lifecycle of the servers defined in a ServerStore.
+// Servers are isolated per tenant: each has its own connector, caches and
+// credentials, and tenants can only address their own servers.
+type Registry struct {
+	store   *ServerStore
+	tenants *TenantStore
+	audit   audit.Recorder
+
+	// Events receives tenant policy violations; nil discards them
+	Events *events.Bus
+
+	// Federation bounds federated queries; nil applies the defaults
+	Federation *FederationConfig
+
+	// Admin restricts the management routes to callers of admin roles;
+	// nil admits the admin role
+	Admin *AdminConfig
+
+	mutex   sync.Mutex
+	servers map[string]*MCPServerWithDB
+	errors  map[string]string
+}
+
+// NewRegistry creates a registry. recorder may be nil.
+func NewRegistry(store *ServerStore, recorder audit.Recorder) *Registry {
+	return &Registry{
+		store:   store,
+		tenants: NewTenantStore(store.db),
+		audit:   recorder,
+		servers: make(map[string]*MCPServerWithDB),
+		errors:  make(map[string]string),
+	}
+}
+
+// Restore starts every server whose desired state is running. Failures are
+// recorded on the server's status rather than aborting the restore.
+func (r *Registry) Restore(ctx context.Context) error {
+	defs, err := r.store.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, def := range defs {
+		if _, ok := r.servers[serverKey(def.Tenant, def.Name)]; ok || !def.Running {
+			continue
+		}
+		if err := r.startLocked(def); err != nil {
+			log.Printf("Warning: Failed to restore server %s: %v", serverKey(def.Tenant, def.Name), err)
+		}
+	}
+	return nil
+}
+
+// List returns the status of every server of a tenant
+func (r *Registry) List(ctx context.Context, tenant string) ([]*ServerStatus, error) {
+	defs, err := r.store.List(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	statuses := make([]*ServerStatus, 0, len(defs))
+	for _, def := range defs {
+		statuses = append(statuses, r.statusLocked(def))
+	}
+	return statuses, nil
+}
+
+// Get returns the status of a tenant's server
+func (r *Registry) Get(ctx context.Context, tenant, name string) (*ServerStatus, error) {
+	def, err := r.store.Get(ctx, tenant, name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.statusLocked(def), nil
+}
+
+// Create registers a new server, starting it when its desired state is running
+func (r *Registry) Create(ctx context.Context, def *ServerDefinition, principal string) (*ServerStatus, error) {
+	if def.Name == "" && def.Config != nil {
+		def.Name = def.Config.Name
+	}
+	if err := validateDefinition(def); err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if err := r.checkTenantPolicy(ctx, def, true); err != nil {
+		r.publishPolicyViolation(def, err)
+		return nil, err
+	}
+	if err := r.store.Create(ctx, def); err != nil {
+		return nil, err
+	}
+	r.record(ctx, "server_create", principal, serverKey(def.Tenant, def.Name), nil)
+
+	if def.Running {
+		if err := r.startLocked(def); err != nil {
+			log.Printf("Warning: Failed to start server %s: %v", serverKey(def.Tenant, def.Name), err)
+		}
+	}
+	return r.statusLocked(def), nil
+}
+
+// Update replaces a server's configuration and restarts it if it is running.
+// Redacted secrets in cfg keep their stored values.
+func (r *Registry) Update(ctx context.Context, tenant, name string, cfg *MCPServerConfig, principal string) (*ServerStatus, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	def, err := r.store.Get(ctx, tenant, name)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Name = name
+	restoreSecrets(cfg, def.Config)
+	def.Config = cfg
+	if err := validateDefinition(def); err != nil {
+		return nil, err
+	}
+	if err := r.checkTenantPolicy(ctx, def, false); err != nil {
+		r.publishPolicyViolation(def, err)
+		return nil, err
+	}
+	if err := r.store.Save(ctx, def); err != nil {
+		return nil, err
+	}
+	key := serverKey(tenant, name)
+	r.record(ctx, "server_update", principal, key, nil)
+
+	if _, ok := r.servers[key]; ok {
+		r.stopLocked(key)
+		if err := r.startLocked(def); err != nil {
+			log.Printf("Warning: Failed to restart server %s: %v", key, err)
+		}
+	}
+	return r.statusLocked(def), nil
+}
+
+// Delete stops a server and removes its definition
+func (r *Registry) Delete(ctx context.Context, tenant, name string, principal string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.deleteLocked(ctx, tenant, name, principal)
+}
+
+// deleteLocked stops a server and removes its definition
+func (r *Registry) deleteLocked(ctx context.Context, tenant, name string, principal string) error {
+	if err := r.store.Delete(ctx, tenant, name); err != nil {
+		return err
+	}
+	key := serverKey(tenant, name)
+	r.stopLocked(key)
+	delete(r.errors, key)
+	r.record(ctx, "server_delete", principal, key, nil)
+	return nil
+}
+
+// Start starts a registered server and persists running as its desired state
+func (r *Registry) Start(ctx context.Context, tenant, name string, principal string) (*ServerStatus, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	def, err := r.store.Get(ctx, tenant, name)
+	if err != nil {
+		return nil, err
+	}
+	def.Running = true
+	if err := r.store.Save(ctx, def); err != nil {
+		return nil, err
+	}
+	key := serverKey(tenant, name)
+	r.record(ctx, "server_start", principal, key, nil)
+
+	if _, ok := r.servers[key]; !ok {
+		if err := r.startLocked(def); err != nil {
+			return r.statusLocked(def), err
+		}
+	}
+	return r.statusLocked(def), nil
+}
+
+// Stop stops a registered server and persists stopped as its desired state
+func (r *Registry) Stop(ctx context.Context, tenant, name string, principal string) (*ServerStatus, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	def, err := r.store.Get(ctx, tenant, name)
+	if err != nil {
+		return nil, err
+	}
+	def.Running = false
+	if err := r.store.Save(ctx, def); err != nil {
+		return nil, err
+	}
+	key := serverKey(tenant, name)
+	r.record(ctx, "server_stop", principal, key, nil)
+
+	r.stopLocked(key)
+	return r.statusLocked(def), nil
+}
+
+// Shutdown stops all running servers without changing their desired state,
+// so they are started again by the next Restore
+func (r *Registry) Shutdown() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	keys := make([]string, 0, len(r.servers))
+	for key := range r.servers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		r.stopLocked(key)
+	}
+}
+
+// server returns the running instance of a tenant's server
+func (r *Registry) server(tenant, name string) (*MCPServerWithDB, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	srv, ok := r.servers[serverKey(tenant, name)]
+	return srv, ok
+}
+
+// startLocked creates and starts a server instance from its definition. A
+// stopped server cannot be restarted, so every start creates a new instance.
+// Tenant servers are not given a listener of their own; their API is
+// mounted under the tenant's routes instead.
+func (r *Registry) startLocked(def *ServerDefinition) error {
+	key := serverKey(def.Tenant, def.Name)
+	srv, err := NewMCPServerWithDB(def.Config)
+	if err == nil {
+		srv.mounted = def.Tenant != ""
+		tenant, name := def.Tenant, def.Name
+		srv.OnEndpointsGenerated = func(endpoints []connector.APIEndpoint) {
+			r.saveEndpoints(tenant, name, endpoints)
+		}
+		if err = srv.RegisterEndpoints(def.Endpoints); err == nil {
+			err = srv.Start()
+		}
+	}
+	if err != nil {
+		r.errors[key] = err.Error()
+		return err
+	}
+
+	delete(r.errors, key)
+	r.servers[key] = srv
+	return nil
+}
+
+// stopLocked stops a server instance if it is running
+func (r *Registry) stopLocked(key string) {
+	srv, ok := r.servers[key]
+	if !ok {
+		return
+	}
+	if err := srv.Stop(); err != nil {
+		log.Printf("Warning: Failed to stop server %s: %v", key, err)
+	}
+	if srv.State != nil {
+		_ = srv.State.Close()
+	}
+	delete(r.servers, key)
+}
+
+// statusLocked combines a definition with the server's runtime state
+func (r *Registry) statusLocked(def *ServerDefinition) *ServerStatus {
+	key := serverKey(def.Tenant, def.Name)
+	status := &ServerStatus{
+		ServerDefinition: *def,
+		LastError:        r.errors[key],
+	}
+	status.Config = redactSecrets(def.Config)
+	if srv, ok := r.servers[key]; ok {
+		status.Active = srv.IsRunning()
+	}
+	return status
+}
+
+// saveEndpoints persists the generated endpoints of a running server
+func (r *Registry) saveEndpoints(tenant, name string, endpoints []connector.APIEndpoint) {
+	ctx := context.Background()
+	def, err := r.store.Get(ctx, tenant, name)
+	if err == nil {
+		def.Endpoints = endpoints
+		err = r.store.Save(ctx, def)
+	}
+	if err != nil {
+		log.Printf("Warning: Failed to persist endpoints of server %s: %v", serverKey(tenant, name), err)
+	}
+}
+
+// record writes an audit event when a recorder is configured
+func (r *Registry) record(ctx context.Context, action, principal, resource string, details map[string]interface{}) {
+	if r.audit == nil {
+		return
+	}
+	_ = r.audit.Record(ctx, &audit.Event{
+		Action:    action,
+		Principal: principal,
+		Resource:  resource,
+		Details:   details,
+	})
+}
+
+// serverKey identifies a server across tenants
+func serverKey(tenant, name string) string {
+	if tenant == "" {
+		return name
+	}
+	return tenant + "/" + name
+}
+
+// validateDefinition checks a definition before it is stored
+func validateDefinition(def *ServerDefinition) error {
+	if def.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidServer)
+	}
+	if def.Config == nil {
+		return fmt.Errorf("%w: config is required", ErrInvalidServer)
+	}
+	if def.Config.Name == "" {
+		def.Config.Name = def.Name
+	}
+	if def.Config.Name != def.Name {
+		return fmt.Errorf("%w: config name %q does not match server name %q", ErrInvalidServer, def.Config.Name, def.Name)
+	}
+	if def.Config.ToolResults != nil {
+		if err := def.Config.ToolResults.validate(); err != nil {
+			return fmt.Errorf("%w: invalid tool result configuration: %v", ErrInvalidServer, err)
+		}
+	}
+	return nil
+}
+
+// secretField is a secret of a configuration: a string field, the value
+// of a header, or the password of the user info of a URL, or its user when
+// it has no password
+type secretField struct {
+	field   *string
+	headers map[string]string
+	name    string
+	url     *string
+}
+
+func (f secretField) get() string {
+	if f.url != nil {
+		u, err := url.Parse(*f.url)
+		if err != nil || u.User == nil {
+			return ""
+		}
+		if password, ok := u.User.Password(); ok {
+			return password
+		}
+		return u.User.Username()
+	}
+	if f.field != nil {
+		return *f.field
+	}
+	return f.headers[f.name]
+}
+
+func (f secretField) set(value string) {
+	if f.url != nil {
+		u, err := url.Parse(*f.url)
+		if err != nil || u.User == nil {
+			return
+		}
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), value)
+		} else {
+			u.User = url.User(value)
+		}
+		*f.url = u.String()
+		return
+	}
+	if f.field != nil {
+		*f.field = value
+		return
+	}
+	f.headers[f.name] = value
+}
+
+// secretFields returns the secret fields of a configuration keyed by path
+func secretFields(cfg *MCPServerConfig) map[string]secretField {
+	fields := make(map[string]secretField)
+	if cfg == nil {
+		return fields
+	}
+	if db := cfg.Database; db != nil {
+		if sf := db.Snowflake; sf != nil {
+			fields["database.snowflake.password"] = secretField{field: &sf.Password}
+			fields["database.snowflake.private_key"] = secretField{field: &sf.PrivateKey}
+		}
+		if custom, ok := db.Custom.(connector.SecretConfig); ok {
+			for name, field := range custom.SecretFields() {
+				fields["database."+db.Type+"."+name] = secretField{field: field}
+			}
+		}
+		if db.LLM != nil {
+			fields["database.llm.api_key"] = secretField{field: &db.LLM.APIKey}
+		}
+	}
+	if cfg.ReadReplicas != nil {
+		for _, r := range cfg.ReadReplicas.Databases {
+			databaseSecretFields(fields, "read_replicas."+r.Name, r.Database)
+		}
+	}
+	for _, conn := range cfg.Connections {
+		databaseSecretFields(fields, "connections."+conn.Label, conn.Database)
+	}
+	if cfg.Embedding != nil {
+		fields["embedding.api_key"] = secretField{field: &cfg.Embedding.APIKey}
+	}
+	if cfg.RowSecurity != nil {
+		fields["row_security.jwt_secret"] = secretField{field: &cfg.RowSecurity.JWTSecret}
+	}
+	if cfg.Admin != nil {
+		fields["admin.jwt_secret"] = secretField{field: &cfg.Admin.JWTSecret}
+	}
+	if cfg.Watermark != nil {
+		fields["watermark.secret"] = secretField{field: &cfg.Watermark.Secret}
+	}
+	for i := range cfg.ScheduledQueries {
+		q := &cfg.ScheduledQueries[i]
+		if q.S3 != nil {
+			fields["scheduled_queries."+q.Name+".s3.secret_access_key"] = secretField{field: &q.S3.SecretAccessKey}
+		}
+		headerSecretFields(fields, "scheduled_queries."+q.Name+".headers", q.Headers)
+	}
+	if cfg.SchemaWatch != nil {
+		headerSecretFields(fields, "schema_watch.headers", cfg.SchemaWatch.Headers)
+	}
+	if cfg.Exports != nil {
+		headerSecretFields(fields, "exports.headers", cfg.Exports.Headers)
+	}
+	if cfg.Events != nil {
+		for i := range cfg.Events.Sinks {
+			sink := &cfg.Events.Sinks[i]
+			prefix := fmt.Sprintf("events.sinks.%d", i)
+			fields[prefix+".token"] = secretField{field: &sink.Token}
+			if u, err := url.Parse(sink.URL); err == nil && u.User != nil {
+				fields[prefix+".url"] = secretField{url: &sink.URL}
+			}
+			headerSecretFields(fields, prefix+".headers", sink.Headers)
+		}
+	}
+	if cfg.Catalog != nil {
+		fields["catalog.token"] = secretField{field: &cfg.Catalog.Token}
+		headerSecretFields(fields, "catalog.headers", cfg.Catalog.Headers)
+	}
+	if cfg.SpendReport != nil {
+		headerSecretFields(fields, "spend_report.headers", cfg.SpendReport.Headers)
+	}
+	return fields
+}
+
+// headerSecretFields adds the values of headers, which carry credentials
+// such as authorization tokens, under a path prefix
+func headerSecretFields(fields map[string]secretField, prefix string, headers map[string]string) {
+	for name := range headers {
+		fields[prefix+"."+name] = secretField{headers: headers, name: name}
+	}
+}
+
+// databaseSecretFields adds the secret fields of a further database of a
+// server, e.g. a read replica, under a path prefix
+func databaseSecretFields(fields map[string]secretField, prefix string, db *connector.DatabaseConfig) {
+	if db == nil {
+		return
+	}
+	if sf := db.Snowflake; sf != nil {
+		fields[prefix+".snowflake.password"] = secretField{field: &sf.Password}
+		fields[prefix+".snowflake.private_key"] = secretField{field: &sf.PrivateKey}
+	}
+	if custom, ok := db.Custom.(connector.SecretConfig); ok {
+		for name, field := range custom.SecretFields() {
+			fields[prefix+"."+db.Type+"."+name] = secretField{field: field}
+		}
+	}
+	if db.LLM != nil {
+		fields[prefix+".llm.api_key"] = secretField{field: &db.LLM.APIKey}
+	}
+}
+
+// redactSecrets returns a copy of the configuration with secrets masked
+func redactSecrets(cfg *MCPServerConfig) *MCPServerConfig {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil
+	}
+	redacted, err := FromJSON(data)
+	if err != nil {
+		return nil
+	}
+	for _, field := range secretFields(redacted) {
+		if field.get() != "" {
+			field.set(redactedSecret)
+		}
+	}
+	return redacted
+}
+
+// restoreSecrets replaces redacted secrets in cfg with the values of the
+// stored configuration, so definitions read from the API can be sent back
+func restoreSecrets(cfg, stored *MCPServerConfig) {
+	previous := secretFields(stored)
+	for path, field := range secretFields(cfg) {
+		if field.get() != redactedSecret {
+			continue
+		}
+		if old, ok := previous[path]; ok {
+			field.set(old.get())
+		} else {
+			field.set("")
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRoutes configures the operator's admin routes for managing servers
+// and tenants, restricted to callers of the admin roles
+func (r *Registry) SetupRoutes(router *gin.RouterGroup) {
+	router = router.Group("", newAdminAuth(r.Admin, nil).middleware(nil))
+	r.setupServerRoutes(router, func(*gin.Context) string { return "" })
+	r.setupFederationRoutes(router, func(*gin.Context) string { return "" })
+	r.setupTenantAdminRoutes(router)
+	r.setupChargebackRoutes(router)
+}
+
+// SetupTenantRoutes configures the routes of each tenant under
+// /t/{tenant}: management of the tenant's own servers and the APIs of its
+// running servers under /t/{tenant}/servers/{name}/. Requests must carry one
+// of the tenant's API keys.
+func (r *Registry) SetupTenantRoutes(router gin.IRouter) {
+	group := router.Group("/t/:tenant", r.tenantAuth())
+	r.setupServerRoutes(group, func(c *gin.Context) string { return c.Param("tenant") })
+	r.setupFederationRoutes(group, func(c *gin.Context) string { return c.Param("tenant") })
+	group.Any("/servers/:name/*path", r.serveTenantAPI)
+}
+
+// setupServerRoutes configures the server management routes of the tenant
+// returned by tenantOf
+func (r *Registry) setupServerRoutes(router *gin.RouterGroup, tenantOf func(*gin.Context) string) {
+	router.GET("/admin/servers", func(c *gin.Context) {
+		servers, err := r.List(c.Request.Context(), tenantOf(c))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list servers: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, servers)
+	})
+
+	router.GET("/admin/servers/:name", func(c *gin.Context) {
+		server, err := r.Get(c.Request.Context(), tenantOf(c), c.Param("name"))
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to get server: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, server)
+	})
+
+	router.POST("/admin/servers", func(c *gin.Context) {
+		var def ServerDefinition
+		if err := c.ShouldBindJSON(&def); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		def.Tenant = tenantOf(c)
+
+		server, err := r.Create(c.Request.Context(), &def, principalFromContext(c))
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to create server: %v", err)})
+			return
+		}
+		c.JSON(http.StatusCreated, server)
+	})
+
+	router.PUT("/admin/servers/:name", func(c *gin.Context) {
+		var cfg MCPServerConfig
+		if err := c.ShouldBindJSON(&cfg); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		server, err := r.Update(c.Request.Context(), tenantOf(c), c.Param("name"), &cfg, principalFromContext(c))
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to update server: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, server)
+	})
+
+	router.DELETE("/admin/servers/:name", func(c *gin.Context) {
+		if err := r.Delete(c.Request.Context(), tenantOf(c), c.Param("name"), principalFromContext(c)); err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to delete server: %v", err)})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	router.POST("/admin/servers/:name/start", func(c *gin.Context) {
+		server, err := r.Start(c.Request.Context(), tenantOf(c), c.Param("name"), principalFromContext(c))
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to start server: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, server)
+	})
+
+	router.POST("/admin/servers/:name/stop", func(c *gin.Context) {
+		server, err := r.Stop(c.Request.Context(), tenantOf(c), c.Param("name"), principalFromContext(c))
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to stop server: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, server)
+	})
+}
+
+// setupTenantAdminRoutes configures the operator routes for managing tenants
+// and their API keys
+func (r *Registry) setupTenantAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/tenants", func(c *gin.Context) {
+		tenants, err := r.tenants.List(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list tenants: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, tenants)
+	})
+
+	router.GET("/admin/tenants/:tenant", func(c *gin.Context) {
+		tenant, err := r.tenants.Get(c.Request.Context(), c.Param("tenant"))
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to get tenant: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, tenant)
+	})
+
+	router.POST("/admin/tenants", func(c *gin.Context) {
+		var tenant Tenant
+		if err := c.ShouldBindJSON(&tenant); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		if err := r.tenants.Create(c.Request.Context(), &tenant); err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to create tenant: %v", err)})
+			return
+		}
+		r.record(c.Request.Context(), "tenant_create", principalFromContext(c), tenant.Name, nil)
+		c.JSON(http.StatusCreated, tenant)
+	})
+
+	router.PUT("/admin/tenants/:tenant/policy", func(c *gin.Context) {
+		var policy TenantPolicy
+		if err := c.ShouldBindJSON(&policy); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		name := c.Param("tenant")
+		if err := r.tenants.UpdatePolicy(c.Request.Context(), name, policy); err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to update tenant policy: %v", err)})
+			return
+		}
+		r.record(c.Request.Context(), "tenant_policy_update", principalFromContext(c), name, nil)
+		c.JSON(http.StatusOK, policy)
+	})
+
+	router.DELETE("/admin/tenants/:tenant", func(c *gin.Context) {
+		if err := r.DeleteTenant(c.Request.Context(), c.Param("tenant"), principalFromContext(c)); err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to delete tenant: %v", err)})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	router.GET("/admin/tenants/:tenant/keys", func(c *gin.Context) {
+		keys, err := r.tenants.ListKeys(c.Request.Context(), c.Param("tenant"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to list API keys: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, keys)
+	})
+
+	router.POST("/admin/tenants/:tenant/keys", func(c *gin.Context) {
+		var request struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		name := c.Param("tenant")
+		key, err := r.tenants.CreateKey(c.Request.Context(), name, request.Name)
+		if err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to create API key: %v", err)})
+			return
+		}
+		r.record(c.Request.Context(), "tenant_key_create", principalFromContext(c), name, map[string]interface{}{
+			"key_id": key.ID,
+			"name":   key.Name,
+		})
+		c.JSON(http.StatusCreated, key)
+	})
+
+	router.DELETE("/admin/tenants/:tenant/keys/:id", func(c *gin.Context) {
+		name := c.Param("tenant")
+		if err := r.tenants.DeleteKey(c.Request.Context(), name, c.Param("id")); err != nil {
+			c.JSON(registryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to delete API key: %v", err)})
+			return
+		}
+		r.record(c.Request.Context(), "tenant_key_delete", principalFromContext(c), name, map[string]interface{}{
+			"key_id": c.Param("id"),
+		})
+		c.Status(http.StatusNoContent)
+	})
+}
+
+// tenantAuth authenticates requests to a tenant's routes with one of the
+// tenant's API keys, passed as a bearer token or in X-API-Key
+func (r *Registry) tenantAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			key = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			return
+		}
+
+		tenant := c.Param("tenant")
+		apiKey, err := r.tenants.Authenticate(c.Request.Context(), tenant, key)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+
+		principal := tenant + ":" + apiKey.Name
+		ctx := context.WithValue(c.Request.Context(), principalKey{}, principal)
+		c.Request = c.Request.WithContext(context.WithValue(ctx, apiKeyKey{}, apiKey))
+		c.Next()
+	}
+}
+
+// serveTenantAPI forwards a request to the API of one of the tenant's
+// running servers
+func (r *Registry) serveTenantAPI(c *gin.Context) {
+	srv, ok := r.server(c.Param("tenant"), c.Param("name"))
+	if !ok || srv.APIRouter == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Server is not running or has no API"})
+		return
+	}
+
+	req := c.Request.Clone(c.Request.Context())
+	req.URL.Path = srv.apiPrefix + c.Param("path")
+	req.URL.RawPath = ""
+	srv.APIRouter.ServeHTTP(c.Writer, req)
+}
+
+// registryErrorStatus maps registry errors to HTTP status codes
+func registryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrServerNotFound), errors.Is(err, ErrTenantNotFound), errors.Is(err, ErrInvalidAPIKey):
+		return http.StatusNotFound
+	case errors.Is(err, ErrServerExists), errors.Is(err, ErrTenantExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidServer), errors.Is(err, ErrInvalidTenant):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrTenantPolicy):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/lineage"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// How a related table joins with a table
+const (
+	// JoinReferences joins a table referenced by one of the table's
+	// foreign keys
+	JoinReferences = "references"
+
+	// JoinReferencedBy joins a table with a foreign key to the table
+	JoinReferencedBy = "referenced_by"
+
+	// JoinSharedReference joins a table referencing the same table and
+	// columns as one of the table's foreign keys
+	JoinSharedReference = "shared_reference"
+)
+
+// joinOrder sorts joins by how directly they relate the tables
+var joinOrder = map[string]int{JoinReferences: 0, JoinReferencedBy: 1, JoinSharedReference: 2}
+
+// LineageConfig adds the lineage of a dbt project to the related tables
+type LineageConfig struct {
+	// DBTManifest is the path of the project's target/manifest.json
+	DBTManifest string `json:"dbt_manifest,omitempty"`
+}
+
+// RelatedTables are the tables a table joins with, from the foreign keys
+// of the schema, and the relations it is built from or feeds, from dbt
+// lineage when configured
+type RelatedTables struct {
+	Table      string             `json:"table"`
+	Joins      []TableJoin        `json:"joins"`
+	Upstream   []lineage.Relation `json:"upstream,omitempty"`
+	Downstream []lineage.Relation `json:"downstream,omitempty"`
+}
+
+// TableJoin is a table that joins with another and the suggested join
+// condition
+type TableJoin struct {
+	Table     string `json:"table"`
+	Relation  string `json:"relation"`
+	Condition string `json:"condition"`
+
+	// Via is the table both tables of a shared reference refer to
+	Via string `json:"via,omitempty"`
+}
+
+// newLineage loads the lineage of a configuration, which may be nil
+func newLineage(cfg *LineageConfig) (*lineage.Graph, error) {
+	if cfg == nil || cfg.DBTManifest == "" {
+		return nil, nil
+	}
+	return lineage.LoadDBTManifest(cfg.DBTManifest)
+}
+
+// foreignKeys returns the foreign keys of the schema, listed at once when
+// the connector supports it
+func (s *MCPServerWithDB) foreignKeys(ctx context.Context) ([]connector.ForeignKey, error) {
+	if lister, ok := s.DBConn.(connector.ForeignKeyLister); ok {
+		return lister.ListForeignKeys(ctx)
+	}
+
+	tables, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var keys []connector.ForeignKey
+	for _, t := range tables {
+		metadata, err := s.DBConn.GetTableMetadata(ctx, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata for table %s: %w", t.Name, err)
+		}
+		for _, col := range metadata.Columns {
+			i := strings.LastIndexByte(col.References, '.')
+			if !col.ForeignKey || i < 0 {
+				continue
+			}
+			keys = append(keys, connector.ForeignKey{
+				Table:             t.Name,
+				Columns:           []string{col.Name},
+				ReferencedTable:   col.References[:i],
+				ReferencedColumns: []string{col.References[i+1:]},
+			})
+		}
+	}
+	return keys, nil
+}
+
+// relatedTables returns the tables related to a table of the catalog
+func (s *MCPServerWithDB) relatedTables(ctx context.Context, table string) (*RelatedTables, error) {
+	if err := s.checkTableName(ctx, table); err != nil {
+		return nil, err
+	}
+	keys, err := s.foreignKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	related := &RelatedTables{Table: table, Joins: joinsOf(connector.DialectOf(s.DBConn), table, keys)}
+
+	if s.lineage != nil {
+		var database, schema string
+		if d, ok := connector.DialectOf(s.DBConn).(connector.SnowflakeDialect); ok {
+			database, schema = d.Database, d.Schema
+		}
+		related.Upstream = s.lineage.Upstream(database, schema, table)
+		related.Downstream = s.lineage.Downstream(database, schema, table)
+	}
+	return related, nil
+}
+
+// joinsOf derives the joins of a table from the foreign keys of the schema
+func joinsOf(d connector.Dialect, table string, keys []connector.ForeignKey) []TableJoin {
+	condition := func(left string, leftColumns []string, right string, rightColumns []string) string {
+		parts := make([]string, len(leftColumns))
+		for i := range leftColumns {
+			parts[i] = fmt.Sprintf("%s.%s = %s.%s",
+				d.QuoteIdentifier(left), d.QuoteIdentifier(leftColumns[i]),
+				d.QuoteIdentifier(right), d.QuoteIdentifier(rightColumns[i]))
+		}
+		return strings.Join(parts, " AND ")
+	}
+
+	joins := []TableJoin{}
+	seen := make(map[string]bool)
+	add := func(join TableJoin) {
+		if key := join.Table + "|" + join.Condition; !seen[key] {
+			seen[key] = true
+			joins = append(joins, join)
+		}
+	}
+	for _, fk := range keys {
+		switch {
+		case fk.Table == table:
+			add(TableJoin{Table: fk.ReferencedTable, Relation: JoinReferences,
+				Condition: condition(table, fk.Columns, fk.ReferencedTable, fk.ReferencedColumns)})
+		case fk.ReferencedTable == table:
+			add(TableJoin{Table: fk.Table, Relation: JoinReferencedBy,
+				Condition: condition(fk.Table, fk.Columns, table, fk.ReferencedColumns)})
+		}
+	}
+
+	// Tables referencing the same key join on their referencing columns
+	for _, own := range keys {
+		if own.Table != table || own.ReferencedTable == table {
+			continue
+		}
+		for _, other := range keys {
+			if other.Table == table || other.ReferencedTable != own.ReferencedTable ||
+				strings.Join(other.ReferencedColumns, ",") != strings.Join(own.ReferencedColumns, ",") {
+				continue
+			}
+			add(TableJoin{Table: other.Table, Relation: JoinSharedReference, Via: own.ReferencedTable,
+				Condition: condition(table, own.Columns, other.Table, other.Columns)})
+		}
+	}
+
+	sort.SliceStable(joins, func(i, j int) bool {
+		if joins[i].Relation != joins[j].Relation {
+			return joinOrder[joins[i].Relation] < joinOrder[joins[j].Relation]
+		}
+		return joins[i].Table < joins[j].Table
+	})
+	return joins
+}
+
+// setupRelatedTableRoutes configures the related tables route
+func (s *MCPServerWithDB) setupRelatedTableRoutes(router *gin.RouterGroup) {
+	router.GET("/tables/:tableName/related", func(c *gin.Context) {
+		related, err := s.relatedTables(c.Request.Context(), c.Param("tableName"))
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to find related tables", err)
+			return
+		}
+		c.JSON(http.StatusOK, related)
+	})
+}
+
+// relatedTablesTool exposes the related tables as an MCP tool
+func (s *MCPServerWithDB) relatedTablesTool() mcpTool {
+	return mcpTool{
+		Schema: mcp.ToolSchema{
+			Name:        "related_tables",
+			Description: "Find the tables a table joins with, with suggested join conditions, and the tables it is built from or feeds",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]any{
+					"table": map[string]any{"type": "string", "description": "Name of the table"},
+				},
+				Required: []string{"table"},
+			},
+		},
+		Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			table, _ := args["table"].(string)
+			if table == "" {
+				return nil, fmt.Errorf("table is required")
+			}
+			related, err := s.relatedTables(ctx, table)
+			if err != nil {
+				return nil, err
+			}
+			return jsonToolResult(related)
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// keysConnector is a schema of orders referencing customers and products,
+// and of invoices referencing customers
+type keysConnector struct {
+	rowsConnector
+}
+
+func (c *keysConnector) ListTables(context.Context) ([]connector.Table, error) {
+	return []connector.Table{{Name: "CUSTOMERS"}, {Name: "INVOICES"}, {Name: "ORDERS"}, {Name: "PRODUCTS"}}, nil
+}
+
+func (c *keysConnector) GetTableMetadata(_ context.Context, table string) (*connector.TableMetadata, error) {
+	columns := map[string][]connector.Column{
+		"CUSTOMERS": {{Name: "ID", PrimaryKey: true}},
+		"PRODUCTS":  {{Name: "SKU", PrimaryKey: true}},
+		"ORDERS": {
+			{Name: "ID", PrimaryKey: true},
+			{Name: "CUSTOMER_ID", ForeignKey: true, References: "CUSTOMERS.ID"},
+			{Name: "SKU", ForeignKey: true, References: "PRODUCTS.SKU"},
+		},
+		"INVOICES": {
+			{Name: "ID", PrimaryKey: true},
+			{Name: "BILLED_TO", ForeignKey: true, References: "CUSTOMERS.ID"},
+		},
+	}
+	return &connector.TableMetadata{Name: table, Columns: columns[table]}, nil
+}
+
+func TestRelatedTables(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: &keysConnector{}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupRelatedTableRoutes(router.Group("/"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tables/ORDERS/related", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var related RelatedTables
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &related))
+	assert.Equal(t, []TableJoin{
+		{Table: "CUSTOMERS", Relation: JoinReferences, Condition: `"ORDERS"."CUSTOMER_ID" = "CUSTOMERS"."ID"`},
+		{Table: "PRODUCTS", Relation: JoinReferences, Condition: `"ORDERS"."SKU" = "PRODUCTS"."SKU"`},
+		{Table: "INVOICES", Relation: JoinSharedReference, Condition: `"ORDERS"."CUSTOMER_ID" = "INVOICES"."BILLED_TO"`, Via: "CUSTOMERS"},
+	}, related.Joins)
+
+	customers, err := s.relatedTables(context.Background(), "CUSTOMERS")
+	require.NoError(t, err)
+	assert.Equal(t, []TableJoin{
+		{Table: "INVOICES", Relation: JoinReferencedBy, Condition: `"INVOICES"."BILLED_TO" = "CUSTOMERS"."ID"`},
+		{Table: "ORDERS", Relation: JoinReferencedBy, Condition: `"ORDERS"."CUSTOMER_ID" = "CUSTOMERS"."ID"`},
+	}, customers.Joins)
+
+	// Composite keys join on every column
+	joins := joinsOf(connector.ANSIDialect{}, "LINES", []connector.ForeignKey{{
+		Table: "LINES", Columns: []string{"ORDER_ID", "LINE"}, ReferencedTable: "SHIPMENTS", ReferencedColumns: []string{"ORDER_ID", "LINE_NO"},
+	}})
+	assert.Equal(t, `"LINES"."ORDER_ID" = "SHIPMENTS"."ORDER_ID" AND "LINES"."LINE" = "SHIPMENTS"."LINE_NO"`, joins[0].Condition)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tables/RETURNS/related", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,322 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// Targets of replica routes
+const (
+	ReplicaTargetPrimary = "primary"
+	ReplicaTargetReplica = "replica"
+)
+
+// defaultReplicaCooldown is how long a failed replica is skipped
+const defaultReplicaCooldown = 30 * time.Second
+
+// ReplicaConfig routes the read queries of a server to read replicas of
+// its database, e.g. secondary databases in other regions. Writes, DDL and
+// statements that cannot be parsed always run on the primary Database.
+type ReplicaConfig struct {
+	// Databases are the replicas, connected like the primary and of its
+	// type. Reads are spread over them in turn.
+	Databases []ReplicaDatabase `json:"databases"`
+
+	// Routes keep some reads on the primary; the first route matching a
+	// read decides, and reads no route matches go to a replica
+	Routes []ReplicaRoute `json:"routes,omitempty"`
+
+	// Cooldown is how long a replica that failed is skipped, its reads
+	// failing over to the next replica and then to the primary (default:
+	// 30s)
+	Cooldown string `json:"cooldown,omitempty"`
+}
+
+// ReplicaDatabase is a named read replica
+type ReplicaDatabase struct {
+	Name     string                    `json:"name"`
+	Database *connector.DatabaseConfig `json:"database"`
+}
+
+// ReplicaRoute sends the reads of a source, or of some tables, to the
+// primary or a replica
+type ReplicaRoute struct {
+	// Source of the queries, e.g. rest, mcp or generated; empty matches
+	// every source
+	Source string `json:"source,omitempty"`
+
+	// Tables the query reads one of; empty matches every query
+	Tables []string `json:"tables,omitempty"`
+
+	// Target is primary or replica
+	Target string `json:"target"`
+}
+
+// matches reports whether a route applies to a read of a source
+func (r *ReplicaRoute) matches(source, query string) bool {
+	if r.Source != "" && r.Source != source {
+		return false
+	}
+	if len(r.Tables) == 0 {
+		return true
+	}
+	for _, table := range r.Tables {
+		if mentionsTable(query, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReplicaStatus is the health of a replica
+type ReplicaStatus struct {
+	Name      string     `json:"name"`
+	Healthy   bool       `json:"healthy"`
+	DownUntil *time.Time `json:"down_until,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+}
+
+// replica is the connection to a read replica and its health
+type replica struct {
+	name string
+	conn connector.DatabaseConnector
+
+	mu        sync.Mutex
+	connected bool
+	downUntil time.Time
+	lastError string
+}
+
+// ready connects a replica that is not connected yet, e.g. because it was
+// down when the server started
+func (r *replica) ready(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.connected {
+		return nil
+	}
+	if err := r.conn.Connect(ctx); err != nil {
+		return err
+	}
+	r.connected = true
+	return nil
+}
+
+// fail skips a replica for a cooldown
+func (r *replica) fail(err error, cooldown time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downUntil = time.Now().Add(cooldown)
+	r.lastError = err.Error()
+}
+
+// status returns the health of a replica
+func (r *replica) status(now time.Time) ReplicaStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := ReplicaStatus{Name: r.name, Healthy: !now.Before(r.downUntil), LastError: r.lastError}
+	if !status.Healthy {
+		downUntil := r.downUntil
+		status.DownUntil = &downUntil
+	}
+	return status
+}
+
+// replicaSet routes reads to the healthy replicas in turn
+type replicaSet struct {
+	syntax   connector.SQLSyntax
+	routes   []ReplicaRoute
+	cooldown time.Duration
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// newReplicaSet validates a replica configuration and creates the
+// connectors of its replicas like the primary's; nil runs every query on
+// the primary
+func newReplicaSet(cfg *ReplicaConfig, primary *connector.DatabaseConfig) (*replicaSet, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.Databases) == 0 {
+		return nil, fmt.Errorf("databases is required")
+	}
+	set := &replicaSet{syntax: connector.SyntaxFor(primary.Type), routes: cfg.Routes, cooldown: defaultReplicaCooldown}
+	if cfg.Cooldown != "" {
+		d, err := time.ParseDuration(cfg.Cooldown)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid cooldown: %s", cfg.Cooldown)
+		}
+		set.cooldown = d
+	}
+	for _, route := range cfg.Routes {
+		if route.Target != ReplicaTargetPrimary && route.Target != ReplicaTargetReplica {
+			return nil, fmt.Errorf("invalid route target: %q", route.Target)
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, db := range cfg.Databases {
+		switch {
+		case db.Name == "":
+			return nil, fmt.Errorf("replica name is required")
+		case names[db.Name]:
+			return nil, fmt.Errorf("duplicate replica %s", db.Name)
+		case db.Database == nil:
+			return nil, fmt.Errorf("replica %s: database is required", db.Name)
+		}
+		names[db.Name] = true
+
+		config := *db.Database
+		if config.Type == "" {
+			config.Type = primary.Type
+		}
+		if config.Type != primary.Type {
+			return nil, fmt.Errorf("replica %s: type %s differs from the primary's %s", db.Name, config.Type, primary.Type)
+		}
+		config.Provider, config.Prompts = primary.Provider, primary.Prompts
+		conn, err := connector.NewDatabaseConnector(&config)
+		if err != nil {
+			return nil, fmt.Errorf("replica %s: %w", db.Name, err)
+		}
+		set.replicas = append(set.replicas, &replica{name: db.Name, conn: conn})
+	}
+	return set, nil
+}
+
+// readsReplica reports whether a query of a source is routed to a replica:
+// it only reads, and no route keeps it on the primary
+func (rs *replicaSet) readsReplica(source, query string) bool {
+	statements, err := connector.ParseStatements(rs.syntax, query)
+	if err != nil || len(statements) == 0 {
+		return false
+	}
+	for _, st := range statements {
+		if st.Kind != connector.StatementRead {
+			return false
+		}
+	}
+	for _, route := range rs.routes {
+		if route.matches(source, query) {
+			return route.Target == ReplicaTargetReplica
+		}
+	}
+	return true
+}
+
+// candidates returns the healthy replicas, starting with the next one in
+// turn
+func (rs *replicaSet) candidates() []*replica {
+	now := time.Now()
+	start := int(rs.next.Add(1) % uint64(len(rs.replicas)))
+	var healthy []*replica
+	for i := range rs.replicas {
+		r := rs.replicas[(start+i)%len(rs.replicas)]
+		if r.status(now).Healthy {
+			healthy = append(healthy, r)
+		}
+	}
+	return healthy
+}
+
+// connect connects the replicas; a replica that cannot be reached is
+// skipped for a cooldown and connected again once it is tried
+func (rs *replicaSet) connect(ctx context.Context) {
+	for _, r := range rs.replicas {
+		if err := r.ready(ctx); err != nil {
+			r.fail(err, rs.cooldown)
+			log.Printf("Warning: Failed to connect to read replica %s: %v", r.name, err)
+		}
+	}
+}
+
+// disconnect closes the connections of the replicas
+func (rs *replicaSet) disconnect(ctx context.Context) {
+	for _, r := range rs.replicas {
+		r.mu.Lock()
+		if r.connected {
+			if err := r.conn.Disconnect(ctx); err != nil {
+				log.Printf("Error disconnecting from read replica %s: %v", r.name, err)
+			}
+			r.connected = false
+		}
+		r.mu.Unlock()
+	}
+}
+
+// replicaUnavailable reports whether a query failed because of its
+// replica rather than the query, so that it fails over
+func replicaUnavailable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	switch connector.ErrorCode(err) {
+	case connector.CodeUnavailable, connector.CodeAuthFailed:
+		return true
+	}
+	return connector.IsTransient(err)
+}
+
+type primaryKey struct{}
+
+// withPrimary returns a context whose queries run on the primary, e.g. to
+// read back a row just written before it reaches the replicas
+func withPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// onConnection runs a query on the connection it is routed to: the
+// connection the request selected, a replica for reads, failing over to the
+// next replica and then to the primary when a replica is unavailable, and
+// the primary for everything else
+func (s *MCPServerWithDB) onConnection(ctx context.Context, source, query string, run func(conn connector.DatabaseConnector) error) error {
+	if connectionLabel(ctx) != "" {
+		conn, err := s.selectedConn(ctx)
+		if err != nil {
+			return err
+		}
+		return run(conn)
+	}
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary || s.replicas == nil || !s.replicas.readsReplica(source, query) {
+		return run(s.DBConn)
+	}
+	for _, r := range s.replicas.candidates() {
+		err := r.ready(ctx)
+		if err == nil {
+			err = run(r.conn)
+			if !replicaUnavailable(ctx, err) {
+				return err
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		r.fail(err, s.replicas.cooldown)
+		log.Printf("Warning: Read replica %s failed, failing over: %v", r.name, err)
+	}
+	return run(s.DBConn)
+}
+
+// setupReplicaRoutes configures the route reporting the health of the read
+// replicas
+func (s *MCPServerWithDB) setupReplicaRoutes(router *gin.RouterGroup) {
+	if s.replicas == nil {
+		return
+	}
+	router.GET("/admin/replicas", func(c *gin.Context) {
+		now := time.Now()
+		statuses := make([]ReplicaStatus, 0, len(s.replicas.replicas))
+		for _, r := range s.replicas.replicas {
+			statuses = append(statuses, r.status(now))
+		}
+		c.JSON(http.StatusOK, statuses)
+	})
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// downConnector is a replica whose connection dropped
+type downConnector struct {
+	rowsConnector
+	calls int
+}
+
+func (c *downConnector) ExecuteQuery(context.Context, string, map[string]interface{}) ([]map[string]interface{}, error) {
+	c.calls++
+	return nil, errors.New("read tcp: connection reset by peer")
+}
+
+func TestReplicaRouting(t *testing.T) {
+	primary, east, west := &paramsConnector{}, &paramsConnector{}, &downConnector{}
+	replicas := &replicaSet{
+		syntax:   connector.SyntaxFor("snowflake"),
+		cooldown: time.Minute,
+		routes: []ReplicaRoute{
+			{Source: "mcp", Target: ReplicaTargetPrimary},
+			{Tables: []string{"ORDERS"}, Target: ReplicaTargetPrimary},
+		},
+		replicas: []*replica{
+			{name: "us-west", conn: west, connected: true},
+			{name: "us-east", conn: east, connected: true},
+		},
+	}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: primary, replicas: replicas}
+	ran := func(ctx context.Context, source, query string) *paramsConnector {
+		primary.query, east.query = "", ""
+		_, err := s.executeQuery(ctx, source, query, nil)
+		require.NoError(t, err)
+		if east.query == query {
+			return east
+		}
+		require.Equal(t, query, primary.query)
+		return primary
+	}
+	ctx := context.Background()
+
+	// Reads fail over from the dropped replica, which is then skipped
+	assert.Same(t, east, ran(ctx, "rest", "SELECT * FROM EVENTS"))
+	assert.Same(t, east, ran(ctx, "rest", "SELECT * FROM EVENTS"))
+	assert.Equal(t, 1, west.calls)
+	status := replicas.replicas[0].status(time.Now())
+	assert.False(t, status.Healthy)
+	assert.Contains(t, status.LastError, "connection reset")
+
+	// Writes, routed reads and reads of fresh writes run on the primary
+	assert.Same(t, primary, ran(ctx, "rest", "DELETE FROM EVENTS WHERE ID = 1"))
+	assert.Same(t, primary, ran(ctx, "mcp", "SELECT * FROM EVENTS"))
+	assert.Same(t, primary, ran(ctx, "rest", `SELECT * FROM "ORDERS"`))
+	assert.Same(t, primary, ran(withPrimary(ctx), "generated", "SELECT * FROM EVENTS"))
+
+	// Without a healthy replica, reads run on the primary
+	replicas.replicas[1].fail(errors.New("down"), time.Minute)
+	assert.Same(t, primary, ran(ctx, "rest", "SELECT * FROM EVENTS"))
+}
+
+func TestReplicaConfig(t *testing.T) {
+	primary := &connector.DatabaseConfig{Type: "snowflake"}
+	replica := func(name, dbType string) ReplicaDatabase {
+		return ReplicaDatabase{Name: name, Database: &connector.DatabaseConfig{Type: dbType}}
+	}
+	for name, cfg := range map[string]*ReplicaConfig{
+		"no replicas":    {},
+		"unnamed":        {Databases: []ReplicaDatabase{replica("", "")}},
+		"other type":     {Databases: []ReplicaDatabase{replica("eu", "mysql")}},
+		"invalid target": {Databases: []ReplicaDatabase{replica("eu", "")}, Routes: []ReplicaRoute{{Target: "nearest"}}},
+		"invalid cool":   {Databases: []ReplicaDatabase{replica("eu", "")}, Cooldown: "-1s"},
+	} {
+		_, err := newReplicaSet(cfg, primary)
+		assert.Error(t, err, name)
+	}
+}
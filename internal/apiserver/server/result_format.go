@@ -0,0 +1,262 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	// ResultFormatJSON returns rows as a JSON array in a text block
+	ResultFormatJSON = "json"
+	// ResultFormatMarkdown returns rows as a markdown table in a text block
+	ResultFormatMarkdown = "markdown"
+	// ResultFormatStructured returns rows as structured content, linking to
+	// the full result when it is too large to inline
+	ResultFormatStructured = "structured"
+
+	defaultMaxInlineRows  = 100
+	defaultMaxResultBytes = 256 * 1024
+	defaultPreviewRows    = 20
+)
+
+// ToolResultConfig controls how tool results are serialized
+type ToolResultConfig struct {
+	// Format is the default format (json, markdown or structured)
+	Format string `json:"format,omitempty"`
+
+	// Tools overrides the format per tool name
+	Tools map[string]string `json:"tools,omitempty"`
+
+	// Clients overrides the format per MCP client name, as sent in initialize
+	Clients map[string]string `json:"clients,omitempty"`
+
+	// MaxInlineRows is the number of rows inlined in structured results
+	MaxInlineRows int `json:"max_inline_rows,omitempty"`
+
+	// MaxResultBytes is the size above which a result is stored as a resource
+	// and replaced by a preview. A negative value disables splitting.
+	MaxResultBytes int `json:"max_result_bytes,omitempty"`
+
+	// PreviewRows is the maximum number of rows in the preview of a split result
+	PreviewRows int `json:"preview_rows,omitempty"`
+
+	// ResultTTL is how long split results stay readable, e.g. "1h"
+	ResultTTL string `json:"result_ttl,omitempty"`
+}
+
+// resultFormat resolves the format for a tool call; tool overrides win over client profiles
+func (s *MCPServerWithDB) resultFormat(tool string, sess *mcpSession) string {
+	cfg := s.Config.ToolResults
+	if cfg == nil {
+		return ResultFormatJSON
+	}
+	if f, ok := cfg.Tools[tool]; ok {
+		return f
+	}
+	if sess != nil {
+		if f, ok := cfg.Clients[sess.ClientInfo.Name]; ok {
+			return f
+		}
+	}
+	if cfg.Format != "" {
+		return cfg.Format
+	}
+	return ResultFormatJSON
+}
+
+// rowsToolResult serializes rows in the format configured for the tool and
+// session. Results larger than MaxResultBytes are stored as a resource and
+// replaced by a preview linking to it.
+func (s *MCPServerWithDB) rowsToolResult(tool string, sess *mcpSession, rows *connector.ResultSet) (*mcp.CallToolResult, error) {
+	return s.formatToolRows(s.resultFormat(tool, sess), tool, rows)
+}
+
+// formatToolRows serializes rows in the given format, splitting oversized results
+func (s *MCPServerWithDB) formatToolRows(format, tool string, rows *connector.ResultSet) (*mcp.CallToolResult, error) {
+	// Structured results never inline more than MaxInlineRows
+	if format == ResultFormatStructured && len(rows.Rows) > s.maxInlineRows() {
+		stored := s.results.put(tool, rows)
+		return s.shrinkToLimit(format, rows, stored, s.maxInlineRows())
+	}
+
+	result, err := formatRows(format, rows, len(rows.Rows), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.maxResultBytes()
+	if limit <= 0 || resultSize(result) <= limit {
+		return result, nil
+	}
+
+	stored := s.results.put(tool, rows)
+	return s.shrinkToLimit(format, rows, stored, s.previewRows())
+}
+
+// shrinkToLimit formats a preview of at most maxRows rows linked to the stored
+// result, halving the preview until it fits in MaxResultBytes
+func (s *MCPServerWithDB) shrinkToLimit(format string, rows *connector.ResultSet, stored *storedResult, maxRows int) (*mcp.CallToolResult, error) {
+	n := maxRows
+	if n > len(rows.Rows) {
+		n = len(rows.Rows)
+	}
+
+	limit := s.maxResultBytes()
+	for {
+		preview := &connector.ResultSet{Columns: rows.Columns, Rows: rows.Rows[:n]}
+		result, err := formatRows(format, preview, len(rows.Rows), stored)
+		if err != nil {
+			return nil, err
+		}
+		if limit <= 0 || n == 0 || resultSize(result) <= limit {
+			return result, nil
+		}
+		n /= 2
+	}
+}
+
+// formatRows renders rows in the given format. When stored is set, rows is
+// a preview of a larger result of total rows that is linked as a resource.
+func formatRows(format string, rows *connector.ResultSet, total int, stored *storedResult) (*mcp.CallToolResult, error) {
+	var result *mcp.CallToolResult
+	switch format {
+	case ResultFormatJSON:
+		r, err := jsonToolResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = r
+	case ResultFormatMarkdown:
+		result = mcp.NewCallToolResultText(markdownTable(rows))
+	case ResultFormatStructured:
+		structured := map[string]interface{}{
+			"rows":      rows,
+			"row_count": total,
+			"truncated": stored != nil,
+		}
+		if stored != nil {
+			structured["resource_uri"] = stored.URI()
+		}
+		// Clients without structured content support still get the data as text
+		text, err := json.Marshal(structured)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result: %w", err)
+		}
+		result = &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Type: mcp.TextContentType, Text: string(text)},
+			},
+			StructuredContent: structured,
+		}
+	default:
+		return nil, fmt.Errorf("unsupported result format: %s", format)
+	}
+
+	if stored != nil {
+		result.Content = append(result.Content,
+			&mcp.TextContent{
+				Type: mcp.TextContentType,
+				Text: fmt.Sprintf("Showing %d of %d rows. Read resource %s for the full result.", len(rows.Rows), total, stored.URI()),
+			},
+			&mcp.ResourceLinkContent{
+				Type:        mcp.ResourceLinkContentType,
+				URI:         stored.URI(),
+				Name:        fmt.Sprintf("%s result", stored.Tool),
+				Description: fmt.Sprintf("Full result of %d rows", total),
+				MimeType:    "application/json",
+			})
+	}
+	return result, nil
+}
+
+// resultSize approximates the size of a tool result as seen by the client
+func resultSize(result *mcp.CallToolResult) int {
+	size := 0
+	for _, c := range result.Content {
+		if t, ok := c.(*mcp.TextContent); ok {
+			size += len(t.Text)
+		}
+	}
+	return size
+}
+
+func (s *MCPServerWithDB) maxInlineRows() int {
+	if s.Config.ToolResults != nil && s.Config.ToolResults.MaxInlineRows > 0 {
+		return s.Config.ToolResults.MaxInlineRows
+	}
+	return defaultMaxInlineRows
+}
+
+func (s *MCPServerWithDB) maxResultBytes() int {
+	if s.Config.ToolResults != nil && s.Config.ToolResults.MaxResultBytes != 0 {
+		return s.Config.ToolResults.MaxResultBytes
+	}
+	return defaultMaxResultBytes
+}
+
+func (s *MCPServerWithDB) previewRows() int {
+	if s.Config.ToolResults != nil && s.Config.ToolResults.PreviewRows > 0 {
+		return s.Config.ToolResults.PreviewRows
+	}
+	return defaultPreviewRows
+}
+
+// markdownTable renders rows as a markdown table with columns in result order
+func markdownTable(rows *connector.ResultSet) string {
+	if len(rows.Rows) == 0 {
+		return "_No rows_"
+	}
+	columns := rows.ColumnOrder()
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(escapeMarkdownCells(columns), " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, row := range rows.Rows {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			if v := row[col]; v != nil {
+				cells[i] = fmt.Sprintf("%v", v)
+			}
+		}
+		b.WriteString("| " + strings.Join(escapeMarkdownCells(cells), " | ") + " |\n")
+	}
+	return b.String()
+}
+
+func escapeMarkdownCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		c = strings.ReplaceAll(c, "|", "\\|")
+		escaped[i] = strings.ReplaceAll(c, "\n", " ")
+	}
+	return escaped
+}
+
+// validate checks that every configured format is supported
+func (c *ToolResultConfig) validate() error {
+	formats := []string{c.Format}
+	for _, f := range c.Tools {
+		formats = append(formats, f)
+	}
+	for _, f := range c.Clients {
+		formats = append(formats, f)
+	}
+	for _, f := range formats {
+		switch f {
+		case "", ResultFormatJSON, ResultFormatMarkdown, ResultFormatStructured:
+		default:
+			return fmt.Errorf("unsupported result format: %s", f)
+		}
+	}
+	if c.ResultTTL != "" {
+		if _, err := time.ParseDuration(c.ResultTTL); err != nil {
+			return fmt.Errorf("invalid result_ttl: %w", err)
+		}
+	}
+	return nil
+}
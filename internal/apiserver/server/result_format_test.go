@@ -0,0 +1,158 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRows(n int) *connector.ResultSet {
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		rows[i] = map[string]interface{}{"id": i, "name": fmt.Sprintf("name-%d", i)}
+	}
+	return &connector.ResultSet{Rows: rows}
+}
+
+func TestRowsToolResult_Formats(t *testing.T) {
+	s, err := NewMCPServerWithDB(&MCPServerConfig{
+		Name: "test",
+		ToolResults: &ToolResultConfig{
+			Format:  ResultFormatJSON,
+			Tools:   map[string]string{"query": ResultFormatMarkdown},
+			Clients: map[string]string{"inspector": ResultFormatStructured},
+		},
+	})
+	require.NoError(t, err)
+
+	sess := &mcpSession{ClientInfo: mcp.ImplementationSchema{Name: "inspector"}}
+
+	result, err := s.rowsToolResult("query", sess, testRows(2))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.Content[0].(*mcp.TextContent).Text, "| id | name |"))
+
+	// Columns keep the order of the query when it is known
+	ordered := testRows(2)
+	ordered.Columns = []string{"name", "id"}
+	result, err = s.rowsToolResult("query", sess, ordered)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(result.Content[0].(*mcp.TextContent).Text, "| name | id |"))
+
+	result, err = s.rowsToolResult("list_tables", sess, testRows(2))
+	require.NoError(t, err)
+	assert.NotNil(t, result.StructuredContent)
+
+	result, err = s.rowsToolResult("list_tables", nil, testRows(2))
+	require.NoError(t, err)
+	assert.Nil(t, result.StructuredContent)
+}
+
+func TestRowsToolResult_SplitsOversizedResults(t *testing.T) {
+	s, err := NewMCPServerWithDB(&MCPServerConfig{
+		Name:        "test",
+		ToolResults: &ToolResultConfig{MaxResultBytes: 2048, PreviewRows: 10},
+	})
+	require.NoError(t, err)
+
+	rows := testRows(1000)
+	result, err := s.rowsToolResult("query", nil, rows)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, resultSize(result), 2048)
+	link, ok := result.Content[len(result.Content)-1].(*mcp.ResourceLinkContent)
+	require.True(t, ok)
+
+	stored, ok := s.results.get(link.URI)
+	require.True(t, ok)
+	assert.Len(t, stored.Rows, 1000)
+
+	small, err := s.rowsToolResult("query", nil, testRows(3))
+	require.NoError(t, err)
+	assert.Len(t, small.Content, 1)
+}
+
+func TestFormatRows_RoundTrip(t *testing.T) {
+	rows := &connector.ResultSet{
+		Columns: []string{"name", "id"},
+		Rows: []map[string]interface{}{
+			{"id": 1.0, "name": "a|b"},
+			{"id": 2.0, "name": nil},
+		},
+	}
+
+	t.Run(ResultFormatJSON, func(t *testing.T) {
+		result, err := formatRows(ResultFormatJSON, rows, 2, nil)
+		require.NoError(t, err)
+		text := result.Content[0].(*mcp.TextContent).Text
+		assert.True(t, strings.HasPrefix(text, `[{"name":"a|b","id":1}`), text)
+		var decoded []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(text), &decoded))
+		assert.Equal(t, rows.Rows, decoded)
+	})
+
+	t.Run(ResultFormatMarkdown, func(t *testing.T) {
+		result, err := formatRows(ResultFormatMarkdown, rows, 2, nil)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSuffix(result.Content[0].(*mcp.TextContent).Text, "\n"), "\n")
+		assert.Equal(t, []string{
+			"| name | id |",
+			"| --- | --- |",
+			`| a\|b | 1 |`,
+			"|  | 2 |",
+		}, lines)
+	})
+
+	t.Run(ResultFormatStructured, func(t *testing.T) {
+		result, err := formatRows(ResultFormatStructured, rows, 2, nil)
+		require.NoError(t, err)
+		var decoded struct {
+			Rows      []map[string]interface{} `json:"rows"`
+			RowCount  int                      `json:"row_count"`
+			Truncated bool                     `json:"truncated"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &decoded))
+		assert.Equal(t, rows.Rows, decoded.Rows)
+		assert.Equal(t, 2, decoded.RowCount)
+		assert.False(t, decoded.Truncated)
+		assert.Equal(t, rows, result.StructuredContent.(map[string]interface{})["rows"])
+	})
+
+	_, err := formatRows("yaml", rows, 2, nil)
+	assert.EqualError(t, err, "unsupported result format: yaml")
+}
+
+func TestResultFormat_Negotiation(t *testing.T) {
+	inspector := &mcpSession{ClientInfo: mcp.ImplementationSchema{Name: "inspector"}}
+	desktop := &mcpSession{ClientInfo: mcp.ImplementationSchema{Name: "claude-desktop"}}
+	cfg := &ToolResultConfig{
+		Format:  ResultFormatMarkdown,
+		Tools:   map[string]string{"query": ResultFormatJSON},
+		Clients: map[string]string{"inspector": ResultFormatStructured},
+	}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "test", ToolResults: cfg}}
+
+	// Tool overrides win over client profiles, which win over the default
+	assert.Equal(t, ResultFormatJSON, s.resultFormat("query", inspector))
+	assert.Equal(t, ResultFormatStructured, s.resultFormat("list_tables", inspector))
+	assert.Equal(t, ResultFormatMarkdown, s.resultFormat("list_tables", desktop))
+	assert.Equal(t, ResultFormatMarkdown, s.resultFormat("list_tables", nil))
+	assert.Equal(t, ResultFormatJSON, (&MCPServerWithDB{Config: &MCPServerConfig{}}).resultFormat("query", inspector))
+
+	// Unknown formats are rejected with the configuration
+	assert.NoError(t, cfg.validate())
+	for _, bad := range []*ToolResultConfig{
+		{Format: "yaml"},
+		{Tools: map[string]string{"query": "csv"}},
+		{Clients: map[string]string{"inspector": "html"}},
+	} {
+		assert.ErrorContains(t, bad.validate(), "unsupported result format")
+	}
+	_, err := NewMCPServerWithDB(&MCPServerConfig{Name: "test", ToolResults: &ToolResultConfig{Format: "yaml"}})
+	assert.Error(t, err)
+}
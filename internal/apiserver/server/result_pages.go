@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// maxResultPageSize is the most rows of a page of a kept result
+const maxResultPageSize = 10000
+
+// Page token errors
+var (
+	ErrPageTokenNotFound  = errors.New("page token not found")
+	ErrPageTokenExpired   = errors.New("page token expired")
+	ErrPagingUnsupported  = errors.New("database cannot page through kept results")
+	ErrInvalidPageRequest = errors.New("invalid page request")
+)
+
+// QueryResultPage is a page of the rows of a query, with the token of the
+// next page when more rows follow
+type QueryResultPage struct {
+	Columns       []string                 `json:"columns,omitempty"`
+	Rows          []map[string]interface{} `json:"rows"`
+	NextPageToken string                   `json:"next_page_token,omitempty"`
+	ExpiresAt     *time.Time               `json:"expires_at,omitempty"`
+}
+
+// pageCursor is where a page token resumes reading a kept result
+type pageCursor struct {
+	resultID   string
+	connection string
+	offset     int
+	limit      int
+	principal  string
+	expires    time.Time
+}
+
+// pageTokens keeps the cursors of page tokens until the results they read
+// are no longer kept by the database. A token can be read again, so that a
+// client resumes after a failed request, and gets the same next token.
+type pageTokens struct {
+	mu      sync.Mutex
+	cursors map[string]*pageCursor
+	tokens  map[pageCursor]string
+}
+
+func newPageTokens() *pageTokens {
+	return &pageTokens{cursors: make(map[string]*pageCursor), tokens: make(map[pageCursor]string)}
+}
+
+// issue returns the token of a cursor, forgetting expired ones
+func (p *pageTokens) issue(cursor pageCursor) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for token, c := range p.cursors {
+		if now.After(c.expires) {
+			delete(p.cursors, token)
+			delete(p.tokens, *c)
+		}
+	}
+	if token, ok := p.tokens[cursor]; ok {
+		return token
+	}
+	token := uuid.New().String()
+	p.cursors[token] = &cursor
+	p.tokens[cursor] = token
+	return token
+}
+
+// get returns the cursor of a caller's token
+func (p *pageTokens) get(token, principal string) (pageCursor, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cursor, ok := p.cursors[token]
+	if !ok || cursor.principal != principal {
+		return pageCursor{}, ErrPageTokenNotFound
+	}
+	if time.Now().After(cursor.expires) {
+		delete(p.cursors, token)
+		delete(p.tokens, *cursor)
+		return pageCursor{}, ErrPageTokenExpired
+	}
+	return *cursor, nil
+}
+
+// resultPage builds the response of a page, issuing the token of the next
+// page when more rows follow
+func (s *MCPServerWithDB) resultPage(page *connector.ResultPage, cursor pageCursor) *QueryResultPage {
+	result := &QueryResultPage{Columns: page.Columns, Rows: page.Rows}
+	if result.Rows == nil {
+		result.Rows = []map[string]interface{}{}
+	}
+	if page.More {
+		cursor.offset += len(page.Rows)
+		result.NextPageToken = s.pages.issue(cursor)
+		result.ExpiresAt = &cursor.expires
+	}
+	return result
+}
+
+// executeFirstPage runs a query like executeOrdered, reading only its first
+// page and keeping a cursor on the rest of its result
+func (s *MCPServerWithDB) executeFirstPage(ctx context.Context, source, query string, params map[string]interface{}, limit int, principal string) (*QueryResultPage, error) {
+	conn, err := s.selectedConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pager, ok := conn.(connector.ResultPager)
+	if !ok {
+		return nil, ErrPagingUnsupported
+	}
+	if err := s.limits.checkParams(params); err != nil {
+		return nil, err
+	}
+	subject, err := s.admitQuery(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	ctx, done, err := s.queries.start(ctx, source, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// The result is kept from when the query runs
+	started := time.Now()
+	expires := started.Add(pager.ResultRetention())
+	var page *connector.ResultPage
+	err = s.retries.do(ctx, query, func() (err error) {
+		page, err = pager.ExecuteQueryPage(ctx, query, params, limit)
+		return err
+	})
+	rows := 0
+	if page != nil {
+		rows = len(page.Rows)
+	}
+	s.quotas.addRows(subject, rows)
+	s.recordHistory(ctx, source, query, started, rows, err)
+	s.reportQueryError(source, query, err)
+	if err != nil {
+		return nil, err
+	}
+	return s.resultPage(page, pageCursor{
+		resultID:   page.ResultID,
+		connection: connectionLabel(ctx),
+		limit:      limit,
+		principal:  principal,
+		expires:    expires,
+	}), nil
+}
+
+// fetchNextPage reads the page of a token from the kept result, on the
+// connection that ran the query, without running the query again
+func (s *MCPServerWithDB) fetchNextPage(ctx context.Context, token, principal string) (*QueryResultPage, error) {
+	cursor, err := s.pages.get(token, principal)
+	if err != nil {
+		return nil, err
+	}
+	if ctx, err = s.restoreConnection(ctx, cursor.connection); err != nil {
+		return nil, err
+	}
+	conn, err := s.selectedConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pager, ok := conn.(connector.ResultPager)
+	if !ok {
+		return nil, ErrPagingUnsupported
+	}
+	page, err := pager.FetchResultPage(ctx, cursor.resultID, cursor.offset, cursor.limit)
+	if err != nil {
+		return nil, err
+	}
+	if s.quotas != nil {
+		subject, _ := s.quotas.quotaOf(ctx)
+		s.quotas.addRows(subject, len(page.Rows))
+	}
+	return s.resultPage(page, cursor), nil
+}
+
+// checkPageSize validates the page size of a paged query
+func checkPageSize(size int) error {
+	if size < 0 || size > maxResultPageSize {
+		return fmt.Errorf("%w: page_size must be between 1 and %d", ErrInvalidPageRequest, maxResultPageSize)
+	}
+	return nil
+}
+
+// setupResultPageRoutes configures the route reading the next page of a
+// paged query with its token
+func (s *MCPServerWithDB) setupResultPageRoutes(router *gin.RouterGroup) {
+	router.GET("/query/pages/:token", func(c *gin.Context) {
+		page, err := s.fetchNextPage(c.Request.Context(), c.Param("token"), principalFromContext(c))
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to fetch result page", err)
+			return
+		}
+		c.JSON(http.StatusOK, page)
+	})
+}
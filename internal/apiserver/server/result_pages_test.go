@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// pagerConnector keeps the rows of the queries it runs like Snowflake's
+// persisted results
+type pagerConnector struct {
+	paramsConnector
+	retention time.Duration
+	executed  int
+	fetched   []int
+}
+
+func (c *pagerConnector) page(offset, limit int) *connector.ResultPage {
+	end := offset + limit
+	if end > len(c.rows) {
+		end = len(c.rows)
+	}
+	return &connector.ResultPage{
+		ResultSet: &connector.ResultSet{Columns: []string{"ID"}, Rows: c.rows[offset:end]},
+		ResultID:  "01b2-result",
+		More:      end < len(c.rows),
+	}
+}
+
+func (c *pagerConnector) ExecuteQueryPage(_ context.Context, query string, _ map[string]interface{}, limit int) (*connector.ResultPage, error) {
+	c.query = query
+	c.executed++
+	return c.page(0, limit), nil
+}
+
+func (c *pagerConnector) FetchResultPage(_ context.Context, resultID string, offset, limit int) (*connector.ResultPage, error) {
+	c.fetched = append(c.fetched, offset)
+	return c.page(offset, limit), nil
+}
+
+func (c *pagerConnector) ResultRetention() time.Duration {
+	return c.retention
+}
+
+func TestResultPages(t *testing.T) {
+	conn := &pagerConnector{retention: time.Hour}
+	for i := 1; i <= 5; i++ {
+		conn.rows = append(conn.rows, map[string]interface{}{"ID": i})
+	}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, pages: newPageTokens()}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+	next := func(token string) QueryResultPage {
+		w := request(http.MethodGet, "/query/pages/"+token, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var page QueryResultPage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+		return page
+	}
+
+	w := request(http.MethodPost, "/query", `{"query": "SELECT * FROM ORDERS", "page_size": 2}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var first QueryResultPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.Len(t, first.Rows, 2)
+	assert.Equal(t, []string{"ID"}, first.Columns)
+	require.NotEmpty(t, first.NextPageToken)
+	require.NotNil(t, first.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *first.ExpiresAt, time.Minute)
+
+	// Later pages are read from the kept result, and a token can be read
+	// again to resume
+	second := next(first.NextPageToken)
+	assert.EqualValues(t, 3, second.Rows[0]["ID"])
+	assert.Equal(t, second, next(first.NextPageToken))
+	last := next(second.NextPageToken)
+	assert.Len(t, last.Rows, 1)
+	assert.Empty(t, last.NextPageToken)
+	assert.Equal(t, 1, conn.executed)
+	assert.Equal(t, []int{2, 2, 4}, conn.fetched)
+
+	// Tokens belong to their caller and expire with the result
+	r := httptest.NewRequest(http.MethodGet, "/query/pages/"+first.NextPageToken, nil)
+	r.RemoteAddr = "198.51.100.7:1234"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	conn.retention = -time.Second
+	w = request(http.MethodPost, "/query", `{"query": "SELECT * FROM ORDERS", "page_size": 2}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var expired QueryResultPage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &expired))
+	assert.Equal(t, http.StatusGone, request(http.MethodGet, "/query/pages/"+expired.NextPageToken, "").Code)
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/query", `{"query": "SELECT 1", "page_size": 100000}`).Code)
+	s.DBConn = &paramsConnector{}
+	assert.Equal(t, http.StatusNotImplemented, request(http.MethodPost, "/query", `{"query": "SELECT 1", "page_size": 2}`).Code)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	resultURIPrefix      = "db://results/"
+	defaultResultTTL     = time.Hour
+	defaultMaxResultSets = 100
+)
+
+// storedResult is a full tool result kept for retrieval as an MCP resource
+type storedResult struct {
+	ID        string
+	Tool      string
+	Columns   []string
+	Rows      []map[string]interface{}
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// URI returns the MCP resource URI of the result
+func (r *storedResult) URI() string {
+	return resultURIPrefix + r.ID
+}
+
+// resultStore keeps full result sets in memory for a limited time
+type resultStore struct {
+	mu      sync.Mutex
+	results map[string]*storedResult
+	order   []string
+	ttl     time.Duration
+	max     int
+}
+
+func newResultStore(ttl time.Duration, max int) *resultStore {
+	if ttl <= 0 {
+		ttl = defaultResultTTL
+	}
+	if max <= 0 {
+		max = defaultMaxResultSets
+	}
+	return &resultStore{
+		results: make(map[string]*storedResult),
+		ttl:     ttl,
+		max:     max,
+	}
+}
+
+// put stores a result set and evicts the oldest ones beyond capacity
+func (st *resultStore) put(tool string, rows *connector.ResultSet) *storedResult {
+	now := time.Now()
+	result := &storedResult{
+		ID:        uuid.New().String(),
+		Tool:      tool,
+		Columns:   rows.Columns,
+		Rows:      rows.Rows,
+		CreatedAt: now,
+		ExpiresAt: now.Add(st.ttl),
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.evictExpiredLocked(now)
+	st.results[result.ID] = result
+	st.order = append(st.order, result.ID)
+	for len(st.order) > st.max {
+		delete(st.results, st.order[0])
+		st.order = st.order[1:]
+	}
+	return result
+}
+
+// get looks up a result set by its resource URI
+func (st *resultStore) get(uri string) (*storedResult, bool) {
+	if !strings.HasPrefix(uri, resultURIPrefix) {
+		return nil, false
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.evictExpiredLocked(time.Now())
+	result, ok := st.results[strings.TrimPrefix(uri, resultURIPrefix)]
+	return result, ok
+}
+
+// list returns the stored result sets, oldest first
+func (st *resultStore) list() []*storedResult {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.evictExpiredLocked(time.Now())
+	results := make([]*storedResult, 0, len(st.order))
+	for _, id := range st.order {
+		results = append(results, st.results[id])
+	}
+	return results
+}
+
+func (st *resultStore) evictExpiredLocked(now time.Time) {
+	kept := st.order[:0]
+	for _, id := range st.order {
+		if now.After(st.results[id].ExpiresAt) {
+			delete(st.results, id)
+			continue
+		}
+		kept = append(kept, id)
+	}
+	st.order = kept
+}
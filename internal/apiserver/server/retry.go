@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultRetryAttempts       = 3
+	defaultRetryInitialBackoff = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+
+	// idempotencyKeyHeader marks a REST request's writes as safe to retry
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// queryRetriesHeader reports the retries of a REST request's queries
+	queryRetriesHeader = "X-Query-Retries"
+)
+
+// RetryConfig retries queries that fail on transient errors. Read-only
+// queries are retried; writes only when the caller supplies an
+// idempotency key.
+type RetryConfig struct {
+	// MaxAttempts is the number of times a query is run (default: 3)
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// InitialBackoff is the wait before the first retry, doubled for each
+	// further one (default: 200ms)
+	InitialBackoff string `json:"initial_backoff,omitempty"`
+
+	// MaxBackoff caps the wait between retries (default: 5s)
+	MaxBackoff string `json:"max_backoff,omitempty"`
+}
+
+// retryPolicy runs queries with the retries of a RetryConfig
+type retryPolicy struct {
+	attempts int
+	initial  time.Duration
+	max      time.Duration
+}
+
+// newRetryPolicy creates the policy of a configuration; nil disables retries
+// and returns a nil policy
+func newRetryPolicy(cfg *RetryConfig) (*retryPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	p := &retryPolicy{attempts: cfg.MaxAttempts, initial: defaultRetryInitialBackoff, max: defaultRetryMaxBackoff}
+	switch {
+	case cfg.MaxAttempts < 0:
+		return nil, fmt.Errorf("max_attempts must not be negative")
+	case cfg.MaxAttempts == 0:
+		p.attempts = defaultRetryAttempts
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"initial_backoff", cfg.InitialBackoff, &p.initial},
+		{"max_backoff", cfg.MaxBackoff, &p.max},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil || v <= 0 {
+			return nil, fmt.Errorf("invalid %s: %s", d.name, d.value)
+		}
+		*d.dst = v
+	}
+	return p, nil
+}
+
+// do runs a query, running it again after a jittered backoff while it
+// fails on transient errors and may be retried. A nil policy runs the query
+// once.
+func (p *retryPolicy) do(ctx context.Context, query string, run func() error) error {
+	err := run()
+	if p == nil || !retryable(ctx, query) {
+		return err
+	}
+	for attempt := 1; attempt < p.attempts && connector.IsTransient(err); attempt++ {
+		wait := p.backoff(attempt)
+		log.Printf("Warning: retrying query in %s after transient error: %v", wait, err)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+		countRetry(ctx)
+		err = run()
+	}
+	return err
+}
+
+// backoff returns the wait before a retry, a random duration between half
+// and all of the exponential backoff
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	d := p.initial << (attempt - 1)
+	if d > p.max || d <= 0 {
+		d = p.max
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable reports whether a query may run more than once: read-only
+// queries may, writes only with an idempotency key
+func retryable(ctx context.Context, query string) bool {
+	return isReadOnlySQL(query) || idempotencyKey(ctx) != ""
+}
+
+type idempotencyKeyKey struct{}
+
+// withIdempotencyKey returns a context whose writes may be retried
+func withIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey{}, key)
+}
+
+// idempotencyKey returns the idempotency key set on a context
+func idempotencyKey(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// retryCounter counts the retries of a request's queries
+type retryCounter struct {
+	n      atomic.Int32
+	report func(total int)
+}
+
+type retryCounterKey struct{}
+
+// withRetryReporter returns a context whose query retries are reported
+// with the total retries so far
+func withRetryReporter(ctx context.Context, report func(total int)) context.Context {
+	return context.WithValue(ctx, retryCounterKey{}, &retryCounter{report: report})
+}
+
+func countRetry(ctx context.Context) {
+	if counter, ok := ctx.Value(retryCounterKey{}).(*retryCounter); ok {
+		counter.report(int(counter.n.Add(1)))
+	}
+}
+
+// retryMiddleware reads the idempotency key of REST requests and reports
+// their query retries in the X-Query-Retries header
+func (s *MCPServerWithDB) retryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := withRetryReporter(c.Request.Context(), func(total int) {
+			c.Header(queryRetriesHeader, strconv.Itoa(total))
+		})
+		if key := c.GetHeader(idempotencyKeyHeader); key != "" {
+			ctx = withIdempotencyKey(ctx, key)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyConnector fails its first queries with a transient error
+type flakyConnector struct {
+	rowsConnector
+	failures int
+	calls    int
+}
+
+func (c *flakyConnector) ExecuteQuery(context.Context, string, map[string]interface{}) ([]map[string]interface{}, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, errors.New("failed to execute query: connection reset by peer")
+	}
+	return c.rows, nil
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	retries, err := newRetryPolicy(&RetryConfig{MaxAttempts: 3, InitialBackoff: "1ms"})
+	require.NoError(t, err)
+
+	run := func(ctx context.Context, failures int, query string) (*flakyConnector, int, error) {
+		conn := &flakyConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": 1}}}, failures: failures}
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, retries: retries}
+		var reported int
+		ctx = withRetryReporter(ctx, func(total int) { reported = total })
+		_, err := s.executeQuery(ctx, "rest", query, nil)
+		return conn, reported, err
+	}
+
+	// Reads are retried up to the attempt limit
+	conn, reported, err := run(context.Background(), 2, "SELECT * FROM ORDERS")
+	require.NoError(t, err)
+	assert.Equal(t, 3, conn.calls)
+	assert.Equal(t, 2, reported)
+
+	conn, _, err = run(context.Background(), 3, "SELECT * FROM ORDERS")
+	assert.Error(t, err)
+	assert.Equal(t, 3, conn.calls)
+
+	// Writes are retried only with an idempotency key
+	conn, _, err = run(context.Background(), 1, "INSERT INTO ORDERS (ID) VALUES (:ID)")
+	assert.Error(t, err)
+	assert.Equal(t, 1, conn.calls)
+
+	conn, reported, err = run(withIdempotencyKey(context.Background(), "order-7"), 1, "INSERT INTO ORDERS (ID) VALUES (:ID)")
+	require.NoError(t, err)
+	assert.Equal(t, 2, conn.calls)
+	assert.Equal(t, 1, reported)
+
+	for _, cfg := range []*RetryConfig{{MaxAttempts: -1}, {InitialBackoff: "soon"}, {MaxBackoff: "-1s"}} {
+		_, err := newRetryPolicy(cfg)
+		assert.Error(t, err)
+	}
+}
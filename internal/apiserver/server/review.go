@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+)
+
+const (
+	// defaultReviewThreshold is the risk score from which a query is parked
+	// for review
+	defaultReviewThreshold = 50
+
+	// defaultLargeScanBytes is the estimated scan from which a query is a
+	// large scan
+	defaultLargeScanBytes = 10 << 30
+
+	// riskScore is the score each risk of a query adds; DDL counts twice
+	riskScore = 50
+)
+
+// ReviewConfig parks the free-form queries of POST /query, exports,
+// transactions, baselines and assertions whose risk score reaches a
+// threshold until a reviewer approves them
+type ReviewConfig struct {
+	// Threshold is the risk score from which a query is parked (default:
+	// 50, any single risk)
+	Threshold int `json:"threshold,omitempty"`
+
+	// LargeScanBytes is the estimated scan, when the connector plans
+	// queries, from which a query is a large scan (default: 10 GiB)
+	LargeScanBytes int64 `json:"large_scan_bytes,omitempty"`
+
+	// Expiry is how long a parked query waits for a review (default: 24h)
+	Expiry string `json:"expiry,omitempty"`
+}
+
+// QueryRisk is the risk score of a query and the risks making it up
+type QueryRisk struct {
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// queryReview parks risky queries in a queue of actions reviewers decide
+type queryReview struct {
+	*actionQueue
+	syntax    connector.SQLSyntax
+	threshold int
+	largeScan int64
+}
+
+// newQueryReview validates a review configuration for a database type; nil
+// runs every query without review
+func newQueryReview(cfg *ReviewConfig, dbType string) (*queryReview, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.Threshold < 0 {
+		return nil, fmt.Errorf("invalid threshold: %d", cfg.Threshold)
+	}
+	if cfg.LargeScanBytes < 0 {
+		return nil, fmt.Errorf("invalid large_scan_bytes: %d", cfg.LargeScanBytes)
+	}
+	queue, err := newActionQueue("rest", cfg.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	r := &queryReview{
+		actionQueue: queue,
+		syntax:      connector.SyntaxFor(dbType),
+		threshold:   cfg.Threshold,
+		largeScan:   cfg.LargeScanBytes,
+	}
+	if r.threshold == 0 {
+		r.threshold = defaultReviewThreshold
+	}
+	if r.largeScan == 0 {
+		r.largeScan = defaultLargeScanBytes
+	}
+	return r, nil
+}
+
+// queryRisk scores a query by the risks it carries: writing or changing
+// the schema, scanning a large share of the data, and reading sensitive
+// tables. It returns the kind of the query's first risky statement, or of
+// its first statement.
+func (s *MCPServerWithDB) queryRisk(ctx context.Context, query string, params map[string]interface{}) (*QueryRisk, connector.StatementKind) {
+	risk := &QueryRisk{Reasons: []string{}}
+	kind := connector.StatementRead
+
+	statements, err := connector.ParseStatements(s.review.syntax, query)
+	if err != nil {
+		risk.Score += riskScore
+		risk.Reasons = append(risk.Reasons, "the query cannot be parsed")
+		kind = connector.StatementOther
+	}
+	seen := make(map[connector.StatementKind]bool)
+	for i, st := range statements {
+		if i == 0 {
+			kind = st.Kind
+		}
+		if st.Kind == connector.StatementRead || seen[st.Kind] {
+			continue
+		}
+		if len(seen) == 0 {
+			kind = st.Kind
+		}
+		seen[st.Kind] = true
+		switch st.Kind {
+		case connector.StatementWrite:
+			risk.Score += riskScore
+			risk.Reasons = append(risk.Reasons, "the query writes rows")
+		case connector.StatementDDL:
+			risk.Score += 2 * riskScore
+			risk.Reasons = append(risk.Reasons, "the query changes the schema")
+		default:
+			risk.Score += riskScore
+			risk.Reasons = append(risk.Reasons, fmt.Sprintf("the query runs a %s statement", st.Kind))
+		}
+	}
+
+	conn, connErr := s.selectedConn(ctx)
+	if planner, ok := conn.(connector.Planner); ok && err == nil && connErr == nil {
+		if plan, err := planner.ExplainQuery(ctx, query, params); err == nil && plan.BytesAssigned >= s.review.largeScan {
+			risk.Score += riskScore
+			risk.Reasons = append(risk.Reasons, fmt.Sprintf("the query scans an estimated %d bytes", plan.BytesAssigned))
+		}
+	}
+
+	for _, table := range s.sensitiveTables(query) {
+		risk.Score += riskScore
+		risk.Reasons = append(risk.Reasons, fmt.Sprintf("the query reads sensitive table %s", table))
+	}
+	return risk, kind
+}
+
+// parkQuery parks a query of POST /query whose risk reaches the review
+// threshold, responding with the action the caller polls. It returns false
+// when the query may run now.
+func (s *MCPServerWithDB) parkQuery(c *gin.Context, query string, params map[string]interface{}) bool {
+	return s.parkRequest(c, query, params, nil)
+}
+
+// parkRequest parks a request running query like parkQuery. Once approved,
+// replay runs the request as its caller; a nil replay runs the query.
+func (s *MCPServerWithDB) parkRequest(c *gin.Context, query string, params map[string]interface{}, replay func(ctx context.Context) (interface{}, error)) bool {
+	if s.review == nil {
+		return false
+	}
+	risk, kind := s.queryRisk(c.Request.Context(), query, params)
+	if risk.Score < s.review.threshold {
+		return false
+	}
+	action := s.review.stage(c.Request.Context(), PendingAction{SQL: query, Params: params, Kind: kind, Principal: principalFromContext(c), Risk: risk, replay: replay})
+	s.publish(events.TypeQueryReview, map[string]interface{}{
+		"id":        action.ID,
+		"query":     query,
+		"principal": action.Principal,
+		"score":     risk.Score,
+		"reasons":   risk.Reasons,
+	})
+	c.Header("Location", "/query/reviews/"+action.ID)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "The query needs review before it runs; poll its review for the outcome",
+		"action":  action,
+	})
+	return true
+}
+
+// setupReviewRoutes configures the routes reviewers decide parked queries
+// with, and the route their callers poll
+func (s *MCPServerWithDB) setupReviewRoutes(router *gin.RouterGroup) {
+	if s.review == nil {
+		return
+	}
+	s.setupActionRoutes(router, "/admin/reviews", s.review.actionQueue)
+
+	// Callers only see their own queries
+	router.GET("/query/reviews/:id", func(c *gin.Context) {
+		action, ok := s.review.get(c.Param("id"))
+		if !ok || action.Principal != principalFromContext(c) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Review %s does not exist", c.Param("id"))})
+			return
+		}
+		c.JSON(http.StatusOK, action)
+	})
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestQueryReview(t *testing.T) {
+	conn := &planConnector{}
+	review, err := newQueryReview(&ReviewConfig{}, "snowflake")
+	require.NoError(t, err)
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", SensitiveTables: []string{"SALARIES"}, Admin: &AdminConfig{JWTSecret: "secret"}},
+		DBConn: conn,
+		review: review,
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupAPIRoutes(router.Group(""))
+	admin := adminToken(t, "admin")
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		if strings.HasPrefix(target, "/admin/") {
+			r.Header.Set("Authorization", "Bearer "+admin)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+	park := func(sql string) PendingAction {
+		body, err := json.Marshal(map[string]string{"query": sql})
+		require.NoError(t, err)
+		w := request(http.MethodPost, "/query", string(body))
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var parked struct {
+			Action PendingAction `json:"action"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &parked))
+		assert.Equal(t, "/query/reviews/"+parked.Action.ID, w.Header().Get("Location"))
+		return parked.Action
+	}
+
+	// Low-risk queries run at once
+	w := request(http.MethodPost, "/query", `{"query": "SELECT * FROM ORDERS"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "SELECT * FROM ORDERS", conn.query)
+
+	conn.query = ""
+	purge := park("DELETE FROM ORDERS WHERE STATUS = 'void'")
+	assert.Equal(t, ActionPending, purge.Status)
+	assert.Equal(t, 50, purge.Risk.Score)
+	assert.EqualValues(t, "write", purge.Kind)
+	salaries := park(`SELECT * FROM HR."SALARIES"`)
+	assert.Equal(t, []string{"the query reads sensitive table SALARIES"}, salaries.Risk.Reasons)
+	assert.Empty(t, conn.query)
+
+	// Callers poll their own queries
+	var polled PendingAction
+	w = request(http.MethodGet, "/query/reviews/"+purge.ID, "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &polled))
+	assert.Equal(t, ActionPending, polled.Status)
+	review.actions[salaries.ID].Principal = "someone-else"
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/query/reviews/"+salaries.ID, "").Code)
+
+	var pending []PendingAction
+	require.NoError(t, json.Unmarshal(request(http.MethodGet, "/admin/reviews?status=pending", "").Body.Bytes(), &pending))
+	assert.Len(t, pending, 2)
+
+	w = request(http.MethodPost, "/admin/reviews/"+salaries.ID+"/reject", `{"reason": "not for agents"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = request(http.MethodPost, "/admin/reviews/"+purge.ID+"/approve", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "DELETE FROM ORDERS WHERE STATUS = 'void'", conn.query)
+
+	w = request(http.MethodGet, "/query/reviews/"+purge.ID, "")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &polled))
+	assert.Equal(t, ActionExecuted, polled.Status)
+
+	// Risks add up to the threshold
+	review.threshold = 100
+	review.largeScan = 256
+	conn.query = ""
+	risk, _ := s.queryRisk(context.Background(), "SELECT * FROM EVENTS", nil)
+	assert.Equal(t, 50, risk.Score)
+	scan := park("UPDATE EVENTS SET SEEN = TRUE")
+	assert.Len(t, scan.Risk.Reasons, 2)
+	assert.Empty(t, conn.query)
+
+	_, err = newQueryReview(&ReviewConfig{Expiry: "soon"}, "")
+	assert.Error(t, err)
+}
+
+func TestQueryReviewEntryPoints(t *testing.T) {
+	exports, err := newExportJobs(&ExportConfig{AllowedDestinations: []string{"https://storage.example.com/exports/"}})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tc := range []struct {
+		name, target, body string
+		conn               connector.DatabaseConnector
+		ran                func(conn connector.DatabaseConnector) bool
+	}{
+		{
+			name:   "export",
+			target: "/exports",
+			body:   `{"query": "SELECT * FROM SALARIES", "destination": "https://storage.example.com/exports/salaries.csv"}`,
+			conn:   &rowsConnector{},
+		},
+		{
+			name:   "transaction",
+			target: "/transaction",
+			body:   `{"statements": [{"sql": "DELETE FROM ORDERS"}]}`,
+			conn:   &txConnector{},
+			ran:    func(conn connector.DatabaseConnector) bool { return len(conn.(*txConnector).log) > 0 },
+		},
+		{
+			name:   "baseline",
+			target: "/query/baselines",
+			body:   `{"query": "SELECT * FROM SALARIES"}`,
+			conn:   &paramsConnector{},
+			ran:    func(conn connector.DatabaseConnector) bool { return conn.(*paramsConnector).query != "" },
+		},
+		{
+			name:   "assertion",
+			target: "/tables/ORDERS/assert",
+			body:   `{"checks": [{"type": "sql", "sql": "SELECT * FROM SALARIES WHERE AMOUNT < 0"}]}`,
+			conn:   &assertConnector{},
+			ran:    func(conn connector.DatabaseConnector) bool { return len(conn.(*assertConnector).queries) > 0 },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			review, err := newQueryReview(&ReviewConfig{}, "snowflake")
+			require.NoError(t, err)
+			s := &MCPServerWithDB{
+				Config:    &MCPServerConfig{Name: "sales", SensitiveTables: []string{"SALARIES"}},
+				DBConn:    tc.conn,
+				review:    review,
+				exports:   exports,
+				baselines: newBaselineStore(),
+				ctx:       ctx,
+			}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			s.setupAPIRoutes(router.Group(""))
+
+			// Risky requests are parked instead of running
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body)))
+			require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+			var parked struct {
+				Action PendingAction `json:"action"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &parked))
+			require.NotEmpty(t, parked.Action.ID)
+			assert.Equal(t, ActionPending, parked.Action.Status)
+			if tc.ran != nil {
+				assert.False(t, tc.ran(tc.conn))
+			}
+			if tc.name == "export" {
+				assert.Empty(t, exports.list())
+			}
+
+			// Approving them runs the request as it was sent
+			action, err := s.approveAction(context.Background(), review.actionQueue, parked.Action.ID, "admin")
+			require.NoError(t, err)
+			assert.Equal(t, ActionExecuted, action.Status, action.Error)
+			assert.NotNil(t, action.Result)
+			if tc.ran != nil {
+				assert.True(t, tc.ran(tc.conn))
+			}
+			if tc.name == "export" {
+				assert.Len(t, exports.list(), 1)
+			}
+		})
+	}
+}
+
+func TestMentionsTable(t *testing.T) {
+	assert.True(t, mentionsTable("select * from salaries", "SALARIES"))
+	assert.True(t, mentionsTable(`SELECT * FROM HR."SALARIES" s`, "SALARIES"))
+	assert.False(t, mentionsTable("SELECT * FROM SALARIES_SUMMARY", "SALARIES"))
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// pathParamPattern matches {param} placeholders in generated endpoint paths
+var pathParamPattern = regexp.MustCompile(`\{([^}/]+)\}`)
+
+// RouteDiff describes how a change altered the generated routes
+type RouteDiff struct {
+	Added   []string `json:"added,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Empty reports whether the change left the routes untouched
+func (d RouteDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Updated) == 0 && len(d.Removed) == 0
+}
+
+// routeManager serves the generated endpoints. Gin cannot add or remove
+// routes on a running engine, so every change builds a new engine holding
+// only the generated routes and swaps it in atomically; the main router
+// forwards unmatched requests to it.
+type routeManager struct {
+	prefix  string
+	handler func(endpoint connector.APIEndpoint) gin.HandlerFunc
+
+	mu     sync.Mutex
+	routes map[string]connector.APIEndpoint
+	engine atomic.Pointer[gin.Engine]
+
+	// statsMu guards the runtime state of routes, keyed by route key. It
+	// outlives regeneration, so a regenerated endpoint stays disabled.
+	statsMu  sync.Mutex
+	disabled map[string]bool
+	stats    map[string]*EndpointStats
+}
+
+// newRouteManager creates a route manager serving routes under prefix
+func newRouteManager(prefix string, handler func(endpoint connector.APIEndpoint) gin.HandlerFunc) *routeManager {
+	return &routeManager{
+		prefix:   prefix,
+		handler:  handler,
+		routes:   make(map[string]connector.APIEndpoint),
+		disabled: make(map[string]bool),
+		stats:    make(map[string]*EndpointStats),
+	}
+}
+
+// routeKey identifies an endpoint by method and path
+func routeKey(e connector.APIEndpoint) string {
+	return e.Method + " " + e.Path
+}
+
+// Apply adds or replaces endpoints. Endpoints previously generated for the
+// tables of the new endpoints but no longer present are removed, so
+// regenerating a table is idempotent.
+func (m *routeManager) Apply(endpoints []connector.APIEndpoint) (RouteDiff, error) {
+	tables := make(map[string]bool)
+	for _, e := range endpoints {
+		if e.Table != "" {
+			tables[e.Table] = true
+		}
+	}
+
+	return m.update(func(next map[string]connector.APIEndpoint) {
+		for key, e := range next {
+			if tables[e.Table] {
+				delete(next, key)
+			}
+		}
+		for _, e := range endpoints {
+			next[routeKey(e)] = e
+		}
+	})
+}
+
+// RemoveTables removes the endpoints generated for the tables
+func (m *routeManager) RemoveTables(tables []string) (RouteDiff, error) {
+	drop := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		drop[t] = true
+	}
+
+	return m.update(func(next map[string]connector.APIEndpoint) {
+		for key, e := range next {
+			if drop[e.Table] {
+				delete(next, key)
+			}
+		}
+	})
+}
+
+// Endpoints returns the generated endpoints ordered by path and method
+func (m *routeManager) Endpoints() []connector.APIEndpoint {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoints := make([]connector.APIEndpoint, 0, len(m.routes))
+	for _, key := range sortedRouteKeys(m.routes) {
+		endpoints = append(endpoints, m.routes[key])
+	}
+	return endpoints
+}
+
+// ServeHTTP serves a request with the current generated routes
+func (m *routeManager) ServeHTTP(c *gin.Context) {
+	engine := m.engine.Load()
+	if engine == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Route not found"})
+		return
+	}
+	engine.ServeHTTP(c.Writer, c.Request)
+}
+
+// update applies a change to a copy of the routes and swaps in the rebuilt
+// engine. The routes are left untouched when the result cannot be served.
+func (m *routeManager) update(change func(next map[string]connector.APIEndpoint)) (RouteDiff, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	next := make(map[string]connector.APIEndpoint, len(m.routes))
+	for key, e := range m.routes {
+		next[key] = e
+	}
+	change(next)
+
+	diff := diffRoutes(m.routes, next)
+	if diff.Empty() && m.engine.Load() != nil {
+		return diff, nil
+	}
+
+	engine, err := m.build(next)
+	if err != nil {
+		return RouteDiff{}, err
+	}
+	m.routes = next
+	m.engine.Store(engine)
+	return diff, nil
+}
+
+// build creates an engine serving the routes. Gin panics on conflicting
+// routes, which is reported as an error instead.
+func (m *routeManager) build(routes map[string]connector.APIEndpoint) (engine *gin.Engine, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			engine, err = nil, fmt.Errorf("conflicting generated routes: %v", r)
+		}
+	}()
+
+	engine = gin.New()
+	group := engine.Group(m.prefix)
+	for _, key := range sortedRouteKeys(routes) {
+		e := routes[key]
+		switch e.Method {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+			group.Handle(e.Method, ginPath(e.Path), m.serve(e))
+		default:
+			log.Printf("Unsupported HTTP method: %s", e.Method)
+		}
+	}
+	return engine, nil
+}
+
+// serve wraps the handler of an endpoint to reject requests while it is
+// disabled and to record the requests it serves
+func (m *routeManager) serve(e connector.APIEndpoint) gin.HandlerFunc {
+	key := routeKey(e)
+	handler := m.handler(e)
+	return func(c *gin.Context) {
+		if m.isDisabled(key) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("Endpoint %s is disabled", key)})
+			return
+		}
+		start := time.Now()
+		handler(c)
+		m.record(key, c.Writer.Status(), start)
+	}
+}
+
+// Lookup returns the endpoint of a route key
+func (m *routeManager) Lookup(key string) (connector.APIEndpoint, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.routes[key]
+	return e, ok
+}
+
+// SetDisabled disables or re-enables a route at runtime
+func (m *routeManager) SetDisabled(key string, disabled bool) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	if disabled {
+		m.disabled[key] = true
+	} else {
+		delete(m.disabled, key)
+	}
+}
+
+// isDisabled reports whether a route is disabled
+func (m *routeManager) isDisabled(key string) bool {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	return m.disabled[key]
+}
+
+// record adds a served request to the stats of a route
+func (m *routeManager) record(key string, status int, start time.Time) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	st := m.stats[key]
+	if st == nil {
+		st = &EndpointStats{}
+		m.stats[key] = st
+	}
+	st.add(status, start, time.Since(start))
+}
+
+// Stats returns a copy of the stats of a route and whether it is disabled
+func (m *routeManager) Stats(key string) (EndpointStats, bool) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	var st EndpointStats
+	if m.stats[key] != nil {
+		st = *m.stats[key]
+	}
+	return st, m.disabled[key]
+}
+
+// ginPath converts {param} placeholders to gin's :param syntax
+func ginPath(path string) string {
+	return pathParamPattern.ReplaceAllString(path, ":$1")
+}
+
+// sortedRouteKeys returns the route keys in a stable order
+func sortedRouteKeys(routes map[string]connector.APIEndpoint) []string {
+	keys := make([]string, 0, len(routes))
+	for key := range routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffRoutes compares two route sets
+func diffRoutes(prev, next map[string]connector.APIEndpoint) RouteDiff {
+	var diff RouteDiff
+	for _, key := range sortedRouteKeys(next) {
+		old, ok := prev[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, key)
+		case !sameEndpoint(old, next[key]):
+			diff.Updated = append(diff.Updated, key)
+		}
+	}
+	for _, key := range sortedRouteKeys(prev) {
+		if _, ok := next[key]; !ok {
+			diff.Removed = append(diff.Removed, key)
+		}
+	}
+	return diff
+}
+
+// sameEndpoint reports whether two endpoints behave the same
+func sameEndpoint(a, b connector.APIEndpoint) bool {
+	if a.Query != b.Query || a.Description != b.Description || a.Table != b.Table || len(a.Parameters) != len(b.Parameters) {
+		return false
+	}
+	if !reflect.DeepEqual(a.Columns, b.Columns) || !reflect.DeepEqual(a.SearchColumns, b.SearchColumns) ||
+		!reflect.DeepEqual(a.Params, b.Params) {
+		return false
+	}
+	for k, v := range a.Parameters {
+		if w, ok := b.Parameters[k]; !ok || fmt.Sprint(v) != fmt.Sprint(w) {
+			return false
+		}
+	}
+	return true
+}
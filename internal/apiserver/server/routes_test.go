@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/api"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestRouteManagerRegeneration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := newRouteManager("/api/db", func(e connector.APIEndpoint) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.String(http.StatusOK, e.Query+" "+c.Param("id"))
+		}
+	})
+	router := gin.New()
+	router.NoRoute(m.ServeHTTP)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	orders := []connector.APIEndpoint{
+		{Table: "orders", Method: "GET", Path: "/orders", Query: "list"},
+		{Table: "orders", Method: "GET", Path: "/orders/{id}", Query: "get"},
+	}
+	diff, err := m.Apply(orders)
+	require.NoError(t, err)
+	assert.Len(t, diff.Added, 2)
+	assert.Equal(t, "get 42", get("/api/db/orders/42").Body.String())
+
+	// Regenerating the same endpoints is a no-op rather than a panic
+	diff, err = m.Apply(orders)
+	require.NoError(t, err)
+	assert.True(t, diff.Empty())
+
+	// Endpoints no longer generated for a table are removed
+	diff, err = m.Apply([]connector.APIEndpoint{{Table: "orders", Method: "GET", Path: "/orders", Query: "list v2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /orders"}, diff.Updated)
+	assert.Equal(t, []string{"GET /orders/{id}"}, diff.Removed)
+	assert.Equal(t, http.StatusNotFound, get("/api/db/orders/42").Code)
+	assert.Equal(t, "list v2 ", get("/api/db/orders").Body.String())
+
+	// Routes gin cannot serve together are rejected and the old routes kept
+	_, err = m.Apply([]connector.APIEndpoint{
+		{Table: "items", Method: "GET", Path: "/items/{id}"},
+		{Table: "items", Method: "GET", Path: "/items/{item_id}"},
+	})
+	assert.Error(t, err)
+	assert.Len(t, m.Endpoints(), 1)
+
+	diff, err = m.RemoveTables([]string{"orders"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /orders"}, diff.Removed)
+	assert.Equal(t, http.StatusNotFound, get("/api/db/orders").Code)
+}
+
+// keylessConnector generates only the list endpoints of its tables
+type keylessConnector struct {
+	graphQLConnector
+}
+
+func (c *keylessConnector) GenerateAPIEndpoints(_ context.Context, tables []string) ([]connector.APIEndpoint, error) {
+	var endpoints []connector.APIEndpoint
+	for _, table := range tables {
+		endpoints = append(endpoints, connector.APIEndpoint{
+			Table: table, Operation: connector.OperationList, Method: http.MethodGet, Path: "/" + table, Query: "list",
+		})
+	}
+	return endpoints, nil
+}
+
+func TestGenerateEndpointsWithConfiguredKey(t *testing.T) {
+	conn := &keylessConnector{graphQLConnector{tables: map[string]*connector.TableMetadata{
+		"EVENTS": {Name: "EVENTS", Columns: []connector.Column{
+			{Name: "SOURCE", Type: "VARCHAR"},
+			{Name: "SEQ", Type: "INTEGER"},
+			{Name: "PAYLOAD", Type: "VARCHAR", Nullable: true},
+		}},
+		"LOGS": {Name: "LOGS", Columns: []connector.Column{{Name: "LINE", Type: "VARCHAR"}}},
+	}}}
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{EndpointOverrides: api.EndpointOverrides{"events": {Key: []string{"SOURCE", "SEQ"}}}},
+		DBConn: conn,
+	}
+
+	endpoints, err := s.GenerateEndpoints(context.Background(), []string{"EVENTS", "LOGS"})
+	require.NoError(t, err)
+	require.Len(t, endpoints, 4)
+	get, update := endpoints[2], endpoints[3]
+	assert.Equal(t, connector.OperationGet, get.Operation)
+	assert.Equal(t, "/EVENTS/{SOURCE}/{SEQ}", get.Path)
+	assert.Equal(t, `SELECT * FROM "EVENTS" WHERE "SOURCE" = :SOURCE AND "SEQ" = :SEQ`, get.Query)
+	assert.Equal(t, connector.OperationUpdate, update.Operation)
+	assert.Equal(t, `UPDATE "EVENTS" SET "PAYLOAD" = :PAYLOAD WHERE "SOURCE" = :SOURCE AND "SEQ" = :SEQ`, update.Query)
+
+	// Configured columns must exist
+	s.Config.EndpointOverrides = api.EndpointOverrides{"EVENTS": {Key: []string{"ID"}}}
+	_, err = s.GenerateEndpoints(context.Background(), []string{"EVENTS"})
+	assert.EqualError(t, err, "table EVENTS: key column ID does not exist")
+}
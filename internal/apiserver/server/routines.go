@@ -0,0 +1,300 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+var (
+	// ErrRoutinesUnsupported is returned when the connector cannot list routines
+	ErrRoutinesUnsupported = errors.New("connector does not support stored procedures and functions")
+
+	// ErrRoutineNotFound is returned for routines that are not exposed
+	ErrRoutineNotFound = errors.New("routine not found")
+)
+
+// listRoutines returns the procedures and functions of the schema
+func (s *MCPServerWithDB) listRoutines(ctx context.Context) ([]connector.Routine, error) {
+	lister, ok := s.DBConn.(connector.RoutineLister)
+	if !ok {
+		return nil, ErrRoutinesUnsupported
+	}
+	return lister.ListRoutines(ctx)
+}
+
+// exposedRoutines returns the routines selected by the configured
+// allowlist, keyed by name, listing them on first use
+func (s *MCPServerWithDB) exposedRoutines(ctx context.Context) (map[string]connector.Routine, error) {
+	if len(s.Config.Routines) == 0 || s.DBConn == nil {
+		return nil, nil
+	}
+
+	s.routinesMu.Lock()
+	defer s.routinesMu.Unlock()
+	if s.routinesCache != nil {
+		return s.routinesCache, nil
+	}
+
+	routines, err := s.listRoutines(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.routinesCache = allowedRoutines(routines, s.Config.Routines)
+	return s.routinesCache, nil
+}
+
+// allowedRoutines selects the routines matching an allowlist entry by name
+// or by signature. Routines are exposed by name, so a name with several
+// allowed overloads is skipped until the allowlist picks one signature.
+func allowedRoutines(routines []connector.Routine, allow []string) map[string]connector.Routine {
+	allowed := make(map[string]bool, len(allow))
+	for _, entry := range allow {
+		allowed[normalizeSignature(entry)] = true
+	}
+
+	exposed := make(map[string]connector.Routine)
+	ambiguous := make(map[string]bool)
+	for _, r := range routines {
+		if !allowed[strings.ToUpper(r.Name)] && !allowed[normalizeSignature(r.Signature())] {
+			continue
+		}
+		if _, ok := exposed[r.Name]; ok {
+			ambiguous[r.Name] = true
+			continue
+		}
+		exposed[r.Name] = r
+	}
+	for name := range ambiguous {
+		log.Printf("Warning: Routine %s is overloaded; allow one of its signatures to expose it", name)
+		delete(exposed, name)
+	}
+	return exposed
+}
+
+// normalizeSignature uppercases a routine name or signature and drops its
+// whitespace so allowlist entries match however they are spaced
+func normalizeSignature(signature string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(signature), ""))
+}
+
+// routineColumns describes the arguments of a routine as columns, so they
+// are validated and converted like the fields of a request body
+func routineColumns(r connector.Routine) []connector.Column {
+	columns := make([]connector.Column, 0, len(r.Arguments))
+	for _, arg := range r.Arguments {
+		columns = append(columns, connector.Column{Name: arg.Name, Type: arg.Type, Nullable: true})
+	}
+	return columns
+}
+
+// bindRoutineArgs checks the arguments of a call against the routine's
+// signature: every argument must be given, possibly as null, and match
+// its type
+func bindRoutineArgs(r connector.Routine, args map[string]interface{}) (map[string]interface{}, []FieldError) {
+	var errs []FieldError
+	params := make(map[string]interface{}, len(r.Arguments))
+	known := make(map[string]bool, len(r.Arguments))
+	for _, col := range routineColumns(r) {
+		known[col.Name] = true
+		value, ok := args[col.Name]
+		if !ok {
+			errs = append(errs, FieldError{Field: col.Name, Message: "is required"})
+			continue
+		}
+		// MCP arguments are decoded without json.Number
+		if f, isFloat := value.(float64); isFloat {
+			value = json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+		}
+		if value != nil {
+			v, err := checkColumnValue(col, value)
+			if err != nil {
+				errs = append(errs, FieldError{Field: col.Name, Message: err.Error()})
+				continue
+			}
+			value = v
+		}
+		params[connector.ParamName(col.Name)] = value
+	}
+	for name := range args {
+		if !known[name] {
+			errs = append(errs, FieldError{Field: name, Message: "is not an argument of this routine"})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return params, errs
+}
+
+// routineQuery returns the statement calling a routine with params.
+// Routines run arbitrary SQL, so callers restricted by row filters may not
+// call them, and the statement must pass the query policy.
+func (s *MCPServerWithDB) routineQuery(ctx context.Context, r connector.Routine, params map[string]interface{}) (string, error) {
+	if err := s.checkFreeForm(ctx); err != nil {
+		return "", err
+	}
+	query := connector.CallQuery(connector.DialectOf(s.DBConn), r)
+	if err := s.checkQueryPolicy(ctx, "routine", query, params); err != nil {
+		return "", err
+	}
+	return query, nil
+}
+
+// routineToolName returns the name of the MCP tool calling a routine
+func routineToolName(name string) string {
+	return "call_" + tableToolBase(name)
+}
+
+// routineTools exposes every allowed routine as an MCP tool
+func (s *MCPServerWithDB) routineTools(ctx context.Context) []mcpTool {
+	routines, err := s.exposedRoutines(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to list routines for MCP tools: %v", err)
+		return nil
+	}
+
+	names := make([]string, 0, len(routines))
+	for name := range routines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]mcpTool, 0, len(routines))
+	for _, name := range names {
+		r := routines[name]
+		toolName := routineToolName(name)
+		schema := mcp.ToolInputSchema{Type: "object", Properties: map[string]any{}}
+		for _, col := range routineColumns(r) {
+			prop := columnSchema(col)
+			prop["description"] = fmt.Sprintf("%s argument", col.Type)
+			schema.Properties[col.Name] = prop
+			schema.Required = append(schema.Required, col.Name)
+		}
+		description := r.Description
+		if description == "" {
+			description = fmt.Sprintf("Call the %s %s", r.Kind, r.Signature())
+		}
+
+		tools = append(tools, mcpTool{
+			Schema: mcp.ToolSchema{
+				Name:        toolName,
+				Description: description,
+				InputSchema: schema,
+			},
+			Handler: func(ctx context.Context, sess *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				params, fieldErrs := bindRoutineArgs(r, args)
+				if len(fieldErrs) > 0 {
+					msgs := make([]string, 0, len(fieldErrs))
+					for _, e := range fieldErrs {
+						msgs = append(msgs, e.Field+" "+e.Message)
+					}
+					return nil, fmt.Errorf("invalid arguments: %s", strings.Join(msgs, "; "))
+				}
+				query, err := s.routineQuery(ctx, r, params)
+				if err != nil {
+					return nil, err
+				}
+				if staged, err := s.stageAction(ctx, toolName, query, params); staged != nil || err != nil {
+					return staged, err
+				}
+				result, err := s.executeTracked(ctx, query, params)
+				if err != nil {
+					return nil, err
+				}
+				return s.rowsToolResult(toolName, sess, result)
+			},
+		})
+	}
+	return tools
+}
+
+// setupRoutineRoutes configures the routes listing the routines of the
+// schema and calling the allowed ones
+func (s *MCPServerWithDB) setupRoutineRoutes(router *gin.RouterGroup) {
+	router.GET("/routines", func(c *gin.Context) {
+		routines, err := s.listRoutines(c.Request.Context())
+		if err != nil {
+			s.respondError(c, routineErrorStatus(err), "Failed to list routines", err)
+			return
+		}
+		exposed, err := s.exposedRoutines(c.Request.Context())
+		if err != nil {
+			s.respondError(c, routineErrorStatus(err), "Failed to list routines", err)
+			return
+		}
+
+		type routineInfo struct {
+			connector.Routine
+			Signature string                `json:"signature"`
+			Exposed   bool                  `json:"exposed"`
+			Params    []connector.Parameter `json:"params,omitempty"`
+		}
+		list := make([]routineInfo, 0, len(routines))
+		for _, r := range routines {
+			info := routineInfo{Routine: r, Signature: r.Signature()}
+			if e, ok := exposed[r.Name]; ok && e.Signature() == r.Signature() {
+				info.Exposed = true
+				info.Params = connector.RoutineParams(r)
+			}
+			list = append(list, info)
+		}
+		c.JSON(http.StatusOK, list)
+	})
+
+	// Allowed routines take their arguments from a JSON body
+	router.POST("/routines/:name", func(c *gin.Context) {
+		routines, err := s.exposedRoutines(c.Request.Context())
+		if err != nil {
+			s.respondError(c, routineErrorStatus(err), "Failed to call routine", err)
+			return
+		}
+		r, ok := routines[c.Param("name")]
+		if !ok {
+			s.respondError(c, http.StatusNotFound, "Failed to call routine", ErrRoutineNotFound)
+			return
+		}
+
+		args := make(map[string]interface{})
+		if c.Request.ContentLength != 0 {
+			if args, err = decodeBody(c.Request.Body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+				return
+			}
+		}
+		params, fieldErrs := bindRoutineArgs(r, args)
+		if len(fieldErrs) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid routine arguments", "fields": fieldErrs})
+			return
+		}
+
+		query, err := s.routineQuery(c.Request.Context(), r, params)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to call routine", err)
+			return
+		}
+		result, err := s.executeOrdered(c.Request.Context(), "routine", query, params)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to call routine", err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	})
+}
+
+// routineErrorStatus maps routine errors to HTTP status codes
+func routineErrorStatus(err error) int {
+	if errors.Is(err, ErrRoutinesUnsupported) {
+		return http.StatusNotImplemented
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// routinesConnector lists fixed routines
+type routinesConnector struct {
+	paramsConnector
+	routines []connector.Routine
+}
+
+func (c *routinesConnector) ListRoutines(context.Context) ([]connector.Routine, error) {
+	return c.routines, nil
+}
+
+func TestRoutines(t *testing.T) {
+	conn := &routinesConnector{routines: []connector.Routine{
+		{Name: "CLOSE_ORDER", Kind: connector.RoutineProcedure, ReturnType: "VARCHAR", Arguments: []connector.RoutineArgument{
+			{Name: "ID", Type: "NUMBER"}, {Name: "NOTE", Type: "VARCHAR"},
+		}},
+		{Name: "TAX", Kind: connector.RoutineFunction, ReturnType: "NUMBER", Arguments: []connector.RoutineArgument{{Name: "AMOUNT", Type: "FLOAT"}}},
+		{Name: "TAX", Kind: connector.RoutineFunction, ReturnType: "NUMBER", Arguments: []connector.RoutineArgument{{Name: "AMOUNT", Type: "FLOAT"}, {Name: "RATE", Type: "FLOAT"}}},
+		{Name: "PURGE", Kind: connector.RoutineProcedure, ReturnType: "VARCHAR", Arguments: []connector.RoutineArgument{}},
+	}}
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", Routines: []string{"close_order", "TAX(FLOAT, FLOAT)"}},
+		DBConn: conn,
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupRoutineRoutes(router.Group(""))
+
+	// Every routine is listed; only allowed ones are exposed
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/routines", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list []struct {
+		Signature string `json:"signature"`
+		Exposed   bool   `json:"exposed"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	exposed := make(map[string]bool)
+	for _, r := range list {
+		exposed[r.Signature] = r.Exposed
+	}
+	assert.Equal(t, map[string]bool{
+		"CLOSE_ORDER(NUMBER, VARCHAR)": true,
+		"TAX(FLOAT)":                   false,
+		"TAX(FLOAT, FLOAT)":            true,
+		"PURGE()":                      false,
+	}, exposed)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/routines/CLOSE_ORDER", strings.NewReader(`{"ID": 7, "NOTE": null}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `CALL "CLOSE_ORDER"(:ID, :NOTE)`, conn.query)
+	assert.Equal(t, map[string]interface{}{"ID": "7", "NOTE": nil}, conn.params)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/routines/CLOSE_ORDER", strings.NewReader(`{"ID": "seven", "REASON": "x"}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp struct {
+		Fields []FieldError `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []FieldError{
+		{Field: "ID", Message: "must be a number, got string"},
+		{Field: "NOTE", Message: "is required"},
+		{Field: "REASON", Message: "is not an argument of this routine"},
+	}, resp.Fields)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/routines/PURGE", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Allowed routines are MCP tools with typed arguments
+	tools := s.routineTools(context.Background())
+	require.Len(t, tools, 2)
+	assert.Equal(t, "call_close_order", tools[0].Schema.Name)
+	assert.Equal(t, []string{"ID", "NOTE"}, tools[0].Schema.InputSchema.Required)
+	assert.Equal(t, []string{"number", "null"}, tools[0].Schema.InputSchema.Properties["ID"].(map[string]any)["type"])
+	assert.Equal(t, "call_tax", tools[1].Schema.Name)
+	_, err := tools[1].Handler(context.Background(), &mcpSession{}, map[string]interface{}{"AMOUNT": 10.0})
+	assert.EqualError(t, err, "invalid arguments: RATE is required")
+}
+
+func TestAllowedRoutinesSkipsAmbiguousOverloads(t *testing.T) {
+	routines := []connector.Routine{
+		{Name: "TAX", Arguments: []connector.RoutineArgument{{Name: "A", Type: "FLOAT"}}},
+		{Name: "TAX", Arguments: []connector.RoutineArgument{{Name: "A", Type: "FLOAT"}, {Name: "B", Type: "FLOAT"}}},
+	}
+	assert.Empty(t, allowedRoutines(routines, []string{"TAX"}))
+	assert.Len(t, allowedRoutines(routines, []string{"tax(float)"})["TAX"].Arguments, 1)
+}
@@ -0,0 +1,305 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/auth/jwt"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/auth/oauth2"
+)
+
+// ErrRowSecurity is returned when row security denies a caller a table or
+// a query
+var ErrRowSecurity = errors.New("denied by row security")
+
+// claimPattern matches the {claims.name} placeholders of row filters
+var claimPattern = regexp.MustCompile(`\{claims\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// RowSecurityConfig restricts the rows callers can read and change. Filters
+// are added to the SQL of generated endpoints, table tools, GraphQL and
+// exports; callers restricted by a filter cannot run free-form SQL.
+type RowSecurityConfig struct {
+	// Filters are the row filters of tables
+	Filters []RowFilterConfig `json:"filters"`
+
+	// BypassRoles are roles whose callers are not filtered
+	BypassRoles []string `json:"bypass_roles,omitempty"`
+
+	// JWTSecret verifies HS256 bearer tokens carrying the callers' claims.
+	// Without it claims come from the authentication in front of the server.
+	JWTSecret string `json:"jwt_secret,omitempty"`
+}
+
+// RowFilterConfig is a condition the rows of a table must satisfy. A table
+// with filters is inaccessible to callers none of its filters applies to.
+type RowFilterConfig struct {
+	Table string `json:"table"`
+
+	// Roles the filter applies to; empty applies it to every caller
+	Roles []string `json:"roles,omitempty"`
+
+	// Filter is a SQL condition on the table's columns in which
+	// {claims.name} stands for a claim of the caller, e.g.
+	// "TENANT_ID = {claims.tenant}". Claims are bound as parameters.
+	Filter string `json:"filter"`
+}
+
+// rowFilter is a validated RowFilterConfig whose claims are replaced by
+// bind parameters
+type rowFilter struct {
+	roles     map[string]bool
+	condition string
+	claims    []string
+}
+
+// rowSecurity applies the row filters of a server
+type rowSecurity struct {
+	filters map[string][]rowFilter // keyed by upper-cased table name
+	bypass  map[string]bool
+	secret  []byte
+}
+
+// newRowSecurity validates a row security configuration; nil disables it
+func newRowSecurity(cfg *RowSecurityConfig) (*rowSecurity, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	rs := &rowSecurity{
+		filters: make(map[string][]rowFilter),
+		bypass:  toSet(cfg.BypassRoles),
+	}
+	if cfg.JWTSecret != "" {
+		rs.secret = []byte(cfg.JWTSecret)
+	}
+	for i, f := range cfg.Filters {
+		if f.Table == "" || strings.TrimSpace(f.Filter) == "" {
+			return nil, fmt.Errorf("filter %d: table and filter are required", i)
+		}
+		filter := rowFilter{roles: toSet(f.Roles)}
+		filter.condition = claimPattern.ReplaceAllStringFunc(f.Filter, func(m string) string {
+			claim := claimPattern.FindStringSubmatch(m)[1]
+			filter.claims = append(filter.claims, claim)
+			return ":" + claimParam(claim)
+		})
+		if strings.Contains(filter.condition, "{") {
+			return nil, fmt.Errorf("filter %d: invalid claim placeholder in %q", i, f.Filter)
+		}
+		if _, err := connector.AddRowFilter("SELECT * FROM t", filter.condition); err != nil {
+			return nil, fmt.Errorf("filter %d: %w", i, err)
+		}
+		table := strings.ToUpper(f.Table)
+		rs.filters[table] = append(rs.filters[table], filter)
+	}
+	return rs, nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// claimParam names the bind parameter of a claim
+func claimParam(claim string) string {
+	return "rls_" + claim
+}
+
+// restricted reports whether filters apply to the caller at all
+func (rs *rowSecurity) restricted(ctx context.Context) bool {
+	return len(rs.filters) > 0 && !rs.bypass[callerRole(ctx)]
+}
+
+// filter returns the condition the caller's rows of a table must satisfy
+// and the claim values it binds. The condition is empty for unrestricted
+// tables; tables whose filters do not apply to the caller are denied.
+func (rs *rowSecurity) filter(ctx context.Context, table string) (string, map[string]interface{}, error) {
+	filters := rs.filters[strings.ToUpper(table)]
+	if len(filters) == 0 || !rs.restricted(ctx) {
+		return "", nil, nil
+	}
+
+	role := callerRole(ctx)
+	claims := claimsFromContext(ctx)
+	var conditions []string
+	params := make(map[string]interface{})
+	for _, f := range filters {
+		if len(f.roles) > 0 && !f.roles[role] {
+			continue
+		}
+		for _, claim := range f.claims {
+			switch v := claims[claim].(type) {
+			case string, bool, float64, int64, int:
+				params[claimParam(claim)] = v
+			case nil:
+				return "", nil, fmt.Errorf("%w: claim %s is required to access %s", ErrRowSecurity, claim, table)
+			default:
+				return "", nil, fmt.Errorf("%w: claim %s must be a scalar", ErrRowSecurity, claim)
+			}
+		}
+		conditions = append(conditions, f.condition)
+	}
+	switch len(conditions) {
+	case 0:
+		return "", nil, fmt.Errorf("%w: %s is not accessible to role %q", ErrRowSecurity, table, role)
+	case 1:
+		return conditions[0], params, nil
+	}
+	return "(" + strings.Join(conditions, ") AND (") + ")", params, nil
+}
+
+// restrictQuery adds the caller's row filter of a table to a generated
+// SELECT, UPDATE or DELETE, binding the claims it refers to in params
+func (s *MCPServerWithDB) restrictQuery(ctx context.Context, table, query string, params map[string]interface{}) (string, error) {
+	if s.rowSecurity == nil {
+		return query, nil
+	}
+	if table == "" {
+		return query, s.checkFreeForm(ctx)
+	}
+	condition, values, err := s.rowSecurity.filter(ctx, table)
+	if err != nil || condition == "" {
+		return query, err
+	}
+	filtered, err := connector.AddRowFilter(query, condition)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrRowSecurity, err)
+	}
+	for k, v := range values {
+		params[k] = v
+	}
+	return filtered, nil
+}
+
+// checkTableAccess rejects inserts into tables the caller cannot access
+func (s *MCPServerWithDB) checkTableAccess(ctx context.Context, table string) error {
+	if s.rowSecurity == nil {
+		return nil
+	}
+	if table == "" {
+		return s.checkFreeForm(ctx)
+	}
+	_, _, err := s.rowSecurity.filter(ctx, table)
+	return err
+}
+
+// checkFreeForm rejects free-form SQL from callers restricted by row
+// filters, since their filters cannot be enforced on arbitrary statements
+func (s *MCPServerWithDB) checkFreeForm(ctx context.Context) error {
+	if s.rowSecurity != nil && s.rowSecurity.restricted(ctx) {
+		return fmt.Errorf("%w: free-form SQL is not allowed for callers restricted by row filters", ErrRowSecurity)
+	}
+	return nil
+}
+
+// redactSamples drops the sample rows and values of a table whose rows the
+// caller may not all see
+func (s *MCPServerWithDB) redactSamples(ctx context.Context, metadata *connector.TableMetadata) *connector.TableMetadata {
+	if s.rowSecurity == nil || !s.rowSecurity.restricted(ctx) || len(s.rowSecurity.filters[strings.ToUpper(metadata.Name)]) == 0 {
+		return metadata
+	}
+	redacted := *metadata
+	redacted.SampleData = nil
+	redacted.Columns = make([]connector.Column, len(metadata.Columns))
+	for i, col := range metadata.Columns {
+		col.Sample = nil
+		redacted.Columns[i] = col
+	}
+	return &redacted
+}
+
+type claimsKey struct{}
+
+// withClaims returns a context carrying the caller's claims
+func withClaims(ctx context.Context, claims map[string]interface{}) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// claimsFromContext returns the claims set with withClaims
+func claimsFromContext(ctx context.Context) map[string]interface{} {
+	claims, _ := ctx.Value(claimsKey{}).(map[string]interface{})
+	return claims
+}
+
+// callerRole returns the role claim of the caller, empty when anonymous
+func callerRole(ctx context.Context) string {
+	role, _ := claimsFromContext(ctx)["role"].(string)
+	return role
+}
+
+// callerClaims collects the claims of a request from the client
+// certificate, the gateway's JWT, the OAuth2 claims of the authentication
+// in front of the server and a bearer token verified with the configured
+// secret, in increasing order of precedence. Tenant API keys carry no
+// claims.
+func (rs *rowSecurity) callerClaims(r *http.Request, gatewayClaims *jwt.Claims) (map[string]interface{}, error) {
+	claims := make(map[string]interface{})
+	if id := certIdentityFromContext(r.Context()); id != nil {
+		claims["username"] = id.Principal
+		claims["cert_subject"] = id.Subject
+		if id.Role != "" {
+			claims["role"] = id.Role
+		}
+	}
+	if gatewayClaims != nil {
+		claims["user_id"] = float64(gatewayClaims.UserID)
+		claims["username"] = gatewayClaims.Username
+		claims["role"] = gatewayClaims.Role
+	}
+	if oc, ok := oauth2.GetClaims(r.Context()); ok {
+		for k, v := range oc {
+			claims[k] = v
+		}
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if rs.secret == nil || token == "" || token == r.Header.Get("Authorization") || strings.HasPrefix(token, apiKeyPrefix) {
+		return claims, nil
+	}
+	parsed, err := gojwt.Parse(token, func(*gojwt.Token) (interface{}, error) {
+		return rs.secret, nil
+	}, gojwt.WithValidMethods([]string{"HS256"}))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range parsed.Claims.(gojwt.MapClaims) {
+		claims[k] = v
+	}
+	return claims, nil
+}
+
+// rowSecurityMiddleware reads the claims of REST and MCP callers for their
+// row filters and role quotas, rejecting invalid bearer tokens
+func (s *MCPServerWithDB) rowSecurityMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rs := s.rowSecurity
+		if rs == nil && (s.quotas != nil && len(s.quotas.roles) > 0 || s.connections != nil && s.connections.restricted()) {
+			// Without row security the roles come from the gateway's
+			// authentication and client certificates only
+			rs = &rowSecurity{}
+		}
+		if rs == nil {
+			c.Next()
+			return
+		}
+		var gatewayClaims *jwt.Claims
+		if v, ok := c.Get("claims"); ok {
+			gatewayClaims, _ = v.(*jwt.Claims)
+		}
+		claims, err := rs.callerClaims(c.Request, gatewayClaims)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Invalid token: %v", err)})
+			return
+		}
+		c.Request = c.Request.WithContext(withClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestNewRowSecurity(t *testing.T) {
+	for _, f := range []RowFilterConfig{
+		{Table: "ORDERS"},
+		{Table: "ORDERS", Filter: "TENANT_ID = {claims.tenant-id}"},
+		{Table: "ORDERS", Filter: "TENANT_ID = {claims.tenant}) OR (1 = 1"},
+	} {
+		_, err := newRowSecurity(&RowSecurityConfig{Filters: []RowFilterConfig{f}})
+		assert.Error(t, err, f.Filter)
+	}
+}
+
+func TestRowSecurity(t *testing.T) {
+	rs, err := newRowSecurity(&RowSecurityConfig{
+		Filters: []RowFilterConfig{
+			{Table: "orders", Filter: "TENANT_ID = {claims.tenant}"},
+			{Table: "ORDERS", Roles: []string{"agent"}, Filter: "STATUS <> 'draft'"},
+			{Table: "INVOICES", Roles: []string{"finance"}, Filter: "REGION = {claims.region}"},
+		},
+		BypassRoles: []string{"admin"},
+	})
+	require.NoError(t, err)
+	as := func(claims map[string]interface{}) context.Context {
+		return withClaims(context.Background(), claims)
+	}
+
+	cond, params, err := rs.filter(as(map[string]interface{}{"role": "agent", "tenant": "acme"}), "ORDERS")
+	require.NoError(t, err)
+	assert.Equal(t, "(TENANT_ID = :rls_tenant) AND (STATUS <> 'draft')", cond)
+	assert.Equal(t, map[string]interface{}{"rls_tenant": "acme"}, params)
+
+	cond, _, err = rs.filter(as(map[string]interface{}{"role": "admin"}), "ORDERS")
+	require.NoError(t, err)
+	assert.Empty(t, cond)
+	cond, _, err = rs.filter(as(nil), "CUSTOMERS")
+	require.NoError(t, err)
+	assert.Empty(t, cond)
+
+	// Missing claims and tables no filter applies to are denied
+	_, _, err = rs.filter(as(map[string]interface{}{"role": "agent"}), "ORDERS")
+	assert.EqualError(t, err, "denied by row security: claim tenant is required to access ORDERS")
+	_, _, err = rs.filter(as(map[string]interface{}{"role": "agent", "tenant": "acme"}), "INVOICES")
+	assert.EqualError(t, err, `denied by row security: INVOICES is not accessible to role "agent"`)
+	_, _, err = rs.filter(as(map[string]interface{}{"tenant": []interface{}{"acme"}}), "ORDERS")
+	assert.ErrorIs(t, err, ErrRowSecurity)
+}
+
+func TestRowSecurityEndpoints(t *testing.T) {
+	conn := &paramsConnector{}
+	cfg := &RowSecurityConfig{
+		Filters:   []RowFilterConfig{{Table: "ORDERS", Filter: "TENANT_ID = {claims.tenant}"}},
+		JWTSecret: "secret",
+	}
+	rs, err := newRowSecurity(cfg)
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales", RowSecurity: cfg}, DBConn: conn, rowSecurity: rs}
+
+	d := connector.ANSIDialect{}
+	routes := newRouteManager("/api", s.generatedEndpointHandler)
+	_, err = routes.Apply([]connector.APIEndpoint{
+		{Table: "ORDERS", Method: http.MethodGet, Path: "/ORDERS", Query: connector.SelectPageQuery(d, "ORDERS")},
+		{Table: "ORDERS", Method: http.MethodDelete, Path: "/ORDERS/{ID}", Query: connector.DeleteQuery(d, "ORDERS", "ID"), Parameters: map[string]interface{}{"ID": "ID"}},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(s.rowSecurityMiddleware())
+	router.POST("/query", func(c *gin.Context) {
+		if err := s.checkFreeForm(c.Request.Context()); err != nil {
+			c.JSON(queryErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	router.NoRoute(routes.ServeHTTP)
+
+	token, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.MapClaims{"tenant": "acme", "role": "agent"}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	call := func(method, target, bearer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader("{}"))
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call(http.MethodGet, "/api/ORDERS?limit=5&offset=0", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `SELECT * FROM "ORDERS" WHERE (TENANT_ID = :rls_tenant) LIMIT :limit OFFSET :offset`, conn.query)
+	assert.Equal(t, map[string]interface{}{"limit": "5", "offset": "0", "rls_tenant": "acme"}, conn.params)
+
+	// Claims cannot be overridden by request parameters
+	w = call(http.MethodDelete, "/api/ORDERS/7?rls_tenant=other", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `DELETE FROM "ORDERS" WHERE ("ID" = :ID) AND (TENANT_ID = :rls_tenant)`, conn.query)
+	assert.Equal(t, "acme", conn.params["rls_tenant"])
+
+	conn.query = ""
+	assert.Equal(t, http.StatusForbidden, call(http.MethodGet, "/api/ORDERS", "").Code)
+	assert.Equal(t, http.StatusForbidden, call(http.MethodPost, "/query", token).Code)
+	assert.Equal(t, http.StatusUnauthorized, call(http.MethodGet, "/api/ORDERS", token+"x").Code)
+	assert.Empty(t, conn.query)
+
+	// Samples of filtered tables are hidden from restricted callers
+	metadata := &connector.TableMetadata{
+		Name:       "ORDERS",
+		Columns:    []connector.Column{{Name: "TENANT_ID", Sample: "globex"}},
+		SampleData: []map[string]interface{}{{"TENANT_ID": "globex"}},
+	}
+	redacted := s.redactSamples(withClaims(context.Background(), map[string]interface{}{"tenant": "acme"}), metadata)
+	assert.Empty(t, redacted.SampleData)
+	assert.Nil(t, redacted.Columns[0].Sample)
+	assert.Equal(t, "globex", metadata.Columns[0].Sample)
+}
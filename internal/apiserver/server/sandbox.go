@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// sandboxHeader names the clone a sandboxed write was applied to
+const sandboxHeader = "X-Sandbox-Table"
+
+// defaultSandboxSuffix is appended to a table's name to name its clone
+const defaultSandboxSuffix = "_SANDBOX"
+
+// ErrSandboxUnsupported is returned when the database cannot clone tables
+var ErrSandboxUnsupported = errors.New("database cannot clone tables")
+
+// SandboxConfig lets tables be sandboxed on demand: the table is cloned
+// without copying its data and the generated write endpoints of the table
+// write to the clone until the sandbox is dropped, so agents can try
+// inserts, updates and deletes without touching production data. Reads
+// are still served from the table.
+type SandboxConfig struct {
+	// Suffix is appended to a table's name to name its clone (default:
+	// _SANDBOX)
+	Suffix string `json:"suffix,omitempty"`
+
+	// Tables limits the tables that can be sandboxed; empty allows all
+	Tables []string `json:"tables,omitempty"`
+}
+
+// Sandbox is a table whose writes go to its clone
+type Sandbox struct {
+	Table     string    `json:"table"`
+	Clone     string    `json:"clone"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// sandboxes tracks the sandboxed tables by upper-cased name
+type sandboxes struct {
+	suffix string
+	tables map[string]bool
+
+	mu     sync.RWMutex
+	clones map[string]*Sandbox
+}
+
+// newSandboxes validates a sandbox configuration; nil disables sandboxes
+func newSandboxes(cfg *SandboxConfig) (*sandboxes, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	sb := &sandboxes{
+		suffix: cfg.Suffix,
+		tables: make(map[string]bool, len(cfg.Tables)),
+		clones: make(map[string]*Sandbox),
+	}
+	if sb.suffix == "" {
+		sb.suffix = defaultSandboxSuffix
+	}
+	for _, table := range cfg.Tables {
+		sb.tables[strings.ToUpper(table)] = true
+	}
+	return sb, nil
+}
+
+// allowed reports whether a table can be sandboxed
+func (sb *sandboxes) allowed(table string) bool {
+	return len(sb.tables) == 0 || sb.tables[strings.ToUpper(table)]
+}
+
+// lookup returns the sandbox of a table
+func (sb *sandboxes) lookup(table string) (*Sandbox, bool) {
+	if sb == nil {
+		return nil, false
+	}
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	sandbox, ok := sb.clones[strings.ToUpper(table)]
+	return sandbox, ok
+}
+
+// list returns the sandboxes ordered by table
+func (sb *sandboxes) list() []*Sandbox {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+	list := make([]*Sandbox, 0, len(sb.clones))
+	for _, sandbox := range sb.clones {
+		list = append(list, sandbox)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Table < list[j].Table })
+	return list
+}
+
+// createSandbox clones a table, replacing the clone of an existing sandbox
+// so that it starts over from the table's current data
+func (s *MCPServerWithDB) createSandbox(ctx context.Context, table string) (*Sandbox, error) {
+	d := connector.DialectOf(s.DBConn)
+	cloner, ok := d.(connector.Cloner)
+	if !ok {
+		return nil, ErrSandboxUnsupported
+	}
+	sandbox := &Sandbox{Table: table, Clone: table + s.sandboxes.suffix, CreatedAt: time.Now().UTC()}
+	if _, err := s.executeQuery(ctx, "sandbox", cloner.CloneTable(d.Table(sandbox.Clone), d.Table(table)), nil); err != nil {
+		return nil, err
+	}
+
+	s.sandboxes.mu.Lock()
+	s.sandboxes.clones[strings.ToUpper(table)] = sandbox
+	s.sandboxes.mu.Unlock()
+	return sandbox, nil
+}
+
+// dropSandbox drops a table's clone and sends its writes back to the table
+func (s *MCPServerWithDB) dropSandbox(ctx context.Context, table string) error {
+	sandbox, ok := s.sandboxes.lookup(table)
+	if !ok {
+		return fmt.Errorf("table %s is not sandboxed", table)
+	}
+	d := connector.DialectOf(s.DBConn)
+	if _, err := s.executeQuery(ctx, "sandbox", "DROP TABLE IF EXISTS "+d.Table(sandbox.Clone), nil); err != nil {
+		return err
+	}
+
+	s.sandboxes.mu.Lock()
+	delete(s.sandboxes.clones, strings.ToUpper(table))
+	s.sandboxes.mu.Unlock()
+	return nil
+}
+
+// sandboxQuery points the query of a generated write endpoint at the clone
+// of its table when the table is sandboxed, returning the clone's name
+func (s *MCPServerWithDB) sandboxQuery(endpoint connector.APIEndpoint, query string) (string, string) {
+	if endpoint.Method == http.MethodGet || endpoint.Table == "" {
+		return query, ""
+	}
+	sandbox, ok := s.sandboxes.lookup(endpoint.Table)
+	if !ok {
+		return query, ""
+	}
+	d := connector.DialectOf(s.DBConn)
+	return strings.ReplaceAll(query, d.Table(endpoint.Table), d.Table(sandbox.Clone)), sandbox.Clone
+}
+
+// setupSandboxRoutes configures the routes creating and dropping sandboxes
+func (s *MCPServerWithDB) setupSandboxRoutes(router *gin.RouterGroup) {
+	if s.sandboxes == nil {
+		return
+	}
+
+	router.GET("/admin/sandboxes", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.sandboxes.list())
+	})
+
+	router.POST("/admin/sandboxes/:table", func(c *gin.Context) {
+		table := c.Param("table")
+		if !s.sandboxes.allowed(table) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Table %s cannot be sandboxed", table)})
+			return
+		}
+		sandbox, err := s.createSandbox(c.Request.Context(), table)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to create sandbox", err)
+			return
+		}
+		s.recordSandboxChange(c, "sandbox_create", table)
+		c.JSON(http.StatusCreated, sandbox)
+	})
+
+	router.DELETE("/admin/sandboxes/:table", func(c *gin.Context) {
+		table := c.Param("table")
+		if _, ok := s.sandboxes.lookup(table); !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Table %s is not sandboxed", table)})
+			return
+		}
+		if err := s.dropSandbox(c.Request.Context(), table); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to drop sandbox", err)
+			return
+		}
+		s.recordSandboxChange(c, "sandbox_drop", table)
+		c.Status(http.StatusNoContent)
+	})
+}
+
+// recordSandboxChange audits the creation or removal of a sandbox
+func (s *MCPServerWithDB) recordSandboxChange(c *gin.Context, action, table string) {
+	if s.Audit == nil {
+		return
+	}
+	_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+		Time:      time.Now().UTC(),
+		Action:    action,
+		Principal: principalFromContext(c),
+		Resource:  table,
+	})
+}
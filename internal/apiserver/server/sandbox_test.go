@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestSandbox(t *testing.T) {
+	conn := &snowflakeParamsConnector{}
+	sandboxes, err := newSandboxes(&SandboxConfig{Tables: []string{"orders"}})
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, sandboxes: sandboxes}
+
+	d := conn.Dialect()
+	routes := newRouteManager("/api", s.generatedEndpointHandler)
+	_, err = routes.Apply([]connector.APIEndpoint{
+		{Table: "ORDERS", Operation: connector.OperationGet, Method: http.MethodGet, Path: "/ORDERS/{ID}",
+			Query: connector.SelectByKeyQuery(d, "ORDERS", "ID")},
+		{Table: "ORDERS", Operation: connector.OperationDelete, Method: http.MethodDelete, Path: "/ORDERS/{ID}",
+			Query: connector.DeleteQuery(d, "ORDERS", "ID")},
+	})
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupSandboxRoutes(router.Group("/api"))
+	router.NoRoute(routes.ServeHTTP)
+	request := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/api/admin/sandboxes/CUSTOMERS").Code)
+	w := request(http.MethodPost, "/api/admin/sandboxes/ORDERS")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, `CREATE OR REPLACE TABLE "DB"."PUBLIC"."ORDERS_SANDBOX" CLONE "DB"."PUBLIC"."ORDERS"`, conn.query)
+	var list []Sandbox
+	require.NoError(t, json.Unmarshal(request(http.MethodGet, "/api/admin/sandboxes").Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "ORDERS_SANDBOX", list[0].Clone)
+
+	// Writes go to the clone while reads stay on the table
+	w = request(http.MethodDelete, "/api/ORDERS/1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ORDERS_SANDBOX", w.Header().Get(sandboxHeader))
+	assert.Equal(t, `DELETE FROM "DB"."PUBLIC"."ORDERS_SANDBOX" WHERE "ID" = :ID`, conn.query)
+	request(http.MethodGet, "/api/ORDERS/1")
+	assert.Equal(t, `SELECT * FROM "DB"."PUBLIC"."ORDERS" WHERE "ID" = :ID`, conn.query)
+
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/api/admin/sandboxes/ORDERS").Code)
+	assert.Equal(t, `DROP TABLE IF EXISTS "DB"."PUBLIC"."ORDERS_SANDBOX"`, conn.query)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/api/admin/sandboxes/ORDERS").Code)
+	w = request(http.MethodDelete, "/api/ORDERS/1")
+	assert.Empty(t, w.Header().Get(sandboxHeader))
+	assert.Equal(t, `DELETE FROM "DB"."PUBLIC"."ORDERS" WHERE "ID" = :ID`, conn.query)
+
+	// Databases without zero-copy clones cannot sandbox
+	s.DBConn = &paramsConnector{}
+	assert.Equal(t, http.StatusNotImplemented, request(http.MethodPost, "/api/admin/sandboxes/ORDERS").Code)
+}
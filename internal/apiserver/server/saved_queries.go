@@ -0,0 +1,563 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// Saved query parameter types
+const (
+	ParamString  = "string"
+	ParamInteger = "integer"
+	ParamNumber  = "number"
+	ParamBoolean = "boolean"
+)
+
+var (
+	// ErrSavedQueryNotFound is returned for unknown saved queries
+	ErrSavedQueryNotFound = errors.New("saved query not found")
+
+	// ErrSavedQueryExists is returned when creating a saved query whose name is taken
+	ErrSavedQueryExists = errors.New("saved query already exists")
+
+	// ErrInvalidSavedQuery is returned for invalid definitions and arguments
+	ErrInvalidSavedQuery = errors.New("invalid saved query")
+)
+
+// namedParamPattern matches :name placeholders, skipping :: casts
+var namedParamPattern = regexp.MustCompile(`(?:^|[^:]):([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// SavedQuery is a curated, parameterized read-only query. Each saved query
+// is served as a REST endpoint and an MCP tool named after it.
+type SavedQuery struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	SQL         string                `json:"sql"`
+	Parameters  []SavedQueryParameter `json:"parameters,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+// SavedQueryParameter declares a named parameter of a saved query
+type SavedQueryParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"` // string (default), integer, number or boolean
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+
+	// Default is used when an optional parameter is omitted
+	Default interface{} `json:"default,omitempty"`
+
+	// Enum restricts the parameter to the listed values
+	Enum []interface{} `json:"enum,omitempty"`
+}
+
+// savedQueryRow is the persisted form of a SavedQuery
+type savedQueryRow struct {
+	Server      string `gorm:"primaryKey"`
+	Name        string `gorm:"primaryKey"`
+	Description string
+	SQL         string `gorm:"column:sql"`
+	Parameters  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName overrides the table name used by savedQueryRow
+func (savedQueryRow) TableName() string {
+	return "saved_queries"
+}
+
+// savedQueries holds a server's saved queries, writing through to the state
+// store when the server has one and keeping them in memory otherwise
+type savedQueries struct {
+	server string
+	db     *gorm.DB
+
+	mu      sync.RWMutex
+	queries map[string]*SavedQuery
+}
+
+// newSavedQueries creates the saved query store of a server. db may be nil.
+func newSavedQueries(server string, db *gorm.DB) *savedQueries {
+	return &savedQueries{
+		server:  server,
+		db:      db,
+		queries: make(map[string]*SavedQuery),
+	}
+}
+
+// load reads the persisted saved queries
+func (q *savedQueries) load(ctx context.Context) error {
+	if q.db == nil {
+		return nil
+	}
+	var rows []savedQueryRow
+	if err := q.db.WithContext(ctx).Where("server = ?", q.server).Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to load saved queries: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, row := range rows {
+		query := &SavedQuery{
+			Name:        row.Name,
+			Description: row.Description,
+			SQL:         row.SQL,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		}
+		if row.Parameters != "" {
+			if err := json.Unmarshal([]byte(row.Parameters), &query.Parameters); err != nil {
+				return fmt.Errorf("failed to decode parameters of saved query %s: %w", row.Name, err)
+			}
+		}
+		q.queries[row.Name] = query
+	}
+	return nil
+}
+
+// List returns the saved queries ordered by name
+func (q *savedQueries) List() []*SavedQuery {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	list := make([]*SavedQuery, 0, len(q.queries))
+	for _, query := range q.queries {
+		list = append(list, query)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Get returns a saved query by name
+func (q *savedQueries) Get(name string) (*SavedQuery, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	query, ok := q.queries[name]
+	if !ok {
+		return nil, ErrSavedQueryNotFound
+	}
+	return query, nil
+}
+
+// Create stores a new saved query
+func (q *savedQueries) Create(ctx context.Context, query *SavedQuery) error {
+	if err := validateSavedQuery(query); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.queries[query.Name]; ok {
+		return ErrSavedQueryExists
+	}
+	query.CreatedAt = time.Now().UTC()
+	query.UpdatedAt = query.CreatedAt
+	if err := q.persist(ctx, query, true); err != nil {
+		return err
+	}
+	q.queries[query.Name] = query
+	return nil
+}
+
+// Update replaces a saved query's definition
+func (q *savedQueries) Update(ctx context.Context, name string, query *SavedQuery) error {
+	query.Name = name
+	if err := validateSavedQuery(query); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	existing, ok := q.queries[name]
+	if !ok {
+		return ErrSavedQueryNotFound
+	}
+	query.CreatedAt = existing.CreatedAt
+	query.UpdatedAt = time.Now().UTC()
+	if err := q.persist(ctx, query, false); err != nil {
+		return err
+	}
+	q.queries[name] = query
+	return nil
+}
+
+// Delete removes a saved query
+func (q *savedQueries) Delete(ctx context.Context, name string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.queries[name]; !ok {
+		return ErrSavedQueryNotFound
+	}
+	if q.db != nil {
+		err := q.db.WithContext(ctx).Where("server = ? AND name = ?", q.server, name).Delete(&savedQueryRow{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete saved query: %w", err)
+		}
+	}
+	delete(q.queries, name)
+	return nil
+}
+
+// persist writes a saved query to the state store
+func (q *savedQueries) persist(ctx context.Context, query *SavedQuery, create bool) error {
+	if q.db == nil {
+		return nil
+	}
+	params, err := json.Marshal(query.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to encode parameters: %w", err)
+	}
+	row := &savedQueryRow{
+		Server:      q.server,
+		Name:        query.Name,
+		Description: query.Description,
+		SQL:         query.SQL,
+		Parameters:  string(params),
+		CreatedAt:   query.CreatedAt,
+		UpdatedAt:   query.UpdatedAt,
+	}
+	db := q.db.WithContext(ctx)
+	if create {
+		err = db.Create(row).Error
+	} else {
+		err = db.Save(row).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store saved query: %w", err)
+	}
+	return nil
+}
+
+// validateSavedQuery checks a definition: the name must be a valid tool
+// name, the SQL read-only and every placeholder a declared parameter
+func validateSavedQuery(query *SavedQuery) error {
+	if !toolNamePattern.MatchString(query.Name) {
+		return fmt.Errorf("%w: name must match %s", ErrInvalidSavedQuery, toolNamePattern)
+	}
+	if !isReadOnlySQL(query.SQL) {
+		return fmt.Errorf("%w: sql must be a single read-only statement", ErrInvalidSavedQuery)
+	}
+
+	declared := make(map[string]bool, len(query.Parameters))
+	for i, p := range query.Parameters {
+		if p.Name == "" || declared[p.Name] {
+			return fmt.Errorf("%w: parameter names must be unique and non-empty", ErrInvalidSavedQuery)
+		}
+		declared[p.Name] = true
+		switch p.Type {
+		case "":
+			query.Parameters[i].Type = ParamString
+		case ParamString, ParamInteger, ParamNumber, ParamBoolean:
+		default:
+			return fmt.Errorf("%w: parameter %s has unsupported type %s", ErrInvalidSavedQuery, p.Name, p.Type)
+		}
+		if p.Default != nil {
+			if _, err := coerceParam(query.Parameters[i], p.Default); err != nil {
+				return fmt.Errorf("%w: invalid default: %v", ErrInvalidSavedQuery, err)
+			}
+		}
+	}
+	for _, m := range namedParamPattern.FindAllStringSubmatch(query.SQL, -1) {
+		if !declared[m[1]] {
+			return fmt.Errorf("%w: placeholder :%s is not a declared parameter", ErrInvalidSavedQuery, m[1])
+		}
+	}
+	return nil
+}
+
+// bindArgs validates arguments against the declared parameters, applying
+// defaults and converting values to the declared types
+func (query *SavedQuery) bindArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(query.Parameters))
+	for _, p := range query.Parameters {
+		value, ok := args[p.Name]
+		if !ok || value == nil {
+			if p.Required {
+				return nil, fmt.Errorf("%w: missing required parameter %s", ErrInvalidSavedQuery, p.Name)
+			}
+			value = p.Default
+		}
+		if value != nil {
+			v, err := coerceParam(p, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidSavedQuery, err)
+			}
+			value = v
+		}
+		params[p.Name] = value
+	}
+	for name := range args {
+		if _, ok := params[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown parameter %s", ErrInvalidSavedQuery, name)
+		}
+	}
+	return params, nil
+}
+
+// coerceParam converts a JSON or query string value to the parameter's type
+func coerceParam(p SavedQueryParameter, value interface{}) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch p.Type {
+	case ParamInteger:
+		switch x := value.(type) {
+		case float64:
+			if x != float64(int64(x)) {
+				return nil, fmt.Errorf("parameter %s must be an integer", p.Name)
+			}
+			v = int64(x)
+		case string:
+			v, err = strconv.ParseInt(x, 10, 64)
+		default:
+			err = fmt.Errorf("unexpected %T", value)
+		}
+	case ParamNumber:
+		switch x := value.(type) {
+		case float64:
+			v = x
+		case string:
+			v, err = strconv.ParseFloat(x, 64)
+		default:
+			err = fmt.Errorf("unexpected %T", value)
+		}
+	case ParamBoolean:
+		switch x := value.(type) {
+		case bool:
+			v = x
+		case string:
+			v, err = strconv.ParseBool(x)
+		default:
+			err = fmt.Errorf("unexpected %T", value)
+		}
+	default:
+		s, ok := value.(string)
+		if !ok {
+			err = fmt.Errorf("unexpected %T", value)
+		}
+		v = s
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s must be of type %s", p.Name, p.Type)
+	}
+
+	if len(p.Enum) > 0 {
+		for _, allowed := range p.Enum {
+			if fmt.Sprint(allowed) == fmt.Sprint(v) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("parameter %s must be one of %v", p.Name, p.Enum)
+	}
+	return v, nil
+}
+
+// inputSchema describes the parameters as the JSON Schema of an MCP tool
+func (query *SavedQuery) inputSchema() mcp.ToolInputSchema {
+	schema := mcp.ToolInputSchema{Type: "object", Properties: map[string]any{}}
+	for _, p := range query.Parameters {
+		prop := map[string]any{"type": p.Type}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		if p.Default != nil {
+			prop["default"] = p.Default
+		}
+		if len(p.Enum) > 0 {
+			prop["enum"] = p.Enum
+		}
+		schema.Properties[p.Name] = prop
+		if p.Required {
+			schema.Required = append(schema.Required, p.Name)
+		}
+	}
+	return schema
+}
+
+// bindSavedQuery looks up a saved query and validates its arguments
+func (s *MCPServerWithDB) bindSavedQuery(name string, args map[string]interface{}) (*SavedQuery, map[string]interface{}, error) {
+	query, err := s.saved.Get(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	params, err := query.bindArgs(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return query, params, nil
+}
+
+// savedQueryTools exposes every saved query as an MCP tool
+func (s *MCPServerWithDB) savedQueryTools() []mcpTool {
+	if s.saved == nil {
+		return nil
+	}
+	queries := s.saved.List()
+	tools := make([]mcpTool, 0, len(queries))
+	for _, query := range queries {
+		name := query.Name
+		tools = append(tools, mcpTool{
+			Schema: mcp.ToolSchema{
+				Name:        name,
+				Description: query.Description,
+				InputSchema: query.inputSchema(),
+			},
+			Handler: func(ctx context.Context, sess *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				query, params, err := s.bindSavedQuery(name, args)
+				if err != nil {
+					return nil, err
+				}
+				if err := s.checkFreeForm(ctx); err != nil {
+					return nil, err
+				}
+				if err := s.checkQueryPolicy(ctx, "saved", query.SQL, params); err != nil {
+					return nil, err
+				}
+				if staged, err := s.stageAction(ctx, name, query.SQL, params); staged != nil || err != nil {
+					return staged, err
+				}
+				result, err := s.executeTracked(ctx, query.SQL, params)
+				if err != nil {
+					return nil, err
+				}
+				return s.rowsToolResult(name, sess, result)
+			},
+		})
+	}
+	return tools
+}
+
+// setupSavedQueryRoutes configures the saved query management routes and
+// the endpoint running each saved query
+func (s *MCPServerWithDB) setupSavedQueryRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/saved-queries", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.saved.List())
+	})
+
+	router.GET("/admin/saved-queries/:name", func(c *gin.Context) {
+		query, err := s.saved.Get(c.Param("name"))
+		if err != nil {
+			c.JSON(savedQueryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to get saved query: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, query)
+	})
+
+	router.POST("/admin/saved-queries", func(c *gin.Context) {
+		var query SavedQuery
+		if err := c.ShouldBindJSON(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if err := s.saved.Create(c.Request.Context(), &query); err != nil {
+			c.JSON(savedQueryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to create saved query: %v", err)})
+			return
+		}
+		s.recordSavedQueryChange(c, "saved_query_create", query.Name)
+		c.JSON(http.StatusCreated, query)
+	})
+
+	router.PUT("/admin/saved-queries/:name", func(c *gin.Context) {
+		var query SavedQuery
+		if err := c.ShouldBindJSON(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+		if err := s.saved.Update(c.Request.Context(), c.Param("name"), &query); err != nil {
+			c.JSON(savedQueryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to update saved query: %v", err)})
+			return
+		}
+		s.recordSavedQueryChange(c, "saved_query_update", query.Name)
+		c.JSON(http.StatusOK, query)
+	})
+
+	router.DELETE("/admin/saved-queries/:name", func(c *gin.Context) {
+		if err := s.saved.Delete(c.Request.Context(), c.Param("name")); err != nil {
+			c.JSON(savedQueryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to delete saved query: %v", err)})
+			return
+		}
+		s.recordSavedQueryChange(c, "saved_query_delete", c.Param("name"))
+		c.Status(http.StatusNoContent)
+	})
+
+	// Saved queries take their arguments from the query string or a JSON body
+	run := func(c *gin.Context) {
+		args := make(map[string]interface{})
+		for key, values := range c.Request.URL.Query() {
+			if len(values) > 0 {
+				args[key] = values[0]
+			}
+		}
+		if c.Request.Method == http.MethodPost && c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&args); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+				return
+			}
+		}
+
+		query, params, err := s.bindSavedQuery(c.Param("name"), args)
+		if err != nil {
+			c.JSON(savedQueryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to run saved query: %v", err)})
+			return
+		}
+		if err := s.checkFreeForm(c.Request.Context()); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to run saved query", err)
+			return
+		}
+		if err := s.checkQueryPolicy(c.Request.Context(), "saved", query.SQL, params); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to run saved query", err)
+			return
+		}
+		result, err := s.executeOrdered(c.Request.Context(), "saved", query.SQL, params)
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to run saved query", err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+	router.GET("/saved/:name", run)
+	router.POST("/saved/:name", run)
+}
+
+// recordSavedQueryChange audits a change to the saved queries
+func (s *MCPServerWithDB) recordSavedQueryChange(c *gin.Context, action, name string) {
+	if s.Audit == nil {
+		return
+	}
+	_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+		Time:      time.Now().UTC(),
+		Action:    action,
+		Principal: principalFromContext(c),
+		Resource:  name,
+	})
+}
+
+// savedQueryErrorStatus maps saved query errors to HTTP status codes
+func savedQueryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrSavedQueryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrSavedQueryExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidSavedQuery):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
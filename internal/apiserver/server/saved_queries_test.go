@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+)
+
+// paramsConnector records the parameters of the last query
+type paramsConnector struct {
+	rowsConnector
+	query  string
+	params map[string]interface{}
+}
+
+func (c *paramsConnector) ExecuteQuery(_ context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.query, c.params = query, params
+	return c.rows, nil
+}
+
+func TestSavedQueries(t *testing.T) {
+	store, err := state.OpenAndMigrate(&state.Config{DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	defer store.Close()
+
+	saved := newSavedQueries("sales", store.DB)
+	query := &SavedQuery{
+		Name:        "orders_by_region",
+		Description: "Orders of a region",
+		SQL:         "SELECT * FROM ORDERS WHERE REGION = :region AND TOTAL::number > :min_total LIMIT :limit",
+		Parameters: []SavedQueryParameter{
+			{Name: "region", Required: true, Enum: []interface{}{"EU", "US"}},
+			{Name: "min_total", Type: ParamNumber, Default: 0.0},
+			{Name: "limit", Type: ParamInteger, Default: 10.0},
+		},
+	}
+	require.NoError(t, saved.Create(context.Background(), query))
+	assert.ErrorIs(t, saved.Create(context.Background(), query), ErrSavedQueryExists)
+
+	// A restarted server reloads the saved queries
+	restored := newSavedQueries("sales", store.DB)
+	require.NoError(t, restored.load(context.Background()))
+	got, err := restored.Get("orders_by_region")
+	require.NoError(t, err)
+	assert.Equal(t, ParamString, got.Parameters[0].Type)
+	assert.Empty(t, newSavedQueries("other", store.DB).List())
+
+	conn := &paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": 1.0}}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, saved: restored}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupSavedQueryRoutes(router.Group(""))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saved/orders_by_region?region=EU&limit=5", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"region": "EU", "min_total": 0.0, "limit": int64(5)}, conn.params)
+
+	for _, target := range []string{
+		"/saved/orders_by_region",
+		"/saved/orders_by_region?region=APAC",
+		"/saved/orders_by_region?region=EU&limit=ten",
+		"/saved/orders_by_region?region=EU&country=FR",
+	} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saved/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Each saved query is an MCP tool with a schema built from its parameters
+	tools := s.savedQueryTools()
+	require.Len(t, tools, 1)
+	assert.Equal(t, []string{"region"}, tools[0].Schema.InputSchema.Required)
+	_, err = tools[0].Handler(context.Background(), &mcpSession{}, map[string]interface{}{"region": "US", "limit": 2.5})
+	assert.ErrorIs(t, err, ErrInvalidSavedQuery)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/admin/saved-queries/orders_by_region", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	reloaded := newSavedQueries("sales", store.DB)
+	require.NoError(t, reloaded.load(context.Background()))
+	assert.Empty(t, reloaded.List())
+}
+
+func TestValidateSavedQuery(t *testing.T) {
+	for _, query := range []*SavedQuery{
+		{Name: "bad name", SQL: "SELECT 1"},
+		{Name: "purge", SQL: "DELETE FROM ORDERS"},
+		{Name: "orders", SQL: "SELECT * FROM ORDERS WHERE ID = :id"},
+		{Name: "orders", SQL: "SELECT 1", Parameters: []SavedQueryParameter{{Name: "id", Type: "date"}}},
+		{Name: "orders", SQL: "SELECT 1", Parameters: []SavedQueryParameter{{Name: "id", Type: ParamInteger, Default: "one"}}},
+	} {
+		assert.ErrorIs(t, validateSavedQuery(query), ErrInvalidSavedQuery, query.SQL)
+	}
+
+	body := `{"name":"orders","sql":"SELECT 1","parameters":[{"name":"id"},{"name":"id"}]}`
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, saved: newSavedQueries("sales", nil)}
+	router := gin.New()
+	s.setupSavedQueryRoutes(router.Group(""))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/saved-queries", strings.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,443 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/cron"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/s3"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultScheduledMaxRows = 1000
+	scheduledRunTimeout     = 10 * time.Minute
+
+	// scheduledURIScheme prefixes the URIs of scheduled query resources
+	scheduledURIScheme = "scheduled://"
+)
+
+var (
+	// ErrScheduledNotFound is returned for unknown scheduled queries
+	ErrScheduledNotFound = errors.New("scheduled query not found")
+
+	// ErrScheduledRunning is returned when a query is still running
+	ErrScheduledRunning = errors.New("scheduled query is already running")
+)
+
+// ScheduledQueryConfig defines a named query the gateway runs on a cron
+// schedule. The latest result is kept and served over REST and MCP; every
+// result can also be pushed to a webhook or an S3 bucket.
+type ScheduledQueryConfig struct {
+	Name string `json:"name"`
+
+	// Schedule is a cron expression evaluated in UTC, e.g. "0 6 * * 1-5",
+	// "@hourly" or "@every 15m"
+	Schedule string                 `json:"schedule"`
+	Query    string                 `json:"query"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+
+	// MaxRows bounds the rows kept with the latest result (default: 1000).
+	// Webhooks and S3 receive every row.
+	MaxRows int `json:"max_rows,omitempty"`
+
+	// WebhookURL receives every result as a JSON POST
+	WebhookURL string            `json:"webhook_url,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+
+	// S3 stores every result as <prefix><name>/<time>.json
+	S3 *s3.Config `json:"s3,omitempty"`
+}
+
+// ScheduledResult is the outcome of a scheduled query run
+type ScheduledResult struct {
+	Name          string                   `json:"name"`
+	RanAt         time.Time                `json:"ran_at"`
+	DurationMs    int64                    `json:"duration_ms"`
+	RowCount      int                      `json:"row_count"`
+	Truncated     bool                     `json:"truncated,omitempty"`
+	Error         string                   `json:"error,omitempty"`
+	DeliveryError string                   `json:"delivery_error,omitempty"`
+	Rows          []map[string]interface{} `json:"rows,omitempty"`
+}
+
+// ScheduledQueryStatus describes a scheduled query and its latest run
+type ScheduledQueryStatus struct {
+	Name     string           `json:"name"`
+	Schedule string           `json:"schedule"`
+	NextRun  time.Time        `json:"next_run,omitempty"`
+	Running  bool             `json:"running"`
+	Latest   *ScheduledResult `json:"latest,omitempty"`
+}
+
+// scheduledResultRow is the persisted latest result of a scheduled query
+type scheduledResultRow struct {
+	Server        string `gorm:"primaryKey"`
+	Name          string `gorm:"primaryKey"`
+	RanAt         time.Time
+	DurationMs    int64
+	RowCount      int
+	Truncated     bool
+	Error         string
+	DeliveryError string
+	Rows          string
+}
+
+// TableName overrides the table name used by scheduledResultRow
+func (scheduledResultRow) TableName() string {
+	return "scheduled_query_results"
+}
+
+// scheduledQuery is a configured query with its parsed schedule
+type scheduledQuery struct {
+	cfg      ScheduledQueryConfig
+	schedule cron.Schedule
+}
+
+// scheduler runs the scheduled queries of a server and keeps their latest
+// results, persisting them when the server has a state store
+type scheduler struct {
+	queries []*scheduledQuery
+	db      *gorm.DB
+
+	mu      sync.Mutex
+	latest  map[string]*ScheduledResult
+	running map[string]bool
+}
+
+// newScheduler validates the scheduled queries. db may be nil.
+func newScheduler(cfgs []ScheduledQueryConfig, db *gorm.DB) (*scheduler, error) {
+	sched := &scheduler{
+		db:      db,
+		latest:  make(map[string]*ScheduledResult),
+		running: make(map[string]bool),
+	}
+	seen := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "" || strings.ContainsAny(cfg.Name, "/?#") {
+			return nil, fmt.Errorf("invalid scheduled query name: %q", cfg.Name)
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("duplicate scheduled query: %s", cfg.Name)
+		}
+		seen[cfg.Name] = true
+		if cfg.Query == "" {
+			return nil, fmt.Errorf("scheduled query %s has no query", cfg.Name)
+		}
+		schedule, err := cron.Parse(cfg.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled query %s: %w", cfg.Name, err)
+		}
+		if cfg.S3 != nil {
+			if err := cfg.S3.Validate(); err != nil {
+				return nil, fmt.Errorf("scheduled query %s: %w", cfg.Name, err)
+			}
+		}
+		sched.queries = append(sched.queries, &scheduledQuery{cfg: cfg, schedule: schedule})
+	}
+	return sched, nil
+}
+
+// query returns a scheduled query by name
+func (sc *scheduler) query(name string) (*scheduledQuery, bool) {
+	for _, q := range sc.queries {
+		if q.cfg.Name == name {
+			return q, true
+		}
+	}
+	return nil, false
+}
+
+// load restores the persisted latest results
+func (sc *scheduler) load(ctx context.Context, server string) error {
+	if sc.db == nil || len(sc.queries) == 0 {
+		return nil
+	}
+	var rows []scheduledResultRow
+	if err := sc.db.WithContext(ctx).Where("server = ?", server).Find(&rows).Error; err != nil {
+		return fmt.Errorf("failed to load scheduled query results: %w", err)
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, row := range rows {
+		if _, ok := sc.query(row.Name); !ok {
+			continue
+		}
+		result := &ScheduledResult{
+			Name:          row.Name,
+			RanAt:         row.RanAt,
+			DurationMs:    row.DurationMs,
+			RowCount:      row.RowCount,
+			Truncated:     row.Truncated,
+			Error:         row.Error,
+			DeliveryError: row.DeliveryError,
+		}
+		if row.Rows != "" {
+			if err := json.Unmarshal([]byte(row.Rows), &result.Rows); err != nil {
+				log.Printf("Warning: Failed to decode stored result of scheduled query %s: %v", row.Name, err)
+			}
+		}
+		sc.latest[row.Name] = result
+	}
+	return nil
+}
+
+// save keeps a result as the latest of its query
+func (sc *scheduler) save(ctx context.Context, server string, result *ScheduledResult) error {
+	sc.mu.Lock()
+	sc.latest[result.Name] = result
+	sc.mu.Unlock()
+
+	if sc.db == nil {
+		return nil
+	}
+	rows, err := json.Marshal(result.Rows)
+	if err != nil {
+		return fmt.Errorf("failed to encode result rows: %w", err)
+	}
+	row := &scheduledResultRow{
+		Server:        server,
+		Name:          result.Name,
+		RanAt:         result.RanAt,
+		DurationMs:    result.DurationMs,
+		RowCount:      result.RowCount,
+		Truncated:     result.Truncated,
+		Error:         result.Error,
+		DeliveryError: result.DeliveryError,
+		Rows:          string(rows),
+	}
+	if err := sc.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to store scheduled query result: %w", err)
+	}
+	return nil
+}
+
+// result returns the latest result of a query
+func (sc *scheduler) result(name string) (*ScheduledResult, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	result, ok := sc.latest[name]
+	return result, ok
+}
+
+// status describes every scheduled query, without result rows
+func (sc *scheduler) status(now time.Time) []ScheduledQueryStatus {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	statuses := make([]ScheduledQueryStatus, 0, len(sc.queries))
+	for _, q := range sc.queries {
+		status := ScheduledQueryStatus{
+			Name:     q.cfg.Name,
+			Schedule: q.cfg.Schedule,
+			NextRun:  q.schedule.Next(now),
+			Running:  sc.running[q.cfg.Name],
+		}
+		if latest, ok := sc.latest[q.cfg.Name]; ok {
+			summary := *latest
+			summary.Rows = nil
+			status.Latest = &summary
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// runScheduledQueries runs each scheduled query on its schedule until the
+// server stops
+func (s *MCPServerWithDB) runScheduledQueries() {
+	if err := s.scheduler.load(s.ctx, s.Config.Name); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	for _, q := range s.scheduler.queries {
+		go s.runSchedule(q)
+	}
+}
+
+// runSchedule waits for each activation of a query's schedule and runs it
+func (s *MCPServerWithDB) runSchedule(q *scheduledQuery) {
+	for {
+		next := q.schedule.Next(time.Now().UTC())
+		if next.IsZero() {
+			log.Printf("Warning: Scheduled query %s never runs", q.cfg.Name)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if _, err := s.runScheduledQuery(s.ctx, q.cfg.Name); err != nil && !errors.Is(err, ErrScheduledRunning) {
+				log.Printf("Warning: Scheduled query %s failed: %v", q.cfg.Name, err)
+			}
+		}
+	}
+}
+
+// runScheduledQuery runs a scheduled query now, stores the result and
+// pushes it to the configured destinations. A failed query still produces
+// a result recording the error.
+func (s *MCPServerWithDB) runScheduledQuery(ctx context.Context, name string) (*ScheduledResult, error) {
+	q, ok := s.scheduler.query(name)
+	if !ok {
+		return nil, ErrScheduledNotFound
+	}
+
+	s.scheduler.mu.Lock()
+	if s.scheduler.running[name] {
+		s.scheduler.mu.Unlock()
+		return nil, ErrScheduledRunning
+	}
+	s.scheduler.running[name] = true
+	s.scheduler.mu.Unlock()
+	defer func() {
+		s.scheduler.mu.Lock()
+		delete(s.scheduler.running, name)
+		s.scheduler.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, scheduledRunTimeout)
+	defer cancel()
+	ctx = connector.WithQueryTag(ctx, s.queryTag("scheduled", "", "", "", name))
+
+	start := time.Now().UTC()
+	rows, err := s.executeQuery(ctx, "scheduled", q.cfg.Query, q.cfg.Params)
+	result := &ScheduledResult{
+		Name:       name,
+		RanAt:      start,
+		DurationMs: time.Since(start).Milliseconds(),
+		RowCount:   len(rows),
+		Rows:       rows,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	if err == nil {
+		if deliveryErr := s.deliverScheduledResult(ctx, q, result); deliveryErr != nil {
+			result.DeliveryError = deliveryErr.Error()
+			log.Printf("Warning: Failed to deliver result of scheduled query %s: %v", name, deliveryErr)
+		}
+	}
+
+	maxRows := q.cfg.MaxRows
+	if maxRows <= 0 {
+		maxRows = defaultScheduledMaxRows
+	}
+	if len(result.Rows) > maxRows {
+		result.Rows = result.Rows[:maxRows]
+		result.Truncated = true
+	}
+
+	if err := s.scheduler.save(ctx, s.Config.Name, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// deliverScheduledResult pushes a complete result to the webhook and S3
+func (s *MCPServerWithDB) deliverScheduledResult(ctx context.Context, q *scheduledQuery, result *ScheduledResult) error {
+	var errs []error
+	if q.cfg.WebhookURL != "" {
+		if err := postWebhook(ctx, q.cfg.WebhookURL, q.cfg.Headers, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if q.cfg.S3 != nil {
+		body, err := json.Marshal(result)
+		if err == nil {
+			key := fmt.Sprintf("%s/%s.json", q.cfg.Name, result.RanAt.Format("20060102T150405Z"))
+			err = q.cfg.S3.Put(ctx, key, "application/json", body)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setupScheduledRoutes configures the routes serving scheduled query results
+func (s *MCPServerWithDB) setupScheduledRoutes(router *gin.RouterGroup) {
+	router.GET("/scheduled", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.scheduler.status(time.Now().UTC()))
+	})
+
+	router.GET("/scheduled/:name", func(c *gin.Context) {
+		name := c.Param("name")
+		if _, ok := s.scheduler.query(name); !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Scheduled query not found: %s", name)})
+			return
+		}
+		result, ok := s.scheduler.result(name)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Scheduled query %s has not run yet", name)})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	})
+
+	router.POST("/scheduled/:name/run", func(c *gin.Context) {
+		result, err := s.runScheduledQuery(c.Request.Context(), c.Param("name"))
+		switch {
+		case errors.Is(err, ErrScheduledNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Scheduled query not found: %s", c.Param("name"))})
+		case errors.Is(err, ErrScheduledRunning):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to run scheduled query: %v", err)})
+		default:
+			c.JSON(http.StatusOK, result)
+		}
+	})
+}
+
+// scheduledResources lists the latest result of every scheduled query as
+// an MCP resource
+func (s *MCPServerWithDB) scheduledResources() []mcp.ResourceSchema {
+	var resources []mcp.ResourceSchema
+	for _, status := range s.scheduler.status(time.Now().UTC()) {
+		if status.Latest == nil {
+			continue
+		}
+		resources = append(resources, mcp.ResourceSchema{
+			URI:         scheduledURIScheme + status.Name,
+			Name:        fmt.Sprintf("%s scheduled query", status.Name),
+			Description: fmt.Sprintf("Latest result of scheduled query %s (%s): %d rows at %s", status.Name, status.Schedule, status.Latest.RowCount, status.Latest.RanAt.Format(time.RFC3339)),
+			MimeType:    "application/json",
+		})
+	}
+	return resources
+}
+
+// readScheduledResource reads a resource listed by scheduledResources. ok
+// is false when the URI does not belong to a scheduled query.
+func (s *MCPServerWithDB) readScheduledResource(uri string) (result *mcp.ReadResourceResult, ok bool, err error) {
+	name, found := strings.CutPrefix(uri, scheduledURIScheme)
+	if !found {
+		return nil, false, nil
+	}
+	latest, found := s.scheduler.result(name)
+	if !found {
+		return nil, true, fmt.Errorf("resource not found: %s", uri)
+	}
+	data, err := json.Marshal(latest)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{{URI: uri, MimeType: "application/json", Text: string(data)}},
+	}, true, nil
+}
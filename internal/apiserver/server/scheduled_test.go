@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+)
+
+// rowsConnector returns fixed rows for every query
+type rowsConnector struct {
+	connector.DatabaseConnector
+	rows []map[string]interface{}
+}
+
+func (c *rowsConnector) ExecuteQuery(context.Context, string, map[string]interface{}) ([]map[string]interface{}, error) {
+	return c.rows, nil
+}
+
+func TestScheduledQueries(t *testing.T) {
+	store, err := state.OpenAndMigrate(&state.Config{DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	defer store.Close()
+
+	var delivered ScheduledResult
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&delivered)
+	}))
+	defer hook.Close()
+
+	cfgs := []ScheduledQueryConfig{{
+		Name:       "daily_orders",
+		Schedule:   "0 6 * * *",
+		Query:      "SELECT * FROM ORDERS",
+		MaxRows:    1,
+		WebhookURL: hook.URL,
+	}}
+	sched, err := newScheduler(cfgs, store.DB)
+	require.NoError(t, err)
+
+	s := &MCPServerWithDB{
+		Config:    &MCPServerConfig{Name: "sales"},
+		DBConn:    &rowsConnector{rows: []map[string]interface{}{{"ID": 1.0}, {"ID": 2.0}}},
+		scheduler: sched,
+	}
+
+	_, err = s.runScheduledQuery(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrScheduledNotFound)
+
+	result, err := s.runScheduledQuery(context.Background(), "daily_orders")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.RowCount)
+	assert.True(t, result.Truncated)
+	assert.Len(t, result.Rows, 1)
+	assert.Empty(t, result.DeliveryError)
+
+	// The webhook receives every row
+	assert.Len(t, delivered.Rows, 2)
+
+	resource, ok, err := s.readScheduledResource("scheduled://daily_orders")
+	require.True(t, ok)
+	require.NoError(t, err)
+	assert.Contains(t, resource.Contents[0].Text, `"row_count":2`)
+	require.Len(t, s.scheduledResources(), 1)
+
+	_, ok, _ = s.readScheduledResource("results://other")
+	assert.False(t, ok)
+
+	// A restarted server restores the latest result
+	restored, err := newScheduler(cfgs, store.DB)
+	require.NoError(t, err)
+	require.NoError(t, restored.load(context.Background(), "sales"))
+	latest, ok := restored.result("daily_orders")
+	require.True(t, ok)
+	assert.Equal(t, []map[string]interface{}{{"ID": 1.0}}, latest.Rows)
+
+	status := restored.status(result.RanAt)
+	require.Len(t, status, 1)
+	assert.Nil(t, status[0].Latest.Rows)
+	assert.Equal(t, 6, status[0].NextRun.Hour())
+}
+
+func TestNewSchedulerRejectsInvalidQueries(t *testing.T) {
+	for _, cfg := range []ScheduledQueryConfig{
+		{Name: "", Schedule: "@hourly", Query: "SELECT 1"},
+		{Name: "a/b", Schedule: "@hourly", Query: "SELECT 1"},
+		{Name: "nightly", Schedule: "every night", Query: "SELECT 1"},
+		{Name: "nightly", Schedule: "@daily"},
+	} {
+		_, err := newScheduler([]ScheduledQueryConfig{cfg}, nil)
+		assert.Error(t, err, cfg.Name)
+	}
+
+	_, err := newScheduler([]ScheduledQueryConfig{
+		{Name: "a", Schedule: "@hourly", Query: "SELECT 1"},
+		{Name: "a", Schedule: "@daily", Query: "SELECT 2"},
+	}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// Formats of the schema export
+const (
+	SchemaFormatDDL        = "ddl"
+	SchemaFormatJSONSchema = "jsonschema"
+	SchemaFormatAvro       = "avro"
+)
+
+// avroInvalidChars are the characters Avro names cannot hold
+var avroInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// avroName converts a table or column name to a valid Avro name
+func avroName(name string) string {
+	name = avroInvalidChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// tableJSONSchema describes a row of a table as a JSON Schema document
+func tableJSONSchema(metadata *connector.TableMetadata) map[string]any {
+	properties := make(map[string]any, len(metadata.Columns))
+	required := []string{}
+	for _, col := range metadata.Columns {
+		schema := columnSchema(col)
+		if col.Computed {
+			schema["readOnly"] = true
+		}
+		properties[col.Name] = schema
+		if !col.Nullable && !col.Computed {
+			required = append(required, col.Name)
+		}
+	}
+	schema := map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      metadata.Name,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+	if metadata.Description != "" {
+		schema["description"] = metadata.Description
+	}
+	return schema
+}
+
+// avroType maps a column to an Avro type, using logical types for
+// decimals, dates and times
+func avroType(col connector.Column) any {
+	t := strings.ToUpper(col.Type)
+	switch paramType := connector.ParamType(col.SQLType()); {
+	case paramType == connector.ParamTypeInteger:
+		return "long"
+	case paramType == connector.ParamTypeNumber && col.Precision > 0:
+		return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": col.Precision, "scale": col.Scale}
+	case paramType == connector.ParamTypeNumber:
+		return "double"
+	case paramType == connector.ParamTypeBoolean:
+		return "boolean"
+	case t == "DATE":
+		return map[string]any{"type": "int", "logicalType": "date"}
+	case strings.HasPrefix(t, "TIMESTAMP") || t == "DATETIME":
+		return map[string]any{"type": "long", "logicalType": "timestamp-micros"}
+	case strings.HasPrefix(t, "TIME"):
+		return map[string]any{"type": "long", "logicalType": "time-micros"}
+	case strings.HasPrefix(t, "BINARY") || strings.HasPrefix(t, "VARBINARY") || t == "BLOB" || t == "BYTEA":
+		return "bytes"
+	}
+	// Text and semi-structured values, which are serialized JSON
+	return "string"
+}
+
+// tableAvroSchema describes a row of a table as an Avro record schema.
+// Nullable columns are unions with null defaulting to null. Names are
+// converted to valid Avro names; a column whose name changed has its
+// original name in its doc.
+func tableAvroSchema(namespace string, metadata *connector.TableMetadata) map[string]any {
+	fields := make([]map[string]any, 0, len(metadata.Columns))
+	for _, col := range metadata.Columns {
+		if col.Computed {
+			continue
+		}
+		field := map[string]any{"name": avroName(col.Name), "type": avroType(col)}
+		if col.Nullable {
+			field["type"] = []any{"null", field["type"]}
+			field["default"] = nil
+		}
+		doc := col.Description
+		if field["name"] != col.Name {
+			doc = strings.TrimSpace(fmt.Sprintf("Column %s. %s", col.Name, doc))
+		}
+		if doc != "" {
+			field["doc"] = doc
+		}
+		fields = append(fields, field)
+	}
+	schema := map[string]any{
+		"type":   "record",
+		"name":   avroName(metadata.Name),
+		"fields": fields,
+	}
+	if namespace != "" {
+		schema["namespace"] = avroName(namespace)
+	}
+	if metadata.Description != "" {
+		schema["doc"] = metadata.Description
+	}
+	return schema
+}
+
+// setupSchemaExportRoutes configures the route exporting a table's schema
+// as DDL, JSON Schema or Avro
+func (s *MCPServerWithDB) setupSchemaExportRoutes(router *gin.RouterGroup) {
+	router.GET("/tables/:tableName/schema", func(c *gin.Context) {
+		format := c.DefaultQuery("format", SchemaFormatDDL)
+		switch format {
+		case SchemaFormatDDL, SchemaFormatJSONSchema, SchemaFormatAvro:
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported schema format %q; use ddl, jsonschema or avro", format)})
+			return
+		}
+
+		metadata, err := s.DBConn.GetTableMetadata(c.Request.Context(), c.Param("tableName"))
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to get table metadata", err)
+			return
+		}
+		metadata = s.withCatalog(metadata)
+
+		switch format {
+		case SchemaFormatDDL:
+			c.Data(http.StatusOK, "application/sql; charset=utf-8", []byte(connector.CreateTableStatement(connector.DialectOf(s.DBConn), metadata)+";\n"))
+		case SchemaFormatJSONSchema:
+			c.JSON(http.StatusOK, tableJSONSchema(s.withComputedColumns(metadata)))
+		case SchemaFormatAvro:
+			c.JSON(http.StatusOK, tableAvroSchema(s.Config.Name, metadata))
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestSchemaExport(t *testing.T) {
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales-db"}, DBConn: &statsConnector{}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupSchemaExportRoutes(router.Group(""))
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get("/tables/ORDERS/schema")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "CREATE TABLE \"ORDERS\" (\n  \"TOTAL\" NUMBER NOT NULL,\n  \"STATUS\" TEXT NOT NULL,\n  \"PAYLOAD\" VARIANT NOT NULL\n);\n", w.Body.String())
+
+	w = get("/tables/ORDERS/schema?format=jsonschema")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var jsonSchema map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jsonSchema))
+	assert.Equal(t, "ORDERS", jsonSchema["title"])
+	assert.Equal(t, []any{"TOTAL", "STATUS", "PAYLOAD"}, jsonSchema["required"])
+	assert.Equal(t, map[string]any{"type": "number"}, jsonSchema["properties"].(map[string]any)["TOTAL"])
+
+	w = get("/tables/ORDERS/schema?format=avro")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var avro struct {
+		Type      string           `json:"type"`
+		Name      string           `json:"name"`
+		Namespace string           `json:"namespace"`
+		Fields    []map[string]any `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &avro))
+	assert.Equal(t, "record", avro.Type)
+	assert.Equal(t, "sales_db", avro.Namespace)
+	require.Len(t, avro.Fields, 3)
+	assert.Equal(t, map[string]any{"name": "TOTAL", "type": "long"}, avro.Fields[0])
+	assert.Equal(t, "string", avro.Fields[2]["type"])
+
+	assert.Equal(t, http.StatusBadRequest, get("/tables/ORDERS/schema?format=protobuf").Code)
+}
+
+func TestAvroType(t *testing.T) {
+	assert.Equal(t, "long", avroType(connector.Column{Type: "NUMBER", Precision: 38}))
+	assert.Equal(t, map[string]any{"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2},
+		avroType(connector.Column{Type: "NUMBER", Precision: 10, Scale: 2}))
+	assert.Equal(t, map[string]any{"type": "int", "logicalType": "date"}, avroType(connector.Column{Type: "DATE"}))
+	assert.Equal(t, map[string]any{"type": "long", "logicalType": "timestamp-micros"}, avroType(connector.Column{Type: "TIMESTAMP_NTZ"}))
+	assert.Equal(t, "boolean", avroType(connector.Column{Type: "BOOLEAN"}))
+	assert.Equal(t, "_2024_SALES", avroName("2024 SALES"))
+
+	schema := tableAvroSchema("", &connector.TableMetadata{Name: "ORDERS", Columns: []connector.Column{
+		{Name: "ORDER ID", Type: "TEXT", Nullable: true, Description: "External reference"},
+	}})
+	assert.Equal(t, []map[string]any{{
+		"name":    "ORDER_ID",
+		"type":    []any{"null", "string"},
+		"default": nil,
+		"doc":     "Column ORDER ID. External reference",
+	}}, schema["fields"])
+	assert.NotContains(t, schema, "namespace")
+}
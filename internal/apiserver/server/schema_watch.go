@@ -0,0 +1,428 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/events"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	defaultSchemaWatchInterval = 10 * time.Minute
+
+	// schemaChangeHistory is the number of detected changes kept for the admin API
+	schemaChangeHistory = 50
+
+	// schemaURIScheme prefixes the URIs of the table schema resources
+	schemaURIScheme = "schema://"
+)
+
+// SchemaWatchConfig schedules periodic re-introspection of the database
+// schema so generated endpoints, MCP tools and cached metadata follow tables
+// and columns being added or dropped
+type SchemaWatchConfig struct {
+	// Interval between introspections (default: 10m)
+	Interval string `json:"interval,omitempty"`
+
+	// WebhookURL receives every detected change as a JSON POST
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// Headers are added to the webhook request, e.g. for authentication
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ColumnChange describes a column that was added, dropped or altered
+type ColumnChange struct {
+	Table        string `json:"table"`
+	Column       string `json:"column"`
+	Type         string `json:"type,omitempty"`
+	PreviousType string `json:"previous_type,omitempty"`
+}
+
+// SchemaChange is a change of the database schema between two introspections
+type SchemaChange struct {
+	Server         string         `json:"server"`
+	DetectedAt     time.Time      `json:"detected_at"`
+	AddedTables    []string       `json:"added_tables,omitempty"`
+	DroppedTables  []string       `json:"dropped_tables,omitempty"`
+	AddedColumns   []ColumnChange `json:"added_columns,omitempty"`
+	DroppedColumns []ColumnChange `json:"dropped_columns,omitempty"`
+	AlteredColumns []ColumnChange `json:"altered_columns,omitempty"`
+
+	// Routes lists the generated routes updated in response to the change
+	Routes RouteDiff `json:"routes"`
+}
+
+// Empty reports whether the schema is unchanged
+func (c *SchemaChange) Empty() bool {
+	return len(c.AddedTables) == 0 && len(c.DroppedTables) == 0 &&
+		len(c.AddedColumns) == 0 && len(c.DroppedColumns) == 0 && len(c.AlteredColumns) == 0
+}
+
+// alteredTables returns the tables present before and after the change
+// whose columns changed
+func (c *SchemaChange) alteredTables() []string {
+	seen := make(map[string]bool)
+	var tables []string
+	for _, changes := range [][]ColumnChange{c.AddedColumns, c.DroppedColumns, c.AlteredColumns} {
+		for _, col := range changes {
+			if !seen[col.Table] {
+				seen[col.Table] = true
+				tables = append(tables, col.Table)
+			}
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// schemaSnapshot holds the columns of every table, keyed by table name
+type schemaSnapshot map[string][]connector.Column
+
+// schemaWatcher holds the last introspected schema and the changes detected
+type schemaWatcher struct {
+	// mu serializes checks so changes are applied in order
+	mu       sync.Mutex
+	snapshot schemaSnapshot
+	changes  []SchemaChange
+}
+
+// diffSchemas compares two snapshots of the schema
+func diffSchemas(prev, next schemaSnapshot) SchemaChange {
+	var change SchemaChange
+	for _, table := range sortedTables(next) {
+		oldColumns, ok := prev[table]
+		if !ok {
+			change.AddedTables = append(change.AddedTables, table)
+			continue
+		}
+
+		old := make(map[string]connector.Column, len(oldColumns))
+		for _, col := range oldColumns {
+			old[col.Name] = col
+		}
+		current := make(map[string]bool, len(next[table]))
+		for _, col := range next[table] {
+			current[col.Name] = true
+			previous, existed := old[col.Name]
+			switch {
+			case !existed:
+				change.AddedColumns = append(change.AddedColumns, ColumnChange{Table: table, Column: col.Name, Type: col.Type})
+			case previous.SQLType() != col.SQLType() || previous.Nullable != col.Nullable:
+				change.AlteredColumns = append(change.AlteredColumns, ColumnChange{
+					Table: table, Column: col.Name, Type: col.Type, PreviousType: previous.Type,
+				})
+			}
+		}
+		for _, col := range oldColumns {
+			if !current[col.Name] {
+				change.DroppedColumns = append(change.DroppedColumns, ColumnChange{Table: table, Column: col.Name, PreviousType: col.Type})
+			}
+		}
+	}
+	for _, table := range sortedTables(prev) {
+		if _, ok := next[table]; !ok {
+			change.DroppedTables = append(change.DroppedTables, table)
+		}
+	}
+	return change
+}
+
+// sortedTables returns the table names of a snapshot in order
+func sortedTables(snapshot schemaSnapshot) []string {
+	tables := make([]string, 0, len(snapshot))
+	for table := range snapshot {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// introspectSchema snapshots the columns of every table, in one query when
+// the connector supports it
+func (s *MCPServerWithDB) introspectSchema(ctx context.Context) (schemaSnapshot, error) {
+	if describer, ok := s.DBConn.(connector.SchemaDescriber); ok {
+		schema, err := describer.DescribeSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return schemaSnapshot(schema), nil
+	}
+
+	tables, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	snapshot := make(schemaSnapshot, len(tables))
+	for _, t := range tables {
+		metadata, err := s.DBConn.GetTableMetadata(ctx, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata for table %s: %w", t.Name, err)
+		}
+		snapshot[t.Name] = metadata.Columns
+	}
+	return snapshot, nil
+}
+
+// checkSchema re-introspects the schema and applies any change since the
+// previous check. The first check only records the baseline and returns nil.
+func (s *MCPServerWithDB) checkSchema(ctx context.Context) (*SchemaChange, error) {
+	snapshot, err := s.introspectSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.schemaWatch.mu.Lock()
+	defer s.schemaWatch.mu.Unlock()
+
+	prev := s.schemaWatch.snapshot
+	s.schemaWatch.snapshot = snapshot
+	if prev == nil {
+		return nil, nil
+	}
+
+	change := diffSchemas(prev, snapshot)
+	if change.Empty() {
+		return nil, nil
+	}
+	change.Server = s.Config.Name
+	change.DetectedAt = time.Now().UTC()
+
+	s.applySchemaChange(ctx, &change)
+	s.notifySchemaChange(&change)
+
+	s.schemaWatch.changes = append(s.schemaWatch.changes, change)
+	if len(s.schemaWatch.changes) > schemaChangeHistory {
+		s.schemaWatch.changes = s.schemaWatch.changes[len(s.schemaWatch.changes)-schemaChangeHistory:]
+	}
+	return &change, nil
+}
+
+// applySchemaChange updates what was derived from the old schema: per-table
+// MCP tools, preloaded metadata, the table search index and the generated
+// endpoints of altered or dropped tables. Tables never generated are not
+// given endpoints.
+func (s *MCPServerWithDB) applySchemaChange(ctx context.Context, change *SchemaChange) {
+	s.invalidateTableTools()
+	if s.warmup != nil {
+		s.warmup.forget()
+	}
+
+	altered := change.alteredTables()
+	if s.tableSearch != nil {
+		changed := append(append([]string{}, change.AddedTables...), altered...)
+		if err := s.reindexTables(ctx, changed, change.DroppedTables); err != nil {
+			log.Printf("Warning: Failed to update table search index: %v", err)
+		}
+	}
+
+	if s.routes == nil {
+		return
+	}
+
+	generated := make(map[string]bool)
+	for _, e := range s.routes.Endpoints() {
+		generated[e.Table] = true
+	}
+	var regenerate []string
+	for _, table := range altered {
+		if generated[table] {
+			regenerate = append(regenerate, table)
+		}
+	}
+
+	if len(change.DroppedTables) > 0 {
+		diff, err := s.routes.RemoveTables(change.DroppedTables)
+		if err != nil {
+			log.Printf("Warning: Failed to remove endpoints of dropped tables: %v", err)
+		}
+		change.Routes.Removed = append(change.Routes.Removed, diff.Removed...)
+	}
+	if len(regenerate) > 0 {
+		endpoints, err := s.GenerateEndpoints(ctx, regenerate)
+		if err != nil {
+			log.Printf("Warning: Failed to regenerate API endpoints: %v", err)
+		} else if diff, err := s.routes.Apply(endpoints); err != nil {
+			log.Printf("Warning: Failed to register regenerated API endpoints: %v", err)
+		} else {
+			change.Routes.Added = append(change.Routes.Added, diff.Added...)
+			change.Routes.Updated = append(change.Routes.Updated, diff.Updated...)
+			change.Routes.Removed = append(change.Routes.Removed, diff.Removed...)
+		}
+	}
+
+	if !change.Routes.Empty() && s.OnEndpointsGenerated != nil {
+		s.OnEndpointsGenerated(s.routes.Endpoints())
+	}
+}
+
+// notifySchemaChange tells MCP clients what a change affected: the tool
+// list always, since per-table tools follow the schema, the resource list
+// when tables were added or dropped and the schema resources of altered
+// tables
+func (s *MCPServerWithDB) notifySchemaChange(change *SchemaChange) {
+	s.notifyListChanged(mcp.NotificationToolListChanged)
+	if len(change.AddedTables) > 0 || len(change.DroppedTables) > 0 {
+		s.notifyListChanged(mcp.NotificationResourceListChanged)
+	}
+	for _, table := range change.alteredTables() {
+		s.notifyResourceUpdated(schemaURIScheme + table)
+	}
+}
+
+// schemaResources lists the columns of every table as of the last schema
+// check. Nothing is listed until the schema has been checked once.
+func (s *MCPServerWithDB) schemaResources() []mcp.ResourceSchema {
+	s.schemaWatch.mu.Lock()
+	defer s.schemaWatch.mu.Unlock()
+	resources := make([]mcp.ResourceSchema, 0, len(s.schemaWatch.snapshot))
+	for _, table := range sortedTables(s.schemaWatch.snapshot) {
+		resources = append(resources, mcp.ResourceSchema{
+			URI:         schemaURIScheme + table,
+			Name:        fmt.Sprintf("%s schema", table),
+			Description: fmt.Sprintf("Columns of %s; subscribe to be notified when they change", table),
+			MimeType:    "application/json",
+		})
+	}
+	return resources
+}
+
+// readSchemaResource reads a resource listed by schemaResources. ok is
+// false when the URI does not belong to a schema resource.
+func (s *MCPServerWithDB) readSchemaResource(ctx context.Context, uri string) (result *mcp.ReadResourceResult, ok bool, err error) {
+	table, found := strings.CutPrefix(uri, schemaURIScheme)
+	if !found {
+		return nil, false, nil
+	}
+	s.schemaWatch.mu.Lock()
+	columns, found := s.schemaWatch.snapshot[table]
+	s.schemaWatch.mu.Unlock()
+	if !found {
+		return nil, true, fmt.Errorf("resource not found: %s", uri)
+	}
+	if err := s.checkTableAccess(ctx, table); err != nil {
+		return nil, true, err
+	}
+	metadata := s.withComputedColumns(&connector.TableMetadata{Name: table, Columns: columns})
+	data, err := json.Marshal(map[string]interface{}{"table": table, "columns": metadata.Columns})
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to encode resource: %w", err)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []mcp.ResourceContents{{
+			URI:      uri,
+			MimeType: "application/json",
+			Text:     string(data),
+			Meta:     map[string]interface{}{"etag": contentETag(data)},
+		}},
+	}, true, nil
+}
+
+// runSchemaWatch re-introspects the schema every interval until the server
+// stops, reporting changes to the log, the audit trail and the webhook
+func (s *MCPServerWithDB) runSchemaWatch(cfg *SchemaWatchConfig) {
+	interval := defaultSchemaWatchInterval
+	if cfg.Interval != "" {
+		if d, err := time.ParseDuration(cfg.Interval); err == nil && d > 0 {
+			interval = d
+		}
+	}
+
+	if _, err := s.checkSchema(s.ctx); err != nil {
+		log.Printf("Warning: Failed to introspect schema: %v", err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			change, err := s.checkSchema(s.ctx)
+			if err != nil {
+				log.Printf("Warning: Failed to introspect schema: %v", err)
+				continue
+			}
+			if change != nil {
+				s.reportSchemaChange(cfg, change)
+			}
+		}
+	}
+}
+
+// reportSchemaChange logs, audits and publishes a change and posts it to
+// the webhook
+func (s *MCPServerWithDB) reportSchemaChange(cfg *SchemaWatchConfig, change *SchemaChange) {
+	log.Printf("Schema changed: %d tables added, %d dropped, %d columns added, %d dropped, %d altered",
+		len(change.AddedTables), len(change.DroppedTables),
+		len(change.AddedColumns), len(change.DroppedColumns), len(change.AlteredColumns))
+
+	_ = s.Audit.Record(s.ctx, &audit.Event{
+		Time:      change.DetectedAt,
+		Action:    "schema_change",
+		Principal: "system",
+		Resource:  s.Config.Name,
+		Details: map[string]interface{}{
+			"added_tables":    change.AddedTables,
+			"dropped_tables":  change.DroppedTables,
+			"added_columns":   len(change.AddedColumns),
+			"dropped_columns": len(change.DroppedColumns),
+			"altered_columns": len(change.AlteredColumns),
+		},
+	})
+
+	s.publish(events.TypeSchemaChanged, map[string]interface{}{
+		"added_tables":    change.AddedTables,
+		"dropped_tables":  change.DroppedTables,
+		"added_columns":   change.AddedColumns,
+		"dropped_columns": change.DroppedColumns,
+		"altered_columns": change.AlteredColumns,
+		"routes":          change.Routes,
+	})
+
+	if cfg == nil || cfg.WebhookURL == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, webhookTimeout)
+	defer cancel()
+	if err := postWebhook(ctx, cfg.WebhookURL, cfg.Headers, change); err != nil {
+		log.Printf("Warning: Failed to deliver schema change event: %v", err)
+	}
+}
+
+// setupSchemaWatchRoutes configures the admin routes listing detected schema
+// changes and triggering an immediate check
+func (s *MCPServerWithDB) setupSchemaWatchRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/schema/changes", func(c *gin.Context) {
+		s.schemaWatch.mu.Lock()
+		changes := append([]SchemaChange{}, s.schemaWatch.changes...)
+		s.schemaWatch.mu.Unlock()
+		c.JSON(http.StatusOK, changes)
+	})
+
+	router.POST("/admin/schema/check", func(c *gin.Context) {
+		change, err := s.checkSchema(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to introspect schema: %v", err)})
+			return
+		}
+		if change == nil {
+			c.JSON(http.StatusOK, gin.H{"changed": false})
+			return
+		}
+		s.reportSchemaChange(s.Config.SchemaWatch, change)
+		c.JSON(http.StatusOK, change)
+	})
+}
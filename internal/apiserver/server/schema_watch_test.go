@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaConnector serves a mutable schema and generates one endpoint per table
+type schemaConnector struct {
+	connector.DatabaseConnector
+	schema map[string][]connector.Column
+}
+
+func (c *schemaConnector) DescribeSchema(context.Context) (map[string][]connector.Column, error) {
+	schema := make(map[string][]connector.Column, len(c.schema))
+	for table, columns := range c.schema {
+		schema[table] = append([]connector.Column{}, columns...)
+	}
+	return schema, nil
+}
+
+func (c *schemaConnector) GenerateAPIEndpoints(_ context.Context, tables []string) ([]connector.APIEndpoint, error) {
+	var endpoints []connector.APIEndpoint
+	for _, table := range tables {
+		query := "SELECT"
+		for _, col := range c.schema[table] {
+			query += " " + col.Name
+		}
+		endpoints = append(endpoints, connector.APIEndpoint{
+			Table: table, Method: "GET", Path: "/" + table, Query: query + " FROM " + table,
+		})
+	}
+	return endpoints, nil
+}
+
+func TestDiffSchemas(t *testing.T) {
+	prev := schemaSnapshot{
+		"ORDERS":    {{Name: "ID", Type: "NUMBER"}, {Name: "NOTE", Type: "TEXT"}, {Name: "TOTAL", Type: "NUMBER"}},
+		"CUSTOMERS": {{Name: "ID", Type: "NUMBER"}},
+	}
+	next := schemaSnapshot{
+		"ORDERS":   {{Name: "ID", Type: "NUMBER"}, {Name: "TOTAL", Type: "FLOAT"}, {Name: "STATUS", Type: "TEXT"}},
+		"INVOICES": {{Name: "ID", Type: "NUMBER"}},
+	}
+
+	change := diffSchemas(prev, next)
+	assert.Equal(t, []string{"INVOICES"}, change.AddedTables)
+	assert.Equal(t, []string{"CUSTOMERS"}, change.DroppedTables)
+	assert.Equal(t, []ColumnChange{{Table: "ORDERS", Column: "STATUS", Type: "TEXT"}}, change.AddedColumns)
+	assert.Equal(t, []ColumnChange{{Table: "ORDERS", Column: "NOTE", PreviousType: "TEXT"}}, change.DroppedColumns)
+	assert.Equal(t, []ColumnChange{{Table: "ORDERS", Column: "TOTAL", Type: "FLOAT", PreviousType: "NUMBER"}}, change.AlteredColumns)
+	assert.Equal(t, []string{"ORDERS"}, change.alteredTables())
+
+	assert.True(t, (&SchemaChange{}).Empty())
+	unchanged := diffSchemas(next, next)
+	assert.True(t, unchanged.Empty())
+}
+
+func TestCheckSchemaUpdatesGeneratedEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := &schemaConnector{schema: map[string][]connector.Column{
+		"ORDERS":    {{Name: "ID", Type: "NUMBER"}},
+		"CUSTOMERS": {{Name: "ID", Type: "NUMBER"}},
+	}}
+
+	var persisted []connector.APIEndpoint
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "sales"},
+		DBConn:      conn,
+		schemaWatch: &schemaWatcher{},
+		OnEndpointsGenerated: func(endpoints []connector.APIEndpoint) {
+			persisted = endpoints
+		},
+	}
+	s.routes = newRouteManager("/api/db", s.generatedEndpointHandler)
+
+	endpoints, err := conn.GenerateAPIEndpoints(context.Background(), []string{"ORDERS", "CUSTOMERS"})
+	require.NoError(t, err)
+	require.NoError(t, s.RegisterEndpoints(endpoints))
+
+	// The first check records the baseline
+	change, err := s.checkSchema(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, change)
+
+	conn.schema["ORDERS"] = append(conn.schema["ORDERS"], connector.Column{Name: "STATUS", Type: "TEXT"})
+	delete(conn.schema, "CUSTOMERS")
+	conn.schema["INVOICES"] = []connector.Column{{Name: "ID", Type: "NUMBER"}}
+
+	change, err = s.checkSchema(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, change)
+	assert.Equal(t, "sales", change.Server)
+	assert.Equal(t, []string{"INVOICES"}, change.AddedTables)
+	assert.Equal(t, []string{"CUSTOMERS"}, change.DroppedTables)
+	assert.Equal(t, []string{"GET /ORDERS"}, change.Routes.Updated)
+	assert.Equal(t, []string{"GET /CUSTOMERS"}, change.Routes.Removed)
+
+	// Tables never generated do not gain endpoints
+	require.Len(t, persisted, 1)
+	assert.Equal(t, "SELECT ID STATUS FROM ORDERS", persisted[0].Query)
+
+	change, err = s.checkSchema(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, change)
+	assert.Len(t, s.schemaWatch.changes, 1)
+}
+
+func TestSchemaChangeNotifications(t *testing.T) {
+	conn := &schemaConnector{schema: map[string][]connector.Column{
+		"ORDERS":    {{Name: "ID", Type: "NUMBER"}},
+		"CUSTOMERS": {{Name: "ID", Type: "NUMBER"}},
+	}}
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "sales"},
+		DBConn:      conn,
+		schemaWatch: &schemaWatcher{},
+		mcpSessions: newMCPSessionStore(),
+	}
+	ctx := context.Background()
+	_, err := s.checkSchema(ctx)
+	require.NoError(t, err)
+	assert.Len(t, s.schemaResources(), 2)
+
+	sess := s.mcpSessions.create(mcp.ImplementationSchema{Name: "client"}, "", "")
+	sess.subscribe("schema://ORDERS")
+	notifications, ok := sess.openStream()
+	require.True(t, ok)
+
+	conn.schema["ORDERS"] = append(conn.schema["ORDERS"], connector.Column{Name: "STATUS", Type: "TEXT"})
+	conn.schema["INVOICES"] = []connector.Column{{Name: "ID", Type: "NUMBER"}}
+	_, err = s.checkSchema(ctx)
+	require.NoError(t, err)
+
+	var methods []string
+	for len(notifications) > 0 {
+		n := <-notifications
+		methods = append(methods, n.Method)
+		if n.Method == mcp.NotificationResourceUpdated {
+			assert.JSONEq(t, `{"uri": "schema://ORDERS"}`, string(n.Params))
+		}
+	}
+	assert.Equal(t, []string{
+		mcp.NotificationToolListChanged, mcp.NotificationResourceListChanged, mcp.NotificationResourceUpdated,
+	}, methods)
+
+	result, ok, err := s.readSchemaResource(ctx, "schema://ORDERS")
+	require.True(t, ok)
+	require.NoError(t, err)
+	assert.Contains(t, result.Contents[0].Text, `"STATUS"`)
+	_, ok, err = s.readSchemaResource(ctx, "schema://MISSING")
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
@@ -0,0 +1,297 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultSnapshotInterval = time.Hour
+	defaultSnapshotMaxRows  = 100000
+
+	// snapshotHeader reports when the snapshot serving a response was taken
+	snapshotHeader = "X-Snapshot"
+)
+
+// ErrSnapshotTooLarge is returned for tables with more rows than a
+// snapshot holds
+var ErrSnapshotTooLarge = errors.New("table too large to snapshot")
+
+// SnapshotConfig copies small, slowly changing tables, such as reference
+// data, into an embedded SQLite store on a schedule. Generated list, get and
+// search endpoints of those tables are served from the copy, falling back
+// to the warehouse when the copy cannot answer.
+type SnapshotConfig struct {
+	// Tables are the tables copied
+	Tables []string `json:"tables"`
+
+	// Interval between refreshes of the copies (default: 1h)
+	Interval string `json:"interval,omitempty"`
+
+	// MaxRows is the most rows of a table copied; larger tables are left
+	// to the warehouse (default: 100000)
+	MaxRows int `json:"max_rows,omitempty"`
+
+	// Path is the SQLite file holding the copies; empty keeps them in
+	// memory, as tenant servers must
+	Path string `json:"path,omitempty"`
+}
+
+// SnapshotStatus describes the copy of a table
+type SnapshotStatus struct {
+	Table       string     `json:"table"`
+	Rows        int        `json:"rows"`
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// snapshotTable is the loaded copy of a table
+type snapshotTable struct {
+	kinds       map[string]int
+	rows        int
+	refreshedAt time.Time
+}
+
+// snapshotStore holds the copies of the configured tables
+type snapshotStore struct {
+	db       *sqlx.DB
+	interval time.Duration
+	maxRows  int
+
+	mu     sync.RWMutex
+	tables map[string]*snapshotTable
+	errors map[string]string
+}
+
+// newSnapshotStore opens the store of a configuration; nil disables
+// snapshots and returns a nil store
+func newSnapshotStore(cfg *SnapshotConfig) (*snapshotStore, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if len(cfg.Tables) == 0 {
+		return nil, fmt.Errorf("tables are required")
+	}
+	st := &snapshotStore{
+		interval: defaultSnapshotInterval,
+		maxRows:  defaultSnapshotMaxRows,
+		tables:   make(map[string]*snapshotTable),
+		errors:   make(map[string]string),
+	}
+	for _, table := range cfg.Tables {
+		st.errors[table] = "not loaded yet"
+	}
+	if cfg.Interval != "" {
+		d, err := time.ParseDuration(cfg.Interval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid interval: %s", cfg.Interval)
+		}
+		st.interval = d
+	}
+	switch {
+	case cfg.MaxRows < 0:
+		return nil, fmt.Errorf("max_rows must not be negative")
+	case cfg.MaxRows > 0:
+		st.maxRows = cfg.MaxRows
+	}
+
+	db, err := openSQLite(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot store: %w", err)
+	}
+	st.db = db
+	return st, nil
+}
+
+// lookup returns the loaded copy of a table
+func (st *snapshotStore) lookup(table string) (*snapshotTable, bool) {
+	if st == nil {
+		return nil, false
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	t, ok := st.tables[table]
+	return t, ok
+}
+
+// status lists the copies of the configured tables in name order
+func (st *snapshotStore) status() []SnapshotStatus {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	statuses := make([]SnapshotStatus, 0, len(st.errors))
+	for table := range st.errors {
+		status := SnapshotStatus{Table: table, Error: st.errors[table]}
+		if t, ok := st.tables[table]; ok {
+			refreshed := t.refreshedAt
+			status.Rows, status.RefreshedAt = t.rows, &refreshed
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Table < statuses[j].Table })
+	return statuses
+}
+
+// configured reports whether a table is copied
+func (st *snapshotStore) configured(table string) bool {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	_, ok := st.errors[table]
+	return ok
+}
+
+// load replaces the copy of a table with rows read from the warehouse
+func (st *snapshotStore) load(ctx context.Context, table string, result *connector.ResultSet) error {
+	tx, err := st.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	kinds, err := createSQLiteTable(ctx, tx, table, result)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	st.mu.Lock()
+	st.tables[table] = &snapshotTable{kinds: kinds, rows: len(result.Rows), refreshedAt: time.Now().UTC()}
+	st.errors[table] = ""
+	st.mu.Unlock()
+	return nil
+}
+
+// fail records why a table could not be copied; a previous copy stays in use
+func (st *snapshotStore) fail(table string, err error) {
+	st.mu.Lock()
+	st.errors[table] = err.Error()
+	st.mu.Unlock()
+}
+
+// refreshSnapshot copies a table from the warehouse. The copy is always
+// taken from the primary, even when an admin's request selected another
+// connection.
+func (s *MCPServerWithDB) refreshSnapshot(ctx context.Context, table string) error {
+	st := s.snapshots
+	if s.DBConn == nil {
+		return fmt.Errorf("no database connection")
+	}
+	ctx = primaryConnection(ctx)
+	d := connector.DialectOf(s.DBConn)
+	query := fmt.Sprintf("SELECT * FROM %s %s", d.Table(table), d.LimitOffset(strconv.Itoa(st.maxRows+1), ""))
+	result, err := s.executeOrdered(ctx, "snapshot", query, map[string]interface{}{})
+	if err == nil && len(result.Rows) > st.maxRows {
+		err = fmt.Errorf("%w: more than %d rows", ErrSnapshotTooLarge, st.maxRows)
+	}
+	if err == nil {
+		err = st.load(ctx, table, result)
+	}
+	if err != nil {
+		st.fail(table, err)
+		return err
+	}
+	return nil
+}
+
+// refreshSnapshots copies every configured table, logging failures
+func (s *MCPServerWithDB) refreshSnapshots(ctx context.Context) {
+	for _, table := range s.Config.Snapshots.Tables {
+		if err := s.refreshSnapshot(ctx, table); err != nil && ctx.Err() == nil {
+			log.Printf("Warning: Failed to snapshot table %s: %v", table, err)
+		}
+	}
+}
+
+// runSnapshots refreshes the copies every interval until the server stops
+func (s *MCPServerWithDB) runSnapshots() {
+	s.refreshSnapshots(s.ctx)
+
+	ticker := time.NewTicker(s.snapshots.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshSnapshots(s.ctx)
+		}
+	}
+}
+
+// snapshotResult answers a generated list, get or search request from the
+// copy of its table. ok is false on a miss: the table has no copy, the
+// query cannot run on it or a get finds no row, which may be newer than
+// the copy.
+func (s *MCPServerWithDB) snapshotResult(c *gin.Context, endpoint connector.APIEndpoint, query string, params map[string]interface{}) (*connector.ResultSet, bool) {
+	switch endpoint.Operation {
+	case connector.OperationList, connector.OperationGet, connector.OperationSearch:
+	default:
+		return nil, false
+	}
+	t, ok := s.snapshots.lookup(endpoint.Table)
+	if !ok || endpoint.Method != http.MethodGet {
+		return nil, false
+	}
+
+	// The copy is stored under the table's bare name
+	d := connector.DialectOf(s.DBConn)
+	query = strings.ReplaceAll(query, d.Table(endpoint.Table), connector.QuoteIdentifier(endpoint.Table))
+	result, err := querySQLite(c.Request.Context(), s.snapshots.db, t.kinds, query, params)
+	if err != nil {
+		log.Printf("Warning: Falling back to the warehouse for table %s: %v", endpoint.Table, err)
+		return nil, false
+	}
+	if endpoint.Operation == connector.OperationGet && len(result.Rows) == 0 {
+		return nil, false
+	}
+	c.Header(snapshotHeader, t.refreshedAt.Format(time.RFC3339))
+	return result, true
+}
+
+// setupSnapshotRoutes sets up the routes reporting and refreshing snapshots
+func (s *MCPServerWithDB) setupSnapshotRoutes(router *gin.RouterGroup) {
+	if s.snapshots == nil {
+		return
+	}
+
+	router.GET("/admin/snapshots", func(c *gin.Context) {
+		c.JSON(http.StatusOK, s.snapshots.status())
+	})
+
+	router.POST("/admin/snapshots/:table/refresh", func(c *gin.Context) {
+		table := c.Param("table")
+		if !s.snapshots.configured(table) {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Table %s is not snapshotted", table)})
+			return
+		}
+		err := s.refreshSnapshot(c.Request.Context(), table)
+		if errors.Is(err, ErrSnapshotTooLarge) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to refresh snapshot", err)
+			return
+		}
+		for _, status := range s.snapshots.status() {
+			if status.Table == table {
+				c.JSON(http.StatusOK, status)
+				return
+			}
+		}
+	})
+}
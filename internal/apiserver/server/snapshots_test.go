@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// warehouseConnector serves fixed rows in the Snowflake dialect, counting
+// the queries it runs
+type warehouseConnector struct {
+	rowsConnector
+	queries int
+}
+
+func (c *warehouseConnector) ExecuteQuery(ctx context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.queries++
+	return c.rowsConnector.ExecuteQuery(ctx, query, params)
+}
+
+func (c *warehouseConnector) Dialect() connector.Dialect {
+	return connector.SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+}
+
+func TestSnapshots(t *testing.T) {
+	conn := &warehouseConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{
+		{"CODE": "NO", "NAME": "Norway", "POPULATION": int64(5500000), "EU": false},
+		{"CODE": "SE", "NAME": "Sweden", "POPULATION": int64(10500000), "EU": true},
+	}}}
+	snapshots, err := newSnapshotStore(&SnapshotConfig{Tables: []string{"COUNTRIES"}})
+	require.NoError(t, err)
+	staging := &warehouseConnector{}
+	l := &labeledConnection{label: "staging", conn: staging, connected: true}
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "geo"},
+		DBConn:      conn,
+		snapshots:   snapshots,
+		connections: &connectionSet{byLabel: map[string]*labeledConnection{"staging": l}, order: []*labeledConnection{l}},
+	}
+
+	// Copies are taken from the primary, whichever connection the caller
+	// selected
+	ctx, err := s.withConnection(context.Background(), "staging")
+	require.NoError(t, err)
+	require.NoError(t, s.refreshSnapshot(ctx, "COUNTRIES"))
+	assert.Equal(t, 1, conn.queries)
+	assert.Zero(t, staging.queries)
+	status := snapshots.status()
+	require.Len(t, status, 1)
+	assert.Equal(t, 2, status[0].Rows)
+	assert.Empty(t, status[0].Error)
+
+	d := conn.Dialect()
+	endpoints := []connector.APIEndpoint{
+		{Table: "COUNTRIES", Operation: connector.OperationList, Method: http.MethodGet, Path: "/COUNTRIES",
+			Query: connector.SelectPageQuery(d, "COUNTRIES")},
+		{Table: "COUNTRIES", Operation: connector.OperationGet, Method: http.MethodGet, Path: "/COUNTRIES/{CODE}",
+			Query: connector.SelectByKeyQuery(d, "COUNTRIES", "CODE")},
+		{Table: "COUNTRIES", Operation: connector.OperationSearch, Method: http.MethodGet, Path: "/COUNTRIES/search",
+			Query: connector.SearchQuery(d, "COUNTRIES", []string{"NAME"}), SearchColumns: []string{"NAME"}},
+	}
+	routes := newRouteManager("/api", s.generatedEndpointHandler)
+	_, err = routes.Apply(endpoints)
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.NoRoute(routes.ServeHTTP)
+	get := func(path string) (*httptest.ResponseRecorder, []map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var rows []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rows))
+		return w, rows
+	}
+
+	w, rows := get("/api/COUNTRIES?limit=1&offset=1")
+	assert.NotEmpty(t, w.Header().Get(snapshotHeader))
+	require.Len(t, rows, 1)
+	assert.Equal(t, map[string]interface{}{"CODE": "SE", "NAME": "Sweden", "POPULATION": float64(10500000), "EU": true}, rows[0])
+
+	_, rows = get("/api/COUNTRIES/NO")
+	require.Len(t, rows, 1)
+	assert.Equal(t, false, rows[0]["EU"])
+
+	_, rows = get("/api/COUNTRIES/search?q=SWE")
+	require.Len(t, rows, 1)
+	assert.Equal(t, "SE", rows[0]["CODE"])
+	assert.Equal(t, 1, conn.queries, "reads are served from the snapshot")
+
+	// Rows missing from the snapshot are looked up in the warehouse
+	w, _ = get("/api/COUNTRIES/DK")
+	assert.Empty(t, w.Header().Get(snapshotHeader))
+	assert.Equal(t, 2, conn.queries)
+
+	// Tables over the row limit keep their previous snapshot
+	snapshots.maxRows = 1
+	assert.ErrorIs(t, s.refreshSnapshot(context.Background(), "COUNTRIES"), ErrSnapshotTooLarge)
+	status = snapshots.status()
+	assert.Equal(t, 2, status[0].Rows)
+	assert.Contains(t, status[0].Error, "more than 1 rows")
+
+	for _, cfg := range []*SnapshotConfig{{}, {Tables: []string{"A"}, Interval: "daily"}, {Tables: []string{"A"}, MaxRows: -1}} {
+		_, err := newSnapshotStore(cfg)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	sqlite "github.com/glebarez/go-sqlite"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// Column kinds whose values SQLite does not store as they are read
+const (
+	sqliteKindPlain = iota
+	sqliteKindBool
+	sqliteKindJSON
+)
+
+var registerSQLiteFunctions sync.Once
+
+// openSQLite opens an embedded SQLite database holding copies of query
+// results; an empty path keeps it in memory
+func openSQLite(path string) (*sqlx.DB, error) {
+	registerSQLiteFunctions.Do(registerWarehouseFunctions)
+	if path == "" {
+		path = ":memory:"
+	}
+	db, err := sqlx.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// A single connection keeps an in-memory database alive and serializes
+	// writes with reads
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	return db, nil
+}
+
+// registerWarehouseFunctions adds the warehouse functions generated queries
+// use that SQLite lacks, so their queries run on the copies unchanged
+func registerWarehouseFunctions() {
+	// CONTAINS(text, part) is Snowflake's substring test
+	err := sqlite.RegisterDeterministicScalarFunction("contains", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		if args[0] == nil || args[1] == nil {
+			return nil, nil
+		}
+		if strings.Contains(fmt.Sprint(args[0]), fmt.Sprint(args[1])) {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to register SQLite functions: %v", err)
+	}
+}
+
+// createSQLiteTable replaces a table with the rows of a result, returning
+// the kinds of its columns
+func createSQLiteTable(ctx context.Context, tx *sqlx.Tx, table string, result *connector.ResultSet) (map[string]int, error) {
+	columns := result.ColumnOrder()
+	kinds := sqliteKinds(columns, result.Rows)
+
+	quoted := make([]string, len(columns))
+	definitions := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = connector.QuoteIdentifier(col)
+		definitions[i] = quoted[i] + " " + sqliteAffinity(kinds[col], result.Rows, col)
+		placeholders[i] = "?"
+	}
+	name := connector.QuoteIdentifier(table)
+
+	if _, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS "+name); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", name, strings.Join(definitions, ", "))); err != nil {
+		return nil, err
+	}
+	insert, err := tx.PreparexContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		name, strings.Join(quoted, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	defer insert.Close()
+	for _, row := range result.Rows {
+		args := make([]interface{}, len(columns))
+		for i, col := range columns {
+			if args[i], err = sqliteValue(kinds[col], row[col]); err != nil {
+				return nil, fmt.Errorf("column %s: %w", col, err)
+			}
+		}
+		if _, err := insert.ExecContext(ctx, args...); err != nil {
+			return nil, err
+		}
+	}
+	return kinds, nil
+}
+
+// querySQLite runs a query binding :name parameters, converting the values
+// of columns of the given kinds back to the ones copied
+func querySQLite(ctx context.Context, db *sqlx.DB, kinds map[string]int, query string, params map[string]interface{}) (*connector.ResultSet, error) {
+	named, args, err := sqlx.Named(query, params)
+	if err != nil {
+		return nil, err
+	}
+	if named, args, err = sqlx.In(named, args...); err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryxContext(ctx, named, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &connector.ResultSet{Columns: columns, Rows: []map[string]interface{}{}}
+	for rows.Next() {
+		row := make(map[string]interface{}, len(columns))
+		if err := rows.MapScan(row); err != nil {
+			return nil, err
+		}
+		for col, v := range row {
+			if row[col], err = sqliteRead(kinds[col], v); err != nil {
+				return nil, fmt.Errorf("column %s: %w", col, err)
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
+
+// sqliteKinds classifies columns by their first non-null value
+func sqliteKinds(columns []string, rows []map[string]interface{}) map[string]int {
+	kinds := make(map[string]int, len(columns))
+	for _, col := range columns {
+		switch firstValue(rows, col).(type) {
+		case bool:
+			kinds[col] = sqliteKindBool
+		case map[string]interface{}, []interface{}:
+			kinds[col] = sqliteKindJSON
+		}
+	}
+	return kinds
+}
+
+// firstValue returns the first non-null value of a column
+func firstValue(rows []map[string]interface{}, col string) interface{} {
+	for _, row := range rows {
+		if v := row[col]; v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// sqliteAffinity declares a column numeric when its values are, so that
+// text parameters, like path parameters, compare with them as numbers
+func sqliteAffinity(kind int, rows []map[string]interface{}, col string) string {
+	if kind == sqliteKindBool {
+		return "NUMERIC"
+	}
+	switch firstValue(rows, col).(type) {
+	case int, int32, int64, float32, float64, json.Number:
+		return "NUMERIC"
+	}
+	return "TEXT"
+}
+
+// sqliteValue converts a copied value to the one stored
+func sqliteValue(kind int, v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		// Stored as rendered in JSON responses
+		return v.Format(time.RFC3339Nano), nil
+	case json.Number:
+		return v.String(), nil
+	}
+	if kind == sqliteKindJSON {
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+	return v, nil
+}
+
+// sqliteRead converts a stored value back to the one copied
+func sqliteRead(kind int, v interface{}) (interface{}, error) {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	switch {
+	case v == nil:
+		return nil, nil
+	case kind == sqliteKindBool:
+		n, ok := v.(int64)
+		return ok && n != 0, nil
+	case kind == sqliteKindJSON:
+		s, _ := v.(string)
+		var decoded interface{}
+		err := json.Unmarshal([]byte(s), &decoded)
+		return decoded, err
+	}
+	return v, nil
+}
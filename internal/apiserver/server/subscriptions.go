@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultSubscriptionInterval    = 30 * time.Second
+	defaultMinSubscriptionInterval = 5 * time.Second
+	subscriptionWriteTimeout       = 10 * time.Second
+)
+
+// Subscription message types
+const (
+	subscriptionSnapshot = "snapshot"
+	subscriptionDiff     = "diff"
+	subscriptionError    = "error"
+)
+
+// SubscriptionConfig limits how often subscribed saved queries are re-run
+type SubscriptionConfig struct {
+	// DefaultInterval is used when a subscription sets no ?interval=
+	// (default: 30s)
+	DefaultInterval string `json:"default_interval,omitempty"`
+
+	// MinInterval is the shortest interval a subscription may ask for
+	// (default: 5s)
+	MinInterval string `json:"min_interval,omitempty"`
+}
+
+// intervals returns the default and minimum refresh intervals
+func (c *SubscriptionConfig) intervals() (def, min time.Duration) {
+	def, min = defaultSubscriptionInterval, defaultMinSubscriptionInterval
+	if c == nil {
+		return def, min
+	}
+	if d, err := time.ParseDuration(c.DefaultInterval); err == nil && d > 0 {
+		def = d
+	}
+	if d, err := time.ParseDuration(c.MinInterval); err == nil && d > 0 {
+		min = d
+	}
+	if def < min {
+		def = min
+	}
+	return def, min
+}
+
+// subscriptionMessage is pushed to subscribers: the full result first,
+// then the rows added, updated and removed by each refresh that changed it
+type subscriptionMessage struct {
+	Type    string                   `json:"type"`
+	Time    time.Time                `json:"time"`
+	Rows    []map[string]interface{} `json:"rows,omitempty"`
+	Added   []map[string]interface{} `json:"added,omitempty"`
+	Updated []map[string]interface{} `json:"updated,omitempty"`
+	Removed []map[string]interface{} `json:"removed,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+var subscriptionUpgrader = websocket.Upgrader{
+	// Callers authenticate with tokens rather than cookies
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// setupSubscriptionRoutes configures the WebSocket endpoint subscribing to
+// a saved query. The query string carries the query's arguments, the
+// refresh ?interval= and an optional ?key= column identifying rows, so
+// changed rows are reported as updates rather than a removal and an
+// addition.
+func (s *MCPServerWithDB) setupSubscriptionRoutes(router *gin.RouterGroup) {
+	router.GET("/saved/:name/subscribe", func(c *gin.Context) {
+		def, min := s.Config.Subscriptions.intervals()
+		args := make(map[string]interface{})
+		for key, values := range c.Request.URL.Query() {
+			if len(values) > 0 && key != "interval" && key != "key" {
+				args[key] = values[0]
+			}
+		}
+
+		interval := def
+		if v := c.Query("interval"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < min {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: interval must be a duration of at least %s", min)})
+				return
+			}
+			interval = d
+		}
+
+		query, params, err := s.bindSavedQuery(c.Param("name"), args)
+		if err != nil {
+			c.JSON(savedQueryErrorStatus(err), gin.H{"error": fmt.Sprintf("Failed to subscribe to saved query: %v", err)})
+			return
+		}
+		if err := s.checkFreeForm(c.Request.Context()); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to subscribe to saved query", err)
+			return
+		}
+		if err := s.checkQueryPolicy(c.Request.Context(), "subscription", query.SQL, params); err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to subscribe to saved query", err)
+			return
+		}
+
+		conn, err := subscriptionUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// The upgrader has already replied
+			log.Printf("Warning: Failed to upgrade subscription to %s: %v", query.Name, err)
+			return
+		}
+		defer conn.Close()
+
+		// The query runs in the request's context, which carries the
+		// caller's tag and claims; it ends when the client goes away
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		sub := &subscription{conn: conn, key: c.Query("key")}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			rows, err := s.executeQuery(ctx, "subscription", query.SQL, params)
+			if ctx.Err() != nil {
+				return
+			}
+			if err := sub.push(rows, err); err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.ctx.Done():
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "server stopping"), time.Now().Add(time.Second))
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// subscription tracks the last result pushed to a subscriber
+type subscription struct {
+	conn    *websocket.Conn
+	key     string
+	rows    []map[string]interface{}
+	started bool
+}
+
+// push sends the first result in full and later results as diffs against
+// the last one sent. Unchanged results and failed refreshes do not replace
+// the last result.
+func (sub *subscription) push(rows []map[string]interface{}, err error) error {
+	msg := subscriptionMessage{Time: time.Now().UTC()}
+	switch {
+	case err != nil:
+		msg.Type, msg.Error = subscriptionError, err.Error()
+	case !sub.started:
+		msg.Type, msg.Rows = subscriptionSnapshot, rows
+		if msg.Rows == nil {
+			msg.Rows = []map[string]interface{}{}
+		}
+		sub.rows, sub.started = rows, true
+	default:
+		msg.Type = subscriptionDiff
+		msg.Added, msg.Updated, msg.Removed = diffRows(sub.rows, rows, sub.key)
+		if len(msg.Added)+len(msg.Updated)+len(msg.Removed) == 0 {
+			return nil
+		}
+		sub.rows = rows
+	}
+
+	_ = sub.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout))
+	return sub.conn.WriteJSON(msg)
+}
+
+// diffRows compares two results. Rows are matched by their key column when
+// one is given, otherwise by their whole content, so a changed row shows
+// up as removed and added.
+func diffRows(prev, next []map[string]interface{}, key string) (added, updated, removed []map[string]interface{}) {
+	identity := func(row map[string]interface{}) string {
+		if key != "" {
+			return fmt.Sprint(row[key])
+		}
+		return rowFingerprint(row)
+	}
+
+	// Count identities so duplicate rows without a key are diffed as a
+	// multiset
+	before := make(map[string][]map[string]interface{}, len(prev))
+	for _, row := range prev {
+		id := identity(row)
+		before[id] = append(before[id], row)
+	}
+	for _, row := range next {
+		id := identity(row)
+		matches := before[id]
+		if len(matches) == 0 {
+			added = append(added, row)
+			continue
+		}
+		if key != "" && rowFingerprint(matches[0]) != rowFingerprint(row) {
+			updated = append(updated, row)
+		}
+		before[id] = matches[1:]
+	}
+	for _, row := range prev {
+		id := identity(row)
+		if len(before[id]) > 0 {
+			removed = append(removed, before[id][0])
+			before[id] = before[id][1:]
+		}
+	}
+	return added, updated, removed
+}
+
+// rowFingerprint renders a row canonically; encoding/json sorts map keys
+func rowFingerprint(row map[string]interface{}) string {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Sprint(row)
+	}
+	return string(data)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceConnector returns its results in turn, repeating the last one
+type sequenceConnector struct {
+	rowsConnector
+	mu      sync.Mutex
+	results [][]map[string]interface{}
+}
+
+func (c *sequenceConnector) ExecuteQuery(context.Context, string, map[string]interface{}) ([]map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rows := c.results[0]
+	if len(c.results) > 1 {
+		c.results = c.results[1:]
+	}
+	return rows, nil
+}
+
+func TestSubscription(t *testing.T) {
+	conn := &sequenceConnector{results: [][]map[string]interface{}{
+		{{"ID": 1.0, "STATUS": "open"}, {"ID": 2.0, "STATUS": "open"}},
+		{{"ID": 1.0, "STATUS": "open"}, {"ID": 2.0, "STATUS": "open"}},
+		{{"ID": 1.0, "STATUS": "closed"}, {"ID": 3.0, "STATUS": "open"}},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", Subscriptions: &SubscriptionConfig{MinInterval: "10ms"}},
+		DBConn: conn,
+		saved:  newSavedQueries("sales", nil),
+		ctx:    ctx,
+	}
+	require.NoError(t, s.saved.Create(ctx, &SavedQuery{Name: "open_orders", SQL: "SELECT ID, STATUS FROM ORDERS"}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupSubscriptionRoutes(router.Group(""))
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	base := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	_, resp, err := websocket.DefaultDialer.Dial(base+"/saved/open_orders/subscribe?interval=1ms", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp, err = websocket.DefaultDialer.Dial(base+"/saved/missing/subscribe", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	ws, _, err := websocket.DefaultDialer.Dial(base+"/saved/open_orders/subscribe?interval=10ms&key=ID", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var msg subscriptionMessage
+	require.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, subscriptionSnapshot, msg.Type)
+	assert.Len(t, msg.Rows, 2)
+
+	// The unchanged second result is not pushed
+	msg = subscriptionMessage{}
+	require.NoError(t, ws.ReadJSON(&msg))
+	assert.Equal(t, subscriptionDiff, msg.Type)
+	assert.Equal(t, []map[string]interface{}{{"ID": 3.0, "STATUS": "open"}}, msg.Added)
+	assert.Equal(t, []map[string]interface{}{{"ID": 1.0, "STATUS": "closed"}}, msg.Updated)
+	assert.Equal(t, []map[string]interface{}{{"ID": 2.0, "STATUS": "open"}}, msg.Removed)
+}
+
+func TestDiffRows(t *testing.T) {
+	prev := []map[string]interface{}{{"A": 1}, {"A": 1}, {"A": 2}}
+	next := []map[string]interface{}{{"A": 1}, {"A": 3}}
+	added, updated, removed := diffRows(prev, next, "")
+	assert.Equal(t, []map[string]interface{}{{"A": 3}}, added)
+	assert.Empty(t, updated)
+	assert.Equal(t, []map[string]interface{}{{"A": 1}, {"A": 2}}, removed)
+}
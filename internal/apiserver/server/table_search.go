@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/llm"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/search"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	embeddingBatchSize = 32
+	defaultSearchLimit = 5
+)
+
+// tableSearcher answers natural-language table lookups from an embedding index
+type tableSearcher struct {
+	embedder llm.Embedder
+	index    *search.Index
+
+	// mu serializes index rebuilds
+	mu sync.Mutex
+}
+
+// TableSearchResult is a table matched by semantic search
+type TableSearchResult struct {
+	Table string  `json:"table"`
+	Score float64 `json:"score"`
+	Text  string  `json:"text"`
+}
+
+// setupTableSearchRoutes configures the semantic table search route
+func (s *MCPServerWithDB) setupTableSearchRoutes(router *gin.RouterGroup) {
+	router.GET("/tables/search", func(c *gin.Context) {
+		if s.tableSearch == nil {
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Semantic table search is not configured"})
+			return
+		}
+
+		q := c.Query("q")
+		if q == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+			return
+		}
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSearchLimit)))
+
+		if c.Query("refresh") == "true" {
+			if err := s.buildTableIndex(c.Request.Context()); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to index tables: %v", err)})
+				return
+			}
+		}
+
+		results, err := s.searchTables(c.Request.Context(), q, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to search tables: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, results)
+	})
+}
+
+// searchTables returns the tables most relevant to a natural-language description
+func (s *MCPServerWithDB) searchTables(ctx context.Context, q string, limit int) ([]TableSearchResult, error) {
+	if s.tableSearch.index.Len() == 0 {
+		if err := s.buildTableIndex(ctx); err != nil {
+			return nil, fmt.Errorf("failed to index tables: %w", err)
+		}
+	}
+
+	vectors, err := s.tableSearch.embedder.Embed(ctx, []string{q})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedder returned %d vectors for 1 query", len(vectors))
+	}
+
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	matches := s.tableSearch.index.Search(vectors[0], limit)
+	results := make([]TableSearchResult, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, TableSearchResult{Table: m.ID, Score: m.Score, Text: m.Text})
+	}
+	return results, nil
+}
+
+// buildTableIndex embeds the metadata of every table into the search index
+func (s *MCPServerWithDB) buildTableIndex(ctx context.Context) error {
+	s.tableSearch.mu.Lock()
+	defer s.tableSearch.mu.Unlock()
+
+	tables, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	names := make([]string, len(tables))
+	for i, t := range tables {
+		names[i] = t.Name
+	}
+	return s.indexTablesLocked(ctx, names)
+}
+
+// reindexTables updates the search index after tables were added, dropped
+// or altered. An index that was never built is left to be built on first use.
+func (s *MCPServerWithDB) reindexTables(ctx context.Context, changed, dropped []string) error {
+	s.tableSearch.mu.Lock()
+	defer s.tableSearch.mu.Unlock()
+
+	for _, table := range dropped {
+		s.tableSearch.index.Delete(table)
+	}
+	if s.tableSearch.index.Len() == 0 {
+		return nil
+	}
+	return s.indexTablesLocked(ctx, changed)
+}
+
+// indexTablesLocked embeds the metadata of the tables into the search
+// index. The caller must hold the searcher's mutex.
+func (s *MCPServerWithDB) indexTablesLocked(ctx context.Context, tables []string) error {
+	docs := make([]search.Document, 0, len(tables))
+	for _, table := range tables {
+		metadata, err := s.DBConn.GetTableMetadata(ctx, table)
+		if err != nil {
+			return fmt.Errorf("failed to get metadata for table %s: %w", table, err)
+		}
+		docs = append(docs, search.Document{ID: table, Text: tableSearchText(metadata)})
+	}
+
+	for start := 0; start < len(docs); start += embeddingBatchSize {
+		end := start + embeddingBatchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		batch := docs[start:end]
+
+		texts := make([]string, len(batch))
+		for i, d := range batch {
+			texts[i] = d.Text
+		}
+		vectors, err := s.tableSearch.embedder.Embed(ctx, texts)
+		if err != nil {
+			return fmt.Errorf("failed to embed table metadata: %w", err)
+		}
+		if len(vectors) != len(batch) {
+			return fmt.Errorf("failed to embed table metadata: embedder returned %d vectors for %d tables", len(vectors), len(batch))
+		}
+		for i, d := range batch {
+			s.tableSearch.index.Put(d, vectors[i])
+		}
+	}
+
+	return nil
+}
+
+// tableSearchText renders the parts of table metadata worth embedding
+func tableSearchText(metadata *connector.TableMetadata) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Table %s.", metadata.Name)
+	if metadata.Description != "" {
+		fmt.Fprintf(&b, " %s", metadata.Description)
+	}
+	if metadata.VerboseDescription != "" {
+		fmt.Fprintf(&b, " %s", metadata.VerboseDescription)
+	}
+	b.WriteString(" Columns:")
+	for _, col := range metadata.Columns {
+		fmt.Fprintf(&b, " %s (%s)", col.Name, col.Type)
+		if col.VerboseDescription != "" {
+			fmt.Fprintf(&b, ": %s", col.VerboseDescription)
+		} else if col.Description != "" {
+			fmt.Fprintf(&b, ": %s", col.Description)
+		}
+		b.WriteString(";")
+	}
+	return b.String()
+}
+
+// searchTablesTool exposes semantic table search as an MCP tool
+func (s *MCPServerWithDB) searchTablesTool() mcpTool {
+	return mcpTool{
+		Schema: mcp.ToolSchema{
+			Name:        "search_tables",
+			Description: "Find the tables most relevant to a natural-language description of the data you need",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]any{
+					"query": map[string]any{"type": "string", "description": "Description of the data you are looking for"},
+					"limit": map[string]any{"type": "integer", "description": "Maximum number of tables to return (default: 5)"},
+				},
+				Required: []string{"query"},
+			},
+		},
+		Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			q, _ := args["query"].(string)
+			if q == "" {
+				return nil, fmt.Errorf("query is required")
+			}
+			limit := defaultSearchLimit
+			if v, ok := args["limit"].(float64); ok {
+				limit = int(v)
+			}
+			results, err := s.searchTables(ctx, q, limit)
+			if err != nil {
+				return nil, err
+			}
+			return jsonToolResult(results)
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/search"
+)
+
+// keywordEmbedder embeds texts as counts of a few keywords, leaving out
+// the last drop vectors
+type keywordEmbedder struct {
+	drop int
+}
+
+func (e *keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		vectors = append(vectors, []float32{
+			float32(strings.Count(text, "order")),
+			float32(strings.Count(text, "customer")),
+			float32(strings.Count(text, "invoice")),
+		})
+	}
+	if e.drop > len(vectors) {
+		e.drop = len(vectors)
+	}
+	return vectors[:len(vectors)-e.drop], nil
+}
+
+// catalogConnector describes a few commented tables
+type catalogConnector struct {
+	connector.DatabaseConnector
+	comments map[string]string
+}
+
+func (c *catalogConnector) ListTables(context.Context) ([]connector.Table, error) {
+	var tables []connector.Table
+	for name := range c.comments {
+		tables = append(tables, connector.Table{Name: name})
+	}
+	return tables, nil
+}
+
+func (c *catalogConnector) GetTableMetadata(_ context.Context, table string) (*connector.TableMetadata, error) {
+	return &connector.TableMetadata{Name: table, Description: c.comments[table], Columns: []connector.Column{{Name: "ID", Type: "NUMBER"}}}, nil
+}
+
+func TestSearchTables(t *testing.T) {
+	embedder := &keywordEmbedder{}
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales"},
+		DBConn: &catalogConnector{comments: map[string]string{
+			"SALES":   "Every order placed by a customer",
+			"CLIENTS": "Customer accounts; a customer has many contacts",
+			"BILLING": "Invoice headers of each order",
+		}},
+		tableSearch: &tableSearcher{embedder: embedder, index: search.NewIndex()},
+	}
+
+	// The index is built on first use and ranks by similarity
+	results, err := s.searchTables(context.Background(), "which customer placed an order", 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "SALES", results[0].Table)
+	assert.Equal(t, "CLIENTS", results[1].Table)
+	assert.Contains(t, results[0].Text, "Every order placed by a customer")
+
+	results, err = s.searchTables(context.Background(), "unpaid invoices", 0)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "BILLING", results[0].Table)
+
+	// Embedders returning too few vectors fail instead of indexing partially
+	embedder.drop = 1
+	_, err = s.searchTables(context.Background(), "orders", 1)
+	assert.EqualError(t, err, "embedder returned 0 vectors for 1 query")
+	assert.EqualError(t, s.buildTableIndex(context.Background()), "failed to embed table metadata: embedder returned 2 vectors for 3 tables")
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	// statsSampleRows is the number of rows sampled for the columns the
+	// database keeps no statistics for
+	statsSampleRows = 10000
+
+	// statsTTL is how long computed statistics are served before they are
+	// computed again
+	statsTTL = 10 * time.Minute
+)
+
+// TableStatistics summarizes the columns of a table. Columns come from the
+// statistics the database maintains when it has them, and are computed
+// from a sample of the table otherwise.
+type TableStatistics struct {
+	Table    string                  `json:"table"`
+	RowCount int64                   `json:"row_count"`
+	Columns  []connector.ColumnStats `json:"columns"`
+
+	// SampleRows is the number of rows the sampled columns were computed from
+	SampleRows int64 `json:"sample_rows,omitempty"`
+
+	// AsOf is when the oldest of the statistics was current, and
+	// AgeSeconds the time since
+	AsOf       time.Time `json:"as_of"`
+	AgeSeconds int64     `json:"age_seconds"`
+
+	// Cached reports statistics computed by an earlier request
+	Cached bool `json:"cached"`
+
+	computedAt time.Time
+}
+
+// statsCache keeps computed statistics by table for statsTTL
+type statsCache struct {
+	mu      sync.Mutex
+	entries map[string]*TableStatistics
+}
+
+func newStatsCache() *statsCache {
+	return &statsCache{entries: make(map[string]*TableStatistics)}
+}
+
+func (sc *statsCache) get(table string) (*TableStatistics, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	stats, ok := sc.entries[strings.ToUpper(table)]
+	if !ok || time.Since(stats.computedAt) > statsTTL {
+		return nil, false
+	}
+	return stats, true
+}
+
+func (sc *statsCache) put(stats *TableStatistics) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.entries[strings.ToUpper(stats.Table)] = stats
+}
+
+// tableStatistics returns the statistics of a table, from the cache unless
+// refresh is set
+func (s *MCPServerWithDB) tableStatistics(ctx context.Context, table string, refresh bool) (*TableStatistics, error) {
+	if err := s.checkTableName(ctx, table); err != nil {
+		return nil, err
+	}
+	// Statistics cover the whole table, which row filters cannot restrict
+	if err := s.checkFreeForm(ctx); err != nil {
+		return nil, err
+	}
+	if !refresh {
+		if cached, ok := s.tableStats.get(table); ok {
+			stats := *cached
+			stats.Cached = true
+			stats.AgeSeconds = int64(time.Since(stats.AsOf).Seconds())
+			return &stats, nil
+		}
+	}
+
+	stats, err := s.computeTableStatistics(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	s.tableStats.put(stats)
+	result := *stats
+	result.AgeSeconds = int64(time.Since(result.AsOf).Seconds())
+	return &result, nil
+}
+
+// computeTableStatistics reads the statistics the database maintains and
+// samples the table for the columns they leave out
+func (s *MCPServerWithDB) computeTableStatistics(ctx context.Context, table string) (*TableStatistics, error) {
+	metadata, err := s.DBConn.GetTableMetadata(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	stats := &TableStatistics{Table: table, RowCount: int64(metadata.RowCount), AsOf: now, computedAt: now}
+
+	byColumn := make(map[string]connector.ColumnStats, len(metadata.Columns))
+	if provider, ok := s.DBConn.(connector.StatisticsProvider); ok {
+		maintained, err := provider.TableStatistics(ctx, table, metadata.Columns)
+		if err != nil {
+			log.Printf("Warning: Failed to read statistics of table %s, sampling it: %v", table, err)
+		} else {
+			stats.RowCount, stats.AsOf = maintained.RowCount, maintained.AsOf
+			for _, col := range maintained.Columns {
+				byColumn[col.Column] = col
+			}
+		}
+	}
+
+	var sampled []connector.Column
+	for _, col := range metadata.Columns {
+		if _, ok := byColumn[col.Name]; !ok {
+			sampled = append(sampled, col)
+		}
+	}
+	if len(sampled) > 0 {
+		columns, rows, err := s.sampleColumnStats(ctx, table, sampled)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range columns {
+			byColumn[col.Column] = col
+		}
+		stats.SampleRows = rows
+	}
+
+	stats.Columns = make([]connector.ColumnStats, 0, len(metadata.Columns))
+	for _, col := range metadata.Columns {
+		stats.Columns = append(stats.Columns, byColumn[col.Name])
+	}
+	return stats, nil
+}
+
+// sampleStatsQuery renders the query computing the statistics of columns
+// over a sample of a table's rows
+func sampleStatsQuery(d connector.Dialect, table string, columns []connector.Column) string {
+	names := make([]string, len(columns))
+	selects := []string{"COUNT(*) AS " + d.QuoteIdentifier("SAMPLE_ROWS")}
+	for i, col := range columns {
+		name := d.QuoteIdentifier(col.Name)
+		names[i] = name
+		selects = append(selects, fmt.Sprintf("COUNT(%s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("NONNULL_%d", i))))
+		if connector.IsOrderable(col) {
+			selects = append(selects,
+				fmt.Sprintf("COUNT(DISTINCT %s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("DISTINCT_%d", i))),
+				fmt.Sprintf("MIN(%s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("MIN_%d", i))),
+				fmt.Sprintf("MAX(%s) AS %s", name, d.QuoteIdentifier(fmt.Sprintf("MAX_%d", i))))
+		}
+	}
+	sample := fmt.Sprintf("SELECT %s FROM %s ", strings.Join(names, ", "), d.Table(table))
+	if sampler, ok := d.(connector.RowSampler); ok {
+		sample += sampler.SampleRows(statsSampleRows)
+	} else {
+		sample += d.LimitOffset(strconv.Itoa(statsSampleRows), "")
+	}
+	return fmt.Sprintf("SELECT %s FROM (%s) sampled", strings.Join(selects, ", "), sample)
+}
+
+// sampleColumnStats computes the statistics of columns over a sample of a
+// table's rows, returning them with the number of rows sampled
+func (s *MCPServerWithDB) sampleColumnStats(ctx context.Context, table string, columns []connector.Column) ([]connector.ColumnStats, int64, error) {
+	query := sampleStatsQuery(connector.DialectOf(s.DBConn), table, columns)
+	if err := s.checkQueryPolicy(ctx, "stats", query, nil); err != nil {
+		return nil, 0, err
+	}
+	rows, err := s.executeQuery(ctx, "stats", query, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) != 1 {
+		return nil, 0, fmt.Errorf("expected one row, got %d", len(rows))
+	}
+	row := rows[0]
+	sampleRows, err := countValue(row["SAMPLE_ROWS"])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stats := make([]connector.ColumnStats, len(columns))
+	for i, col := range columns {
+		stats[i] = connector.ColumnStats{Column: col.Name, Source: connector.StatsSourceSample}
+		if sampleRows > 0 {
+			if nonNull, err := countValue(row[fmt.Sprintf("NONNULL_%d", i)]); err == nil {
+				fraction := 1 - float64(nonNull)/float64(sampleRows)
+				stats[i].NullFraction = &fraction
+			}
+		}
+		if !connector.IsOrderable(col) {
+			continue
+		}
+		if distinct, err := countValue(row[fmt.Sprintf("DISTINCT_%d", i)]); err == nil {
+			stats[i].Distinct = &distinct
+		}
+		stats[i].Min, stats[i].Max = row[fmt.Sprintf("MIN_%d", i)], row[fmt.Sprintf("MAX_%d", i)]
+	}
+	return stats, sampleRows, nil
+}
+
+// setupTableStatsRoutes configures the column statistics route
+func (s *MCPServerWithDB) setupTableStatsRoutes(router *gin.RouterGroup) {
+	// Statistics are cached for statsTTL; ?refresh=true computes them again
+	router.GET("/tables/:tableName/stats", func(c *gin.Context) {
+		stats, err := s.tableStatistics(c.Request.Context(), c.Param("tableName"), c.Query("refresh") == "true")
+		if err != nil {
+			s.respondError(c, queryErrorStatus(err), "Failed to get table statistics", err)
+			return
+		}
+		c.JSON(http.StatusOK, stats)
+	})
+}
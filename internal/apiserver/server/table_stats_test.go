@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// statsConnector samples a table of 100 rows and counts its queries
+type statsConnector struct {
+	connector.DatabaseConnector
+	queries []string
+}
+
+func (c *statsConnector) ListTables(context.Context) ([]connector.Table, error) {
+	return []connector.Table{{Name: "ORDERS", RowCount: 100}}, nil
+}
+
+func (c *statsConnector) GetTableMetadata(context.Context, string) (*connector.TableMetadata, error) {
+	return &connector.TableMetadata{Name: "ORDERS", RowCount: 100, Columns: []connector.Column{
+		{Name: "TOTAL", Type: "NUMBER"},
+		{Name: "STATUS", Type: "TEXT"},
+		{Name: "PAYLOAD", Type: "VARIANT"},
+	}}, nil
+}
+
+func (c *statsConnector) ExecuteQuery(_ context.Context, query string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+	c.queries = append(c.queries, query)
+	return []map[string]interface{}{{
+		"SAMPLE_ROWS": 100.0, "NONNULL_0": 90.0, "DISTINCT_0": 3.0, "MIN_0": "closed", "MAX_0": "open", "NONNULL_1": 100.0,
+	}}, nil
+}
+
+// statisticsConnector also keeps statistics for numeric columns
+type statisticsConnector struct {
+	statsConnector
+	asOf time.Time
+}
+
+func (c *statisticsConnector) TableStatistics(context.Context, string, []connector.Column) (*connector.TableStats, error) {
+	fraction := 0.0
+	return &connector.TableStats{RowCount: 120, AsOf: c.asOf, Columns: []connector.ColumnStats{
+		{Column: "TOTAL", Source: connector.StatsSourceStatistics, NullFraction: &fraction, Min: 1.0, Max: 99.0},
+	}}, nil
+}
+
+func TestTableStatistics(t *testing.T) {
+	asOf := time.Now().Add(-time.Hour).UTC()
+	conn := &statisticsConnector{asOf: asOf}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, tableStats: newStatsCache()}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupTableStatsRoutes(router.Group(""))
+	get := func(target string) *TableStatistics {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var stats TableStatistics
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		return &stats
+	}
+
+	stats := get("/tables/ORDERS/stats")
+	assert.False(t, stats.Cached)
+	assert.EqualValues(t, 120, stats.RowCount)
+	assert.EqualValues(t, 100, stats.SampleRows)
+	assert.WithinDuration(t, asOf, stats.AsOf, time.Second)
+	assert.InDelta(t, time.Hour.Seconds(), float64(stats.AgeSeconds), 5)
+	require.Len(t, stats.Columns, 3)
+	assert.Equal(t, connector.StatsSourceStatistics, stats.Columns[0].Source)
+	assert.Equal(t, 99.0, stats.Columns[0].Max)
+
+	// Only the columns without statistics are sampled
+	require.Len(t, conn.queries, 1)
+	assert.Equal(t, `SELECT COUNT(*) AS "SAMPLE_ROWS", COUNT("STATUS") AS "NONNULL_0", COUNT(DISTINCT "STATUS") AS "DISTINCT_0", `+
+		`MIN("STATUS") AS "MIN_0", MAX("STATUS") AS "MAX_0", COUNT("PAYLOAD") AS "NONNULL_1" `+
+		`FROM (SELECT "STATUS", "PAYLOAD" FROM "ORDERS" LIMIT 10000) sampled`, conn.queries[0])
+	assert.Equal(t, connector.StatsSourceSample, stats.Columns[1].Source)
+	assert.InDelta(t, 0.1, *stats.Columns[1].NullFraction, 1e-9)
+	assert.EqualValues(t, 3, *stats.Columns[1].Distinct)
+	assert.Equal(t, "open", stats.Columns[1].Max)
+	assert.Nil(t, stats.Columns[2].Distinct)
+
+	// Statistics are cached until refreshed
+	assert.True(t, get("/tables/ORDERS/stats").Cached)
+	assert.Len(t, conn.queries, 1)
+	assert.False(t, get("/tables/ORDERS/stats?refresh=true").Cached)
+	assert.Len(t, conn.queries, 2)
+
+	// Without database statistics every column is sampled
+	s.DBConn = &statsConnector{}
+	s.tableStats = newStatsCache()
+	stats = get("/tables/ORDERS/stats")
+	assert.EqualValues(t, 100, stats.RowCount)
+	assert.Equal(t, connector.StatsSourceSample, stats.Columns[0].Source)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tables/CUSTOMERS/stats", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
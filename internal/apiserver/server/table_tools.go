@@ -0,0 +1,324 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const defaultTableToolLimit = 100
+
+var toolNameUnsafe = regexp.MustCompile(`[^a-z0-9_]+`)
+
+var integerTypes = map[string]bool{
+	"INT": true, "INTEGER": true, "BIGINT": true, "SMALLINT": true, "TINYINT": true, "BYTEINT": true,
+	"MEDIUMINT": true, "INT2": true, "INT4": true, "INT8": true, "SERIAL": true, "BIGSERIAL": true,
+}
+
+// tableTools returns the per-table MCP tools, building them from table
+// metadata on first use
+func (s *MCPServerWithDB) tableTools(ctx context.Context) []mcpTool {
+	if !s.Config.TableTools || s.DBConn == nil {
+		return nil
+	}
+
+	s.tableToolsMu.Lock()
+	defer s.tableToolsMu.Unlock()
+	if s.tableToolsCache != nil {
+		return s.tableToolsCache
+	}
+
+	tables, err := s.DBConn.ListTables(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to list tables for MCP tools: %v", err)
+		return nil
+	}
+
+	tools := make([]mcpTool, 0, 2*len(tables))
+	for _, t := range tables {
+		metadata, err := s.tableMetadata(ctx, t.Name)
+		if err != nil {
+			log.Printf("Warning: Failed to get metadata for table %s: %v", t.Name, err)
+			continue
+		}
+		if s.Config.EnableLLM {
+			if err := s.DBConn.EnhanceMetadataWithLLM(ctx, metadata); err != nil {
+				log.Printf("Warning: Failed to enhance metadata with LLM: %v", err)
+			}
+		}
+		for _, tool := range s.toolsForTable(s.withCatalog(s.withComputedColumns(metadata))) {
+			tools = append(tools, s.withTableUsage(t.Name, tool))
+		}
+	}
+
+	s.tableToolsCache = tools
+	return tools
+}
+
+// invalidateTableTools drops the cached per-table tools so they are rebuilt
+// from fresh metadata
+func (s *MCPServerWithDB) invalidateTableTools() {
+	s.tableToolsMu.Lock()
+	defer s.tableToolsMu.Unlock()
+	s.tableToolsCache = nil
+}
+
+// tableToolBase returns the suffix of the tool names of a table
+func tableToolBase(table string) string {
+	return toolNameUnsafe.ReplaceAllString(strings.ToLower(table), "_")
+}
+
+// toolsForTable builds the read tools of a table. Their results are always
+// structured content conforming to an output schema derived from the columns.
+func (s *MCPServerWithDB) toolsForTable(metadata *connector.TableMetadata) []mcpTool {
+	table := metadata.Name
+	columns := metadata.Columns
+	outputSchema := rowsOutputSchema(columns)
+	base := tableToolBase(table)
+
+	listName := "list_" + base
+	tools := []mcpTool{
+		{
+			Schema: mcp.ToolSchema{
+				Name:        listName,
+				Description: toolDescription(metadata, connector.OperationList, fmt.Sprintf("List records from the %s table", table)),
+				InputSchema: mcp.ToolInputSchema{
+					Type: "object",
+					Properties: map[string]any{
+						"limit":  map[string]any{"type": "integer", "description": fmt.Sprintf("Number of records to return (default: %d)", defaultTableToolLimit)},
+						"offset": map[string]any{"type": "integer", "description": "Number of records to skip (default: 0)"},
+					},
+				},
+				OutputSchema: outputSchema,
+			},
+			Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+				limit, offset := defaultTableToolLimit, 0
+				if v, ok := args["limit"].(float64); ok && v > 0 {
+					limit = int(v)
+				}
+				if v, ok := args["offset"].(float64); ok && v > 0 {
+					offset = int(v)
+				}
+				params := map[string]interface{}{"limit": limit, "offset": offset}
+				query, err := s.restrictQuery(ctx, table, s.selectComputed(table, connector.SelectPageQuery(connector.DialectOf(s.DBConn), table)), params)
+				if err != nil {
+					return nil, err
+				}
+				rows, err := s.executeTracked(ctx, query, params)
+				if err != nil {
+					return nil, err
+				}
+				rows.Rows = coerceRows(rows.Rows, columns)
+				return s.formatToolRows(ResultFormatStructured, listName, rows)
+			},
+		},
+	}
+
+	keys := connector.PrimaryKeyColumns(columns)
+	if len(keys) == 0 {
+		return tools
+	}
+
+	getName := "get_" + base
+	keyNames := connector.ColumnNames(keys)
+	properties := make(map[string]any, len(keys))
+	for _, key := range keys {
+		keySchema := columnSchema(key)
+		keySchema["description"] = fmt.Sprintf("%s of the %s record", key.Name, table)
+		delete(keySchema, "format")
+		properties[key.Name] = keySchema
+	}
+	tools = append(tools, mcpTool{
+		Schema: mcp.ToolSchema{
+			Name:        getName,
+			Description: toolDescription(metadata, connector.OperationGet, fmt.Sprintf("Get a record from the %s table by %s", table, strings.Join(keyNames, " and "))),
+			InputSchema: mcp.ToolInputSchema{
+				Type:       "object",
+				Properties: properties,
+				Required:   keyNames,
+			},
+			OutputSchema: outputSchema,
+		},
+		Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+			params := make(map[string]interface{}, len(keyNames))
+			for _, name := range keyNames {
+				id, ok := args[name]
+				if !ok {
+					return nil, fmt.Errorf("%s is required", name)
+				}
+				params[connector.ParamName(name)] = id
+			}
+			query, err := s.restrictQuery(ctx, table, s.selectComputed(table, connector.SelectByKeyQuery(connector.DialectOf(s.DBConn), table, keyNames...)), params)
+			if err != nil {
+				return nil, err
+			}
+			rows, err := s.executeTracked(ctx, query, params)
+			if err != nil {
+				return nil, err
+			}
+			rows.Rows = coerceRows(rows.Rows, columns)
+			return s.formatToolRows(ResultFormatStructured, getName, rows)
+		},
+	})
+	return tools
+}
+
+// toolDescription returns the LLM-generated description of a table operation,
+// falling back to the given default
+func toolDescription(metadata *connector.TableMetadata, operation, fallback string) string {
+	if desc := metadata.ToolDescriptions[operation]; desc != "" {
+		return desc
+	}
+	return fallback
+}
+
+// rowsOutputSchema describes the structured content produced by formatRows
+// for rows of the given columns
+func rowsOutputSchema(columns []connector.Column) map[string]any {
+	properties := make(map[string]any, len(columns))
+	var required []string
+	for _, col := range columns {
+		properties[col.Name] = columnSchema(col)
+		if !col.Nullable {
+			required = append(required, col.Name)
+		}
+	}
+
+	row := map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		row["required"] = required
+	}
+
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"rows":         map[string]any{"type": "array", "items": row},
+			"row_count":    map[string]any{"type": "integer", "description": "Total number of rows in the result"},
+			"truncated":    map[string]any{"type": "boolean", "description": "Whether rows is a preview of a larger result"},
+			"resource_uri": map[string]any{"type": "string", "description": "Resource holding the full result when truncated"},
+		},
+		"required": []string{"rows", "row_count", "truncated"},
+	}
+}
+
+// columnSchema maps a column to a JSON Schema
+func columnSchema(col connector.Column) map[string]any {
+	schema := map[string]any{}
+	jsonType, format := jsonSchemaType(col.Type)
+	if jsonType != "" {
+		if col.Nullable {
+			schema["type"] = []string{jsonType, "null"}
+		} else {
+			schema["type"] = jsonType
+		}
+	}
+	if format != "" {
+		schema["format"] = format
+	}
+	if jsonType == "string" && col.MaxLength > 0 {
+		schema["maxLength"] = col.MaxLength
+	}
+	// Bound numbers by their precision while the bound is exact as a float
+	if (jsonType == "number" || jsonType == "integer") && col.Precision > 0 && col.Precision-col.Scale <= 15 {
+		bound := math.Pow10(col.Precision - col.Scale)
+		schema["exclusiveMaximum"] = bound
+		schema["exclusiveMinimum"] = -bound
+	}
+	if col.VerboseDescription != "" {
+		schema["description"] = col.VerboseDescription
+	} else if col.Description != "" {
+		schema["description"] = col.Description
+	}
+	return schema
+}
+
+// jsonSchemaType maps a database type to a JSON Schema type and format.
+// Semi-structured types map to no type so any JSON value validates.
+func jsonSchemaType(dbType string) (string, string) {
+	t := strings.ToUpper(dbType)
+	if i := strings.IndexByte(t, '('); i >= 0 {
+		t = t[:i]
+	}
+	t = strings.TrimSpace(t)
+
+	switch {
+	case integerTypes[t]:
+		return "integer", ""
+	case t == "NUMBER" || t == "DECIMAL" || t == "NUMERIC" || t == "FLOAT" || t == "DOUBLE" ||
+		t == "DOUBLE PRECISION" || t == "REAL" || strings.HasPrefix(t, "FLOAT"):
+		return "number", ""
+	case t == "BOOLEAN" || t == "BOOL":
+		return "boolean", ""
+	case t == "DATE":
+		return "string", "date"
+	case strings.HasPrefix(t, "TIMESTAMP") || t == "DATETIME":
+		return "string", "date-time"
+	case t == "TIME":
+		return "string", "time"
+	case t == "VARIANT" || t == "OBJECT" || t == "ARRAY" || t == "JSON" || t == "JSONB":
+		return "", ""
+	default:
+		return "string", ""
+	}
+}
+
+// coerceRows converts driver values to the JSON types promised by the output
+// schema, e.g. numeric strings to numbers and timestamps to RFC 3339
+func coerceRows(rows []map[string]interface{}, columns []connector.Column) []map[string]interface{} {
+	types := make(map[string]string, len(columns))
+	for _, col := range columns {
+		jsonType, _ := jsonSchemaType(col.Type)
+		types[col.Name] = jsonType
+	}
+
+	for _, row := range rows {
+		for name, v := range row {
+			row[name] = coerceValue(v, types[name])
+		}
+	}
+	return rows
+}
+
+func coerceValue(v interface{}, jsonType string) interface{} {
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	switch val := v.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		if jsonType == "string" {
+			return val.Format(time.RFC3339Nano)
+		}
+	case string:
+		switch jsonType {
+		case "integer":
+			if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+				return n
+			}
+			if f, err := strconv.ParseFloat(val, 64); err == nil {
+				return f
+			}
+		case "number":
+			if f, err := strconv.ParseFloat(val, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(val); err == nil {
+				return b
+			}
+		}
+	}
+	return v
+}
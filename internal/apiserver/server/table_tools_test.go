@@ -0,0 +1,48 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowsOutputSchema(t *testing.T) {
+	schema := rowsOutputSchema([]connector.Column{
+		{Name: "ID", Type: "NUMBER(38,0)", PrimaryKey: true},
+		{Name: "NAME", Type: "TEXT", Nullable: true, Description: "Customer name"},
+		{Name: "CREATED_AT", Type: "TIMESTAMP_NTZ"},
+		{Name: "ATTRS", Type: "VARIANT", Nullable: true},
+	})
+
+	rows := schema["properties"].(map[string]any)["rows"].(map[string]any)
+	items := rows["items"].(map[string]any)
+	props := items["properties"].(map[string]any)
+
+	assert.Equal(t, "number", props["ID"].(map[string]any)["type"])
+	assert.Equal(t, []string{"string", "null"}, props["NAME"].(map[string]any)["type"])
+	assert.Equal(t, "Customer name", props["NAME"].(map[string]any)["description"])
+	assert.Equal(t, "date-time", props["CREATED_AT"].(map[string]any)["format"])
+	assert.NotContains(t, props["ATTRS"].(map[string]any), "type")
+	assert.Equal(t, []string{"ID", "CREATED_AT"}, items["required"])
+}
+
+func TestCoerceRows(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := coerceRows([]map[string]interface{}{
+		{"ID": "42", "PRICE": []byte("9.5"), "ACTIVE": "true", "CREATED_AT": ts, "NOTE": nil},
+	}, []connector.Column{
+		{Name: "ID", Type: "BIGINT"},
+		{Name: "PRICE", Type: "NUMBER(10,2)"},
+		{Name: "ACTIVE", Type: "BOOLEAN"},
+		{Name: "CREATED_AT", Type: "TIMESTAMP_LTZ"},
+		{Name: "NOTE", Type: "VARCHAR", Nullable: true},
+	})
+
+	assert.Equal(t, int64(42), rows[0]["ID"])
+	assert.Equal(t, 9.5, rows[0]["PRICE"])
+	assert.Equal(t, true, rows[0]["ACTIVE"])
+	assert.Equal(t, "2024-01-02T03:04:05Z", rows[0]["CREATED_AT"])
+	assert.Nil(t, rows[0]["NOTE"])
+}
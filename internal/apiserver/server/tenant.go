@@ -0,0 +1,412 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/core/mcpproxy"
+	"gorm.io/gorm"
+)
+
+// Tenant errors
+var (
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+	ErrInvalidTenant  = errors.New("invalid tenant")
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrTenantPolicy   = errors.New("denied by tenant policy")
+)
+
+// apiKeyPrefix marks gateway-issued tenant API keys
+const apiKeyPrefix = "dbg_"
+
+// tenantNamePattern restricts tenant names to URL-safe slugs
+var tenantNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// principalKey carries the authenticated tenant principal in a request context
+type principalKey struct{}
+
+// apiKeyKey carries the API key that authenticated a request
+type apiKeyKey struct{}
+
+// TenantPolicy limits what a tenant may configure
+type TenantPolicy struct {
+	// MaxServers caps the number of servers of the tenant. Zero means no limit.
+	MaxServers int `json:"max_servers,omitempty"`
+
+	// AllowedDatabaseTypes restricts the database types. Empty allows all.
+	AllowedDatabaseTypes []string `json:"allowed_database_types,omitempty"`
+
+	// DisableLLM rejects servers using LLM features
+	DisableLLM bool `json:"disable_llm,omitempty"`
+}
+
+// Tenant is an isolated workspace with its own servers and API keys
+type Tenant struct {
+	Name      string       `json:"name"`
+	Policy    TenantPolicy `json:"policy"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// APIKey authenticates requests to a tenant's routes. Only a hash of the key
+// is stored; the key itself is returned once, when it is created.
+type APIKey struct {
+	ID        string    `json:"id"`
+	Tenant    string    `json:"tenant"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	Key       string    `json:"key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// tenantRow is the persisted form of a Tenant
+type tenantRow struct {
+	Name      string `gorm:"primaryKey"`
+	Policy    string
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name used by tenantRow
+func (tenantRow) TableName() string {
+	return "tenants"
+}
+
+// apiKeyRow is the persisted form of an APIKey
+type apiKeyRow struct {
+	ID        string `gorm:"primaryKey"`
+	Tenant    string
+	Name      string
+	Prefix    string
+	Hash      string
+	CreatedAt time.Time
+}
+
+// TableName overrides the table name used by apiKeyRow
+func (apiKeyRow) TableName() string {
+	return "tenant_api_keys"
+}
+
+// toAPIKey converts a persisted key, leaving out its hash
+func (r *apiKeyRow) toAPIKey() *APIKey {
+	return &APIKey{
+		ID:        r.ID,
+		Tenant:    r.Tenant,
+		Name:      r.Name,
+		Prefix:    r.Prefix,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// TenantStore persists tenants and their API keys in the gateway's state store
+type TenantStore struct {
+	db *gorm.DB
+}
+
+// NewTenantStore creates a store on top of the state store. The tables are
+// created by the state store migrations.
+func NewTenantStore(db *gorm.DB) *TenantStore {
+	return &TenantStore{
+		db: db,
+	}
+}
+
+// List returns all tenants ordered by name
+func (s *TenantStore) List(ctx context.Context) ([]*Tenant, error) {
+	var rows []tenantRow
+	if err := s.db.WithContext(ctx).Order("name").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	tenants := make([]*Tenant, 0, len(rows))
+	for i := range rows {
+		t, err := rows[i].toTenant()
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, nil
+}
+
+// Get returns a tenant by name
+func (s *TenantStore) Get(ctx context.Context, name string) (*Tenant, error) {
+	var row tenantRow
+	err := s.db.WithContext(ctx).Where("name = ?", name).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+	return row.toTenant()
+}
+
+// Create stores a new tenant
+func (s *TenantStore) Create(ctx context.Context, t *Tenant) error {
+	if !tenantNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("%w: name must be a lowercase slug", ErrInvalidTenant)
+	}
+	if _, err := s.Get(ctx, t.Name); err == nil {
+		return ErrTenantExists
+	} else if !errors.Is(err, ErrTenantNotFound) {
+		return err
+	}
+
+	policy, err := json.Marshal(t.Policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tenant policy: %w", err)
+	}
+	t.CreatedAt = time.Now()
+	row := &tenantRow{Name: t.Name, Policy: string(policy), CreatedAt: t.CreatedAt}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return fmt.Errorf("failed to create tenant: %w", err)
+	}
+	return nil
+}
+
+// UpdatePolicy replaces a tenant's policy
+func (s *TenantStore) UpdatePolicy(ctx context.Context, name string, policy TenantPolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tenant policy: %w", err)
+	}
+	result := s.db.WithContext(ctx).Model(&tenantRow{}).Where("name = ?", name).Update("policy", string(data))
+	if result.Error != nil {
+		return fmt.Errorf("failed to update tenant: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrTenantNotFound
+	}
+	return nil
+}
+
+// Delete removes a tenant and its API keys
+func (s *TenantStore) Delete(ctx context.Context, name string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant = ?", name).Delete(&apiKeyRow{}).Error; err != nil {
+			return fmt.Errorf("failed to delete tenant API keys: %w", err)
+		}
+		result := tx.Where("name = ?", name).Delete(&tenantRow{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete tenant: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTenantNotFound
+		}
+		return nil
+	})
+}
+
+// CreateKey issues a new API key for a tenant. The returned key holds the
+// plaintext secret, which cannot be retrieved again.
+func (s *TenantStore) CreateKey(ctx context.Context, tenant, name string) (*APIKey, error) {
+	if _, err := s.Get(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	row := &apiKeyRow{
+		ID:        uuid.New().String(),
+		Tenant:    tenant,
+		Name:      name,
+		Prefix:    key[:len(apiKeyPrefix)+8],
+		Hash:      hashAPIKey(key),
+		CreatedAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	apiKey := row.toAPIKey()
+	apiKey.Key = key
+	return apiKey, nil
+}
+
+// ListKeys returns a tenant's API keys without their secrets
+func (s *TenantStore) ListKeys(ctx context.Context, tenant string) ([]*APIKey, error) {
+	var rows []apiKeyRow
+	if err := s.db.WithContext(ctx).Where("tenant = ?", tenant).Order("created_at").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	keys := make([]*APIKey, 0, len(rows))
+	for i := range rows {
+		keys = append(keys, rows[i].toAPIKey())
+	}
+	return keys, nil
+}
+
+// DeleteKey revokes a tenant's API key
+func (s *TenantStore) DeleteKey(ctx context.Context, tenant, id string) error {
+	result := s.db.WithContext(ctx).Where("tenant = ? AND id = ?", tenant, id).Delete(&apiKeyRow{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete API key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
+// Authenticate returns the key if it was issued for the tenant
+func (s *TenantStore) Authenticate(ctx context.Context, tenant, key string) (*APIKey, error) {
+	var row apiKeyRow
+	err := s.db.WithContext(ctx).Where("tenant = ? AND hash = ?", tenant, hashAPIKey(key)).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate API key: %w", err)
+	}
+	return row.toAPIKey(), nil
+}
+
+// toTenant decodes a persisted tenant
+func (r *tenantRow) toTenant() (*Tenant, error) {
+	t := &Tenant{Name: r.Name, CreatedAt: r.CreatedAt}
+	if r.Policy != "" {
+		if err := json.Unmarshal([]byte(r.Policy), &t.Policy); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal policy of tenant %s: %w", r.Name, err)
+		}
+	}
+	return t, nil
+}
+
+// hashAPIKey returns the stored form of an API key
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// DeleteTenant stops and removes a tenant's servers, then the tenant itself
+func (r *Registry) DeleteTenant(ctx context.Context, name string, principal string) error {
+	if _, err := r.tenants.Get(ctx, name); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	defs, err := r.store.List(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, def := range defs {
+		if err := r.deleteLocked(ctx, name, def.Name, principal); err != nil {
+			return err
+		}
+	}
+	if err := r.tenants.Delete(ctx, name); err != nil {
+		return err
+	}
+	r.record(ctx, "tenant_delete", principal, name, nil)
+	return nil
+}
+
+// checkTenantPolicy enforces tenant isolation and the tenant's policy on a
+// definition. Operator servers (no tenant) are not restricted.
+func (r *Registry) checkTenantPolicy(ctx context.Context, def *ServerDefinition, creating bool) error {
+	if def.Tenant == "" {
+		return nil
+	}
+	tenant, err := r.tenants.Get(ctx, def.Tenant)
+	if err != nil {
+		return err
+	}
+	if err := validateTenantConfig(def.Config); err != nil {
+		return err
+	}
+
+	policy := tenant.Policy
+	cfg := def.Config
+	if len(policy.AllowedDatabaseTypes) > 0 && cfg.Database != nil {
+		allowed := false
+		for _, t := range policy.AllowedDatabaseTypes {
+			if t == cfg.Database.Type {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: database type %q is not allowed", ErrTenantPolicy, cfg.Database.Type)
+		}
+	}
+	if policy.DisableLLM && (cfg.EnableLLM || cfg.Embedding != nil || (cfg.Database != nil && cfg.Database.LLM != nil)) {
+		return fmt.Errorf("%w: LLM features are disabled", ErrTenantPolicy)
+	}
+	if creating && policy.MaxServers > 0 {
+		n, err := r.store.Count(ctx, def.Tenant)
+		if err != nil {
+			return err
+		}
+		if n >= policy.MaxServers {
+			return fmt.Errorf("%w: tenant is limited to %d servers", ErrTenantPolicy, policy.MaxServers)
+		}
+	}
+	return nil
+}
+
+// hostKeyPath reports whether a database reads its private key from a file
+// of the host
+func hostKeyPath(db *connector.DatabaseConfig) bool {
+	return db != nil && db.Snowflake != nil && db.Snowflake.PrivateKeyPath != ""
+}
+
+// validateTenantConfig rejects settings that would let a tenant reach
+// resources of the host or of other tenants
+func validateTenantConfig(cfg *MCPServerConfig) error {
+	switch {
+	case cfg.State != nil:
+		return fmt.Errorf("%w: tenant servers cannot configure a state store", ErrTenantPolicy)
+	case cfg.APIAddr != "":
+		return fmt.Errorf("%w: tenant servers cannot configure a listen address", ErrTenantPolicy)
+	case cfg.GRPC != nil && cfg.GRPC.Enabled:
+		return fmt.Errorf("%w: tenant servers cannot serve gRPC", ErrTenantPolicy)
+	case cfg.Audit != nil && cfg.Audit.Type != "" && cfg.Audit.Type != "memory":
+		return fmt.Errorf("%w: tenant servers can only use the memory audit recorder", ErrTenantPolicy)
+	case cfg.Prompts != nil && (cfg.Prompts.Dir != "" || len(cfg.Prompts.Templates) > 0):
+		return fmt.Errorf("%w: tenant servers cannot load prompt templates from files", ErrTenantPolicy)
+	case cfg.Snapshots != nil && cfg.Snapshots.Path != "":
+		return fmt.Errorf("%w: tenant servers must keep snapshots in memory", ErrTenantPolicy)
+	case hostKeyPath(cfg.Database):
+		return fmt.Errorf("%w: tenant servers must provide private keys inline", ErrTenantPolicy)
+	case cfg.Lineage != nil && cfg.Lineage.DBTManifest != "":
+		return fmt.Errorf("%w: tenant servers cannot load dbt manifests from files", ErrTenantPolicy)
+	case cfg.ClientTLS != nil && (cfg.ClientTLS.CertFile != "" || cfg.ClientTLS.KeyFile != "" || cfg.ClientTLS.ClientCAFile != ""):
+		return fmt.Errorf("%w: tenant servers cannot load certificates from files", ErrTenantPolicy)
+	}
+	if cfg.ReadReplicas != nil {
+		for _, r := range cfg.ReadReplicas.Databases {
+			if hostKeyPath(r.Database) {
+				return fmt.Errorf("%w: tenant servers must provide private keys of read replicas inline", ErrTenantPolicy)
+			}
+		}
+	}
+	for _, conn := range cfg.Connections {
+		if hostKeyPath(conn.Database) {
+			return fmt.Errorf("%w: tenant servers must provide private keys of connections inline", ErrTenantPolicy)
+		}
+	}
+	for _, q := range cfg.ScheduledQueries {
+		if q.S3 != nil && (q.S3.AccessKeyID == "" || q.S3.SecretAccessKey == "") {
+			return fmt.Errorf("%w: tenant servers must provide S3 credentials inline", ErrTenantPolicy)
+		}
+	}
+	for _, u := range cfg.Upstreams {
+		if u.Type == string(mcpproxy.TypeStdio) {
+			return fmt.Errorf("%w: tenant servers cannot run stdio upstreams", ErrTenantPolicy)
+		}
+	}
+	return nil
+}
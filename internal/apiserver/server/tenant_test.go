@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/state"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	store, err := state.OpenAndMigrate(&state.Config{DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	registry := NewRegistry(NewServerStore(store.DB), nil)
+	defer registry.Shutdown()
+	require.NoError(t, registry.tenants.Create(ctx, &Tenant{Name: "acme", Policy: TenantPolicy{MaxServers: 1}}))
+	require.NoError(t, registry.tenants.Create(ctx, &Tenant{Name: "globex"}))
+	acmeKey, err := registry.tenants.CreateKey(ctx, "acme", "ci")
+	require.NoError(t, err)
+	globexKey, err := registry.tenants.CreateKey(ctx, "globex", "ci")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registry.SetupTenantRoutes(router)
+	call := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/t/acme/admin/servers", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, call("GET", "/t/acme/admin/servers", globexKey.Key, "").Code)
+
+	// Tenants cannot reach host resources through their configuration
+	w := call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"state":{"dsn":"/etc/other.db"}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"grpc":{"enabled":true}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"snapshots":{"tables":["ORDERS"],"path":"/var/lib/gateway/state.db"}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"read_replicas":{"databases":[{"name":"r1","database":{"type":"snowflake","snowflake":{"auth_type":"key_pair","private_key_path":"/etc/ssh/ssh_host_rsa_key"}}}]}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"connections":[{"label":"staging","database":{"type":"snowflake","snowflake":{"auth_type":"key_pair","private_key_path":"/etc/ssh/ssh_host_rsa_key"}}}]}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"lineage":{"dbt_manifest":"/etc/passwd"}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"client_tls":{"client_ca_file":"/etc/gateway/ca.pem"}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"sales","config":{"database":{"type":"none"}}}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = call("POST", "/t/acme/admin/servers", acmeKey.Key, `{"name":"hr","config":{"database":{"type":"none"}}}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Server names are scoped to the tenant
+	_, err = registry.Create(ctx, &ServerDefinition{Tenant: "globex", Name: "sales", Config: &MCPServerConfig{Database: &connector.DatabaseConfig{Type: "none"}}}, "test")
+	require.NoError(t, err)
+	w = call("GET", "/t/globex/admin/servers", globexKey.Key, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"tenant":"globex"`)
+	assert.NotContains(t, w.Body.String(), `"tenant":"acme"`)
+
+	require.NoError(t, registry.DeleteTenant(ctx, "acme", "test"))
+	_, err = registry.Get(ctx, "acme", "sales")
+	assert.ErrorIs(t, err, ErrServerNotFound)
+	_, err = registry.tenants.Authenticate(ctx, "acme", acmeKey.Key)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// defaultTextSearchLimit is the page size of search endpoints
+const defaultTextSearchLimit = 100
+
+// textSearchQuery prepares a search endpoint's query: it requires the
+// search text, fills in the paging defaults and narrows the search to the
+// columns requested with ?columns=
+func (s *MCPServerWithDB) textSearchQuery(endpoint connector.APIEndpoint, params map[string]interface{}) (string, error) {
+	if q, _ := params[connector.SearchParam].(string); strings.TrimSpace(q) == "" {
+		return "", fmt.Errorf("missing search text, set ?%s=", connector.SearchParam)
+	}
+	if _, ok := params["limit"]; !ok {
+		params["limit"] = defaultTextSearchLimit
+	}
+	if _, ok := params["offset"]; !ok {
+		params["offset"] = 0
+	}
+
+	requested, _ := params["columns"].(string)
+	delete(params, "columns")
+	if requested == "" {
+		return endpoint.Query, nil
+	}
+
+	searchable := make(map[string]bool, len(endpoint.SearchColumns))
+	for _, col := range endpoint.SearchColumns {
+		searchable[col] = true
+	}
+	var columns []string
+	for _, col := range strings.Split(requested, ",") {
+		col = strings.TrimSpace(col)
+		if !searchable[col] {
+			return "", fmt.Errorf("column %q is not searchable", col)
+		}
+		columns = append(columns, col)
+	}
+	query := s.selectComputed(endpoint.Table, connector.SearchQuery(connector.DialectOf(s.DBConn), endpoint.Table, columns))
+	if endpoint.Filter != "" {
+		return connector.AddRowFilter(query, endpoint.Filter)
+	}
+	return query, nil
+}
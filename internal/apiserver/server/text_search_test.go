@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestTextSearchEndpoint(t *testing.T) {
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	text := []string{"CUSTOMER", "NOTE"}
+	search := connector.APIEndpoint{
+		Table:         "ORDERS",
+		Method:        http.MethodGet,
+		Path:          "/ORDERS/search",
+		Query:         connector.SearchQuery(connector.ANSIDialect{}, "ORDERS", text),
+		SearchColumns: text,
+	}
+	byID := connector.APIEndpoint{
+		Table:      "ORDERS",
+		Method:     http.MethodGet,
+		Path:       "/ORDERS/{ID}",
+		Query:      connector.SelectByKeyQuery(connector.ANSIDialect{}, "ORDERS", "ID"),
+		Parameters: map[string]interface{}{"ID": "ID of the ORDERS record"},
+	}
+	routes := newRouteManager("/api", s.generatedEndpointHandler)
+	_, err := routes.Apply([]connector.APIEndpoint{search, byID})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.NoRoute(routes.ServeHTTP)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ORDERS/search?q=acme", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, search.Query, conn.query)
+	assert.Equal(t, map[string]interface{}{"q": "acme", "limit": defaultTextSearchLimit, "offset": 0}, conn.params)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ORDERS/search?q=acme&columns=NOTE&limit=5", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, connector.SearchQuery(connector.ANSIDialect{}, "ORDERS", []string{"NOTE"}), conn.query)
+	assert.Equal(t, map[string]interface{}{"q": "acme", "limit": "5", "offset": 0}, conn.params)
+
+	for _, target := range []string{"/api/ORDERS/search", "/api/ORDERS/search?q=acme&columns=ID"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ORDERS/7", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"ID": "7"}, conn.params)
+}
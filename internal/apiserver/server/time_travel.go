@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// timeTravelQuery makes the query of a generated read endpoint read its
+// table as of the at_timestamp or at_offset parameter, reporting whether
+// either was given. Offsets are bound as integers.
+func (s *MCPServerWithDB) timeTravelQuery(endpoint connector.APIEndpoint, query string, params map[string]interface{}) (string, bool, error) {
+	switch endpoint.Operation {
+	case connector.OperationList, connector.OperationGet, connector.OperationSearch:
+	default:
+		return query, false, nil
+	}
+	at, hasAt := params[connector.AtTimestampParam].(string)
+	offset, hasOffset := params[connector.AtOffsetParam].(string)
+	switch {
+	case !hasAt && !hasOffset:
+		return query, false, nil
+	case hasAt && hasOffset:
+		return "", false, fmt.Errorf("%s and %s cannot be combined", connector.AtTimestampParam, connector.AtOffsetParam)
+	case hasAt:
+		if _, err := time.Parse(time.RFC3339, at); err != nil {
+			return "", false, fmt.Errorf("%s must be an RFC 3339 timestamp, got %q", connector.AtTimestampParam, at)
+		}
+	default:
+		n, err := strconv.ParseInt(offset, 10, 64)
+		if err != nil || n > 0 {
+			return "", false, fmt.Errorf("%s must be a negative number of seconds, got %q", connector.AtOffsetParam, offset)
+		}
+		params[connector.AtOffsetParam] = n
+	}
+
+	query, err := connector.TimeTravelQuery(connector.DialectOf(s.DBConn), query, endpoint.Table, hasOffset)
+	if err != nil {
+		return "", false, err
+	}
+	return query, true, nil
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// snowflakeParamsConnector records queries in the Snowflake dialect
+type snowflakeParamsConnector struct {
+	paramsConnector
+}
+
+func (c *snowflakeParamsConnector) Dialect() connector.Dialect {
+	return connector.SnowflakeDialect{Database: "DB", Schema: "PUBLIC"}
+}
+
+func TestTimeTravelEndpoints(t *testing.T) {
+	conn := &snowflakeParamsConnector{paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{{"ID": int64(1)}}}}}
+	snapshots, err := newSnapshotStore(&SnapshotConfig{Tables: []string{"ORDERS"}})
+	require.NoError(t, err)
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, snapshots: snapshots}
+	require.NoError(t, s.refreshSnapshot(context.Background(), "ORDERS"))
+
+	d := conn.Dialect()
+	routes := newRouteManager("/api", s.generatedEndpointHandler)
+	_, err = routes.Apply([]connector.APIEndpoint{
+		{Table: "ORDERS", Operation: connector.OperationList, Method: http.MethodGet, Path: "/ORDERS",
+			Query: connector.SelectPageQuery(d, "ORDERS"), Params: connector.PageParams()},
+		{Table: "ORDERS", Operation: connector.OperationDelete, Method: http.MethodDelete, Path: "/ORDERS/{ID}",
+			Query: connector.DeleteQuery(d, "ORDERS", "ID")},
+	})
+	require.NoError(t, err)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.NoRoute(routes.ServeHTTP)
+	request := func(method, target string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w.Code
+	}
+
+	// Time travel reads the warehouse even when the table has a snapshot
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/ORDERS?at_timestamp=2026-10-01T12:00:00Z"))
+	assert.Equal(t, `SELECT * FROM "DB"."PUBLIC"."ORDERS" AT(TIMESTAMP => TO_TIMESTAMP_TZ(:at_timestamp)) LIMIT :limit OFFSET :offset`, conn.query)
+	assert.Equal(t, "2026-10-01T12:00:00Z", conn.params["at_timestamp"])
+
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/api/ORDERS?at_offset=-300"))
+	assert.Contains(t, conn.query, "AT(OFFSET => :at_offset)")
+	assert.Equal(t, int64(-300), conn.params["at_offset"])
+
+	for _, target := range []string{
+		"/api/ORDERS?at_timestamp=yesterday",
+		"/api/ORDERS?at_offset=300",
+		"/api/ORDERS?at_timestamp=2026-10-01T12:00:00Z&at_offset=-60",
+	} {
+		assert.Equal(t, http.StatusBadRequest, request(http.MethodGet, target), target)
+	}
+
+	// Writes ignore the parameters
+	require.Equal(t, http.StatusOK, request(http.MethodDelete, "/api/ORDERS/1?at_offset=-60"))
+	assert.Equal(t, `DELETE FROM "DB"."PUBLIC"."ORDERS" WHERE "ID" = :ID`, conn.query)
+}
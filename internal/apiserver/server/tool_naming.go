@@ -0,0 +1,177 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tool name conflict policies
+const (
+	// ConflictSuffix renames later duplicates with a numeric suffix
+	ConflictSuffix = "suffix"
+	// ConflictFirst keeps the first tool and drops later duplicates
+	ConflictFirst = "first"
+	// ConflictError fails tool listing until the conflict is resolved
+	ConflictError = "error"
+)
+
+// sourceDatabase is the source of the server's own tools
+const sourceDatabase = "database"
+
+// toolNamePattern is the set of names MCP clients accept for tools
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ToolNamingConfig controls how MCP tool names are built and how clashes
+// between tools of the database and of upstream servers are resolved
+type ToolNamingConfig struct {
+	// Prefix namespaces the server's own database tools, so clients
+	// connected to several gateways can tell them apart
+	Prefix string `json:"prefix,omitempty"`
+
+	// Separator joins prefixes and upstream namespaces to tool names (default: "_")
+	Separator string `json:"separator,omitempty"`
+
+	// Aliases renames tools, keyed by their namespaced name
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// ConflictPolicy is suffix (default), first or error
+	ConflictPolicy string `json:"conflict_policy,omitempty"`
+}
+
+// ToolConflict reports tools that resolved to the same name
+type ToolConflict struct {
+	Name       string   `json:"name"`
+	Sources    []string `json:"sources"`
+	Resolution string   `json:"resolution"`
+}
+
+// validate checks the naming configuration
+func (c *ToolNamingConfig) validate() error {
+	switch c.ConflictPolicy {
+	case "", ConflictSuffix, ConflictFirst, ConflictError:
+	default:
+		return fmt.Errorf("unsupported conflict policy: %s", c.ConflictPolicy)
+	}
+	if c.Prefix != "" && !toolNamePattern.MatchString(c.Prefix) {
+		return fmt.Errorf("invalid tool prefix: %s", c.Prefix)
+	}
+	if c.Separator != "" && !toolNamePattern.MatchString(c.Separator) {
+		return fmt.Errorf("invalid tool separator: %s", c.Separator)
+	}
+	for from, to := range c.Aliases {
+		if !toolNamePattern.MatchString(to) {
+			return fmt.Errorf("invalid alias for tool %s: %s", from, to)
+		}
+	}
+	return nil
+}
+
+// toolNaming returns the naming configuration with defaults applied
+func (s *MCPServerWithDB) toolNaming() ToolNamingConfig {
+	var cfg ToolNamingConfig
+	if s.Config.ToolNaming != nil {
+		cfg = *s.Config.ToolNaming
+	}
+	if cfg.Separator == "" {
+		cfg.Separator = "_"
+	}
+	if cfg.ConflictPolicy == "" {
+		cfg.ConflictPolicy = ConflictSuffix
+	}
+	return cfg
+}
+
+// namespaced joins a namespace and a tool name
+func (c ToolNamingConfig) namespaced(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + c.Separator + name
+}
+
+// resolveToolNames applies aliases and the conflict policy to tools whose
+// names are already namespaced. Tools keep their order, so with the first
+// policy the database's own tools win over upstream tools.
+func (c ToolNamingConfig) resolveToolNames(tools []mcpTool) ([]mcpTool, []ToolConflict, error) {
+	resolved := make([]mcpTool, 0, len(tools))
+	sources := make(map[string]string, len(tools))
+	taken := make(map[string]bool, len(tools))
+	conflicts := make(map[string]*ToolConflict)
+	var order []string
+
+	for _, tool := range tools {
+		if alias, ok := c.Aliases[tool.Schema.Name]; ok {
+			tool.Schema.Name = alias
+		}
+		source := tool.Source
+		if source == "" {
+			source = sourceDatabase
+		}
+
+		name := tool.Schema.Name
+		if !taken[name] {
+			taken[name] = true
+			sources[name] = source
+			resolved = append(resolved, tool)
+			continue
+		}
+
+		conflict, ok := conflicts[name]
+		if !ok {
+			conflict = &ToolConflict{Name: name, Sources: []string{sources[name]}, Resolution: c.ConflictPolicy}
+			conflicts[name] = conflict
+			order = append(order, name)
+		}
+		conflict.Sources = append(conflict.Sources, source)
+
+		switch c.ConflictPolicy {
+		case ConflictSuffix:
+			for i := 2; ; i++ {
+				candidate := name + c.Separator + strconv.Itoa(i)
+				if !taken[candidate] {
+					taken[candidate] = true
+					tool.Schema.Name = candidate
+					resolved = append(resolved, tool)
+					break
+				}
+			}
+		case ConflictFirst:
+			// The later tool is dropped
+		}
+	}
+
+	list := make([]ToolConflict, 0, len(order))
+	for _, name := range order {
+		list = append(list, *conflicts[name])
+	}
+	if len(list) > 0 && c.ConflictPolicy == ConflictError {
+		names := make([]string, len(list))
+		for i, conflict := range list {
+			names[i] = fmt.Sprintf("%s (%s)", conflict.Name, strings.Join(conflict.Sources, ", "))
+		}
+		return nil, list, fmt.Errorf("conflicting tool names: %s", strings.Join(names, "; "))
+	}
+	return resolved, list, nil
+}
+
+// logToolConflicts reports conflicts that were resolved automatically
+func logToolConflicts(conflicts []ToolConflict) {
+	for _, conflict := range conflicts {
+		log.Printf("Warning: Tool name %s is provided by %s; resolved with policy %s",
+			conflict.Name, strings.Join(conflict.Sources, ", "), conflict.Resolution)
+	}
+}
+
+// setupToolNamingRoutes configures the admin route reporting tool name conflicts
+func (s *MCPServerWithDB) setupToolNamingRoutes(router *gin.RouterGroup) {
+	router.GET("/admin/tools/conflicts", func(c *gin.Context) {
+		_, conflicts, _ := s.toolNaming().resolveToolNames(s.namespacedTools(c.Request.Context()))
+		c.JSON(http.StatusOK, conflicts)
+	})
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+func namedTools(names ...string) []mcpTool {
+	tools := make([]mcpTool, len(names))
+	for i, name := range names {
+		tools[i] = mcpTool{Schema: mcp.ToolSchema{Name: name}}
+	}
+	return tools
+}
+
+func TestResolveToolNames(t *testing.T) {
+	tools := namedTools("query", "list_orders")
+	upstream := namedTools("query", "search")
+	for i := range upstream {
+		upstream[i].Source = "upstream:github"
+	}
+	tools = append(tools, upstream...)
+
+	names := func(tools []mcpTool) []string {
+		var out []string
+		for _, tool := range tools {
+			out = append(out, tool.Schema.Name)
+		}
+		return out
+	}
+
+	naming := ToolNamingConfig{Separator: "_", ConflictPolicy: ConflictSuffix}
+	resolved, conflicts, err := naming.resolveToolNames(tools)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query", "list_orders", "query_2", "search"}, names(resolved))
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, []string{"database", "upstream:github"}, conflicts[0].Sources)
+
+	naming.ConflictPolicy = ConflictFirst
+	resolved, _, err = naming.resolveToolNames(tools)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"query", "list_orders", "search"}, names(resolved))
+
+	naming.ConflictPolicy = ConflictError
+	_, _, err = naming.resolveToolNames(tools)
+	assert.ErrorContains(t, err, "query (database, upstream:github)")
+
+	// Aliases rename tools once their names are distinct
+	naming.Aliases = map[string]string{"search": "github_search"}
+	tools[2].Schema.Name = "gh_query"
+	resolved, conflicts, err = naming.resolveToolNames(tools)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.Equal(t, []string{"query", "list_orders", "gh_query", "github_search"}, names(resolved))
+}
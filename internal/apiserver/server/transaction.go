@@ -0,0 +1,293 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/audit"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultMaxTransactionStatements = 50
+	defaultTransactionTimeout       = 5 * time.Minute
+)
+
+var (
+	// ErrTransactionsUnsupported is returned when the connector cannot run transactions
+	ErrTransactionsUnsupported = errors.New("transactions are not supported by this database")
+
+	// ErrInvalidTransaction is returned for malformed transaction requests
+	ErrInvalidTransaction = errors.New("invalid transaction")
+)
+
+// savepointPattern restricts savepoint names to plain identifiers, since
+// connectors interpolate them into SQL
+var savepointPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]{0,127}$`)
+
+// TransactionConfig limits the transactional execution API
+type TransactionConfig struct {
+	// MaxStatements caps the statements of one transaction (default: 50)
+	MaxStatements int `json:"max_statements,omitempty"`
+
+	// Timeout bounds a whole transaction (default: 5m)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// TransactionStatement is one step of a transaction: a SQL statement, or
+// the creation of or rollback to a savepoint
+type TransactionStatement struct {
+	SQL    string                 `json:"sql,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Savepoint creates a savepoint with this name
+	Savepoint string `json:"savepoint,omitempty"`
+
+	// RollbackTo undoes the statements run since the named savepoint
+	RollbackTo string `json:"rollback_to,omitempty"`
+
+	// OnErrorRollbackTo names a savepoint to roll back to when this
+	// statement fails, continuing the transaction; by default a failed
+	// statement rolls back the whole transaction
+	OnErrorRollbackTo string `json:"on_error_rollback_to,omitempty"`
+}
+
+// StatementResult is the outcome of one transaction step
+type StatementResult struct {
+	Index        int                      `json:"index"`
+	Rows         []map[string]interface{} `json:"rows,omitempty"`
+	Error        string                   `json:"error,omitempty"`
+	Code         string                   `json:"code,omitempty"`
+	RolledBackTo string                   `json:"rolled_back_to,omitempty"`
+}
+
+// TransactionResult is the outcome of a transaction
+type TransactionResult struct {
+	Committed  bool              `json:"committed"`
+	Statements []StatementResult `json:"statements"`
+	Error      string            `json:"error,omitempty"`
+	Code       string            `json:"code,omitempty"`
+}
+
+// transactionLimits returns the configured limits with defaults applied
+func (s *MCPServerWithDB) transactionLimits() (int, time.Duration) {
+	maxStatements, timeout := defaultMaxTransactionStatements, defaultTransactionTimeout
+	if cfg := s.Config.Transactions; cfg != nil {
+		if cfg.MaxStatements > 0 {
+			maxStatements = cfg.MaxStatements
+		}
+		if d, err := time.ParseDuration(cfg.Timeout); err == nil && d > 0 {
+			timeout = d
+		}
+	}
+	return maxStatements, timeout
+}
+
+// validateTransaction checks that every step does exactly one thing and
+// only refers to savepoints created before it
+func validateTransaction(statements []TransactionStatement, maxStatements int) error {
+	if len(statements) == 0 {
+		return fmt.Errorf("%w: no statements", ErrInvalidTransaction)
+	}
+	if len(statements) > maxStatements {
+		return fmt.Errorf("%w: %d statements exceed the limit of %d", ErrInvalidTransaction, len(statements), maxStatements)
+	}
+
+	savepoints := make(map[string]bool)
+	for i, stmt := range statements {
+		set := 0
+		for _, v := range []string{stmt.SQL, stmt.Savepoint, stmt.RollbackTo} {
+			if v != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("%w: statement %d must have exactly one of sql, savepoint or rollback_to", ErrInvalidTransaction, i)
+		}
+		if stmt.Savepoint != "" {
+			if !savepointPattern.MatchString(stmt.Savepoint) {
+				return fmt.Errorf("%w: invalid savepoint name %q", ErrInvalidTransaction, stmt.Savepoint)
+			}
+			savepoints[stmt.Savepoint] = true
+		}
+		for _, name := range []string{stmt.RollbackTo, stmt.OnErrorRollbackTo} {
+			if name != "" && !savepoints[name] {
+				return fmt.Errorf("%w: statement %d refers to unknown savepoint %q", ErrInvalidTransaction, i, name)
+			}
+		}
+	}
+	return nil
+}
+
+// runTransaction runs the statements in one transaction, committing when
+// they all succeed and rolling back otherwise. The returned result
+// describes every step run, also when an error is returned.
+func (s *MCPServerWithDB) runTransaction(ctx context.Context, statements []TransactionStatement) (*TransactionResult, error) {
+	maxStatements, timeout := s.transactionLimits()
+	if err := validateTransaction(statements, maxStatements); err != nil {
+		return nil, err
+	}
+	if err := s.checkFreeForm(ctx); err != nil {
+		return nil, err
+	}
+	for _, st := range statements {
+		if st.SQL == "" {
+			continue
+		}
+		if err := s.checkQueryPolicy(ctx, "transaction", st.SQL, st.Params); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := s.selectedConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transactor, ok := conn.(connector.Transactor)
+	if !ok {
+		return nil, ErrTransactionsUnsupported
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tx, err := transactor.BeginTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TransactionResult{Statements: make([]StatementResult, 0, len(statements))}
+	abort := func(err error) (*TransactionResult, error) {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Printf("Warning: %v", rbErr)
+		}
+		result.Error, result.Code = err.Error(), errorCode(err)
+		return result, err
+	}
+
+	for i, stmt := range statements {
+		step := StatementResult{Index: i}
+		switch {
+		case stmt.Savepoint != "":
+			if err := tx.Savepoint(ctx, stmt.Savepoint); err != nil {
+				return abort(fmt.Errorf("statement %d: %w", i, err))
+			}
+		case stmt.RollbackTo != "":
+			if err := tx.RollbackToSavepoint(ctx, stmt.RollbackTo); err != nil {
+				return abort(fmt.Errorf("statement %d: %w", i, err))
+			}
+			step.RolledBackTo = stmt.RollbackTo
+		default:
+			if err := s.limits.checkParams(stmt.Params); err != nil {
+				return abort(fmt.Errorf("statement %d: %w", i, err))
+			}
+			rows, err := tx.ExecuteQuery(ctx, stmt.SQL, stmt.Params)
+			if err != nil {
+				s.reportQueryError("transaction", stmt.SQL, err)
+				if stmt.OnErrorRollbackTo == "" {
+					result.Statements = append(result.Statements, StatementResult{Index: i, Error: err.Error(), Code: errorCode(err)})
+					return abort(fmt.Errorf("statement %d: %w", i, err))
+				}
+				if err := tx.RollbackToSavepoint(ctx, stmt.OnErrorRollbackTo); err != nil {
+					return abort(fmt.Errorf("statement %d: %w", i, err))
+				}
+				step.Error, step.Code = err.Error(), errorCode(err)
+				step.RolledBackTo = stmt.OnErrorRollbackTo
+			}
+			step.Rows = rows
+		}
+		result.Statements = append(result.Statements, step)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return abort(err)
+	}
+	result.Committed = true
+	return result, nil
+}
+
+// setupTransactionRoutes configures the transactional execution route
+func (s *MCPServerWithDB) setupTransactionRoutes(router *gin.RouterGroup) {
+	router.POST("/transaction", func(c *gin.Context) {
+		var request struct {
+			Statements []TransactionStatement `json:"statements"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid request: %v", err)})
+			return
+		}
+
+		var statements []string
+		for _, stmt := range request.Statements {
+			if stmt.SQL != "" {
+				statements = append(statements, stmt.SQL)
+			}
+		}
+		if s.parkRequest(c, strings.Join(statements, ";\n"), nil, func(ctx context.Context) (interface{}, error) {
+			result, err := s.runTransaction(ctx, request.Statements)
+			if result == nil {
+				return nil, err
+			}
+			return s.publicTransactionResult(result), err
+		}) {
+			return
+		}
+
+		result, err := s.runTransaction(c.Request.Context(), request.Statements)
+		if result != nil && s.Audit != nil {
+			_ = s.Audit.Record(c.Request.Context(), &audit.Event{
+				Time:      time.Now().UTC(),
+				Action:    "transaction",
+				Principal: principalFromContext(c),
+				Resource:  s.Config.Name,
+				Details: map[string]interface{}{
+					"statements": len(request.Statements),
+					"committed":  result.Committed,
+				},
+			})
+		}
+		if err != nil {
+			status := transactionErrorStatus(err)
+			if result == nil {
+				s.respondError(c, status, "Failed to run transaction", err)
+				return
+			}
+			c.JSON(status, s.publicTransactionResult(result))
+			return
+		}
+
+		c.JSON(http.StatusOK, s.publicTransactionResult(result))
+	})
+}
+
+// publicTransactionResult replaces the database error messages of a
+// transaction result as respondError does
+func (s *MCPServerWithDB) publicTransactionResult(result *TransactionResult) *TransactionResult {
+	public := *result
+	public.Error = s.publicMessage(result.Code, result.Error)
+	public.Statements = make([]StatementResult, len(result.Statements))
+	for i, stmt := range result.Statements {
+		stmt.Error = s.publicMessage(stmt.Code, stmt.Error)
+		public.Statements[i] = stmt
+	}
+	return &public
+}
+
+// transactionErrorStatus maps transaction errors to HTTP status codes
+func transactionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidTransaction):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrTransactionsUnsupported), errors.Is(err, connector.ErrSavepointsUnsupported):
+		return http.StatusNotImplemented
+	default:
+		return queryErrorStatus(err)
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// txConnector records the steps of its transactions; statements containing
+// FAIL fail
+type txConnector struct {
+	rowsConnector
+	log []string
+}
+
+func (c *txConnector) BeginTransaction(context.Context) (connector.Transaction, error) {
+	c.log = append(c.log, "BEGIN")
+	return c, nil
+}
+
+func (c *txConnector) ExecuteQuery(_ context.Context, query string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+	if strings.Contains(query, "FAIL") {
+		return nil, errors.New("syntax error")
+	}
+	c.log = append(c.log, query)
+	return c.rows, nil
+}
+
+func (c *txConnector) Savepoint(_ context.Context, name string) error {
+	c.log = append(c.log, "SAVEPOINT "+name)
+	return nil
+}
+
+func (c *txConnector) RollbackToSavepoint(_ context.Context, name string) error {
+	c.log = append(c.log, "ROLLBACK TO "+name)
+	return nil
+}
+
+func (c *txConnector) Commit() error {
+	c.log = append(c.log, "COMMIT")
+	return nil
+}
+
+func (c *txConnector) Rollback() error {
+	c.log = append(c.log, "ROLLBACK")
+	return nil
+}
+
+func TestRunTransaction(t *testing.T) {
+	conn := &txConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+
+	result, err := s.runTransaction(context.Background(), []TransactionStatement{
+		{SQL: "INSERT INTO A"},
+		{Savepoint: "before_b"},
+		{SQL: "INSERT INTO B FAIL", OnErrorRollbackTo: "before_b"},
+		{SQL: "INSERT INTO C"},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, "before_b", result.Statements[2].RolledBackTo)
+	assert.Equal(t, "syntax error", result.Statements[2].Error)
+	assert.Equal(t, []string{"BEGIN", "INSERT INTO A", "SAVEPOINT before_b", "ROLLBACK TO before_b", "INSERT INTO C", "COMMIT"}, conn.log)
+
+	conn.log = nil
+	result, err = s.runTransaction(context.Background(), []TransactionStatement{
+		{SQL: "INSERT INTO A"},
+		{SQL: "INSERT INTO B FAIL"},
+		{SQL: "INSERT INTO C"},
+	})
+	require.Error(t, err)
+	assert.False(t, result.Committed)
+	assert.Len(t, result.Statements, 2)
+	assert.Equal(t, []string{"BEGIN", "INSERT INTO A", "ROLLBACK"}, conn.log)
+}
+
+func TestTransactionRoute(t *testing.T) {
+	s := &MCPServerWithDB{
+		Config: &MCPServerConfig{Name: "sales", Transactions: &TransactionConfig{MaxStatements: 2}},
+		DBConn: &txConnector{},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupTransactionRoutes(router.Group(""))
+
+	for body, status := range map[string]int{
+		`{"statements":[{"sql":"INSERT INTO A"},{"sql":"INSERT INTO B"}]}`: http.StatusOK,
+		`{"statements":[{"sql":"A"},{"sql":"B"},{"sql":"C"}]}`:             http.StatusBadRequest,
+		`{"statements":[]}`:                                                            http.StatusBadRequest,
+		`{"statements":[{"sql":"A","savepoint":"x"}]}`:                                 http.StatusBadRequest,
+		`{"statements":[{"rollback_to":"x"}]}`:                                         http.StatusBadRequest,
+		`{"statements":[{"savepoint":"x; DROP TABLE A"}]}`:                             http.StatusBadRequest,
+		`{"statements":[{"sql":"INSERT INTO A FAIL"}]}`:                                http.StatusInternalServerError,
+		`{"statements":[{"savepoint":"x"},{"sql":"FAIL","on_error_rollback_to":"x"}]}`: http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transaction", strings.NewReader(body)))
+		assert.Equal(t, status, w.Code, body)
+	}
+
+	s.DBConn = &rowsConnector{}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/transaction", strings.NewReader(`{"statements":[{"sql":"A"}]}`)))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// resultKey is the row key through which a field expression hands back its
+// value; expressions cannot refer to it
+const resultKey = "__result"
+
+// ResponseTransformConfig reshapes the rows a generated endpoint returns
+// before they are serialized. Fields are set first, from the rows as the
+// database returned them, then renamed, then dropped.
+type ResponseTransformConfig struct {
+	// Set adds or replaces fields with expressions over the row's columns.
+	// Expressions are text/template pipelines, e.g. `.PRICE` or
+	// `printf "%s %s" .FIRST_NAME .LAST_NAME`, and keep the type of their
+	// value.
+	Set map[string]string `json:"set,omitempty"`
+
+	// Rename maps field names to the names they are returned under
+	Rename map[string]string `json:"rename,omitempty"`
+
+	// Drop lists fields left out of the response
+	Drop []string `json:"drop,omitempty"`
+}
+
+// transformFuncs are the functions available to field expressions in
+// addition to the text/template builtins
+var transformFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"coalesce": func(values ...interface{}) interface{} {
+		for _, v := range values {
+			if v != nil {
+				return v
+			}
+		}
+		return nil
+	},
+}
+
+// rowTransform is a compiled ResponseTransformConfig
+type rowTransform struct {
+	fields []string // sorted names of the set fields
+	set    map[string]*template.Template
+	rename map[string]string
+	drop   []string
+}
+
+// resultCapture receives the value of a field expression
+type resultCapture struct {
+	value interface{}
+}
+
+// Set records the value and renders nothing
+func (c *resultCapture) Set(v interface{}) string {
+	c.value = v
+	return ""
+}
+
+// newRowTransforms compiles the transforms of the generated endpoints,
+// keyed by "METHOD /path" as the endpoints are generated
+func newRowTransforms(configs map[string]ResponseTransformConfig) (map[string]*rowTransform, error) {
+	transforms := make(map[string]*rowTransform, len(configs))
+	for endpoint, cfg := range configs {
+		method, path, ok := strings.Cut(endpoint, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("endpoint %q must be METHOD /path", endpoint)
+		}
+		t := &rowTransform{
+			set:    make(map[string]*template.Template, len(cfg.Set)),
+			rename: cfg.Rename,
+			drop:   cfg.Drop,
+		}
+		for field, expr := range cfg.Set {
+			if strings.Contains(expr, "{{") || strings.Contains(expr, "}}") {
+				return nil, fmt.Errorf("%s: expression of %s must be a pipeline without delimiters", endpoint, field)
+			}
+			tmpl, err := template.New(field).Funcs(transformFuncs).Option("missingkey=zero").
+				Parse(fmt.Sprintf("{{.%s.Set (%s)}}", resultKey, expr))
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid expression of %s: %w", endpoint, field, err)
+			}
+			t.fields = append(t.fields, field)
+			t.set[field] = tmpl
+		}
+		sort.Strings(t.fields)
+		transforms[strings.ToUpper(method)+" "+path] = t
+	}
+	return transforms, nil
+}
+
+// apply returns the transformed copies of rows
+func (t *rowTransform) apply(rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	transformed := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		out := make(map[string]interface{}, len(row)+len(t.fields))
+		for k, v := range row {
+			out[k] = v
+		}
+
+		if len(t.fields) > 0 {
+			scope := make(map[string]interface{}, len(row)+1)
+			for k, v := range row {
+				scope[k] = v
+			}
+			for _, field := range t.fields {
+				capture := &resultCapture{}
+				scope[resultKey] = capture
+				var discard strings.Builder
+				if err := t.set[field].Execute(&discard, scope); err != nil {
+					return nil, fmt.Errorf("failed to evaluate %s: %w", field, err)
+				}
+				out[field] = capture.value
+			}
+		}
+
+		for from, to := range t.rename {
+			if v, ok := out[from]; ok {
+				delete(out, from)
+				out[to] = v
+			}
+		}
+		for _, field := range t.drop {
+			delete(out, field)
+		}
+		transformed[i] = out
+	}
+	return transformed, nil
+}
+
+// transformRows applies the configured transform and the TransformRows
+// hook of a generated endpoint to its rows
+func (s *MCPServerWithDB) transformRows(endpoint connector.APIEndpoint, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	if t := s.transforms[routeKey(endpoint)]; t != nil {
+		var err error
+		if rows, err = t.apply(rows); err != nil {
+			return nil, err
+		}
+	}
+	if s.TransformRows != nil {
+		return s.TransformRows(endpoint, rows)
+	}
+	return rows, nil
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+func TestRowTransforms(t *testing.T) {
+	for endpoint, cfg := range map[string]ResponseTransformConfig{
+		"/ORDERS":     {},
+		"GET /ORDERS": {Set: map[string]string{"X": "{{.ID}}"}},
+		"GET ORDERS":  {},
+	} {
+		_, err := newRowTransforms(map[string]ResponseTransformConfig{endpoint: cfg})
+		assert.Error(t, err, endpoint)
+	}
+	_, err := newRowTransforms(map[string]ResponseTransformConfig{"GET /ORDERS": {Set: map[string]string{"X": "printf ("}}})
+	assert.Error(t, err)
+
+	transforms, err := newRowTransforms(map[string]ResponseTransformConfig{
+		"get /ORDERS/{ID}": {
+			Set: map[string]string{
+				"customer": `printf "%s %s" .FIRST_NAME .LAST_NAME`,
+				"total":    `.TOTAL`,
+				"note":     `coalesce .NOTE "none"`,
+			},
+			Rename: map[string]string{"ID": "id"},
+			Drop:   []string{"FIRST_NAME", "LAST_NAME", "TOTAL"},
+		},
+	})
+	require.NoError(t, err)
+
+	conn := &paramsConnector{rowsConnector: rowsConnector{rows: []map[string]interface{}{
+		{"ID": int64(7), "FIRST_NAME": "Ada", "LAST_NAME": "Lovelace", "TOTAL": 9.5},
+	}}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn, transforms: transforms}
+	s.TransformRows = func(_ connector.APIEndpoint, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+		for _, row := range rows {
+			row["hooked"] = true
+		}
+		return rows, nil
+	}
+	endpoint := connector.APIEndpoint{
+		Table:      "ORDERS",
+		Method:     http.MethodGet,
+		Path:       "/ORDERS/{ID}",
+		Query:      `SELECT * FROM ORDERS WHERE ID = :ID`,
+		Parameters: map[string]interface{}{"ID": "ID of the ORDERS record"},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ORDERS/:ID", s.generatedEndpointHandler(endpoint))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ORDERS/7", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var rows []map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rows))
+	assert.Equal(t, []map[string]interface{}{{
+		"id":       float64(7),
+		"customer": "Ada Lovelace",
+		"total":    9.5,
+		"note":     "none",
+		"hooked":   true,
+	}}, rows)
+	assert.Equal(t, "Ada", conn.rows[0]["FIRST_NAME"])
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uiPage is the web UI: Swagger UI over the generated endpoints, a table
+// browser and a query console. Swagger UI's assets load from unpkg.com.
+//
+//go:embed ui/index.html
+var uiPage []byte
+
+// setupUIRoutes serves the web UI when enabled. The page calls the API
+// with the bearer token entered in it, so it exposes nothing by itself.
+func (s *MCPServerWithDB) setupUIRoutes(router *gin.RouterGroup) {
+	if !s.Config.EnableUI {
+		return
+	}
+	router.GET("/ui", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", uiPage)
+	})
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/common/cnst"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/common/config"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/core/mcpproxy"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/template"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+const (
+	// upstreamToolsTTL is how long an upstream's tool list is reused
+	upstreamToolsTTL = time.Minute
+
+	// upstreamURIScheme prefixes the URIs of re-exposed upstream resources
+	upstreamURIScheme = "upstream://"
+)
+
+// UpstreamConfig registers an upstream MCP server (stdio, sse or
+// streamable-http) whose tools and resources are re-exposed by the gateway
+type UpstreamConfig struct {
+	config.MCPServerConfig
+
+	// Namespace prefixes the upstream's tool names (default: the upstream name)
+	Namespace string `json:"namespace,omitempty"`
+
+	// AllowTools and DenyTools filter the re-exposed tools by glob pattern.
+	// An empty allow list allows every tool not denied.
+	AllowTools []string `json:"allow_tools,omitempty"`
+	DenyTools  []string `json:"deny_tools,omitempty"`
+}
+
+// upstream is a connected upstream MCP server. Transports are not safe for
+// concurrent use, so every operation holds the mutex.
+type upstream struct {
+	cfg       UpstreamConfig
+	namespace string
+	transport mcpproxy.Transport
+
+	mu      sync.Mutex
+	tools   []mcp.ToolSchema
+	toolsAt time.Time
+}
+
+// newUpstreams creates the transports of the configured upstream servers
+func newUpstreams(cfgs []UpstreamConfig) ([]*upstream, error) {
+	upstreams := make([]*upstream, 0, len(cfgs))
+	seen := make(map[string]bool, len(cfgs))
+	for _, cfg := range cfgs {
+		namespace := cfg.Namespace
+		if namespace == "" {
+			namespace = cfg.Name
+		}
+		if namespace == "" {
+			return nil, fmt.Errorf("upstream name or namespace is required")
+		}
+		if seen[namespace] {
+			return nil, fmt.Errorf("duplicate upstream namespace: %s", namespace)
+		}
+		seen[namespace] = true
+
+		transport, err := mcpproxy.NewTransport(cfg.MCPServerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create upstream %s: %w", namespace, err)
+		}
+		upstreams = append(upstreams, &upstream{
+			cfg:       cfg,
+			namespace: namespace,
+			transport: transport,
+		})
+	}
+	return upstreams, nil
+}
+
+// start connects upstreams whose startup policy is onStart
+func (u *upstream) start(ctx context.Context) error {
+	if u.cfg.Policy == cnst.PolicyOnDemand {
+		return nil
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.transport.Start(ctx, template.NewContext())
+}
+
+// stop disconnects the upstream
+func (u *upstream) stop(ctx context.Context) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.transport.Stop(ctx)
+}
+
+// allowed reports whether the policy exposes an upstream tool
+func (u *upstream) allowed(tool string) bool {
+	for _, pattern := range u.cfg.DenyTools {
+		if ok, _ := path.Match(pattern, tool); ok {
+			return false
+		}
+	}
+	if len(u.cfg.AllowTools) == 0 {
+		return true
+	}
+	for _, pattern := range u.cfg.AllowTools {
+		if ok, _ := path.Match(pattern, tool); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchTools returns the upstream's allowed tools, refreshed every upstreamToolsTTL
+func (u *upstream) fetchTools(ctx context.Context) ([]mcp.ToolSchema, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.tools != nil && time.Since(u.toolsAt) < upstreamToolsTTL {
+		return u.tools, nil
+	}
+	tools, err := u.transport.FetchTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]mcp.ToolSchema, 0, len(tools))
+	for _, tool := range tools {
+		if u.allowed(tool.Name) {
+			allowed = append(allowed, tool)
+		}
+	}
+	u.tools, u.toolsAt = allowed, time.Now()
+	return u.tools, nil
+}
+
+// callTool invokes a tool on the upstream under its original name
+func (u *upstream) callTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if !u.allowed(name) {
+		return nil, fmt.Errorf("tool %s is not allowed", name)
+	}
+	arguments, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments: %w", err)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.transport.CallTool(ctx, mcp.CallToolParams{Name: name, Arguments: arguments}, nil)
+}
+
+// upstreamTools returns the tools of every upstream under its namespace.
+// Unreachable upstreams are skipped so they do not hide the other tools.
+func (s *MCPServerWithDB) upstreamTools(ctx context.Context) []mcpTool {
+	var tools []mcpTool
+	naming := s.toolNaming()
+	for _, u := range s.upstreams {
+		schemas, err := u.fetchTools(ctx)
+		if err != nil {
+			log.Printf("Warning: Failed to list tools of upstream %s: %v", u.namespace, err)
+			continue
+		}
+		for _, schema := range schemas {
+			u, name := u, schema.Name
+			schema.Name = naming.namespaced(u.namespace, name)
+			schema.Description = fmt.Sprintf("[%s] %s", u.namespace, schema.Description)
+			tools = append(tools, mcpTool{
+				Schema: schema,
+				Source: "upstream:" + u.namespace,
+				Handler: func(ctx context.Context, _ *mcpSession, args map[string]interface{}) (*mcp.CallToolResult, error) {
+					return u.callTool(ctx, name, args)
+				},
+			})
+		}
+	}
+	return tools
+}
+
+// upstreamResources returns the resources of every upstream, with URIs
+// rewritten to route reads back to the upstream
+func (s *MCPServerWithDB) upstreamResources(ctx context.Context) []mcp.ResourceSchema {
+	var resources []mcp.ResourceSchema
+	for _, u := range s.upstreams {
+		rt, ok := u.transport.(mcpproxy.ResourceTransport)
+		if !ok {
+			continue
+		}
+		u.mu.Lock()
+		list, err := rt.FetchResources(ctx)
+		u.mu.Unlock()
+		if err != nil {
+			log.Printf("Warning: Failed to list resources of upstream %s: %v", u.namespace, err)
+			continue
+		}
+		for _, r := range list {
+			r.URI = upstreamURIScheme + u.namespace + "/" + r.URI
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// readUpstreamResource reads a resource listed by upstreamResources. ok is
+// false when the URI does not belong to an upstream.
+func (s *MCPServerWithDB) readUpstreamResource(ctx context.Context, uri string) (result *mcp.ReadResourceResult, ok bool, err error) {
+	rest, found := strings.CutPrefix(uri, upstreamURIScheme)
+	if !found {
+		return nil, false, nil
+	}
+	namespace, original, found := strings.Cut(rest, "/")
+	if !found {
+		return nil, true, fmt.Errorf("invalid upstream resource URI: %s", uri)
+	}
+
+	for _, u := range s.upstreams {
+		if u.namespace != namespace {
+			continue
+		}
+		rt, isResource := u.transport.(mcpproxy.ResourceTransport)
+		if !isResource {
+			break
+		}
+		u.mu.Lock()
+		result, err := rt.ReadResource(ctx, original)
+		u.mu.Unlock()
+		if err != nil {
+			return nil, true, err
+		}
+		for i := range result.Contents {
+			result.Contents[i].URI = upstreamURIScheme + namespace + "/" + result.Contents[i].URI
+		}
+		return result, true, nil
+	}
+	return nil, true, fmt.Errorf("unknown upstream: %s", namespace)
+}
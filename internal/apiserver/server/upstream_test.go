@@ -0,0 +1,32 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/common/config"
+)
+
+func TestUpstreamToolPolicy(t *testing.T) {
+	upstreams, err := newUpstreams([]UpstreamConfig{{
+		MCPServerConfig: config.MCPServerConfig{Name: "github", Type: "streamable-http", URL: "http://localhost:1/mcp"},
+		AllowTools:      []string{"list_*", "get_issue"},
+		DenyTools:       []string{"list_secrets"},
+	}})
+	require.NoError(t, err)
+	u := upstreams[0]
+	assert.Equal(t, "github", u.namespace)
+
+	assert.True(t, u.allowed("list_issues"))
+	assert.True(t, u.allowed("get_issue"))
+	assert.False(t, u.allowed("list_secrets"))
+	assert.False(t, u.allowed("delete_repo"))
+
+	_, err = newUpstreams([]UpstreamConfig{
+		{MCPServerConfig: config.MCPServerConfig{Name: "a", Type: "sse"}, Namespace: "tools"},
+		{MCPServerConfig: config.MCPServerConfig{Name: "b", Type: "sse"}, Namespace: "tools"},
+	})
+	assert.Error(t, err)
+}
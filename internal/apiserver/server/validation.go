@@ -0,0 +1,250 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// FieldError describes why a field of a request body was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// dateFormats are the layouts accepted for the string formats of jsonSchemaType
+var dateFormats = map[string][]string{
+	"date":      {time.DateOnly},
+	"date-time": {time.RFC3339Nano, "2006-01-02T15:04:05", time.DateTime},
+	"time":      {time.TimeOnly, "15:04:05.999999999"},
+}
+
+// decodeBody decodes a JSON object keeping numbers exact
+func decodeBody(body io.Reader) (map[string]interface{}, error) {
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	var values map[string]interface{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("body must be a JSON object: %w", err)
+	}
+	if values == nil {
+		return nil, fmt.Errorf("body must be a JSON object")
+	}
+	return values, nil
+}
+
+// validateBody checks a request body against the columns of a write
+// endpoint: unknown fields, missing NOT NULL columns, type mismatches and
+// over-length strings are reported per field. It returns the query
+// parameters, with every column bound under its connector.ParamName and
+// values converted for the driver. Inserts only bind the columns present
+// in the body, so the database fills in the others, and NOT NULL columns
+// with a default may be omitted.
+func validateBody(columns []connector.Column, body map[string]interface{}, insert bool) (map[string]interface{}, []FieldError) {
+	var errs []FieldError
+	known := make(map[string]bool, len(columns))
+	params := make(map[string]interface{}, len(columns))
+
+	for _, col := range columns {
+		known[col.Name] = true
+		value, ok := body[col.Name]
+		if !ok && insert {
+			if !col.Nullable && col.Default == "" {
+				errs = append(errs, FieldError{Field: col.Name, Message: "is required"})
+			}
+			continue
+		}
+		if !ok || value == nil {
+			if !col.Nullable {
+				msg := "is required"
+				if ok {
+					msg = "must not be null"
+				}
+				errs = append(errs, FieldError{Field: col.Name, Message: msg})
+			}
+			params[connector.ParamName(col.Name)] = nil
+			continue
+		}
+
+		v, err := checkColumnValue(col, value)
+		if err != nil {
+			errs = append(errs, FieldError{Field: col.Name, Message: err.Error()})
+			continue
+		}
+		params[connector.ParamName(col.Name)] = v
+	}
+
+	for name := range body {
+		if !known[name] {
+			errs = append(errs, FieldError{Field: name, Message: "is not a column of this table"})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return params, errs
+}
+
+// validateParams checks the path and query parameters of a request against
+// an endpoint's parameter definitions: required parameters must be present
+// and values must parse as their type. Missing parameters with a default
+// are set to it. Values are kept as strings, which every driver binds.
+func validateParams(defs []connector.Parameter, params map[string]interface{}) []FieldError {
+	var errs []FieldError
+	for _, def := range defs {
+		if def.In != connector.ParamInPath && def.In != connector.ParamInQuery {
+			continue
+		}
+		value, ok := params[def.Name].(string)
+		if !ok {
+			switch {
+			case def.Default != nil:
+				params[def.Name] = def.Default
+			case def.Required:
+				errs = append(errs, FieldError{Field: def.Name, Message: "is required"})
+			}
+			continue
+		}
+
+		var err error
+		switch def.Type {
+		case connector.ParamTypeInteger:
+			_, err = strconv.ParseInt(value, 10, 64)
+		case connector.ParamTypeNumber:
+			_, err = strconv.ParseFloat(value, 64)
+		case connector.ParamTypeBoolean:
+			_, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			errs = append(errs, FieldError{Field: def.Name, Message: fmt.Sprintf("must be %s %s, got %q", article(def.Type), def.Type, value)})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+// article returns the indefinite article of a type name
+func article(typeName string) string {
+	if strings.ContainsAny(typeName[:1], "aeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+// checkColumnValue checks a JSON value against a column's JSON Schema type
+// and converts it to a driver value
+func checkColumnValue(col connector.Column, value interface{}) (interface{}, error) {
+	jsonType, format := jsonSchemaType(col.Type)
+	switch jsonType {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("must be an integer, got %s", jsonTypeName(value))
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer, got %s", n)
+		}
+		if err := checkDigits(col, n.String()); err != nil {
+			return nil, err
+		}
+		return i, nil
+	case "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("must be a number, got %s", jsonTypeName(value))
+		}
+		if err := checkDigits(col, n.String()); err != nil {
+			return nil, err
+		}
+		// Keep the literal so decimals are not rounded through float64
+		return n.String(), nil
+	case "boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be a boolean, got %s", jsonTypeName(value))
+		}
+		return b, nil
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string, got %s", jsonTypeName(value))
+		}
+		if col.MaxLength > 0 && utf8.RuneCountInString(str) > col.MaxLength {
+			return nil, fmt.Errorf("must be at most %d characters, got %d", col.MaxLength, utf8.RuneCountInString(str))
+		}
+		if layouts := dateFormats[format]; len(layouts) > 0 && !parsesAs(str, layouts) {
+			return nil, fmt.Errorf("must be a %s string", format)
+		}
+		return str, nil
+	default:
+		// Semi-structured columns accept any JSON value, bound as JSON text
+		switch v := value.(type) {
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("must be valid JSON: %v", err)
+			}
+			return string(data), nil
+		case json.Number:
+			return v.String(), nil
+		}
+		return value, nil
+	}
+}
+
+// checkDigits checks a number literal against the precision and scale of a
+// numeric column: the digits before the decimal point must fit in
+// precision - scale and those after it in scale. Exponents are left to the
+// database.
+func checkDigits(col connector.Column, literal string) error {
+	if col.Precision <= 0 || strings.ContainsAny(literal, "eE") {
+		return nil
+	}
+	whole, fraction, _ := strings.Cut(strings.TrimLeft(literal, "-"), ".")
+	whole = strings.TrimLeft(whole, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > col.Scale {
+		if col.Scale == 0 {
+			return fmt.Errorf("must be a whole number, got %s", literal)
+		}
+		return fmt.Errorf("must have at most %d decimal places, got %s", col.Scale, literal)
+	}
+	if digits := col.Precision - col.Scale; len(whole) > digits {
+		return fmt.Errorf("must have at most %d digits before the decimal point, got %s", digits, literal)
+	}
+	return nil
+}
+
+// parsesAs reports whether a string parses with one of the layouts
+func parsesAs(s string, layouts []string) bool {
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeName names the JSON type of a decoded value for error messages
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case json.Number, float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
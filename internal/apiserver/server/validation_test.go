@@ -0,0 +1,226 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+var orderColumns = []connector.Column{
+	{Name: "ID", Type: "NUMBER(38,0)", PrimaryKey: true},
+	{Name: "CUSTOMER", Type: "VARCHAR", MaxLength: 5},
+	{Name: "TOTAL", Type: "NUMBER(10,2)", Nullable: true},
+	{Name: "PAID", Type: "BOOLEAN", Nullable: true},
+	{Name: "ORDERED_AT", Type: "TIMESTAMP_NTZ", Nullable: true},
+	{Name: "ATTRS", Type: "VARIANT", Nullable: true},
+}
+
+func TestValidateBody(t *testing.T) {
+	body, err := decodeBody(strings.NewReader(`{"ID": 7, "CUSTOMER": "ACME", "TOTAL": 10.25, "ATTRS": {"rush": true}}`))
+	require.NoError(t, err)
+	params, errs := validateBody(append([]connector.Column{{Name: "ID", Type: "INTEGER"}}, orderColumns[1:]...), body, false)
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"ID":         int64(7),
+		"CUSTOMER":   "ACME",
+		"TOTAL":      "10.25",
+		"PAID":       nil,
+		"ORDERED_AT": nil,
+		"ATTRS":      `{"rush":true}`,
+	}, params)
+
+	body, err = decodeBody(strings.NewReader(`{"CUSTOMER": "ACME LTD", "TOTAL": "ten", "PAID": 1, "ORDERED_AT": "yesterday", "NOTE": "x"}`))
+	require.NoError(t, err)
+	_, errs = validateBody(orderColumns, body, false)
+	assert.Equal(t, []FieldError{
+		{Field: "CUSTOMER", Message: "must be at most 5 characters, got 8"},
+		{Field: "ID", Message: "is required"},
+		{Field: "NOTE", Message: "is not a column of this table"},
+		{Field: "ORDERED_AT", Message: "must be a date-time string"},
+		{Field: "PAID", Message: "must be a boolean, got number"},
+		{Field: "TOTAL", Message: "must be a number, got string"},
+	}, errs)
+
+	_, err = decodeBody(strings.NewReader(`[1, 2]`))
+	assert.Error(t, err)
+}
+
+func TestCheckDigits(t *testing.T) {
+	price := connector.Column{Name: "PRICE", Type: "NUMBER", Precision: 5, Scale: 2}
+	for literal, msg := range map[string]string{
+		"999.99":  "",
+		"-12.5":   "",
+		"0012.10": "",
+		"1e10":    "",
+		"1000":    "must have at most 3 digits before the decimal point, got 1000",
+		"1.005":   "must have at most 2 decimal places, got 1.005",
+	} {
+		err := checkDigits(price, literal)
+		if msg == "" {
+			assert.NoError(t, err, literal)
+		} else {
+			assert.EqualError(t, err, msg, literal)
+		}
+	}
+
+	_, err := checkColumnValue(connector.Column{Name: "QTY", Type: "NUMBER", Precision: 3}, json.Number("1.5"))
+	assert.EqualError(t, err, "must be a whole number, got 1.5")
+}
+
+func TestGeneratedEndpointValidatesParams(t *testing.T) {
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	endpoint := connector.APIEndpoint{
+		Table:  "ORDERS",
+		Method: http.MethodGet,
+		Path:   "/ORDERS/search",
+		Query:  `SELECT * FROM ORDERS WHERE CUSTOMER = :q LIMIT :limit OFFSET :offset`,
+		Params: connector.SearchParams(),
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ORDERS/search", s.generatedEndpointHandler(endpoint))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ORDERS/search?limit=ten", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp struct {
+		Fields []FieldError `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []FieldError{
+		{Field: "limit", Message: `must be an integer, got "ten"`},
+		{Field: "q", Message: "is required"},
+	}, resp.Fields)
+	assert.Empty(t, conn.query)
+
+	// Defaults fill in missing parameters
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ORDERS/search?q=ACME&offset=20", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"q": "ACME", "limit": connector.DefaultPageSize, "offset": "20"}, conn.params)
+}
+
+func TestGeneratedWriteEndpointValidatesBody(t *testing.T) {
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	endpoint := connector.APIEndpoint{
+		Table:      "ORDERS",
+		Method:     http.MethodPut,
+		Path:       "/ORDERS/{ID}",
+		Query:      `UPDATE ORDERS SET CUSTOMER = :CUSTOMER WHERE ID = :ID`,
+		Parameters: map[string]interface{}{"ID": "ID of the ORDERS record"},
+		Columns:    orderColumns[1:2],
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/ORDERS/:ID", s.generatedEndpointHandler(endpoint))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/ORDERS/7", strings.NewReader(`{"CUSTOMER": 42}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp struct {
+		Fields []FieldError `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []FieldError{{Field: "CUSTOMER", Message: "must be a string, got number"}}, resp.Fields)
+	assert.Empty(t, conn.query)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/ORDERS/7", strings.NewReader(`{"CUSTOMER": "ACME"}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, map[string]interface{}{"ID": "7", "CUSTOMER": "ACME"}, conn.params)
+}
+
+func TestGeneratedInsertSetsProvidedColumns(t *testing.T) {
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	columns := []connector.Column{
+		{Name: "CUSTOMER", Type: "VARCHAR"},
+		{Name: "STATUS", Type: "VARCHAR", Default: "'NEW'"},
+		{Name: "NOTE", Type: "VARCHAR", Nullable: true},
+	}
+	endpoint := connector.APIEndpoint{
+		Table:     "ORDERS",
+		Method:    http.MethodPost,
+		Operation: connector.OperationCreate,
+		Path:      "/ORDERS",
+		Query:     connector.InsertQuery(connector.ANSIDialect{}, "ORDERS", columns),
+		Columns:   columns,
+		Params:    connector.InsertParams(columns),
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/ORDERS", s.generatedEndpointHandler(endpoint))
+
+	// Columns with a default may be omitted; NOT NULL columns without may not
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ORDERS", strings.NewReader(`{"NOTE": "rush"}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp struct {
+		Fields []FieldError `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []FieldError{{Field: "CUSTOMER", Message: "is required"}}, resp.Fields)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ORDERS", strings.NewReader(`{"CUSTOMER": "ACME"}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `INSERT INTO "ORDERS" ("CUSTOMER") VALUES (:CUSTOMER)`, conn.query)
+	assert.Equal(t, map[string]interface{}{"CUSTOMER": "ACME"}, conn.params)
+
+	// Explicit nulls are inserted
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ORDERS", strings.NewReader(`{"CUSTOMER": "ACME", "NOTE": null}`)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `INSERT INTO "ORDERS" ("CUSTOMER", "NOTE") VALUES (:CUSTOMER, :NOTE)`, conn.query)
+}
+
+func TestVersionedUpdateEndpoint(t *testing.T) {
+	conn := &paramsConnector{}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+	columns := []connector.Column{
+		{Name: "ID", Type: "NUMBER", PrimaryKey: true},
+		{Name: "CUSTOMER", Type: "VARCHAR"},
+		{Name: "VERSION", Type: "NUMBER", Nullable: true},
+	}
+	endpoint := connector.APIEndpoint{
+		Table:      "ORDERS",
+		Method:     http.MethodPut,
+		Path:       "/ORDERS/{ID}",
+		Parameters: map[string]interface{}{"ID": "ID of the ORDERS record"},
+		Columns:    columns[1:],
+	}
+	connector.VersionUpdate(connector.ANSIDialect{}, &endpoint, "ORDERS", []string{"ID"}, columns[2], columns)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/ORDERS/:ID", s.generatedEndpointHandler(endpoint))
+	put := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/ORDERS/7", strings.NewReader(body)))
+		return w
+	}
+
+	// The version read by the caller is required
+	w := put(`{"CUSTOMER": "ACME"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"VERSION"`)
+
+	conn.rows = []map[string]interface{}{{"number of rows updated": int64(1)}}
+	w = put(`{"CUSTOMER": "ACME", "VERSION": 3}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `UPDATE "ORDERS" SET "CUSTOMER" = :CUSTOMER, "VERSION" = "VERSION" + 1 WHERE "ID" = :ID AND "VERSION" = :VERSION`, conn.query)
+	assert.Equal(t, "3", conn.params["VERSION"])
+
+	conn.rows = []map[string]interface{}{{"number of rows updated": int64(0)}}
+	w = put(`{"CUSTOMER": "ACME", "VERSION": 3}`)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+const (
+	defaultWarmupQuery   = "SELECT 1"
+	defaultWarmupTimeout = 2 * time.Minute
+	defaultMetadataTTL   = 10 * time.Minute
+)
+
+// WarmupConfig warms a server up on startup, before it reports ready, so
+// that its first requests do not pay for opening sessions, resuming the
+// warehouse and introspecting tables
+type WarmupConfig struct {
+	// Tables whose metadata is preloaded and then served from memory for
+	// MetadataTTL
+	Tables []string `json:"tables,omitempty"`
+
+	// Endpoints generates the endpoints of the Tables that have none yet
+	Endpoints bool `json:"endpoints,omitempty"`
+
+	// Query is run on the primary, every read replica and every labeled
+	// connection (default: SELECT 1)
+	Query string `json:"query,omitempty"`
+
+	// MetadataTTL is how long preloaded metadata is served before it is
+	// loaded again (default: 10m)
+	MetadataTTL string `json:"metadata_ttl,omitempty"`
+
+	// Timeout bounds the warmup; steps left when it passes fail and the
+	// server starts anyway (default: 2m)
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// WarmupStep is the outcome of a step of the warmup
+type WarmupStep struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WarmupReport is the progress of a server's warmup
+type WarmupReport struct {
+	Ready      bool         `json:"ready"`
+	StartedAt  *time.Time   `json:"started_at,omitempty"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	Steps      []WarmupStep `json:"steps,omitempty"`
+}
+
+// cachedMetadata is the metadata of a table and when it was loaded
+type cachedMetadata struct {
+	metadata *connector.TableMetadata
+	loadedAt time.Time
+}
+
+// warmup preloads a server and keeps the metadata of its tables
+type warmup struct {
+	tables    []string
+	endpoints bool
+	query     string
+	ttl       time.Duration
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	report   WarmupReport
+	metadata map[string]cachedMetadata
+}
+
+// newWarmup validates a warmup configuration; nil starts the server cold
+func newWarmup(cfg *WarmupConfig) (*warmup, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	w := &warmup{
+		tables:    cfg.Tables,
+		endpoints: cfg.Endpoints,
+		query:     cfg.Query,
+		ttl:       defaultMetadataTTL,
+		timeout:   defaultWarmupTimeout,
+		metadata:  make(map[string]cachedMetadata),
+	}
+	if w.query == "" {
+		w.query = defaultWarmupQuery
+	}
+	if cfg.MetadataTTL != "" {
+		d, err := time.ParseDuration(cfg.MetadataTTL)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid metadata_ttl: %s", cfg.MetadataTTL)
+		}
+		w.ttl = d
+	}
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout: %s", cfg.Timeout)
+		}
+		w.timeout = d
+	}
+	return w, nil
+}
+
+// preloads reports whether the metadata of a table is kept
+func (w *warmup) preloads(table string) bool {
+	for _, t := range w.tables {
+		if strings.EqualFold(t, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// cached returns a copy of the kept metadata of a table, so callers may
+// change it
+func (w *warmup) cached(table string) (*connector.TableMetadata, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	entry, ok := w.metadata[strings.ToUpper(table)]
+	if !ok || time.Since(entry.loadedAt) > w.ttl {
+		return nil, false
+	}
+	return copyMetadata(entry.metadata), true
+}
+
+func (w *warmup) keep(table string, metadata *connector.TableMetadata) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.metadata[strings.ToUpper(table)] = cachedMetadata{metadata: copyMetadata(metadata), loadedAt: time.Now()}
+}
+
+// forget drops the kept metadata, e.g. when the schema changed
+func (w *warmup) forget() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.metadata = make(map[string]cachedMetadata)
+}
+
+// copyMetadata copies metadata along with its columns and sample rows
+func copyMetadata(metadata *connector.TableMetadata) *connector.TableMetadata {
+	copied := *metadata
+	copied.Columns = append([]connector.Column(nil), metadata.Columns...)
+	copied.SampleData = append([]map[string]interface{}(nil), metadata.SampleData...)
+	return &copied
+}
+
+// tableMetadata returns the metadata of a table, from memory when the
+// warmup preloads it
+func (s *MCPServerWithDB) tableMetadata(ctx context.Context, table string) (*connector.TableMetadata, error) {
+	if s.warmup == nil || !s.warmup.preloads(table) {
+		return s.DBConn.GetTableMetadata(ctx, table)
+	}
+	if metadata, ok := s.warmup.cached(table); ok {
+		return metadata, nil
+	}
+	metadata, err := s.DBConn.GetTableMetadata(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	s.warmup.keep(table, metadata)
+	return metadata, nil
+}
+
+// runWarmup connects and exercises every connection, preloads the metadata
+// of the configured tables, and generates their endpoints and the MCP tools.
+// Failed steps are reported and logged; the server starts without them.
+func (s *MCPServerWithDB) runWarmup() {
+	w := s.warmup
+	ctx, cancel := context.WithTimeout(s.ctx, w.timeout)
+	defer cancel()
+
+	started := time.Now().UTC()
+	w.mu.Lock()
+	w.report = WarmupReport{StartedAt: &started}
+	w.mu.Unlock()
+	step := func(name string, run func() error) {
+		begun := time.Now()
+		err := run()
+		result := WarmupStep{Name: name, DurationMs: time.Since(begun).Milliseconds()}
+		if err != nil {
+			result.Error = err.Error()
+			log.Printf("Warning: Warmup of server %s: %s: %v", s.Config.Name, name, err)
+		}
+		w.mu.Lock()
+		w.report.Steps = append(w.report.Steps, result)
+		w.mu.Unlock()
+	}
+
+	step("connection primary", func() error {
+		_, err := s.DBConn.ExecuteQuery(ctx, w.query, nil)
+		return err
+	})
+	if s.replicas != nil {
+		for _, r := range s.replicas.replicas {
+			step("replica "+r.name, func() error {
+				err := r.ready(ctx)
+				if err == nil {
+					_, err = r.conn.ExecuteQuery(ctx, w.query, nil)
+				}
+				if err != nil {
+					r.fail(err, s.replicas.cooldown)
+				}
+				return err
+			})
+		}
+	}
+	if s.connections != nil {
+		for _, l := range s.connections.order {
+			step("connection "+l.label, func() error {
+				if err := l.ready(ctx); err != nil {
+					return err
+				}
+				_, err := l.conn.ExecuteQuery(ctx, w.query, nil)
+				return err
+			})
+		}
+	}
+
+	for _, table := range w.tables {
+		step("metadata "+table, func() error {
+			metadata, err := s.DBConn.GetTableMetadata(ctx, table)
+			if err == nil {
+				w.keep(table, metadata)
+			}
+			return err
+		})
+	}
+	if w.endpoints && s.routes != nil {
+		step("endpoints", func() error {
+			return s.warmEndpoints(ctx)
+		})
+	}
+	if s.Config.TableTools {
+		step("tools", func() error {
+			s.invalidateTableTools()
+			if s.tableTools(ctx) == nil {
+				return fmt.Errorf("no table tools were built")
+			}
+			return nil
+		})
+	}
+	if cfg := s.Config.GraphQL; cfg != nil && cfg.Enabled && s.routes != nil {
+		step("graphql", func() error {
+			_, err := s.graphQLSchema(ctx)
+			return err
+		})
+	}
+
+	finished := time.Now().UTC()
+	w.mu.Lock()
+	w.report.Ready, w.report.FinishedAt = true, &finished
+	w.mu.Unlock()
+	log.Printf("Server %s warmed up in %s", s.Config.Name, finished.Sub(started).Round(time.Millisecond))
+}
+
+// warmEndpoints generates and registers the endpoints of the preloaded
+// tables that have none, keeping the endpoints already registered
+func (s *MCPServerWithDB) warmEndpoints(ctx context.Context) error {
+	registered := make(map[string]bool)
+	for _, e := range s.routes.Endpoints() {
+		registered[strings.ToUpper(e.Table)] = true
+	}
+	var tables []string
+	for _, table := range s.warmup.tables {
+		if !registered[strings.ToUpper(table)] {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	endpoints, err := s.GenerateEndpoints(ctx, tables)
+	if err != nil {
+		return err
+	}
+	diff, err := s.routes.Apply(endpoints)
+	if err != nil {
+		return err
+	}
+	if !diff.Empty() && s.OnEndpointsGenerated != nil {
+		s.OnEndpointsGenerated(s.routes.Endpoints())
+	}
+	return nil
+}
+
+// warmupReport returns the progress of the warmup; servers without one
+// are ready at once
+func (s *MCPServerWithDB) warmupReport() WarmupReport {
+	if s.warmup == nil {
+		return WarmupReport{Ready: true}
+	}
+	s.warmup.mu.Lock()
+	defer s.warmup.mu.Unlock()
+	report := s.warmup.report
+	report.Steps = append([]WarmupStep(nil), report.Steps...)
+	return report
+}
+
+// setupWarmupRoutes configures the readiness route, which fails until the
+// warmup is done
+func (s *MCPServerWithDB) setupWarmupRoutes(router *gin.RouterGroup) {
+	router.GET("/ready", func(c *gin.Context) {
+		report := s.warmupReport()
+		status := http.StatusOK
+		if !report.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	})
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// warmConnector counts the introspections warmed up requests save
+type warmConnector struct {
+	schemaConnector
+	queries     []string
+	described   int
+	unreachable bool
+}
+
+func (c *warmConnector) ExecuteQuery(_ context.Context, query string, _ map[string]interface{}) ([]map[string]interface{}, error) {
+	if c.unreachable {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	c.queries = append(c.queries, query)
+	return nil, nil
+}
+
+func (c *warmConnector) GetTableMetadata(_ context.Context, table string) (*connector.TableMetadata, error) {
+	c.described++
+	return &connector.TableMetadata{Name: table, Columns: c.schema[table]}, nil
+}
+
+func TestWarmup(t *testing.T) {
+	primary := &warmConnector{schemaConnector: schemaConnector{schema: map[string][]connector.Column{
+		"ORDERS": {{Name: "ID", Type: "NUMBER"}},
+	}}}
+	staging := &warmConnector{unreachable: true}
+	l := &labeledConnection{label: "staging", conn: staging, connected: true}
+	w, err := newWarmup(&WarmupConfig{Tables: []string{"ORDERS"}, Endpoints: true})
+	require.NoError(t, err)
+	var persisted []connector.APIEndpoint
+	s := &MCPServerWithDB{
+		Config:      &MCPServerConfig{Name: "sales"},
+		DBConn:      primary,
+		ctx:         context.Background(),
+		connections: &connectionSet{byLabel: map[string]*labeledConnection{"staging": l}, order: []*labeledConnection{l}},
+		warmup:      w,
+		OnEndpointsGenerated: func(endpoints []connector.APIEndpoint) {
+			persisted = endpoints
+		},
+	}
+	s.routes = newRouteManager("", s.generatedEndpointHandler)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	s.setupWarmupRoutes(router.Group(""))
+	ready := func() (int, WarmupReport) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		var report WarmupReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	code, _ := ready()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	// Every connection is exercised, and failed steps are reported without
+	// holding the server back
+	s.runWarmup()
+	code, report := ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, report.Ready)
+	require.Len(t, report.Steps, 4)
+	assert.Equal(t, "connection primary", report.Steps[0].Name)
+	assert.Equal(t, []string{defaultWarmupQuery}, primary.queries)
+	assert.Equal(t, "connection staging", report.Steps[1].Name)
+	assert.Contains(t, report.Steps[1].Error, "connection refused")
+	assert.Equal(t, "metadata ORDERS", report.Steps[2].Name)
+	assert.Equal(t, "endpoints", report.Steps[3].Name)
+	assert.Empty(t, report.Steps[3].Error)
+	require.Len(t, persisted, 1)
+	assert.Equal(t, "/ORDERS", persisted[0].Path)
+
+	// Preloaded metadata is served from memory, as a copy, until the
+	// schema changes
+	metadata, err := s.tableMetadata(context.Background(), "orders")
+	require.NoError(t, err)
+	metadata.Columns[0].Name = "CHANGED"
+	metadata, err = s.tableMetadata(context.Background(), "ORDERS")
+	require.NoError(t, err)
+	assert.Equal(t, "ID", metadata.Columns[0].Name)
+	assert.Equal(t, 1, primary.described)
+	s.applySchemaChange(context.Background(), &SchemaChange{})
+	_, err = s.tableMetadata(context.Background(), "ORDERS")
+	require.NoError(t, err)
+	assert.Equal(t, 2, primary.described)
+
+	// Other tables are always introspected
+	_, err = s.tableMetadata(context.Background(), "CUSTOMERS")
+	require.NoError(t, err)
+	assert.Equal(t, 3, primary.described)
+}
+
+func TestWarmupConfig(t *testing.T) {
+	w, err := newWarmup(&WarmupConfig{MetadataTTL: "1m", Timeout: "1m"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, w.ttl)
+	assert.Equal(t, time.Minute, w.timeout)
+	assert.Equal(t, defaultWarmupQuery, w.query)
+
+	for _, cfg := range []*WarmupConfig{{MetadataTTL: "soon"}, {Timeout: "-1s"}} {
+		_, err := newWarmup(cfg)
+		assert.Error(t, err)
+	}
+}
+
+func TestWarmupLazyConnection(t *testing.T) {
+	lazy := &connector.DatabaseConfig{Type: "snowflake", Snowflake: &connector.SnowflakeConfig{Lazy: true}}
+	s := &MCPServerWithDB{Config: &MCPServerConfig{Database: lazy}}
+	assert.NoError(t, s.checkLazyConnection())
+
+	// Warming up, or anything else that polls, would keep sessions open
+	s.warmup, _ = newWarmup(&WarmupConfig{})
+	assert.ErrorContains(t, s.checkLazyConnection(), "warmup")
+	s.warmup = nil
+	s.Config.SchemaWatch = &SchemaWatchConfig{}
+	assert.ErrorContains(t, s.checkLazyConnection(), "schema_watch")
+
+	s.Config.Database = &connector.DatabaseConfig{Type: "snowflake", Snowflake: &connector.SnowflakeConfig{}}
+	assert.NoError(t, s.checkLazyConnection())
+}
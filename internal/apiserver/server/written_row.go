@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// returnsRow reports whether a write endpoint responds with the row it
+// wrote, as create and update endpoints knowing the key of their row do
+func returnsRow(endpoint connector.APIEndpoint) bool {
+	return endpoint.Table != "" && len(endpoint.KeyColumns) > 0 &&
+		(endpoint.Operation == connector.OperationCreate || endpoint.Operation == connector.OperationUpdate)
+}
+
+// writtenRow reads back the row written by a create or update endpoint by
+// the key values bound to its query, so that the response carries the
+// persisted row, defaults and computed columns included. Sandboxed writes
+// are read back from the clone. It returns nil when a key value was not
+// bound, e.g. for a key generated by the database.
+func (s *MCPServerWithDB) writtenRow(ctx context.Context, endpoint connector.APIEndpoint, params map[string]interface{}) (*connector.ResultSet, error) {
+	keys := make(map[string]interface{}, len(endpoint.KeyColumns))
+	for _, key := range endpoint.KeyColumns {
+		v, ok := params[connector.ParamName(key)]
+		if !ok || v == nil {
+			return nil, nil
+		}
+		keys[connector.ParamName(key)] = v
+	}
+
+	query := s.selectComputed(endpoint.Table, connector.SelectByKeyQuery(connector.DialectOf(s.DBConn), endpoint.Table, endpoint.KeyColumns...))
+	query, _ = s.sandboxQuery(endpoint, query)
+	query, err := s.restrictQuery(ctx, endpoint.Table, query, keys)
+	if err != nil {
+		return nil, err
+	}
+	return s.executeOrdered(withPrimary(ctx), "generated", query, keys)
+}
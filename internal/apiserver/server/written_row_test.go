@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mcp-ecosystem/mcp-gateway/internal/apiserver/database/connector"
+)
+
+// writesConnector answers writes with the driver's row count and reads
+// with the written row
+type writesConnector struct {
+	connector.DatabaseConnector
+	queries []string
+	params  []map[string]interface{}
+}
+
+func (c *writesConnector) ExecuteQuery(_ context.Context, query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	c.queries = append(c.queries, query)
+	c.params = append(c.params, params)
+	if strings.HasPrefix(query, "SELECT") || strings.Contains(query, "RETURNING") {
+		return []map[string]interface{}{{"ID": 7.0, "CUSTOMER": "ACME", "STATUS": "NEW"}}, nil
+	}
+	return []map[string]interface{}{{"number of rows inserted": 1.0}}, nil
+}
+
+// returningDialect returns the rows written by INSERT and UPDATE
+type returningDialect struct {
+	connector.ANSIDialect
+}
+
+func (returningDialect) Returning() string {
+	return "RETURNING *"
+}
+
+type returningConnector struct {
+	writesConnector
+}
+
+func (c *returningConnector) Dialect() connector.Dialect {
+	return returningDialect{}
+}
+
+func TestWriteEndpointsReturnWrittenRow(t *testing.T) {
+	columns := []connector.Column{
+		{Name: "ID", Type: "NUMBER", PrimaryKey: true},
+		{Name: "CUSTOMER", Type: "VARCHAR"},
+		{Name: "STATUS", Type: "VARCHAR", Default: "'NEW'"},
+	}
+	endpoint := connector.APIEndpoint{
+		Table:      "ORDERS",
+		Method:     http.MethodPost,
+		Operation:  connector.OperationCreate,
+		Path:       "/ORDERS",
+		Query:      connector.InsertQuery(connector.ANSIDialect{}, "ORDERS", columns),
+		Columns:    columns,
+		Params:     connector.InsertParams(columns),
+		KeyColumns: []string{"ID"},
+	}
+	create := func(conn connector.DatabaseConnector, body string) []map[string]interface{} {
+		s := &MCPServerWithDB{Config: &MCPServerConfig{Name: "sales"}, DBConn: conn}
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/ORDERS", s.generatedEndpointHandler(endpoint))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/ORDERS", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var rows []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rows))
+		return rows
+	}
+
+	// The row is read back by its key, defaults included
+	conn := &writesConnector{}
+	rows := create(conn, `{"ID": 7, "CUSTOMER": "ACME"}`)
+	require.Len(t, conn.queries, 2)
+	assert.Equal(t, `INSERT INTO "ORDERS" ("ID", "CUSTOMER") VALUES (:ID, :CUSTOMER)`, conn.queries[0])
+	assert.Equal(t, `SELECT * FROM "ORDERS" WHERE "ID" = :ID`, conn.queries[1])
+	assert.Equal(t, map[string]interface{}{"ID": "7"}, conn.params[1])
+	assert.Equal(t, []map[string]interface{}{{"ID": 7.0, "CUSTOMER": "ACME", "STATUS": "NEW"}}, rows)
+
+	// Dialects returning written rows need no second query
+	returning := &returningConnector{}
+	rows = create(returning, `{"ID": 7, "CUSTOMER": "ACME"}`)
+	assert.Equal(t, []string{`INSERT INTO "ORDERS" ("ID", "CUSTOMER") VALUES (:ID, :CUSTOMER) RETURNING *`}, returning.queries)
+	assert.Equal(t, "NEW", rows[0]["STATUS"])
+
+	// Without the key value the driver's result is returned
+	endpoint.Columns[0].Nullable = true
+	conn = &writesConnector{}
+	rows = create(conn, `{"CUSTOMER": "ACME"}`)
+	assert.Len(t, conn.queries, 1)
+	assert.Equal(t, []map[string]interface{}{{"number of rows inserted": 1.0}}, rows)
+}
@@ -0,0 +1,133 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrStoreNewer is returned when the store was migrated by a newer gateway
+// version; running an older binary against it could corrupt state
+var ErrStoreNewer = errors.New("state store schema is newer than this gateway supports")
+
+// Migration is a single, immutable schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version   int       `json:"version" gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// TableName overrides the table name used by SchemaMigration
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus describes where the store stands relative to this binary
+type MigrationStatus struct {
+	Current int                `json:"current"`
+	Latest  int                `json:"latest"`
+	Applied []SchemaMigration  `json:"applied"`
+	Pending []MigrationSummary `json:"pending"`
+}
+
+// MigrationSummary identifies a migration
+type MigrationSummary struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// latestVersion returns the highest known migration version
+func latestVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Status reports the applied and pending migrations
+func (s *Store) Status() (*MigrationStatus, error) {
+	if err := s.DB.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var applied []SchemaMigration
+	if err := s.DB.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	status := &MigrationStatus{
+		Latest:  latestVersion(),
+		Applied: applied,
+	}
+	done := make(map[int]bool, len(applied))
+	for _, m := range applied {
+		done[m.Version] = true
+		if m.Version > status.Current {
+			status.Current = m.Version
+		}
+	}
+	if status.Current > status.Latest {
+		return status, fmt.Errorf("%w: store is at version %d, latest known is %d", ErrStoreNewer, status.Current, status.Latest)
+	}
+
+	for _, m := range migrations {
+		if !done[m.Version] {
+			status.Pending = append(status.Pending, MigrationSummary{Version: m.Version, Name: m.Name})
+		}
+	}
+	return status, nil
+}
+
+// Migrate applies pending migrations in order, each in its own transaction.
+// With dryRun set nothing is applied and the pending migrations are returned.
+func (s *Store) Migrate(dryRun bool) ([]MigrationSummary, error) {
+	status, err := s.Status()
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(status.Pending) == 0 {
+		return status.Pending, nil
+	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	var applied []MigrationSummary
+	for _, p := range status.Pending {
+		m := byVersion[p.Version]
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		applied = append(applied, p)
+	}
+	return applied, nil
+}
+
+func init() {
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			panic(fmt.Sprintf("duplicate state store migration version %d", migrations[i].Version))
+		}
+	}
+}
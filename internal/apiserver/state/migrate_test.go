@@ -0,0 +1,59 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	store, err := Open(&Config{Type: "sqlite", DSN: filepath.Join(t.TempDir(), "state.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestMigrate(t *testing.T) {
+	store := openTestStore(t)
+
+	pending, err := store.Migrate(true)
+	require.NoError(t, err)
+	assert.Len(t, pending, len(migrations))
+
+	status, err := store.Status()
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.Current)
+
+	applied, err := store.Migrate(false)
+	require.NoError(t, err)
+	assert.Equal(t, pending, applied)
+	assert.True(t, store.DB.Migrator().HasTable("audit_events"))
+
+	applied, err = store.Migrate(false)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	status, err = store.Status()
+	require.NoError(t, err)
+	assert.Equal(t, latestVersion(), status.Current)
+	assert.Empty(t, status.Pending)
+}
+
+func TestMigrateRefusesNewerStore(t *testing.T) {
+	store := openTestStore(t)
+	_, err := store.Migrate(false)
+	require.NoError(t, err)
+
+	require.NoError(t, store.DB.Create(&SchemaMigration{
+		Version:   latestVersion() + 1,
+		Name:      "from_the_future",
+		AppliedAt: time.Now(),
+	}).Error)
+
+	_, err = store.Migrate(false)
+	assert.ErrorIs(t, err, ErrStoreNewer)
+}
@@ -0,0 +1,210 @@
+package state
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrations lists every schema change of the state store. Entries must never
+// be edited or removed once released; add a new version instead. Models are
+// declared inline so later changes to the live types don't rewrite history.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_audit_events",
+		Up: func(tx *gorm.DB) error {
+			type auditEvent struct {
+				ID          uint      `gorm:"primaryKey;autoIncrement"`
+				Time        time.Time `gorm:"index"`
+				Action      string    `gorm:"type:varchar(64);index"`
+				Principal   string    `gorm:"type:varchar(255);index"`
+				Resource    string    `gorm:"type:varchar(255)"`
+				Fingerprint string    `gorm:"type:varchar(128);index"`
+				Details     string    `gorm:"type:text"`
+			}
+			return tx.Table("audit_events").AutoMigrate(&auditEvent{})
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_prompt_templates",
+		Up: func(tx *gorm.DB) error {
+			type promptTemplate struct {
+				ID        uint   `gorm:"primaryKey;autoIncrement"`
+				Name      string `gorm:"type:varchar(128);not null;uniqueIndex:idx_prompt_templates_name_version"`
+				Version   int    `gorm:"not null;uniqueIndex:idx_prompt_templates_name_version"`
+				Text      string `gorm:"type:text;not null"`
+				Comment   string `gorm:"type:varchar(512)"`
+				Author    string `gorm:"type:varchar(255)"`
+				CreatedAt time.Time
+				Active    bool
+			}
+			return tx.Table("prompt_templates").AutoMigrate(&promptTemplate{})
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_eval_tables",
+		Up: func(tx *gorm.DB) error {
+			type evalCase struct {
+				ID             string `gorm:"type:varchar(64);primaryKey"`
+				Connection     string `gorm:"type:varchar(255);index"`
+				Question       string `gorm:"type:text;not null"`
+				ExpectedSQL    string `gorm:"type:text"`
+				ExpectedResult string `gorm:"type:text"`
+				Tags           string `gorm:"type:text"`
+				CreatedAt      time.Time
+			}
+			type evalRun struct {
+				ID            string    `gorm:"type:varchar(64);primaryKey"`
+				Connection    string    `gorm:"type:varchar(255);index"`
+				StartedAt     time.Time `gorm:"index"`
+				FinishedAt    time.Time
+				Total         int
+				Passed        int
+				Accuracy      float64
+				PromptVersion int
+				Results       string `gorm:"type:text"`
+			}
+			if err := tx.Table("eval_cases").AutoMigrate(&evalCase{}); err != nil {
+				return err
+			}
+			return tx.Table("eval_runs").AutoMigrate(&evalRun{})
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create_server_definitions",
+		Up: func(tx *gorm.DB) error {
+			type serverDefinition struct {
+				Name      string `gorm:"type:varchar(255);primaryKey"`
+				Config    string `gorm:"type:text;not null"`
+				Endpoints string `gorm:"type:text"`
+				Running   bool
+				CreatedAt time.Time
+				UpdatedAt time.Time
+			}
+			return tx.Table("server_definitions").AutoMigrate(&serverDefinition{})
+		},
+	},
+	{
+		Version: 5,
+		Name:    "add_tenants",
+		Up: func(tx *gorm.DB) error {
+			type tenant struct {
+				Name      string `gorm:"type:varchar(64);primaryKey"`
+				Policy    string `gorm:"type:text"`
+				CreatedAt time.Time
+			}
+			type tenantAPIKey struct {
+				ID        string `gorm:"type:varchar(64);primaryKey"`
+				Tenant    string `gorm:"type:varchar(64);index"`
+				Name      string `gorm:"type:varchar(255)"`
+				Prefix    string `gorm:"type:varchar(16)"`
+				Hash      string `gorm:"type:varchar(64);uniqueIndex"`
+				CreatedAt time.Time
+			}
+			if err := tx.Table("tenants").AutoMigrate(&tenant{}); err != nil {
+				return err
+			}
+			if err := tx.Table("tenant_api_keys").AutoMigrate(&tenantAPIKey{}); err != nil {
+				return err
+			}
+
+			// Server names become unique per tenant; existing servers belong
+			// to the operator (empty tenant)
+			type serverDefinition struct {
+				Tenant    string `gorm:"type:varchar(64);primaryKey"`
+				Name      string `gorm:"type:varchar(255);primaryKey"`
+				Config    string `gorm:"type:text;not null"`
+				Endpoints string `gorm:"type:text"`
+				Running   bool
+				CreatedAt time.Time
+				UpdatedAt time.Time
+			}
+			if err := tx.Table("server_definitions_v5").AutoMigrate(&serverDefinition{}); err != nil {
+				return err
+			}
+			err := tx.Exec("INSERT INTO server_definitions_v5 (tenant, name, config, endpoints, running, created_at, updated_at) " +
+				"SELECT '', name, config, endpoints, running, created_at, updated_at FROM server_definitions").Error
+			if err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropTable("server_definitions"); err != nil {
+				return err
+			}
+			return tx.Migrator().RenameTable("server_definitions_v5", "server_definitions")
+		},
+	},
+	{
+		Version: 6,
+		Name:    "create_scheduled_query_results",
+		Up: func(tx *gorm.DB) error {
+			type scheduledQueryResult struct {
+				Server        string `gorm:"type:varchar(255);primaryKey"`
+				Name          string `gorm:"type:varchar(255);primaryKey"`
+				RanAt         time.Time
+				DurationMs    int64
+				RowCount      int
+				Truncated     bool
+				Error         string `gorm:"type:text"`
+				DeliveryError string `gorm:"type:text"`
+				Rows          string `gorm:"type:text"`
+			}
+			return tx.Table("scheduled_query_results").AutoMigrate(&scheduledQueryResult{})
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create_saved_queries",
+		Up: func(tx *gorm.DB) error {
+			type savedQuery struct {
+				Server      string `gorm:"type:varchar(255);primaryKey"`
+				Name        string `gorm:"type:varchar(255);primaryKey"`
+				Description string `gorm:"type:text"`
+				SQL         string `gorm:"column:sql;type:text"`
+				Parameters  string `gorm:"type:text"`
+				CreatedAt   time.Time
+				UpdatedAt   time.Time
+			}
+			return tx.Table("saved_queries").AutoMigrate(&savedQuery{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "create_query_history",
+		Up: func(tx *gorm.DB) error {
+			type queryHistory struct {
+				ID          uint      `gorm:"primaryKey;autoIncrement"`
+				Server      string    `gorm:"type:varchar(255);index"`
+				Source      string    `gorm:"type:varchar(255)"`
+				Fingerprint string    `gorm:"type:varchar(64);index"`
+				SQL         string    `gorm:"column:sql;type:text"`
+				Caller      string    `gorm:"type:varchar(255)"`
+				Client      string    `gorm:"type:varchar(255)"`
+				StartedAt   time.Time `gorm:"index"`
+				DurationMs  int64
+				Rows        int
+				Error       string `gorm:"type:text"`
+			}
+			return tx.Table("query_history").AutoMigrate(&queryHistory{})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "create_monitors",
+		Up: func(tx *gorm.DB) error {
+			type monitor struct {
+				Server          string `gorm:"type:varchar(255);primaryKey"`
+				Name            string `gorm:"type:varchar(255);primaryKey"`
+				Table           string `gorm:"column:table_name;type:varchar(255)"`
+				MinRows         *int64
+				TimestampColumn string `gorm:"type:varchar(255)"`
+				MaxStaleness    string `gorm:"type:varchar(64)"`
+				CreatedAt       time.Time
+			}
+			return tx.Table("monitors").AutoMigrate(&monitor{})
+		},
+	},
+}
@@ -0,0 +1,109 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Config holds the configuration of the gateway's internal state store
+type Config struct {
+	// Type of database (sqlite, postgres, mysql)
+	Type string `json:"type"`
+
+	// DSN is the connection string, or the file path for sqlite
+	DSN string `json:"dsn"`
+
+	// AutoMigrate applies pending migrations on boot. Defaults to true; when
+	// disabled the server refuses to start with pending migrations.
+	AutoMigrate *bool `json:"auto_migrate,omitempty"`
+}
+
+// autoMigrate reports whether pending migrations are applied on boot
+func (c *Config) autoMigrate() bool {
+	return c.AutoMigrate == nil || *c.AutoMigrate
+}
+
+// Store is the gateway's internal state store
+type Store struct {
+	DB  *gorm.DB
+	cfg *Config
+}
+
+// Open connects to the state store without running migrations
+func Open(cfg *Config) (*Store, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("state store configuration is required")
+	}
+
+	var dialector gorm.Dialector
+	switch cfg.Type {
+	case "", "sqlite":
+		dsn := cfg.DSN
+		if dsn == "" {
+			dsn = "./data/db-gateway.db"
+		}
+		if err := os.MkdirAll(filepath.Dir(dsn), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create state store directory: %w", err)
+		}
+		dialector = sqlite.Open(dsn)
+	case "postgres":
+		dialector = postgres.Open(cfg.DSN)
+	case "mysql":
+		dialector = mysql.Open(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unsupported state store type: %s", cfg.Type)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state store: %w", err)
+	}
+
+	return &Store{
+		DB:  db,
+		cfg: cfg,
+	}, nil
+}
+
+// OpenAndMigrate connects to the state store and brings its schema up to
+// date, honoring the auto_migrate setting
+func OpenAndMigrate(cfg *Config) (*Store, error) {
+	store, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.autoMigrate() {
+		if _, err := store.Migrate(false); err != nil {
+			store.Close()
+			return nil, err
+		}
+		return store, nil
+	}
+
+	status, err := store.Status()
+	if err != nil {
+		store.Close()
+		return nil, err
+	}
+	if len(status.Pending) > 0 {
+		store.Close()
+		return nil, fmt.Errorf("state store has %d pending migrations; run the migrate command", len(status.Pending))
+	}
+	return store, nil
+}
+
+// Close closes the state store connection
+func (s *Store) Close() error {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
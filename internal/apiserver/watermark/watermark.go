@@ -0,0 +1,175 @@
+package watermark
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ModeColumn injects a per-row trace column derived from the principal
+	ModeColumn = "column"
+	// ModeSampling deterministically drops a principal-specific subset of rows
+	ModeSampling = "sampling"
+
+	defaultTraceColumn = "_trace_id"
+	defaultDropRate    = 0.01
+)
+
+// Config holds the watermarking configuration
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// Mode is either "column" or "sampling"
+	Mode string `json:"mode"`
+
+	// Secret keys the HMAC so watermarks cannot be forged by recipients
+	Secret string `json:"secret"`
+
+	// TraceColumn is the name of the injected column (only used in column mode)
+	TraceColumn string `json:"trace_column,omitempty"`
+
+	// DropRate is the fraction of rows omitted (only used in sampling mode)
+	DropRate float64 `json:"drop_rate,omitempty"`
+}
+
+// Watermarker applies principal-bound watermarks to result sets
+type Watermarker struct {
+	config *Config
+}
+
+// New creates a new watermarker
+func New(config *Config) (*Watermarker, error) {
+	if config == nil {
+		return nil, fmt.Errorf("watermark configuration is required")
+	}
+	if config.Secret == "" {
+		return nil, fmt.Errorf("watermark secret is required")
+	}
+
+	switch config.Mode {
+	case "", ModeColumn, ModeSampling:
+	default:
+		return nil, fmt.Errorf("unsupported watermark mode: %s", config.Mode)
+	}
+
+	return &Watermarker{
+		config: config,
+	}, nil
+}
+
+// Apply watermarks the rows for the given principal and returns the resulting rows
+func (w *Watermarker) Apply(principal string, rows []map[string]interface{}) []map[string]interface{} {
+	switch w.config.Mode {
+	case ModeSampling:
+		return w.applySampling(principal, rows)
+	default:
+		return w.applyColumn(principal, rows)
+	}
+}
+
+// Mode returns the effective watermarking mode
+func (w *Watermarker) Mode() string {
+	if w.config.Mode == "" {
+		return ModeColumn
+	}
+	return w.config.Mode
+}
+
+// applyColumn adds a trace column whose value is bound to the principal and
+// the row's content, so that it survives the rows being reordered or
+// filtered
+func (w *Watermarker) applyColumn(principal string, rows []map[string]interface{}) []map[string]interface{} {
+	column := w.traceColumn()
+	result := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		marked := make(map[string]interface{}, len(row)+1)
+		for k, v := range row {
+			marked[k] = v
+		}
+		marked[column] = w.trace(principal, row)
+		result = append(result, marked)
+	}
+	return result
+}
+
+// traceColumn returns the name of the injected column
+func (w *Watermarker) traceColumn() string {
+	if w.config.TraceColumn == "" {
+		return defaultTraceColumn
+	}
+	return w.config.TraceColumn
+}
+
+// trace returns the trace value of a row for the principal, keyed by a
+// digest of the row's other columns
+func (w *Watermarker) trace(principal string, row map[string]interface{}) string {
+	column := w.traceColumn()
+	content := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		if k != column {
+			content[k] = v
+		}
+	}
+	// json.Marshal sorts map keys, which keeps the digest stable
+	data, _ := json.Marshal(content)
+	digest := sha256.Sum256(data)
+	return w.sign(principal, hex.EncodeToString(digest[:]))[:16]
+}
+
+// applySampling omits rows selected by a keyed hash so the subset identifies the principal
+func (w *Watermarker) applySampling(principal string, rows []map[string]interface{}) []map[string]interface{} {
+	rate := w.config.DropRate
+	if rate <= 0 || rate >= 1 {
+		rate = defaultDropRate
+	}
+	threshold := uint64(rate * float64(^uint64(0)))
+
+	result := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		data, _ := json.Marshal(row)
+		mac := w.mac(principal, string(data))
+		if binary.BigEndian.Uint64(mac[:8]) < threshold {
+			continue
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+// Identify reports whether the trace value of a leaked row was issued to the
+// principal, wherever the row appears in the leaked result
+func (w *Watermarker) Identify(principal string, row map[string]interface{}) bool {
+	traceValue, ok := row[w.traceColumn()].(string)
+	if !ok {
+		return false
+	}
+	return hmac.Equal([]byte(w.trace(principal, row)), []byte(traceValue))
+}
+
+func (w *Watermarker) sign(principal, data string) string {
+	return hex.EncodeToString(w.mac(principal, data))
+}
+
+func (w *Watermarker) mac(principal, data string) []byte {
+	h := hmac.New(sha256.New, []byte(w.config.Secret))
+	h.Write([]byte(principal))
+	h.Write([]byte{0})
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Fingerprint computes a stable content hash of a result set
+func Fingerprint(rows []map[string]interface{}) string {
+	h := sha256.New()
+	for _, row := range rows {
+		// json.Marshal sorts map keys, which keeps the hash stable
+		data, _ := json.Marshal(row)
+		h.Write(data)
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
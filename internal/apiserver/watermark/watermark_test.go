@@ -0,0 +1,62 @@
+package watermark
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatermarkerColumnMode(t *testing.T) {
+	w, err := New(&Config{Enabled: true, Mode: ModeColumn, Secret: "s3cret"})
+	require.NoError(t, err)
+
+	rows := []map[string]interface{}{{"id": 1}, {"id": 2}}
+	alice := w.Apply("alice", rows)
+	bob := w.Apply("bob", rows)
+
+	require.Len(t, alice, 2)
+	assert.NotContains(t, rows[0], defaultTraceColumn, "input rows must not be mutated")
+	assert.NotEqual(t, alice[0][defaultTraceColumn], bob[0][defaultTraceColumn])
+	assert.True(t, w.Identify("alice", alice[1]))
+	assert.False(t, w.Identify("bob", alice[1]))
+	assert.False(t, w.Identify("alice", rows[1]))
+
+	// A reordered or filtered export still attributes each row
+	reordered := []map[string]interface{}{alice[1], alice[0]}
+	for _, row := range reordered {
+		assert.True(t, w.Identify("alice", row))
+		assert.False(t, w.Identify("bob", row))
+	}
+
+	// Tampered rows no longer match their trace
+	tampered := map[string]interface{}{"id": 3, defaultTraceColumn: alice[1][defaultTraceColumn]}
+	assert.False(t, w.Identify("alice", tampered))
+}
+
+func TestWatermarkerSamplingMode(t *testing.T) {
+	w, err := New(&Config{Enabled: true, Mode: ModeSampling, Secret: "s3cret", DropRate: 0.2})
+	require.NoError(t, err)
+
+	var rows []map[string]interface{}
+	for i := 0; i < 500; i++ {
+		rows = append(rows, map[string]interface{}{"id": i, "name": fmt.Sprintf("row-%d", i)})
+	}
+
+	first := w.Apply("alice", rows)
+	second := w.Apply("alice", rows)
+	other := w.Apply("bob", rows)
+
+	assert.Equal(t, Fingerprint(first), Fingerprint(second), "sampling must be deterministic per principal")
+	assert.NotEqual(t, Fingerprint(first), Fingerprint(other))
+	assert.Less(t, len(first), len(rows))
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	_, err := New(&Config{Mode: ModeColumn})
+	assert.Error(t, err)
+
+	_, err = New(&Config{Mode: "bogus", Secret: "x"})
+	assert.Error(t, err)
+}
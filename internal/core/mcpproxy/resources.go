@@ -0,0 +1,132 @@
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/client"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/common/cnst"
+	"github.com/mcp-ecosystem/mcp-gateway/internal/template"
+	"github.com/mcp-ecosystem/mcp-gateway/pkg/mcp"
+)
+
+// ResourceTransport is implemented by transports that can list and read the
+// resources of the upstream server
+type ResourceTransport interface {
+	// FetchResources fetches the list of available resources
+	FetchResources(ctx context.Context) ([]mcp.ResourceSchema, error)
+
+	// ReadResource reads a resource
+	ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error)
+}
+
+var (
+	_ ResourceTransport = (*SSETransport)(nil)
+	_ ResourceTransport = (*StdioTransport)(nil)
+	_ ResourceTransport = (*StreamableTransport)(nil)
+)
+
+func (t *SSETransport) FetchResources(ctx context.Context) ([]mcp.ResourceSchema, error) {
+	if !t.IsRunning() {
+		if err := t.Start(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	return fetchResources(ctx, t.client)
+}
+
+func (t *SSETransport) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if !t.IsRunning() {
+		if err := t.Start(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	return readResource(ctx, t.client, uri)
+}
+
+func (t *StreamableTransport) FetchResources(ctx context.Context) ([]mcp.ResourceSchema, error) {
+	if !t.IsRunning() {
+		if err := t.Start(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	return fetchResources(ctx, t.client)
+}
+
+func (t *StreamableTransport) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if !t.IsRunning() {
+		if err := t.Start(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+	return readResource(ctx, t.client, uri)
+}
+
+func (t *StdioTransport) FetchResources(ctx context.Context) ([]mcp.ResourceSchema, error) {
+	if !t.IsRunning() {
+		if err := t.Start(ctx, template.NewContext()); err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		if t.cfg.Policy == cnst.PolicyOnDemand {
+			_ = t.Stop(ctx)
+		}
+	}()
+	return fetchResources(ctx, t.client)
+}
+
+func (t *StdioTransport) ReadResource(ctx context.Context, uri string) (*mcp.ReadResourceResult, error) {
+	if !t.IsRunning() {
+		if err := t.Start(ctx, template.NewContext()); err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		if t.cfg.Policy == cnst.PolicyOnDemand {
+			_ = t.Stop(ctx)
+		}
+	}()
+	return readResource(ctx, t.client, uri)
+}
+
+// fetchResources lists the resources of an initialized client
+func fetchResources(ctx context.Context, c *client.Client) ([]mcp.ResourceSchema, error) {
+	res, err := c.ListResources(ctx, mcpgo.ListResourcesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resources: %w", err)
+	}
+
+	resources := make([]mcp.ResourceSchema, len(res.Resources))
+	for i, r := range res.Resources {
+		resources[i] = mcp.ResourceSchema{
+			URI:         r.URI,
+			Name:        r.Name,
+			Description: r.Description,
+			MimeType:    r.MIMEType,
+		}
+	}
+	return resources, nil
+}
+
+// readResource reads a resource through an initialized client
+func readResource(ctx context.Context, c *client.Client, uri string) (*mcp.ReadResourceResult, error) {
+	req := mcpgo.ReadResourceRequest{}
+	req.Params.URI = uri
+	res, err := c.ReadResource(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+
+	result := &mcp.ReadResourceResult{}
+	for _, content := range res.Contents {
+		switch c := content.(type) {
+		case mcpgo.TextResourceContents:
+			result.Contents = append(result.Contents, mcp.ResourceContents{URI: c.URI, MimeType: c.MIMEType, Text: c.Text})
+		case mcpgo.BlobResourceContents:
+			result.Contents = append(result.Contents, mcp.ResourceContents{URI: c.URI, MimeType: c.MIMEType, Blob: c.Blob})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,356 @@
+// Package client is a Go client for the REST API of a database gateway
+// server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries = 2
+	defaultBackoff = 500 * time.Millisecond
+)
+
+// Error is an error response of the gateway
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("gateway returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Table is a table with its row count
+type Table struct {
+	Name     string `json:"name"`
+	RowCount int    `json:"row_count"`
+}
+
+// Column describes a column of a table
+type Column struct {
+	Name               string      `json:"name"`
+	Type               string      `json:"type"`
+	Description        string      `json:"description,omitempty"`
+	PrimaryKey         bool        `json:"primary_key,omitempty"`
+	Nullable           bool        `json:"nullable,omitempty"`
+	Default            string      `json:"default,omitempty"`
+	MaxLength          int         `json:"max_length,omitempty"`
+	Precision          int         `json:"precision,omitempty"`
+	Scale              int         `json:"scale,omitempty"`
+	ForeignKey         bool        `json:"foreign_key,omitempty"`
+	References         string      `json:"references,omitempty"`
+	Sample             interface{} `json:"sample,omitempty"`
+	Computed           bool        `json:"computed,omitempty"`
+	VerboseDescription string      `json:"verbose_description,omitempty"`
+}
+
+// TableMetadata describes a table's columns with sample rows
+type TableMetadata struct {
+	Name               string            `json:"name"`
+	Description        string            `json:"description,omitempty"`
+	Columns            []Column          `json:"columns"`
+	SampleData         []Row             `json:"sample_data,omitempty"`
+	RowCount           int               `json:"row_count"`
+	VerboseDescription string            `json:"verbose_description,omitempty"`
+	Indexes            []Index           `json:"indexes,omitempty"`
+	Constraints        []Constraint      `json:"constraints,omitempty"`
+	ToolDescriptions   map[string]string `json:"tool_descriptions,omitempty"`
+}
+
+// Index is an index of a table, or the clustering key of a Snowflake table
+type Index struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type,omitempty"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique,omitempty"`
+}
+
+// Constraint is a primary key, unique or check constraint of a table
+type Constraint struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Columns []string `json:"columns,omitempty"`
+	Check   string   `json:"check,omitempty"`
+}
+
+// Endpoint is a generated REST endpoint
+type Endpoint struct {
+	Table       string                 `json:"table,omitempty"`
+	Operation   string                 `json:"operation,omitempty"`
+	Method      string                 `json:"method"`
+	Path        string                 `json:"path"`
+	Description string                 `json:"description"`
+	Query       string                 `json:"query"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+	Params      []Parameter            `json:"params,omitempty"`
+}
+
+// Parameter defines a path, query or body parameter of an endpoint
+type Parameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Type        string      `json:"type"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// Row is a result row keyed by column name
+type Row map[string]interface{}
+
+// Client calls the REST API of a gateway server. It is safe for concurrent
+// use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      func(ctx context.Context) (string, error)
+	headers    http.Header
+	retries    int
+	backoff    time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken authenticates requests with a bearer token: a tenant API key
+// or a JWT carrying the claims of row filters
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = func(context.Context) (string, error) { return token, nil }
+	}
+}
+
+// WithTokenSource authenticates requests with a bearer token obtained per
+// request, e.g. to refresh short-lived JWTs
+func WithTokenSource(source func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) { c.token = source }
+}
+
+// WithHeader adds a header to every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) { c.headers.Add(key, value) }
+}
+
+// WithRetries sets how many times a request is retried after a transport
+// error or a 502, 503 or 504 response, waiting backoff before the first
+// retry and doubling it after each (default: 2 retries, 500ms)
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) { c.retries, c.backoff = retries, backoff }
+}
+
+// New creates a client of the server whose API is served at baseURL,
+// including the API prefix, e.g. http://localhost:8081/api/db
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		headers:    make(http.Header),
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ListTables lists the tables with their row counts
+func (c *Client) ListTables(ctx context.Context) ([]Table, error) {
+	var tables []Table
+	err := c.do(ctx, http.MethodGet, "/tables", nil, true, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&tables)
+	})
+	return tables, err
+}
+
+// GetTableMetadata describes a table
+func (c *Client) GetTableMetadata(ctx context.Context, table string) (*TableMetadata, error) {
+	var metadata TableMetadata
+	err := c.do(ctx, http.MethodGet, "/tables/"+url.PathEscape(table), nil, true, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&metadata)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// Query runs a SQL statement with named parameters (:name) and returns its
+// rows
+func (c *Client) Query(ctx context.Context, query string, params map[string]interface{}) ([]Row, error) {
+	rows := make([]Row, 0)
+	err := c.QueryStream(ctx, query, params, func(row Row) error {
+		rows = append(rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// QueryStream runs a SQL statement like Query but decodes the rows as they
+// arrive, calling fn with each, so large results need not be held in
+// memory. An error returned by fn stops the query and is returned.
+// Statements are only retried when they read, i.e. start with SELECT or
+// WITH, and only before fn has been called.
+func (c *Client) QueryStream(ctx context.Context, query string, params map[string]interface{}, fn func(Row) error) error {
+	body := map[string]interface{}{"query": query, "params": params}
+	return c.do(ctx, http.MethodPost, "/query", body, isRead(query), func(r io.Reader) error {
+		dec := json.NewDecoder(r)
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var row Row
+			if err := dec.Decode(&row); err != nil {
+				return fmt.Errorf("failed to decode row: %w", err)
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return expectDelim(dec, ']')
+	})
+}
+
+// GenerateEndpoints generates and registers the REST endpoints of tables,
+// replacing earlier ones of the same tables
+func (c *Client) GenerateEndpoints(ctx context.Context, tables []string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	body := map[string]interface{}{"tables": tables}
+	// Regenerating endpoints is idempotent
+	err := c.do(ctx, http.MethodPost, "/generate-api", body, true, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&endpoints)
+	})
+	return endpoints, err
+}
+
+// do sends a request, retrying it when retryable, and decodes a successful
+// response with decode
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, retryable bool, decode func(io.Reader) error) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, payload)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			defer resp.Body.Close()
+			return decode(resp.Body)
+		}
+		if err == nil {
+			err = responseError(resp)
+		}
+		if !retryable || attempt >= c.retries || !isTransient(err) || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// send sends one attempt of a request
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return c.httpClient.Do(req)
+}
+
+// responseError reads the error of a failed response
+func responseError(resp *http.Response) error {
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	var body struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		message = body.Error
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	return &Error{StatusCode: resp.StatusCode, Message: message}
+}
+
+// isTransient reports whether a failed request may succeed when retried
+func isTransient(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// isRead reports whether a statement only reads
+func isRead(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	keyword := strings.ToUpper(fields[0])
+	return keyword == "SELECT" || keyword == "WITH"
+}
+
+// expectDelim reads a JSON delimiter
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to decode rows: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("failed to decode rows: expected %q, got %v", want, tok)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	var tableCalls, queryCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/api/db/tables":
+			// The first attempt fails transiently
+			tableCalls++
+			if tableCalls == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"name": "ORDERS", "row_count": 2}]`))
+		case "/api/db/tables/ORDER ITEMS":
+			_, _ = w.Write([]byte(`{"name": "ORDER ITEMS", "columns": [{"name": "ID", "type": "NUMBER", "primary_key": true}]}`))
+		case "/api/db/query":
+			queryCalls++
+			var body struct {
+				Query  string                 `json:"query"`
+				Params map[string]interface{} `json:"params"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body.Query == "DELETE FROM ORDERS" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			if body.Params["fail"] != nil {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error": "Failed to execute query: denied"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`[{"ID": 1}, {"ID": 2}, {"ID": 3}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := New(srv.URL+"/api/db/", WithToken("secret"), WithRetries(2, time.Millisecond))
+
+	tables, err := c.ListTables(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Table{{Name: "ORDERS", RowCount: 2}}, tables)
+	assert.Equal(t, 2, tableCalls)
+
+	metadata, err := c.GetTableMetadata(ctx, "ORDER ITEMS")
+	require.NoError(t, err)
+	assert.True(t, metadata.Columns[0].PrimaryKey)
+
+	rows, err := c.Query(ctx, "SELECT ID FROM ORDERS", nil)
+	require.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	// Streaming stops at the callback's error
+	stop := errors.New("stop")
+	var seen int
+	err = c.QueryStream(ctx, "SELECT ID FROM ORDERS", nil, func(row Row) error {
+		seen++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, seen)
+
+	_, err = c.Query(ctx, "SELECT ID FROM ORDERS", map[string]interface{}{"fail": true})
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, "Failed to execute query: denied", apiErr.Message)
+
+	// Writes are not retried
+	queryCalls = 0
+	_, err = c.Query(ctx, "DELETE FROM ORDERS", nil)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 1, queryCalls)
+}
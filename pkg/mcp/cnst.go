@@ -38,6 +38,8 @@ const (
 	ResourcesList          = "resources/list"
 	ResourcesTemplatesList = "resources/templates/list"
 	ResourcesRead          = "resources/read"
+	ResourcesSubscribe     = "resources/subscribe"
+	ResourcesUnsubscribe   = "resources/unsubscribe"
 )
 
 // Error codes for MCP protocol
@@ -64,4 +66,6 @@ const (
 	TextContentType  = "text"
 	ImageContentType = "image"
 	AudioContentType = "audio"
+
+	ResourceLinkContentType = "resource_link"
 )
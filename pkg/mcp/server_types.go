@@ -56,6 +56,8 @@ type (
 		Description string `json:"description"`
 		// A JSON Schema object defining the expected parameters for the tool
 		InputSchema ToolInputSchema `json:"inputSchema"`
+		// An optional JSON Schema object describing the tool's structured result
+		OutputSchema map[string]any `json:"outputSchema,omitempty"`
 	}
 
 	ToolInputSchema struct {
@@ -112,10 +114,134 @@ type (
 		MimeType string `json:"mimeType"`
 	}
 
+	// ResourceLinkContent represents a link to a resource the client can read
+	ResourceLinkContent struct {
+		// Must be "resource_link"
+		Type string `json:"type"`
+		// The URI of the resource
+		URI string `json:"uri"`
+		// The name of the resource
+		Name string `json:"name"`
+		// A description of the resource
+		Description string `json:"description,omitempty"`
+		// The MIME type of the resource
+		MimeType string `json:"mimeType,omitempty"`
+	}
+
 	// CallToolResult represents the result of a tools/call request
 	CallToolResult struct {
 		Content []Content `json:"content"`
-		IsError bool      `json:"isError"`
+		// Structured result conforming to the tool's output schema, if any
+		StructuredContent any  `json:"structuredContent,omitempty"`
+		IsError           bool `json:"isError"`
+	}
+
+	// ResourceSchema represents a resource definition
+	ResourceSchema struct {
+		// The URI of the resource
+		URI string `json:"uri"`
+		// The name of the resource
+		Name string `json:"name"`
+		// A description of the resource
+		Description string `json:"description,omitempty"`
+		// The MIME type of the resource
+		MimeType string `json:"mimeType,omitempty"`
+	}
+
+	// ListResourcesResult represents the result of a resources/list request
+	ListResourcesResult struct {
+		Resources []ResourceSchema `json:"resources"`
+	}
+
+	// ReadResourceParams represents parameters for a resources/read request
+	ReadResourceParams struct {
+		BaseRequestParams
+		// The URI of the resource to read
+		URI string `json:"uri"`
+	}
+
+	// SubscribeResourceParams represents parameters for a resources/subscribe
+	// or resources/unsubscribe request
+	SubscribeResourceParams struct {
+		BaseRequestParams
+		// The URI of the resource to receive updates of
+		URI string `json:"uri"`
+	}
+
+	// ResourceUpdatedNotificationParams represents the parameters of a
+	// notifications/resources/updated notification
+	ResourceUpdatedNotificationParams struct {
+		// The URI of the updated resource
+		URI string `json:"uri"`
+	}
+
+	// ResourceContents represents the text or binary contents of a resource
+	ResourceContents struct {
+		// The URI of the resource
+		URI string `json:"uri"`
+		// The MIME type of the resource
+		MimeType string `json:"mimeType,omitempty"`
+		// The text of the resource
+		Text string `json:"text,omitempty"`
+		// The base64-encoded binary data of the resource
+		Blob string `json:"blob,omitempty"`
+		// Metadata of the contents, e.g. their entity tag
+		Meta map[string]interface{} `json:"_meta,omitempty"`
+	}
+
+	// ReadResourceResult represents the result of a resources/read request
+	ReadResourceResult struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+
+	// PromptSchema represents a prompt or prompt template the server offers
+	PromptSchema struct {
+		// The name of the prompt
+		Name string `json:"name"`
+		// A description of what the prompt provides
+		Description string `json:"description,omitempty"`
+		// The arguments used to populate the prompt
+		Arguments []PromptArgumentSchema `json:"arguments,omitempty"`
+	}
+
+	// PromptArgumentSchema describes an argument a prompt accepts
+	PromptArgumentSchema struct {
+		// The name of the argument
+		Name string `json:"name"`
+		// A description of the argument
+		Description string `json:"description,omitempty"`
+		// Whether the argument must be provided
+		Required bool `json:"required,omitempty"`
+	}
+
+	// ListPromptsResult represents the result of a prompts/list request
+	ListPromptsResult struct {
+		Prompts []PromptSchema `json:"prompts"`
+	}
+
+	// GetPromptParams represents parameters for a prompts/get request
+	GetPromptParams struct {
+		BaseRequestParams
+		// The name of the prompt
+		Name string `json:"name"`
+		// The arguments used to populate the prompt
+		Arguments map[string]string `json:"arguments,omitempty"`
+	}
+
+	// PromptMessage represents a message returned as part of a prompt
+	PromptMessage struct {
+		// The role of the message author, "user" or "assistant"
+		Role string `json:"role"`
+		// The content of the message
+		Content Content `json:"content"`
+	}
+
+	// GetPromptResult represents the result of a prompts/get request
+	GetPromptResult struct {
+		// A description of the prompt
+		Description string `json:"description,omitempty"`
+		// The messages of the prompt
+		Messages []PromptMessage `json:"messages"`
 	}
 
 	// ImplementationSchema describes the name and version of an MCP implementation
@@ -272,6 +398,10 @@ func (i *AudioContent) GetType() string {
 	return AudioContentType
 }
 
+func (r *ResourceLinkContent) GetType() string {
+	return ResourceLinkContentType
+}
+
 // NewCallToolResult creates a new CallToolResult
 // @param content the content of the result
 // @param isError indicates if the result is an error